@@ -13,6 +13,7 @@ var exitFunc = os.Exit
 
 func run(args []string, in io.Reader, out io.Writer, errOut io.Writer) int {
 	runner := app.New(out, in)
+	runner.Err = errOut
 	commandLine := cli.New(runner, out, errOut)
 	return commandLine.Run(context.Background(), args)
 }
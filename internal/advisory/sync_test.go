@@ -3836,6 +3836,10 @@ func (r *advisoryFakeRoot) Chmod(name string, perm os.FileMode) error {
 	return nil
 }
 
+func (r *advisoryFakeRoot) Chtimes(string, time.Time, time.Time) error {
+	return errors.New("unexpected chtimes")
+}
+
 func (r *advisoryFakeRoot) MkdirAll(name string, perm os.FileMode) error {
 	if r.mkdirAll != nil {
 		return r.mkdirAll(name, perm)
@@ -29,6 +29,8 @@ type analysisCache struct {
 	inputDigestMemo  map[cacheInputDigestMemoKey]string
 	stableRepoPath   string
 	analysisRepoPath string
+	files            *fileResultCache
+	fileResults      bool
 }
 
 func newAnalysisCache(req Request, repoPath string, analysisRepoPaths ...string) *analysisCache {
@@ -45,6 +47,7 @@ func newAnalysisCache(req Request, repoPath string, analysisRepoPaths ...string)
 		rejectReadHits:   !options.ExplicitPath,
 		stableRepoPath:   filepath.Clean(repoPath),
 		analysisRepoPath: filepath.Clean(repoPath),
+		fileResults:      req.Features.Enabled(analysisFileCachePreviewFeature),
 	}
 	if len(analysisRepoPaths) > 0 && strings.TrimSpace(analysisRepoPaths[0]) != "" {
 		cache.analysisRepoPath = filepath.Clean(analysisRepoPaths[0])
@@ -88,6 +91,21 @@ func (c *analysisCache) stableCacheRoot(rootPath string) string {
 	return filepath.Join(c.stableRepoPath, rel)
 }
 
+// portableCachePath expresses a path inside the repository relative to it, so
+// cache keys and input digests match across checkouts at different absolute
+// paths and a bundle exported on one machine hits on another. Paths outside
+// the repository are kept as they are.
+func (c *analysisCache) portableCachePath(path string) string {
+	if c == nil || c.stableRepoPath == "" || path == "" {
+		return path
+	}
+	rel, err := filepath.Rel(c.stableRepoPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return "repo:" + filepath.ToSlash(rel)
+}
+
 func prepareWritableAnalysisCacheRoot(cachePath string) (identity fs.FileInfo, returnErr error) {
 	root, currentPath, missingParts, err := safeio.OpenRootExistingAncestorNoFollow(cachePath)
 	if err != nil {
@@ -103,7 +121,7 @@ func prepareWritableAnalysisCacheRoot(cachePath string) (identity fs.FileInfo, r
 	if err != nil {
 		return nil, err
 	}
-	for _, name := range []string{"keys", "objects"} {
+	for _, name := range []string{"keys", "objects", analysisCacheFilesDir} {
 		child, err := openOrCreatePinnedAnalysisCacheChild(root, currentPath, name)
 		if err != nil {
 			return nil, err
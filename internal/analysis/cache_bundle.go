@@ -0,0 +1,328 @@
+package analysis
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	cacheBundleSchemaVersion    = "lopper.cache-bundle.v1"
+	cacheBundleManifestName     = "manifest.json"
+	maxCacheBundleManifestBytes = 16 << 20
+	maxCacheBundleEntryBytes    = 64 << 20
+	maxCacheBundleBytes         = 1 << 30
+)
+
+var cacheBundleEntryPattern = regexp.MustCompile(`^(keys|objects|files)/[0-9a-f]{64}\.json$`)
+
+// CacheBundleSummary describes the entries written to or restored from a
+// portable cache bundle.
+type CacheBundleSummary struct {
+	Path        string `json:"path"`
+	Entries     int    `json:"entries"`
+	Objects     int    `json:"objects"`
+	FileEntries int    `json:"fileEntries"`
+	Bytes       int64  `json:"bytes"`
+	Skipped     int    `json:"skipped,omitempty"`
+}
+
+// cacheBundleManifest is the first member of a bundle. It pins the cache
+// schema the entries were written with and the digest of every member, so an
+// import verifies the whole archive before writing anything.
+type cacheBundleManifest struct {
+	Schema      string            `json:"schema"`
+	CacheSchema string            `json:"cacheSchema"`
+	Files       []cacheBundleFile `json:"files"`
+}
+
+type cacheBundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type cacheBundleMember struct {
+	cacheBundleFile
+	Data []byte
+}
+
+// ExportCache writes the cache at cachePath to w as a tar bundle. Only entries
+// that verify are exported: pointers whose object exists and matches its
+// digest, and well-formed file-tier entries. Members are sorted and carry
+// fixed metadata, so the same cache contents always produce the same bytes.
+func ExportCache(cachePath string, w io.Writer) (summary CacheBundleSummary, err error) {
+	root, err := openMaintainedCacheRoot(cachePath)
+	if err != nil {
+		return CacheBundleSummary{}, err
+	}
+	defer func() {
+		err = errors.Join(err, root.Close())
+	}()
+	inventory, err := readCacheInventory(root)
+	if err != nil {
+		return CacheBundleSummary{}, err
+	}
+
+	summary = CacheBundleSummary{Path: cachePath, Skipped: len(inventory.Issues)}
+	members := make([]cacheBundleMember, 0, len(inventory.Pointers)*2+len(inventory.Files))
+	exportedObjects := make(map[string]bool)
+	for _, pointer := range inventory.Pointers {
+		object, ok := inventory.Objects[pointer.Pointer.ObjectDigest]
+		if !pointer.Valid || !isCacheDigest(pointer.digest()) || !ok {
+			summary.Skipped++
+			continue
+		}
+		if _, seen := exportedObjects[object.digest()]; !seen {
+			objectData, readErr := safeio.ReadFileWithinRoot(root, object.rel())
+			exportedObjects[object.digest()] = readErr == nil && sha256Hex(objectData) == object.digest()
+			if exportedObjects[object.digest()] {
+				members = append(members, newCacheBundleMember(object, objectData))
+				summary.Objects++
+			}
+		}
+		if !exportedObjects[object.digest()] {
+			summary.Skipped++
+			continue
+		}
+		pointerData, readErr := safeio.ReadFileWithinRoot(root, pointer.rel())
+		if readErr != nil {
+			summary.Skipped++
+			continue
+		}
+		members = append(members, newCacheBundleMember(pointer.cacheFileInfo, pointerData))
+		summary.Entries++
+	}
+	for _, file := range inventory.Files {
+		data, readErr := safeio.ReadFileWithinRoot(root, file.rel())
+		if readErr != nil || !isCacheDigest(file.digest()) || !json.Valid(data) {
+			summary.Skipped++
+			continue
+		}
+		members = append(members, newCacheBundleMember(file, data))
+		summary.FileEntries++
+	}
+	slices.SortFunc(members, func(a, b cacheBundleMember) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	manifest := cacheBundleManifest{Schema: cacheBundleSchemaVersion, CacheSchema: analysisCacheSchemaVersion, Files: make([]cacheBundleFile, 0, len(members))}
+	for _, member := range members {
+		manifest.Files = append(manifest.Files, member.cacheBundleFile)
+		summary.Bytes += member.Size
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return CacheBundleSummary{}, err
+	}
+	tw := tar.NewWriter(w)
+	if err := writeCacheBundleMember(tw, cacheBundleManifestName, manifestData); err != nil {
+		return CacheBundleSummary{}, err
+	}
+	for _, member := range members {
+		if err := writeCacheBundleMember(tw, member.Path, member.Data); err != nil {
+			return CacheBundleSummary{}, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return CacheBundleSummary{}, err
+	}
+	return summary, nil
+}
+
+// ImportCache restores a bundle written by ExportCache into cachePath. The
+// whole archive is read and checked against its manifest first; a bundle
+// with an unexpected member, a digest mismatch, or a pointer to an object it
+// does not carry is rejected without touching the cache. The cache directory
+// is created when only its last path element is missing.
+func ImportCache(cachePath string, r io.Reader) (CacheBundleSummary, error) {
+	members, err := readCacheBundle(r)
+	if err != nil {
+		return CacheBundleSummary{}, err
+	}
+	if err := ensureAnalysisCacheRoot(cachePath); err != nil {
+		return CacheBundleSummary{}, err
+	}
+	cache := &analysisCache{options: resolvedCacheOptions{Enabled: true, Path: cachePath, ExplicitPath: true}}
+	writeRoot, err := cache.openWriteRoot()
+	if err != nil {
+		return CacheBundleSummary{}, err
+	}
+
+	summary := CacheBundleSummary{Path: cachePath}
+	for _, member := range members {
+		target := filepath.FromSlash(member.Path)
+		var writeErr error
+		switch path.Dir(member.Path) {
+		case "keys":
+			writeErr = writeRoot.WriteFileCreatingParents(target, member.Data, 0o640, 0o750)
+			summary.Entries++
+		case "objects":
+			writeErr = writeRoot.WriteFileCreatingParentsAtomicallyIfAbsent(target, member.Data, 0o640, 0o750)
+			summary.Objects++
+		default:
+			writeErr = writeRoot.WriteFileCreatingParentsAtomicallyIfAbsent(target, member.Data, 0o640, 0o750)
+			summary.FileEntries++
+		}
+		if writeErr != nil && !errors.Is(writeErr, os.ErrExist) {
+			return summary, errors.Join(writeErr, writeRoot.Close())
+		}
+		summary.Bytes += member.Size
+	}
+	return summary, writeRoot.Close()
+}
+
+func readCacheBundle(r io.Reader) ([]cacheBundleMember, error) {
+	tr := tar.NewReader(r)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read cache bundle: %w", err)
+	}
+	if header.Name != cacheBundleManifestName || header.Typeflag != tar.TypeReg || header.Size > maxCacheBundleManifestBytes {
+		return nil, errors.New("cache bundle must start with " + cacheBundleManifestName)
+	}
+	manifestData, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, err
+	}
+	var manifest cacheBundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("parse cache bundle manifest: %w", err)
+	}
+	if manifest.Schema != cacheBundleSchemaVersion {
+		return nil, fmt.Errorf("unsupported cache bundle schema %q", manifest.Schema)
+	}
+	if manifest.CacheSchema != analysisCacheSchemaVersion {
+		return nil, fmt.Errorf("cache bundle was written for cache schema %s; this lopper uses %s", manifest.CacheSchema, analysisCacheSchemaVersion)
+	}
+	expected := make(map[string]cacheBundleFile, len(manifest.Files))
+	var total int64
+	for _, file := range manifest.Files {
+		if !cacheBundleEntryPattern.MatchString(file.Path) || file.Size < 0 || file.Size > maxCacheBundleEntryBytes {
+			return nil, fmt.Errorf("cache bundle manifest lists invalid member %q", file.Path)
+		}
+		if _, duplicate := expected[file.Path]; duplicate {
+			return nil, fmt.Errorf("cache bundle manifest lists %s twice", file.Path)
+		}
+		if total += file.Size; total > maxCacheBundleBytes {
+			return nil, fmt.Errorf("cache bundle exceeds %d bytes", maxCacheBundleBytes)
+		}
+		expected[file.Path] = file
+	}
+
+	members := make([]cacheBundleMember, 0, len(expected))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read cache bundle: %w", err)
+		}
+		file, ok := expected[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg || header.Size != file.Size {
+			return nil, fmt.Errorf("cache bundle member %q is not listed in the manifest", header.Name)
+		}
+		delete(expected, header.Name)
+		data, err := io.ReadAll(io.LimitReader(tr, file.Size+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != file.Size || sha256Hex(data) != file.SHA256 {
+			return nil, fmt.Errorf("cache bundle member %s does not match its manifest digest", header.Name)
+		}
+		members = append(members, cacheBundleMember{cacheBundleFile: file, Data: data})
+	}
+	if len(expected) > 0 {
+		return nil, fmt.Errorf("cache bundle is missing %d member(s) listed in its manifest", len(expected))
+	}
+	if err := verifyCacheBundleMembers(members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// verifyCacheBundleMembers checks what the manifest digest cannot: objects
+// are named by their own content digest, and every pointer names an object
+// the bundle carries.
+func verifyCacheBundleMembers(members []cacheBundleMember) error {
+	objects := make(map[string]bool)
+	for _, member := range members {
+		if path.Dir(member.Path) == "objects" {
+			if strings.TrimSuffix(path.Base(member.Path), ".json") != member.SHA256 {
+				return fmt.Errorf("cache bundle object %s is not named by its digest", member.Path)
+			}
+			objects[member.SHA256] = true
+		}
+	}
+	for _, member := range members {
+		switch path.Dir(member.Path) {
+		case "keys":
+			var pointer cachePointer
+			if json.Unmarshal(member.Data, &pointer) != nil || !objects[pointer.ObjectDigest] {
+				return fmt.Errorf("cache bundle pointer %s references an object the bundle does not carry", member.Path)
+			}
+		case analysisCacheFilesDir:
+			if !json.Valid(member.Data) {
+				return fmt.Errorf("cache bundle file entry %s is not valid JSON", member.Path)
+			}
+		}
+	}
+	return nil
+}
+
+func newCacheBundleMember(info cacheFileInfo, data []byte) cacheBundleMember {
+	return cacheBundleMember{
+		cacheBundleFile: cacheBundleFile{Path: filepath.ToSlash(info.rel()), SHA256: sha256Hex(data), Size: int64(len(data))},
+		Data:            data,
+	}
+}
+
+func writeCacheBundleMember(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Unix(0, 0),
+		Format:   tar.FormatUSTAR,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, bytes.NewReader(data))
+	return err
+}
+
+// ensureAnalysisCacheRoot creates the cache directory under an existing parent
+// so a fresh CI workspace can import without a separate mkdir.
+func ensureAnalysisCacheRoot(cachePath string) (returnErr error) {
+	if _, err := os.Lstat(cachePath); !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	parentPath := filepath.Dir(filepath.Clean(cachePath))
+	parent, err := safeio.OpenRootNoFollow(parentPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		returnErr = errors.Join(returnErr, parent.Close())
+	}()
+	child, err := safeio.OpenOrCreatePinnedDirectory(parent, parentPath, filepath.Base(filepath.Clean(cachePath)), 0o750)
+	if err != nil {
+		return err
+	}
+	return child.Close()
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestCacheBundleRoundTripIsDeterministicAndPortable(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "checkout-a")
+	testutil.MustWriteFile(t, filepath.Join(source, cacheTestJSIndexFileName), "import dep from \"dep\"\n")
+	svc, adapter := newCacheTestService(t)
+	cacheDir := filepath.Join(t.TempDir(), cacheTestDirectoryName)
+	if _, err := svc.Analyse(context.Background(), newCacheRequest(t, source, cacheDir, false)); err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+
+	var first, second bytes.Buffer
+	summary, err := ExportCache(cacheDir, &first)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if summary.Entries != 1 || summary.Objects != 1 || summary.Skipped != 0 || summary.Bytes == 0 {
+		t.Fatalf("unexpected export summary %#v", summary)
+	}
+	if _, err := ExportCache(cacheDir, &second); err != nil {
+		t.Fatalf("second export: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Fatalf("expected identical bundles for identical cache contents")
+	}
+
+	restored := filepath.Join(t.TempDir(), "restored-cache")
+	imported, err := ImportCache(restored, bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if imported.Entries != 1 || imported.Objects != 1 || imported.Bytes != summary.Bytes {
+		t.Fatalf("unexpected import summary %#v", imported)
+	}
+	if _, err := ImportCache(restored, bytes.NewReader(first.Bytes())); err != nil {
+		t.Fatalf("expected re-import to be idempotent: %v", err)
+	}
+
+	relocated := filepath.Join(t.TempDir(), "checkout-b")
+	testutil.MustWriteFile(t, filepath.Join(relocated, cacheTestJSIndexFileName), "import dep from \"dep\"\n")
+	reportData, err := svc.Analyse(context.Background(), newCacheRequest(t, relocated, restored, true))
+	if err != nil {
+		t.Fatalf("analyse relocated checkout: %v", err)
+	}
+	if adapter.calls != 1 || reportData.Cache == nil || reportData.Cache.Hits != 1 {
+		t.Fatalf("expected imported cache to hit from another checkout path, calls=%d cache=%#v", adapter.calls, reportData.Cache)
+	}
+}
+
+func TestImportCacheRejectsTamperedBundles(t *testing.T) {
+	cacheDir := t.TempDir()
+	mustMkdirCacheLayout(t, cacheDir)
+	objectDigest := writeTestCacheObject(t, cacheDir, `{"report":{"repoPath":"repo"}}`)
+	writeTestCachePointer(t, cacheDir, strings.Repeat("a", 64), objectDigest, time.Now())
+	var bundle bytes.Buffer
+	if _, err := ExportCache(cacheDir, &bundle); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	tampered := rewriteTestCacheBundle(t, bundle.Bytes(), func(name string, data []byte) []byte {
+		if strings.HasPrefix(name, "objects/") {
+			return bytes.Replace(data, []byte("repo"), []byte("evil"), 1)
+		}
+		return data
+	})
+	target := filepath.Join(t.TempDir(), "cache")
+	if _, err := ImportCache(target, bytes.NewReader(tampered)); err == nil || !strings.Contains(err.Error(), "manifest digest") {
+		t.Fatalf("expected digest mismatch, got %v", err)
+	}
+	if _, err := ImportCache(target, bytes.NewReader(bundle.Bytes()[:512])); err == nil {
+		t.Fatalf("expected truncated bundle to be rejected")
+	}
+	if _, err := InspectCache(target); err == nil {
+		t.Fatalf("expected rejected imports to leave no cache behind")
+	}
+}
+
+func rewriteTestCacheBundle(t *testing.T, bundle []byte, rewrite func(string, []byte) []byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	tr := tar.NewReader(bytes.NewReader(bundle))
+	tw := tar.NewWriter(&out)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read bundle: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read bundle member: %v", err)
+		}
+		if err := writeCacheBundleMember(tw, header.Name, rewrite(header.Name, data)); err != nil {
+			t.Fatalf("write bundle member: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close bundle: %v", err)
+	}
+	return out.Bytes()
+}
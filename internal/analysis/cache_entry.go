@@ -7,11 +7,14 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
 )
 
 const analysisCacheSchemaVersion = "v4"
 
 type cacheEntryDescriptor struct {
+	Adapter     string
 	KeyLabel    string
 	KeyDigest   string
 	InputDigest string
@@ -42,13 +45,13 @@ func (c *analysisCache) prepareEntryWithSchemaVersion(req Request, adapterID, no
 	baseKey := map[string]any{
 		"schema":         schemaVersion,
 		"adapter":        adapterID,
-		"root":           stableRoot,
+		"root":           c.portableCachePath(stableRoot),
 		"dependency":     req.Dependency,
 		"language":       normalizeCacheLanguage(req.Language),
 		"topN":           req.TopN,
 		"suggestOnly":    req.SuggestOnly,
 		"runtimeProfile": req.RuntimeProfile,
-		"configPath":     strings.TrimSpace(req.ConfigPath),
+		"configPath":     c.portableCachePath(cleanConfigPath(req.ConfigPath)),
 	}
 	if command := strings.TrimSpace(req.RuntimeTestCommand); command != "" {
 		baseKey["runtimeTestCommand"] = command
@@ -72,6 +75,12 @@ func (c *analysisCache) prepareEntryWithSchemaVersion(req Request, adapterID, no
 	if len(req.LicenseDenyList) > 0 {
 		baseKey["licenseDeny"] = req.LicenseDenyList
 	}
+	if len(req.LicenseAllowList) > 0 {
+		baseKey["licenseAllow"] = req.LicenseAllowList
+	}
+	if unknown := report.NormalizeLicenseUnknownPolicy(req.LicenseUnknownPolicy); unknown != report.LicenseUnknownAllow {
+		baseKey["licenseUnknown"] = unknown
+	}
 	if scopeIdentity := normalizedScopeCacheIdentity(req); scopeIdentity != nil {
 		baseKey["pathScope"] = scopeIdentity
 	}
@@ -85,6 +94,7 @@ func (c *analysisCache) prepareEntryWithSchemaVersion(req Request, adapterID, no
 		return cacheEntryDescriptor{}, err
 	}
 	return cacheEntryDescriptor{
+		Adapter:     adapterID,
 		KeyLabel:    adapterID + ":" + stableRoot,
 		KeyDigest:   baseDigest,
 		InputDigest: inputDigest,
@@ -144,7 +154,7 @@ func (c *analysisCache) computeInputDigest(rootPath, configPath string) (string,
 	cleanedConfigPath := cleanConfigPath(configPath)
 	if cleanedConfigPath != "" {
 		inputs = append(inputs, cacheDigestInput{
-			sortKey:      "config\x00" + cleanedConfigPath,
+			sortKey:      "config\x00" + c.portableCachePath(cleanedConfigPath),
 			path:         cleanedConfigPath,
 			allowMissing: true,
 		})
@@ -377,6 +377,61 @@ func TestLockOrConfigFileRecognizesGradleVersionCatalogs(t *testing.T) {
 	}
 }
 
+func TestIsCacheRelevantFileRecognizesDotNetProjectInputs(t *testing.T) {
+	for _, path := range []string{"src/App/packages.config", "Directory.Build.props", "src/Directory.Build.targets", "App.sln", "App.slnx"} {
+		if !isCacheRelevantFile(path) {
+			t.Fatalf("expected %s to participate in cache invalidation", path)
+		}
+	}
+}
+
+func TestIsCacheRelevantFileRecognizesCMakeInputs(t *testing.T) {
+	for _, path := range []string{"CMakeLists.txt", "src/CMakeLists.txt", "cmake/FindZLIB.cmake"} {
+		if !isCacheRelevantFile(path) {
+			t.Fatalf("expected %s to participate in cache invalidation", path)
+		}
+	}
+	if isCacheRelevantFile("notes.txt") {
+		t.Fatalf("did not expect arbitrary text files to participate in cache invalidation")
+	}
+}
+
+func TestIsCacheRelevantFileRecognizesConanAndMesonInputs(t *testing.T) {
+	for _, path := range []string{"conanfile.py", "meson.build", "lib/meson.build", filepath.Join("subprojects", "zlib.wrap")} {
+		if !isCacheRelevantFile(path) {
+			t.Fatalf("expected %s to participate in cache invalidation", path)
+		}
+	}
+	if isCacheRelevantFile(filepath.Join("packaging", "zlib.wrap")) {
+		t.Fatalf("did not expect wrap files outside subprojects to participate in cache invalidation")
+	}
+}
+
+func TestIsCacheRelevantFileRecognizesXcodeProjects(t *testing.T) {
+	if !isCacheRelevantFile(filepath.Join("App.xcodeproj", "project.pbxproj")) {
+		t.Fatalf("expected Xcode project files to participate in cache invalidation")
+	}
+}
+
+func TestIsCacheRelevantFileRecognizesEveryAdapterInput(t *testing.T) {
+	for _, path := range []string{
+		"Gemfile", "Gemfile.lock", "demo.gemspec", "lib/app.rb",
+		"Package.swift", "Package.resolved", "Podfile", "Podfile.lock", "Cartfile", "Cartfile.resolved", "Sources/App/main.swift",
+		"pubspec.yaml", "pubspec.lock", "lib/main.dart",
+		"mix.exs", "mix.lock", "lib/demo.ex", "test/demo_test.exs",
+		"run.ps1", "Demo.psm1", "Demo.psd1",
+		"src/App/App.csproj", "src/Lib/Lib.fsproj", "Directory.Packages.props",
+		"conanfile.txt", "conan.lock", "vcpkg.json", "vcpkg-lock.json", "compile_commands.json", "include/demo.hh",
+		"app/src/main/AndroidManifest.xml",
+		"src/main/resources/application.properties", "src/main/resources/application.yml", "config/application.yaml",
+		"src/main/resources/META-INF/services/java.sql.Driver", "src/main/resources/META-INF/spring.factories",
+	} {
+		if !isCacheRelevantFile(path) {
+			t.Fatalf("expected %s to participate in cache invalidation", path)
+		}
+	}
+}
+
 func TestHashFileOrMissingAndWriteFileAtomic(t *testing.T) {
 	dir := t.TempDir()
 	missingPath := filepath.Join(dir, cacheMissingFileName)
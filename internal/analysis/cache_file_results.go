@@ -0,0 +1,191 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	analysisCacheFilesDir = "files"
+	// analysisFileCachePreviewFeature enables the file-level cache tier.
+	analysisFileCachePreviewFeature = "analysis-file-cache-preview"
+)
+
+// fileResultCache is the file-level cache tier handed to adapters when the
+// root-level entry misses. Entries are content addressed: the key digest
+// covers the file's content, its repo-relative path, both schema versions,
+// and the adapter's repo-wide inputs, so a changed file or manifest simply
+// misses and stale entries are never read.
+// One pinned root is opened on first use and reused for the whole run's
+// loads, stores, and touches.
+type fileResultCache struct {
+	cache     *analysisCache
+	mu        sync.Mutex
+	writeRoot *safeio.WriteRoot
+}
+
+type fileResultCacheKey struct {
+	Schema        string `json:"schema"`
+	Adapter       string `json:"adapter"`
+	AdapterSchema string `json:"adapterSchema"`
+	Path          string `json:"path"`
+	ContentDigest string `json:"contentDigest"`
+	Inputs        string `json:"inputs,omitempty"`
+}
+
+// fileTier returns the run's file-level tier, or nil when the cache is
+// disabled, unusable, or the preview feature is off so adapters skip it
+// entirely.
+func (c *analysisCache) fileTier() language.FileCache {
+	if c == nil || !c.options.Enabled || !c.cacheable || !c.fileResults {
+		return nil
+	}
+	if c.files == nil {
+		c.files = &fileResultCache{cache: c}
+	}
+	return c.files
+}
+
+// closeFileTier releases the file tier's write root at the end of a run.
+func (c *analysisCache) closeFileTier() {
+	if c == nil || c.files == nil {
+		return
+	}
+	if err := c.files.close(); err != nil {
+		c.warn("analysis file cache close failed: " + err.Error())
+	}
+}
+
+func (f *fileResultCache) Load(key language.FileCacheKey, dest any) bool {
+	if f.cache.rejectReadHits {
+		f.countLoad(false)
+		return false
+	}
+	digest, err := fileResultDigest(key)
+	if err != nil {
+		f.countLoad(false)
+		return false
+	}
+	f.mu.Lock()
+	root, err := f.openWriteRoot()
+	f.mu.Unlock()
+	var data []byte
+	if err == nil {
+		data, err = root.ReadFile(filepath.Join(analysisCacheFilesDir, digest+".json"))
+	}
+	if err != nil || json.Unmarshal(data, dest) != nil {
+		f.countLoad(false)
+		return false
+	}
+	f.countLoad(true)
+	f.touch(key, digest)
+	return true
+}
+
+func (f *fileResultCache) countLoad(hit bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if hit {
+		f.cache.metadata.FileHits++
+		return
+	}
+	f.cache.metadata.FileMisses++
+}
+
+// touch sets a hit's modification time to now so it records when the entry
+// was last used; `lopper cache prune` evicts least recently used entries
+// first. Unlike touchPointer it does not rewrite the entry, so warm runs do
+// one metadata update per file rather than one atomic write.
+func (f *fileResultCache) touch(key language.FileCacheKey, digest string) {
+	if f.cache.options.ReadOnly {
+		return
+	}
+	f.mu.Lock()
+	writeRoot, err := f.openWriteRoot()
+	f.mu.Unlock()
+	if err == nil {
+		now := time.Now()
+		err = writeRoot.Chtimes(filepath.Join(analysisCacheFilesDir, digest+".json"), now, now)
+	}
+	if err != nil {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.cache.warn("analysis file cache last-used update failed for " + key.Adapter + ":" + key.Path + ": " + err.Error())
+	}
+}
+
+func (f *fileResultCache) Store(key language.FileCacheKey, value any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cache.options.ReadOnly {
+		return
+	}
+	if err := f.store(key, value); err != nil {
+		f.cache.warn("analysis file cache store failed for " + key.Adapter + ":" + key.Path + ": " + err.Error())
+	}
+}
+
+func (f *fileResultCache) store(key language.FileCacheKey, value any) error {
+	digest, err := fileResultDigest(key)
+	if err != nil {
+		return err
+	}
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	writeRoot, err := f.openWriteRoot()
+	if err != nil {
+		return err
+	}
+	err = writeRoot.WriteFileCreatingParentsAtomicallyIfAbsent(filepath.Join(analysisCacheFilesDir, digest+".json"), serialized, 0o640, 0o750)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.cache.metadata.FileWrites++
+	return nil
+}
+
+// openWriteRoot returns the run's shared pinned root, opening it on first use.
+func (f *fileResultCache) openWriteRoot() (*safeio.WriteRoot, error) {
+	if f.writeRoot == nil {
+		writeRoot, err := f.cache.openWriteRoot()
+		if err != nil {
+			return nil, err
+		}
+		f.writeRoot = writeRoot
+	}
+	return f.writeRoot, nil
+}
+
+func (f *fileResultCache) close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.writeRoot == nil {
+		return nil
+	}
+	err := f.writeRoot.Close()
+	f.writeRoot = nil
+	return err
+}
+
+func fileResultDigest(key language.FileCacheKey) (string, error) {
+	return hashJSON(fileResultCacheKey{
+		Schema:        analysisCacheSchemaVersion,
+		Adapter:       key.Adapter,
+		AdapterSchema: key.Schema,
+		Path:          filepath.ToSlash(key.Path),
+		ContentDigest: sha256Hex(key.Content),
+		Inputs:        key.Inputs,
+	})
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/lang/js"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestAnalysisFileCacheReparsesOnlyChangedFiles(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, cacheTestPackageJSONFileName), "{\n  \"name\": \"demo\",\n  \"dependencies\": {\"lodash\": \"^4.17.21\"}\n}\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, cacheTestJSIndexFileName), "import { map } from \"lodash\"\nmap([1], (x) => x)\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "util.js"), "import { filter } from \"lodash\"\nexport const odd = filter([1, 2], (x) => x % 2)\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "node_modules", "lodash", cacheTestPackageJSONFileName), "{\n  \"name\": \"lodash\",\n  \"version\": \"4.17.21\"\n}\n")
+
+	reg := language.NewRegistry()
+	if err := reg.Register(js.NewAdapter()); err != nil {
+		t.Fatalf("register adapter: %v", err)
+	}
+	svc := &Service{Registry: reg}
+	req := newCacheRequest(t, repo, cacheTestDirectoryName, false)
+	req.Features = mustResolveFileCacheFeatures(t)
+	req.Language = "js-ts"
+	req.TopN = 5
+
+	first, err := svc.Analyse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("first analyse: %v", err)
+	}
+	if first.Cache == nil || first.Cache.FileHits != 0 || first.Cache.FileWrites < 2 || first.Cache.FileMisses != first.Cache.FileWrites {
+		t.Fatalf("expected a cold run to parse and store every file, got %#v", first.Cache)
+	}
+
+	testutil.MustWriteFile(t, filepath.Join(repo, cacheTestJSIndexFileName), "import { map, uniq } from \"lodash\"\nuniq(map([1], (x) => x))\n")
+	second, err := svc.Analyse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("second analyse: %v", err)
+	}
+	if second.Cache == nil || second.Cache.Hits != 0 || second.Cache.FileMisses != 1 || second.Cache.FileWrites != 1 || second.Cache.FileHits != first.Cache.FileWrites-1 {
+		t.Fatalf("expected only the changed file to be re-parsed, got %#v", second.Cache)
+	}
+	if len(second.Dependencies) != 1 || len(second.Dependencies[0].UsedImports) != 3 {
+		t.Fatalf("expected cached and fresh file scans to aggregate, got %#v", second.Dependencies)
+	}
+}
+
+func TestAnalysisFileCacheIsDisabledWithoutACache(t *testing.T) {
+	var cache *analysisCache
+	if cache.fileTier() != nil {
+		t.Fatalf("expected no file tier for a nil cache")
+	}
+	repo := t.TempDir()
+	if newAnalysisCache(newCacheRequest(t, repo, cacheTestDirectoryName, false), repo).fileTier() != nil {
+		t.Fatalf("expected no file tier without %s", analysisFileCachePreviewFeature)
+	}
+	var dest struct{}
+	if language.LoadCachedFile(nil, language.FileCacheKey{}, &dest) {
+		t.Fatalf("expected nil file cache to miss")
+	}
+}
+
+func TestAnalysisFileCacheReusesOneWriteRootPerRun(t *testing.T) {
+	repo := t.TempDir()
+	req := newCacheRequest(t, repo, cacheTestDirectoryName, false)
+	req.Features = mustResolveFileCacheFeatures(t)
+	cache := newAnalysisCache(req, repo)
+	tier := cache.fileTier()
+	if tier == nil || cache.fileTier() != tier {
+		t.Fatalf("expected one file tier per run")
+	}
+	files := cache.files
+	tier.Store(language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "a.js", Content: []byte("a")}, map[string]int{"a": 1})
+	writeRoot := files.writeRoot
+	tier.Store(language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "b.js", Content: []byte("b")}, map[string]int{"b": 2})
+	if writeRoot == nil || files.writeRoot != writeRoot || cache.metadata.FileWrites != 2 {
+		t.Fatalf("expected both stores to share one write root, got %#v writes=%d", files.writeRoot, cache.metadata.FileWrites)
+	}
+	var loaded map[string]int
+	if !tier.Load(language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "a.js", Content: []byte("a")}, &loaded) || loaded["a"] != 1 || files.writeRoot != writeRoot {
+		t.Fatalf("expected the load to read through the shared write root, got %#v", loaded)
+	}
+
+	cache.closeFileTier()
+	if files.writeRoot != nil || len(cache.takeWarnings()) != 0 {
+		t.Fatalf("expected the write root to close cleanly at the end of the run")
+	}
+	var dest map[string]int
+	if !tier.Load(language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "b.js", Content: []byte("b")}, &dest) || dest["b"] != 2 {
+		t.Fatalf("expected stored entry to load after close, got %#v", dest)
+	}
+	cache.closeFileTier()
+}
+
+func TestAnalysisFileCacheLoadsThroughThePinnedRoot(t *testing.T) {
+	repo := t.TempDir()
+	req := newCacheRequest(t, repo, cacheTestDirectoryName, false)
+	req.Features = mustResolveFileCacheFeatures(t)
+	cache := newAnalysisCache(req, repo)
+	tier := cache.fileTier()
+	key := language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "a.js", Content: []byte("a")}
+	tier.Store(key, map[string]int{"a": 1})
+	defer cache.closeFileTier()
+
+	cachePath := req.Cache.Path
+	if err := os.Rename(cachePath, cachePath+"-moved"); err != nil {
+		t.Fatalf("move cache root: %v", err)
+	}
+	digest, err := fileResultDigest(key)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	testutil.MustWriteFile(t, filepath.Join(cachePath, analysisCacheFilesDir, digest+".json"), `{"a": 99}`)
+
+	var dest map[string]int
+	if !tier.Load(key, &dest) || dest["a"] != 1 {
+		t.Fatalf("expected the load to read the pinned root rather than the replaced path, got %#v", dest)
+	}
+}
+
+func mustResolveFileCacheFeatures(t *testing.T) featureflags.Set {
+	t.Helper()
+	resolved, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{Channel: featureflags.ChannelDev, Enable: []string{analysisFileCachePreviewFeature}})
+	if err != nil {
+		t.Fatalf("resolve file cache features: %v", err)
+	}
+	return resolved
+}
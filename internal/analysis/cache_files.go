@@ -74,12 +74,12 @@ func shouldSkipCacheDir(name string) bool {
 
 func isCacheRelevantFile(path string) bool {
 	base := strings.ToLower(filepath.Base(path))
-	if lockOrConfigFile(base) {
+	if lockOrConfigFile(base) || isMesonWrapFile(path) || shared.IsJVMRuntimeConfigPath(path) {
 		return true
 	}
 	ext := strings.ToLower(filepath.Ext(base))
 	switch ext {
-	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".py", ".go", ".rs", ".php", ".java", ".kt", ".kts", ".cs", ".fs", ".fsx", ".c", ".cc", ".cpp", ".cxx", ".h", ".hpp":
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".py", ".go", ".rs", ".php", ".java", ".kt", ".kts", ".cs", ".fs", ".fsx", ".csproj", ".fsproj", ".c", ".cc", ".cpp", ".cxx", ".h", ".hh", ".hpp", ".hxx", ".sln", ".slnx", ".cmake", ".rb", ".gemspec", ".swift", ".dart", ".ex", ".exs", ".ps1", ".psm1", ".psd1":
 		return true
 	default:
 		return false
@@ -91,9 +91,13 @@ func lockOrConfigFile(base string) bool {
 		return true
 	}
 	switch base {
-	case "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "package.json", "tsconfig.json", "composer.lock", "composer.json", "cargo.lock", "cargo.toml", "go.mod", "go.sum", "requirements.txt", "requirements-dev.txt", "pipfile", "pipfile.lock", "poetry.lock", "pyproject.toml", "uv.lock", "pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile", "settings.gradle", "settings.gradle.kts", "packages.lock.json", ".lopper.yml", ".lopper.yaml", "lopper.json":
+	case "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "package.json", "tsconfig.json", "composer.lock", "composer.json", "cargo.lock", "cargo.toml", "go.mod", "go.sum", "requirements.txt", "requirements-dev.txt", "pipfile", "pipfile.lock", "poetry.lock", "pyproject.toml", "uv.lock", "pom.xml", "build.gradle", "build.gradle.kts", "gradle.lockfile", "settings.gradle", "settings.gradle.kts", "packages.lock.json", "packages.config", "cmakelists.txt", "conanfile.py", "meson.build", "project.pbxproj", "directory.build.props", "directory.build.targets", "directory.packages.props", "conanfile.txt", "conan.lock", "vcpkg.json", "vcpkg-lock.json", "compile_commands.json", "androidmanifest.xml", "gemfile", "gemfile.lock", "package.resolved", "podfile", "podfile.lock", "cartfile", "cartfile.resolved", "pubspec.yaml", "pubspec.yml", "pubspec.lock", "mix.lock", ".lopper.yml", ".lopper.yaml", "lopper.json":
 		return true
 	default:
 		return false
 	}
 }
+
+func isMesonWrapFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".wrap") && strings.EqualFold(filepath.Base(filepath.Dir(path)), "subprojects")
+}
@@ -0,0 +1,490 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const unknownCacheAdapter = "unknown"
+
+// CacheStats summarises the contents of an analysis cache directory.
+type CacheStats struct {
+	Path                string              `json:"path"`
+	Entries             int                 `json:"entries"`
+	Objects             int                 `json:"objects"`
+	UnreferencedObjects int                 `json:"unreferencedObjects"`
+	FileEntries         int                 `json:"fileEntries"`
+	Bytes               int64               `json:"bytes"`
+	LastUsed            *time.Time          `json:"lastUsed,omitempty"`
+	Adapters            []CacheAdapterStats `json:"adapters,omitempty"`
+}
+
+// CacheAdapterStats is the share of root-level entries written by one adapter.
+// Entries written before pointers recorded their adapter count as "unknown".
+type CacheAdapterStats struct {
+	Adapter  string    `json:"adapter"`
+	Entries  int       `json:"entries"`
+	Bytes    int64     `json:"bytes"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// CacheVerification lists the integrity problems found in a cache directory.
+type CacheVerification struct {
+	Path    string       `json:"path"`
+	Checked int          `json:"checked"`
+	Issues  []CacheIssue `json:"issues,omitempty"`
+}
+
+type CacheIssue struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// CachePruneOptions bounds a cache by entry age and total size. Zero values
+// disable the corresponding bound.
+type CachePruneOptions struct {
+	MaxAge   time.Duration
+	MaxBytes int64
+	Now      time.Time
+}
+
+type CachePruneResult struct {
+	Path               string `json:"path"`
+	RemovedEntries     int    `json:"removedEntries"`
+	RemovedObjects     int    `json:"removedObjects"`
+	RemovedFileEntries int    `json:"removedFileEntries"`
+	FreedBytes         int64  `json:"freedBytes"`
+	RemainingBytes     int64  `json:"remainingBytes"`
+}
+
+type cacheFileInfo struct {
+	Dir     string
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+func (f cacheFileInfo) rel() string {
+	return filepath.Join(f.Dir, f.Name)
+}
+
+func (f cacheFileInfo) digest() string {
+	return strings.TrimSuffix(f.Name, ".json")
+}
+
+type cachePointerFile struct {
+	cacheFileInfo
+	Pointer cachePointer
+	Valid   bool
+}
+
+// cacheInventory is one listing of keys/, objects/, and files/ taken through
+// a root pinned to the cache directory, so maintenance never follows a path
+// outside it.
+type cacheInventory struct {
+	Pointers []cachePointerFile
+	Objects  map[string]cacheFileInfo
+	Files    []cacheFileInfo
+	Issues   []CacheIssue
+}
+
+// InspectCache reports entry counts, sizes, and last-used times for the cache
+// at cachePath.
+func InspectCache(cachePath string) (stats CacheStats, err error) {
+	root, err := openMaintainedCacheRoot(cachePath)
+	if err != nil {
+		return CacheStats{}, err
+	}
+	defer func() {
+		err = errors.Join(err, root.Close())
+	}()
+	inventory, err := readCacheInventory(root)
+	if err != nil {
+		return CacheStats{}, err
+	}
+
+	stats = CacheStats{Path: cachePath, Entries: len(inventory.Pointers), Objects: len(inventory.Objects), FileEntries: len(inventory.Files)}
+	referenced := inventory.referencedObjects()
+	adapters := make(map[string]*CacheAdapterStats)
+	var lastUsed time.Time
+	for _, pointer := range inventory.Pointers {
+		adapter := pointer.Pointer.Adapter
+		if adapter == "" {
+			adapter = unknownCacheAdapter
+		}
+		current, ok := adapters[adapter]
+		if !ok {
+			current = &CacheAdapterStats{Adapter: adapter}
+			adapters[adapter] = current
+		}
+		current.Entries++
+		current.Bytes += pointer.Size
+		if object, ok := inventory.Objects[pointer.Pointer.ObjectDigest]; ok && pointer.Valid {
+			current.Bytes += object.Size
+		}
+		if pointer.ModTime.After(current.LastUsed) {
+			current.LastUsed = pointer.ModTime
+		}
+		if pointer.ModTime.After(lastUsed) {
+			lastUsed = pointer.ModTime
+		}
+		stats.Bytes += pointer.Size
+	}
+	for digest, object := range inventory.Objects {
+		if referenced[digest] == 0 {
+			stats.UnreferencedObjects++
+		}
+		stats.Bytes += object.Size
+	}
+	for _, file := range inventory.Files {
+		stats.Bytes += file.Size
+		if file.ModTime.After(lastUsed) {
+			lastUsed = file.ModTime
+		}
+	}
+	if !lastUsed.IsZero() {
+		stats.LastUsed = &lastUsed
+	}
+	for _, name := range slices.Sorted(maps.Keys(adapters)) {
+		stats.Adapters = append(stats.Adapters, *adapters[name])
+	}
+	return stats, nil
+}
+
+// VerifyCache checks that every pointer parses and names an existing object,
+// that every object's content matches its digest name, and that file-tier
+// entries are well-formed JSON.
+func VerifyCache(cachePath string) (verification CacheVerification, err error) {
+	root, err := openMaintainedCacheRoot(cachePath)
+	if err != nil {
+		return CacheVerification{}, err
+	}
+	defer func() {
+		err = errors.Join(err, root.Close())
+	}()
+	inventory, err := readCacheInventory(root)
+	if err != nil {
+		return CacheVerification{}, err
+	}
+
+	verification = CacheVerification{Path: cachePath, Issues: inventory.Issues}
+	for _, pointer := range inventory.Pointers {
+		verification.Checked++
+		switch {
+		case !pointer.Valid:
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(pointer.rel()), Reason: "pointer-corrupt"})
+		case !hasCacheObject(inventory.Objects, pointer.Pointer.ObjectDigest):
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(pointer.rel()), Reason: "object-missing"})
+		}
+	}
+	for _, digest := range slices.Sorted(maps.Keys(inventory.Objects)) {
+		object := inventory.Objects[digest]
+		verification.Checked++
+		data, readErr := safeio.ReadFileWithinRoot(root, object.rel())
+		if readErr != nil {
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(object.rel()), Reason: "object-read-error"})
+			continue
+		}
+		if sha256Hex(data) != digest {
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(object.rel()), Reason: "digest-mismatch"})
+			continue
+		}
+		var payload cachedPayload
+		if json.Unmarshal(data, &payload) != nil || !payload.restoreUsageIncomplete() || !payload.restoreSuppressedUnusedImports() {
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(object.rel()), Reason: cacheObjectCorruptReason})
+		}
+	}
+	for _, file := range inventory.Files {
+		verification.Checked++
+		data, readErr := safeio.ReadFileWithinRoot(root, file.rel())
+		if readErr != nil || !json.Valid(data) {
+			verification.Issues = append(verification.Issues, CacheIssue{File: filepath.ToSlash(file.rel()), Reason: "file-entry-corrupt"})
+		}
+	}
+	return verification, nil
+}
+
+// PruneCache removes unreferenced objects, then entries older than MaxAge,
+// then least recently used entries until the cache fits in MaxBytes. An
+// object is removed once no remaining pointer references it.
+func PruneCache(cachePath string, options CachePruneOptions) (result CachePruneResult, err error) {
+	root, err := openMaintainedCacheRoot(cachePath)
+	if err != nil {
+		return CachePruneResult{}, err
+	}
+	defer func() {
+		err = errors.Join(err, root.Close())
+	}()
+	inventory, err := readCacheInventory(root)
+	if err != nil {
+		return CachePruneResult{}, err
+	}
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+
+	result = CachePruneResult{Path: cachePath}
+	pruner := cachePruner{root: root, inventory: inventory, result: &result, references: inventory.referencedObjects()}
+	for _, digest := range slices.Sorted(maps.Keys(inventory.Objects)) {
+		if pruner.references[digest] == 0 {
+			if err := pruner.removeObject(digest); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	candidates := inventory.evictionOrder()
+	total := inventory.retainedBytes(pruner.references)
+	for _, candidate := range candidates {
+		expired := options.MaxAge > 0 && options.Now.Sub(candidate.ModTime) > options.MaxAge
+		oversized := options.MaxBytes > 0 && total > options.MaxBytes
+		if !expired && !oversized {
+			continue
+		}
+		freed, err := pruner.evict(candidate)
+		if err != nil {
+			return result, err
+		}
+		total -= freed
+	}
+	result.RemainingBytes = total
+	return result, nil
+}
+
+// ClearCache removes every entry from the cache while keeping the directory
+// layout in place.
+func ClearCache(cachePath string) (result CachePruneResult, err error) {
+	root, err := openMaintainedCacheRoot(cachePath)
+	if err != nil {
+		return CachePruneResult{}, err
+	}
+	defer func() {
+		err = errors.Join(err, root.Close())
+	}()
+	inventory, err := readCacheInventory(root)
+	if err != nil {
+		return CachePruneResult{}, err
+	}
+
+	result = CachePruneResult{Path: cachePath}
+	pruner := cachePruner{root: root, inventory: inventory, result: &result, references: inventory.referencedObjects()}
+	for _, candidate := range inventory.evictionOrder() {
+		if _, err := pruner.evict(candidate); err != nil {
+			return result, err
+		}
+	}
+	for _, digest := range slices.Sorted(maps.Keys(inventory.Objects)) {
+		if err := pruner.removeObject(digest); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+type cacheEvictionCandidate struct {
+	cacheFileInfo
+	ObjectDigest string
+	Pointer      bool
+}
+
+type cachePruner struct {
+	root       safeio.Root
+	inventory  cacheInventory
+	result     *CachePruneResult
+	references map[string]int
+}
+
+func (p *cachePruner) evict(candidate cacheEvictionCandidate) (int64, error) {
+	if err := p.root.Remove(candidate.rel()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	freed := candidate.Size
+	p.result.FreedBytes += candidate.Size
+	if !candidate.Pointer {
+		p.result.RemovedFileEntries++
+		return freed, nil
+	}
+	p.result.RemovedEntries++
+	if candidate.ObjectDigest == "" {
+		return freed, nil
+	}
+	p.references[candidate.ObjectDigest]--
+	if p.references[candidate.ObjectDigest] > 0 {
+		return freed, nil
+	}
+	if object, ok := p.inventory.Objects[candidate.ObjectDigest]; ok {
+		freed += object.Size
+	}
+	return freed, p.removeObject(candidate.ObjectDigest)
+}
+
+func (p *cachePruner) removeObject(digest string) error {
+	object, ok := p.inventory.Objects[digest]
+	if !ok {
+		return nil
+	}
+	if err := p.root.Remove(object.rel()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	delete(p.inventory.Objects, digest)
+	p.result.RemovedObjects++
+	p.result.FreedBytes += object.Size
+	return nil
+}
+
+func openMaintainedCacheRoot(cachePath string) (safeio.Root, error) {
+	if strings.TrimSpace(cachePath) == "" {
+		return nil, errors.New("analysis cache path is empty")
+	}
+	root, err := safeio.OpenRootNoFollow(cachePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("analysis cache not found at %s", cachePath)
+		}
+		return nil, err
+	}
+	if _, err := verifyPinnedAnalysisCacheDirectory(root, cachePath); err != nil {
+		return nil, errors.Join(err, root.Close())
+	}
+	return root, nil
+}
+
+func readCacheInventory(root safeio.Root) (cacheInventory, error) {
+	inventory := cacheInventory{Objects: make(map[string]cacheFileInfo)}
+	pointers, err := listCacheDir(root, "keys", &inventory.Issues)
+	if err != nil {
+		return cacheInventory{}, err
+	}
+	for _, info := range pointers {
+		pointer := cachePointerFile{cacheFileInfo: info}
+		if data, readErr := safeio.ReadFileWithinRoot(root, info.rel()); readErr == nil && json.Unmarshal(data, &pointer.Pointer) == nil {
+			pointer.Valid = isCacheDigest(pointer.Pointer.ObjectDigest)
+		}
+		inventory.Pointers = append(inventory.Pointers, pointer)
+	}
+	objects, err := listCacheDir(root, "objects", &inventory.Issues)
+	if err != nil {
+		return cacheInventory{}, err
+	}
+	for _, info := range objects {
+		inventory.Objects[info.digest()] = info
+	}
+	if inventory.Files, err = listCacheDir(root, analysisCacheFilesDir, &inventory.Issues); err != nil {
+		return cacheInventory{}, err
+	}
+	return inventory, nil
+}
+
+// listCacheDir returns the regular .json entries of one cache subdirectory.
+// Symlinks and other non-regular entries are reported rather than followed.
+func listCacheDir(root safeio.Root, dir string, issues *[]CacheIssue) (_ []cacheFileInfo, err error) {
+	handle, err := safeio.OpenPinnedDirectory(root, dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, handle.Close())
+	}()
+	entries, err := handle.ReadDir(-1)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]cacheFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			*issues = append(*issues, CacheIssue{File: filepath.ToSlash(filepath.Join(dir, entry.Name())), Reason: "not-regular-file"})
+			continue
+		}
+		files = append(files, cacheFileInfo{Dir: dir, Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b cacheFileInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return files, nil
+}
+
+func (inventory cacheInventory) referencedObjects() map[string]int {
+	references := make(map[string]int)
+	for _, pointer := range inventory.Pointers {
+		if pointer.Valid {
+			references[pointer.Pointer.ObjectDigest]++
+		}
+	}
+	return references
+}
+
+// evictionOrder lists pointers and file-tier entries least recently used
+// first. Pointers are rewritten and file-tier entries have their modification
+// time refreshed on every hit, so both record when they were last used.
+func (inventory cacheInventory) evictionOrder() []cacheEvictionCandidate {
+	candidates := make([]cacheEvictionCandidate, 0, len(inventory.Pointers)+len(inventory.Files))
+	for _, pointer := range inventory.Pointers {
+		candidate := cacheEvictionCandidate{cacheFileInfo: pointer.cacheFileInfo, Pointer: true}
+		if pointer.Valid {
+			candidate.ObjectDigest = pointer.Pointer.ObjectDigest
+		}
+		candidates = append(candidates, candidate)
+	}
+	for _, file := range inventory.Files {
+		candidates = append(candidates, cacheEvictionCandidate{cacheFileInfo: file})
+	}
+	slices.SortStableFunc(candidates, func(a, b cacheEvictionCandidate) int {
+		if cmp := a.ModTime.Compare(b.ModTime); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(a.rel(), b.rel())
+	})
+	return candidates
+}
+
+func (inventory cacheInventory) retainedBytes(references map[string]int) int64 {
+	var total int64
+	for _, pointer := range inventory.Pointers {
+		total += pointer.Size
+	}
+	for digest, object := range inventory.Objects {
+		if references[digest] > 0 {
+			total += object.Size
+		}
+	}
+	for _, file := range inventory.Files {
+		total += file.Size
+	}
+	return total
+}
+
+func hasCacheObject(objects map[string]cacheFileInfo, digest string) bool {
+	_, ok := objects[digest]
+	return ok
+}
+
+func isCacheDigest(value string) bool {
+	if len(value) != 64 {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestCacheMaintenanceInspectVerifyAndClear(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, cacheTestJSIndexFileName), "import dep from \"dep\"\n")
+	svc, _ := newCacheTestService(t)
+	cacheDir := filepath.Join(t.TempDir(), cacheTestDirectoryName)
+	req := newCacheRequest(t, repo, cacheDir, false)
+	if _, err := svc.Analyse(context.Background(), req); err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+
+	stats, err := InspectCache(cacheDir)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if stats.Entries != 1 || stats.Objects != 1 || stats.UnreferencedObjects != 0 || stats.Bytes == 0 || stats.LastUsed == nil {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+	if len(stats.Adapters) != 1 || stats.Adapters[0].Adapter != "cachelang" || stats.Adapters[0].Entries != 1 {
+		t.Fatalf("expected per-adapter breakdown, got %#v", stats.Adapters)
+	}
+
+	verification, err := VerifyCache(cacheDir)
+	if err != nil || verification.Checked != 2 || len(verification.Issues) != 0 {
+		t.Fatalf("expected a clean cache, got %#v err=%v", verification, err)
+	}
+	objectPath := mustSingleCacheFile(t, filepath.Join(cacheDir, "objects"))
+	mustWriteFile(t, objectPath, []byte(`{"report":{}}`))
+	mustWriteFile(t, filepath.Join(cacheDir, "keys", "broken.json"), []byte("{"))
+	if err := os.Symlink(objectPath, filepath.Join(cacheDir, "files", "linked.json")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	verification, err = VerifyCache(cacheDir)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	reasons := make([]string, 0, len(verification.Issues))
+	for _, issue := range verification.Issues {
+		reasons = append(reasons, issue.Reason)
+	}
+	slices.Sort(reasons)
+	if !slices.Equal(reasons, []string{"digest-mismatch", "not-regular-file", "pointer-corrupt"}) {
+		t.Fatalf("unexpected verification issues %#v", verification.Issues)
+	}
+
+	result, err := ClearCache(cacheDir)
+	if err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if result.RemovedEntries != 2 || result.RemovedObjects != 1 {
+		t.Fatalf("unexpected clear result %#v", result)
+	}
+	if _, err := os.Lstat(filepath.Join(cacheDir, "files", "linked.json")); err != nil {
+		t.Fatalf("expected clear to leave non-regular entries untouched: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "keys")); err != nil {
+		t.Fatalf("expected cache layout to remain: %v", err)
+	}
+}
+
+func TestPruneCacheEvictsExpiredAndLeastRecentlyUsedEntries(t *testing.T) {
+	cacheDir := t.TempDir()
+	mustMkdirCacheLayout(t, cacheDir)
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	shared := writeTestCacheObject(t, cacheDir, `{"report":{"repoPath":"shared"}}`)
+	fresh := writeTestCacheObject(t, cacheDir, `{"report":{"repoPath":"fresh"}}`)
+	orphan := writeTestCacheObject(t, cacheDir, `{"report":{"repoPath":"orphan"}}`)
+	writeTestCachePointer(t, cacheDir, "old", shared, now.Add(-60*24*time.Hour))
+	writeTestCachePointer(t, cacheDir, "middle", shared, now.Add(-2*time.Hour))
+	writeTestCachePointer(t, cacheDir, "recent", fresh, now.Add(-time.Hour))
+	filePath := filepath.Join(cacheDir, "files", "entry.json")
+	mustWriteFile(t, filePath, []byte(`{"Scan":{}}`))
+	mustChtimes(t, filePath, now.Add(-3*time.Hour))
+
+	result, err := PruneCache(cacheDir, CachePruneOptions{MaxAge: 30 * 24 * time.Hour, Now: now})
+	if err != nil {
+		t.Fatalf("prune by age: %v", err)
+	}
+	if result.RemovedEntries != 1 || result.RemovedObjects != 1 || result.RemovedFileEntries != 0 {
+		t.Fatalf("expected the expired pointer and orphaned object to go, got %#v", result)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "objects", shared+".json")); err != nil {
+		t.Fatalf("expected object still referenced by another pointer to stay: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "objects", orphan+".json")); !os.IsNotExist(err) {
+		t.Fatalf("expected orphaned object to be removed, got %v", err)
+	}
+
+	stats, err := InspectCache(cacheDir)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	recentBytes := fileSize(t, filepath.Join(cacheDir, "keys", "recent.json")) + fileSize(t, filepath.Join(cacheDir, "objects", fresh+".json"))
+	result, err = PruneCache(cacheDir, CachePruneOptions{MaxBytes: recentBytes, Now: now})
+	if err != nil {
+		t.Fatalf("prune by size: %v", err)
+	}
+	if result.RemovedEntries != 1 || result.RemovedFileEntries != 1 || result.RemainingBytes != recentBytes || result.FreedBytes != stats.Bytes-recentBytes {
+		t.Fatalf("expected least recently used entries evicted down to the budget, got %#v (before %d bytes)", result, stats.Bytes)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "keys", "recent.json")); err != nil {
+		t.Fatalf("expected most recently used pointer to stay: %v", err)
+	}
+}
+
+func TestPruneCacheKeepsRecentlyLoadedFileEntries(t *testing.T) {
+	repo := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), cacheTestDirectoryName)
+	req := newCacheRequest(t, repo, cacheDir, false)
+	req.Features = mustResolveFileCacheFeatures(t)
+	cache := newAnalysisCache(req, repo)
+	tier := cache.fileTier()
+	hot := language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "hot.js", Content: []byte("hot")}
+	cold := language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "cold.js", Content: []byte("cold")}
+	tier.Store(hot, map[string]int{"hot": 1})
+	tier.Store(cold, map[string]int{"cold": 1})
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	paths, err := filepath.Glob(filepath.Join(cacheDir, "files", "*.json"))
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("expected two file entries, got %v err=%v", paths, err)
+	}
+	for _, path := range paths {
+		mustChtimes(t, path, old)
+	}
+
+	digest, err := fileResultDigest(hot)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	hotPath := filepath.Join(cacheDir, "files", digest+".json")
+	before, err := os.Stat(hotPath)
+	if err != nil {
+		t.Fatalf("stat hot entry: %v", err)
+	}
+
+	var dest map[string]int
+	if !tier.Load(hot, &dest) {
+		t.Fatalf("expected stored entry to load")
+	}
+	cache.closeFileTier()
+	if warnings := cache.takeWarnings(); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %#v", warnings)
+	}
+	after, err := os.Stat(hotPath)
+	if err != nil || !os.SameFile(before, after) || !after.ModTime().After(old) {
+		t.Fatalf("expected the hit to refresh the entry's mtime in place, got %v err=%v", after, err)
+	}
+
+	result, err := PruneCache(cacheDir, CachePruneOptions{MaxAge: 30 * 24 * time.Hour, Now: time.Now()})
+	if err != nil {
+		t.Fatalf("prune by age: %v", err)
+	}
+	if result.RemovedFileEntries != 1 {
+		t.Fatalf("expected only the unused file entry to expire, got %#v", result)
+	}
+	if _, err := os.Stat(hotPath); err != nil {
+		t.Fatalf("expected recently loaded file entry to survive: %v", err)
+	}
+}
+
+func TestAnalysisFileCacheReadOnlyLoadKeepsModTime(t *testing.T) {
+	repo := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), cacheTestDirectoryName)
+	req := newCacheRequest(t, repo, cacheDir, false)
+	req.Features = mustResolveFileCacheFeatures(t)
+	key := language.FileCacheKey{Adapter: "js-ts", Schema: "1", Path: "a.js", Content: []byte("a")}
+	writer := newAnalysisCache(req, repo)
+	writer.fileTier().Store(key, map[string]int{"a": 1})
+	writer.closeFileTier()
+	path := mustSingleCacheFile(t, filepath.Join(cacheDir, "files"))
+	old := time.Now().Add(-60 * 24 * time.Hour).Truncate(time.Second)
+	mustChtimes(t, path, old)
+
+	req.Cache.ReadOnly = true
+	reader := newAnalysisCache(req, repo)
+	var dest map[string]int
+	if !reader.fileTier().Load(key, &dest) {
+		t.Fatalf("expected read-only load to hit")
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().Equal(old) {
+		t.Fatalf("expected read-only load to leave the entry untouched, got %v err=%v", info, err)
+	}
+}
+
+func TestCacheMaintenanceRejectsMissingAndSymlinkedRoots(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := InspectCache(missing); err == nil {
+		t.Fatalf("expected missing cache error")
+	}
+	target := t.TempDir()
+	mustMkdirCacheLayout(t, target)
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if _, err := ClearCache(link); err == nil {
+		t.Fatalf("expected symlinked cache root to be rejected")
+	}
+}
+
+func writeTestCacheObject(t *testing.T, cacheDir, payload string) string {
+	t.Helper()
+	digest := sha256Hex([]byte(payload))
+	mustWriteFile(t, filepath.Join(cacheDir, "objects", digest+".json"), []byte(payload))
+	return digest
+}
+
+func writeTestCachePointer(t *testing.T, cacheDir, key, objectDigest string, modTime time.Time) {
+	t.Helper()
+	data, err := json.Marshal(cachePointer{Adapter: "js-ts", InputDigest: "input", ObjectDigest: objectDigest})
+	if err != nil {
+		t.Fatalf("marshal pointer: %v", err)
+	}
+	path := filepath.Join(cacheDir, "keys", key+".json")
+	mustWriteFile(t, path, data)
+	mustChtimes(t, path, modTime)
+}
+
+func mustChtimes(t *testing.T, path string, modTime time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	return info.Size()
+}
+
+func mustSingleCacheFile(t *testing.T, dir string) string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cache file in %s, got %d (%v)", dir, len(entries), err)
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
@@ -13,6 +13,7 @@ import (
 const cacheObjectCorruptReason = "object-corrupt"
 
 type cachePointer struct {
+	Adapter      string `json:"adapter,omitempty"`
 	InputDigest  string `json:"inputDigest"`
 	ObjectDigest string `json:"objectDigest"`
 }
@@ -84,9 +85,33 @@ func (c *analysisCache) lookup(entry cacheEntryDescriptor) (report.Report, bool,
 		return report.Report{}, false, nil
 	}
 	c.metadata.Hits++
+	c.touchPointer(entry, pointerData)
 	return payload.Report, true, nil
 }
 
+// touchPointer rewrites a hit's pointer so its modification time records when
+// the entry was last used; `lopper cache prune` evicts least recently used
+// entries first.
+func (c *analysisCache) touchPointer(entry cacheEntryDescriptor, pointerData []byte) {
+	if c.options.ReadOnly {
+		return
+	}
+	if err := c.writePointer(entry.KeyDigest, pointerData); err != nil {
+		c.warn("analysis cache last-used update failed for " + entry.KeyLabel + ": " + err.Error())
+	}
+}
+
+func (c *analysisCache) writePointer(keyDigest string, data []byte) (returnErr error) {
+	writeRoot, err := c.openWriteRoot()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		returnErr = errors.Join(returnErr, writeRoot.Close())
+	}()
+	return writeRoot.WriteFileCreatingParents(filepath.Join("keys", keyDigest+".json"), data, 0o640, 0o750)
+}
+
 func (c *analysisCache) rejectDefaultCacheRead(entry cacheEntryDescriptor) (bool, error) {
 	if !c.rejectReadHits {
 		return false, nil
@@ -142,7 +167,7 @@ func (c *analysisCache) store(entry cacheEntryDescriptor, data report.Report) (r
 		return err
 	}
 
-	pointer := cachePointer{InputDigest: entry.InputDigest, ObjectDigest: objectDigest}
+	pointer := cachePointer{Adapter: entry.Adapter, InputDigest: entry.InputDigest, ObjectDigest: objectDigest}
 	serializedPointer, err := json.Marshal(pointer)
 	if err != nil {
 		return err
@@ -59,7 +59,11 @@ func (s *Service) runCandidateOnRoots(ctx context.Context, req Request, repoPath
 		cacheEntry, cachedReport, hit := prepareAndLoadCachedReport(req, cache, candidate.Adapter.ID(), normalizedRoot)
 		if hit {
 			applyLanguageID(cachedReport.Dependencies, candidate.Adapter.ID())
+			recordUnusedRoot(repoPath, normalizedRoot, cachedReport.Dependencies)
 			adjustRelativeLocations(repoPath, normalizedRoot, cachedReport.Dependencies)
+			if err := streamRootDependencies(req, repoPath, normalizedRoot, cachedReport.Dependencies); err != nil {
+				return nil, nil, nil, err
+			}
 			reports = append(reports, cachedReport)
 			continue
 		}
@@ -74,6 +78,7 @@ func (s *Service) runCandidateOnRoots(ctx context.Context, req Request, repoPath
 			MinUsagePercentForRecommendations: req.MinUsagePercentForRecommendations,
 			RemovalCandidateWeights:           req.RemovalCandidateWeights,
 			IncludeRegistryProvenance:         req.IncludeRegistryProvenance,
+			FileCache:                         cache.fileTier(),
 		})
 		if err != nil {
 			if isMultiLanguage(req.Language) {
@@ -84,12 +89,27 @@ func (s *Service) runCandidateOnRoots(ctx context.Context, req Request, repoPath
 		}
 		storeCachedReport(cache, candidate.Adapter.ID(), normalizedRoot, cacheEntry, current)
 		applyLanguageID(current.Dependencies, candidate.Adapter.ID())
+		recordUnusedRoot(repoPath, normalizedRoot, current.Dependencies)
 		adjustRelativeLocations(repoPath, normalizedRoot, current.Dependencies)
+		if err := streamRootDependencies(req, repoPath, normalizedRoot, current.Dependencies); err != nil {
+			return nil, nil, nil, err
+		}
 		reports = append(reports, current)
 	}
 	return reports, warnings, analyzedRoots, nil
 }
 
+func streamRootDependencies(req Request, repoPath, normalizedRoot string, dependencies []report.DependencyReport) error {
+	if req.DependencyStream == nil {
+		return nil
+	}
+	root, err := filepath.Rel(repoPath, normalizedRoot)
+	if err != nil {
+		return err
+	}
+	return req.DependencyStream(filepath.ToSlash(root), dependencies)
+}
+
 func alreadySeenRoot(seen map[string]struct{}, normalizedRoot string) bool {
 	if _, ok := seen[normalizedRoot]; ok {
 		return true
@@ -132,6 +152,21 @@ func applyLanguageID(dependencies []report.DependencyReport, languageID string)
 	}
 }
 
+// recordUnusedRoot notes analyzedRoot on the dependencies its adapter
+// confirmed unused, so codemods can tell which root's manifest to edit once
+// reports from several roots are merged.
+func recordUnusedRoot(repoPath string, analyzedRoot string, dependencies []report.DependencyReport) {
+	root, err := filepath.Rel(repoPath, analyzedRoot)
+	if err != nil {
+		return
+	}
+	for i := range dependencies {
+		if report.ConfirmedUnused(dependencies[i]) {
+			dependencies[i].UnusedRoots = []string{filepath.ToSlash(root)}
+		}
+	}
+}
+
 func adjustRelativeLocations(repoPath string, analyzedRoot string, dependencies []report.DependencyReport) {
 	prefix, err := filepath.Rel(repoPath, analyzedRoot)
 	if err != nil || prefix == "." || prefix == "" {
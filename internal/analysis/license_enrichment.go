@@ -0,0 +1,292 @@
+package analysis
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	licenseDetectionPreviewFeature        = "license-detection-ecosystems-preview"
+	licenseExpressionPolicyPreviewFeature = "license-expression-policy-preview"
+	licenseMetadataReadLimit              = 4 << 20
+	licenseSourceLicenseFile              = "license-file"
+)
+
+// licenseScan holds the repository manifests and wanted dependency keys for
+// one license enrichment pass. Detectors only resolve metadata for names in
+// wanted so package caches are never enumerated wholesale. templates enables
+// classifying license and NOTICE files against the bundled SPDX templates.
+type licenseScan struct {
+	repoPath  string
+	wanted    map[string]map[string]struct{}
+	manifests licenseManifestSnapshot
+	warnings  *identityWarningCollector
+	templates bool
+}
+
+type licenseManifestSnapshot struct {
+	goModFiles      []string
+	cargoLockFiles  []string
+	composerLocks   []string
+	gemfileLocks    []string
+	pubLocks        []string
+	dotnetManifests []string
+	pythonEnvs      []string
+}
+
+type licenseDetector func(scan *licenseScan) map[string]*report.DependencyLicense
+
+var licenseDetectors = map[string]licenseDetector{
+	"python": detectPythonDependencyLicenses,
+	"rust":   detectCargoDependencyLicenses,
+	"go":     detectGoDependencyLicenses,
+	"php":    detectComposerDependencyLicenses,
+	"ruby":   detectGemDependencyLicenses,
+	"dotnet": detectNuGetDependencyLicenses,
+	"dart":   detectPubDependencyLicenses,
+}
+
+// annotateDependencyLicenses fills in licenses for non-JS dependencies from
+// locally installed package metadata so license policy applies repo-wide.
+// Dependencies that already carry license data are left untouched.
+func annotateDependencyLicenses(repoPath string, reportData *report.Report, templates bool) {
+	if reportData == nil || len(reportData.Dependencies) == 0 {
+		return
+	}
+	scan := &licenseScan{
+		repoPath:  repoPath,
+		wanted:    map[string]map[string]struct{}{},
+		warnings:  newIdentityWarningCollector(repoPath),
+		templates: templates,
+	}
+	for _, dep := range reportData.Dependencies {
+		languageID := strings.ToLower(strings.TrimSpace(dep.Language))
+		if dep.License != nil || licenseDetectors[languageID] == nil {
+			continue
+		}
+		if scan.wanted[languageID] == nil {
+			scan.wanted[languageID] = map[string]struct{}{}
+		}
+		scan.wanted[languageID][licenseLookupKey(languageID, dep.Name)] = struct{}{}
+	}
+	if len(scan.wanted) == 0 {
+		return
+	}
+	scan.manifests = discoverLicenseManifests(repoPath, scan.warnings)
+
+	detected := make(map[string]map[string]*report.DependencyLicense, len(scan.wanted))
+	for languageID := range scan.wanted {
+		detected[languageID] = licenseDetectors[languageID](scan)
+	}
+	for i := range reportData.Dependencies {
+		dep := &reportData.Dependencies[i]
+		languageID := strings.ToLower(strings.TrimSpace(dep.Language))
+		if dep.License != nil {
+			continue
+		}
+		if license, ok := detected[languageID][licenseLookupKey(languageID, dep.Name)]; ok {
+			copied := *license
+			copied.Evidence = append([]string(nil), license.Evidence...)
+			dep.License = &copied
+		}
+	}
+	reportData.Warnings = sortedUnique(append(reportData.Warnings, scan.warnings.list()...))
+}
+
+func (s *licenseScan) wants(languageID, name string) bool {
+	_, ok := s.wanted[languageID][licenseLookupKey(languageID, name)]
+	return ok
+}
+
+// licenseLookupKey only lowercases Ruby names: RubyGems treats names that
+// differ by `_` and `-` as different gems.
+func licenseLookupKey(languageID, name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	switch languageID {
+	case "python":
+		return report.CanonicalPackageNameForEcosystem("pypi", key)
+	case "rust", "php":
+		return strings.ReplaceAll(key, "_", "-")
+	default:
+		return key
+	}
+}
+
+func discoverLicenseManifests(repoPath string, warnings *identityWarningCollector) licenseManifestSnapshot {
+	snapshot := licenseManifestSnapshot{}
+	_ = filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			warnings.addFailure("discovery", path, identityDiscoveryFailed, err)
+			return nil
+		}
+		if entry.IsDir() {
+			if path == repoPath {
+				return nil
+			}
+			if isPythonEnvDirName(entry.Name()) {
+				snapshot.pythonEnvs = append(snapshot.pythonEnvs, path)
+			}
+			if shouldSkipIdentityDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		recordLicenseManifest(&snapshot, path, entry.Name())
+		return nil
+	})
+	return snapshot
+}
+
+func recordLicenseManifest(snapshot *licenseManifestSnapshot, path, base string) {
+	switch {
+	case base == goModFileName:
+		snapshot.goModFiles = append(snapshot.goModFiles, path)
+	case base == cargoLockFileName:
+		snapshot.cargoLockFiles = append(snapshot.cargoLockFiles, path)
+	case base == composerIdentityLockName:
+		snapshot.composerLocks = append(snapshot.composerLocks, path)
+	case base == "Gemfile.lock":
+		snapshot.gemfileLocks = append(snapshot.gemfileLocks, path)
+	case base == pubIdentityLockName:
+		snapshot.pubLocks = append(snapshot.pubLocks, path)
+	case base == dotnetLockFileName, base == dotnetCentralFileName, isDotNetProjectFileName(base):
+		snapshot.dotnetManifests = append(snapshot.dotnetManifests, path)
+	}
+}
+
+func isDotNetProjectFileName(base string) bool {
+	switch strings.ToLower(filepath.Ext(base)) {
+	case ".csproj", ".fsproj", ".vbproj":
+		return true
+	default:
+		return false
+	}
+}
+
+func readLicenseMetadata(root, path string, warnings *identityWarningCollector) ([]byte, bool) {
+	content, err := safeio.ReadFileUnderLimit(root, path, licenseMetadataReadLimit)
+	if err != nil {
+		warnings.addFailure("read", path, identityReadFailed, err)
+		return nil, false
+	}
+	return content, true
+}
+
+// declaredDependencyLicense builds a license from package metadata that
+// declares an SPDX expression, mirroring how package.json licenses are read.
+func declaredDependencyLicense(raw, source, evidence string) *report.DependencyLicense {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	spdx := shared.NormalizeSPDXExpression(raw)
+	confidence := "high"
+	if spdx == "" {
+		confidence = "medium"
+	}
+	return &report.DependencyLicense{
+		SPDX:       spdx,
+		Raw:        raw,
+		Source:     source,
+		Confidence: confidence,
+		Unknown:    spdx == "",
+		Evidence:   licenseEvidence(evidence),
+	}
+}
+
+// detectDirectoryLicense classifies the first recognisable license file at
+// the top of an installed package directory.
+func (s *licenseScan) detectDirectoryLicense(root, dir, label string) *report.DependencyLicense {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, 2)
+	for _, entry := range entries {
+		if !entry.IsDir() && (shared.IsLicenseFileName(entry.Name()) || (s.templates && shared.IsLicenseNoticeFileName(entry.Name()))) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if license := s.classifyLicenseFile(root, filepath.Join(dir, name), label); license != nil {
+			return license
+		}
+	}
+	return nil
+}
+
+func (s *licenseScan) classifyLicenseFile(root, path, label string) *report.DependencyLicense {
+	content, err := safeio.ReadFileUnderLimit(root, path, licenseMetadataReadLimit)
+	if err != nil {
+		return nil
+	}
+	match, ok := shared.DetectLicenseText(string(content), s.templates)
+	if !ok {
+		return nil
+	}
+	evidence := licenseEvidence(licenseEvidenceLabel(label, root, path))
+	if template := match.Evidence(); template != "" {
+		evidence = append(evidence, template)
+	}
+	return &report.DependencyLicense{
+		SPDX:       match.SPDX,
+		Source:     licenseSourceLicenseFile,
+		Confidence: match.Confidence,
+		Evidence:   evidence,
+	}
+}
+
+// licenseEvidenceLabel keeps evidence portable: repository files are shown
+// relative to the repo and cache files relative to a named cache root.
+func licenseEvidenceLabel(label, root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	if label == "" {
+		return rel
+	}
+	return fmt.Sprintf("%s:%s", label, rel)
+}
+
+func licenseEvidence(evidence string) []string {
+	if evidence == "" {
+		return nil
+	}
+	return []string{evidence}
+}
+
+// licenseCacheRoot resolves a package cache directory from its override
+// environment variable, falling back to a path under the user's home.
+func licenseCacheRoot(envName string, homeParts ...string) string {
+	if value := strings.TrimSpace(os.Getenv(envName)); value != "" {
+		return value
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, homeParts...)...)
+}
+
+func licenseDetectionPreviewEnabled(req Request) bool {
+	return req.Features.Enabled(licenseDetectionPreviewFeature)
+}
+
+func licenseExpressionPolicyPreviewEnabled(req Request) bool {
+	return req.Features.Enabled(licenseExpressionPolicyPreviewFeature)
+}
+
+func licenseTemplatesPreviewEnabled(req Request) bool {
+	return req.Features.Enabled(shared.LicenseTemplatesPreviewFeature)
+}
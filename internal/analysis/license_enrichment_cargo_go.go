@@ -0,0 +1,146 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+type cargoPackageLicense struct {
+	Package struct {
+		License     string `toml:"license"`
+		LicenseFile string `toml:"license-file"`
+	} `toml:"package"`
+}
+
+// detectCargoDependencyLicenses resolves Cargo.lock packages to `cargo
+// vendor` output next to the lockfile or to extracted crates in the Cargo
+// registry source cache, reading the `license` field from their Cargo.toml.
+func detectCargoDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	registrySources := ""
+	if cargoHome := licenseCacheRoot("CARGO_HOME", ".cargo"); cargoHome != "" {
+		registrySources = filepath.Join(cargoHome, "registry", "src")
+	}
+	for _, lockPath := range scan.manifests.cargoLockFiles {
+		content, ok := readLicenseMetadata(scan.repoPath, lockPath, scan.warnings)
+		if !ok {
+			continue
+		}
+		var lockfile struct {
+			Package []cargoLockedPackage `toml:"package"`
+		}
+		if err := toml.Unmarshal(content, &lockfile); err != nil {
+			scan.warnings.addFailure("parse", lockPath, identityParseFailed, err)
+			continue
+		}
+		vendorDir := filepath.Join(filepath.Dir(lockPath), "vendor")
+		for _, pkg := range lockfile.Package {
+			key := licenseLookupKey("rust", pkg.Name)
+			if !scan.wants("rust", pkg.Name) || licenses[key] != nil {
+				continue
+			}
+			candidates := []struct{ root, dir, label string }{
+				{scan.repoPath, filepath.Join(vendorDir, pkg.Name+"-"+pkg.Version), ""},
+				{scan.repoPath, filepath.Join(vendorDir, pkg.Name), ""},
+			}
+			if pkg.Source != "" && registrySources != "" {
+				matches, _ := filepath.Glob(filepath.Join(registrySources, "*", pkg.Name+"-"+pkg.Version))
+				sort.Strings(matches)
+				for _, match := range matches {
+					candidates = append(candidates, struct{ root, dir, label string }{registrySources, match, "CARGO_HOME/registry/src"})
+				}
+			}
+			for _, candidate := range candidates {
+				if license := scan.cargoCrateLicense(candidate.root, candidate.dir, candidate.label); license != nil {
+					licenses[key] = license
+					break
+				}
+			}
+		}
+	}
+	return licenses
+}
+
+func (s *licenseScan) cargoCrateLicense(root, dir, label string) *report.DependencyLicense {
+	manifestPath := filepath.Join(dir, cargoManifestFileName)
+	content, err := safeio.ReadFileUnderLimit(root, manifestPath, licenseMetadataReadLimit)
+	if err != nil {
+		return nil
+	}
+	var manifest cargoPackageLicense
+	if toml.Unmarshal(content, &manifest) != nil {
+		return nil
+	}
+	evidence := licenseEvidenceLabel(label, root, manifestPath)
+	if license := declaredDependencyLicense(manifest.Package.License, cargoManifestFileName, evidence); license != nil {
+		return license
+	}
+	if manifest.Package.LicenseFile != "" {
+		if license := s.classifyLicenseFile(root, filepath.Join(dir, filepath.FromSlash(manifest.Package.LicenseFile)), label); license != nil {
+			return license
+		}
+	}
+	if license := s.detectDirectoryLicense(root, dir, label); license != nil {
+		return license
+	}
+	return &report.DependencyLicense{Source: cargoManifestFileName, Confidence: "low", Unknown: true, Evidence: licenseEvidence(evidence)}
+}
+
+// detectGoDependencyLicenses classifies module LICENSE files from each
+// module's vendor directory or the module cache at the go.mod version.
+func detectGoDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	cacheRoot := goModuleCacheRoot()
+	for _, goModPath := range scan.manifests.goModFiles {
+		content, ok := readLicenseMetadata(scan.repoPath, goModPath, scan.warnings)
+		if !ok {
+			continue
+		}
+		file, err := modfile.ParseLax(goModPath, content, nil)
+		if err != nil {
+			scan.warnings.addFailure("parse", goModPath, identityParseFailed, err)
+			continue
+		}
+		vendorDir := filepath.Join(filepath.Dir(goModPath), "vendor")
+		for _, require := range file.Require {
+			path, version := require.Mod.Path, require.Mod.Version
+			key := licenseLookupKey("go", path)
+			if !scan.wants("go", path) || licenses[key] != nil {
+				continue
+			}
+			if license := scan.detectDirectoryLicense(scan.repoPath, filepath.Join(vendorDir, filepath.FromSlash(path)), ""); license != nil {
+				licenses[key] = license
+				continue
+			}
+			if cacheRoot == "" {
+				continue
+			}
+			escapedPath, pathErr := module.EscapePath(path)
+			escapedVersion, versionErr := module.EscapeVersion(version)
+			if pathErr != nil || versionErr != nil {
+				continue
+			}
+			moduleDir := filepath.Join(cacheRoot, filepath.FromSlash(escapedPath)+"@"+escapedVersion)
+			if license := scan.detectDirectoryLicense(cacheRoot, moduleDir, "GOMODCACHE"); license != nil {
+				licenses[key] = license
+			}
+		}
+	}
+	return licenses
+}
+
+func goModuleCacheRoot() string {
+	if gopath := strings.TrimSpace(os.Getenv("GOPATH")); gopath != "" && strings.TrimSpace(os.Getenv("GOMODCACHE")) == "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	return licenseCacheRoot("GOMODCACHE", "go", "pkg", "mod")
+}
@@ -0,0 +1,220 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const pythonDistInfoSource = "dist-info"
+
+var pythonLicenseClassifiers = map[string]string{
+	"mit license":                                             "MIT",
+	"mit no attribution license (mit-0)":                      "MIT-0",
+	"apache software license":                                 "Apache-2.0",
+	"bsd license":                                             "BSD-3-Clause",
+	"isc license (iscl)":                                      "ISC",
+	"mozilla public license 2.0 (mpl 2.0)":                    "MPL-2.0",
+	"python software foundation license":                      "PSF-2.0",
+	"the unlicense (unlicense)":                               "Unlicense",
+	"zope public license":                                     "ZPL-2.1",
+	"eclipse public license 2.0 (epl-2.0)":                    "EPL-2.0",
+	"gnu general public license v2 (gplv2)":                   "GPL-2.0-only",
+	"gnu general public license v2 or later (gplv2+)":         "GPL-2.0-or-later",
+	"gnu general public license v3 (gplv3)":                   "GPL-3.0-only",
+	"gnu general public license v3 or later (gplv3+)":         "GPL-3.0-or-later",
+	"gnu lesser general public license v2 (lgplv2)":           "LGPL-2.0-only",
+	"gnu lesser general public license v2 or later (lgplv2+)": "LGPL-2.0-or-later",
+	"gnu lesser general public license v3 (lgplv3)":           "LGPL-3.0-only",
+	"gnu lesser general public license v3 or later (lgplv3+)": "LGPL-3.0-or-later",
+	"gnu affero general public license v3":                    "AGPL-3.0-only",
+	"gnu affero general public license v3 or later (agplv3+)": "AGPL-3.0-or-later",
+}
+
+var pythonLicenseAliases = map[string]string{
+	"apache 2.0":              "Apache-2.0",
+	"apache 2":                "Apache-2.0",
+	"apache license 2.0":      "Apache-2.0",
+	"apache software license": "Apache-2.0",
+	"bsd":                     "BSD-3-Clause",
+	"new bsd":                 "BSD-3-Clause",
+	"bsd license":             "BSD-3-Clause",
+	"mit license":             "MIT",
+	"psf":                     "PSF-2.0",
+	"gplv2":                   "GPL-2.0-only",
+	"gplv3":                   "GPL-3.0-only",
+	"lgplv3":                  "LGPL-3.0-only",
+	"mpl 2.0":                 "MPL-2.0",
+}
+
+type pythonDistMetadata struct {
+	name        string
+	expression  string
+	license     string
+	classifiers []string
+}
+
+// detectPythonDependencyLicenses reads *.dist-info/METADATA from repository
+// virtual environments and the active VIRTUAL_ENV, preferring PEP 639
+// License-Expression over trove classifiers and the free-form License field.
+func detectPythonDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	envs := append([]string(nil), scan.manifests.pythonEnvs...)
+	if active := strings.TrimSpace(os.Getenv("VIRTUAL_ENV")); active != "" {
+		envs = append(envs, active)
+	}
+	for _, env := range envs {
+		for _, distInfo := range pythonDistInfoDirs(env) {
+			root := scan.repoPath
+			label := ""
+			if !pathWithin(scan.repoPath, distInfo) {
+				root, label = env, "VIRTUAL_ENV"
+			}
+			path := filepath.Join(distInfo, "METADATA")
+			content, err := safeio.ReadFileUnderLimit(root, path, licenseMetadataReadLimit)
+			if err != nil {
+				continue
+			}
+			metadata := parsePythonDistMetadata(content)
+			key := licenseLookupKey("python", metadata.name)
+			if metadata.name == "" || !scan.wants("python", metadata.name) || licenses[key] != nil {
+				continue
+			}
+			evidence := licenseEvidenceLabel(label, root, path)
+			license := pythonDistLicense(metadata, evidence)
+			if license == nil || license.Unknown {
+				if detected := scan.detectDirectoryLicense(root, distInfo, label); detected != nil {
+					license = detected
+				} else if detected := scan.detectDirectoryLicense(root, filepath.Join(distInfo, "licenses"), label); detected != nil {
+					license = detected
+				}
+			}
+			if license != nil {
+				licenses[key] = license
+			}
+		}
+	}
+	return licenses
+}
+
+func pythonDistInfoDirs(env string) []string {
+	patterns := []string{
+		filepath.Join(env, "lib", "python*", "site-packages", "*.dist-info"),
+		filepath.Join(env, "Lib", "site-packages", "*.dist-info"),
+	}
+	dirs := make([]string, 0)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		dirs = append(dirs, matches...)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func isPythonEnvDirName(name string) bool {
+	switch name {
+	case ".venv", "venv", "env":
+		return true
+	default:
+		return false
+	}
+}
+
+func parsePythonDistMetadata(content []byte) pythonDistMetadata {
+	metadata := pythonDistMetadata{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), licenseMetadataReadLimit)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(field) {
+		case "name":
+			metadata.name = value
+		case "license-expression":
+			metadata.expression = value
+		case "license":
+			metadata.license = value
+		case "classifier":
+			if classifier, ok := strings.CutPrefix(value, "License :: OSI Approved :: "); ok {
+				metadata.classifiers = append(metadata.classifiers, classifier)
+			} else if classifier, ok := strings.CutPrefix(value, "License :: "); ok {
+				metadata.classifiers = append(metadata.classifiers, classifier)
+			}
+		}
+	}
+	return metadata
+}
+
+func pythonDistLicense(metadata pythonDistMetadata, evidence string) *report.DependencyLicense {
+	if metadata.expression != "" {
+		return declaredDependencyLicense(metadata.expression, pythonDistInfoSource, evidence)
+	}
+	if expression := pythonClassifierExpression(metadata.classifiers); expression != "" {
+		license := declaredDependencyLicense(expression, pythonDistInfoSource, evidence)
+		license.Raw = strings.Join(metadata.classifiers, "; ")
+		license.Confidence = "medium"
+		return license
+	}
+	return pythonLicenseField(metadata.license, evidence)
+}
+
+// pythonClassifierExpression treats several license classifiers as a choice,
+// which is how dual-licensed distributions declare them.
+func pythonClassifierExpression(classifiers []string) string {
+	ids := make([]string, 0, len(classifiers))
+	for _, classifier := range classifiers {
+		if id, ok := pythonLicenseClassifiers[strings.ToLower(strings.TrimSpace(classifier))]; ok {
+			ids = append(ids, id)
+		}
+	}
+	ids = sortedUnique(ids)
+	if len(ids) > 1 {
+		return "(" + strings.Join(ids, " OR ") + ")"
+	}
+	return strings.Join(ids, "")
+}
+
+func pythonLicenseField(value, evidence string) *report.DependencyLicense {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "UNKNOWN") {
+		return nil
+	}
+	if id, ok := pythonLicenseAliases[strings.ToLower(value)]; ok {
+		license := declaredDependencyLicense(id, pythonDistInfoSource, evidence)
+		license.Raw = value
+		license.Confidence = "medium"
+		return license
+	}
+	if spdx, confidence := shared.DetectSPDXFromLicenseText(value); spdx != "" {
+		return &report.DependencyLicense{SPDX: spdx, Raw: firstLine(value), Source: pythonDistInfoSource, Confidence: confidence, Evidence: licenseEvidence(evidence)}
+	}
+	if len(strings.Fields(value)) == 1 {
+		return declaredDependencyLicense(value, pythonDistInfoSource, evidence)
+	}
+	return &report.DependencyLicense{Raw: firstLine(value), Source: pythonDistInfoSource, Confidence: "low", Unknown: true, Evidence: licenseEvidence(evidence)}
+}
+
+func firstLine(value string) string {
+	line, _, _ := strings.Cut(value, "\n")
+	return strings.TrimSpace(line)
+}
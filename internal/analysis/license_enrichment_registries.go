@@ -0,0 +1,328 @@
+package analysis
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	gemspecLicenseSource = "gemspec"
+	nuspecLicenseSource  = "nuspec"
+	nugetLicenseURLHost  = "licenses.nuget.org/"
+)
+
+var (
+	gemspecNamePattern      = regexp.MustCompile(`\.name\s*=\s*["']([^"']+)["']`)
+	gemspecVersionPattern   = regexp.MustCompile(`\.version\s*=\s*["']([^"']+)["']`)
+	gemspecLicensesPattern  = regexp.MustCompile(`\.licenses?\s*=\s*(\[[^\]]*\]|["'][^"']*["'])`)
+	gemspecQuotedPattern    = regexp.MustCompile(`["']([^"']+)["']`)
+	gemfileLockSpecPattern  = regexp.MustCompile(`^    ([^ (]+) \(([^)]+)\)$`)
+	dotnetPackageTagPattern = regexp.MustCompile(`<Package(?:Reference|Version)\b[^>]*>`)
+	dotnetIncludePattern    = regexp.MustCompile(`\b(?:Include|Update)\s*=\s*"([^"]+)"`)
+	dotnetVersionPattern    = regexp.MustCompile(`\bVersion\s*=\s*"([^"]+)"`)
+)
+
+// detectComposerDependencyLicenses reads the `license` arrays composer.lock
+// records for every installed package. Composer treats several entries as a
+// choice between licenses.
+func detectComposerDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	type composerLockPackage struct {
+		Name    string   `json:"name"`
+		License []string `json:"license"`
+	}
+	licenses := map[string]*report.DependencyLicense{}
+	for _, lockPath := range scan.manifests.composerLocks {
+		content, ok := readLicenseMetadata(scan.repoPath, lockPath, scan.warnings)
+		if !ok {
+			continue
+		}
+		var lockfile struct {
+			Packages    []composerLockPackage `json:"packages"`
+			PackagesDev []composerLockPackage `json:"packages-dev"`
+		}
+		if err := json.Unmarshal(content, &lockfile); err != nil {
+			scan.warnings.addFailure("parse", lockPath, identityParseFailed, err)
+			continue
+		}
+		evidence := relativeIdentitySource(scan.repoPath, lockPath)
+		for _, pkg := range append(lockfile.Packages, lockfile.PackagesDev...) {
+			key := licenseLookupKey("php", pkg.Name)
+			if !scan.wants("php", pkg.Name) || licenses[key] != nil {
+				continue
+			}
+			if license := declaredDependencyLicense(disjunctiveLicenseExpression(pkg.License), composerIdentityLockName, evidence); license != nil {
+				licenses[key] = license
+			}
+		}
+	}
+	return licenses
+}
+
+func disjunctiveLicenseExpression(values []string) string {
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			ids = append(ids, value)
+		}
+	}
+	if len(ids) > 1 {
+		return "(" + strings.Join(ids, " OR ") + ")"
+	}
+	return strings.Join(ids, "")
+}
+
+// detectGemDependencyLicenses reads installed gemspecs from Bundler's
+// vendor/bundle path next to each Gemfile.lock and from GEM_HOME, preferring
+// the gemspec whose version matches the lockfile.
+func detectGemDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	for _, lockPath := range scan.manifests.gemfileLocks {
+		content, ok := readLicenseMetadata(scan.repoPath, lockPath, scan.warnings)
+		if !ok {
+			continue
+		}
+		locked := parseGemfileLockVersions(content)
+		specs, _ := filepath.Glob(filepath.Join(filepath.Dir(lockPath), "vendor", "bundle", "ruby", "*", "specifications", "*.gemspec"))
+		collectGemspecLicenses(scan, scan.repoPath, "", specs, locked, licenses)
+	}
+	if gemHome := strings.TrimSpace(os.Getenv("GEM_HOME")); gemHome != "" {
+		specs, _ := filepath.Glob(filepath.Join(gemHome, "specifications", "*.gemspec"))
+		collectGemspecLicenses(scan, gemHome, "GEM_HOME", specs, nil, licenses)
+	}
+	return licenses
+}
+
+func parseGemfileLockVersions(content []byte) map[string]string {
+	versions := map[string]string{}
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if match := gemfileLockSpecPattern.FindStringSubmatch(line); match != nil {
+			version, _, _ := strings.Cut(match[2], "-")
+			versions[licenseLookupKey("ruby", match[1])] = version
+		}
+	}
+	return versions
+}
+
+func collectGemspecLicenses(scan *licenseScan, root, label string, specs []string, locked map[string]string, licenses map[string]*report.DependencyLicense) {
+	sort.Strings(specs)
+	for _, specPath := range specs {
+		content, err := safeio.ReadFileUnderLimit(root, specPath, licenseMetadataReadLimit)
+		if err != nil {
+			continue
+		}
+		name, version, expression := parseGemspecLicense(string(content))
+		key := licenseLookupKey("ruby", name)
+		if name == "" || !scan.wants("ruby", name) || licenses[key] != nil {
+			continue
+		}
+		if want, ok := locked[key]; ok && want != version {
+			continue
+		}
+		if license := declaredDependencyLicense(expression, gemspecLicenseSource, licenseEvidenceLabel(label, root, specPath)); license != nil {
+			licenses[key] = license
+		}
+	}
+}
+
+func parseGemspecLicense(content string) (string, string, string) {
+	name, version := "", ""
+	if match := gemspecNamePattern.FindStringSubmatch(content); match != nil {
+		name = match[1]
+	}
+	if match := gemspecVersionPattern.FindStringSubmatch(content); match != nil {
+		version = match[1]
+	}
+	match := gemspecLicensesPattern.FindStringSubmatch(content)
+	if match == nil {
+		return name, version, ""
+	}
+	values := make([]string, 0, 1)
+	for _, quoted := range gemspecQuotedPattern.FindAllStringSubmatch(match[1], -1) {
+		values = append(values, quoted[1])
+	}
+	return name, version, disjunctiveLicenseExpression(values)
+}
+
+type nuspecDocument struct {
+	Metadata struct {
+		ID      string `xml:"id"`
+		License struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"license"`
+		LicenseURL string `xml:"licenseUrl"`
+	} `xml:"metadata"`
+}
+
+// detectNuGetDependencyLicenses reads .nuspec metadata from the NuGet global
+// packages folder at the versions declared in project files, central package
+// management, or packages.lock.json.
+func detectNuGetDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	root := licenseCacheRoot("NUGET_PACKAGES", ".nuget", "packages")
+	if root == "" {
+		return licenses
+	}
+	versions := collectNuGetDeclaredVersions(scan)
+	for key := range scan.wanted["dotnet"] {
+		candidates := versions[key]
+		if len(candidates) == 0 {
+			installed, err := os.ReadDir(filepath.Join(root, key))
+			if err != nil || len(installed) != 1 {
+				continue
+			}
+			candidates = []string{installed[0].Name()}
+		}
+		for _, version := range candidates {
+			dir := filepath.Join(root, key, strings.ToLower(version))
+			if license := scan.nuspecLicense(root, dir, key); license != nil {
+				licenses[key] = license
+				break
+			}
+		}
+	}
+	return licenses
+}
+
+func collectNuGetDeclaredVersions(scan *licenseScan) map[string][]string {
+	versions := map[string][]string{}
+	add := func(id, version string) {
+		version = strings.TrimSpace(version)
+		if id == "" || version == "" || strings.ContainsAny(version, "[]()*$") {
+			return
+		}
+		key := licenseLookupKey("dotnet", id)
+		versions[key] = sortedUnique(append(versions[key], version))
+	}
+	for _, path := range scan.manifests.dotnetManifests {
+		content, ok := readLicenseMetadata(scan.repoPath, path, scan.warnings)
+		if !ok {
+			continue
+		}
+		if filepath.Base(path) == dotnetLockFileName {
+			var lockfile struct {
+				Dependencies map[string]map[string]struct {
+					Resolved string `json:"resolved"`
+				} `json:"dependencies"`
+			}
+			if err := json.Unmarshal(content, &lockfile); err != nil {
+				scan.warnings.addFailure("parse", path, identityParseFailed, err)
+				continue
+			}
+			for _, packages := range lockfile.Dependencies {
+				for id, entry := range packages {
+					add(id, entry.Resolved)
+				}
+			}
+			continue
+		}
+		for _, tag := range dotnetPackageTagPattern.FindAllString(string(content), -1) {
+			include := dotnetIncludePattern.FindStringSubmatch(tag)
+			version := dotnetVersionPattern.FindStringSubmatch(tag)
+			if include != nil && version != nil {
+				add(include[1], version[1])
+			}
+		}
+	}
+	return versions
+}
+
+func (s *licenseScan) nuspecLicense(root, dir, key string) *report.DependencyLicense {
+	nuspecPath := filepath.Join(dir, key+".nuspec")
+	content, err := safeio.ReadFileUnderLimit(root, nuspecPath, licenseMetadataReadLimit)
+	if err != nil {
+		return nil
+	}
+	var document nuspecDocument
+	if xml.Unmarshal(content, &document) != nil {
+		return nil
+	}
+	evidence := licenseEvidenceLabel("NUGET_PACKAGES", root, nuspecPath)
+	license := document.Metadata.License
+	switch strings.ToLower(strings.TrimSpace(license.Type)) {
+	case "expression":
+		return declaredDependencyLicense(license.Value, nuspecLicenseSource, evidence)
+	case "file":
+		if detected := s.classifyLicenseFile(root, filepath.Join(dir, filepath.FromSlash(strings.TrimSpace(license.Value))), "NUGET_PACKAGES"); detected != nil {
+			return detected
+		}
+	}
+	licenseURL := strings.TrimSpace(document.Metadata.LicenseURL)
+	if _, expression, ok := strings.Cut(licenseURL, nugetLicenseURLHost); ok {
+		return declaredDependencyLicense(strings.TrimSuffix(expression, "/"), nuspecLicenseSource, evidence)
+	}
+	if licenseURL == "" {
+		return nil
+	}
+	return &report.DependencyLicense{Raw: licenseURL, Source: nuspecLicenseSource, Confidence: "low", Unknown: true, Evidence: licenseEvidence(evidence)}
+}
+
+// detectPubDependencyLicenses classifies LICENSE files of hosted packages in
+// the pub cache and of path dependencies, at the versions in pubspec.lock.
+func detectPubDependencyLicenses(scan *licenseScan) map[string]*report.DependencyLicense {
+	licenses := map[string]*report.DependencyLicense{}
+	pubCache := licenseCacheRoot("PUB_CACHE", ".pub-cache")
+	for _, lockPath := range scan.manifests.pubLocks {
+		content, ok := readLicenseMetadata(scan.repoPath, lockPath, scan.warnings)
+		if !ok {
+			continue
+		}
+		var lockfile struct {
+			Packages map[string]struct {
+				Source      string `yaml:"source"`
+				Version     string `yaml:"version"`
+				Description any    `yaml:"description"`
+			} `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(content, &lockfile); err != nil {
+			scan.warnings.addFailure("parse", lockPath, identityParseFailed, err)
+			continue
+		}
+		names := make([]string, 0, len(lockfile.Packages))
+		for name := range lockfile.Packages {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pkg := lockfile.Packages[name]
+			key := licenseLookupKey("dart", name)
+			if !scan.wants("dart", name) || licenses[key] != nil {
+				continue
+			}
+			var license *report.DependencyLicense
+			switch pkg.Source {
+			case "hosted":
+				if pubCache == "" {
+					continue
+				}
+				matches, _ := filepath.Glob(filepath.Join(pubCache, "hosted", "*", name+"-"+pkg.Version))
+				sort.Strings(matches)
+				for _, match := range matches {
+					if license = scan.detectDirectoryLicense(pubCache, match, "PUB_CACHE"); license != nil {
+						break
+					}
+				}
+			case "path":
+				description, _ := pkg.Description.(map[string]any)
+				relPath, _ := description["path"].(string)
+				if relPath != "" && !filepath.IsAbs(relPath) {
+					license = scan.detectDirectoryLicense(scan.repoPath, filepath.Join(filepath.Dir(lockPath), filepath.FromSlash(relPath)), "")
+				}
+			}
+			if license != nil {
+				licenses[key] = license
+			}
+		}
+	}
+	return licenses
+}
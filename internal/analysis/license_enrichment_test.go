@@ -0,0 +1,257 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+const mitLicenseText = "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\n"
+
+func isolateLicenseCaches(t *testing.T) string {
+	t.Helper()
+	caches := t.TempDir()
+	t.Setenv("GOPATH", "")
+	t.Setenv("GOMODCACHE", filepath.Join(caches, "gomod"))
+	t.Setenv("CARGO_HOME", filepath.Join(caches, "cargo"))
+	t.Setenv("NUGET_PACKAGES", filepath.Join(caches, "nuget"))
+	t.Setenv("PUB_CACHE", filepath.Join(caches, "pub"))
+	t.Setenv("GEM_HOME", "")
+	t.Setenv("VIRTUAL_ENV", "")
+	return caches
+}
+
+func licenseReport(languageID string, names ...string) report.Report {
+	deps := make([]report.DependencyReport, 0, len(names))
+	for _, name := range names {
+		deps = append(deps, report.DependencyReport{Name: name, Language: languageID})
+	}
+	return report.Report{Dependencies: deps}
+}
+
+func requireDependencyLicense(t *testing.T, reportData report.Report, name, spdx, source, evidence string) {
+	t.Helper()
+	for _, dep := range reportData.Dependencies {
+		if dep.Name != name {
+			continue
+		}
+		if dep.License == nil {
+			t.Fatalf("expected license for %s, got none (warnings %#v)", name, reportData.Warnings)
+		}
+		if dep.License.SPDX != spdx || dep.License.Source != source || strings.Join(dep.License.Evidence, ",") != evidence {
+			t.Fatalf("unexpected license for %s: %#v", name, dep.License)
+		}
+		return
+	}
+	t.Fatalf("dependency %s not found", name)
+}
+
+func TestAnnotateDependencyLicensesPythonDistInfo(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	sitePackages := filepath.Join(repo, ".venv", "lib", "python3.12", "site-packages")
+	writeFile(t, filepath.Join(sitePackages, "requests-2.32.3.dist-info", "METADATA"), "Metadata-Version: 2.1\nName: requests\nVersion: 2.32.3\nLicense: Apache 2.0\nClassifier: License :: OSI Approved :: Apache Software License\n\nlong description\nLicense-Expression: GPL-3.0-only\n")
+	writeFile(t, filepath.Join(sitePackages, "attrs-24.1.0.dist-info", "METADATA"), "Metadata-Version: 2.4\nName: attrs\nLicense-Expression: MIT\n")
+	writeFile(t, filepath.Join(sitePackages, "typing_extensions-4.12.dist-info", "METADATA"), "Metadata-Version: 2.1\nName: typing_extensions\nLicense: UNKNOWN\n")
+	writeFile(t, filepath.Join(sitePackages, "typing_extensions-4.12.dist-info", "LICENSE"), mitLicenseText)
+	writeFile(t, filepath.Join(sitePackages, "six-1.16.dist-info", "METADATA"), "Metadata-Version: 2.1\nName: six\nClassifier: License :: OSI Approved :: MIT License\nClassifier: License :: OSI Approved :: Apache Software License\n")
+
+	reportData := licenseReport("python", "requests", "attrs", "typing-extensions", "six", "missing")
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	prefix := ".venv/lib/python3.12/site-packages/"
+	requireDependencyLicense(t, reportData, "requests", "APACHE-2.0", "dist-info", prefix+"requests-2.32.3.dist-info/METADATA")
+	requireDependencyLicense(t, reportData, "attrs", "MIT", "dist-info", prefix+"attrs-24.1.0.dist-info/METADATA")
+	requireDependencyLicense(t, reportData, "typing-extensions", "MIT", "license-file", prefix+"typing_extensions-4.12.dist-info/LICENSE")
+	requireDependencyLicense(t, reportData, "six", "( APACHE-2.0 OR MIT )", "dist-info", prefix+"six-1.16.dist-info/METADATA")
+	if reportData.Dependencies[0].License.Confidence != "medium" || reportData.Dependencies[1].License.Confidence != "high" {
+		t.Fatalf("expected classifier/alias licenses to be medium confidence and expressions high, got %#v", reportData.Dependencies)
+	}
+	if reportData.Dependencies[4].License != nil {
+		t.Fatalf("expected undetected dependency to stay unset, got %#v", reportData.Dependencies[4].License)
+	}
+}
+
+func TestAnnotateDependencyLicensesCargoAndGo(t *testing.T) {
+	caches := isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "Cargo.lock"), "[[package]]\nname = \"serde\"\nversion = \"1.0.203\"\nsource = \"registry+https://github.com/rust-lang/crates.io-index\"\n\n[[package]]\nname = \"local_crate\"\nversion = \"0.1.0\"\n\n[[package]]\nname = \"custom\"\nversion = \"2.0.0\"\nsource = \"registry+https://github.com/rust-lang/crates.io-index\"\n")
+	writeFile(t, filepath.Join(caches, "cargo", "registry", "src", "index.crates.io-6f17d22bba15001f", "serde-1.0.203", "Cargo.toml"), "[package]\nname = \"serde\"\nlicense = \"MIT OR Apache-2.0\"\n")
+	writeFile(t, filepath.Join(repo, "vendor", "local_crate", "Cargo.toml"), "[package]\nname = \"local_crate\"\nlicense = \"MPL-2.0\"\n")
+	writeFile(t, filepath.Join(caches, "cargo", "registry", "src", "index.crates.io-6f17d22bba15001f", "custom-2.0.0", "Cargo.toml"), "[package]\nname = \"custom\"\nlicense-file = \"COPYRIGHT.txt\"\n")
+	writeFile(t, filepath.Join(caches, "cargo", "registry", "src", "index.crates.io-6f17d22bba15001f", "custom-2.0.0", "COPYRIGHT.txt"), mitLicenseText)
+
+	writeFile(t, filepath.Join(repo, "go.mod"), "module example.com/demo\n\ngo 1.22\n\nrequire (\n\tgithub.com/BurntSushi/toml v1.4.0\n\tgolang.org/x/sync v0.7.0\n)\n")
+	writeFile(t, filepath.Join(caches, "gomod", "github.com", "!burnt!sushi", "toml@v1.4.0", "COPYING"), mitLicenseText)
+	writeFile(t, filepath.Join(repo, "vendor", "golang.org", "x", "sync", "LICENSE"), "Redistribution and use in source and binary forms, with or without modification")
+
+	reportData := licenseReport("rust", "serde", "local-crate", "custom")
+	reportData.Dependencies = append(reportData.Dependencies, licenseReport("go", "github.com/burntsushi/toml", "golang.org/x/sync").Dependencies...)
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	requireDependencyLicense(t, reportData, "serde", "MIT OR APACHE-2.0", "Cargo.toml", "CARGO_HOME/registry/src:index.crates.io-6f17d22bba15001f/serde-1.0.203/Cargo.toml")
+	requireDependencyLicense(t, reportData, "local-crate", "MPL-2.0", "Cargo.toml", "vendor/local_crate/Cargo.toml")
+	requireDependencyLicense(t, reportData, "custom", "MIT", "license-file", "CARGO_HOME/registry/src:index.crates.io-6f17d22bba15001f/custom-2.0.0/COPYRIGHT.txt")
+	requireDependencyLicense(t, reportData, "github.com/burntsushi/toml", "MIT", "license-file", "GOMODCACHE:github.com/!burnt!sushi/toml@v1.4.0/COPYING")
+	requireDependencyLicense(t, reportData, "golang.org/x/sync", "BSD-3-CLAUSE", "license-file", "vendor/golang.org/x/sync/LICENSE")
+}
+
+func TestAnnotateDependencyLicensesClassifiesFullLicenseText(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "go.mod"), "module example.com/demo\n\ngo 1.22\n\nrequire github.com/madler/zlib v1.3.1\n")
+	writeFile(t, filepath.Join(repo, "vendor", "github.com", "madler", "zlib", "LICENSE"), `Copyright (C) 1995-2024 Jean-loup Gailly and Mark Adler
+
+This software is provided 'as-is', without any express or implied
+warranty.  In no event will the authors be held liable for any damages
+arising from the use of this software.
+
+Permission is granted to anyone to use this software for any purpose,
+including commercial applications, and to alter it and redistribute it
+freely, subject to the following restrictions:
+
+1. The origin of this software must not be misrepresented; you must not
+   claim that you wrote the original software. If you use this software
+   in a product, an acknowledgment in the product documentation would be
+   appreciated but is not required.
+2. Altered source versions must be plainly marked as such, and must not be
+   misrepresented as being the original software.
+3. This notice may not be removed or altered from any source distribution.
+`)
+
+	reportData := licenseReport("go", "github.com/madler/zlib")
+	annotateDependencyLicenses(repo, &reportData, true)
+
+	requireDependencyLicense(t, reportData, "github.com/madler/zlib", "ZLIB", "license-file", "vendor/github.com/madler/zlib/LICENSE,template:ZLIB similarity=1.00")
+	if reportData.Dependencies[0].License.Confidence != "high" {
+		t.Fatalf("expected high confidence template match, got %#v", reportData.Dependencies[0].License)
+	}
+
+	baseline := licenseReport("go", "github.com/madler/zlib")
+	annotateDependencyLicenses(repo, &baseline, false)
+	if baseline.Dependencies[0].License != nil {
+		t.Fatalf("expected no template match without the preview flag, got %#v", baseline.Dependencies[0].License)
+	}
+}
+
+func TestAnnotateDependencyLicensesComposerAndGems(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "composer.lock"), `{"packages": [{"name": "monolog/monolog", "license": ["MIT"]}, {"name": "symfony/polyfill_mbstring", "license": ["MIT", "GPL-2.0-or-later"]}], "packages-dev": [{"name": "phpunit/phpunit", "license": ["BSD-3-Clause"]}]}`)
+	writeFile(t, filepath.Join(repo, "Gemfile.lock"), "GEM\n  specs:\n    rails (7.1.3)\n    nokogiri (1.16.0-x86_64-linux)\n")
+	specs := filepath.Join(repo, "vendor", "bundle", "ruby", "3.3.0", "specifications")
+	writeFile(t, filepath.Join(specs, "rails-7.0.0.gemspec"), "Gem::Specification.new do |s|\n  s.name = \"rails\".freeze\n  s.version = \"7.0.0\"\n  s.licenses = [\"GPL-3.0-only\".freeze]\nend\n")
+	writeFile(t, filepath.Join(specs, "rails-7.1.3.gemspec"), "Gem::Specification.new do |s|\n  s.name = \"rails\".freeze\n  s.version = \"7.1.3\"\n  s.required_rubygems_version = Gem::Requirement.new(\">= 0\".freeze)\n  s.licenses = [\"MIT\".freeze]\nend\n")
+	writeFile(t, filepath.Join(specs, "nokogiri-1.16.0-x86_64-linux.gemspec"), "Gem::Specification.new do |s|\n  s.name = \"nokogiri\".freeze\n  s.version = \"1.16.0\"\n  s.license = \"MIT\"\nend\n")
+
+	reportData := licenseReport("php", "monolog/monolog", "symfony/polyfill-mbstring", "phpunit/phpunit")
+	reportData.Dependencies = append(reportData.Dependencies, licenseReport("ruby", "rails", "nokogiri").Dependencies...)
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	requireDependencyLicense(t, reportData, "monolog/monolog", "MIT", "composer.lock", "composer.lock")
+	requireDependencyLicense(t, reportData, "symfony/polyfill-mbstring", "( MIT OR GPL-2.0-OR-LATER )", "composer.lock", "composer.lock")
+	requireDependencyLicense(t, reportData, "phpunit/phpunit", "BSD-3-CLAUSE", "composer.lock", "composer.lock")
+	requireDependencyLicense(t, reportData, "rails", "MIT", "gemspec", "vendor/bundle/ruby/3.3.0/specifications/rails-7.1.3.gemspec")
+	requireDependencyLicense(t, reportData, "nokogiri", "MIT", "gemspec", "vendor/bundle/ruby/3.3.0/specifications/nokogiri-1.16.0-x86_64-linux.gemspec")
+}
+
+func TestAnnotateDependencyLicensesKeepsGemsThatDifferByUnderscore(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "Gemfile.lock"), "GEM\n  specs:\n    net_http (1.0.0)\n    net-http (2.0.0)\n")
+	specs := filepath.Join(repo, "vendor", "bundle", "ruby", "3.3.0", "specifications")
+	writeFile(t, filepath.Join(specs, "net-http-2.0.0.gemspec"), "Gem::Specification.new do |s|\n  s.name = \"net-http\".freeze\n  s.version = \"2.0.0\"\n  s.license = \"MIT\"\nend\n")
+	writeFile(t, filepath.Join(specs, "net_http-1.0.0.gemspec"), "Gem::Specification.new do |s|\n  s.name = \"net_http\".freeze\n  s.version = \"1.0.0\"\n  s.license = \"GPL-3.0-only\"\nend\n")
+
+	reportData := licenseReport("ruby", "net_http", "net-http")
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	requireDependencyLicense(t, reportData, "net_http", "GPL-3.0-ONLY", "gemspec", "vendor/bundle/ruby/3.3.0/specifications/net_http-1.0.0.gemspec")
+	requireDependencyLicense(t, reportData, "net-http", "MIT", "gemspec", "vendor/bundle/ruby/3.3.0/specifications/net-http-2.0.0.gemspec")
+}
+
+func TestAnnotateDependencyLicensesNuGetAndPub(t *testing.T) {
+	caches := isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "src", "App", "App.csproj"), `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><PackageReference Include="Newtonsoft.Json" Version="13.0.3" /><PackageReference Version="[1.0,2.0)" Include="Ranged.Package" /></ItemGroup></Project>`)
+	writeFile(t, filepath.Join(caches, "nuget", "newtonsoft.json", "13.0.1", "newtonsoft.json.nuspec"), `<package><metadata><id>Newtonsoft.Json</id><license type="expression">GPL-3.0-only</license></metadata></package>`)
+	writeFile(t, filepath.Join(caches, "nuget", "newtonsoft.json", "13.0.3", "newtonsoft.json.nuspec"), `<?xml version="1.0"?><package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd"><metadata><id>Newtonsoft.Json</id><license type="expression">MIT</license><licenseUrl>https://licenses.nuget.org/MIT</licenseUrl></metadata></package>`)
+	writeFile(t, filepath.Join(caches, "nuget", "ranged.package", "1.5.0", "ranged.package.nuspec"), `<package><metadata><id>Ranged.Package</id><licenseUrl>https://licenses.nuget.org/Apache-2.0</licenseUrl></metadata></package>`)
+	writeFile(t, filepath.Join(caches, "nuget", "legacy.package", "2.0.0", "legacy.package.nuspec"), `<package><metadata><id>Legacy.Package</id><licenseUrl>https://example.com/license</licenseUrl></metadata></package>`)
+
+	writeFile(t, filepath.Join(repo, "app", "pubspec.lock"), "packages:\n  http:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"1.2.1\"\n    description:\n      name: http\n      url: \"https://pub.dev\"\n  shared_utils:\n    dependency: \"direct main\"\n    source: path\n    version: \"0.1.0\"\n    description:\n      path: \"../packages/shared_utils\"\n      relative: true\n")
+	writeFile(t, filepath.Join(caches, "pub", "hosted", "pub.dev", "http-1.2.1", "LICENSE"), "Redistribution and use in source and binary forms, with or without")
+	writeFile(t, filepath.Join(repo, "packages", "shared_utils", "LICENSE"), mitLicenseText)
+
+	reportData := licenseReport("dotnet", "newtonsoft.json", "ranged.package", "legacy.package")
+	reportData.Dependencies = append(reportData.Dependencies, licenseReport("dart", "http", "shared_utils").Dependencies...)
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	requireDependencyLicense(t, reportData, "newtonsoft.json", "MIT", "nuspec", "NUGET_PACKAGES:newtonsoft.json/13.0.3/newtonsoft.json.nuspec")
+	requireDependencyLicense(t, reportData, "ranged.package", "APACHE-2.0", "nuspec", "NUGET_PACKAGES:ranged.package/1.5.0/ranged.package.nuspec")
+	requireDependencyLicense(t, reportData, "legacy.package", "", "nuspec", "NUGET_PACKAGES:legacy.package/2.0.0/legacy.package.nuspec")
+	if !reportData.Dependencies[2].License.Unknown || reportData.Dependencies[2].License.Raw != "https://example.com/license" {
+		t.Fatalf("expected non-expression licenseUrl to stay unknown, got %#v", reportData.Dependencies[2].License)
+	}
+	requireDependencyLicense(t, reportData, "http", "BSD-3-CLAUSE", "license-file", "PUB_CACHE:hosted/pub.dev/http-1.2.1/LICENSE")
+	requireDependencyLicense(t, reportData, "shared_utils", "MIT", "license-file", "packages/shared_utils/LICENSE")
+}
+
+func TestAnnotateDependencyLicensesKeepsExistingAndWarnsOnInvalidMetadata(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "composer.lock"), `{"packages": [`)
+	existing := &report.DependencyLicense{SPDX: "ISC", Source: "package.json", Confidence: "high"}
+	reportData := report.Report{Dependencies: []report.DependencyReport{
+		{Name: "left-pad", Language: "js-ts", License: existing},
+		{Name: "monolog/monolog", Language: "php"},
+	}}
+	annotateDependencyLicenses(repo, &reportData, false)
+
+	if reportData.Dependencies[0].License != existing {
+		t.Fatalf("expected existing license to be preserved, got %#v", reportData.Dependencies[0].License)
+	}
+	if reportData.Dependencies[1].License != nil {
+		t.Fatalf("expected unparsable lockfile to leave license unset, got %#v", reportData.Dependencies[1].License)
+	}
+	if len(reportData.Warnings) != 1 || reportData.Warnings[0] != "identity manifest parse failed for composer.lock: invalid JSON" {
+		t.Fatalf("expected composer.lock parse warning, got %#v", reportData.Warnings)
+	}
+}
+
+func TestFinalizeReportAppliesLicensePolicyToDetectedLicenses(t *testing.T) {
+	isolateLicenseCaches(t)
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "composer.lock"), `{"packages": [{"name": "vendor/copyleft", "license": ["GPL-3.0-only"]}]}`)
+	reportData := licenseReport("php", "vendor/copyleft")
+
+	disabled, err := finalizeReport(Request{LicenseDenyList: []string{"GPL-3.0-only"}}, repo, repo, nil, reportData)
+	if err != nil {
+		t.Fatalf("finalize report: %v", err)
+	}
+	if license := disabled.Dependencies[0].License; license == nil || !license.Unknown || license.Denied {
+		t.Fatalf("expected license detection to stay disabled without the preview flag, got %#v", license)
+	}
+
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{licenseDetectionPreviewFeature},
+	})
+	if err != nil {
+		t.Fatalf("resolve license detection feature set: %v", err)
+	}
+	reportData = licenseReport("php", "vendor/copyleft")
+	enabled, err := finalizeReport(Request{LicenseDenyList: []string{"GPL-3.0-only"}, Features: features}, repo, repo, nil, reportData)
+	if err != nil {
+		t.Fatalf("finalize report: %v", err)
+	}
+	if license := enabled.Dependencies[0].License; license == nil || license.SPDX != "GPL-3.0-ONLY" || !license.Denied {
+		t.Fatalf("expected detected license to be denied, got %#v", license)
+	}
+	if enabled.Summary == nil || enabled.Summary.DeniedLicenseCount != 1 {
+		t.Fatalf("expected denied license summary, got %#v", enabled.Summary)
+	}
+}
@@ -64,6 +64,7 @@ var dependencyFamilyMergeOrder = []dependencyFamilyMergeFn{
 	mergeDependencyRuntimeFamily,
 	mergeDependencyMetadataFamily,
 	mergeDependencyUsageCompletenessFamily,
+	mergeDependencyUnusedRootFamily,
 }
 
 func mergeDependencyExportFamily(merged *report.DependencyReport, left, right report.DependencyReport) {
@@ -133,6 +134,14 @@ func mergeDependencyUsageCompletenessFamily(merged *report.DependencyReport, lef
 	merged.RemovalCandidate = nil
 }
 
+func mergeDependencyUnusedRootFamily(merged *report.DependencyReport, left, right report.DependencyReport) {
+	if merged.UsageIncomplete {
+		merged.UnusedRoots = nil
+		return
+	}
+	merged.UnusedRoots = uniqueSorted(append(append([]string(nil), left.UnusedRoots...), right.UnusedRoots...))
+}
+
 func mergeIncompletePathEvidence(left, right report.DependencyReport) []report.ImportUse {
 	hidden := mergeImportUses(left.SuppressedUnusedImports, right.SuppressedUnusedImports)
 	hidden = mergeImportUses(hidden, left.UnusedImports)
@@ -382,3 +382,27 @@ func assertMergedDependencyMetadata(t *testing.T, dependency report.DependencyRe
 		t.Fatalf("expected merged dependency provenance, got %#v", dependency.Provenance)
 	}
 }
+
+func TestUnusedRootsFollowEachRootsOwnFinding(t *testing.T) {
+	unused := []report.Recommendation{{Code: "remove-unused-dependency"}}
+	web := []report.DependencyReport{{Language: "js-ts", Name: "left-pad", Recommendations: unused}, {Language: "js-ts", Name: "react"}}
+	api := []report.DependencyReport{{Language: "js-ts", Name: "left-pad", UsedExportsCount: 1}}
+	docs := []report.DependencyReport{{Language: "js-ts", Name: "left-pad", Recommendations: unused}}
+	recordUnusedRoot("/repo", "/repo/packages/web", web)
+	recordUnusedRoot("/repo", "/repo/packages/api", api)
+	recordUnusedRoot("/repo", "/repo/docs", docs)
+	if !slices.Equal(web[0].UnusedRoots, []string{"packages/web"}) || web[1].UnusedRoots != nil || api[0].UnusedRoots != nil {
+		t.Fatalf("expected only confirmed unused dependencies to record their root, got %#v %#v", web, api)
+	}
+
+	merged := mergeReports("/repo", []report.Report{{Dependencies: web}, {Dependencies: api}, {Dependencies: docs}})
+	if got := merged.Dependencies[0].UnusedRoots; !slices.Equal(got, []string{"docs", "packages/web"}) {
+		t.Fatalf("expected merged unused roots to exclude the root that uses the dependency, got %#v", got)
+	}
+
+	incomplete := docs[0]
+	incomplete.UsageIncomplete = true
+	if got := mergeDependency(web[0], incomplete).UnusedRoots; got != nil {
+		t.Fatalf("expected incomplete usage to drop unused roots, got %#v", got)
+	}
+}
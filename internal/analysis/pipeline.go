@@ -77,7 +77,13 @@ func (p *analysisPipeline) cleanup() {
 }
 
 func (p *analysisPipeline) execute(ctx context.Context) error {
+	if p.request.StreamStart != nil {
+		if err := p.request.StreamStart(p.plannedScope(), p.cacheMetadata()); err != nil {
+			return err
+		}
+	}
 	reports, warnings, analyzedRoots, err := p.service.runCandidates(ctx, p.request, p.analysisRepoPath, p.candidates, p.cache)
+	p.cache.closeFileTier()
 	if err != nil {
 		return err
 	}
@@ -124,6 +130,24 @@ func (p *analysisPipeline) cacheMetadata() *report.CacheMetadata {
 	return p.cache.metadataSnapshot()
 }
 
+// plannedScope is the scope metadata of the roots execute is about to
+// analyse; it matches the finished report's scope.
+func (p *analysisPipeline) plannedScope() *report.ScopeMetadata {
+	roots := make([]string, 0)
+	for _, candidate := range p.candidates {
+		rootSeen := make(map[string]struct{})
+		scopedRoots, _ := scopedCandidateRootsForRequest(p.request, candidate.Detection.Roots, p.analysisRepoPath)
+		for _, root := range scopedRoots {
+			normalizedRoot := normalizeCandidateRoot(p.analysisRepoPath, root)
+			if normalizedRoot == "" || alreadySeenRoot(rootSeen, normalizedRoot) {
+				continue
+			}
+			roots = append(roots, normalizedRoot)
+		}
+	}
+	return scopeMetadata(p.request.ScopeMode, p.repoPath, remapAnalyzedRoots(uniqueSorted(roots), p.analysisRepoPath, p.repoPath))
+}
+
 func (p *analysisPipeline) remappedAnalyzedRoots() []string {
 	return remapAnalyzedRoots(p.analyzedRoots, p.analysisRepoPath, p.repoPath)
 }
@@ -145,8 +169,17 @@ func finalizeReport(req Request, repoPath string, identityRepoPath string, analy
 	report.AnnotateReachabilityConfidence(&reportData)
 	report.AnnotateFindingConfidence(reportData.Dependencies)
 	report.FilterFindingsByConfidence(reportData.Dependencies, lowConfidenceThreshold)
+	if licenseDetectionPreviewEnabled(req) {
+		annotateDependencyLicenses(identityRepoPath, &reportData, licenseTemplatesPreviewEnabled(req))
+	}
 	report.NormalizeDependencyLicenses(reportData.Dependencies)
-	report.ApplyLicensePolicy(reportData.Dependencies, req.LicenseDenyList)
+	licenseDiagnostics := report.ApplyLicensePolicyRules(reportData.Dependencies, report.LicensePolicyRules{
+		Deny:        req.LicenseDenyList,
+		Allow:       req.LicenseAllowList,
+		Unknown:     req.LicenseUnknownPolicy,
+		Expressions: licenseExpressionPolicyPreviewEnabled(req),
+	})
+	reportData.Warnings = append(reportData.Warnings, licenseDiagnostics...)
 	reportData.Scope = scopeMetadata(req.ScopeMode, repoPath, analyzedRoots)
 	report.AnnotateRemovalCandidateScoresWithWeights(reportData.Dependencies, resolveRemovalCandidateWeights(req.RemovalCandidateWeights))
 	reportData.Summary = report.ComputeSummary(reportData.Dependencies)
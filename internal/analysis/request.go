@@ -40,7 +40,17 @@ type Request struct {
 	MinUsagePercentForRecommendations *int
 	RemovalCandidateWeights           *report.RemovalCandidateWeights
 	LicenseDenyList                   []string
+	LicenseAllowList                  []string
+	LicenseUnknownPolicy              string
 	IncludeRegistryProvenance         bool
 	VulnerabilityExceptions           []report.VulnerabilityException
 	Cache                             *CacheOptions
+	// StreamStart, when set, is called once the roots to analyse are known
+	// and before the first of them runs, with the run's scope and cache
+	// settings. An error aborts the analysis.
+	StreamStart func(scope *report.ScopeMetadata, cache *report.CacheMetadata) error
+	// DependencyStream, when set, receives each analysed root's dependencies
+	// as soon as the root completes, before reports are merged and finalized.
+	// root is relative to RepoPath. An error aborts the analysis.
+	DependencyStream func(root string, dependencies []report.DependencyReport) error
 }
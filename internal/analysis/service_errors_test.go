@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -81,6 +82,92 @@ func TestRunCandidateOnRootsSingleLanguageError(t *testing.T) {
 	}
 }
 
+func TestRunCandidateOnRootsStreamsEachRootAsItCompletes(t *testing.T) {
+	repo := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatalf("mkdir root: %v", err)
+		}
+	}
+	analysed := 0
+	adapter := &testServiceAdapter{id: "stream", analyseFn: func(_ context.Context, req language.Request) (report.Report, error) {
+		analysed++
+		return report.Report{Dependencies: []report.DependencyReport{{Name: filepath.Base(req.RepoPath) + "-dep"}}}, nil
+	}}
+	candidate := language.Candidate{Adapter: adapter, Detection: language.Detection{Matched: true, Confidence: 10, Roots: []string{"a", "b"}}}
+	var streamed []string
+	req := Request{RepoPath: repo, Language: "stream", DependencyStream: func(root string, dependencies []report.DependencyReport) error {
+		if analysed != len(streamed)+1 {
+			t.Fatalf("expected %s to stream before the next root is analysed", root)
+		}
+		for _, dependency := range dependencies {
+			streamed = append(streamed, root+":"+dependency.Language+":"+dependency.Name)
+		}
+		return nil
+	}}
+	svc := &Service{}
+	if _, _, _, err := svc.runCandidateOnRoots(context.Background(), req, repo, candidate, nil); err != nil {
+		t.Fatalf("run candidate: %v", err)
+	}
+	if strings.Join(streamed, ",") != "a:stream:a-dep,b:stream:b-dep" {
+		t.Fatalf("unexpected streamed dependencies: %v", streamed)
+	}
+
+	streamErr := errors.New("stream closed")
+	req.DependencyStream = func(string, []report.DependencyReport) error { return streamErr }
+	if _, _, _, err := svc.runCandidateOnRoots(context.Background(), req, repo, candidate, nil); !errors.Is(err, streamErr) {
+		t.Fatalf("expected stream error to abort the run, got %v", err)
+	}
+}
+
+func TestAnalyseStartsStreamWithPlannedScopeBeforeRoots(t *testing.T) {
+	repo := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatalf("mkdir root: %v", err)
+		}
+	}
+	reg := language.NewRegistry()
+	if err := reg.Register(&testServiceAdapter{
+		id:      "stream",
+		detect:  language.Detection{Matched: true, Confidence: 90, Roots: []string{"b", "a", "a"}},
+		analyse: report.Report{Dependencies: []report.DependencyReport{{Name: "dep"}}},
+	}); err != nil {
+		t.Fatalf(registerAdapterFmt, err)
+	}
+	var started *report.ScopeMetadata
+	var events []string
+	req := Request{
+		RepoPath: repo,
+		Language: "stream",
+		StreamStart: func(scope *report.ScopeMetadata, _ *report.CacheMetadata) error {
+			started = scope
+			events = append(events, "start")
+			return nil
+		},
+		DependencyStream: func(root string, _ []report.DependencyReport) error {
+			events = append(events, root)
+			return nil
+		},
+	}
+	rep, err := (&Service{Registry: reg}).Analyse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+	if strings.Join(events, ",") != "start,b,a" {
+		t.Fatalf("expected the stream to start before any root, got %v", events)
+	}
+	if started == nil || !reflect.DeepEqual(started, rep.Scope) {
+		t.Fatalf("expected the planned scope to match the final scope, got %#v want %#v", started, rep.Scope)
+	}
+
+	startErr := errors.New("stream closed")
+	req.StreamStart = func(*report.ScopeMetadata, *report.CacheMetadata) error { return startErr }
+	if _, err := (&Service{Registry: reg}).Analyse(context.Background(), req); !errors.Is(err, startErr) {
+		t.Fatalf("expected stream start error to abort the run, got %v", err)
+	}
+}
+
 func TestAnalyseNoReportsAndRuntimeTraceErrorBranches(t *testing.T) {
 	reg := language.NewRegistry()
 	if err := reg.Register(&testServiceAdapter{
@@ -0,0 +1,214 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultWatchPollInterval = 500 * time.Millisecond
+	defaultWatchDebounce     = 300 * time.Millisecond
+	maxWatchedFiles          = 50000
+)
+
+var errWatchFileLimitExceeded = errors.New("watch file limit exceeded")
+
+// WatchOptions configures a RepoWatcher. The watcher applies the same include
+// and exclude patterns as analysis scope, and the same directory skips and
+// file relevance rules as the analysis cache, so it only wakes for edits that
+// can change a cache key.
+type WatchOptions struct {
+	RepoPath        string
+	IncludePatterns []string
+	ExcludePatterns []string
+	// IgnoredPaths are files or directories the watch loop itself writes to,
+	// such as an explicit cache directory.
+	IgnoredPaths []string
+	PollInterval time.Duration
+	Debounce     time.Duration
+}
+
+// RepoWatcher polls a repository for changes between analysis runs.
+type RepoWatcher struct {
+	repoPath        string
+	includeCompiled []compiledPattern
+	excludeCompiled []compiledPattern
+	ignored         map[string]struct{}
+	pollInterval    time.Duration
+	debounce        time.Duration
+	current         map[string]watchedFileStamp
+}
+
+type watchedFileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// NewRepoWatcher records the current state of the repository; the first call
+// to Next reports changes made after this point.
+func NewRepoWatcher(opts WatchOptions) (*RepoWatcher, error) {
+	includeCompiled, err := compileGlobPatterns(normalizePatterns(opts.IncludePatterns))
+	if err != nil {
+		return nil, err
+	}
+	excludeCompiled, err := compileGlobPatterns(normalizePatterns(opts.ExcludePatterns))
+	if err != nil {
+		return nil, err
+	}
+	repoPath, err := filepath.Abs(opts.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	ignoredPaths := make([]string, 0, len(opts.IgnoredPaths))
+	for _, path := range opts.IgnoredPaths {
+		if absolute, err := filepath.Abs(path); err == nil && strings.TrimSpace(path) != "" {
+			ignoredPaths = append(ignoredPaths, absolute)
+		}
+	}
+	watcher := &RepoWatcher{
+		repoPath:        repoPath,
+		includeCompiled: includeCompiled,
+		excludeCompiled: excludeCompiled,
+		ignored:         scopedCopySkippedDirs(repoPath, ignoredPaths),
+		pollInterval:    opts.PollInterval,
+		debounce:        opts.Debounce,
+	}
+	if watcher.pollInterval <= 0 {
+		watcher.pollInterval = defaultWatchPollInterval
+	}
+	switch {
+	case watcher.debounce == 0:
+		watcher.debounce = defaultWatchDebounce
+	case watcher.debounce < 0:
+		watcher.debounce = 0
+	}
+	if watcher.current, err = watcher.snapshot(); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// Next blocks until watched files change and then stay unchanged for the
+// debounce window, and returns the sorted repo-relative paths that differ
+// from the previous state. Edits that are reverted within the window are not
+// reported.
+func (w *RepoWatcher) Next(ctx context.Context) ([]string, error) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	var pending map[string]watchedFileStamp
+	var quietSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		next, err := w.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		switch {
+		case pending == nil && len(diffWatchSnapshots(w.current, next)) == 0:
+			continue
+		case pending == nil || len(diffWatchSnapshots(pending, next)) > 0:
+			pending, quietSince = next, now
+		}
+		if now.Sub(quietSince) < w.debounce {
+			continue
+		}
+		changed := diffWatchSnapshots(w.current, pending)
+		w.current = pending
+		pending = nil
+		if len(changed) > 0 {
+			return changed, nil
+		}
+	}
+}
+
+func (w *RepoWatcher) snapshot() (map[string]watchedFileStamp, error) {
+	files := make(map[string]watchedFileStamp)
+	err := filepath.WalkDir(w.repoPath, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path != w.repoPath && errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if path == w.repoPath {
+			return nil
+		}
+		if _, ignored := w.ignored[filepath.Clean(path)]; ignored {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if shouldSkipCacheDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !shouldHashFile(path, entry) || !w.inScope(path) {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(files) >= maxWatchedFiles {
+			return errWatchFileLimitExceeded
+		}
+		rel, err := filepath.Rel(w.repoPath, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = watchedFileStamp{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (w *RepoWatcher) inScope(path string) bool {
+	rel, err := filepath.Rel(w.repoPath, path)
+	if err != nil {
+		return false
+	}
+	slashed := filepath.ToSlash(rel)
+	if len(w.includeCompiled) > 0 {
+		if matched, _ := matchFirstCompiledPattern(slashed, w.includeCompiled); !matched {
+			return false
+		}
+	}
+	excluded, _ := matchFirstCompiledPattern(slashed, w.excludeCompiled)
+	return !excluded
+}
+
+func diffWatchSnapshots(previous, next map[string]watchedFileStamp) []string {
+	changed := make([]string, 0)
+	for path, stamp := range next {
+		if before, ok := previous[path]; !ok || before.size != stamp.size || !before.modTime.Equal(stamp.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return slices.Compact(changed)
+}
@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestRepoWatcherReportsDebouncedRelevantChanges(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "index.js"), "import a from \"a\"\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "legacy.js"), "import b from \"b\"\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "README.md"), "# demo\n")
+	cacheDir := filepath.Join(repo, "ci-cache")
+	testutil.MustWriteFile(t, filepath.Join(cacheDir, "objects", "entry.js"), "{}\n")
+
+	watcher, err := NewRepoWatcher(WatchOptions{
+		RepoPath:        repo,
+		ExcludePatterns: []string{"src/legacy.js"},
+		IgnoredPaths:    []string{cacheDir},
+		PollInterval:    5 * time.Millisecond,
+		Debounce:        20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+
+	testutil.MustWriteFile(t, filepath.Join(repo, "README.md"), "# changed\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "legacy.js"), "import c from \"c\"\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "node_modules", "a", "index.js"), "module.exports = {}\n")
+	testutil.MustWriteFile(t, filepath.Join(cacheDir, "objects", "entry.js"), "{\"changed\":true}\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "index.js"), "import a from \"a\"\nimport d from \"d\"\n")
+	if err := os.Remove(filepath.Join(repo, "src", "legacy.js")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "new.ts"), "export {}\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := watcher.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if !slices.Equal(changed, []string{"src/index.js", "src/new.ts"}) {
+		t.Fatalf("unexpected changed paths %#v", changed)
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer shortCancel()
+	if changed, err := watcher.Next(shortCtx); err == nil {
+		t.Fatalf("expected no further changes, got %#v", changed)
+	}
+}
+
+func TestRepoWatcherReportsNonJSAdapterSourcesAndManifests(t *testing.T) {
+	repo := t.TempDir()
+	inputs := []string{"Gemfile", "lib/app.rb", "Package.swift", "Sources/App/main.swift", "pubspec.yaml", "lib/main.dart", "mix.exs", "lib/demo.ex", "src/App/App.csproj"}
+	for _, path := range inputs {
+		testutil.MustWriteFile(t, filepath.Join(repo, filepath.FromSlash(path)), "initial\n")
+	}
+
+	watcher, err := NewRepoWatcher(WatchOptions{RepoPath: repo, PollInterval: 5 * time.Millisecond, Debounce: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	for _, path := range inputs {
+		testutil.MustWriteFile(t, filepath.Join(repo, filepath.FromSlash(path)), "changed\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed, err := watcher.Next(ctx)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	want := slices.Clone(inputs)
+	slices.Sort(want)
+	if !slices.Equal(changed, want) {
+		t.Fatalf("expected every adapter input to be reported, got %#v", changed)
+	}
+}
@@ -13,6 +13,18 @@ func validateAnalyseFeatures(req AnalyseRequest) error {
 	if err := validateAnalyseFormatFeatures(req); err != nil {
 		return err
 	}
+	if err := validateManifestCodemodFeatures(req); err != nil {
+		return err
+	}
+	if err := validateAnalyseWatchFeatures(req); err != nil {
+		return err
+	}
+	if err := validateAnalyseExitFeatures(req); err != nil {
+		return err
+	}
+	if err := validateAnalysePolicyRuleFeatures(req); err != nil {
+		return err
+	}
 	return validateAnalysisPolicyFeatures(req.Features, req.AdvisorySourcePath, req.Thresholds, req.VulnerabilityExceptions)
 }
 
@@ -33,6 +45,26 @@ func validateAnalyseFormatFeatures(req AnalyseRequest) error {
 			return nil
 		}
 		return fmt.Errorf("analyse format %q requires --enable-feature %s", report.FormatVEX, report.VulnerabilityExceptionsVEXPreviewFeature)
+	case report.FormatHTML:
+		if req.Features.Enabled(report.HTMLReportPreviewFeature) {
+			return nil
+		}
+		return fmt.Errorf("analyse format %q requires --enable-feature %s", report.FormatHTML, report.HTMLReportPreviewFeature)
+	case report.FormatNDJSON:
+		if req.Features.Enabled(report.NDJSONReportStreamPreviewFeature) {
+			return nil
+		}
+		return fmt.Errorf("analyse format %q requires --enable-feature %s", report.FormatNDJSON, report.NDJSONReportStreamPreviewFeature)
+	case report.FormatJUnit, report.FormatCodeQuality:
+		if req.Features.Enabled(report.CIReportFormatsPreviewFeature) {
+			return nil
+		}
+		return fmt.Errorf("analyse format %q requires --enable-feature %s", req.Format, report.CIReportFormatsPreviewFeature)
+	case report.FormatTemplate:
+		if req.Features.Enabled(report.CustomOutputTemplatesPreviewFeature) {
+			return nil
+		}
+		return fmt.Errorf("analyse format %q requires --enable-feature %s", report.FormatTemplate, report.CustomOutputTemplatesPreviewFeature)
 	default:
 		return nil
 	}
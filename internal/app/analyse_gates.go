@@ -0,0 +1,98 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func validateAnalyseExitFeatures(req AnalyseRequest) error {
+	if !req.ExplainExit || req.Features.Enabled(report.ExitCodeContractPreviewFeature) {
+		return nil
+	}
+	return fmt.Errorf("analyse --explain-exit requires --enable-feature %s", report.ExitCodeContractPreviewFeature)
+}
+
+// recordGateResults lists every gate in the order analyse checks them:
+// lockfile drift runs before analysis, the report gates after the baseline
+// comparison.
+func recordGateResults(reportData report.Report, req AnalyseRequest) report.Report {
+	if !req.Features.Enabled(report.ExitCodeContractPreviewFeature) {
+		return reportData
+	}
+	gates := []report.GateResult{lockfileDriftGateResult(req.Thresholds.LockfileDriftPolicy, countLockfileDriftWarnings(reportData.Warnings), nil)}
+	reportData.GateResults = append(gates, report.EvaluateGates(reportData)...)
+	return reportData
+}
+
+// preparedAnalyseGateResults covers analyses that stop before a report
+// exists; only a failed lockfile drift gate has anything to show.
+func preparedAnalyseGateResults(req AnalyseRequest, err error) []report.GateResult {
+	if !errors.Is(err, ErrLockfileDrift) {
+		return nil
+	}
+	return []report.GateResult{lockfileDriftGateResult(req.Thresholds.LockfileDriftPolicy, 0, err)}
+}
+
+func lockfileDriftGateResult(policy string, drift int, driftErr error) report.GateResult {
+	result := report.GateResult{
+		Gate:     report.GateLockfileDrift,
+		Status:   report.GateStatusPass,
+		ExitCode: report.ExitCodeLockfileDrift,
+	}
+	policy = strings.TrimSpace(policy)
+	if policy == "off" {
+		result.Status = report.GateStatusSkipped
+		result.Detail = "disabled"
+		return result
+	}
+	result.Threshold = policy
+	if driftErr != nil {
+		result.Status = report.GateStatusFail
+		result.Detail = driftErr.Error()
+		return result
+	}
+	result.Observed = strconv.Itoa(drift)
+	result.Detail = fmt.Sprintf("%d lockfile drift findings", drift)
+	return result
+}
+
+func countLockfileDriftWarnings(warnings []string) int {
+	count := 0
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, lockfileDriftWarningPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// explainAnalyseExit writes the --explain-exit breakdown: the exit code, what
+// decided it, and every gate that was evaluated.
+func (a *App) explainAnalyseExit(req AnalyseRequest, gates []report.GateResult, runErr error) {
+	if !req.ExplainExit {
+		return
+	}
+	out := a.Err
+	if out == nil {
+		out = io.Discard
+	}
+	code := ExitCode(runErr, true)
+	var explanation strings.Builder
+	switch gate := GateForError(runErr); {
+	case runErr == nil:
+		fmt.Fprintf(&explanation, "exit %d: all gates passed\n", code)
+	case gate != "":
+		fmt.Fprintf(&explanation, "exit %d: gate %s failed\n", code, gate)
+	default:
+		fmt.Fprintf(&explanation, "exit %d: %s\n", code, runErr.Error())
+	}
+	for _, result := range gates {
+		explanation.WriteString("- " + report.FormatGateLine(result) + "\n")
+	}
+	_, _ = io.WriteString(out, explanation.String())
+}
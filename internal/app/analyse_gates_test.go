@@ -0,0 +1,159 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func TestExecuteAnalyseRecordsGateResultsAndExplainsExit(t *testing.T) {
+	analyzer := &fakeAnalyzer{
+		report: report.Report{
+			RepoPath:         ".",
+			Dependencies:     []report.DependencyReport{{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 2, UsedPercent: 50}},
+			UsageUncertainty: &report.UsageUncertainty{UncertainImportUses: 3},
+		},
+	}
+	var stderr bytes.Buffer
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter(), Err: &stderr}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = t.TempDir()
+	req.Analyse.TopN = 1
+	req.Analyse.Format = report.FormatJSON
+	req.Analyse.Thresholds.MaxUncertainImportCount = 1
+	req.Analyse.ExplainExit = true
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.ExitCodeContractPreviewFeature)
+
+	output, err := application.Execute(context.Background(), req)
+	if !errors.Is(err, ErrUncertaintyThresholdExceeded) {
+		t.Fatalf("expected uncertainty threshold error, got %v", err)
+	}
+	if got := ExitCode(err, true); got != report.ExitCodeMaxUncertainImports {
+		t.Fatalf("expected contract exit code %d, got %d", report.ExitCodeMaxUncertainImports, got)
+	}
+
+	var decoded report.Report
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	gates := make([]string, 0, len(decoded.GateResults))
+	for _, result := range decoded.GateResults {
+		gates = append(gates, result.Gate+"="+result.Status)
+	}
+	want := "lockfile-drift=pass,fail-on-increase=skipped,max-uncertain-imports=fail,license-fail-on-deny=skipped,reachable-vulnerability-priority=skipped,policy-rules=skipped"
+	if got := strings.Join(gates, ","); got != want {
+		t.Fatalf("unexpected gate results %q", got)
+	}
+
+	explanation := stderr.String()
+	for _, fragment := range []string{
+		"exit 11: gate max-uncertain-imports failed",
+		"- max-uncertain-imports: fail (observed 3, threshold 1) -> exit 11",
+		"- license-fail-on-deny: skipped",
+	} {
+		if !strings.Contains(explanation, fragment) {
+			t.Fatalf("expected explanation to contain %q, got %q", fragment, explanation)
+		}
+	}
+}
+
+func TestExecuteAnalyseOmitsGateResultsWithoutContract(t *testing.T) {
+	analyzer := &fakeAnalyzer{report: report.Report{RepoPath: "."}}
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter()}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = t.TempDir()
+	req.Analyse.TopN = 1
+	req.Analyse.Format = report.FormatJSON
+
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf(executeAnalyseErrFmt, err)
+	}
+	if strings.Contains(output, "gateResults") {
+		t.Fatalf("expected gateResults to stay behind the preview flag, got %q", output)
+	}
+
+	req.Analyse.ExplainExit = true
+	if _, err := application.Execute(context.Background(), req); err == nil || !strings.Contains(err.Error(), report.ExitCodeContractPreviewFeature) {
+		t.Fatalf("expected --explain-exit feature gate error, got %v", err)
+	}
+}
+
+func TestExecuteAnalyseExplainsLockfileDriftFailure(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "package.json"), []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("write package.json: %v", err)
+	}
+	analyzer := &fakeAnalyzer{}
+	var stderr bytes.Buffer
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter(), Err: &stderr}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = repo
+	req.Analyse.TopN = 1
+	req.Analyse.Thresholds.LockfileDriftPolicy = "fail"
+	req.Analyse.ExplainExit = true
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.ExitCodeContractPreviewFeature)
+
+	_, err := application.Execute(context.Background(), req)
+	if !errors.Is(err, ErrLockfileDrift) {
+		t.Fatalf("expected lockfile drift error, got %v", err)
+	}
+	if analyzer.called {
+		t.Fatalf("expected lockfile drift to fail before analysis")
+	}
+	if got := stderr.String(); !strings.Contains(got, "exit 14: gate lockfile-drift failed") || !strings.Contains(got, "- lockfile-drift: fail (threshold fail) -> exit 14") {
+		t.Fatalf("unexpected lockfile drift explanation %q", got)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err      error
+		legacy   int
+		contract int
+	}{
+		{err: nil, legacy: 0, contract: 0},
+		{err: errors.New("boom"), legacy: 1, contract: 1},
+		{err: ErrBaselineRequired, legacy: 1, contract: 1},
+		{err: ErrFailOnIncrease, legacy: 3, contract: report.ExitCodeFailOnIncrease},
+		{err: ErrUncertaintyThresholdExceeded, legacy: 3, contract: report.ExitCodeMaxUncertainImports},
+		{err: ErrDeniedLicenses, legacy: 3, contract: report.ExitCodeLicenseFailOnDeny},
+		{err: ErrReachableVulnerabilities, legacy: 3, contract: report.ExitCodeReachableVulnerabilityPriority},
+		{err: formatLockfileDriftError([]string{lockfileDriftWarningPrefix + "npm in .: drift"}), legacy: 4, contract: report.ExitCodeLockfileDrift},
+		{err: ErrPRReviewRegressions, legacy: 3, contract: report.ExitCodePRReviewRegressions},
+	}
+	for _, tc := range cases {
+		if got := ExitCode(tc.err, false); got != tc.legacy {
+			t.Fatalf("legacy exit code for %v: want %d, got %d", tc.err, tc.legacy, got)
+		}
+		if got := ExitCode(tc.err, true); got != tc.contract {
+			t.Fatalf("contract exit code for %v: want %d, got %d", tc.err, tc.contract, got)
+		}
+	}
+}
+
+func TestLockfileDriftGateResult(t *testing.T) {
+	if got := lockfileDriftGateResult("off", 2, nil); got.Status != report.GateStatusSkipped {
+		t.Fatalf("expected disabled lockfile drift gate to be skipped, got %#v", got)
+	}
+	got := lockfileDriftGateResult("warn", countLockfileDriftWarnings([]string{lockfileDriftWarningPrefix + "a", "other", lockfileDriftWarningPrefix + "b"}), nil)
+	if got.Status != report.GateStatusPass || got.Observed != "2" || got.Threshold != "warn" {
+		t.Fatalf("unexpected warn lockfile drift gate %#v", got)
+	}
+	if gates := preparedAnalyseGateResults(AnalyseRequest{}, errors.New("boom")); gates != nil {
+		t.Fatalf("expected no gate results for non-gate failures, got %#v", gates)
+	}
+}
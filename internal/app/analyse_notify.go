@@ -28,7 +28,7 @@ func buildNotificationOutcome(reportData report.Report, runErr error) notify.Out
 		return outcome
 	}
 
-	if errors.Is(runErr, ErrFailOnIncrease) || errors.Is(runErr, ErrDeniedLicenses) || errors.Is(runErr, ErrUncertaintyThresholdExceeded) {
+	if errors.Is(runErr, ErrFailOnIncrease) || errors.Is(runErr, ErrDeniedLicenses) || errors.Is(runErr, ErrUncertaintyThresholdExceeded) || errors.Is(runErr, ErrPolicyRules) {
 		outcome.Breach = true
 	}
 
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/ben-ranford/lopper/internal/report"
@@ -13,21 +14,70 @@ func (a *App) executeAnalyse(ctx context.Context, req Request) (string, error) {
 	if err := validateAnalyseFeatures(req.Analyse); err != nil {
 		return "", err
 	}
+	if err := validateAnalyseTemplate(req.Analyse); err != nil {
+		return "", err
+	}
+	if req.Analyse.Watch {
+		return a.executeAnalyseWatch(ctx, req)
+	}
 
+	output, gates, err := a.runAnalyse(ctx, req)
+	a.explainAnalyseExit(req.Analyse, gates, err)
+	return output, err
+}
+
+// runAnalyse runs one analysis and also returns the gate results behind its
+// exit code for --explain-exit.
+func (a *App) runAnalyse(ctx context.Context, req Request) (string, []report.GateResult, error) {
 	prepared, err := prepareAnalyseExecution(ctx, req)
 	if err != nil {
-		return "", err
+		return "", preparedAnalyseGateResults(req.Analyse, err), err
+	}
+	if req.Analyse.Format == report.FormatNDJSON {
+		return a.streamAnalyse(ctx, req, prepared)
 	}
 
 	reportData, err := a.invokeAnalyse(ctx, prepared)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	decorateAnalyseReport(&reportData, prepared)
 	reportData, err = a.runAnalysePostStages(ctx, req.RepoPath, req.Analyse, reportData)
 
-	return a.completeAnalyseExecution(ctx, req.RepoPath, req.Analyse, reportData, err)
+	output, err := a.completeAnalyseExecution(ctx, req.RepoPath, req.Analyse, reportData, err)
+	return output, reportData.GateResults, err
+}
+
+// streamAnalyse is runAnalyse for the NDJSON format: the header is written
+// once the analysis service has planned its roots, each root's dependency
+// records as the service completes it, and the trailer with the post-stage
+// results once those have run.
+func (a *App) streamAnalyse(ctx context.Context, req Request, prepared preparedAnalyseExecution) (string, []report.GateResult, error) {
+	var reportData report.Report
+	var runErr error
+	output, err := streamCommandOutput(a.Out, func(w io.Writer) error {
+		stream := report.NewNDJSONWriter(w)
+		prepared.request.StreamStart = func(scope *report.ScopeMetadata, cache *report.CacheMetadata) error {
+			header := report.Report{Scope: scope, Cache: cache}
+			decorateAnalyseReport(&header, prepared)
+			return stream.WriteHeader(header)
+		}
+		prepared.request.DependencyStream = stream.WriteDependencies
+		var err error
+		reportData, err = a.invokeAnalyse(ctx, prepared)
+		if err != nil {
+			return err
+		}
+		decorateAnalyseReport(&reportData, prepared)
+		reportData, runErr = a.runAnalysePostStages(ctx, req.RepoPath, req.Analyse, reportData)
+		a.appendNotificationWarnings(ctx, req.Analyse.Notifications, &reportData, buildNotificationOutcome(reportData, runErr))
+		return stream.WriteTrailer(reportData)
+	}, req.Analyse.OutputPath, "analyse report", req.RepoPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return output, reportData.GateResults, runErr
 }
 
 func (a *App) invokeAnalyse(ctx context.Context, prepared preparedAnalyseExecution) (report.Report, error) {
@@ -44,9 +94,18 @@ func (a *App) runAnalysePostStages(ctx context.Context, repoPath string, req Ana
 		func(_ context.Context, reportData report.Report) (report.Report, error) {
 			return applyVulnerabilityExceptionsIfNeeded(reportData, req, now)
 		},
+		func(_ context.Context, reportData report.Report) (report.Report, error) {
+			return applyLicenseExceptionsToReport(reportData, req.LicenseExceptions, now), nil
+		},
+		func(_ context.Context, reportData report.Report) (report.Report, error) {
+			return applyPolicyRulesIfNeeded(reportData, req)
+		},
 		func(_ context.Context, reportData report.Report) (report.Report, error) {
 			return a.applyBaselineIfNeeded(reportData, repoPath, req)
 		},
+		func(_ context.Context, reportData report.Report) (report.Report, error) {
+			return recordGateResults(reportData, req), nil
+		},
 		analyseValidationStage(func(reportData report.Report) error {
 			return validateFailOnIncrease(reportData, req.Thresholds.FailOnIncreasePercent)
 		}),
@@ -59,9 +118,18 @@ func (a *App) runAnalysePostStages(ctx context.Context, repoPath string, req Ana
 		analyseValidationStage(func(reportData report.Report) error {
 			return validateReachableVulnerabilityThreshold(reportData, req.Thresholds.ReachableVulnerabilityPriority)
 		}),
+		analyseValidationStage(func(reportData report.Report) error {
+			return validatePolicyRules(reportData, req.PolicyRules)
+		}),
+		func(ctx context.Context, reportData report.Report) (report.Report, error) {
+			return applyManifestCodemodIfNeeded(ctx, reportData, repoPath, req)
+		},
 		func(ctx context.Context, reportData report.Report) (report.Report, error) {
 			return applyCodemodIfNeeded(ctx, reportData, repoPath, req, now)
 		},
+		func(_ context.Context, reportData report.Report) (report.Report, error) {
+			return appendManifestLockfileRefreshWarnings(reportData, req), nil
+		},
 		func(_ context.Context, reportData report.Report) (report.Report, error) {
 			return a.saveBaselineIfNeeded(reportData, repoPath, req, now)
 		},
@@ -94,7 +162,7 @@ func (a *App) completeAnalyseExecution(ctx context.Context, repoPath string, req
 		}
 		return "", err
 	}
-	formatted, err := a.Formatter.Format(reportData, req.Format)
+	formatted, err := a.formatAnalyseReport(ctx, reportData, req)
 	if err != nil {
 		if runErr != nil {
 			return "", runErr
@@ -216,6 +216,18 @@ func appendVulnerabilityExceptionWarnings(warnings, diagnostics []string) []stri
 	return warnings
 }
 
+func applyLicenseExceptionsToReport(reportData report.Report, exceptions []report.LicenseException, now time.Time) report.Report {
+	if len(exceptions) == 0 {
+		return reportData
+	}
+	diagnostics := report.ApplyLicenseExceptions(&reportData, exceptions, now)
+	reportData.Summary = report.ComputeSummary(reportData.Dependencies)
+	for _, diagnostic := range diagnostics {
+		reportData.Warnings = append(reportData.Warnings, "license exception: "+diagnostic)
+	}
+	return reportData
+}
+
 func resolveCurrentBaselineKey(repoPath string) string {
 	sha, err := workspace.CurrentCommitSHA(repoPath)
 	if err != nil || strings.TrimSpace(sha) == "" {
@@ -259,13 +271,7 @@ func validateDeniedLicenses(reportData report.Report, failOnDeny bool) error {
 	if !failOnDeny {
 		return nil
 	}
-	if reportData.BaselineComparison != nil {
-		if len(reportData.BaselineComparison.NewDeniedLicenses) > 0 {
-			return ErrDeniedLicenses
-		}
-		return nil
-	}
-	if report.CountDeniedLicenses(reportData.Dependencies) > 0 {
+	if report.HasDeniedLicenseBreach(reportData) {
 		return ErrDeniedLicenses
 	}
 
@@ -276,49 +282,8 @@ func validateReachableVulnerabilityThreshold(reportData report.Report, threshold
 	if !report.ValidVulnerabilityPriorityThreshold(threshold) {
 		return fmt.Errorf("invalid reachable vulnerability priority threshold: %s", threshold)
 	}
-	if !hasReachableVulnerabilityAtOrAbove(reportData, threshold) {
+	if !report.HasReachableVulnerabilityAtOrAbove(reportData, threshold) {
 		return nil
 	}
 	return ErrReachableVulnerabilities
 }
-
-func hasReachableVulnerabilityAtOrAbove(reportData report.Report, threshold string) bool {
-	if strings.TrimSpace(threshold) == "" || report.NormalizeVulnerabilityPriorityThreshold(threshold) == report.VulnerabilityPriorityOff {
-		return false
-	}
-	if reportData.BaselineComparison != nil {
-		return baselineHasReachableVulnerabilityAtOrAbove(reportData.BaselineComparison.NewReachableVulnerabilities, threshold)
-	}
-	return dependencyHasReachableVulnerabilityAtOrAbove(reportData.Dependencies, threshold)
-}
-
-func baselineHasReachableVulnerabilityAtOrAbove(findings []report.VulnerabilityDelta, threshold string) bool {
-	for _, finding := range findings {
-		if vulnerabilityDeltaMeetsReachableThreshold(finding, threshold) {
-			return true
-		}
-	}
-	return false
-}
-
-func dependencyHasReachableVulnerabilityAtOrAbove(deps []report.DependencyReport, threshold string) bool {
-	for _, dep := range deps {
-		for _, finding := range dep.Vulnerabilities {
-			if !finding.Reachable || report.FindingSuppressedByException(finding) {
-				continue
-			}
-			if vulnerabilityFindingMeetsReachableThreshold(finding, threshold) {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func vulnerabilityDeltaMeetsReachableThreshold(finding report.VulnerabilityDelta, threshold string) bool {
-	return finding.VersionStatus == "unevaluable" || report.VulnerabilityPriorityMeetsThreshold(finding.Priority, threshold)
-}
-
-func vulnerabilityFindingMeetsReachableThreshold(finding report.VulnerabilityFinding, threshold string) bool {
-	return finding.VersionStatus == "unevaluable" || report.VulnerabilityPriorityMeetsThreshold(finding.Priority, threshold)
-}
@@ -15,6 +15,7 @@ type preparedAnalyseExecution struct {
 	removalCandidateWeights report.RemovalCandidateWeights
 	licensePolicy           report.LicensePolicy
 	vulnerabilityPolicy     report.VulnerabilityPolicy
+	policyRules             []report.PolicyRule
 	policySources           []string
 	policyTrace             []report.PolicyMergeTrace
 }
@@ -55,6 +56,8 @@ func prepareAnalyseExecution(ctx context.Context, req Request) (preparedAnalyseE
 		advisorySourcePath:      req.Analyse.AdvisorySourcePath,
 		advisorySourceTrustRoot: req.Analyse.AdvisorySourceTrustRoot,
 		vulnerabilityExceptions: req.Analyse.VulnerabilityExceptions,
+		licenseExceptions:       req.Analyse.LicenseExceptions,
+		policyRules:             req.Analyse.PolicyRules,
 		policySources:           req.Analyse.PolicySources,
 		policyTrace:             req.Analyse.PolicyTrace,
 	}
@@ -67,6 +70,7 @@ func prepareAnalyseExecution(ctx context.Context, req Request) (preparedAnalyseE
 		removalCandidateWeights: preparedPolicy.removalCandidateWeights,
 		licensePolicy:           preparedPolicy.licensePolicy,
 		vulnerabilityPolicy:     preparedPolicy.vulnerabilityPolicy,
+		policyRules:             preparedPolicy.policyRules,
 		policySources:           preparedPolicy.policySources,
 		policyTrace:             preparedPolicy.policyTrace,
 	}, nil
@@ -99,6 +103,7 @@ func decorateAnalyseReport(reportData *report.Report, prepared preparedAnalyseEx
 		RemovalCandidateWeights: prepared.removalCandidateWeights,
 		License:                 licensePolicy,
 		Vulnerabilities:         prepared.vulnerabilityPolicy,
+		Rules:                   append([]report.PolicyRule(nil), prepared.policyRules...),
 	}
 	reportData.Warnings = append(reportData.Warnings, prepared.lockfileWarnings...)
 }
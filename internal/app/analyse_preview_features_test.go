@@ -19,6 +19,13 @@ func TestValidateAnalysePreviewFeatureGates(t *testing.T) {
 	}{
 		{name: "cyclonedx", req: AnalyseRequest{Format: report.FormatCycloneDX}, feature: report.SBOMAttestationExportsPreviewFeature, want: "cyclonedx-json"},
 		{name: "spdx", req: AnalyseRequest{Format: report.FormatSPDX}, feature: report.SPDXSBOMExportPreviewFeature, want: "spdx-json"},
+		{name: "html", req: AnalyseRequest{Format: report.FormatHTML}, feature: report.HTMLReportPreviewFeature, want: "html"},
+		{name: "junit", req: AnalyseRequest{Format: report.FormatJUnit}, feature: report.CIReportFormatsPreviewFeature, want: "junit"},
+		{name: "gitlab codequality", req: AnalyseRequest{Format: report.FormatCodeQuality}, feature: report.CIReportFormatsPreviewFeature, want: "gitlab-codequality"},
+		{name: "template", req: AnalyseRequest{Format: report.FormatTemplate}, feature: report.CustomOutputTemplatesPreviewFeature, want: "template"},
+		{name: "explain exit", req: AnalyseRequest{ExplainExit: true}, feature: report.ExitCodeContractPreviewFeature, want: "--explain-exit"},
+		{name: "policy rules", req: AnalyseRequest{PolicyRules: []report.PolicyRule{{ID: "r", Severity: report.RuleSeverityError, Message: "m", Expr: "true"}}}, feature: report.PolicyRulesPreviewFeature, want: "policy rules"},
+		{name: "ndjson", req: AnalyseRequest{Format: report.FormatNDJSON}, feature: report.NDJSONReportStreamPreviewFeature, want: "ndjson"},
 		{name: "vex", req: AnalyseRequest{Format: report.FormatVEX}, feature: report.VulnerabilityExceptionsVEXPreviewFeature, want: "cyclonedx-vex-json"},
 		{name: "exceptions", req: AnalyseRequest{VulnerabilityExceptions: []report.VulnerabilityException{{VulnerabilityID: "GHSA-test"}}}, feature: report.VulnerabilityExceptionsVEXPreviewFeature, want: "vulnerability exceptions"},
 		{name: "advisory source", req: AnalyseRequest{AdvisorySourcePath: "advisories.json"}, feature: report.ReachabilityVulnerabilityPrioritizationPreviewFeature, want: "reachable vulnerability prioritization"},
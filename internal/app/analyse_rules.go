@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func validateAnalysePolicyRuleFeatures(req AnalyseRequest) error {
+	if len(req.PolicyRules) == 0 || req.Features.Enabled(report.PolicyRulesPreviewFeature) {
+		return nil
+	}
+	return fmt.Errorf("policy rules require --enable-feature %s", report.PolicyRulesPreviewFeature)
+}
+
+// applyPolicyRulesIfNeeded records rule findings before the baseline is
+// applied, so the baseline comparison can tell which findings are new.
+func applyPolicyRulesIfNeeded(reportData report.Report, req AnalyseRequest) (report.Report, error) {
+	if len(req.PolicyRules) == 0 {
+		return reportData, nil
+	}
+	findings, warnings, err := report.EvaluatePolicyRules(reportData, req.PolicyRules)
+	if err != nil {
+		return reportData, err
+	}
+	reportData.RuleFindings = findings
+	reportData.Warnings = append(reportData.Warnings, warnings...)
+	return reportData, nil
+}
+
+func validatePolicyRules(reportData report.Report, rules []report.PolicyRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	if report.HasPolicyRuleBreach(reportData) {
+		return ErrPolicyRules
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func policyRuleTestRequest(t *testing.T, rules ...report.PolicyRule) Request {
+	t.Helper()
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = t.TempDir()
+	req.Analyse.TopN = 5
+	req.Analyse.Format = report.FormatJSON
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.PolicyRulesPreviewFeature)
+	for _, rule := range rules {
+		normalized, err := report.NormalizePolicyRule(rule)
+		if err != nil {
+			t.Fatalf("normalize rule %s: %v", rule.ID, err)
+		}
+		req.Analyse.PolicyRules = append(req.Analyse.PolicyRules, normalized)
+	}
+	return req
+}
+
+func policyRuleTestAnalyzer() *fakeAnalyzer {
+	return &fakeAnalyzer{report: report.Report{
+		RepoPath: ".",
+		Dependencies: []report.DependencyReport{
+			{Language: "js-ts", Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 40, UsedPercent: 2.5, EstimatedUnusedBytes: 150 * 1024},
+			{
+				Language:     "js-ts",
+				Name:         "native-addon",
+				UsedPercent:  60,
+				RuntimeUsage: &report.RuntimeUsage{LoadCount: 1, RuntimeOnly: true},
+				RiskCues:     []report.RiskCue{{Code: "native-module", Severity: "critical", Message: "loads native code"}},
+			},
+		},
+	}}
+}
+
+func TestExecuteAnalyseEvaluatesPolicyRules(t *testing.T) {
+	req := policyRuleTestRequest(t,
+		report.PolicyRule{ID: "js-low-usage-weight", Severity: "warning", Message: "low-usage JS dependency adds more than 100 KB", Expr: `dependency.language == "js-ts" && dependency.usedPercent < 5 && dependency.estimatedUnusedBytes > 100 * 1024`},
+		report.PolicyRule{ID: "runtime-critical-risk", Severity: "error", Message: "runtime-only dependency has a critical risk cue", Expr: `has(dependency.runtimeUsage) && dependency.runtimeUsage.runtimeOnly && has(dependency.riskCues) && dependency.riskCues.exists(c, c.severity == "critical")`},
+	)
+	application := &App{Analyzer: policyRuleTestAnalyzer(), Formatter: report.NewFormatter()}
+
+	output, err := application.Execute(context.Background(), req)
+	if !errors.Is(err, ErrPolicyRules) {
+		t.Fatalf("expected policy rule error, got %v", err)
+	}
+	if got := ExitCode(err, false); got != legacyGateExitCode {
+		t.Fatalf("expected legacy gate exit code, got %d", got)
+	}
+	if got := ExitCode(err, true); got != report.ExitCodePolicyRules {
+		t.Fatalf("expected contract exit code %d, got %d", report.ExitCodePolicyRules, got)
+	}
+
+	var decoded report.Report
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	got := make([]string, 0, len(decoded.RuleFindings))
+	for _, finding := range decoded.RuleFindings {
+		got = append(got, finding.RuleID+"="+finding.Dependency)
+	}
+	if strings.Join(got, ",") != "js-low-usage-weight=lodash,runtime-critical-risk=native-addon" {
+		t.Fatalf("unexpected rule findings %q", strings.Join(got, ","))
+	}
+	if decoded.EffectivePolicy == nil || len(decoded.EffectivePolicy.Rules) != 2 {
+		t.Fatalf("expected effective policy to list rules, got %#v", decoded.EffectivePolicy)
+	}
+	if !buildNotificationOutcome(decoded, err).Breach {
+		t.Fatalf("expected policy rule failure to notify as a breach")
+	}
+}
+
+func TestExecuteAnalysePolicyRuleWarningsDoNotFail(t *testing.T) {
+	req := policyRuleTestRequest(t,
+		report.PolicyRule{ID: "js-low-usage-weight", Severity: "warning", Message: "low-usage JS dependency", Expr: `dependency.usedPercent < 5`},
+		report.PolicyRule{ID: "bad-field", Severity: "error", Message: "never matches", Expr: `dependency.name > 1`},
+	)
+	application := &App{Analyzer: policyRuleTestAnalyzer(), Formatter: report.NewFormatter()}
+
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected warning-only findings to pass, got %v", err)
+	}
+	var decoded report.Report
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(decoded.RuleFindings) != 1 || decoded.RuleFindings[0].Severity != report.RuleSeverityWarning {
+		t.Fatalf("unexpected rule findings %#v", decoded.RuleFindings)
+	}
+	if !strings.Contains(strings.Join(decoded.Warnings, "\n"), "policy rule bad-field could not be evaluated for 2 dependencies") {
+		t.Fatalf("expected evaluation warning, got %#v", decoded.Warnings)
+	}
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func loadAnalyseTemplate(req AnalyseRequest) (*report.ReportTemplate, error) {
+	if strings.TrimSpace(req.TemplatePath) == "" {
+		return nil, report.ErrTemplateRequired
+	}
+	return report.LoadReportTemplate(req.TemplatePath)
+}
+
+// validateAnalyseTemplate parses --template before analysis starts so a broken
+// template fails fast instead of after a full scan.
+func validateAnalyseTemplate(req AnalyseRequest) error {
+	if req.Format != report.FormatTemplate {
+		return nil
+	}
+	_, err := loadAnalyseTemplate(req)
+	return err
+}
+
+func (a *App) formatAnalyseReport(ctx context.Context, reportData report.Report, req AnalyseRequest) (string, error) {
+	if req.Format != report.FormatTemplate {
+		return a.Formatter.Format(reportData, req.Format)
+	}
+	tmpl, err := loadAnalyseTemplate(req)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Render(ctx, reportData)
+}
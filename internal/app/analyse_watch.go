@@ -0,0 +1,233 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/analysis"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/terminal"
+)
+
+const AnalyseWatchFeature = "analyse-watch-preview"
+
+var (
+	analyseWatchPollInterval time.Duration
+	analyseWatchDebounce     time.Duration
+)
+
+// analyseWatchEvent is one NDJSON line of `analyse --watch --format json`.
+// The first run lists every dependency; later runs list only dependencies
+// whose usage changed, appeared, or disappeared.
+type analyseWatchEvent struct {
+	Run          int                           `json:"run"`
+	Changed      []string                      `json:"changed,omitempty"`
+	Error        string                        `json:"error,omitempty"`
+	CacheHits    int                           `json:"cacheHits"`
+	CacheMisses  int                           `json:"cacheMisses"`
+	Summary      *report.Summary               `json:"summary,omitempty"`
+	Dependencies []analyseWatchDependencyDelta `json:"dependencies,omitempty"`
+}
+
+type analyseWatchDependencyDelta struct {
+	Language            string   `json:"language,omitempty"`
+	Name                string   `json:"name"`
+	UsedExportsCount    int      `json:"usedExportsCount"`
+	TotalExportsCount   int      `json:"totalExportsCount"`
+	UsedPercent         float64  `json:"usedPercent"`
+	PreviousUsedPercent *float64 `json:"previousUsedPercent,omitempty"`
+	Removed             bool     `json:"removed,omitempty"`
+}
+
+func validateAnalyseWatchFeatures(req AnalyseRequest) error {
+	if !req.Watch || req.Features.Enabled(AnalyseWatchFeature) {
+		return nil
+	}
+	return fmt.Errorf("analyse --watch requires --enable-feature %s", AnalyseWatchFeature)
+}
+
+// executeAnalyseWatch re-runs analysis whenever watched files change until ctx
+// is cancelled. Every run goes through the analysis cache, so only adapter
+// roots whose inputs changed are analysed again. Without an explicit
+// --cache-path the session uses a private temporary cache, because the
+// default in-repo cache never serves reads.
+func (a *App) executeAnalyseWatch(ctx context.Context, req Request) (string, error) {
+	cleanup, err := prepareAnalyseWatchCache(&req.Analyse)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	watcher, err := analysis.NewRepoWatcher(analysis.WatchOptions{
+		RepoPath:        req.RepoPath,
+		IncludePatterns: req.Analyse.IncludePatterns,
+		ExcludePatterns: req.Analyse.ExcludePatterns,
+		IgnoredPaths:    []string{req.Analyse.CachePath},
+		PollInterval:    analyseWatchPollInterval,
+		Debounce:        analyseWatchDebounce,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	out := a.Out
+	if out == nil {
+		out = io.Discard
+	}
+	var previous []report.DependencyReport
+	var changed []string
+	for run := 1; ; run++ {
+		reportData, runErr := a.runAnalyseWatchIteration(ctx, req)
+		if ctx.Err() != nil {
+			return "", nil
+		}
+		if err := a.writeAnalyseWatchRun(out, req, run, changed, previous, reportData, runErr); err != nil {
+			return "", err
+		}
+		if runErr == nil || len(reportData.Dependencies) > 0 {
+			previous = reportData.Dependencies
+		}
+
+		changed, err = watcher.Next(ctx)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func prepareAnalyseWatchCache(req *AnalyseRequest) (func(), error) {
+	if !req.CacheEnabled || strings.TrimSpace(req.CachePath) != "" {
+		return func() {}, nil
+	}
+	cachePath, cleanup, err := newWatchCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	req.CachePath = cachePath
+	req.CacheReadOnly = false
+	return cleanup, nil
+}
+
+func newWatchCacheDir() (string, func(), error) {
+	cachePath, err := os.MkdirTemp("", "lopper-watch-cache-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("create watch cache: %w", err)
+	}
+	return cachePath, func() {
+		_ = os.RemoveAll(cachePath)
+	}, nil
+}
+
+func (a *App) runAnalyseWatchIteration(ctx context.Context, req Request) (report.Report, error) {
+	prepared, err := prepareAnalyseExecution(ctx, req)
+	if err != nil {
+		return report.Report{}, err
+	}
+	reportData, err := a.invokeAnalyse(ctx, prepared)
+	if err != nil {
+		return report.Report{}, err
+	}
+	decorateAnalyseReport(&reportData, prepared)
+	return a.runAnalysePostStages(ctx, req.RepoPath, req.Analyse, reportData)
+}
+
+func (a *App) writeAnalyseWatchRun(out io.Writer, req Request, run int, changed []string, previous []report.DependencyReport, reportData report.Report, runErr error) error {
+	if req.Analyse.Format == report.FormatJSON {
+		event := analyseWatchEvent{
+			Run:          run,
+			Changed:      changed,
+			Summary:      reportData.Summary,
+			Dependencies: diffAnalyseWatchDependencies(previous, reportData.Dependencies),
+		}
+		if reportData.Cache != nil {
+			event.CacheHits, event.CacheMisses = reportData.Cache.Hits, reportData.Cache.Misses
+		}
+		if runErr != nil {
+			event.Error = runErr.Error()
+		}
+		line, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", line)
+		return err
+	}
+
+	var output strings.Builder
+	if runErr == nil || len(reportData.Dependencies) > 0 {
+		formatted, err := a.Formatter.Format(reportData, req.Analyse.Format)
+		if err != nil {
+			return err
+		}
+		output.WriteString(formatted)
+	}
+	output.WriteString("\n")
+	output.WriteString(formatAnalyseWatchStatus(req.RepoPath, run, changed, reportData.Cache, runErr))
+	if terminal.SupportsScreenRefresh(out) {
+		if err := terminal.ClearScreen(out); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(out, output.String())
+	return err
+}
+
+func formatAnalyseWatchStatus(repoPath string, run int, changed []string, cache *report.CacheMetadata, runErr error) string {
+	var status strings.Builder
+	fmt.Fprintf(&status, "Watching %s (run %d)", terminal.SanitizeString(repoPath), run)
+	if cache != nil && cache.Enabled {
+		fmt.Fprintf(&status, "; cache hits %d, misses %d", cache.Hits, cache.Misses)
+	}
+	status.WriteString(". Press Ctrl-C to stop.\n")
+	if len(changed) > 0 {
+		status.WriteString("Changed: " + strings.Join(terminal.SanitizeStrings(changed), ", ") + "\n")
+	}
+	if runErr != nil {
+		status.WriteString("error: " + terminal.SanitizeString(runErr.Error()) + "\n")
+	}
+	return status.String()
+}
+
+func diffAnalyseWatchDependencies(previous, current []report.DependencyReport) []analyseWatchDependencyDelta {
+	before := make(map[string]report.DependencyReport, len(previous))
+	for _, dependency := range previous {
+		before[dependency.Language+"\x00"+dependency.Name] = dependency
+	}
+	deltas := make([]analyseWatchDependencyDelta, 0)
+	for _, dependency := range current {
+		key := dependency.Language + "\x00" + dependency.Name
+		delta := analyseWatchDependencyDelta{
+			Language:          dependency.Language,
+			Name:              dependency.Name,
+			UsedExportsCount:  dependency.UsedExportsCount,
+			TotalExportsCount: dependency.TotalExportsCount,
+			UsedPercent:       dependency.UsedPercent,
+		}
+		prior, existed := before[key]
+		delete(before, key)
+		if existed {
+			if prior.UsedPercent == dependency.UsedPercent && prior.UsedExportsCount == dependency.UsedExportsCount && prior.TotalExportsCount == dependency.TotalExportsCount {
+				continue
+			}
+			previousUsedPercent := prior.UsedPercent
+			delta.PreviousUsedPercent = &previousUsedPercent
+		}
+		deltas = append(deltas, delta)
+	}
+	for _, dependency := range previous {
+		if _, missing := before[dependency.Language+"\x00"+dependency.Name]; missing {
+			previousUsedPercent := dependency.UsedPercent
+			deltas = append(deltas, analyseWatchDependencyDelta{Language: dependency.Language, Name: dependency.Name, PreviousUsedPercent: &previousUsedPercent, Removed: true})
+		}
+	}
+	return deltas
+}
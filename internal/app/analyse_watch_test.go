@@ -0,0 +1,131 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/analysis"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+type watchSequenceAnalyzer struct {
+	t       *testing.T
+	repo    string
+	reports []report.Report
+	calls   int
+	caches  []string
+}
+
+func (w *watchSequenceAnalyzer) Analyse(_ context.Context, req analysis.Request) (report.Report, error) {
+	w.calls++
+	w.caches = append(w.caches, req.Cache.Path)
+	if w.calls == 1 {
+		testutil.MustWriteFile(w.t, filepath.Join(w.repo, "index.js"), "import { map, filter } from \"lodash\"\n")
+	}
+	return w.reports[min(w.calls, len(w.reports))-1], nil
+}
+
+type cancellingLineWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	lines  int
+	after  int
+	cancel context.CancelFunc
+}
+
+func (w *cancellingLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines += bytes.Count(p, []byte("\n"))
+	n, err := w.buf.Write(p)
+	if w.lines >= w.after {
+		w.cancel()
+	}
+	return n, err
+}
+
+func TestExecuteAnalyseWatchEmitsNDJSONDeltas(t *testing.T) {
+	restorePoll, restoreDebounce := analyseWatchPollInterval, analyseWatchDebounce
+	analyseWatchPollInterval, analyseWatchDebounce = 5*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() { analyseWatchPollInterval, analyseWatchDebounce = restorePoll, restoreDebounce })
+
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "index.js"), "import { map } from \"lodash\"\n")
+	analyzer := &watchSequenceAnalyzer{t: t, repo: repo, reports: []report.Report{
+		{Dependencies: []report.DependencyReport{{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 4, UsedPercent: 25}, {Name: "left-pad", UsedPercent: 100}}},
+		{Dependencies: []report.DependencyReport{{Name: "lodash", UsedExportsCount: 2, TotalExportsCount: 4, UsedPercent: 50}}},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &cancellingLineWriter{after: 2, cancel: cancel}
+	application := &App{Analyzer: analyzer, Out: out, Formatter: report.NewFormatter()}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = repo
+	req.Analyse.TopN = 5
+	req.Analyse.Format = report.FormatJSON
+	req.Analyse.Watch = true
+	req.Analyse.Features = mustResolveAppTestFeatures(t, AnalyseWatchFeature)
+	output, err := application.Execute(ctx, req)
+	if err != nil || output != "" {
+		t.Fatalf("expected watch to stop cleanly on cancel, output=%q err=%v", output, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one NDJSON line per run, got %q", out.buf.String())
+	}
+	var first, second analyseWatchEvent
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode first event: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("decode second event: %v", err)
+	}
+	if first.Run != 1 || len(first.Dependencies) != 2 || len(first.Changed) != 0 {
+		t.Fatalf("unexpected first event %#v", first)
+	}
+	if second.Run != 2 || len(second.Changed) != 1 || second.Changed[0] != "index.js" || len(second.Dependencies) != 2 {
+		t.Fatalf("unexpected second event %#v", second)
+	}
+	lodash, leftPad := second.Dependencies[0], second.Dependencies[1]
+	if lodash.Name != "lodash" || lodash.UsedPercent != 50 || lodash.PreviousUsedPercent == nil || *lodash.PreviousUsedPercent != 25 {
+		t.Fatalf("unexpected lodash delta %#v", lodash)
+	}
+	if leftPad.Name != "left-pad" || !leftPad.Removed {
+		t.Fatalf("expected left-pad removal delta, got %#v", leftPad)
+	}
+	if len(analyzer.caches) != 2 || analyzer.caches[0] == "" || analyzer.caches[0] != analyzer.caches[1] || strings.HasPrefix(analyzer.caches[0], repo) {
+		t.Fatalf("expected one private session cache outside the repo, got %#v", analyzer.caches)
+	}
+}
+
+func TestExecuteAnalyseWatchRequiresPreviewFeature(t *testing.T) {
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.RepoPath = t.TempDir()
+	req.Analyse.TopN = 1
+	req.Analyse.Watch = true
+	if _, err := (&App{Analyzer: &fakeAnalyzer{}, Formatter: report.NewFormatter()}).Execute(context.Background(), req); err == nil || !strings.Contains(err.Error(), AnalyseWatchFeature) {
+		t.Fatalf("expected watch preview error, got %v", err)
+	}
+
+	if _, err := (&App{TUI: &fakeTUI{}}).Execute(context.Background(), Request{Mode: ModeTUI, TUI: TUIRequest{Watch: true}}); err == nil || !strings.Contains(err.Error(), AnalyseWatchFeature) {
+		t.Fatalf("expected tui watch preview error, got %v", err)
+	}
+}
+
+func TestFormatAnalyseWatchStatus(t *testing.T) {
+	status := formatAnalyseWatchStatus("repo", 3, []string{"src/a.ts"}, &report.CacheMetadata{Enabled: true, Hits: 2, Misses: 1}, nil)
+	if !strings.Contains(status, "Watching repo (run 3); cache hits 2, misses 1.") || !strings.Contains(status, "Changed: src/a.ts") {
+		t.Fatalf("unexpected status %q", status)
+	}
+}
@@ -11,6 +11,8 @@ type analysisRequestPolicy struct {
 	advisorySourcePath      string
 	advisorySourceTrustRoot string
 	vulnerabilityExceptions []report.VulnerabilityException
+	licenseExceptions       []report.LicenseException
+	policyRules             []report.PolicyRule
 	policySources           []string
 	policyTrace             []report.PolicyMergeTrace
 }
@@ -21,6 +23,7 @@ type preparedAnalysisPolicy struct {
 	removalCandidateWeights report.RemovalCandidateWeights
 	licensePolicy           report.LicensePolicy
 	vulnerabilityPolicy     report.VulnerabilityPolicy
+	policyRules             []report.PolicyRule
 	policySources           []string
 	policyTrace             []report.PolicyMergeTrace
 }
@@ -33,6 +36,8 @@ func prepareAnalysisPolicy(base analysis.Request, policy analysisRequestPolicy)
 	base.MinUsagePercentForRecommendations = &minUsage
 	base.RemovalCandidateWeights = &weights
 	base.LicenseDenyList = append([]string{}, policy.thresholds.LicenseDenyList...)
+	base.LicenseAllowList = append([]string{}, policy.thresholds.LicenseAllowList...)
+	base.LicenseUnknownPolicy = policy.thresholds.LicenseUnknownPolicy
 	base.IncludeRegistryProvenance = policy.thresholds.LicenseIncludeRegistryProvenance
 	base.VulnerabilityExceptions = append([]report.VulnerabilityException{}, policy.vulnerabilityExceptions...)
 
@@ -48,6 +53,9 @@ func prepareAnalysisPolicy(base analysis.Request, policy analysisRequestPolicy)
 		removalCandidateWeights: weights,
 		licensePolicy: report.LicensePolicy{
 			Deny:                      report.SortedDenyList(policy.thresholds.LicenseDenyList),
+			Allow:                     report.SortedLicensePatterns(policy.thresholds.LicenseAllowList),
+			Unknown:                   effectiveLicenseUnknownPolicy(policy.thresholds.LicenseUnknownPolicy),
+			Exceptions:                append([]report.LicenseException(nil), policy.licenseExceptions...),
 			FailOnDenied:              policy.thresholds.LicenseFailOnDeny,
 			IncludeRegistryProvenance: policy.thresholds.LicenseIncludeRegistryProvenance,
 		},
@@ -55,7 +63,18 @@ func prepareAnalysisPolicy(base analysis.Request, policy analysisRequestPolicy)
 			AdvisorySourcePath:         policy.advisorySourcePath,
 			ReachablePriorityThreshold: policy.thresholds.ReachableVulnerabilityPriority,
 		},
+		policyRules:   append([]report.PolicyRule(nil), policy.policyRules...),
 		policySources: append([]string{}, policy.policySources...),
 		policyTrace:   append([]report.PolicyMergeTrace{}, policy.policyTrace...),
 	}
 }
+
+// effectiveLicenseUnknownPolicy leaves the default allow policy out of the
+// report so runs without license policy keep their existing output.
+func effectiveLicenseUnknownPolicy(value string) string {
+	normalized := report.NormalizeLicenseUnknownPolicy(value)
+	if normalized == report.LicenseUnknownAllow {
+		return ""
+	}
+	return normalized
+}
@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
 
 	"github.com/ben-ranford/lopper/internal/analysis"
 	"github.com/ben-ranford/lopper/internal/featureflags"
@@ -22,11 +24,14 @@ var (
 	ErrUncertaintyThresholdExceeded = errors.New("uncertain dynamic import/require usage exceeded threshold")
 	ErrDeniedLicenses               = errors.New("denied licenses detected")
 	ErrReachableVulnerabilities     = errors.New("reachable vulnerabilities detected")
+	ErrPolicyRules                  = errors.New("policy rule violations detected")
 	ErrDirtyWorktree                = errors.New("codemod apply requires a clean git worktree")
 	ErrCodemodApplyFailed           = errors.New("codemod apply failed")
 	ErrMCPFeatureDisabled           = errors.New("mcp server feature is disabled")
 	ErrProfileFeatureDisabled       = errors.New("threshold profile command feature is disabled; enable threshold-profiles with --enable-feature")
 	ErrBaselineFeatureDisabled      = errors.New("baseline discovery feature is disabled; remove baseline-store-discovery from --disable-feature or features.disable")
+	ErrCacheFeatureDisabled         = errors.New("cache maintenance command is disabled; enable analysis-cache-maintenance-preview with --enable-feature")
+	ErrCacheVerificationFailed      = errors.New("analysis cache verification failed")
 	ErrPRReviewRegressions          = errors.New("pr review regressions detected")
 )
 
@@ -34,6 +39,7 @@ type App struct {
 	Analyzer  analysis.Analyser
 	In        io.Reader
 	Out       io.Writer
+	Err       io.Writer
 	Formatter *report.Formatter
 	TUI       ui.TUI
 	Notify    *notify.Dispatcher
@@ -49,6 +55,7 @@ func New(out io.Writer, in io.Reader) *App {
 		Analyzer:  analyzer,
 		In:        in,
 		Out:       out,
+		Err:       os.Stderr,
 		Formatter: formatter,
 		Notify:    notify.NewDefaultDispatcher(),
 		Features:  featureflags.DefaultRegistry(),
@@ -89,6 +96,8 @@ func (a *App) Execute(ctx context.Context, req Request) (string, error) {
 		})
 	case ModeAdvisory:
 		return a.executeAdvisory(ctx, req)
+	case ModeCache:
+		return a.executeCache(req)
 	default:
 		return "", ErrUnknownMode
 	}
@@ -109,5 +118,17 @@ func (a *App) executeTUI(ctx context.Context, req Request) (string, error) {
 	if req.TUI.SnapshotPath != "" {
 		return "", a.TUI.Snapshot(ctx, opts, req.TUI.SnapshotPath)
 	}
+	if req.TUI.Watch {
+		if !req.TUI.Features.Enabled(AnalyseWatchFeature) {
+			return "", fmt.Errorf("tui --watch requires --enable-feature %s", AnalyseWatchFeature)
+		}
+		cachePath, cleanup, err := newWatchCacheDir()
+		if err != nil {
+			return "", err
+		}
+		defer cleanup()
+		opts.Watch = true
+		opts.CachePath = cachePath
+	}
 	return "", a.TUI.Start(ctx, opts)
 }
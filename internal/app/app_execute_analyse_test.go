@@ -1,6 +1,7 @@
 package app
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ben-ranford/lopper/internal/analysis"
 	"github.com/ben-ranford/lopper/internal/featureflags"
 	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/safeio"
@@ -123,6 +125,169 @@ func TestExecuteAnalyseOutputFile(t *testing.T) {
 	}
 }
 
+func TestExecuteAnalyseStreamsNDJSON(t *testing.T) {
+	analyzer := &fakeAnalyzer{
+		report: report.Report{
+			RepoPath: ".",
+			Dependencies: []report.DependencyReport{
+				{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 2, UsedPercent: 50},
+			},
+		},
+	}
+	var out bytes.Buffer
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter(), Out: &out}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.Analyse.TopN = 1
+	req.Analyse.Format = report.FormatNDJSON
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.NDJSONReportStreamPreviewFeature)
+
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf(executeAnalyseErrFmt, err)
+	}
+	if output != "" {
+		t.Fatalf("expected ndjson to stream to Out instead of returning output, got %q", output)
+	}
+	streamed, err := report.ReadNDJSON(&out, nil)
+	if err != nil {
+		t.Fatalf("read streamed ndjson: %v", err)
+	}
+	if len(streamed.Dependencies) != 1 || streamed.Dependencies[0].Name != "lodash" || streamed.EffectivePolicy == nil {
+		t.Fatalf("unexpected streamed report: %#v", streamed)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "reports", "analyse.ndjson")
+	req.Analyse.OutputPath = outputPath
+	output, err = application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf(executeAnalyseErrFmt, err)
+	}
+	if output != "analyse report written to "+outputPath {
+		t.Fatalf("expected output file confirmation, got %q", output)
+	}
+	loaded, err := report.Load(outputPath)
+	if err != nil {
+		t.Fatalf("load ndjson output as baseline: %v", err)
+	}
+	if len(loaded.Dependencies) != 1 || loaded.Dependencies[0].Name != "lodash" {
+		t.Fatalf("unexpected ndjson output file: %#v", loaded)
+	}
+	entries, err := os.ReadDir(filepath.Dir(outputPath))
+	if err != nil {
+		t.Fatalf("read output dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the ndjson output file, found %d entries", len(entries))
+	}
+}
+
+func TestExecuteAnalyseRendersTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "digest.tmpl")
+	if err := os.WriteFile(templatePath, []byte("{{ range .Dependencies }}{{ .Name }} {{ percent .UsedPercent }}\n{{ end }}"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	analyzer := &fakeAnalyzer{
+		report: report.Report{
+			RepoPath:     ".",
+			Dependencies: []report.DependencyReport{{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 2, UsedPercent: 50}},
+		},
+	}
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter()}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.Analyse.TopN = 1
+	req.Analyse.Format = report.FormatTemplate
+	req.Analyse.TemplatePath = templatePath
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.CustomOutputTemplatesPreviewFeature)
+
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf(executeAnalyseErrFmt, err)
+	}
+	if output != "lodash 50.0%\n" {
+		t.Fatalf("unexpected template output %q", output)
+	}
+
+	if err := os.WriteFile(templatePath, []byte("{{ .Name "), 0o600); err != nil {
+		t.Fatalf("write broken template: %v", err)
+	}
+	analyzer.called = false
+	if _, err := application.Execute(context.Background(), req); err == nil || !strings.Contains(err.Error(), "parse template") {
+		t.Fatalf("expected template parse error, got %v", err)
+	}
+	if analyzer.called {
+		t.Fatalf("expected a broken template to fail before analysis")
+	}
+
+	req.Analyse.TemplatePath = ""
+	if _, err := application.Execute(context.Background(), req); !errors.Is(err, report.ErrTemplateRequired) {
+		t.Fatalf("expected ErrTemplateRequired, got %v", err)
+	}
+}
+
+type streamingAnalyzer struct {
+	out        *bytes.Buffer
+	roots      map[string][]report.DependencyReport
+	headerSeen bool
+}
+
+func (s *streamingAnalyzer) Analyse(_ context.Context, req analysis.Request) (report.Report, error) {
+	scope := &report.ScopeMetadata{Mode: "package", Packages: []string{"packages/a", "packages/b"}}
+	if err := req.StreamStart(scope, &report.CacheMetadata{Enabled: true, Path: ".lopper-cache"}); err != nil {
+		return report.Report{}, err
+	}
+	s.headerSeen = strings.HasPrefix(s.out.String(), `{"record":"header"`)
+	merged := report.Report{RepoPath: req.RepoPath, Scope: scope}
+	for _, root := range []string{"packages/a", "packages/b"} {
+		if err := req.DependencyStream(root, s.roots[root]); err != nil {
+			return report.Report{}, err
+		}
+		merged.Dependencies = append(merged.Dependencies, s.roots[root]...)
+	}
+	merged.Summary = report.ComputeSummary(merged.Dependencies)
+	return merged, nil
+}
+
+func TestExecuteAnalyseStreamsNDJSONRootsDuringAnalysis(t *testing.T) {
+	var out bytes.Buffer
+	analyzer := &streamingAnalyzer{out: &out, roots: map[string][]report.DependencyReport{
+		"packages/a": {{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 2, UsedPercent: 50}},
+		"packages/b": {{Name: "react", UsedExportsCount: 1, TotalExportsCount: 1, UsedPercent: 100}},
+	}}
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter(), Out: &out}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.Analyse.Format = report.FormatNDJSON
+	req.Analyse.Features = mustResolveAppTestFeatures(t, report.NDJSONReportStreamPreviewFeature)
+	if _, err := application.Execute(context.Background(), req); err != nil {
+		t.Fatalf(executeAnalyseErrFmt, err)
+	}
+	if !analyzer.headerSeen {
+		t.Fatalf("expected the header to be written before roots are analysed")
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if !strings.Contains(lines[0], `"scope":{"mode":"package"`) || !strings.Contains(lines[0], `"cache":{"enabled":true`) {
+		t.Fatalf("expected scope and cache in the header, got %q", lines[0])
+	}
+	if len(lines) != 6 || !strings.Contains(lines[1], `"root":"packages/a"`) || !strings.Contains(lines[2], `"root":"packages/b"`) {
+		t.Fatalf("expected one dependency record per streamed root, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[3], `{"record":"result","name":"lodash"`) || !strings.HasPrefix(lines[4], `{"record":"result","name":"react"`) {
+		t.Fatalf("expected result records for the finished report, got %q", out.String())
+	}
+	streamed, err := report.ReadNDJSON(&out, nil)
+	if err != nil {
+		t.Fatalf("read streamed ndjson: %v", err)
+	}
+	if len(streamed.Dependencies) != 2 || streamed.Summary == nil || streamed.Summary.DependencyCount != 2 || streamed.EffectivePolicy == nil {
+		t.Fatalf("unexpected streamed report: %#v", streamed)
+	}
+}
+
 func TestExecuteAnalyseRejectsAbsoluteOutputUnderRequestedRepoSymlinkOutsideWorkingDirectory(t *testing.T) {
 	repo := filepath.Join(t.TempDir(), "repo")
 	if err := os.MkdirAll(repo, 0o755); err != nil {
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/thresholds"
@@ -302,6 +303,60 @@ func TestExecuteAnalyseDeniedLicensesError(t *testing.T) {
 	}
 }
 
+func TestExecuteAnalyseLicenseExceptionLiftsDenial(t *testing.T) {
+	analyzer := &fakeAnalyzer{
+		report: report.Report{
+			RepoPath: ".",
+			Dependencies: []report.DependencyReport{
+				{
+					Name:    "copyleft",
+					License: &report.DependencyLicense{SPDX: deniedLicenseSPDX, Denied: true},
+				},
+			},
+		},
+	}
+	application := &App{Analyzer: analyzer, Formatter: report.NewFormatter()}
+
+	req := DefaultRequest()
+	req.Mode = ModeAnalyse
+	req.Analyse.TopN = 1
+	req.Analyse.Format = report.FormatJSON
+	req.Analyse.Thresholds.LicenseFailOnDeny = true
+	req.Analyse.LicenseExceptions = []report.LicenseException{{Package: "copyleft", Owner: "legal", Reason: "reviewed", Expires: "2999-01-01"}}
+
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected license exception to lift denial, got %v", err)
+	}
+	if !strings.Contains(output, `"exception"`) {
+		t.Fatalf("expected license exception decision in output, got %q", output)
+	}
+}
+
+func TestApplyLicenseExceptionsToReportRecomputesSummary(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	reportData := report.Report{Dependencies: []report.DependencyReport{
+		{Name: "lifted", License: &report.DependencyLicense{SPDX: deniedLicenseSPDX, Denied: true}},
+		{Name: "lapsed", License: &report.DependencyLicense{SPDX: deniedLicenseSPDX, Denied: true}},
+	}}
+	reportData.Summary = report.ComputeSummary(reportData.Dependencies)
+	exceptions := []report.LicenseException{
+		{Package: "lifted", Owner: "legal", Reason: "reviewed", Expires: "2027-01-01"},
+		{Package: "lapsed", Owner: "legal", Reason: "reviewed", Expires: "2026-01-01"},
+	}
+
+	got := applyLicenseExceptionsToReport(reportData, exceptions, now)
+	if got.Summary == nil || got.Summary.DeniedLicenseCount != 1 {
+		t.Fatalf("expected summary recomputed with one denied license, got %#v", got.Summary)
+	}
+	if strings.Join(got.Warnings, ";") != "license exception: expired license exception restored denial for lapsed" {
+		t.Fatalf("unexpected warnings: %#v", got.Warnings)
+	}
+	if unchanged := applyLicenseExceptionsToReport(reportData, nil, now); unchanged.Summary.DeniedLicenseCount != 2 {
+		t.Fatalf("expected no-op without exceptions, got %#v", unchanged.Summary)
+	}
+}
+
 func TestExecuteAnalyseReachableVulnerabilityThresholdError(t *testing.T) {
 	tmp := t.TempDir()
 	advisoryPath := filepath.Join(tmp, "advisories.yml")
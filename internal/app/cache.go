@@ -0,0 +1,159 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/analysis"
+	"github.com/ben-ranford/lopper/internal/safeio"
+	"github.com/ben-ranford/lopper/internal/terminal"
+)
+
+const CacheMaintenanceFeature = "analysis-cache-maintenance-preview"
+
+func (a *App) executeCache(req Request) (string, error) {
+	if !req.Cache.Features.Enabled(CacheMaintenanceFeature) {
+		return "", ErrCacheFeatureDisabled
+	}
+	format := strings.ToLower(strings.TrimSpace(req.Cache.Format))
+	if format != "table" && format != "json" {
+		return "", fmt.Errorf("invalid cache format: %s", req.Cache.Format)
+	}
+	switch req.Cache.Command {
+	case "stats":
+		stats, err := analysis.InspectCache(req.Cache.CachePath)
+		if err != nil {
+			return "", err
+		}
+		if format == "json" {
+			return formatBaselineJSON(stats)
+		}
+		return formatCacheStats(stats), nil
+	case "verify":
+		verification, err := analysis.VerifyCache(req.Cache.CachePath)
+		if err != nil {
+			return "", err
+		}
+		output := formatCacheVerification(verification)
+		if format == "json" {
+			if output, err = formatBaselineJSON(verification); err != nil {
+				return "", err
+			}
+		}
+		if len(verification.Issues) > 0 {
+			return output, fmt.Errorf("%w: %d issue(s)", ErrCacheVerificationFailed, len(verification.Issues))
+		}
+		return output, nil
+	case "prune":
+		result, err := analysis.PruneCache(req.Cache.CachePath, analysis.CachePruneOptions{MaxAge: req.Cache.MaxAge, MaxBytes: req.Cache.MaxBytes})
+		if err != nil {
+			return "", err
+		}
+		if format == "json" {
+			return formatBaselineJSON(result)
+		}
+		return formatCacheCleanup("Pruned", result), nil
+	case "clear":
+		result, err := analysis.ClearCache(req.Cache.CachePath)
+		if err != nil {
+			return "", err
+		}
+		if format == "json" {
+			return formatBaselineJSON(result)
+		}
+		return formatCacheCleanup("Cleared", result), nil
+	case "export":
+		return executeCacheExport(req.Cache, format)
+	case "import":
+		return executeCacheImport(req.Cache, format)
+	default:
+		return "", fmt.Errorf("unknown cache command: %s", req.Cache.Command)
+	}
+}
+
+func executeCacheExport(req CacheRequest, format string) (string, error) {
+	if strings.TrimSpace(req.BundlePath) == "" || strings.TrimSpace(req.BundlePath) == "-" {
+		return "", fmt.Errorf("cache export requires an output file")
+	}
+	// Bundles can approach maxCacheBundleBytes, so they are streamed into a
+	// temporary file beside the destination instead of buffered in memory.
+	var summary analysis.CacheBundleSummary
+	written, err := streamCommandOutput(nil, func(w io.Writer) error {
+		var exportErr error
+		summary, exportErr = analysis.ExportCache(req.CachePath, w)
+		return exportErr
+	}, req.BundlePath, "Cache bundle")
+	if err != nil {
+		return "", err
+	}
+	summary.Path = req.BundlePath
+	if format == "json" {
+		return formatBaselineJSON(summary)
+	}
+	return written + "\n" + formatCacheBundleSummary("Exported to", summary), nil
+}
+
+func executeCacheImport(req CacheRequest, format string) (_ string, returnErr error) {
+	bundle, err := safeio.OpenFile(req.BundlePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		returnErr = errors.Join(returnErr, bundle.Close())
+	}()
+	summary, err := analysis.ImportCache(req.CachePath, bundle)
+	if err != nil {
+		return "", err
+	}
+	if format == "json" {
+		return formatBaselineJSON(summary)
+	}
+	return formatCacheBundleSummary("Imported", summary), nil
+}
+
+func formatCacheStats(stats analysis.CacheStats) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Cache: %s\n", terminal.SanitizeString(stats.Path))
+	fmt.Fprintf(&output, "Entries: %d\n", stats.Entries)
+	fmt.Fprintf(&output, "Objects: %d (%d unreferenced)\n", stats.Objects, stats.UnreferencedObjects)
+	fmt.Fprintf(&output, "File entries: %d\n", stats.FileEntries)
+	fmt.Fprintf(&output, "Bytes: %d\n", stats.Bytes)
+	if stats.LastUsed != nil {
+		fmt.Fprintf(&output, "Last used: %s\n", stats.LastUsed.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	if len(stats.Adapters) > 0 {
+		output.WriteString("ADAPTER\tENTRIES\tBYTES\tLAST USED\n")
+		for _, adapter := range stats.Adapters {
+			fmt.Fprintf(&output, "%s\t%d\t%d\t%s\n", terminal.SanitizeString(adapter.Adapter), adapter.Entries, adapter.Bytes, adapter.LastUsed.UTC().Format("2006-01-02T15:04:05Z"))
+		}
+	}
+	return output.String()
+}
+
+func formatCacheVerification(verification analysis.CacheVerification) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "Verified %d cache file(s) in %s.\n", verification.Checked, terminal.SanitizeString(verification.Path))
+	if len(verification.Issues) == 0 {
+		output.WriteString("No issues found.\n")
+		return output.String()
+	}
+	output.WriteString("Issues:\n")
+	for _, issue := range verification.Issues {
+		fmt.Fprintf(&output, "- %s: %s\n", terminal.SanitizeString(issue.File), issue.Reason)
+	}
+	return output.String()
+}
+
+func formatCacheCleanup(verb string, result analysis.CachePruneResult) string {
+	return fmt.Sprintf("%s %s: removed %d entries, %d objects, %d file entries; freed %d bytes, %d bytes remain.\n", verb, terminal.SanitizeString(result.Path), result.RemovedEntries, result.RemovedObjects, result.RemovedFileEntries, result.FreedBytes, result.RemainingBytes)
+}
+
+func formatCacheBundleSummary(verb string, summary analysis.CacheBundleSummary) string {
+	line := fmt.Sprintf("%s %s: %d entries, %d objects, %d file entries, %d bytes", verb, terminal.SanitizeString(summary.Path), summary.Entries, summary.Objects, summary.FileEntries, summary.Bytes)
+	if summary.Skipped > 0 {
+		line += fmt.Sprintf("; skipped %d unverifiable cache file(s)", summary.Skipped)
+	}
+	return line + ".\n"
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestExecuteCacheRequiresPreviewAndReportsVerification(t *testing.T) {
+	cachePath := t.TempDir()
+	for _, dir := range []string{"keys", "objects", "files"} {
+		if err := os.MkdirAll(filepath.Join(cachePath, dir), 0o750); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	testutil.MustWriteFile(t, filepath.Join(cachePath, "keys", "entry.json"), `{"adapter":"js-ts","inputDigest":"input","objectDigest":"`+strings.Repeat("a", 64)+`"}`)
+
+	application := &App{}
+	req := DefaultRequest()
+	req.Mode = ModeCache
+	req.Cache = CacheRequest{Command: "stats", CachePath: cachePath, Format: "table"}
+	if _, err := application.Execute(context.Background(), req); !errors.Is(err, ErrCacheFeatureDisabled) {
+		t.Fatalf("expected cache preview feature error, got %v", err)
+	}
+
+	req.Cache.Features = mustResolveAppTestFeatures(t, CacheMaintenanceFeature)
+	output, err := application.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("cache stats: %v", err)
+	}
+	if !strings.Contains(output, "Entries: 1\n") || !strings.Contains(output, "js-ts\t1\t") {
+		t.Fatalf("unexpected stats output %q", output)
+	}
+
+	req.Cache.Command = "verify"
+	output, err = application.Execute(context.Background(), req)
+	if !errors.Is(err, ErrCacheVerificationFailed) || !strings.Contains(output, "keys/entry.json: object-missing") {
+		t.Fatalf("expected verification failure output, got %q err=%v", output, err)
+	}
+
+	req.Cache.Command = "clear"
+	req.Cache.Format = "json"
+	output, err = application.Execute(context.Background(), req)
+	if err != nil || !strings.Contains(output, `"removedEntries": 1`) {
+		t.Fatalf("unexpected clear output %q err=%v", output, err)
+	}
+}
+
+func TestExecuteCacheExportAndImportBundle(t *testing.T) {
+	cachePath := t.TempDir()
+	for _, dir := range []string{"keys", "objects", "files"} {
+		if err := os.MkdirAll(filepath.Join(cachePath, dir), 0o750); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	testutil.MustWriteFile(t, filepath.Join(cachePath, "files", strings.Repeat("b", 64)+".json"), `{"scan":{}}`)
+
+	application := &App{}
+	req := DefaultRequest()
+	req.Mode = ModeCache
+	bundlePath := filepath.Join(t.TempDir(), "cache.tar")
+	req.Cache = CacheRequest{Command: "export", CachePath: cachePath, BundlePath: bundlePath, Format: "table", Features: mustResolveAppTestFeatures(t, CacheMaintenanceFeature)}
+	output, err := application.Execute(context.Background(), req)
+	if err != nil || !strings.Contains(output, "Cache bundle written to "+bundlePath) || !strings.Contains(output, "1 file entries") {
+		t.Fatalf("unexpected export output %q err=%v", output, err)
+	}
+
+	req.Cache.Command = "import"
+	req.Cache.CachePath = filepath.Join(t.TempDir(), "restored")
+	req.Cache.Format = "json"
+	output, err = application.Execute(context.Background(), req)
+	if err != nil || !strings.Contains(output, `"fileEntries": 1`) {
+		t.Fatalf("unexpected import output %q err=%v", output, err)
+	}
+	if _, err := os.Stat(filepath.Join(req.Cache.CachePath, "files", strings.Repeat("b", 64)+".json")); err != nil {
+		t.Fatalf("expected imported file entry: %v", err)
+	}
+
+	failedDir := t.TempDir()
+	req.Cache = CacheRequest{Command: "export", CachePath: filepath.Join(t.TempDir(), "missing"), BundlePath: filepath.Join(failedDir, "cache.tar"), Format: "table", Features: req.Cache.Features}
+	if _, err := application.Execute(context.Background(), req); err == nil {
+		t.Fatalf("expected export of a missing cache to fail")
+	}
+	if entries, err := os.ReadDir(failedDir); err != nil || len(entries) != 0 {
+		t.Fatalf("expected failed export to leave no bundle or temp file, got %v err=%v", entries, err)
+	}
+}
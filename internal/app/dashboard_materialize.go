@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,9 @@ var (
 	writeCommandOutputFileFn     = func(root *safeio.WriteRoot, targetPath string, data []byte, perm, parentPerm os.FileMode) error {
 		return root.WriteFileCreatingParents(targetPath, data, perm, parentPerm)
 	}
+	streamCommandOutputFileFn = func(root *safeio.WriteRoot, targetPath string, write func(io.Writer) error, perm, parentPerm os.FileMode) error {
+		return root.StreamFileCreatingParents(targetPath, write, perm, parentPerm)
+	}
 	commandOutputBoundaryAcceptedFn = func() error { return nil }
 )
 
@@ -32,16 +36,39 @@ func persistDashboardOutput(formatted, outputPath string, trustedRoots ...string
 	return persistCommandOutput(formatted, outputPath, "dashboard report", trustedRoots...)
 }
 
-func persistCommandOutput(formatted, outputPath, label string, trustedRoots ...string) (result string, returnErr error) {
+func persistCommandOutput(formatted, outputPath, label string, trustedRoots ...string) (string, error) {
 	trimmedOutputPath := strings.TrimSpace(outputPath)
 	if trimmedOutputPath == "" || trimmedOutputPath == "-" {
 		return formatted, nil
 	}
-	if hasDirectoryStyleOutputPath(trimmedOutputPath) {
-		return "", fmt.Errorf("output path must name a file: %s", trimmedOutputPath)
+	return writeCommandOutputDestination(trimmedOutputPath, label, trustedRoots, func(destination commandOutputDestination) error {
+		return writeCommandOutputFileFn(destination.root, destination.targetPath, []byte(formatted), 0o600, 0o750)
+	})
+}
+
+// streamCommandOutput is persistCommandOutput for output rendered
+// incrementally: without an output path it writes straight to out, otherwise
+// it streams into the pinned output file, which is replaced only once write
+// succeeds.
+func streamCommandOutput(out io.Writer, write func(io.Writer) error, outputPath, label string, trustedRoots ...string) (string, error) {
+	trimmedOutputPath := strings.TrimSpace(outputPath)
+	if trimmedOutputPath == "" || trimmedOutputPath == "-" {
+		if out == nil {
+			out = io.Discard
+		}
+		return "", write(out)
+	}
+	return writeCommandOutputDestination(trimmedOutputPath, label, trustedRoots, func(destination commandOutputDestination) error {
+		return streamCommandOutputFileFn(destination.root, destination.targetPath, write, 0o600, 0o750)
+	})
+}
+
+func writeCommandOutputDestination(outputPath, label string, trustedRoots []string, write func(commandOutputDestination) error) (result string, returnErr error) {
+	if hasDirectoryStyleOutputPath(outputPath) {
+		return "", fmt.Errorf("output path must name a file: %s", outputPath)
 	}
 
-	destination, err := openCommandOutputDestination(trimmedOutputPath, trustedRoots...)
+	destination, err := openCommandOutputDestination(outputPath, trustedRoots...)
 	if err != nil {
 		return "", err
 	}
@@ -50,10 +77,10 @@ func persistCommandOutput(formatted, outputPath, label string, trustedRoots ...s
 			returnErr = errors.Join(returnErr, closeErr)
 		}
 	}()
-	if err := writeCommandOutputFileFn(destination.root, destination.targetPath, []byte(formatted), 0o600, 0o750); err != nil {
+	if err := write(destination); err != nil {
 		return "", err
 	}
-	return label + " written to " + trimmedOutputPath, nil
+	return label + " written to " + outputPath, nil
 }
 
 func commandOutputRoot(outputPath string, trustedRoots ...string) (string, error) {
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+// Legacy exit codes used when exit-code-contract-preview is disabled.
+const (
+	legacyGateExitCode          = 3
+	legacyLockfileDriftExitCode = 4
+)
+
+// GateForError returns the gate behind a gate failure, or "" when err is not
+// one.
+func GateForError(err error) string {
+	switch {
+	case errors.Is(err, ErrLockfileDrift):
+		return report.GateLockfileDrift
+	case errors.Is(err, ErrFailOnIncrease):
+		return report.GateFailOnIncrease
+	case errors.Is(err, ErrUncertaintyThresholdExceeded):
+		return report.GateMaxUncertainImports
+	case errors.Is(err, ErrDeniedLicenses):
+		return report.GateLicenseFailOnDeny
+	case errors.Is(err, ErrReachableVulnerabilities):
+		return report.GateReachableVulnerabilityPriority
+	case errors.Is(err, ErrPolicyRules):
+		return report.GatePolicyRules
+	case errors.Is(err, ErrPRReviewRegressions):
+		return report.GatePRReviewRegressions
+	default:
+		return ""
+	}
+}
+
+// ExitCode maps the error returned by Execute to a process exit code. With the
+// exit-code contract each gate exits with its own code; otherwise every gate
+// exits 3 and lockfile drift exits 4. Any other error exits 1.
+func ExitCode(err error, contract bool) int {
+	if err == nil {
+		return 0
+	}
+	gate := GateForError(err)
+	switch {
+	case gate == "":
+		return 1
+	case contract:
+		return report.GateExitCode(gate)
+	case gate == report.GateLockfileDrift:
+		return legacyLockfileDriftExitCode
+	default:
+		return legacyGateExitCode
+	}
+}
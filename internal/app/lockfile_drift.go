@@ -29,16 +29,39 @@ func evaluateLockfileDriftPolicyWithFeatures(ctx context.Context, repoPath, poli
 	}
 	failMode := normalizedPolicy == "fail"
 	result := detectLockfileDriftDetailed(ctx, repoPath, failMode, features)
+	contentWarnings := appendLockfileContentDrift(ctx, repoPath, failMode, features, &result)
 	if result.err != nil && !failMode && isPureLockfileManifestReadSizeError(result.err) {
-		return result.orderedWarnings, nil
+		return append(result.orderedWarnings, contentWarnings...), nil
 	}
-	if result.err != nil || len(result.findings) == 0 {
+	if result.err != nil {
 		return result.findings, result.err
 	}
+	if len(result.findings) == 0 {
+		return contentWarnings, nil
+	}
 	if failMode {
 		return result.findings, formatLockfileDriftError(result.findings)
 	}
-	return result.findings, nil
+	return append(result.findings, contentWarnings...), nil
+}
+
+// appendLockfileContentDrift adds content-level findings to result and returns
+// non-drift warnings for lockfiles that could not be compared.
+func appendLockfileContentDrift(ctx context.Context, repoPath string, stopOnFirst bool, features featureflags.Set, result *lockfileDriftResult) []string {
+	if result.err != nil && !isPureLockfileManifestReadSizeError(result.err) {
+		return nil
+	}
+	if stopOnFirst && len(result.findings) > 0 {
+		return nil
+	}
+	scan, err := detectLockfileContentDrift(ctx, repoPath, features, stopOnFirst)
+	if err != nil {
+		result.err = errors.Join(result.err, err)
+		return nil
+	}
+	result.findings = append(result.findings, scan.findings...)
+	result.orderedWarnings = append(result.orderedWarnings, scan.findings...)
+	return scan.warnings
 }
 
 func oversizedLockfileDriftWarning(err error) string {
@@ -0,0 +1,300 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	LockfileDriftContentPreviewFeature       = "lockfile-drift-content-preview"
+	lockfileContentReadLimit           int64 = 64 << 20
+)
+
+type lockfileContentDriftKind uint8
+
+const (
+	lockfileContentMissing lockfileContentDriftKind = iota + 1
+	lockfileContentUnsatisfied
+	lockfileContentExtra
+)
+
+// lockfileContentParser compares a manifest against the contents of one of
+// its rule's lockfiles. Parsers only need to extract declarations and locked
+// versions; the comparison itself is shared.
+type lockfileContentParser struct {
+	declared func(manifest []byte) ([]lockfileDeclaredDependency, error)
+	locked   map[string]func(lockfile []byte) (lockfileLockedState, error)
+	// manifests, when set, limits the comparison to the rule manifests that
+	// declare dependencies the parser can read.
+	manifests func(manifest string) bool
+	normalize func(string) string
+	style     lockfileConstraintStyle
+}
+
+type lockfileDeclaredDependency struct {
+	name       string
+	constraint string
+}
+
+// lockfileLockedState holds every locked version per normalized name. roots
+// lists the dependencies the lockfile records as direct; it stays nil when
+// the format does not distinguish direct from transitive entries.
+type lockfileLockedState struct {
+	versions map[string][]string
+	roots    map[string]string
+}
+
+type lockfileContentDrift struct {
+	kind       lockfileContentDriftKind
+	name       string
+	constraint string
+	locked     []string
+}
+
+type lockfileContentFinding struct {
+	rule     lockfileRule
+	relDir   string
+	manifest string
+	lockfile string
+	drift    []lockfileContentDrift
+}
+
+type lockfileContentScan struct {
+	findings []string
+	warnings []string
+}
+
+func newLockfileLockedState() lockfileLockedState {
+	return lockfileLockedState{versions: make(map[string][]string)}
+}
+
+func (s *lockfileLockedState) add(name, version string) {
+	if name == "" {
+		return
+	}
+	s.versions[name] = append(s.versions[name], strings.TrimSpace(version))
+}
+
+func (s *lockfileLockedState) addRoot(name, display string) {
+	if name == "" {
+		return
+	}
+	if s.roots == nil {
+		s.roots = make(map[string]string)
+	}
+	s.roots[name] = display
+}
+
+func detectLockfileContentDrift(ctx context.Context, repoPath string, features featureflags.Set, stopOnFirst bool) (lockfileContentScan, error) {
+	if !features.Enabled(LockfileDriftContentPreviewFeature) {
+		return lockfileContentScan{}, nil
+	}
+	normalizedPath, err := normalizeRepoPathFn(repoPath)
+	if err != nil {
+		return lockfileContentScan{}, err
+	}
+	rules := activeLockfileRules(features)
+	var scan lockfileContentScan
+	walkErr := filepath.WalkDir(normalizedPath, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if current != normalizedPath && shouldSkipLockfileDir(entry.Name()) {
+			return filepath.SkipDir
+		}
+		snapshot, err := readLockfileDirSnapshot(normalizedPath, current)
+		if err != nil {
+			return err
+		}
+		findings, warnings := evaluateLockfileContentDir(snapshot, rules)
+		scan.warnings = append(scan.warnings, warnings...)
+		for _, finding := range findings {
+			scan.findings = append(scan.findings, buildLockfileContentDriftWarning(finding))
+			if stopOnFirst {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return lockfileContentScan{}, walkErr
+	}
+	return scan, nil
+}
+
+func evaluateLockfileContentDir(snapshot lockfileDirSnapshot, rules []lockfileRule) ([]lockfileContentFinding, []string) {
+	var (
+		findings []lockfileContentFinding
+		warnings []string
+	)
+	for _, rule := range rules {
+		if rule.contentParser == nil {
+			continue
+		}
+		for _, manifest := range findRuleManifests(snapshot.files, rule) {
+			if rule.contentParser.manifests != nil && !rule.contentParser.manifests(manifest) {
+				continue
+			}
+			ruleFindings, err := evaluateLockfileContentRule(snapshot, rule, manifest)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("unable to compare %s lockfile content in %s: %v", rule.manager, snapshot.relDir, err))
+				continue
+			}
+			findings = append(findings, ruleFindings...)
+		}
+	}
+	return findings, warnings
+}
+
+func evaluateLockfileContentRule(snapshot lockfileDirSnapshot, rule lockfileRule, manifest string) ([]lockfileContentFinding, error) {
+	lockfiles := findRuleLockfiles(snapshot.files, rule.lockfiles)
+	if len(lockfiles) == 0 {
+		return nil, nil
+	}
+	if rule.manifestMatcher != nil {
+		matches, err := rule.manifestMatcher(snapshot.repoPath, snapshot.path)
+		if err != nil || !matches {
+			return nil, err
+		}
+	}
+	manifestContent, err := safeio.ReadFileUnderLimit(snapshot.repoPath, filepath.Join(snapshot.path, manifest), lockfileDriftManifestReadLimit)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", manifest, err)
+	}
+	declared, err := rule.contentParser.declared(manifestContent)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", manifest, err)
+	}
+
+	findings := make([]lockfileContentFinding, 0, 1)
+	var errs []error
+	for _, lockfile := range lockfiles {
+		parseLocked, ok := rule.contentParser.locked[lockfile.name]
+		if !ok {
+			continue
+		}
+		content, err := safeio.ReadFileUnderLimit(snapshot.repoPath, filepath.Join(snapshot.path, lockfile.name), lockfileContentReadLimit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read %s: %w", lockfile.name, err))
+			continue
+		}
+		locked, err := parseLocked(content)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse %s: %w", lockfile.name, err))
+			continue
+		}
+		drift := compareLockfileContent(*rule.contentParser, declared, locked)
+		if len(drift) == 0 {
+			continue
+		}
+		findings = append(findings, lockfileContentFinding{
+			rule:     rule,
+			relDir:   snapshot.relDir,
+			manifest: manifest,
+			lockfile: lockfile.name,
+			drift:    drift,
+		})
+	}
+	return findings, errors.Join(errs...)
+}
+
+func compareLockfileContent(parser lockfileContentParser, declared []lockfileDeclaredDependency, locked lockfileLockedState) []lockfileContentDrift {
+	normalize := parser.normalize
+	if normalize == nil {
+		normalize = strings.TrimSpace
+	}
+	drift := make([]lockfileContentDrift, 0)
+	seen := make(map[string]struct{}, len(declared))
+	for _, dependency := range declared {
+		key := normalize(dependency.name)
+		if _, duplicate := seen[key]; duplicate {
+			continue
+		}
+		seen[key] = struct{}{}
+		versions, ok := locked.versions[key]
+		if !ok {
+			drift = append(drift, lockfileContentDrift{kind: lockfileContentMissing, name: dependency.name, constraint: dependency.constraint})
+			continue
+		}
+		if !lockedVersionsSatisfy(parser.style, versions, dependency.constraint) {
+			drift = append(drift, lockfileContentDrift{kind: lockfileContentUnsatisfied, name: dependency.name, constraint: dependency.constraint, locked: uniqueSortedStrings(versions)})
+		}
+	}
+	for key, display := range locked.roots {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		drift = append(drift, lockfileContentDrift{kind: lockfileContentExtra, name: display})
+	}
+	sort.SliceStable(drift, func(i, j int) bool {
+		if drift[i].kind != drift[j].kind {
+			return drift[i].kind < drift[j].kind
+		}
+		return drift[i].name < drift[j].name
+	})
+	return drift
+}
+
+// lockedVersionsSatisfy treats any locked copy satisfying the constraint as a
+// match, since lockfiles may hold several versions of one package.
+func lockedVersionsSatisfy(style lockfileConstraintStyle, versions []string, constraint string) bool {
+	if strings.TrimSpace(constraint) == "" {
+		return true
+	}
+	understood := false
+	for _, version := range versions {
+		satisfied, ok := lockfileVersionSatisfies(style, version, constraint)
+		if satisfied {
+			return true
+		}
+		understood = understood || ok
+	}
+	return !understood
+}
+
+func buildLockfileContentDriftWarning(finding lockfileContentFinding) string {
+	var missing, unsatisfied, extra []string
+	for _, drift := range finding.drift {
+		switch drift.kind {
+		case lockfileContentMissing:
+			missing = append(missing, describeDeclaredDependency(drift.name, drift.constraint))
+		case lockfileContentUnsatisfied:
+			unsatisfied = append(unsatisfied, fmt.Sprintf("%s (locked %s)", describeDeclaredDependency(drift.name, drift.constraint), strings.Join(drift.locked, ", ")))
+		case lockfileContentExtra:
+			extra = append(extra, drift.name)
+		}
+	}
+	details := make([]string, 0, 3)
+	if len(missing) > 0 {
+		details = append(details, "missing "+strings.Join(missing, ", "))
+	}
+	if len(unsatisfied) > 0 {
+		details = append(details, "unsatisfied "+strings.Join(unsatisfied, ", "))
+	}
+	if len(extra) > 0 {
+		details = append(details, "extra "+strings.Join(extra, ", "))
+	}
+	return fmt.Sprintf("%s%s in %s: %s does not match %s (%s); %s", lockfileDriftWarningPrefix, finding.rule.manager, finding.relDir, finding.lockfile, finding.manifest, strings.Join(details, "; "), finding.rule.remedy)
+}
+
+func describeDeclaredDependency(name, constraint string) string {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return name
+	}
+	return fmt.Sprintf("%s %s", name, constraint)
+}
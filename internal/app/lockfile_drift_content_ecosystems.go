@@ -0,0 +1,288 @@
+package app
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	mixDepsFunctionPattern = regexp.MustCompile(`(?m)^\s*defp?\s+deps\b`)
+	mixNextFunctionPattern = regexp.MustCompile(`(?m)^\s*defp?\s+\w`)
+	mixDepEntryPattern     = regexp.MustCompile(`\{\s*:([a-z_][A-Za-z0-9_]*)\s*(?:,\s*"([^"]*)")?([^{}]*)\}`)
+	mixLockEntryPattern    = regexp.MustCompile(`(?m)^\s*"([^"]+)"\s*:\s*\{:(\w+),\s*(.*)$`)
+	mixLockHexPattern      = regexp.MustCompile(`^:\w+,\s*"([^"]+)"`)
+	swiftPackageURLPattern = regexp.MustCompile(`\burl:\s*"([^"]+)"`)
+	swiftPackageIDPattern  = regexp.MustCompile(`\bid:\s*"([^"]+)"`)
+	swiftFromPattern       = regexp.MustCompile(`(\.upToNextMinor\(\s*)?\bfrom:\s*"([^"]+)"`)
+	swiftExactPattern      = regexp.MustCompile(`(?:\bexact:\s*|\.exact\(\s*)"([^"]+)"`)
+	swiftRangePattern      = regexp.MustCompile(`"([^"]+)"\s*\.\.([.<])\s*"([^"]+)"`)
+)
+
+// nugetLockfileContentParser compares PackageReference items in .csproj and
+// .fsproj files with packages.lock.json. NuGet marks SDK-implicit packages
+// and references inherited from Directory.Build.props as "Direct" too, so the
+// lockfile is not checked for extra entries. Directory.Packages.props only
+// pins versions for projects that reference them and is not compared.
+var nugetLockfileContentParser = &lockfileContentParser{
+	declared: parseNuGetProjectDeclarations,
+	locked:   map[string]func([]byte) (lockfileLockedState, error){"packages.lock.json": parseNuGetLockState},
+	manifests: func(manifest string) bool {
+		return manifestMatchesAnyExt(manifest, []string{dotnetCSharpProjectManifestExt, dotnetFSharpProjectManifestExt})
+	},
+	normalize: strings.ToLower,
+	style:     constraintStyleRange,
+}
+
+// mixLockfileContentParser compares the deps/0 function of mix.exs with
+// mix.lock. mix.lock holds transitive dependencies without marking direct
+// ones, so it is not checked for extra entries.
+var mixLockfileContentParser = &lockfileContentParser{
+	declared: parseMixDeclarations,
+	locked:   map[string]func([]byte) (lockfileLockedState, error){"mix.lock": parseMixLockState},
+	style:    constraintStyleMix,
+}
+
+// swiftPMLockfileContentParser compares .package dependencies in
+// Package.swift with the pins in Package.resolved (format versions 1 to 3).
+// Pins include transitive packages, so Package.resolved is not checked for
+// extra entries.
+var swiftPMLockfileContentParser = &lockfileContentParser{
+	declared:  parseSwiftPackageDeclarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"Package.resolved": parseSwiftPackageResolvedState},
+	normalize: strings.ToLower,
+	style:     constraintStyleRange,
+}
+
+func parseNuGetProjectDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var project struct {
+		ItemGroups []struct {
+			PackageReferences []struct {
+				Include         string `xml:"Include,attr"`
+				Version         string `xml:"Version,attr"`
+				VersionOverride string `xml:"VersionOverride,attr"`
+				VersionElement  string `xml:"Version"`
+			} `xml:"PackageReference"`
+		} `xml:"ItemGroup"`
+	}
+	if err := xml.Unmarshal(content, &project); err != nil {
+		return nil, err
+	}
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, group := range project.ItemGroups {
+		for _, reference := range group.PackageReferences {
+			version := firstNonEmpty(strings.TrimSpace(reference.VersionOverride), strings.TrimSpace(reference.Version), strings.TrimSpace(reference.VersionElement))
+			for _, name := range strings.Split(reference.Include, ";") {
+				if name = strings.TrimSpace(name); name != "" {
+					declared = append(declared, lockfileDeclaredDependency{name: name, constraint: nugetVersionConstraint(version)})
+				}
+			}
+		}
+	}
+	return declared, nil
+}
+
+// nugetVersionConstraint rewrites a NuGet version into comparator clauses: a
+// bare version is a minimum and interval notation ("[1.0,2.0)") bounds both
+// ends. MSBuild property references are left unconstrained.
+func nugetVersionConstraint(version string) string {
+	if version == "" || strings.Contains(version, "$(") {
+		return ""
+	}
+	if !strings.HasPrefix(version, "[") && !strings.HasPrefix(version, "(") {
+		if strings.Contains(version, "*") {
+			return version
+		}
+		return ">= " + version
+	}
+	inner := strings.TrimRight(version[1:], ")]")
+	low, high, isRange := strings.Cut(inner, ",")
+	if !isRange {
+		return "= " + strings.TrimSpace(inner)
+	}
+	lowOp, highOp := "> ", "< "
+	if version[0] == '[' {
+		lowOp = ">= "
+	}
+	if strings.HasSuffix(version, "]") {
+		highOp = "<= "
+	}
+	clauses := make([]string, 0, 2)
+	if low = strings.TrimSpace(low); low != "" {
+		clauses = append(clauses, lowOp+low)
+	}
+	if high = strings.TrimSpace(high); high != "" {
+		clauses = append(clauses, highOp+high)
+	}
+	return strings.Join(clauses, ", ")
+}
+
+func parseNuGetLockState(content []byte) (lockfileLockedState, error) {
+	var lockfile struct {
+		Dependencies map[string]map[string]struct {
+			Type     string `json:"type"`
+			Resolved string `json:"resolved"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, framework := range lockfile.Dependencies {
+		for name, pkg := range framework {
+			if pkg.Type == "Project" {
+				continue
+			}
+			state.add(strings.ToLower(name), pkg.Resolved)
+		}
+	}
+	return state, nil
+}
+
+// parseMixDeclarations skips path and umbrella dependencies, which mix.lock
+// never records.
+func parseMixDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	source := string(content)
+	start := mixDepsFunctionPattern.FindStringIndex(source)
+	if start == nil {
+		return nil, nil
+	}
+	body := source[start[1]:]
+	if next := mixNextFunctionPattern.FindStringIndex(body); next != nil {
+		body = body[:next[0]]
+	}
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, match := range mixDepEntryPattern.FindAllStringSubmatch(body, -1) {
+		if strings.Contains(match[3], "path:") || strings.Contains(match[3], "in_umbrella:") {
+			continue
+		}
+		declared = append(declared, lockfileDeclaredDependency{name: match[1], constraint: match[2]})
+	}
+	return declared, nil
+}
+
+// parseMixLockState records hex packages with their version and git
+// packages without one, since a git pin has no comparable version.
+func parseMixLockState(content []byte) (lockfileLockedState, error) {
+	state := newLockfileLockedState()
+	for _, match := range mixLockEntryPattern.FindAllStringSubmatch(string(content), -1) {
+		version := ""
+		if match[2] == "hex" {
+			if hex := mixLockHexPattern.FindStringSubmatch(match[3]); hex != nil {
+				version = hex[1]
+			}
+		}
+		state.add(match[1], version)
+	}
+	return state, nil
+}
+
+// parseSwiftPackageDeclarations reads every .package(...) call. Local path
+// packages are skipped because SwiftPM does not pin them.
+func parseSwiftPackageDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	source := string(content)
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, arguments := range swiftPackageCalls(source) {
+		name := ""
+		if match := swiftPackageURLPattern.FindStringSubmatch(arguments); match != nil {
+			name = swiftPackageIdentity(match[1])
+		} else if match := swiftPackageIDPattern.FindStringSubmatch(arguments); match != nil {
+			name = match[1]
+		}
+		if name == "" {
+			continue
+		}
+		declared = append(declared, lockfileDeclaredDependency{name: name, constraint: swiftPackageConstraint(arguments)})
+	}
+	return declared, nil
+}
+
+func swiftPackageCalls(source string) []string {
+	calls := make([]string, 0)
+	for offset := 0; ; {
+		index := strings.Index(source[offset:], ".package(")
+		if index < 0 {
+			return calls
+		}
+		start := offset + index + len(".package(")
+		depth := 1
+		end := start
+		for ; end < len(source) && depth > 0; end++ {
+			switch source[end] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+		calls = append(calls, source[start:end])
+		offset = end
+	}
+}
+
+// swiftPackageIdentity derives SwiftPM's package identity from a source
+// control URL: the last path component without a ".git" suffix.
+func swiftPackageIdentity(location string) string {
+	location = strings.TrimSuffix(strings.TrimSpace(location), "/")
+	if location == "" {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(path.Base(location), ".git"))
+}
+
+func swiftPackageConstraint(arguments string) string {
+	if match := swiftExactPattern.FindStringSubmatch(arguments); match != nil {
+		return "= " + match[1]
+	}
+	if match := swiftRangePattern.FindStringSubmatch(arguments); match != nil {
+		upper := "< "
+		if match[2] == "." {
+			upper = "<= "
+		}
+		return ">= " + match[1] + ", " + upper + match[3]
+	}
+	match := swiftFromPattern.FindStringSubmatch(arguments)
+	if match == nil {
+		return ""
+	}
+	version, ok := parseLooseVersion(match[2])
+	if !ok || version.wildcard {
+		return ""
+	}
+	if match[1] != "" {
+		return fmt.Sprintf(">= %s, < %d.%d.0", match[2], version.parts[0], version.parts[1]+1)
+	}
+	return fmt.Sprintf(">= %s, < %d.0.0", match[2], version.parts[0]+1)
+}
+
+func parseSwiftPackageResolvedState(content []byte) (lockfileLockedState, error) {
+	type swiftPin struct {
+		Identity      string `json:"identity"`
+		Location      string `json:"location"`
+		RepositoryURL string `json:"repositoryURL"`
+		State         struct {
+			Version string `json:"version"`
+		} `json:"state"`
+	}
+	var resolved struct {
+		Pins   []swiftPin `json:"pins"`
+		Object struct {
+			Pins []swiftPin `json:"pins"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(content, &resolved); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, pin := range append(resolved.Pins, resolved.Object.Pins...) {
+		identity := strings.ToLower(pin.Identity)
+		if identity == "" {
+			identity = swiftPackageIdentity(firstNonEmpty(pin.Location, pin.RepositoryURL))
+		}
+		state.add(identity, pin.State.Version)
+	}
+	return state, nil
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var npmManifestDependencySections = []string{"dependencies", "devDependencies", "optionalDependencies"}
+
+var npmLockfileContentParser = &lockfileContentParser{
+	declared: parsePackageJSONDeclarations,
+	locked: map[string]func([]byte) (lockfileLockedState, error){
+		"package-lock.json":   parsePackageLockState,
+		"npm-shrinkwrap.json": parsePackageLockState,
+		"yarn.lock":           parseYarnLockState,
+		"pnpm-lock.yaml":      parsePNPMLockState,
+	},
+	style: constraintStyleNPM,
+}
+
+func parsePackageJSONDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, section := range npmManifestDependencySections {
+		raw, ok := manifest[section]
+		if !ok {
+			continue
+		}
+		var entries map[string]string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, err
+		}
+		declared = appendDeclaredDependencyMap(declared, entries)
+	}
+	return declared, nil
+}
+
+func parsePackageLockState(content []byte) (lockfileLockedState, error) {
+	var lockfile struct {
+		Packages map[string]struct {
+			Version              string            `json:"version"`
+			Dependencies         map[string]string `json:"dependencies"`
+			DevDependencies      map[string]string `json:"devDependencies"`
+			OptionalDependencies map[string]string `json:"optionalDependencies"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	if len(lockfile.Packages) == 0 {
+		for name, entry := range lockfile.Dependencies {
+			state.add(name, entry.Version)
+		}
+		return state, nil
+	}
+	for key, entry := range lockfile.Packages {
+		if key == "" {
+			for _, deps := range []map[string]string{entry.Dependencies, entry.DevDependencies, entry.OptionalDependencies} {
+				for name := range deps {
+					state.addRoot(name, name)
+				}
+			}
+			continue
+		}
+		name, ok := strings.CutPrefix(key, "node_modules/")
+		if !ok || strings.Contains(name, "/node_modules/") {
+			continue
+		}
+		state.add(name, entry.Version)
+	}
+	return state, nil
+}
+
+// parseYarnLockState reads both classic and Berry yarn.lock entries. Each
+// header lists one or more "name@range" descriptors for a single resolution.
+func parseYarnLockState(content []byte) (lockfileLockedState, error) {
+	state := newLockfileLockedState()
+	var names []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			names = yarnDescriptorNames(strings.TrimSuffix(trimmed, ":"))
+			continue
+		}
+		if len(names) == 0 || strings.HasPrefix(line, "   ") {
+			continue
+		}
+		version, ok := strings.CutPrefix(trimmed, "version ")
+		if !ok {
+			version, ok = strings.CutPrefix(trimmed, "version:")
+		}
+		if !ok {
+			continue
+		}
+		version = strings.Trim(strings.TrimSpace(version), `"`)
+		for _, name := range names {
+			state.add(name, version)
+		}
+		names = nil
+	}
+	return state, scanner.Err()
+}
+
+func yarnDescriptorNames(header string) []string {
+	names := make([]string, 0, 1)
+	seen := make(map[string]struct{})
+	for _, descriptor := range strings.Split(header, ",") {
+		descriptor = strings.Trim(strings.TrimSpace(descriptor), `"`)
+		index := strings.LastIndex(descriptor, "@")
+		if index <= 0 {
+			continue
+		}
+		name := descriptor[:index]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parsePNPMLockState reads the root importer, which records the direct
+// dependencies of the manifest next to the lockfile.
+func parsePNPMLockState(content []byte) (lockfileLockedState, error) {
+	var document map[string]any
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return lockfileLockedState{}, err
+	}
+	importer := document
+	if importers, ok := document["importers"].(map[string]any); ok {
+		root, _ := importers["."].(map[string]any)
+		importer = root
+	}
+	state := newLockfileLockedState()
+	state.roots = make(map[string]string)
+	for _, section := range npmManifestDependencySections {
+		entries, _ := importer[section].(map[string]any)
+		for name, value := range entries {
+			state.addRoot(name, name)
+			state.add(name, pnpmLockedVersion(value))
+		}
+	}
+	return state, nil
+}
+
+func pnpmLockedVersion(value any) string {
+	version := ""
+	switch typed := value.(type) {
+	case string:
+		version = typed
+	case map[string]any:
+		version, _ = typed["version"].(string)
+	}
+	if index := strings.IndexAny(version, "(_"); index >= 0 {
+		version = version[:index]
+	}
+	if strings.HasPrefix(version, "link:") || strings.HasPrefix(version, "file:") {
+		return ""
+	}
+	return version
+}
+
+func appendDeclaredDependencyMap(declared []lockfileDeclaredDependency, entries map[string]string) []lockfileDeclaredDependency {
+	for name, constraint := range entries {
+		declared = append(declared, lockfileDeclaredDependency{name: name, constraint: constraint})
+	}
+	return declared
+}
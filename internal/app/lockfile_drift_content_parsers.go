@@ -0,0 +1,276 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	gemfileConstraintPattern = regexp.MustCompile(`^["']\s*((~>|>=|<=|!=|=|>|<)?\s*\d[^"']*)["']$`)
+	gemfileLockSpecPattern   = regexp.MustCompile(`^    ([^ (]+) \(([^)]+)\)$`)
+	gemfileLockRootPattern   = regexp.MustCompile(`^  ([^ (!]+)!?(?: \(([^)]*)\))?$`)
+)
+
+var cargoLockfileContentParser = &lockfileContentParser{
+	declared:  parseCargoTomlDeclarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"Cargo.lock": parseCargoLockState},
+	normalize: normalizeCargoName,
+	style:     constraintStyleCargo,
+}
+
+var composerLockfileContentParser = &lockfileContentParser{
+	declared:  parseComposerJSONDeclarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"composer.lock": parseComposerLockState},
+	normalize: strings.ToLower,
+	style:     constraintStyleComposer,
+}
+
+var goModulesLockfileContentParser = &lockfileContentParser{
+	declared: parseGoModDeclarations,
+	locked:   map[string]func([]byte) (lockfileLockedState, error){"go.sum": parseGoSumState},
+	style:    constraintStyleExact,
+}
+
+var bundlerLockfileContentParser = &lockfileContentParser{
+	declared: parseGemfileDeclarations,
+	locked:   map[string]func([]byte) (lockfileLockedState, error){"Gemfile.lock": parseGemfileLockState},
+	style:    constraintStyleRubyGems,
+}
+
+var pubLockfileContentParser = &lockfileContentParser{
+	declared: parsePubspecDeclarations,
+	locked:   map[string]func([]byte) (lockfileLockedState, error){"pubspec.lock": parsePubspecLockState},
+	style:    constraintStylePub,
+}
+
+func parseCargoTomlDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var document map[string]any
+	if err := toml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	tables := cargoDependencyTables(document)
+	for _, target := range tomlTable(document, "target") {
+		targetTable, _ := target.(map[string]any)
+		tables = append(tables, cargoDependencyTables(targetTable)...)
+	}
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, table := range tables {
+		for key, value := range table {
+			name, constraint := key, ""
+			switch typed := value.(type) {
+			case string:
+				constraint = typed
+			case map[string]any:
+				constraint, _ = typed["version"].(string)
+				if renamed, ok := typed["package"].(string); ok {
+					name = renamed
+				}
+			}
+			declared = append(declared, lockfileDeclaredDependency{name: name, constraint: constraint})
+		}
+	}
+	return declared, nil
+}
+
+func cargoDependencyTables(document map[string]any) []map[string]any {
+	return []map[string]any{
+		tomlTable(document, "dependencies"),
+		tomlTable(document, cargoDevDependenciesSection),
+		tomlTable(document, "build-dependencies"),
+	}
+}
+
+func parseCargoLockState(content []byte) (lockfileLockedState, error) {
+	var lockfile struct {
+		Package []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, pkg := range lockfile.Package {
+		state.add(normalizeCargoName(pkg.Name), pkg.Version)
+	}
+	return state, nil
+}
+
+func parseComposerJSONDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var manifest struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	declared := make([]lockfileDeclaredDependency, 0, len(manifest.Require)+len(manifest.RequireDev))
+	for _, entries := range []map[string]string{manifest.Require, manifest.RequireDev} {
+		for name, constraint := range entries {
+			// Platform requirements (php, ext-*, lib-*) never appear in composer.lock.
+			if !strings.Contains(name, "/") {
+				continue
+			}
+			declared = append(declared, lockfileDeclaredDependency{name: name, constraint: constraint})
+		}
+	}
+	return declared, nil
+}
+
+func parseComposerLockState(content []byte) (lockfileLockedState, error) {
+	type composerLockPackage struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	var lockfile struct {
+		Packages    []composerLockPackage `json:"packages"`
+		PackagesDev []composerLockPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, packages := range [][]composerLockPackage{lockfile.Packages, lockfile.PackagesDev} {
+		for _, pkg := range packages {
+			state.add(strings.ToLower(pkg.Name), pkg.Version)
+		}
+	}
+	return state, nil
+}
+
+// parseGoModDeclarations skips replaced modules: go.sum records checksums for
+// the replacement, or nothing at all for local directory replacements.
+func parseGoModDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	file, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, err
+	}
+	replaced := make(map[string]struct{}, len(file.Replace))
+	for _, replace := range file.Replace {
+		replaced[replace.Old.Path] = struct{}{}
+	}
+	declared := make([]lockfileDeclaredDependency, 0, len(file.Require))
+	for _, require := range file.Require {
+		if _, ok := replaced[require.Mod.Path]; ok {
+			continue
+		}
+		declared = append(declared, lockfileDeclaredDependency{name: require.Mod.Path, constraint: require.Mod.Version})
+	}
+	return declared, nil
+}
+
+func parseGoSumState(content []byte) (lockfileLockedState, error) {
+	state := newLockfileLockedState()
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		state.add(fields[0], strings.TrimSuffix(fields[1], "/go.mod"))
+	}
+	return state, scanner.Err()
+}
+
+func parseGemfileDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		match := gemfileEntryPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		constraints := make([]string, 0, 1)
+		arguments := strings.Split(strings.TrimSpace(line[len(match[0]):]), ",")
+		for _, argument := range arguments[1:] {
+			if constraint := gemfileConstraintPattern.FindStringSubmatch(strings.TrimSpace(argument)); constraint != nil {
+				constraints = append(constraints, strings.TrimSpace(constraint[1]))
+			}
+		}
+		declared = append(declared, lockfileDeclaredDependency{name: match[1], constraint: strings.Join(constraints, ", ")})
+	}
+	return declared, nil
+}
+
+// parseGemfileLockState reads resolved specs from every source section and
+// the DEPENDENCIES section as the direct dependencies. A local PATH source
+// means the Gemfile loads a gemspec whose dependencies are not visible in the
+// Gemfile, so those lockfiles are not compared for extra entries.
+func parseGemfileLockState(content []byte) (lockfileLockedState, error) {
+	state := newLockfileLockedState()
+	state.roots = make(map[string]string)
+	section := ""
+	gemspec := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if line != "" && !strings.HasPrefix(line, " ") {
+			section = strings.TrimSpace(line)
+			continue
+		}
+		if section == "PATH" && strings.TrimSpace(line) == "remote: ." {
+			gemspec = true
+		}
+		if section == "DEPENDENCIES" {
+			if match := gemfileLockRootPattern.FindStringSubmatch(line); match != nil {
+				state.addRoot(match[1], match[1])
+			}
+			continue
+		}
+		if match := gemfileLockSpecPattern.FindStringSubmatch(line); match != nil {
+			version, _, _ := strings.Cut(match[2], "-")
+			state.add(match[1], version)
+		}
+	}
+	if gemspec {
+		state.roots = nil
+	}
+	return state, nil
+}
+
+func parsePubspecDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var manifest struct {
+		Dependencies    map[string]any `yaml:"dependencies"`
+		DevDependencies map[string]any `yaml:"dev_dependencies"`
+	}
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	declared := make([]lockfileDeclaredDependency, 0, len(manifest.Dependencies)+len(manifest.DevDependencies))
+	for _, entries := range []map[string]any{manifest.Dependencies, manifest.DevDependencies} {
+		for name, value := range entries {
+			if source, ok := value.(map[string]any); ok && source["sdk"] != nil {
+				continue
+			}
+			declared = append(declared, lockfileDeclaredDependency{name: name, constraint: tableVersionConstraint(value)})
+		}
+	}
+	return declared, nil
+}
+
+func parsePubspecLockState(content []byte) (lockfileLockedState, error) {
+	var lockfile struct {
+		Packages map[string]struct {
+			Dependency string `yaml:"dependency"`
+			Source     string `yaml:"source"`
+			Version    string `yaml:"version"`
+		} `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	state.roots = make(map[string]string)
+	for name, pkg := range lockfile.Packages {
+		state.add(name, pkg.Version)
+		if (pkg.Dependency == "direct main" || pkg.Dependency == "direct dev") && pkg.Source != "sdk" {
+			state.addRoot(name, name)
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+var pipenvLockfileContentParser = &lockfileContentParser{
+	declared:  parsePipfileDeclarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"Pipfile.lock": parsePipfileLockState},
+	normalize: normalizePythonName,
+	style:     constraintStylePEP440,
+}
+
+var poetryLockfileContentParser = &lockfileContentParser{
+	declared:  parsePoetryDeclarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"poetry.lock": parsePythonPackageLockState},
+	normalize: normalizePythonName,
+	style:     constraintStylePoetry,
+}
+
+var uvLockfileContentParser = &lockfileContentParser{
+	declared:  parsePEP621Declarations,
+	locked:    map[string]func([]byte) (lockfileLockedState, error){"uv.lock": parsePythonPackageLockState},
+	normalize: normalizePythonName,
+	style:     constraintStylePEP440,
+}
+
+func parsePipfileDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var document map[string]any
+	if err := toml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	declared := make([]lockfileDeclaredDependency, 0)
+	for _, section := range []string{"packages", "dev-packages"} {
+		entries, _ := document[section].(map[string]any)
+		for name, value := range entries {
+			declared = append(declared, lockfileDeclaredDependency{name: name, constraint: tableVersionConstraint(value)})
+		}
+	}
+	return declared, nil
+}
+
+func parsePipfileLockState(content []byte) (lockfileLockedState, error) {
+	type pipfileLockEntry struct {
+		Version string `json:"version"`
+	}
+	var lockfile struct {
+		Default map[string]pipfileLockEntry `json:"default"`
+		Develop map[string]pipfileLockEntry `json:"develop"`
+	}
+	if err := json.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, entries := range []map[string]pipfileLockEntry{lockfile.Default, lockfile.Develop} {
+		for name, entry := range entries {
+			state.add(normalizePythonName(name), strings.TrimPrefix(entry.Version, "=="))
+		}
+	}
+	return state, nil
+}
+
+func parsePoetryDeclarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var document map[string]any
+	if err := toml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	declared := pep621DeclarationsFromDocument(document, false)
+	poetry := tomlTable(tomlTable(document, "tool"), "poetry")
+	tables := []map[string]any{tomlTable(poetry, "dependencies"), tomlTable(poetry, "dev-dependencies")}
+	for _, group := range tomlTable(poetry, "group") {
+		groupTable, _ := group.(map[string]any)
+		tables = append(tables, tomlTable(groupTable, "dependencies"))
+	}
+	for _, table := range tables {
+		for name, value := range table {
+			if strings.EqualFold(name, "python") {
+				continue
+			}
+			declared = append(declared, lockfileDeclaredDependency{name: name, constraint: tableVersionConstraint(value)})
+		}
+	}
+	return declared, nil
+}
+
+func parsePEP621Declarations(content []byte) ([]lockfileDeclaredDependency, error) {
+	var document map[string]any
+	if err := toml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	return pep621DeclarationsFromDocument(document, true), nil
+}
+
+func pep621DeclarationsFromDocument(document map[string]any, includeGroups bool) []lockfileDeclaredDependency {
+	project := tomlTable(document, "project")
+	requirements := tomlStrings(project["dependencies"])
+	for _, extra := range tomlTable(project, "optional-dependencies") {
+		requirements = append(requirements, tomlStrings(extra)...)
+	}
+	if includeGroups {
+		for _, group := range tomlTable(document, "dependency-groups") {
+			requirements = append(requirements, tomlStrings(group)...)
+		}
+		requirements = append(requirements, tomlStrings(tomlTable(tomlTable(document, "tool"), "uv")["dev-dependencies"])...)
+	}
+	declared := make([]lockfileDeclaredDependency, 0, len(requirements))
+	for _, requirement := range requirements {
+		name := pythonRequirementName(requirement)
+		if name == "" {
+			continue
+		}
+		declared = append(declared, lockfileDeclaredDependency{name: name, constraint: pep508Specifier(requirement, name)})
+	}
+	return declared
+}
+
+// pep508Specifier returns the version specifier of a PEP 508 requirement,
+// dropping extras and environment markers. Direct URL references have none.
+func pep508Specifier(requirement, name string) string {
+	rest := strings.TrimSpace(requirement)[len(name):]
+	if marker := strings.Index(rest, ";"); marker >= 0 {
+		rest = rest[:marker]
+	}
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.Index(rest, "]"); end >= 0 {
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+	}
+	if strings.HasPrefix(rest, "@") {
+		return ""
+	}
+	return strings.TrimSpace(strings.Trim(rest, "()"))
+}
+
+// parsePythonPackageLockState reads the [[package]] tables shared by
+// poetry.lock and uv.lock. uv marks the project itself with an editable or
+// virtual source, whose dependency lists are the direct dependencies.
+func parsePythonPackageLockState(content []byte) (lockfileLockedState, error) {
+	var lockfile struct {
+		Package []struct {
+			Name                 string         `toml:"name"`
+			Version              string         `toml:"version"`
+			Source               map[string]any `toml:"source"`
+			Dependencies         any            `toml:"dependencies"`
+			OptionalDependencies map[string]any `toml:"optional-dependencies"`
+			DevDependencies      map[string]any `toml:"dev-dependencies"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(content, &lockfile); err != nil {
+		return lockfileLockedState{}, err
+	}
+	state := newLockfileLockedState()
+	for _, pkg := range lockfile.Package {
+		if !isUVProjectSource(pkg.Source) {
+			state.add(normalizePythonName(pkg.Name), pkg.Version)
+			continue
+		}
+		if state.roots == nil {
+			state.roots = make(map[string]string)
+		}
+		groups := []any{pkg.Dependencies}
+		for _, deps := range pkg.OptionalDependencies {
+			groups = append(groups, deps)
+		}
+		for _, deps := range pkg.DevDependencies {
+			groups = append(groups, deps)
+		}
+		for _, deps := range groups {
+			items, _ := deps.([]any)
+			for _, item := range items {
+				dep, _ := item.(map[string]any)
+				name, _ := dep["name"].(string)
+				state.addRoot(normalizePythonName(name), name)
+			}
+		}
+	}
+	return state, nil
+}
+
+func isUVProjectSource(source map[string]any) bool {
+	for _, key := range []string{"editable", "virtual"} {
+		if value, _ := source[key].(string); value == "." {
+			return true
+		}
+	}
+	return false
+}
+
+func tableVersionConstraint(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case map[string]any:
+		version, _ := typed["version"].(string)
+		return version
+	default:
+		return ""
+	}
+}
+
+func tomlTable(document map[string]any, key string) map[string]any {
+	table, _ := document[key].(map[string]any)
+	return table
+}
+
+func tomlStrings(value any) []string {
+	items, _ := value.([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			values = append(values, text)
+		}
+	}
+	return values
+}
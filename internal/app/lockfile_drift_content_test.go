@@ -0,0 +1,349 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+)
+
+func mustLockfileContentFeatureSet(t *testing.T) featureflags.Set {
+	t.Helper()
+	return mustResolveAppTestFeatures(t, LockfileDriftContentPreviewFeature)
+}
+
+func TestLockfileVersionSatisfies(t *testing.T) {
+	cases := []struct {
+		style      lockfileConstraintStyle
+		locked     string
+		constraint string
+		satisfied  bool
+		understood bool
+	}{
+		{constraintStyleNPM, "4.17.21", "^4.17.0", true, true},
+		{constraintStyleNPM, "5.0.0", "^4.17.0", false, true},
+		{constraintStyleNPM, "0.2.9", "^0.2.3", true, true},
+		{constraintStyleNPM, "0.3.0", "^0.2.3", false, true},
+		{constraintStyleNPM, "1.2.9", "~1.2.3", true, true},
+		{constraintStyleNPM, "1.3.0", "~1.2.3", false, true},
+		{constraintStyleNPM, "2.4.0", "1.x || >=2.3.0 <3", true, true},
+		{constraintStyleNPM, "1.5.0", "1.2.3 - 1.4", false, true},
+		{constraintStyleNPM, "1.4.7", "1.2.3 - 1.4", true, true},
+		{constraintStyleNPM, "1.2.9", "<=1.2", true, true},
+		{constraintStyleNPM, "1.2.9", ">1.2", false, true},
+		{constraintStyleNPM, "1.0.0-beta.10", ">=1.0.0-beta.2", true, true},
+		{constraintStyleNPM, "1.0.0-beta.2", ">=1.0.0-beta.10", false, true},
+		{constraintStyleNPM, "1.0.0-beta", "<1.0.0-beta.1", true, true},
+		{constraintStyleNPM, "1.0.0-1", "<1.0.0-alpha", true, true},
+		{constraintStyleNPM, "1.0.0", "latest", false, false},
+		{constraintStyleNPM, "1.0.0", "github:user/repo", false, false},
+		{constraintStyleCargo, "1.0.203", "1.0", true, true},
+		{constraintStyleCargo, "1.37.0", "1.38", false, true},
+		{constraintStyleCargo, "0.7.4", ">=0.7, <0.8", true, true},
+		{constraintStyleCargo, "0.7.4", "<=0.7", true, true},
+		{constraintStyleComposer, "v2.9.1", "~2.8", true, true},
+		{constraintStyleComposer, "3.0.0", "^2.0 || ^1.0@dev", false, true},
+		{constraintStyleComposer, "dev-main", "dev-main", false, false},
+		{constraintStylePEP440, "2.31.0", ">=2.30,<3", true, true},
+		{constraintStylePEP440, "1.4.2", "~=1.4.0", true, true},
+		{constraintStylePEP440, "1.5.0", "~=1.4.0", false, true},
+		{constraintStylePEP440, "1.2.0", "==1.2", true, true},
+		{constraintStylePEP440, "2.0.0rc1", ">=2.0", false, true},
+		{constraintStylePEP440, "1.2.1", "<=1.2", false, true},
+		{constraintStylePEP440, "1.2.0", "<=1.2", true, true},
+		{constraintStylePEP440, "1.2.1", ">1.2", true, true},
+		{constraintStylePEP440, "2.0.0rc10", ">=2.0.0rc2", true, true},
+		{constraintStylePEP440, "1.0.0.dev1", "<1.0", true, true},
+		{constraintStylePEP440, "1.0.0.dev1", ">=1.0", false, true},
+		{constraintStylePEP440, "1.0.post1", ">1.0", true, true},
+		{constraintStylePEP440, "1.0.post1", "==1.0", false, true},
+		{constraintStylePEP440, "1!1.0", ">=2.0", true, true},
+		{constraintStylePEP440, "2.0", ">=1!1.0", false, true},
+		{constraintStylePEP440, "1.2.3.4", "==1.2.3", false, true},
+		{constraintStylePEP440, "1.2.3.4", ">1.2.3", true, true},
+		{constraintStylePoetry, "1.0.0.dev1", "<1.0", true, true},
+		{constraintStyleRubyGems, "1.2.3.4", "!= 1.2.3", true, true},
+		{constraintStyleRubyGems, "1.2.3.4", "<= 1.2.3", false, true},
+		{constraintStyleRubyGems, "1.0.0.pre", "< 1.0.0", true, true},
+		{constraintStyleRange, "4.0.0.1", "= 4.0.0", false, true},
+		{constraintStyleRubyGems, "7.1.3", "~> 7.1", true, true},
+		{constraintStyleRubyGems, "8.0.0", "~> 7.1", false, true},
+		{constraintStyleRubyGems, "1.4.9", "~> 1.4.2, != 1.4.5", true, true},
+		{constraintStylePub, "1.9.0", ">=1.2.0 <2.0.0", true, true},
+		{constraintStylePub, "2.0.0", "^1.2.0", false, true},
+		{constraintStylePub, "2.0.0", "any", true, true},
+		{constraintStyleMix, "1.4.1", "~> 1.2", true, true},
+		{constraintStyleMix, "2.1.0", "~> 1.0 or ~> 2.0", true, true},
+		{constraintStyleMix, "1.3.0", "~> 1.2.3", false, true},
+		{constraintStyleMix, "0.9.0", ">= 0.8.0 and < 0.9.0", false, true},
+		{constraintStyleRange, "13.0.3", ">= 13.0.1", true, true},
+		{constraintStyleRange, "2.0.0", ">= 1.0, < 2.0", false, true},
+		{constraintStyleRange, "1.2.3", "= 1.2.3", true, true},
+		{constraintStyleExact, "v0.7.0", "v0.7.0", true, true},
+		{constraintStyleExact, "v0.6.0", "v0.7.0", false, true},
+	}
+	for _, tc := range cases {
+		satisfied, understood := lockfileVersionSatisfies(tc.style, tc.locked, tc.constraint)
+		if satisfied != tc.satisfied || understood != tc.understood {
+			t.Errorf("style %d: %s against %q = (%v, %v), want (%v, %v)", tc.style, tc.locked, tc.constraint, satisfied, understood, tc.satisfied, tc.understood)
+		}
+	}
+}
+
+func compareLockfileContentForTest(t *testing.T, parser *lockfileContentParser, lockfileName, manifest, lockfile string) []lockfileContentDrift {
+	t.Helper()
+	declared, err := parser.declared([]byte(manifest))
+	if err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+	locked, err := parser.locked[lockfileName]([]byte(lockfile))
+	if err != nil {
+		t.Fatalf("parse %s: %v", lockfileName, err)
+	}
+	return compareLockfileContent(*parser, declared, locked)
+}
+
+func assertLockfileContentDrift(t *testing.T, drift []lockfileContentDrift, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(drift))
+	for _, entry := range drift {
+		label := map[lockfileContentDriftKind]string{
+			lockfileContentMissing:     "missing",
+			lockfileContentUnsatisfied: "unsatisfied",
+			lockfileContentExtra:       "extra",
+		}[entry.kind]
+		got = append(got, label+":"+entry.name)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected drift entries: got %v, want %v", got, want)
+	}
+}
+
+func TestNPMLockfileContentDrift(t *testing.T) {
+	manifest := `{"dependencies": {"left-pad": "^1.3.0", "react": "^18.2.0", "local": "file:../local"}, "devDependencies": {"vitest": "^1.0.0"}}`
+	packageLock := `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"react": "^17.0.0", "moment": "^2.0.0", "local": "file:../local"}, "devDependencies": {"vitest": "^1.0.0"}},
+    "node_modules/react": {"version": "17.0.2"},
+    "node_modules/moment": {"version": "2.29.4"},
+    "node_modules/local": {"resolved": "../local", "link": true},
+    "node_modules/vitest": {"version": "1.6.0"},
+    "node_modules/vitest/node_modules/left-pad": {"version": "1.3.0"}
+  }
+}`
+	drift := compareLockfileContentForTest(t, npmLockfileContentParser, "package-lock.json", manifest, packageLock)
+	assertLockfileContentDrift(t, drift, "missing:left-pad", "unsatisfied:react", "extra:moment")
+	if strings.Join(drift[1].locked, ",") != "17.0.2" {
+		t.Fatalf("expected locked version detail, got %#v", drift[1])
+	}
+
+	yarnLock := "# yarn lockfile v1\n\n\"react@^18.2.0\", react@^18.0.0:\n  version \"18.3.1\"\n  dependencies:\n    version-utils \"^1.0.0\"\n\nvitest@^1.0.0:\n  version \"1.6.0\"\n\n\"@scope/pkg@npm:^2.0.0\":\n  version: 2.1.0\n"
+	drift = compareLockfileContentForTest(t, npmLockfileContentParser, "yarn.lock", manifest, yarnLock)
+	assertLockfileContentDrift(t, drift, "missing:left-pad", "missing:local")
+
+	pnpmLock := "lockfileVersion: '9.0'\nimporters:\n  .:\n    dependencies:\n      left-pad:\n        specifier: ^1.3.0\n        version: 1.3.0\n      react:\n        specifier: ^18.2.0\n        version: 18.3.1(react-dom@18.3.1)\n      local:\n        specifier: file:../local\n        version: link:../local\n    devDependencies:\n      vitest:\n        specifier: ^0.34.0\n        version: 0.34.6\n"
+	drift = compareLockfileContentForTest(t, npmLockfileContentParser, "pnpm-lock.yaml", manifest, pnpmLock)
+	assertLockfileContentDrift(t, drift, "unsatisfied:vitest")
+}
+
+func TestCargoAndComposerLockfileContentDrift(t *testing.T) {
+	cargoManifest := "[package]\nname = \"demo\"\n\n[dependencies]\nserde = { version = \"1\", features = [\"derive\"] }\ntokio = \"1.38\"\nutil = { package = \"tokio_util\", version = \"0.7\" }\n\n[target.'cfg(unix)'.dependencies]\nlibc = \"0.2\"\n"
+	cargoLock := "version = 3\n\n[[package]]\nname = \"demo\"\nversion = \"0.1.0\"\n\n[[package]]\nname = \"serde\"\nversion = \"1.0.203\"\n\n[[package]]\nname = \"tokio\"\nversion = \"1.37.0\"\n\n[[package]]\nname = \"tokio-util\"\nversion = \"0.7.11\"\n"
+	drift := compareLockfileContentForTest(t, cargoLockfileContentParser, "Cargo.lock", cargoManifest, cargoLock)
+	assertLockfileContentDrift(t, drift, "missing:libc", "unsatisfied:tokio")
+
+	composerManifest := `{"require": {"php": ">=8.2", "ext-json": "*", "Monolog/Monolog": "^3.0", "symfony/console": "~6.4"}, "require-dev": {"phpunit/phpunit": "^11.0"}}`
+	composerLock := `{"packages": [{"name": "monolog/monolog", "version": "3.6.0"}, {"name": "symfony/console", "version": "v7.0.1"}], "packages-dev": []}`
+	drift = compareLockfileContentForTest(t, composerLockfileContentParser, "composer.lock", composerManifest, composerLock)
+	assertLockfileContentDrift(t, drift, "missing:phpunit/phpunit", "unsatisfied:symfony/console")
+}
+
+func TestGoModulesAndBundlerLockfileContentDrift(t *testing.T) {
+	goMod := "module example.com/demo\n\ngo 1.22\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/sync v0.7.0\n\texample.com/local v0.0.0\n)\n\nrequire github.com/google/uuid v1.6.0\n\nreplace example.com/local => ../local\n"
+	goSum := "github.com/pkg/errors v0.9.1 h1:abc=\ngithub.com/pkg/errors v0.9.1/go.mod h1:def=\ngolang.org/x/sync v0.6.0/go.mod h1:ghi=\n"
+	drift := compareLockfileContentForTest(t, goModulesLockfileContentParser, "go.sum", goMod, goSum)
+	assertLockfileContentDrift(t, drift, "missing:github.com/google/uuid", "unsatisfied:golang.org/x/sync")
+
+	gemfile := "source \"https://rubygems.org\"\n\ngem \"rails\", \"~> 7.1\", require: false\ngem 'pry'\ngem \"nokogiri\", \">= 1.16\"\n"
+	gemfileLock := "GEM\n  remote: https://rubygems.org/\n  specs:\n    nokogiri (1.15.4-x86_64-linux)\n      racc (~> 1.4)\n    rails (7.1.3)\n    racc (1.7.3)\n\nPLATFORMS\n  x86_64-linux\n\nDEPENDENCIES\n  nokogiri (>= 1.15)\n  rails (~> 7.1)\n  sidekiq\n\nBUNDLED WITH\n   2.5.6\n"
+	drift = compareLockfileContentForTest(t, bundlerLockfileContentParser, "Gemfile.lock", gemfile, gemfileLock)
+	assertLockfileContentDrift(t, drift, "missing:pry", "unsatisfied:nokogiri", "extra:sidekiq")
+
+	gemspecLock := "PATH\n  remote: .\n  specs:\n    demo (0.1.0)\n\nGEM\n  specs:\n    rails (7.1.3)\n    pry (0.14.2)\n    nokogiri (1.16.0)\n\nDEPENDENCIES\n  demo!\n  rails\n"
+	drift = compareLockfileContentForTest(t, bundlerLockfileContentParser, "Gemfile.lock", gemfile, gemspecLock)
+	assertLockfileContentDrift(t, drift)
+}
+
+func TestPythonLockfileContentDrift(t *testing.T) {
+	pipfile := "[packages]\nrequests = \">=2.31\"\nflask = {version = \"~=3.0.0\"}\n\n[dev-packages]\npytest = \"*\"\n"
+	pipfileLock := `{"default": {"requests": {"version": "==2.32.3"}, "flask": {"version": "==2.3.3"}}, "develop": {}}`
+	drift := compareLockfileContentForTest(t, pipenvLockfileContentParser, "Pipfile.lock", pipfile, pipfileLock)
+	assertLockfileContentDrift(t, drift, "missing:pytest", "unsatisfied:flask")
+
+	poetry := "[tool.poetry.dependencies]\npython = \"^3.11\"\nRequests = \"^2.31\"\n\n[tool.poetry.group.dev.dependencies]\npytest = { version = \"^8.0\" }\n"
+	poetryLock := "[[package]]\nname = \"requests\"\nversion = \"2.32.3\"\n\n[package.dependencies]\nurllib3 = \">=1.21.1,<3\"\n\n[[package]]\nname = \"pytest\"\nversion = \"7.4.4\"\n"
+	drift = compareLockfileContentForTest(t, poetryLockfileContentParser, "poetry.lock", poetry, poetryLock)
+	assertLockfileContentDrift(t, drift, "unsatisfied:pytest")
+
+	uvProject := "[project]\nname = \"demo\"\ndependencies = [\"httpx[http2]>=0.27 ; python_version >= '3.10'\", \"rich\"]\n\n[dependency-groups]\ndev = [\"pytest>=8\", {include-group = \"lint\"}]\n"
+	uvLock := "version = 1\n\n[[package]]\nname = \"demo\"\nversion = \"0.1.0\"\nsource = { editable = \".\" }\ndependencies = [{ name = \"httpx\", extra = [\"http2\"] }, { name = \"click\" }]\n\n[package.dev-dependencies]\ndev = [{ name = \"pytest\" }]\n\n[[package]]\nname = \"httpx\"\nversion = \"0.26.0\"\n\n[[package]]\nname = \"pytest\"\nversion = \"8.2.0\"\n\n[[package]]\nname = \"click\"\nversion = \"8.1.7\"\n"
+	drift = compareLockfileContentForTest(t, uvLockfileContentParser, "uv.lock", uvProject, uvLock)
+	assertLockfileContentDrift(t, drift, "missing:rich", "unsatisfied:httpx", "extra:click")
+}
+
+func TestPubLockfileContentDrift(t *testing.T) {
+	pubspec := "name: demo\ndependencies:\n  flutter:\n    sdk: flutter\n  http: ^1.2.0\n  collection:\n    version: \">=1.17.0 <2.0.0\"\ndev_dependencies:\n  lints: ^3.0.0\n"
+	pubLock := "packages:\n  flutter:\n    dependency: \"direct main\"\n    source: sdk\n    version: \"0.0.0\"\n  http:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"0.13.6\"\n  collection:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"1.18.0\"\n  path:\n    dependency: \"direct main\"\n    source: hosted\n    version: \"1.9.0\"\n  meta:\n    dependency: transitive\n    source: hosted\n    version: \"1.11.0\"\n"
+	drift := compareLockfileContentForTest(t, pubLockfileContentParser, "pubspec.lock", pubspec, pubLock)
+	assertLockfileContentDrift(t, drift, "missing:lints", "unsatisfied:http", "extra:path")
+}
+
+func TestNuGetLockfileContentDrift(t *testing.T) {
+	project := `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageReference Include="Serilog" Version="[3.0,4.0)" />
+    <PackageReference Include="Polly"><Version>8.*</Version></PackageReference>
+  </ItemGroup>
+  <ItemGroup Condition="'$(TargetFramework)' == 'net8.0'">
+    <PackageReference Include="Dapper" Version="$(DapperVersion)" />
+    <PackageReference Include="xunit" Version="2.9.0" />
+  </ItemGroup>
+  <ItemGroup>
+    <ProjectReference Include="../Lib/Lib.csproj" />
+  </ItemGroup>
+</Project>`
+	packagesLock := `{
+  "version": 1,
+  "dependencies": {
+    "net8.0": {
+      "newtonsoft.json": {"type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.3"},
+      "Serilog": {"type": "Direct", "requested": "[3.0.0, 4.0.0)", "resolved": "4.0.1"},
+      "Polly": {"type": "Direct", "requested": "[8.*, )", "resolved": "8.4.1"},
+      "Dapper": {"type": "Direct", "requested": "[2.1.35, )", "resolved": "2.1.35"},
+      "Microsoft.NET.ILLink.Tasks": {"type": "Direct", "requested": "[8.0.8, )", "resolved": "8.0.8"},
+      "lib": {"type": "Project"}
+    }
+  }
+}`
+	drift := compareLockfileContentForTest(t, nugetLockfileContentParser, "packages.lock.json", project, packagesLock)
+	assertLockfileContentDrift(t, drift, "missing:xunit", "unsatisfied:Serilog")
+	if nugetLockfileContentParser.manifests("Directory.Packages.props") || !nugetLockfileContentParser.manifests("App.fsproj") {
+		t.Fatalf("expected only project files to be compared with packages.lock.json")
+	}
+}
+
+func TestMixLockfileContentDrift(t *testing.T) {
+	mixExs := `defmodule Demo.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :demo, deps: deps()]
+  end
+
+  defp deps do
+    [
+      {:phoenix, "~> 1.7"},
+      {:jason, "~> 1.4"},
+      {:plug_cowboy, ">= 2.6.0 and < 2.7.0", only: :prod},
+      {:ecto, git: "https://github.com/elixir-ecto/ecto.git", tag: "v3.11.0"},
+      {:shared, path: "../shared"},
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false}
+    ]
+  end
+
+  defp aliases, do: [{:setup, "deps.get"}]
+end
+`
+	mixLock := `%{
+  "ecto": {:git, "https://github.com/elixir-ecto/ecto.git", "2a1b3c", [tag: "v3.11.0"]},
+  "jason": {:hex, :jason, "1.3.0", "fa6b82a934feb176263ad2df0dbd91bf633d4a46ebfdffea0c8ae82953714946", [:mix], [], "hexpm", "53fc1f51255390e0ec7e50f9cb41e751c260d065dcba2bf0d08dc51a4002c2ac"},
+  "phoenix": {:hex, :phoenix, "1.7.14", "a7d0b3f1bc95987044ddada111e77bd7f75646a08518942c72a8440278ae7825", [:mix], [{:jason, "~> 1.0", [hex: :jason, repo: "hexpm", optional: true]}], "hexpm", "c7859bc56cc5dfef19ecfc240775dae358cbaa530231118a9e014df392ace61a"},
+  "plug_cowboy": {:hex, :plug_cowboy, "2.6.2", "753611b23b29231fb916b0cdd96028084b12aff57bfd7b71781bd04b1dbeb5c9", [:mix], [], "hexpm", "951ed2433df22f4c97b85fdb145d4cee561f36b74854d64c06d896d7cd2921a7"},
+}
+`
+	drift := compareLockfileContentForTest(t, mixLockfileContentParser, "mix.lock", mixExs, mixLock)
+	assertLockfileContentDrift(t, drift, "missing:credo", "unsatisfied:jason")
+}
+
+func TestSwiftPMLockfileContentDrift(t *testing.T) {
+	packageSwift := `// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "Demo",
+    dependencies: [
+        .package(url: "https://github.com/Alamofire/Alamofire.git", from: "5.8.0"),
+        .package(url: "https://github.com/apple/swift-collections", .upToNextMinor(from: "1.0.4")),
+        .package(url: "https://github.com/pointfreeco/swift-snapshot-testing.git", exact: "1.15.0"),
+        .package(url: "https://github.com/apple/swift-argument-parser", "1.2.0"..<"2.0.0"),
+        .package(url: "https://github.com/vapor/vapor.git", branch: "main"),
+        .package(path: "../LocalKit"),
+    ],
+    targets: [.target(name: "Demo", dependencies: [.product(name: "Alamofire", package: "Alamofire")])]
+)
+`
+	resolvedV2 := `{
+  "pins": [
+    {"identity": "alamofire", "kind": "remoteSourceControl", "location": "https://github.com/Alamofire/Alamofire.git", "state": {"revision": "f455c29", "version": "5.9.1"}},
+    {"identity": "swift-collections", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-collections", "state": {"revision": "94cf62b", "version": "1.1.0"}},
+    {"identity": "swift-argument-parser", "kind": "remoteSourceControl", "location": "https://github.com/apple/swift-argument-parser", "state": {"revision": "46989693", "version": "1.3.1"}},
+    {"identity": "vapor", "kind": "remoteSourceControl", "location": "https://github.com/vapor/vapor.git", "state": {"branch": "main", "revision": "a1b2c3"}}
+  ],
+  "version": 2
+}`
+	drift := compareLockfileContentForTest(t, swiftPMLockfileContentParser, "Package.resolved", packageSwift, resolvedV2)
+	assertLockfileContentDrift(t, drift, "missing:swift-snapshot-testing", "unsatisfied:swift-collections")
+
+	resolvedV1 := `{"object": {"pins": [
+    {"package": "Alamofire", "repositoryURL": "https://github.com/Alamofire/Alamofire.git", "state": {"branch": null, "revision": "f455c29", "version": "6.0.0"}},
+    {"package": "swift-collections", "repositoryURL": "https://github.com/apple/swift-collections", "state": {"branch": null, "revision": "94cf62b", "version": "1.0.6"}},
+    {"package": "SnapshotTesting", "repositoryURL": "https://github.com/pointfreeco/swift-snapshot-testing.git", "state": {"branch": null, "revision": "5b0c434", "version": "1.15.0"}},
+    {"package": "swift-argument-parser", "repositoryURL": "https://github.com/apple/swift-argument-parser", "state": {"branch": null, "revision": "46989693", "version": "1.3.1"}},
+    {"package": "vapor", "repositoryURL": "https://github.com/vapor/vapor.git", "state": {"branch": "main", "revision": "a1b2c3", "version": null}}
+  ]}, "version": 1}`
+	drift = compareLockfileContentForTest(t, swiftPMLockfileContentParser, "Package.resolved", packageSwift, resolvedV1)
+	assertLockfileContentDrift(t, drift, "unsatisfied:alamofire")
+}
+
+func TestEvaluateLockfileDriftPolicyReportsContentDriftWithoutGit(t *testing.T) {
+	repo := t.TempDir()
+	webDir := filepath.Join(repo, "packages", "web")
+	mustMkdirAll(t, webDir)
+	writeTextFile(t, filepath.Join(webDir, "package.json"), `{"dependencies": {"left-pad": "^1.3.0", "react": "^18.2.0"}}`, 0o644)
+	writeTextFile(t, filepath.Join(webDir, "package-lock.json"), `{"lockfileVersion": 3, "packages": {"": {"dependencies": {"react": "^18.2.0"}}, "node_modules/react": {"version": "18.3.1"}}}`, 0o644)
+	writeTextFile(t, filepath.Join(repo, "Cargo.toml"), "[package]\nname = \"demo\"\n\n[dependencies]\nserde = \"1\"\n", 0o644)
+	writeTextFile(t, filepath.Join(repo, "Cargo.lock"), "[[package]]\nname = \"serde\"\nversion = \"1.0.203\"\n", 0o644)
+	writeTextFile(t, filepath.Join(repo, "composer.json"), `{"require": {"monolog/monolog": "^3.0"}}`, 0o644)
+	writeTextFile(t, filepath.Join(repo, "composer.lock"), `{"packages": [`, 0o644)
+
+	warnings, err := evaluateLockfileDriftPolicyWithFeatures(context.Background(), repo, "warn", featureflags.Set{})
+	if err != nil || hasWarningContaining(warnings, "does not match") {
+		t.Fatalf("expected content drift to stay disabled without the preview flag, got %#v, %v", warnings, err)
+	}
+
+	features := mustLockfileContentFeatureSet(t)
+	warnings, err = evaluateLockfileDriftPolicyWithFeatures(context.Background(), repo, "warn", features)
+	if err != nil {
+		t.Fatalf("evaluate lockfile drift: %v", err)
+	}
+	want := "lockfile drift detected: npm in packages/web: package-lock.json does not match package.json (missing left-pad ^1.3.0);"
+	if !hasWarningContaining(warnings, want) {
+		t.Fatalf("expected npm content drift warning %q, got %#v", want, warnings)
+	}
+	if hasWarningContaining(warnings, "Cargo in .") {
+		t.Fatalf("expected in-sync Cargo.lock to be clean, got %#v", warnings)
+	}
+	if !hasWarningContaining(warnings, "unable to compare Composer lockfile content in .: parse composer.lock") {
+		t.Fatalf("expected malformed composer.lock warning, got %#v", warnings)
+	}
+
+	_, err = evaluateLockfileDriftPolicyWithFeatures(context.Background(), repo, "fail", features)
+	if !errors.Is(err, ErrLockfileDrift) || !strings.Contains(err.Error(), "missing left-pad") {
+		t.Fatalf("expected fail policy to report content drift, got %v", err)
+	}
+}
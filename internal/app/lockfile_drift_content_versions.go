@@ -0,0 +1,485 @@
+package app
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/report/pep440"
+)
+
+// lockfileConstraintStyle selects how a manifest version constraint is read.
+// Styles differ mainly in what a bare version and a tilde mean.
+type lockfileConstraintStyle uint8
+
+const (
+	constraintStyleNPM lockfileConstraintStyle = iota + 1
+	constraintStyleCargo
+	constraintStyleComposer
+	constraintStylePoetry
+	constraintStylePEP440
+	constraintStyleRubyGems
+	constraintStylePub
+	constraintStyleMix
+	// constraintStyleRange is comma-separated comparator clauses that parsers
+	// translate native range syntax into; a bare version is exact.
+	constraintStyleRange
+	constraintStyleExact
+)
+
+type looseVersion struct {
+	raw      string
+	parts    [3]int
+	rest     []int
+	count    int
+	wildcard bool
+	pre      string
+}
+
+type versionComparator struct {
+	op      string
+	version looseVersion
+}
+
+// lockfileVersionSatisfies reports whether locked satisfies constraint and
+// whether the constraint was understood at all. Unknown syntax (git refs,
+// dist-tags, path specs) is never reported as unsatisfied.
+func lockfileVersionSatisfies(style lockfileConstraintStyle, locked, constraint string) (bool, bool) {
+	constraint = strings.TrimSpace(constraint)
+	if style == constraintStyleExact {
+		return strings.TrimPrefix(strings.TrimSpace(locked), "v") == strings.TrimPrefix(constraint, "v"), constraint != ""
+	}
+	version, ok := parseLooseVersion(locked)
+	if !ok || version.count == 0 || version.wildcard {
+		return false, false
+	}
+	alternatives, ok := parseLockfileConstraint(style, constraint)
+	if !ok {
+		return false, false
+	}
+	for _, comparators := range alternatives {
+		if versionMatchesAll(style, version, comparators) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func versionMatchesAll(style lockfileConstraintStyle, version looseVersion, comparators []versionComparator) bool {
+	for _, comparator := range comparators {
+		if !comparator.matches(style, version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c versionComparator) matches(style lockfileConstraintStyle, version looseVersion) bool {
+	order := compareLockfileVersions(style, version, c.version)
+	switch c.op {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	default:
+		return false
+	}
+}
+
+func parseLockfileConstraint(style lockfileConstraintStyle, constraint string) ([][]versionComparator, bool) {
+	constraint = strings.TrimSpace(constraint)
+	if style == constraintStyleComposer {
+		constraint = stripComposerStability(constraint)
+	}
+	if constraint == "" {
+		return nil, false
+	}
+	alternatives := make([][]versionComparator, 0, 1)
+	for _, alternative := range splitConstraintAlternatives(style, constraint) {
+		comparators, ok := parseConstraintAlternative(style, strings.TrimSpace(alternative))
+		if !ok {
+			return nil, false
+		}
+		alternatives = append(alternatives, comparators)
+	}
+	return alternatives, len(alternatives) > 0
+}
+
+func splitConstraintAlternatives(style lockfileConstraintStyle, constraint string) []string {
+	switch style {
+	case constraintStyleNPM, constraintStylePoetry, constraintStylePub:
+		return strings.Split(constraint, "||")
+	case constraintStyleComposer:
+		return strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|")
+	case constraintStyleMix:
+		return strings.Split(constraint, " or ")
+	default:
+		return []string{constraint}
+	}
+}
+
+func parseConstraintAlternative(style lockfileConstraintStyle, alternative string) ([]versionComparator, bool) {
+	if alternative == "" {
+		return nil, false
+	}
+	if style == constraintStyleNPM {
+		if low, high, ok := strings.Cut(alternative, " - "); ok {
+			return parseHyphenRange(low, high)
+		}
+	}
+	comparators := make([]versionComparator, 0, 2)
+	for _, token := range splitConstraintClauses(style, alternative) {
+		parsed, ok := parseConstraintClause(style, token)
+		if !ok {
+			return nil, false
+		}
+		comparators = append(comparators, parsed...)
+	}
+	return comparators, true
+}
+
+// splitConstraintClauses splits an alternative into AND-ed clauses, joining
+// operators that are separated from their version by whitespace.
+func splitConstraintClauses(style lockfileConstraintStyle, alternative string) []string {
+	var raw []string
+	switch style {
+	case constraintStyleNPM, constraintStylePub:
+		raw = strings.Fields(alternative)
+	case constraintStyleComposer, constraintStylePoetry:
+		raw = strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+	case constraintStyleMix:
+		raw = strings.Split(alternative, " and ")
+	default:
+		raw = strings.Split(alternative, ",")
+	}
+	clauses := make([]string, 0, len(raw))
+	pending := ""
+	for _, token := range raw {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if strings.Trim(token, "<>=!~^") == "" {
+			pending += token
+			continue
+		}
+		clauses = append(clauses, pending+token)
+		pending = ""
+	}
+	if pending != "" {
+		clauses = append(clauses, pending)
+	}
+	return clauses
+}
+
+func parseConstraintClause(style lockfileConstraintStyle, clause string) ([]versionComparator, bool) {
+	clause = strings.TrimSpace(clause)
+	switch strings.ToLower(clause) {
+	case "*", "x", "any":
+		return nil, true
+	}
+	op, rest := splitConstraintOperator(clause)
+	version, ok := parseLooseVersion(rest)
+	if !ok {
+		return nil, false
+	}
+	switch op {
+	case "^":
+		return caretRange(version), true
+	case "~":
+		if style == constraintStyleComposer {
+			return compatibleRange(version), true
+		}
+		return tildeRange(version), true
+	case "~>", "~=":
+		return compatibleRange(version), true
+	case "", "=", "==", "===":
+		return bareConstraintRange(style, op, version), true
+	case "!=":
+		if version.wildcard || version.count < 3 {
+			return nil, false
+		}
+		return []versionComparator{{op: "!=", version: version}}, true
+	case ">", ">=", "<", "<=":
+		return inequalityRange(style, op, version), true
+	default:
+		return nil, false
+	}
+}
+
+func splitConstraintOperator(clause string) (string, string) {
+	for _, op := range []string{"===", "==", "!=", ">=", "<=", "~>", "~=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(clause, op) {
+			return op, strings.TrimSpace(clause[len(op):])
+		}
+	}
+	return "", clause
+}
+
+func bareConstraintRange(style lockfileConstraintStyle, op string, version looseVersion) []versionComparator {
+	if op == "" && style == constraintStyleCargo {
+		return caretRange(version)
+	}
+	exactPartial := style == constraintStylePEP440 || style == constraintStyleRubyGems || style == constraintStylePub || style == constraintStyleMix || style == constraintStyleRange
+	if exactPartial && !version.wildcard {
+		return []versionComparator{{op: "=", version: version}}
+	}
+	return partialRange(version)
+}
+
+func parseHyphenRange(low, high string) ([]versionComparator, bool) {
+	lower, ok := parseLooseVersion(low)
+	if !ok {
+		return nil, false
+	}
+	upper, ok := parseLooseVersion(high)
+	if !ok {
+		return nil, false
+	}
+	comparators := []versionComparator{{op: ">=", version: lower}}
+	return append(comparators, inequalityRange(constraintStyleNPM, "<=", upper)...), true
+}
+
+func caretRange(version looseVersion) []versionComparator {
+	upper := looseVersion{count: 3}
+	switch {
+	case version.parts[0] > 0 || version.count <= 1:
+		upper.parts = [3]int{version.parts[0] + 1, 0, 0}
+	case version.parts[1] > 0 || version.count == 2:
+		upper.parts = [3]int{0, version.parts[1] + 1, 0}
+	default:
+		upper.parts = [3]int{0, 0, version.parts[2] + 1}
+	}
+	return boundedRange(version, upper)
+}
+
+func tildeRange(version looseVersion) []versionComparator {
+	upper := looseVersion{count: 3}
+	if version.count <= 1 {
+		upper.parts = [3]int{version.parts[0] + 1, 0, 0}
+	} else {
+		upper.parts = [3]int{version.parts[0], version.parts[1] + 1, 0}
+	}
+	return boundedRange(version, upper)
+}
+
+// compatibleRange implements "~>" (RubyGems, Mix), "~=" (PEP 440), and Composer's
+// "~": the last given component may increase.
+func compatibleRange(version looseVersion) []versionComparator {
+	upper := looseVersion{count: 3}
+	if version.count >= 3 {
+		upper.parts = [3]int{version.parts[0], version.parts[1] + 1, 0}
+	} else {
+		upper.parts = [3]int{version.parts[0] + 1, 0, 0}
+	}
+	return boundedRange(version, upper)
+}
+
+func partialRange(version looseVersion) []versionComparator {
+	if version.count >= 3 && !version.wildcard {
+		return []versionComparator{{op: "=", version: version}}
+	}
+	if version.count == 0 {
+		return nil
+	}
+	upper := looseVersion{count: 3}
+	if version.count == 1 {
+		upper.parts = [3]int{version.parts[0] + 1, 0, 0}
+	} else {
+		upper.parts = [3]int{version.parts[0], version.parts[1] + 1, 0}
+	}
+	return boundedRange(version, upper)
+}
+
+// inequalityRange builds a comparator for "<", "<=", ">", or ">=". npm and
+// Cargo read a partial version as the range it names, so "<=1.2" admits every
+// 1.2.x and ">1.2" starts at 1.3.0. Other styles pad a partial version with
+// zeros, so under PEP 440 "<=1.2" means "<=1.2.0".
+func inequalityRange(style lockfileConstraintStyle, op string, version looseVersion) []versionComparator {
+	partialRanges := style == constraintStyleNPM || style == constraintStyleCargo
+	if !partialRanges || version.count >= 3 || version.count == 0 {
+		return []versionComparator{{op: op, version: version}}
+	}
+	bumped := partialRange(version)
+	upper := bumped[len(bumped)-1].version
+	switch op {
+	case "<=":
+		return []versionComparator{{op: "<", version: upper}}
+	case ">":
+		return []versionComparator{{op: ">=", version: upper}}
+	default:
+		return []versionComparator{{op: op, version: version}}
+	}
+}
+
+func boundedRange(lower, upper looseVersion) []versionComparator {
+	if lower.wildcard {
+		lower.raw, lower.wildcard = "", false
+	}
+	return []versionComparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+func stripComposerStability(constraint string) string {
+	if index := strings.Index(constraint, "@"); index >= 0 {
+		constraint = constraint[:index]
+	}
+	return strings.TrimSpace(constraint)
+}
+
+// parseLooseVersion reads a dotted numeric version. The first three
+// components fill parts and any further numeric ones rest. A wildcard
+// component ("x", "*") ends the version; trailing text after the last digits
+// of a component (for example "0rc1" or "0-beta.1"), or from the first
+// non-numeric component on (RubyGems' "1.0.0.pre", PEP 440's "1.0.post1"),
+// becomes the prerelease.
+// raw keeps the version as written for the ecosystem comparators.
+func parseLooseVersion(value string) (looseVersion, bool) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "v"), "V")
+	version := looseVersion{raw: value}
+	if index := strings.Index(value, "+"); index >= 0 {
+		value = value[:index]
+	}
+	if core, pre, ok := strings.Cut(value, "-"); ok {
+		value, version.pre = core, pre
+	}
+	if value == "" {
+		return looseVersion{}, false
+	}
+	components := strings.Split(value, ".")
+	for index, component := range components {
+		switch component {
+		case "x", "X", "*":
+			version.wildcard = true
+			return version, true
+		}
+		digits := len(component) - len(strings.TrimLeft(component, "0123456789"))
+		if digits == 0 {
+			if version.count == 0 {
+				return looseVersion{}, false
+			}
+			if version.pre == "" {
+				version.pre = strings.Join(components[index:], ".")
+			}
+			break
+		}
+		number, err := strconv.Atoi(component[:digits])
+		if err != nil {
+			return looseVersion{}, false
+		}
+		if version.count < len(version.parts) {
+			version.parts[version.count] = number
+			version.count++
+		} else {
+			version.rest = append(version.rest, number)
+		}
+		if digits < len(component) {
+			if version.pre == "" {
+				version.pre = component[digits:]
+			}
+			break
+		}
+	}
+	return version, true
+}
+
+// text is the version as written, or for bounds computed from a constraint,
+// its numeric components and prerelease.
+func (v looseVersion) text() string {
+	if v.raw != "" {
+		return v.raw
+	}
+	components := make([]string, 0, v.count)
+	for _, part := range v.parts[:v.count] {
+		components = append(components, strconv.Itoa(part))
+	}
+	text := strings.Join(components, ".")
+	if v.pre != "" {
+		text += "-" + v.pre
+	}
+	return text
+}
+
+// compareLockfileVersions orders versions by the ecosystem's own rules where
+// the tree has them: PEP 440 for pip and Poetry, and semver for npm, Cargo,
+// Pub, and Mix. Other styles, and versions those rules reject, fall back to
+// compareLooseVersions.
+func compareLockfileVersions(style lockfileConstraintStyle, left, right looseVersion) int {
+	switch style {
+	case constraintStylePEP440, constraintStylePoetry:
+		if order, ok := pep440.CompareVersions(left.text(), right.text()); ok {
+			return order
+		}
+	case constraintStyleNPM, constraintStyleCargo, constraintStylePub, constraintStyleMix:
+		if order, ok := report.CompareSemanticVersions(left.text(), right.text()); ok {
+			return order
+		}
+	}
+	return compareLooseVersions(left, right)
+}
+
+func compareLooseVersions(left, right looseVersion) int {
+	for index := range left.parts {
+		if order := cmp.Compare(left.parts[index], right.parts[index]); order != 0 {
+			return order
+		}
+	}
+	for index := 0; index < len(left.rest) || index < len(right.rest); index++ {
+		if order := cmp.Compare(restComponent(left, index), restComponent(right, index)); order != 0 {
+			return order
+		}
+	}
+	switch {
+	case left.pre == right.pre:
+		return 0
+	case left.pre == "":
+		return 1
+	case right.pre == "":
+		return -1
+	default:
+		return comparePrerelease(left.pre, right.pre)
+	}
+}
+
+func restComponent(version looseVersion, index int) int {
+	if index < len(version.rest) {
+		return version.rest[index]
+	}
+	return 0
+}
+
+// comparePrerelease orders the prerelease tags of styles without a shared
+// comparator: dot-separated identifiers compare in turn, all-digit ones
+// numerically and below the rest, and a tag that is a prefix of another sorts
+// first.
+func comparePrerelease(left, right string) int {
+	leftIDs, rightIDs := strings.Split(left, "."), strings.Split(right, ".")
+	for index := 0; index < len(leftIDs) && index < len(rightIDs); index++ {
+		leftNumber, leftErr := strconv.Atoi(leftIDs[index])
+		rightNumber, rightErr := strconv.Atoi(rightIDs[index])
+		var order int
+		switch {
+		case leftErr == nil && rightErr == nil:
+			order = cmp.Compare(leftNumber, rightNumber)
+		case leftErr == nil:
+			order = -1
+		case rightErr == nil:
+			order = 1
+		default:
+			order = strings.Compare(leftIDs[index], rightIDs[index])
+		}
+		if order != 0 {
+			return order
+		}
+	}
+	return cmp.Compare(len(leftIDs), len(rightIDs))
+}
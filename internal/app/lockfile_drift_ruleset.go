@@ -28,6 +28,8 @@ type lockfileRule struct {
 	manifestLabel         string
 	lockfiles             []string
 	remedy                string
+	refreshCommands       map[string]string
+	contentParser         *lockfileContentParser
 	previewFeatureFlag    string
 	manifestMatcherLabel  string
 	manifestMatcherNeedle string
@@ -40,18 +42,20 @@ type presentLockfile struct {
 }
 
 var lockfileRules = []lockfileRule{
-	{manager: "npm", manifest: "package.json", lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb"}, remedy: "run npm install for package-lock.json/npm-shrinkwrap.json, yarn install for yarn.lock, pnpm install for pnpm-lock.yaml, or bun install for bun.lockb; then commit the updated manifest and lockfile"},
-	{manager: "Bundler", manifest: "Gemfile", lockfiles: []string{"Gemfile.lock"}, remedy: "run bundle install (or bundle lock) and commit the updated Gemfile and Gemfile.lock"},
-	{manager: "Composer", manifest: "composer.json", lockfiles: []string{"composer.lock"}, remedy: "run composer update --lock (or composer install) and commit the updated files"},
-	{manager: "Cargo", manifest: "Cargo.toml", lockfiles: []string{"Cargo.lock"}, remedy: "run cargo generate-lockfile (or cargo build) and commit the updated files"},
-	{manager: "Go modules", manifest: "go.mod", lockfiles: []string{"go.sum"}, remedy: "run go mod tidy and commit the updated files"},
-	{manager: "Pipenv", manifest: "Pipfile", lockfiles: []string{"Pipfile.lock"}, remedy: "run pipenv lock and commit the updated files"},
+	{manager: "npm", manifest: "package.json", lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb"}, remedy: "run npm install for package-lock.json/npm-shrinkwrap.json, yarn install for yarn.lock, pnpm install for pnpm-lock.yaml, or bun install for bun.lockb; then commit the updated manifest and lockfile", refreshCommands: map[string]string{"package-lock.json": "npm install", "npm-shrinkwrap.json": "npm install", "yarn.lock": "yarn install", "pnpm-lock.yaml": "pnpm install", "bun.lockb": "bun install"}, contentParser: npmLockfileContentParser},
+	{manager: "Bundler", manifest: "Gemfile", lockfiles: []string{"Gemfile.lock"}, remedy: "run bundle install (or bundle lock) and commit the updated Gemfile and Gemfile.lock", refreshCommands: map[string]string{"Gemfile.lock": "bundle lock"}, contentParser: bundlerLockfileContentParser},
+	{manager: "Composer", manifest: "composer.json", lockfiles: []string{"composer.lock"}, remedy: "run composer update --lock (or composer install) and commit the updated files", refreshCommands: map[string]string{"composer.lock": "composer update --lock"}, contentParser: composerLockfileContentParser},
+	{manager: "Cargo", manifest: "Cargo.toml", lockfiles: []string{"Cargo.lock"}, remedy: "run cargo generate-lockfile (or cargo build) and commit the updated files", refreshCommands: map[string]string{"Cargo.lock": "cargo update --workspace"}, contentParser: cargoLockfileContentParser},
+	{manager: "Go modules", manifest: "go.mod", lockfiles: []string{"go.sum"}, remedy: "run go mod tidy and commit the updated files", refreshCommands: map[string]string{"go.sum": "go mod tidy"}, contentParser: goModulesLockfileContentParser},
+	{manager: "Pipenv", manifest: "Pipfile", lockfiles: []string{"Pipfile.lock"}, remedy: "run pipenv lock and commit the updated files", refreshCommands: map[string]string{"Pipfile.lock": "pipenv lock"}, contentParser: pipenvLockfileContentParser},
 	{
 		manager:               "Poetry",
 		manifest:              pyprojectManifestName,
 		manifestLabel:         "Poetry configuration in pyproject.toml",
 		lockfiles:             []string{"poetry.lock"},
 		remedy:                "run poetry lock and commit the updated files",
+		refreshCommands:       map[string]string{"poetry.lock": "poetry lock"},
+		contentParser:         poetryLockfileContentParser,
 		manifestMatcherLabel:  pyprojectPoetrySection,
 		manifestMatcherNeedle: pyprojectSectionNeedle(pyprojectPoetrySection),
 		manifestMatcher:       pyprojectSectionMatcher(pyprojectPoetrySection),
@@ -62,6 +66,8 @@ var lockfileRules = []lockfileRule{
 		manifestLabel:         "uv configuration in pyproject.toml",
 		lockfiles:             []string{"uv.lock"},
 		remedy:                "run uv lock and commit the updated files",
+		refreshCommands:       map[string]string{"uv.lock": "uv lock"},
+		contentParser:         uvLockfileContentParser,
 		manifestMatcherLabel:  pyprojectUVSection,
 		manifestMatcherNeedle: pyprojectSectionNeedle(pyprojectUVSection),
 		manifestMatcher:       pyprojectSectionMatcher(pyprojectUVSection),
@@ -73,6 +79,8 @@ var lockfileRules = []lockfileRule{
 		manifestLabel:      ".NET project manifest (*.csproj, *.fsproj) or Directory.Packages.props",
 		lockfiles:          []string{"packages.lock.json"},
 		remedy:             "run dotnet restore --use-lock-file (or dotnet restore for existing lock mode) and commit the updated files",
+		refreshCommands:    map[string]string{"packages.lock.json": "dotnet restore --use-lock-file"},
+		contentParser:      nugetLockfileContentParser,
 		previewFeatureFlag: lockfileDriftEcosystemExpansionPreviewFlagName,
 	},
 	{
@@ -81,6 +89,8 @@ var lockfileRules = []lockfileRule{
 		manifestNames:      []string{"pubspec.yml"},
 		lockfiles:          []string{"pubspec.lock"},
 		remedy:             "run dart pub get (or flutter pub get) and commit the updated files",
+		refreshCommands:    map[string]string{"pubspec.lock": "dart pub get"},
+		contentParser:      pubLockfileContentParser,
 		previewFeatureFlag: lockfileDriftEcosystemExpansionPreviewFlagName,
 	},
 	{
@@ -88,6 +98,8 @@ var lockfileRules = []lockfileRule{
 		manifest:           "mix.exs",
 		lockfiles:          []string{"mix.lock"},
 		remedy:             "run mix deps.get and commit the updated files",
+		refreshCommands:    map[string]string{"mix.lock": "mix deps.get"},
+		contentParser:      mixLockfileContentParser,
 		previewFeatureFlag: lockfileDriftEcosystemExpansionPreviewFlagName,
 	},
 	{
@@ -95,6 +107,8 @@ var lockfileRules = []lockfileRule{
 		manifest:           "Package.swift",
 		lockfiles:          []string{"Package.resolved"},
 		remedy:             "run swift package resolve and commit the updated files",
+		refreshCommands:    map[string]string{"Package.resolved": "swift package resolve"},
+		contentParser:      swiftPMLockfileContentParser,
 		previewFeatureFlag: lockfileDriftEcosystemExpansionPreviewFlagName,
 	},
 }
@@ -0,0 +1,367 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	ManifestCodemodPreviewFeature = "manifest-codemod-preview"
+
+	ManifestActionRemove = "remove"
+	ManifestActionDemote = "demote"
+
+	manifestCodemodReasonConfirmedUnused   = "confirmed-unused-dependency"
+	manifestCodemodReasonRemove            = "manifest-remove-entry"
+	manifestCodemodReasonDemote            = "manifest-demote-entry"
+	manifestCodemodReasonLayoutUnsupported = "manifest-layout-unsupported"
+	manifestCodemodReasonDemoteUnsupported = "manifest-demote-unsupported"
+	manifestCodemodReasonAlreadyDev        = "already-dev-dependency"
+	manifestCodemodReasonOptional          = "optional-dependency"
+)
+
+type manifestCodemodTarget struct {
+	repoPath   string
+	dependency *report.DependencyReport
+	action     string
+	editors    []manifestEditor
+	roots      []string
+}
+
+type manifestCodemodFile struct {
+	relPath string
+	editor  manifestEditor
+}
+
+func applyManifestCodemodIfNeeded(ctx context.Context, reportData report.Report, repoPath string, req AnalyseRequest) (report.Report, error) {
+	if !req.RemoveUnusedDependencies {
+		return reportData, nil
+	}
+
+	target, warning, err := resolveManifestCodemodTarget(&reportData, repoPath, req)
+	if err != nil {
+		return reportData, err
+	}
+	if warning != "" {
+		reportData.Warnings = append(reportData.Warnings, warning)
+		return reportData, nil
+	}
+
+	files, err := findManifestCodemodFiles(ctx, target.repoPath, target.roots, target.editors)
+	if err != nil {
+		return reportData, fmt.Errorf("discover manifests for %s: %w", target.dependency.Name, err)
+	}
+
+	var suggestions []report.CodemodSuggestion
+	var skips []report.CodemodSkip
+	edited := make([]string, 0, len(files))
+	planned := 0
+	for _, file := range files {
+		fileSuggestions, fileSkips, found, err := planManifestCodemodFile(target, file)
+		if err != nil {
+			return reportData, err
+		}
+		if !found {
+			continue
+		}
+		planned++
+		suggestions = append(suggestions, fileSuggestions...)
+		skips = append(skips, fileSkips...)
+		if len(fileSuggestions) > 0 {
+			edited = append(edited, file.relPath)
+		}
+	}
+	if planned == 0 {
+		reportData.Warnings = append(reportData.Warnings, fmt.Sprintf("manifest codemod found no manifest entry for %s", target.dependency.Name))
+		return reportData, nil
+	}
+
+	codemod := ensureManifestCodemodReport(target.dependency)
+	codemod.Suggestions = append(codemod.Suggestions, suggestions...)
+	codemod.Skips = append(codemod.Skips, skips...)
+	codemod.LockfileRefresh = append(codemod.LockfileRefresh, resolveManifestLockfileRefresh(target.repoPath, edited, req)...)
+	return reportData, nil
+}
+
+func resolveManifestCodemodTarget(reportData *report.Report, repoPath string, req AnalyseRequest) (manifestCodemodTarget, string, error) {
+	normalizedRepoPath, err := normalizeRepoPathForCodemod(repoPath)
+	if err != nil {
+		return manifestCodemodTarget{}, "", err
+	}
+	dependency := findDependencyReport(reportData, req.Dependency)
+	if dependency == nil {
+		return manifestCodemodTarget{}, fmt.Sprintf("manifest codemod skipped: dependency %s was not found in the report", req.Dependency), nil
+	}
+	if !report.ConfirmedUnused(*dependency) {
+		return manifestCodemodTarget{}, fmt.Sprintf("manifest codemod skipped for %s: dependency is not confirmed unused", dependency.Name), nil
+	}
+	if len(dependency.UnusedRoots) == 0 {
+		return manifestCodemodTarget{}, fmt.Sprintf("manifest codemod skipped for %s: no analysed root reported it unused", dependency.Name), nil
+	}
+	editors := manifestEditorsForLanguage(dependency.Language)
+	if len(editors) == 0 {
+		return manifestCodemodTarget{}, fmt.Sprintf("manifest codemod skipped for %s: %s manifests are not supported", dependency.Name, dependency.Language), nil
+	}
+
+	return manifestCodemodTarget{
+		repoPath:   normalizedRepoPath,
+		dependency: dependency,
+		action:     normalizeManifestAction(req.ManifestAction),
+		editors:    editors,
+		roots:      dependency.UnusedRoots,
+	}, "", nil
+}
+
+func findDependencyReport(reportData *report.Report, dependency string) *report.DependencyReport {
+	for i := range reportData.Dependencies {
+		if reportData.Dependencies[i].Name == dependency {
+			return &reportData.Dependencies[i]
+		}
+	}
+	return nil
+}
+
+func normalizeManifestAction(action string) string {
+	if strings.TrimSpace(action) == "" {
+		return ManifestActionRemove
+	}
+	return strings.ToLower(strings.TrimSpace(action))
+}
+
+func ensureManifestCodemodReport(dep *report.DependencyReport) *report.CodemodReport {
+	if dep.Codemod == nil {
+		dep.Codemod = &report.CodemodReport{Mode: shared.CodemodModeSuggestOnly}
+	}
+	return dep.Codemod
+}
+
+// findManifestCodemodFiles lists the supported manifests directly inside each
+// root. Only the roots whose analysis reported the dependency unused are
+// searched, so packages elsewhere in a monorepo that still import it keep
+// their entries.
+func findManifestCodemodFiles(ctx context.Context, repoPath string, roots []string, editors []manifestEditor) ([]manifestCodemodFile, error) {
+	byName := make(map[string]manifestEditor)
+	for _, editor := range editors {
+		for _, name := range editor.manifests {
+			byName[name] = editor
+		}
+	}
+
+	files := make([]manifestCodemodFile, 0)
+	for _, root := range roots {
+		if ctx != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		entries, err := readDirectoryFiles(filepath.Join(repoPath, filepath.FromSlash(root)))
+		if err != nil {
+			return nil, err
+		}
+		for name, info := range entries {
+			editor, ok := byName[name]
+			if !ok || !info.Mode().IsRegular() {
+				continue
+			}
+			files = append(files, manifestCodemodFile{relPath: path.Join(root, name), editor: editor})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].relPath < files[j].relPath
+	})
+	return files, nil
+}
+
+func planManifestCodemodFile(target manifestCodemodTarget, file manifestCodemodFile) ([]report.CodemodSuggestion, []report.CodemodSkip, bool, error) {
+	content, err := safeio.ReadFileUnder(target.repoPath, filepath.Join(target.repoPath, filepath.FromSlash(file.relPath)))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("read manifest %s: %w", file.relPath, err)
+	}
+	lines := manifestLineTexts(string(content))
+	outcome := file.editor.plan(manifestEditInput{
+		lines:      lines,
+		dependency: target.dependency.Name,
+		action:     target.action,
+		newline:    detectManifestNewline(string(content)),
+	})
+	if !outcome.found {
+		return nil, nil, false, nil
+	}
+
+	dep := target.dependency
+	if outcome.skipReason != "" {
+		skip := shared.NewCodemodSkip(shared.CodemodSkipSpec{
+			Language:   dep.Language,
+			Dependency: dep.Name,
+			File:       file.relPath,
+			Line:       max(outcome.skipLine, 1),
+			ImportName: dep.Name,
+			Module:     outcome.fromSection,
+			ReasonCode: outcome.skipReason,
+			Message:    outcome.skipMessage,
+		})
+		return nil, []report.CodemodSkip{skip}, true, nil
+	}
+
+	actionReason := manifestCodemodReasonRemove
+	if target.action == ManifestActionDemote {
+		actionReason = manifestCodemodReasonDemote
+	}
+	suggestions := make([]report.CodemodSuggestion, 0, len(outcome.edits))
+	for _, edit := range outcome.edits {
+		suggestions = append(suggestions, shared.NewCodemodSuggestion(shared.CodemodSuggestionSpec{
+			Language:          dep.Language,
+			Dependency:        dep.Name,
+			File:              file.relPath,
+			Line:              edit.line,
+			ImportName:        dep.Name,
+			FromModule:        outcome.fromSection,
+			ToModule:          outcome.toSection,
+			Original:          edit.original,
+			Replacement:       edit.replacement,
+			Patch:             buildManifestEditPatch(file.relPath, edit),
+			SafetyReasonCodes: []string{manifestCodemodReasonConfirmedUnused, actionReason},
+			DeleteLine:        edit.deleteLine,
+		}))
+	}
+	return suggestions, nil, true, nil
+}
+
+func manifestLineTexts(content string) []string {
+	contentLines := splitCodemodContentLines(content)
+	lines := make([]string, 0, len(contentLines))
+	for _, line := range contentLines {
+		lines = append(lines, line.text)
+	}
+	return lines
+}
+
+func detectManifestNewline(content string) string {
+	if strings.Contains(content, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+func buildManifestEditPatch(file string, edit manifestLineEdit) string {
+	if edit.deleteLine {
+		return shared.BuildDeleteLinePatch(file, edit.line, edit.original)
+	}
+	added := strings.Split(strings.ReplaceAll(edit.replacement, "\r\n", "\n"), "\n")
+	if len(added) == 1 {
+		return shared.BuildSingleLinePatch(file, edit.line, edit.original, edit.replacement)
+	}
+	patch := []string{
+		fmt.Sprintf("--- a/%s", file),
+		fmt.Sprintf("+++ b/%s", file),
+		fmt.Sprintf("@@ -%d +%d,%d @@", edit.line, edit.line, len(added)),
+		"-" + edit.original,
+	}
+	for _, line := range added {
+		patch = append(patch, "+"+line)
+	}
+	return strings.Join(patch, "\n")
+}
+
+func resolveManifestLockfileRefresh(repoPath string, manifests []string, req AnalyseRequest) []report.CodemodLockfileRefresh {
+	rules := activeLockfileRules(req.Features)
+	refresh := make([]report.CodemodLockfileRefresh, 0, len(manifests))
+	for _, manifest := range manifests {
+		dir := filepath.Join(repoPath, filepath.Dir(filepath.FromSlash(manifest)))
+		files, err := readDirectoryFiles(dir)
+		if err != nil {
+			continue
+		}
+		rule, lockfile, ok := matchManifestLockfileRule(repoPath, dir, filepath.Base(manifest), files, rules)
+		if !ok {
+			continue
+		}
+		refresh = append(refresh, report.CodemodLockfileRefresh{
+			Manifest: manifest,
+			Manager:  rule.manager,
+			Command:  lockfileRefreshCommand(rule, lockfile, path.Dir(manifest)),
+		})
+	}
+	return refresh
+}
+
+func matchManifestLockfileRule(repoPath, dir, manifestName string, files map[string]fs.FileInfo, rules []lockfileRule) (lockfileRule, string, bool) {
+	candidates := make([]lockfileRule, 0, 2)
+	for _, rule := range rules {
+		if rule.manifest == manifestName || slices.Contains(rule.manifestNames, manifestName) {
+			candidates = append(candidates, rule)
+		}
+	}
+	for _, rule := range candidates {
+		if present := findRuleLockfiles(files, rule.lockfiles); len(present) > 0 {
+			return rule, present[0].name, true
+		}
+	}
+	for _, rule := range candidates {
+		if rule.manifestMatcher == nil {
+			return rule, rule.lockfiles[0], true
+		}
+		matched, err := rule.manifestMatcher(repoPath, dir)
+		if err == nil && matched {
+			return rule, rule.lockfiles[0], true
+		}
+	}
+	return lockfileRule{}, "", false
+}
+
+func lockfileRefreshCommand(rule lockfileRule, lockfile, dir string) string {
+	command := rule.refreshCommands[lockfile]
+	if dir == "" || dir == "." {
+		return command
+	}
+	return fmt.Sprintf("cd %s && %s", shellQuote(dir), command)
+}
+
+// shellQuote returns value as a single POSIX shell word, leaving plain paths
+// unquoted.
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./", r))
+	}) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func appendManifestLockfileRefreshWarnings(reportData report.Report, req AnalyseRequest) report.Report {
+	if !req.RemoveUnusedDependencies || !req.ApplyCodemod {
+		return reportData
+	}
+	dep := findDependencyReport(&reportData, req.Dependency)
+	if dep == nil || dep.Codemod == nil || dep.Codemod.Apply == nil {
+		return reportData
+	}
+	applied := make(map[string]struct{}, len(dep.Codemod.Apply.Results))
+	for _, result := range dep.Codemod.Apply.Results {
+		if result.Status == codemodApplyStatusApplied {
+			applied[result.File] = struct{}{}
+		}
+	}
+	for _, refresh := range dep.Codemod.LockfileRefresh {
+		if _, ok := applied[refresh.Manifest]; !ok {
+			continue
+		}
+		reportData.Warnings = append(reportData.Warnings, fmt.Sprintf("refresh %s lockfile after editing %s: %s", refresh.Manager, refresh.Manifest, refresh.Command))
+	}
+	return reportData
+}
+
+func validateManifestCodemodFeatures(req AnalyseRequest) error {
+	if !req.RemoveUnusedDependencies || req.Features.Enabled(ManifestCodemodPreviewFeature) {
+		return nil
+	}
+	return fmt.Errorf("--remove-unused-dependencies requires --enable-feature %s", ManifestCodemodPreviewFeature)
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"strings"
+)
+
+type manifestEditor struct {
+	language  string
+	manifests []string
+	plan      func(manifestEditInput) manifestEditOutcome
+}
+
+type manifestEditInput struct {
+	lines      []string
+	dependency string
+	action     string
+	newline    string
+}
+
+type manifestLineEdit struct {
+	line        int
+	original    string
+	replacement string
+	deleteLine  bool
+}
+
+type manifestEditOutcome struct {
+	found       bool
+	edits       []manifestLineEdit
+	fromSection string
+	toSection   string
+	skipLine    int
+	skipReason  string
+	skipMessage string
+}
+
+var manifestEditors = []manifestEditor{
+	{language: "js-ts", manifests: []string{"package.json"}, plan: planPackageJSONEdit},
+	{language: "php", manifests: []string{"composer.json"}, plan: planComposerJSONEdit},
+	{language: "python", manifests: []string{pyprojectManifestName}, plan: planPyprojectEdit},
+	{language: "rust", manifests: []string{"Cargo.toml"}, plan: planCargoTomlEdit},
+	{language: "go", manifests: []string{"go.mod"}, plan: planGoModEdit},
+	{language: "ruby", manifests: []string{"Gemfile"}, plan: planGemfileEdit},
+}
+
+func manifestEditorsForLanguage(language string) []manifestEditor {
+	normalized := strings.ToLower(strings.TrimSpace(language))
+	if normalized == "" {
+		return append([]manifestEditor{}, manifestEditors...)
+	}
+	editors := make([]manifestEditor, 0, 1)
+	for _, editor := range manifestEditors {
+		if editor.language == normalized {
+			editors = append(editors, editor)
+		}
+	}
+	return editors
+}
+
+func deleteManifestLine(lines []string, index int) manifestLineEdit {
+	return manifestLineEdit{line: index + 1, original: lines[index], deleteLine: true}
+}
+
+func replaceManifestLine(lines []string, index int, replacement string) manifestLineEdit {
+	return manifestLineEdit{line: index + 1, original: lines[index], replacement: replacement}
+}
+
+func manifestLayoutSkip(line int, section, message string) manifestEditOutcome {
+	return manifestEditOutcome{
+		found:       true,
+		fromSection: section,
+		skipLine:    line + 1,
+		skipReason:  manifestCodemodReasonLayoutUnsupported,
+		skipMessage: message,
+	}
+}
+
+func manifestAlreadyDevSkip(line int, section string) manifestEditOutcome {
+	return manifestEditOutcome{
+		found:       true,
+		fromSection: section,
+		skipLine:    line + 1,
+		skipReason:  manifestCodemodReasonAlreadyDev,
+		skipMessage: "dependency is already declared in a development section",
+	}
+}
+
+// appendManifestBlock appends block after the last non-blank line and returns
+// it together with removal. When that line is the one removal targets, both
+// collapse into a single replacement so two edits never target the same line.
+func appendManifestBlock(lines []string, removal manifestLineEdit, block []string, newline string) []manifestLineEdit {
+	last := len(lines) - 1
+	for last > 0 && strings.TrimSpace(lines[last]) == "" {
+		last--
+	}
+	appended := strings.Join(block, newline)
+	if removal.line != last+1 {
+		return []manifestLineEdit{removal, replaceManifestLine(lines, last, lines[last]+newline+newline+appended)}
+	}
+	if removal.deleteLine {
+		return []manifestLineEdit{replaceManifestLine(lines, last, newline+appended)}
+	}
+	return []manifestLineEdit{replaceManifestLine(lines, last, removal.replacement+newline+newline+appended)}
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func hasTrailingComma(line string) bool {
+	return strings.HasSuffix(strings.TrimRight(line, " \t"), ",")
+}
+
+func stripTrailingComma(line string) string {
+	trimmed := strings.TrimRight(line, " \t")
+	return strings.TrimSuffix(trimmed, ",")
+}
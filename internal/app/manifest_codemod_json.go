@@ -0,0 +1,274 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	jsonManifestSectionPattern = regexp.MustCompile(`^(\s*)"((?:[^"\\]|\\.)*)"\s*:\s*\{(.*)$`)
+	jsonManifestEntryPattern   = regexp.MustCompile(`^(\s*)"((?:[^"\\]|\\.)*)"\s*:`)
+)
+
+type jsonManifestLayout struct {
+	prodSections []string
+	devSection   string
+	sameName     func(string, string) bool
+}
+
+type jsonManifestSection struct {
+	name    string
+	header  int
+	close   int
+	indent  string
+	inline  bool
+	compact bool
+}
+
+type jsonManifestEntry struct {
+	index int
+	key   string
+}
+
+func planPackageJSONEdit(in manifestEditInput) manifestEditOutcome {
+	return planJSONManifestEdit(in, jsonManifestLayout{
+		prodSections: []string{"dependencies", "optionalDependencies", "peerDependencies"},
+		devSection:   "devDependencies",
+		sameName:     func(a, b string) bool { return a == b },
+	})
+}
+
+func planComposerJSONEdit(in manifestEditInput) manifestEditOutcome {
+	return planJSONManifestEdit(in, jsonManifestLayout{
+		prodSections: []string{"require"},
+		devSection:   "require-dev",
+		sameName:     strings.EqualFold,
+	})
+}
+
+func planJSONManifestEdit(in manifestEditInput, layout jsonManifestLayout) manifestEditOutcome {
+	depths := jsonLineDepths(in.lines)
+	for _, name := range layout.prodSections {
+		section, ok := findJSONManifestSection(in.lines, depths, name)
+		if !ok {
+			continue
+		}
+		outcome, found := planJSONSectionEdit(in, depths, section, layout, false)
+		if found {
+			return outcome
+		}
+	}
+	if section, ok := findJSONManifestSection(in.lines, depths, layout.devSection); ok {
+		if outcome, found := planJSONSectionEdit(in, depths, section, layout, true); found {
+			return outcome
+		}
+	}
+	return manifestEditOutcome{}
+}
+
+func planJSONSectionEdit(in manifestEditInput, depths []int, section jsonManifestSection, layout jsonManifestLayout, devSection bool) (manifestEditOutcome, bool) {
+	if section.compact {
+		if strings.Contains(in.lines[section.header], `"`+in.dependency+`"`) {
+			return manifestLayoutSkip(section.header, section.name, "single-line dependency objects are not rewritten"), true
+		}
+		return manifestEditOutcome{}, false
+	}
+	entry, ok, supported := findJSONManifestEntry(in.lines, depths, section, in.dependency, layout.sameName)
+	if !ok {
+		return manifestEditOutcome{}, false
+	}
+	if !supported {
+		return manifestLayoutSkip(entry.index, section.name, "multi-line dependency entries are not rewritten"), true
+	}
+	if devSection && in.action == ManifestActionDemote {
+		return manifestAlreadyDevSkip(entry.index, section.name), true
+	}
+
+	outcome := manifestEditOutcome{
+		found:       true,
+		fromSection: section.name,
+		edits:       removeJSONManifestEntry(in.lines, section, entry.index),
+	}
+	if in.action != ManifestActionDemote {
+		return outcome, true
+	}
+
+	insertion, ok := insertJSONManifestEntry(in, depths, section, layout.devSection, entry, layout.sameName)
+	if !ok {
+		return manifestLayoutSkip(entry.index, section.name, "development dependency section layout is not rewritten"), true
+	}
+	outcome.toSection = layout.devSection
+	outcome.edits = append(outcome.edits, insertion)
+	return outcome, true
+}
+
+func removeJSONManifestEntry(lines []string, section jsonManifestSection, index int) []manifestLineEdit {
+	edits := []manifestLineEdit{deleteManifestLine(lines, index)}
+	if hasTrailingComma(lines[index]) {
+		return edits
+	}
+	for previous := index - 1; previous > section.header; previous-- {
+		if strings.TrimSpace(lines[previous]) == "" {
+			continue
+		}
+		if hasTrailingComma(lines[previous]) {
+			edits = append(edits, replaceManifestLine(lines, previous, stripTrailingComma(lines[previous])))
+		}
+		break
+	}
+	return edits
+}
+
+func insertJSONManifestEntry(in manifestEditInput, depths []int, from jsonManifestSection, devName string, entry jsonManifestEntry, sameName func(string, string) bool) (manifestLineEdit, bool) {
+	entryText := strings.TrimSpace(stripTrailingComma(in.lines[entry.index]))
+	entryIndent := leadingWhitespace(in.lines[entry.index])
+	unit := strings.TrimPrefix(entryIndent, from.indent)
+	if unit == "" {
+		unit = "  "
+	}
+
+	dev, ok := findJSONManifestSection(in.lines, depths, devName)
+	if !ok {
+		closing := in.lines[from.close]
+		suffix := ""
+		if hasTrailingComma(closing) {
+			suffix = ","
+		}
+		replacement := strings.Join([]string{
+			stripTrailingComma(closing) + ",",
+			from.indent + `"` + devName + `": {`,
+			entryIndent + entryText,
+			from.indent + "}" + suffix,
+		}, in.newline)
+		return replaceManifestLine(in.lines, from.close, replacement), true
+	}
+	if dev.compact {
+		return manifestLineEdit{}, false
+	}
+	devEntryIndent := dev.indent + unit
+	if dev.inline {
+		header := in.lines[dev.header]
+		closing := strings.TrimPrefix(strings.TrimSpace(header[strings.LastIndex(header, "{")+1:]), "}")
+		replacement := strings.Join([]string{
+			dev.indent + `"` + devName + `": {`,
+			devEntryIndent + entryText,
+			dev.indent + "}" + closing,
+		}, in.newline)
+		return replaceManifestLine(in.lines, dev.header, replacement), true
+	}
+
+	entries := jsonManifestEntries(in.lines, depths, dev)
+	if len(entries) == 0 {
+		return replaceManifestLine(in.lines, dev.header, in.lines[dev.header]+in.newline+devEntryIndent+entryText), true
+	}
+	devEntryIndent = leadingWhitespace(in.lines[entries[0].index])
+	for _, existing := range entries {
+		if sameName(existing.key, entry.key) {
+			return manifestLineEdit{}, false
+		}
+		if existing.key > entry.key {
+			return replaceManifestLine(in.lines, existing.index, devEntryIndent+entryText+","+in.newline+in.lines[existing.index]), true
+		}
+	}
+	last := entries[len(entries)-1].index
+	return replaceManifestLine(in.lines, last, stripTrailingComma(in.lines[last])+","+in.newline+devEntryIndent+entryText), true
+}
+
+func findJSONManifestSection(lines []string, depths []int, name string) (jsonManifestSection, bool) {
+	for index, line := range lines {
+		if depths[index] != 1 {
+			continue
+		}
+		match := jsonManifestSectionPattern.FindStringSubmatch(line)
+		if match == nil || match[2] != name {
+			continue
+		}
+		section := jsonManifestSection{name: name, header: index, indent: match[1]}
+		rest := strings.TrimSpace(match[3])
+		switch {
+		case rest == "":
+			section.close = findJSONSectionClose(lines, depths, index)
+		case rest == "}" || rest == "},":
+			section.inline = true
+			section.close = index
+		default:
+			section.compact = true
+			section.close = index
+		}
+		return section, true
+	}
+	return jsonManifestSection{}, false
+}
+
+func findJSONSectionClose(lines []string, depths []int, header int) int {
+	for index := header + 1; index < len(lines); index++ {
+		if depths[index+1] <= 1 {
+			return index
+		}
+	}
+	return len(lines) - 1
+}
+
+func findJSONManifestEntry(lines []string, depths []int, section jsonManifestSection, dependency string, sameName func(string, string) bool) (jsonManifestEntry, bool, bool) {
+	if section.inline {
+		return jsonManifestEntry{}, false, false
+	}
+	for _, entry := range jsonManifestEntries(lines, depths, section) {
+		if !sameName(entry.key, dependency) {
+			continue
+		}
+		return entry, true, depths[entry.index+1] == depths[entry.index]
+	}
+	return jsonManifestEntry{}, false, false
+}
+
+func jsonManifestEntries(lines []string, depths []int, section jsonManifestSection) []jsonManifestEntry {
+	entries := make([]jsonManifestEntry, 0)
+	for index := section.header + 1; index < section.close; index++ {
+		if depths[index] != 2 {
+			continue
+		}
+		match := jsonManifestEntryPattern.FindStringSubmatch(lines[index])
+		if match == nil {
+			continue
+		}
+		entries = append(entries, jsonManifestEntry{index: index, key: match[2]})
+	}
+	return entries
+}
+
+// jsonLineDepths returns the object/array nesting depth at the start of every
+// line, with one trailing element for the depth after the final line.
+func jsonLineDepths(lines []string) []int {
+	depths := make([]int, len(lines)+1)
+	depth := 0
+	inString := false
+	escaped := false
+	for index, line := range lines {
+		depths[index] = depth
+		for i := 0; i < len(line); i++ {
+			ch := line[i]
+			if inString {
+				switch {
+				case escaped:
+					escaped = false
+				case ch == '\\':
+					escaped = true
+				case ch == '"':
+					inString = false
+				}
+				continue
+			}
+			switch ch {
+			case '"':
+				inString = true
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+	}
+	depths[len(lines)] = depth
+	return depths
+}
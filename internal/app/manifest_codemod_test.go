@@ -0,0 +1,390 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+const unusedDependencyRecommendation = "remove-unused-dependency"
+
+func applyManifestEditorForTest(t *testing.T, plan func(manifestEditInput) manifestEditOutcome, content, dependency, action string) (string, manifestEditOutcome) {
+	t.Helper()
+	outcome := plan(manifestEditInput{
+		lines:      manifestLineTexts(content),
+		dependency: dependency,
+		action:     action,
+		newline:    detectManifestNewline(content),
+	})
+	if !outcome.found {
+		t.Fatalf("expected %s to be found in manifest", dependency)
+	}
+	if outcome.skipReason != "" {
+		return content, outcome
+	}
+	suggestions := make([]report.CodemodSuggestion, 0, len(outcome.edits))
+	for _, edit := range outcome.edits {
+		suggestions = append(suggestions, report.CodemodSuggestion{
+			File:        "manifest",
+			Line:        edit.line,
+			Original:    edit.original,
+			Replacement: edit.replacement,
+			DeleteLine:  edit.deleteLine,
+		})
+	}
+	updated, err := applySuggestionsToContent(content, suggestions)
+	if err != nil {
+		t.Fatalf("apply manifest edits: %v", err)
+	}
+	return updated, outcome
+}
+
+func assertManifestContent(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("unexpected manifest content\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestPackageJSONManifestEditRemovesLastEntryAndTrailingComma(t *testing.T) {
+	content := "{\n  \"name\": \"demo\",\n  \"dependencies\": {\n    \"express\": \"^4.0.0\",\n    \"lodash\": \"^4.17.21\"\n  }\n}\n"
+	got, outcome := applyManifestEditorForTest(t, planPackageJSONEdit, content, "lodash", ManifestActionRemove)
+	assertManifestContent(t, got, "{\n  \"name\": \"demo\",\n  \"dependencies\": {\n    \"express\": \"^4.0.0\"\n  }\n}\n")
+	if outcome.fromSection != "dependencies" || outcome.toSection != "" {
+		t.Fatalf("unexpected sections: %#v", outcome)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("expected valid JSON after removal: %v", err)
+	}
+}
+
+func TestPackageJSONManifestEditDemotesIntoSortedDevSection(t *testing.T) {
+	content := "{\n  \"dependencies\": {\n    \"lodash\": \"^4.17.21\",\n    \"react\": \"^18.0.0\"\n  },\n  \"devDependencies\": {\n    \"eslint\": \"^9.0.0\",\n    \"vitest\": \"^1.0.0\"\n  }\n}\n"
+	got, outcome := applyManifestEditorForTest(t, planPackageJSONEdit, content, "lodash", ManifestActionDemote)
+	assertManifestContent(t, got, "{\n  \"dependencies\": {\n    \"react\": \"^18.0.0\"\n  },\n  \"devDependencies\": {\n    \"eslint\": \"^9.0.0\",\n    \"lodash\": \"^4.17.21\",\n    \"vitest\": \"^1.0.0\"\n  }\n}\n")
+	if outcome.toSection != "devDependencies" {
+		t.Fatalf("expected devDependencies target, got %#v", outcome)
+	}
+}
+
+func TestPackageJSONManifestEditCreatesDevSectionWithCRLF(t *testing.T) {
+	content := "{\r\n  \"dependencies\": {\r\n    \"lodash\": \"^4.17.21\"\r\n  }\r\n}\r\n"
+	got, _ := applyManifestEditorForTest(t, planPackageJSONEdit, content, "lodash", ManifestActionDemote)
+	assertManifestContent(t, got, "{\r\n  \"dependencies\": {\r\n  },\r\n  \"devDependencies\": {\r\n    \"lodash\": \"^4.17.21\"\r\n  }\r\n}\r\n")
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("expected valid JSON after demotion: %v", err)
+	}
+}
+
+func TestPackageJSONManifestEditSkipsUnsupportedLayouts(t *testing.T) {
+	compact := "{\n  \"dependencies\": {\"lodash\": \"^4\"}\n}\n"
+	_, outcome := applyManifestEditorForTest(t, planPackageJSONEdit, compact, "lodash", ManifestActionRemove)
+	if outcome.skipReason != manifestCodemodReasonLayoutUnsupported {
+		t.Fatalf("expected compact layout skip, got %#v", outcome)
+	}
+
+	dev := "{\n  \"devDependencies\": {\n    \"lodash\": \"^4\"\n  }\n}\n"
+	_, outcome = applyManifestEditorForTest(t, planPackageJSONEdit, dev, "lodash", ManifestActionDemote)
+	if outcome.skipReason != manifestCodemodReasonAlreadyDev {
+		t.Fatalf("expected already-dev skip, got %#v", outcome)
+	}
+}
+
+func TestComposerJSONManifestEditRemovesRequireEntry(t *testing.T) {
+	content := "{\n    \"require\": {\n        \"php\": \">=8.2\",\n        \"Monolog/Monolog\": \"^3.0\"\n    },\n    \"require-dev\": {}\n}\n"
+	got, _ := applyManifestEditorForTest(t, planComposerJSONEdit, content, "monolog/monolog", ManifestActionDemote)
+	assertManifestContent(t, got, "{\n    \"require\": {\n        \"php\": \">=8.2\"\n    },\n    \"require-dev\": {\n        \"Monolog/Monolog\": \"^3.0\"\n    }\n}\n")
+}
+
+func TestCargoManifestEditRemovesAndDemotesEntries(t *testing.T) {
+	content := "[package]\nname = \"demo\"\n\n[dependencies]\nserde = { version = \"1\", features = [\"derive\"] }\nregex = \"1\"\n"
+	got, _ := applyManifestEditorForTest(t, planCargoTomlEdit, content, "serde", ManifestActionRemove)
+	assertManifestContent(t, got, "[package]\nname = \"demo\"\n\n[dependencies]\nregex = \"1\"\n")
+
+	got, outcome := applyManifestEditorForTest(t, planCargoTomlEdit, content, "regex", ManifestActionDemote)
+	assertManifestContent(t, got, "[package]\nname = \"demo\"\n\n[dependencies]\nserde = { version = \"1\", features = [\"derive\"] }\n\n[dev-dependencies]\nregex = \"1\"\n")
+	if outcome.toSection != cargoDevDependenciesSection {
+		t.Fatalf("expected dev-dependencies target, got %#v", outcome)
+	}
+}
+
+func TestCargoManifestEditHandlesSubtablesAndExistingDevSection(t *testing.T) {
+	content := "[dependencies.tokio_util]\nversion = \"0.7\"\nfeatures = [\"io\"]\n\n[dependencies]\nanyhow = \"1\"\n\n[dev-dependencies]\ninsta = \"1\"\n"
+	got, _ := applyManifestEditorForTest(t, planCargoTomlEdit, content, "tokio-util", ManifestActionDemote)
+	assertManifestContent(t, got, "[dev-dependencies.tokio_util]\nversion = \"0.7\"\nfeatures = [\"io\"]\n\n[dependencies]\nanyhow = \"1\"\n\n[dev-dependencies]\ninsta = \"1\"\n")
+
+	got, _ = applyManifestEditorForTest(t, planCargoTomlEdit, content, "tokio-util", ManifestActionRemove)
+	assertManifestContent(t, got, "\n[dependencies]\nanyhow = \"1\"\n\n[dev-dependencies]\ninsta = \"1\"\n")
+
+	got, _ = applyManifestEditorForTest(t, planCargoTomlEdit, content, "anyhow", ManifestActionDemote)
+	assertManifestContent(t, got, "[dependencies.tokio_util]\nversion = \"0.7\"\nfeatures = [\"io\"]\n\n[dependencies]\n\n[dev-dependencies]\ninsta = \"1\"\nanyhow = \"1\"\n")
+}
+
+func TestCargoManifestEditSkipsMultilineInlineTables(t *testing.T) {
+	content := "[dependencies]\nserde = { version = \"1\",\n  features = [\"derive\"] }\n"
+	_, outcome := applyManifestEditorForTest(t, planCargoTomlEdit, content, "serde", ManifestActionRemove)
+	if outcome.skipReason != manifestCodemodReasonLayoutUnsupported {
+		t.Fatalf("expected layout skip, got %#v", outcome)
+	}
+}
+
+func TestCargoManifestEditSkipsOptionalAndFeatureReferencedDependencies(t *testing.T) {
+	content := "[dependencies]\nserde = { version = \"1\", optional = true }\nregex = \"1\"\nanyhow = \"1\"\n\n[dependencies.tokio]\nversion = \"1\"\noptional = true\n\n[features]\ndefault = [\"serde/std\"]\nextra = [\"dep:serde\", \"regex?/unicode\"]\n"
+	for _, dependency := range []string{"serde", "regex", "tokio"} {
+		for _, action := range []string{ManifestActionRemove, ManifestActionDemote} {
+			_, outcome := applyManifestEditorForTest(t, planCargoTomlEdit, content, dependency, action)
+			if outcome.skipReason != manifestCodemodReasonOptional {
+				t.Fatalf("expected %s %s to be skipped as optional, got %#v", action, dependency, outcome)
+			}
+		}
+	}
+
+	got, _ := applyManifestEditorForTest(t, planCargoTomlEdit, content, "anyhow", ManifestActionRemove)
+	assertManifestContent(t, got, strings.Replace(content, "anyhow = \"1\"\n", "", 1))
+}
+
+func TestPyprojectManifestEditHandlesPEP621Arrays(t *testing.T) {
+	content := "[project]\nname = \"demo\"\ndependencies = [\n    \"requests>=2.31\",\n    \"Typing_Extensions; python_version < '3.11'\",\n]\n"
+	got, _ := applyManifestEditorForTest(t, planPyprojectEdit, content, "typing-extensions", ManifestActionRemove)
+	assertManifestContent(t, got, "[project]\nname = \"demo\"\ndependencies = [\n    \"requests>=2.31\",\n]\n")
+
+	got, outcome := applyManifestEditorForTest(t, planPyprojectEdit, content, "requests", ManifestActionDemote)
+	assertManifestContent(t, got, "[project]\nname = \"demo\"\ndependencies = [\n    \"Typing_Extensions; python_version < '3.11'\",\n]\n\n[dependency-groups]\ndev = [\"requests>=2.31\"]\n")
+	if outcome.toSection != "dependency-groups.dev" {
+		t.Fatalf("expected dependency-groups.dev target, got %#v", outcome)
+	}
+
+	inline := "[project]\ndependencies = [\"requests\", \"rich>=13\"]\n\n[dependency-groups]\ndev = [\"pytest\"]\n"
+	got, _ = applyManifestEditorForTest(t, planPyprojectEdit, inline, "rich", ManifestActionDemote)
+	assertManifestContent(t, got, "[project]\ndependencies = [\"requests\"]\n\n[dependency-groups]\ndev = [\"pytest\", \"rich>=13\"]\n")
+}
+
+func TestPyprojectManifestEditDemotesPoetryDependency(t *testing.T) {
+	content := "[tool.poetry.dependencies]\npython = \"^3.11\"\nrequests = \"^2.31\"\n\n[tool.poetry.group.dev.dependencies]\npytest = \"^8.0\"\n"
+	got, outcome := applyManifestEditorForTest(t, planPyprojectEdit, content, "requests", ManifestActionDemote)
+	assertManifestContent(t, got, "[tool.poetry.dependencies]\npython = \"^3.11\"\n\n[tool.poetry.group.dev.dependencies]\npytest = \"^8.0\"\nrequests = \"^2.31\"\n")
+	if outcome.toSection != poetryDevGroupSection {
+		t.Fatalf("expected poetry dev group target, got %#v", outcome)
+	}
+
+	outcome = planPyprojectEdit(manifestEditInput{lines: manifestLineTexts(content), dependency: "python", action: ManifestActionRemove, newline: "\n"})
+	if outcome.found {
+		t.Fatalf("expected python interpreter constraint to be ignored, got %#v", outcome)
+	}
+}
+
+func TestPyprojectManifestEditSkipsPoetryExtras(t *testing.T) {
+	content := "[tool.poetry.dependencies]\npython = \"^3.11\"\npsycopg = { version = \"^3.1\", optional = true }\nrich = \"^13\"\nrequests = \"^2.31\"\n\n[tool.poetry.extras]\npretty = [\"Rich\"]\n"
+	for _, dependency := range []string{"psycopg", "rich"} {
+		_, outcome := applyManifestEditorForTest(t, planPyprojectEdit, content, dependency, ManifestActionRemove)
+		if outcome.skipReason != manifestCodemodReasonOptional {
+			t.Fatalf("expected %s to be skipped as an extra, got %#v", dependency, outcome)
+		}
+	}
+
+	got, _ := applyManifestEditorForTest(t, planPyprojectEdit, content, "requests", ManifestActionRemove)
+	assertManifestContent(t, got, strings.Replace(content, "requests = \"^2.31\"\n", "", 1))
+}
+
+func TestGoModManifestEditRemovesRequirementAndRejectsDemote(t *testing.T) {
+	content := "module example.com/demo\n\ngo 1.22\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/sync v0.7.0 // indirect\n)\n\nrequire github.com/google/uuid v1.6.0\n"
+	got, _ := applyManifestEditorForTest(t, planGoModEdit, content, "golang.org/x/sync", ManifestActionRemove)
+	assertManifestContent(t, got, "module example.com/demo\n\ngo 1.22\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n)\n\nrequire github.com/google/uuid v1.6.0\n")
+
+	got, _ = applyManifestEditorForTest(t, planGoModEdit, content, "github.com/google/uuid", ManifestActionRemove)
+	assertManifestContent(t, got, "module example.com/demo\n\ngo 1.22\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/sync v0.7.0 // indirect\n)\n\n")
+
+	_, outcome := applyManifestEditorForTest(t, planGoModEdit, content, "github.com/pkg/errors", ManifestActionDemote)
+	if outcome.skipReason != manifestCodemodReasonDemoteUnsupported {
+		t.Fatalf("expected demote-unsupported skip, got %#v", outcome)
+	}
+}
+
+func TestGemfileManifestEditDemotesIntoDevelopmentGroup(t *testing.T) {
+	content := "source \"https://rubygems.org\"\n\ngem \"rails\", \"~> 7.1\"\ngem 'pry'\n\ngroup :development, :test do\n  gem \"rspec\"\nend\n"
+	got, outcome := applyManifestEditorForTest(t, planGemfileEdit, content, "pry", ManifestActionDemote)
+	assertManifestContent(t, got, "source \"https://rubygems.org\"\n\ngem \"rails\", \"~> 7.1\"\n\ngroup :development, :test do\n  gem 'pry'\n  gem \"rspec\"\nend\n")
+	if outcome.toSection != "group :development" {
+		t.Fatalf("expected development group target, got %#v", outcome)
+	}
+
+	_, outcome = applyManifestEditorForTest(t, planGemfileEdit, content, "rspec", ManifestActionDemote)
+	if outcome.skipReason != manifestCodemodReasonAlreadyDev {
+		t.Fatalf("expected already-dev skip, got %#v", outcome)
+	}
+
+	got, _ = applyManifestEditorForTest(t, planGemfileEdit, "gem \"rails\"\ngem \"pry\"\n", "pry", ManifestActionDemote)
+	assertManifestContent(t, got, "gem \"rails\"\n\ngroup :development do\n  gem \"pry\"\nend\n")
+}
+
+func manifestCodemodReport(language, dependency string, unusedRoots ...string) report.Report {
+	if len(unusedRoots) == 0 {
+		unusedRoots = []string{"."}
+	}
+	return report.Report{
+		Dependencies: []report.DependencyReport{{
+			Language:        language,
+			Name:            dependency,
+			Recommendations: []report.Recommendation{{Code: unusedDependencyRecommendation}},
+			UnusedRoots:     unusedRoots,
+		}},
+	}
+}
+
+func manifestCodemodRequest(dependency string) AnalyseRequest {
+	return AnalyseRequest{
+		Dependency:               dependency,
+		RemoveUnusedDependencies: true,
+		ManifestAction:           ManifestActionRemove,
+		Features:                 featureflags.Set{},
+	}
+}
+
+func TestApplyManifestCodemodIfNeededAppliesWithRollbackAndLockfileRefresh(t *testing.T) {
+	repo := t.TempDir()
+	manifestPath := filepath.Join(repo, "packages", "web", "package.json")
+	mustMkdirAll(t, filepath.Dir(manifestPath))
+	original := "{\n  \"dependencies\": {\n    \"left-pad\": \"^1.3.0\",\n    \"react\": \"^18.0.0\"\n  }\n}\n"
+	writeTextFile(t, manifestPath, original, 0o644)
+	writeTextFile(t, filepath.Join(repo, "packages", "web", "pnpm-lock.yaml"), "lockfileVersion: '9.0'\n", 0o644)
+	writeTextFile(t, filepath.Join(repo, "node_modules-package.json"), "{}\n", 0o644)
+
+	req := manifestCodemodRequest("left-pad")
+	req.ApplyCodemod = true
+	reportData, err := applyManifestCodemodIfNeeded(context.Background(), manifestCodemodReport("js-ts", "left-pad", "packages/web"), repo, req)
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	codemod := reportData.Dependencies[0].Codemod
+	if codemod == nil || len(codemod.Suggestions) != 1 {
+		t.Fatalf("expected one manifest suggestion, got %#v", codemod)
+	}
+	suggestion := codemod.Suggestions[0]
+	if suggestion.File != "packages/web/package.json" || !suggestion.DeleteLine || !strings.Contains(suggestion.Patch, "-    \"left-pad\": \"^1.3.0\",") {
+		t.Fatalf("unexpected manifest suggestion: %#v", suggestion)
+	}
+	if len(codemod.LockfileRefresh) != 1 || codemod.LockfileRefresh[0].Command != "cd packages/web && pnpm install" {
+		t.Fatalf("unexpected lockfile refresh: %#v", codemod.LockfileRefresh)
+	}
+
+	reportData, err = applyCodemodIfNeeded(context.Background(), reportData, repo, req, time.Date(2026, time.March, 13, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("apply manifest codemod: %v", err)
+	}
+	reportData = appendManifestLockfileRefreshWarnings(reportData, req)
+
+	if got := readTextFile(t, manifestPath); got != "{\n  \"dependencies\": {\n    \"react\": \"^18.0.0\"\n  }\n}\n" {
+		t.Fatalf("unexpected manifest after apply: %q", got)
+	}
+	applyReport := requireCodemodApplyReport(t, reportData)
+	assertRollbackArtifact(t, repo, applyReport.BackupPath, "left-pad", original)
+	if !hasWarningContaining(reportData.Warnings, "refresh npm lockfile after editing packages/web/package.json: cd packages/web && pnpm install") {
+		t.Fatalf("expected lockfile refresh warning, got %#v", reportData.Warnings)
+	}
+}
+
+func TestApplyManifestCodemodIfNeededEditsOnlyRootsReportingUnused(t *testing.T) {
+	repo := t.TempDir()
+	manifest := "{\n  \"dependencies\": {\n    \"left-pad\": \"^1.3.0\"\n  }\n}\n"
+	for _, dir := range []string{"packages/web app", "packages/api", "packages/web app/nested"} {
+		mustMkdirAll(t, filepath.Join(repo, filepath.FromSlash(dir)))
+		writeTextFile(t, filepath.Join(repo, filepath.FromSlash(dir), "package.json"), manifest, 0o644)
+	}
+	writeTextFile(t, filepath.Join(repo, "packages", "web app", "pnpm-lock.yaml"), "lockfileVersion: '9.0'\n", 0o644)
+
+	reportData, err := applyManifestCodemodIfNeeded(context.Background(), manifestCodemodReport("js-ts", "left-pad", "packages/web app"), repo, manifestCodemodRequest("left-pad"))
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	codemod := reportData.Dependencies[0].Codemod
+	if codemod == nil || len(codemod.Suggestions) != 1 || codemod.Suggestions[0].File != "packages/web app/package.json" {
+		t.Fatalf("expected only the unused root's manifest to be edited, got %#v", codemod)
+	}
+	if len(codemod.LockfileRefresh) != 1 || codemod.LockfileRefresh[0].Command != "cd 'packages/web app' && pnpm install" {
+		t.Fatalf("expected a quoted lockfile refresh command, got %#v", codemod.LockfileRefresh)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	cases := map[string]string{
+		"packages/web":   "packages/web",
+		"web app":        "'web app'",
+		"a;rm -rf x":     "'a;rm -rf x'",
+		"it's":           `'it'\''s'`,
+		"$(touch pwned)": "'$(touch pwned)'",
+	}
+	for input, want := range cases {
+		if got := shellQuote(input); got != want {
+			t.Fatalf("shellQuote(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestApplyManifestCodemodIfNeededWarnsWhenNotApplicable(t *testing.T) {
+	repo := t.TempDir()
+	writeTextFile(t, filepath.Join(repo, "package.json"), "{\n  \"dependencies\": {\n    \"react\": \"^18.0.0\"\n  }\n}\n", 0o644)
+
+	used := manifestCodemodReport("js-ts", "react")
+	used.Dependencies[0].Recommendations = nil
+	reportData, err := applyManifestCodemodIfNeeded(context.Background(), used, repo, manifestCodemodRequest("react"))
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	if reportData.Dependencies[0].Codemod != nil || !hasWarningContaining(reportData.Warnings, "not confirmed unused") {
+		t.Fatalf("expected in-use dependency to be skipped, got %#v", reportData)
+	}
+
+	reportData, err = applyManifestCodemodIfNeeded(context.Background(), manifestCodemodReport("js-ts", "left-pad"), repo, manifestCodemodRequest("left-pad"))
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	if !hasWarningContaining(reportData.Warnings, "found no manifest entry for left-pad") {
+		t.Fatalf("expected missing manifest entry warning, got %#v", reportData.Warnings)
+	}
+
+	unrooted := manifestCodemodReport("js-ts", "left-pad")
+	unrooted.Dependencies[0].UnusedRoots = nil
+	reportData, err = applyManifestCodemodIfNeeded(context.Background(), unrooted, repo, manifestCodemodRequest("left-pad"))
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	if reportData.Dependencies[0].Codemod != nil || !hasWarningContaining(reportData.Warnings, "no analysed root reported it unused") {
+		t.Fatalf("expected dependency without an unused root to be skipped, got %#v", reportData)
+	}
+
+	reportData, err = applyManifestCodemodIfNeeded(context.Background(), manifestCodemodReport("jvm", "guava"), repo, manifestCodemodRequest("guava"))
+	if err != nil {
+		t.Fatalf("plan manifest codemod: %v", err)
+	}
+	if !hasWarningContaining(reportData.Warnings, "jvm manifests are not supported") {
+		t.Fatalf("expected unsupported language warning, got %#v", reportData.Warnings)
+	}
+}
+
+func TestValidateManifestCodemodFeatures(t *testing.T) {
+	req := manifestCodemodRequest("lodash")
+	if err := validateManifestCodemodFeatures(req); err == nil || !strings.Contains(err.Error(), ManifestCodemodPreviewFeature) {
+		t.Fatalf("expected preview gate error, got %v", err)
+	}
+	req.Features = mustResolveAppTestFeatures(t, ManifestCodemodPreviewFeature)
+	if err := validateManifestCodemodFeatures(req); err != nil {
+		t.Fatalf("expected enabled preview to pass, got %v", err)
+	}
+}
+
+func hasWarningContaining(warnings []string, want string) bool {
+	for _, warning := range warnings {
+		if strings.Contains(warning, want) {
+			return true
+		}
+	}
+	return false
+}
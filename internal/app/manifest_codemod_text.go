@@ -0,0 +1,147 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+)
+
+const gemfileDevelopmentGroup = "group :development do"
+
+var (
+	gemfileEntryPattern      = regexp.MustCompile(`^\s*gem\s*\(?\s*["']([^"']+)["']`)
+	gemfileBlockOpenPattern  = regexp.MustCompile(`\bdo\s*(\|[^|]*\|)?\s*(#.*)?$`)
+	gemfileBlockEndPattern   = regexp.MustCompile(`^\s*end\b`)
+	gemfileGroupPattern      = regexp.MustCompile(`^\s*group\b`)
+	gemfileDevGroupPattern   = regexp.MustCompile(`:development\b|["']development["']`)
+	gemfileDevOrTestPattern  = regexp.MustCompile(`:(development|test)\b|["'](development|test)["']`)
+	gemfileInlineGroupOption = regexp.MustCompile(`\bgroups?\s*(:|=>)`)
+)
+
+func planGoModEdit(in manifestEditInput) manifestEditOutcome {
+	inBlock := false
+	for index, line := range in.lines {
+		fields := strings.Fields(stripGoModComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case !inBlock && fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		}
+
+		module := ""
+		if inBlock {
+			module = fields[0]
+		} else if fields[0] == "require" && len(fields) >= 3 {
+			module = fields[1]
+		}
+		if module != in.dependency {
+			continue
+		}
+		if in.action == ManifestActionDemote {
+			return manifestDemoteUnsupportedSkip(index, "require")
+		}
+		return manifestEditOutcome{
+			found:       true,
+			fromSection: "require",
+			edits:       []manifestLineEdit{deleteManifestLine(in.lines, index)},
+		}
+	}
+	return manifestEditOutcome{}
+}
+
+func stripGoModComment(line string) string {
+	if index := strings.Index(line, "//"); index >= 0 {
+		return line[:index]
+	}
+	return line
+}
+
+type gemfileBlock struct {
+	dev bool
+}
+
+func planGemfileEdit(in manifestEditInput) manifestEditOutcome {
+	stack := make([]gemfileBlock, 0)
+	devGroupHeader := -1
+	for index, line := range in.lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if match := gemfileEntryPattern.FindStringSubmatch(line); match != nil && match[1] == in.dependency {
+			return planGemfileEntryEdit(in, index, gemfileInDevBlock(stack) || gemfileInlineDevGroup(line), devGroupHeader)
+		}
+		switch {
+		case gemfileBlockEndPattern.MatchString(line):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case gemfileBlockOpenPattern.MatchString(trimmed):
+			group := gemfileGroupPattern.MatchString(line)
+			stack = append(stack, gemfileBlock{dev: group && gemfileDevOrTestPattern.MatchString(line)})
+			if group && devGroupHeader < 0 && gemfileDevGroupPattern.MatchString(line) {
+				devGroupHeader = index
+			}
+		}
+	}
+	return manifestEditOutcome{}
+}
+
+func planGemfileEntryEdit(in manifestEditInput, index int, dev bool, devGroupHeader int) manifestEditOutcome {
+	line := in.lines[index]
+	trimmed := strings.TrimSpace(line)
+	if hasTrailingComma(trimmed) || strings.HasSuffix(trimmed, `\`) || gemfileBlockOpenPattern.MatchString(trimmed) {
+		return manifestLayoutSkip(index, "Gemfile", "multi-line gem declarations are not rewritten")
+	}
+	if dev && in.action == ManifestActionDemote {
+		return manifestAlreadyDevSkip(index, "Gemfile")
+	}
+
+	outcome := manifestEditOutcome{found: true, fromSection: "Gemfile"}
+	removal := deleteManifestLine(in.lines, index)
+	if in.action != ManifestActionDemote {
+		outcome.edits = []manifestLineEdit{removal}
+		return outcome
+	}
+
+	outcome.toSection = "group :development"
+	if devGroupHeader < 0 {
+		devGroupHeader = findGemfileDevGroupAfter(in.lines, index)
+	}
+	if devGroupHeader < 0 {
+		outcome.edits = appendManifestBlock(in.lines, removal, []string{gemfileDevelopmentGroup, "  " + trimmed, "end"}, in.newline)
+		return outcome
+	}
+	header := in.lines[devGroupHeader]
+	indent := leadingWhitespace(header) + "  "
+	outcome.edits = []manifestLineEdit{removal, replaceManifestLine(in.lines, devGroupHeader, header+in.newline+indent+trimmed)}
+	return outcome
+}
+
+func findGemfileDevGroupAfter(lines []string, start int) int {
+	for index := start + 1; index < len(lines); index++ {
+		line := lines[index]
+		if gemfileGroupPattern.MatchString(line) && gemfileDevGroupPattern.MatchString(line) && gemfileBlockOpenPattern.MatchString(strings.TrimSpace(line)) {
+			return index
+		}
+	}
+	return -1
+}
+
+func gemfileInDevBlock(stack []gemfileBlock) bool {
+	for _, block := range stack {
+		if block.dev {
+			return true
+		}
+	}
+	return false
+}
+
+func gemfileInlineDevGroup(line string) bool {
+	return gemfileInlineGroupOption.MatchString(line) && gemfileDevOrTestPattern.MatchString(line)
+}
@@ -0,0 +1,570 @@
+package app
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	cargoDependenciesSection    = "dependencies"
+	cargoDevDependenciesSection = "dev-dependencies"
+	cargoFeaturesSection        = "features"
+	poetryDependenciesSection   = "tool.poetry.dependencies"
+	poetryDevGroupSection       = "tool.poetry.group.dev.dependencies"
+	poetryLegacyDevSection      = "tool.poetry.dev-dependencies"
+	poetryExtrasSection         = "tool.poetry.extras"
+	pep621ProjectSection        = "project"
+	pep621OptionalSection       = "project.optional-dependencies"
+	pep735GroupsSection         = "dependency-groups"
+	pep735DevGroup              = "dev"
+)
+
+var (
+	tomlManifestHeaderPattern  = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	tomlManifestKeyPattern     = regexp.MustCompile(`^\s*(?:"([^"]+)"|'([^']+)'|([A-Za-z0-9_.-]+))\s*=\s*(.*)$`)
+	tomlManifestStringPattern  = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|'([^']*)'`)
+	tomlOptionalTruePattern    = regexp.MustCompile(`(?:^|[{,\s])optional\s*=\s*true\b`)
+	pythonRequirementNameRegex = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pythonNameSeparatorPattern = regexp.MustCompile(`[-_.]+`)
+)
+
+type tomlManifestSection struct {
+	name   string
+	header int
+	start  int
+	end    int
+}
+
+type tomlManifestEntry struct {
+	index int
+	key   string
+	value string
+}
+
+func planCargoTomlEdit(in manifestEditInput) manifestEditOutcome {
+	sameName := func(a, b string) bool { return normalizeCargoName(a) == normalizeCargoName(b) }
+	sections := parseTOMLManifestSections(in.lines)
+	return planTOMLTableManifestEdit(in, sections, tomlTableLayout{
+		prodSections:  []string{cargoDependenciesSection, "build-dependencies"},
+		devSections:   []string{cargoDevDependenciesSection},
+		devTarget:     cargoDevDependenciesSection,
+		demotableFrom: cargoDependenciesSection,
+		featureTable:  cargoFeaturesSection,
+		sameName:      sameName,
+		extraProd:     isCargoTargetDependencySection,
+	})
+}
+
+type tomlTableLayout struct {
+	prodSections  []string
+	devSections   []string
+	devTarget     string
+	demotableFrom string
+	sameName      func(string, string) bool
+	extraProd     func(string) bool
+	extraDev      func(string) bool
+	skipKeys      map[string]struct{}
+	// featureTable names the table whose values may reference optional
+	// dependencies: Cargo [features] or Poetry extras.
+	featureTable string
+}
+
+func (l tomlTableLayout) isDev(name string) bool {
+	return slices.Contains(l.devSections, name) || (l.extraDev != nil && l.extraDev(name))
+}
+
+func (l tomlTableLayout) isProd(name string) bool {
+	return slices.Contains(l.prodSections, name) || (l.extraProd != nil && l.extraProd(name))
+}
+
+func planTOMLTableManifestEdit(in manifestEditInput, sections []tomlManifestSection, layout tomlTableLayout) manifestEditOutcome {
+	for _, section := range sections {
+		table, key, ok := splitTOMLDependencySubtable(section.name, layout)
+		if ok && layout.sameName(key, in.dependency) {
+			if skip, optional := tomlOptionalDependencySkip(in.lines, sections, section.header, table, layout, in.dependency, tomlSubtableOptional(in.lines, section)); optional {
+				return skip
+			}
+			return planTOMLSubtableEdit(in, section, table, layout)
+		}
+	}
+	for _, section := range sections {
+		dev := layout.isDev(section.name)
+		if !dev && !layout.isProd(section.name) {
+			continue
+		}
+		entry, ok := findTOMLManifestEntry(in.lines, section, in.dependency, layout.sameName, layout.skipKeys)
+		if !ok {
+			continue
+		}
+		return planTOMLInlineEdit(in, sections, section, entry, layout, dev)
+	}
+	return manifestEditOutcome{}
+}
+
+func planTOMLSubtableEdit(in manifestEditInput, section tomlManifestSection, table string, layout tomlTableLayout) manifestEditOutcome {
+	dev := layout.isDev(table)
+	if dev && in.action == ManifestActionDemote {
+		return manifestAlreadyDevSkip(section.header, table)
+	}
+	outcome := manifestEditOutcome{found: true, fromSection: table}
+	if in.action == ManifestActionDemote {
+		if table != layout.demotableFrom {
+			return manifestDemoteUnsupportedSkip(section.header, table)
+		}
+		header := in.lines[section.header]
+		outcome.toSection = layout.devTarget
+		outcome.edits = []manifestLineEdit{replaceManifestLine(in.lines, section.header, strings.Replace(header, table+".", layout.devTarget+".", 1))}
+		return outcome
+	}
+	last := lastTOMLContentLine(in.lines, section)
+	for index := section.header; index <= last; index++ {
+		outcome.edits = append(outcome.edits, deleteManifestLine(in.lines, index))
+	}
+	return outcome
+}
+
+func planTOMLInlineEdit(in manifestEditInput, sections []tomlManifestSection, section tomlManifestSection, entry tomlManifestEntry, layout tomlTableLayout, dev bool) manifestEditOutcome {
+	if !tomlValueComplete(entry.value) {
+		return manifestLayoutSkip(entry.index, section.name, "multi-line dependency entries are not rewritten")
+	}
+	if skip, optional := tomlOptionalDependencySkip(in.lines, sections, entry.index, section.name, layout, in.dependency, tomlOptionalTruePattern.MatchString(entry.value)); optional {
+		return skip
+	}
+	if dev && in.action == ManifestActionDemote {
+		return manifestAlreadyDevSkip(entry.index, section.name)
+	}
+	outcome := manifestEditOutcome{found: true, fromSection: section.name}
+	if in.action != ManifestActionDemote {
+		outcome.edits = []manifestLineEdit{deleteManifestLine(in.lines, entry.index)}
+		return outcome
+	}
+	if section.name != layout.demotableFrom {
+		return manifestDemoteUnsupportedSkip(entry.index, section.name)
+	}
+
+	outcome.toSection = layout.devTarget
+	outcome.edits = insertTOMLTableEntry(in, sections, layout.devSections, layout.devTarget, entry.index, strings.TrimSpace(in.lines[entry.index]))
+	return outcome
+}
+
+// tomlOptionalDependencySkip skips optional dependencies and dependencies
+// named in the layout's feature table: removing or moving them would leave
+// feature references the package manager rejects.
+func tomlOptionalDependencySkip(lines []string, sections []tomlManifestSection, line int, section string, layout tomlTableLayout, dependency string, optional bool) (manifestEditOutcome, bool) {
+	if !optional && !tomlFeatureTableReferences(lines, sections, layout, dependency) {
+		return manifestEditOutcome{}, false
+	}
+	return manifestEditOutcome{
+		found:       true,
+		fromSection: section,
+		skipLine:    line + 1,
+		skipReason:  manifestCodemodReasonOptional,
+		skipMessage: "optional dependencies and dependencies referenced from [" + layout.featureTable + "] are not rewritten",
+	}, true
+}
+
+func tomlSubtableOptional(lines []string, section tomlManifestSection) bool {
+	for index := section.start; index < section.end; index++ {
+		match := tomlManifestKeyPattern.FindStringSubmatch(lines[index])
+		if match != nil && firstNonEmpty(match[1], match[2], match[3]) == "optional" && tomlOptionalTruePattern.MatchString("optional = "+match[4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// tomlFeatureTableReferences reports whether any value in the feature table
+// names dependency, including Cargo's "dep:name", "name/feature" and
+// "name?/feature" forms.
+func tomlFeatureTableReferences(lines []string, sections []tomlManifestSection, layout tomlTableLayout, dependency string) bool {
+	if layout.featureTable == "" {
+		return false
+	}
+	features, ok := findTOMLManifestSection(sections, layout.featureTable)
+	if !ok {
+		return false
+	}
+	for index := features.start; index < features.end; index++ {
+		for _, reference := range tomlStringLiterals(lines[index]) {
+			name, _, _ := strings.Cut(strings.TrimPrefix(reference, "dep:"), "/")
+			if layout.sameName(strings.TrimSuffix(name, "?"), dependency) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// insertTOMLTableEntry removes the entry at index and re-adds entryText to the
+// first existing development table, creating devTarget when none exists.
+func insertTOMLTableEntry(in manifestEditInput, sections []tomlManifestSection, devSections []string, devTarget string, index int, entryText string) []manifestLineEdit {
+	removal := deleteManifestLine(in.lines, index)
+	for _, name := range devSections {
+		dev, ok := findTOMLManifestSection(sections, name)
+		if !ok {
+			continue
+		}
+		last := lastTOMLContentLine(in.lines, dev)
+		return []manifestLineEdit{removal, replaceManifestLine(in.lines, last, in.lines[last]+in.newline+entryText)}
+	}
+	return appendManifestBlock(in.lines, removal, []string{"[" + devTarget + "]", entryText}, in.newline)
+}
+
+func planPyprojectEdit(in manifestEditInput) manifestEditOutcome {
+	sections := parseTOMLManifestSections(in.lines)
+	poetry := planTOMLTableManifestEdit(in, sections, tomlTableLayout{
+		prodSections:  []string{poetryDependenciesSection},
+		devSections:   []string{poetryDevGroupSection, poetryLegacyDevSection},
+		devTarget:     poetryDevGroupSection,
+		demotableFrom: poetryDependenciesSection,
+		featureTable:  poetryExtrasSection,
+		sameName:      samePythonName,
+		extraDev:      isPoetryGroupDependencySection,
+		skipKeys:      map[string]struct{}{"python": {}},
+	})
+	if poetry.found {
+		return poetry
+	}
+	return planPEP621Edit(in, sections)
+}
+
+func planPEP621Edit(in manifestEditInput, sections []tomlManifestSection) manifestEditOutcome {
+	for _, section := range sections {
+		for _, array := range pep621DependencyArrays(in.lines, section) {
+			index, ok, supported := findPEP621ArrayEntry(in.lines, array, in.dependency)
+			if !ok {
+				continue
+			}
+			label := array.label()
+			if !supported {
+				return manifestLayoutSkip(index, label, "dependency arrays mixing several requirements per line are not rewritten")
+			}
+			dev := array.section == pep735GroupsSection || array.section == pep621OptionalSection
+			if dev && in.action == ManifestActionDemote {
+				return manifestAlreadyDevSkip(index, label)
+			}
+			return planPEP621ArrayEdit(in, sections, array, index, label)
+		}
+	}
+	return manifestEditOutcome{}
+}
+
+func planPEP621ArrayEdit(in manifestEditInput, sections []tomlManifestSection, array tomlManifestArray, index int, label string) manifestEditOutcome {
+	outcome := manifestEditOutcome{found: true, fromSection: label}
+	removal := removePEP621ArrayEntry(in.lines, array, index, in.dependency)
+	if in.action != ManifestActionDemote {
+		outcome.edits = []manifestLineEdit{removal}
+		return outcome
+	}
+
+	requirement := pep621Requirement(in.lines[index], in.dependency)
+	outcome.toSection = pep735GroupsSection + "." + pep735DevGroup
+	groups, ok := findTOMLManifestSection(sections, pep735GroupsSection)
+	if !ok {
+		block := []string{"[" + pep735GroupsSection + "]", pep735DevGroup + " = [" + requirement + "]"}
+		outcome.edits = appendManifestBlock(in.lines, removal, block, in.newline)
+		return outcome
+	}
+
+	for _, array := range pep621DependencyArrays(in.lines, groups) {
+		if array.key != pep735DevGroup {
+			continue
+		}
+		outcome.edits = []manifestLineEdit{removal, insertPEP621ArrayEntry(in, array, requirement, leadingWhitespace(in.lines[index]))}
+		return outcome
+	}
+	header := in.lines[groups.header]
+	outcome.edits = []manifestLineEdit{removal, replaceManifestLine(in.lines, groups.header, header+in.newline+pep735DevGroup+" = ["+requirement+"]")}
+	return outcome
+}
+
+type tomlManifestArray struct {
+	section string
+	key     string
+	open    int
+	close   int
+}
+
+func (a tomlManifestArray) label() string {
+	if a.section == pep621ProjectSection {
+		return pep621ProjectSection + "." + a.key
+	}
+	return a.section + "." + a.key
+}
+
+func (a tomlManifestArray) singleLine() bool {
+	return a.open == a.close
+}
+
+func pep621DependencyArrays(lines []string, section tomlManifestSection) []tomlManifestArray {
+	switch section.name {
+	case pep621ProjectSection, pep621OptionalSection, pep735GroupsSection:
+	default:
+		return nil
+	}
+	arrays := make([]tomlManifestArray, 0)
+	for index := section.start; index < section.end; index++ {
+		match := tomlManifestKeyPattern.FindStringSubmatch(lines[index])
+		if match == nil || !strings.HasPrefix(strings.TrimSpace(match[4]), "[") {
+			continue
+		}
+		key := firstNonEmpty(match[1], match[2], match[3])
+		if section.name == pep621ProjectSection && key != "dependencies" {
+			continue
+		}
+		array := tomlManifestArray{section: section.name, key: key, open: index, close: index}
+		if !tomlValueComplete(match[4]) {
+			array.close = findTOMLArrayClose(lines, index, section.end)
+		}
+		arrays = append(arrays, array)
+		index = array.close
+	}
+	return arrays
+}
+
+func findTOMLArrayClose(lines []string, open, end int) int {
+	value := lines[open]
+	for index := open + 1; index < end; index++ {
+		value += "\n" + lines[index]
+		if tomlValueComplete(value[strings.Index(value, "=")+1:]) {
+			return index
+		}
+	}
+	return end - 1
+}
+
+func findPEP621ArrayEntry(lines []string, array tomlManifestArray, dependency string) (int, bool, bool) {
+	if array.singleLine() {
+		for _, requirement := range tomlStringLiterals(lines[array.open]) {
+			if samePythonName(pythonRequirementName(requirement), dependency) {
+				return array.open, true, true
+			}
+		}
+		return 0, false, false
+	}
+	for index := array.open + 1; index < array.close; index++ {
+		literals := tomlStringLiterals(lines[index])
+		for _, requirement := range literals {
+			if samePythonName(pythonRequirementName(requirement), dependency) {
+				return index, true, len(literals) == 1
+			}
+		}
+	}
+	return 0, false, false
+}
+
+func removePEP621ArrayEntry(lines []string, array tomlManifestArray, index int, dependency string) manifestLineEdit {
+	if !array.singleLine() {
+		return deleteManifestLine(lines, index)
+	}
+	line := lines[index]
+	open := strings.Index(line, "[")
+	closeIndex := strings.LastIndex(line, "]")
+	kept := make([]string, 0)
+	for _, match := range tomlManifestStringPattern.FindAllString(line[open:closeIndex], -1) {
+		if samePythonName(pythonRequirementName(unquoteTOMLString(match)), dependency) {
+			continue
+		}
+		kept = append(kept, match)
+	}
+	return replaceManifestLine(lines, index, line[:open+1]+strings.Join(kept, ", ")+line[closeIndex:])
+}
+
+func insertPEP621ArrayEntry(in manifestEditInput, array tomlManifestArray, requirement, indent string) manifestLineEdit {
+	line := in.lines[array.open]
+	if array.singleLine() {
+		closeIndex := strings.LastIndex(line, "]")
+		inner := strings.TrimSpace(line[strings.Index(line, "[")+1 : closeIndex])
+		separator := ", "
+		if inner == "" {
+			separator = ""
+		} else if strings.HasSuffix(inner, ",") {
+			separator = " "
+		}
+		return replaceManifestLine(in.lines, array.open, strings.TrimRight(line[:closeIndex], " ")+separator+requirement+line[closeIndex:])
+	}
+	return replaceManifestLine(in.lines, array.open, line+in.newline+indent+requirement+",")
+}
+
+func pep621Requirement(line, dependency string) string {
+	for _, match := range tomlManifestStringPattern.FindAllString(line, -1) {
+		if samePythonName(pythonRequirementName(unquoteTOMLString(match)), dependency) {
+			return match
+		}
+	}
+	return `"` + dependency + `"`
+}
+
+func parseTOMLManifestSections(lines []string) []tomlManifestSection {
+	sections := []tomlManifestSection{{header: -1, start: 0}}
+	for index, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "[[") {
+			sections[len(sections)-1].end = index
+			sections = append(sections, tomlManifestSection{header: index, start: index + 1})
+			continue
+		}
+		match := tomlManifestHeaderPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		sections[len(sections)-1].end = index
+		sections = append(sections, tomlManifestSection{name: normalizeTOMLTableName(match[1]), header: index, start: index + 1})
+	}
+	sections[len(sections)-1].end = len(lines)
+	return sections
+}
+
+func normalizeTOMLTableName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+func findTOMLManifestSection(sections []tomlManifestSection, name string) (tomlManifestSection, bool) {
+	for _, section := range sections {
+		if section.header >= 0 && section.name == name {
+			return section, true
+		}
+	}
+	return tomlManifestSection{}, false
+}
+
+func findTOMLManifestEntry(lines []string, section tomlManifestSection, dependency string, sameName func(string, string) bool, skipKeys map[string]struct{}) (tomlManifestEntry, bool) {
+	for index := section.start; index < section.end; index++ {
+		match := tomlManifestKeyPattern.FindStringSubmatch(lines[index])
+		if match == nil {
+			continue
+		}
+		key := firstNonEmpty(match[1], match[2], match[3])
+		if _, skip := skipKeys[strings.ToLower(key)]; skip {
+			continue
+		}
+		if sameName(key, dependency) {
+			return tomlManifestEntry{index: index, key: key, value: match[4]}, true
+		}
+	}
+	return tomlManifestEntry{}, false
+}
+
+func splitTOMLDependencySubtable(name string, layout tomlTableLayout) (string, string, bool) {
+	tables := append(append([]string{}, layout.prodSections...), layout.devSections...)
+	for _, table := range tables {
+		if key, ok := strings.CutPrefix(name, table+"."); ok && key != "" && !strings.Contains(key, ".") {
+			return table, key, true
+		}
+	}
+	return "", "", false
+}
+
+func lastTOMLContentLine(lines []string, section tomlManifestSection) int {
+	last := section.header
+	for index := section.start; index < section.end; index++ {
+		trimmed := strings.TrimSpace(lines[index])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		last = index
+	}
+	return last
+}
+
+// tomlValueComplete reports whether every string, inline table and array
+// opened in value is also closed on the same logical value.
+func tomlValueComplete(value string) bool {
+	if strings.Contains(value, `"""`) || strings.Contains(value, "'''") {
+		return false
+	}
+	depth := 0
+	var quote byte
+	escaped := false
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\' && quote == '"':
+				escaped = true
+			case ch == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch ch {
+		case '"', '\'':
+			quote = ch
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		case '#':
+			return depth == 0
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+func tomlStringLiterals(line string) []string {
+	literals := make([]string, 0, 1)
+	for _, match := range tomlManifestStringPattern.FindAllString(line, -1) {
+		literals = append(literals, unquoteTOMLString(match))
+	}
+	return literals
+}
+
+func unquoteTOMLString(value string) string {
+	if len(value) >= 2 {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func pythonRequirementName(requirement string) string {
+	match := pythonRequirementNameRegex.FindStringSubmatch(requirement)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+func samePythonName(a, b string) bool {
+	return a != "" && normalizePythonName(a) == normalizePythonName(b)
+}
+
+func normalizePythonName(name string) string {
+	return pythonNameSeparatorPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
+
+func normalizeCargoName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+}
+
+func isCargoTargetDependencySection(name string) bool {
+	return strings.HasPrefix(name, "target.") && (strings.HasSuffix(name, ".dependencies") || strings.HasSuffix(name, ".build-dependencies"))
+}
+
+func isPoetryGroupDependencySection(name string) bool {
+	return strings.HasPrefix(name, "tool.poetry.group.") && strings.HasSuffix(name, ".dependencies")
+}
+
+func manifestDemoteUnsupportedSkip(line int, section string) manifestEditOutcome {
+	return manifestEditOutcome{
+		found:       true,
+		fromSection: section,
+		skipLine:    line + 1,
+		skipReason:  manifestCodemodReasonDemoteUnsupported,
+		skipMessage: "dependencies in " + section + " cannot be moved to a development section",
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
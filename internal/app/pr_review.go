@@ -388,6 +388,7 @@ func (a *App) analysePRReviewWorktree(ctx context.Context, repoPath, callerRepoP
 		advisorySourcePath:      req.AdvisorySourcePath,
 		advisorySourceTrustRoot: req.AdvisorySourceTrustRoot,
 		vulnerabilityExceptions: req.VulnerabilityExceptions,
+		licenseExceptions:       req.LicenseExceptions,
 		policySources:           req.PolicySources,
 		policyTrace:             req.PolicyTrace,
 	}
@@ -407,7 +408,9 @@ func (a *App) analysePRReviewWorktree(ctx context.Context, repoPath, callerRepoP
 		report.AnnotateVulnerabilities(&reportData, advisories)
 		reportData.Summary = report.ComputeSummary(reportData.Dependencies)
 	}
-	return applyVulnerabilityExceptionsToReport(reportData, req.VulnerabilityExceptions, prReviewNow().UTC()), nil
+	now := prReviewNow().UTC()
+	reportData = applyVulnerabilityExceptionsToReport(reportData, req.VulnerabilityExceptions, now)
+	return applyLicenseExceptionsToReport(reportData, req.LicenseExceptions, now), nil
 }
 
 func validatePRReviewFeatures(req PRReviewRequest) error {
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"time"
+
 	"github.com/ben-ranford/lopper/internal/analysis"
 	"github.com/ben-ranford/lopper/internal/featureflags"
 	"github.com/ben-ranford/lopper/internal/notify"
@@ -20,6 +22,7 @@ const (
 	ModeProfile   Mode = "profile"
 	ModeMCP       Mode = "mcp"
 	ModeAdvisory  Mode = "advisory"
+	ModeCache     Mode = "cache"
 
 	ScopeModeRepo            = analysis.ScopeModeRepo
 	ScopeModePackage         = analysis.ScopeModePackage
@@ -38,40 +41,48 @@ type Request struct {
 	Profile   ProfileRequest
 	MCP       MCPRequest
 	Advisory  AdvisoryRequest
+	Cache     CacheRequest
 }
 
 type AnalyseRequest struct {
-	Dependency              string
-	TopN                    int
-	ScopeMode               string
-	SuggestOnly             bool
-	ApplyCodemod            bool
-	AllowDirty              bool
-	Format                  report.Format
-	OutputPath              string
-	Language                string
-	CacheEnabled            bool
-	CachePath               string
-	CacheReadOnly           bool
-	RuntimeProfile          string
-	BaselinePath            string
-	BaselineStorePath       string
-	BaselineKey             string
-	BaselineLabel           string
-	SaveBaseline            bool
-	RuntimeTracePath        string
-	RuntimeTestCommand      string
-	AdvisorySourcePath      string
-	AdvisorySourceTrustRoot string
-	IncludePatterns         []string
-	ExcludePatterns         []string
-	ConfigPath              string
-	PolicySources           []string
-	PolicyTrace             []report.PolicyMergeTrace
-	VulnerabilityExceptions []report.VulnerabilityException
-	Features                featureflags.Set
-	Thresholds              thresholds.Values
-	Notifications           notify.Config
+	Dependency               string
+	TopN                     int
+	ScopeMode                string
+	SuggestOnly              bool
+	ApplyCodemod             bool
+	AllowDirty               bool
+	RemoveUnusedDependencies bool
+	ManifestAction           string
+	Format                   report.Format
+	OutputPath               string
+	TemplatePath             string
+	Language                 string
+	CacheEnabled             bool
+	CachePath                string
+	CacheReadOnly            bool
+	RuntimeProfile           string
+	BaselinePath             string
+	BaselineStorePath        string
+	BaselineKey              string
+	BaselineLabel            string
+	SaveBaseline             bool
+	RuntimeTracePath         string
+	RuntimeTestCommand       string
+	AdvisorySourcePath       string
+	AdvisorySourceTrustRoot  string
+	IncludePatterns          []string
+	ExcludePatterns          []string
+	ConfigPath               string
+	PolicySources            []string
+	PolicyTrace              []report.PolicyMergeTrace
+	VulnerabilityExceptions  []report.VulnerabilityException
+	LicenseExceptions        []report.LicenseException
+	PolicyRules              []report.PolicyRule
+	Features                 featureflags.Set
+	Thresholds               thresholds.Values
+	Notifications            notify.Config
+	Watch                    bool
+	ExplainExit              bool
 }
 
 type TUIRequest struct {
@@ -84,6 +95,8 @@ type TUIRequest struct {
 	BaselinePath      string
 	BaselineStorePath string
 	BaselineKey       string
+	Watch             bool
+	Features          featureflags.Set
 }
 
 type DashboardRepo struct {
@@ -131,6 +144,7 @@ type PRReviewRequest struct {
 	PolicySources           []string
 	PolicyTrace             []report.PolicyMergeTrace
 	VulnerabilityExceptions []report.VulnerabilityException
+	LicenseExceptions       []report.LicenseException
 	IncludePatterns         []string
 	ExcludePatterns         []string
 	FailOnRegression        bool
@@ -167,6 +181,16 @@ type AdvisoryRequest struct {
 	Features   featureflags.Set
 }
 
+type CacheRequest struct {
+	Command    string
+	CachePath  string
+	BundlePath string
+	Format     string
+	MaxAge     time.Duration
+	MaxBytes   int64
+	Features   featureflags.Set
+}
+
 func DefaultRequest() Request {
 	return Request{
 		Mode:     ModeTUI,
@@ -207,5 +231,8 @@ func DefaultRequest() Request {
 		Features: FeaturesRequest{
 			Format: "table",
 		},
+		Cache: CacheRequest{
+			Format: "table",
+		},
 	}
 }
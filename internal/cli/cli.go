@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/ben-ranford/lopper/internal/app"
+	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/version"
 )
 
@@ -44,7 +45,7 @@ func (c *CommandLine) Run(ctx context.Context, args []string) int {
 	writeErr := c.writeOutput(output)
 	if writeErr != nil {
 		if runErr != nil {
-			return exitCodeForRunError(runErr)
+			return exitCodeForRunError(req, runErr)
 		}
 		return 1
 	}
@@ -54,7 +55,7 @@ func (c *CommandLine) Run(ctx context.Context, args []string) int {
 		if writeErr != nil {
 			return 1
 		}
-		return exitCodeForRunError(runErr)
+		return exitCodeForRunError(req, runErr)
 	}
 
 	return 0
@@ -73,6 +74,8 @@ func featureDeprecationWarnings(req app.Request) []string {
 	switch req.Mode {
 	case app.ModeAnalyse:
 		return req.Analyse.Features.DeprecationWarnings()
+	case app.ModeTUI:
+		return req.TUI.Features.DeprecationWarnings()
 	case app.ModeDashboard:
 		return req.Dashboard.Features.DeprecationWarnings()
 	case app.ModeBaseline:
@@ -85,6 +88,8 @@ func featureDeprecationWarnings(req app.Request) []string {
 		return req.MCP.Features.DeprecationWarnings()
 	case app.ModeAdvisory:
 		return req.Advisory.Features.DeprecationWarnings()
+	case app.ModeCache:
+		return req.Cache.Features.DeprecationWarnings()
 	default:
 		return nil
 	}
@@ -130,18 +135,21 @@ func (c *CommandLine) writeOutput(output string) error {
 	return nil
 }
 
-func exitCodeForRunError(runErr error) int {
-	if errors.Is(runErr, app.ErrFailOnIncrease) ||
-		errors.Is(runErr, app.ErrUncertaintyThresholdExceeded) ||
-		errors.Is(runErr, app.ErrDeniedLicenses) ||
-		errors.Is(runErr, app.ErrReachableVulnerabilities) ||
-		errors.Is(runErr, app.ErrPRReviewRegressions) {
-		return 3
-	}
-	if errors.Is(runErr, app.ErrLockfileDrift) {
-		return 4
+// exitCodeForRunError uses the per-gate exit codes when the command enabled
+// exit-code-contract-preview and the legacy codes otherwise.
+func exitCodeForRunError(req app.Request, runErr error) int {
+	return app.ExitCode(runErr, exitCodeContractEnabled(req))
+}
+
+func exitCodeContractEnabled(req app.Request) bool {
+	switch req.Mode {
+	case app.ModeAnalyse:
+		return req.Analyse.Features.Enabled(report.ExitCodeContractPreviewFeature)
+	case app.ModePRReview:
+		return req.PRReview.Features.Enabled(report.ExitCodeContractPreviewFeature)
+	default:
+		return false
 	}
-	return 1
 }
 
 func (c *CommandLine) writeOut(value string) error {
@@ -313,7 +313,7 @@ func TestUsageReturnsText(t *testing.T) {
 		!strings.Contains(Usage(), "lopper advisory status --cache-path PATH") {
 		t.Fatalf("expected usage text to include advisory cache commands")
 	}
-	if !strings.Contains(Usage(), "--format table|csv|json|sarif|pr-comment|cyclonedx-json") {
+	if !strings.Contains(Usage(), "--format table|csv|json|ndjson|html|sarif|pr-comment|junit|gitlab-codequality|template|cyclonedx-json") {
 		t.Fatalf("expected usage text to include analyse csv format")
 	}
 	if !strings.Contains(Usage(), "Supported IDs: auto, all, js-ts, python, cpp, jvm, kotlin-android") {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/ben-ranford/lopper/internal/app"
+	"github.com/ben-ranford/lopper/internal/report"
 )
 
 func TestWriteOutputAdditionalBranches(t *testing.T) {
@@ -30,17 +31,30 @@ func TestWriteOutputAdditionalBranches(t *testing.T) {
 }
 
 func TestExitCodeForDeniedLicenses(t *testing.T) {
-	if got := exitCodeForRunError(app.ErrDeniedLicenses); got != 3 {
+	if got := exitCodeForRunError(app.Request{}, app.ErrDeniedLicenses); got != 3 {
 		t.Fatalf("expected denied-license error to use exit code 3, got %d", got)
 	}
 }
 
 func TestExitCodeForReachableVulnerabilities(t *testing.T) {
-	if got := exitCodeForRunError(app.ErrReachableVulnerabilities); got != 3 {
+	if got := exitCodeForRunError(app.Request{}, app.ErrReachableVulnerabilities); got != 3 {
 		t.Fatalf("expected reachable-vulnerability error to use exit code 3, got %d", got)
 	}
 }
 
+func TestExitCodeForRunErrorUsesContractWhenEnabled(t *testing.T) {
+	req := mustParseArgs(t, []string{"analyse", "--top", "1", "--enable-feature", report.ExitCodeContractPreviewFeature})
+	if got := exitCodeForRunError(req, app.ErrDeniedLicenses); got != report.ExitCodeLicenseFailOnDeny {
+		t.Fatalf("expected contract exit code %d, got %d", report.ExitCodeLicenseFailOnDeny, got)
+	}
+	if got := exitCodeForRunError(req, app.ErrLockfileDrift); got != report.ExitCodeLockfileDrift {
+		t.Fatalf("expected contract lockfile drift exit code %d, got %d", report.ExitCodeLockfileDrift, got)
+	}
+	if got := exitCodeForRunError(app.Request{Mode: app.ModeAnalyse}, app.ErrLockfileDrift); got != 4 {
+		t.Fatalf("expected legacy lockfile drift exit code 4, got %d", got)
+	}
+}
+
 func TestRunRunnerErrorWriteFailure(t *testing.T) {
 	c := New(&fakeRunner{err: app.ErrLockfileDrift}, &bytes.Buffer{}, &failWriter{})
 	if code := c.Run(context.Background(), []string{"analyse", "lodash"}); code != 1 {
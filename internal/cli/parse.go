@@ -45,6 +45,8 @@ func ParseArgs(args []string) (app.Request, error) {
 		return parseMCP(args[1:], req)
 	case "advisory":
 		return parseAdvisory(args[1:], req)
+	case "cache":
+		return parseCache(args[1:], req)
 	default:
 		return req, fmt.Errorf("unknown command: %s", args[0])
 	}
@@ -26,6 +26,8 @@ type analyseParseState struct {
 	advisorySourcePath      string
 	advisorySourceTrustRoot string
 	vulnerabilityExceptions []report.VulnerabilityException
+	licenseExceptions       []report.LicenseException
+	policyRules             []report.PolicyRule
 	configPath              string
 	features                featureflags.Set
 	notifications           notify.Config
@@ -62,6 +64,10 @@ func parseAnalyseState(fs *flag.FlagSet, flags analyseFlagValues) (analyseParseS
 	if err := validateCodemodApplyFlags(*flags.suggestOnly, *flags.applyCodemod, *flags.applyCodemodConfirm, *flags.allowDirty, dependency, *flags.top); err != nil {
 		return analyseParseState{}, err
 	}
+	visited := visitedFlags(fs)
+	if err := validateManifestCodemodFlags(*flags.removeUnusedDependencies, *flags.manifestAction, visited["manifest-action"], dependency, *flags.top); err != nil {
+		return analyseParseState{}, err
+	}
 	format, err := report.ParseFormat(*flags.formatFlag)
 	if err != nil {
 		return analyseParseState{}, err
@@ -70,12 +76,20 @@ func parseAnalyseState(fs *flag.FlagSet, flags analyseFlagValues) (analyseParseS
 	if err != nil {
 		return analyseParseState{}, err
 	}
+	if err := validateTemplateFlags(format, *flags.templatePath); err != nil {
+		return analyseParseState{}, err
+	}
+	if err := validateWatchFlags(*flags.watch, format, outputPath, *flags.applyCodemod || *flags.removeUnusedDependencies, *flags.saveBaseline); err != nil {
+		return analyseParseState{}, err
+	}
+	if *flags.watch && *flags.explainExit {
+		return analyseParseState{}, fmt.Errorf("--watch cannot be combined with --explain-exit")
+	}
 	scopeMode, err := parseScopeMode(*flags.scopeMode)
 	if err != nil {
 		return analyseParseState{}, err
 	}
 
-	visited := visitedFlags(fs)
 	resolvedPolicy, err := resolveAnalysisPolicy(visited, flags)
 	if err != nil {
 		return analyseParseState{}, err
@@ -98,6 +112,8 @@ func parseAnalyseState(fs *flag.FlagSet, flags analyseFlagValues) (analyseParseS
 		advisorySourcePath:      resolvedPolicy.advisorySourcePath,
 		advisorySourceTrustRoot: resolvedPolicy.advisorySourceTrustRoot,
 		vulnerabilityExceptions: resolvedPolicy.vulnerabilityExceptions,
+		licenseExceptions:       resolvedPolicy.licenseExceptions,
+		policyRules:             resolvedPolicy.policyRules,
 		configPath:              resolvedPolicy.configPath,
 		features:                resolvedPolicy.features,
 		notifications:           resolvedPolicy.notifications,
@@ -108,37 +124,44 @@ func buildAnalyseRequest(req app.Request, flags analyseFlagValues, state analyse
 	req.Mode = app.ModeAnalyse
 	req.RepoPath = strings.TrimSpace(*flags.repoPath)
 	req.Analyse = app.AnalyseRequest{
-		Dependency:              state.dependency,
-		TopN:                    *flags.top,
-		ScopeMode:               state.scopeMode,
-		SuggestOnly:             *flags.suggestOnly,
-		ApplyCodemod:            *flags.applyCodemod,
-		AllowDirty:              *flags.allowDirty,
-		Format:                  state.format,
-		OutputPath:              state.outputPath,
-		Language:                strings.TrimSpace(*flags.languageFlag),
-		CacheEnabled:            *flags.cacheEnabled,
-		CachePath:               strings.TrimSpace(*flags.cachePath),
-		CacheReadOnly:           *flags.cacheReadOnly,
-		RuntimeProfile:          strings.TrimSpace(*flags.runtimeProfile),
-		BaselinePath:            strings.TrimSpace(*flags.baselinePath),
-		BaselineStorePath:       strings.TrimSpace(*flags.baselineStorePath),
-		BaselineKey:             strings.TrimSpace(*flags.baselineKey),
-		BaselineLabel:           strings.TrimSpace(*flags.baselineLabel),
-		SaveBaseline:            *flags.saveBaseline,
-		RuntimeTracePath:        strings.TrimSpace(*flags.runtimeTracePath),
-		RuntimeTestCommand:      strings.TrimSpace(*flags.runtimeTestCommand),
-		AdvisorySourcePath:      state.advisorySourcePath,
-		AdvisorySourceTrustRoot: state.advisorySourceTrustRoot,
-		VulnerabilityExceptions: append([]report.VulnerabilityException{}, state.vulnerabilityExceptions...),
-		IncludePatterns:         resolveScopePatterns(state.visited, "include", flags.includePatterns.Values(), state.scope.Include),
-		ExcludePatterns:         resolveScopePatterns(state.visited, "exclude", flags.excludePatterns.Values(), state.scope.Exclude),
-		ConfigPath:              state.configPath,
-		PolicySources:           state.policySources,
-		PolicyTrace:             state.policyTrace,
-		Features:                state.features,
-		Thresholds:              state.thresholds,
-		Notifications:           state.notifications,
+		Dependency:               state.dependency,
+		TopN:                     *flags.top,
+		ScopeMode:                state.scopeMode,
+		SuggestOnly:              *flags.suggestOnly,
+		ApplyCodemod:             *flags.applyCodemod,
+		AllowDirty:               *flags.allowDirty,
+		RemoveUnusedDependencies: *flags.removeUnusedDependencies,
+		ManifestAction:           strings.ToLower(strings.TrimSpace(*flags.manifestAction)),
+		Format:                   state.format,
+		OutputPath:               state.outputPath,
+		TemplatePath:             strings.TrimSpace(*flags.templatePath),
+		Language:                 strings.TrimSpace(*flags.languageFlag),
+		CacheEnabled:             *flags.cacheEnabled,
+		CachePath:                strings.TrimSpace(*flags.cachePath),
+		CacheReadOnly:            *flags.cacheReadOnly,
+		RuntimeProfile:           strings.TrimSpace(*flags.runtimeProfile),
+		BaselinePath:             strings.TrimSpace(*flags.baselinePath),
+		BaselineStorePath:        strings.TrimSpace(*flags.baselineStorePath),
+		BaselineKey:              strings.TrimSpace(*flags.baselineKey),
+		BaselineLabel:            strings.TrimSpace(*flags.baselineLabel),
+		SaveBaseline:             *flags.saveBaseline,
+		RuntimeTracePath:         strings.TrimSpace(*flags.runtimeTracePath),
+		RuntimeTestCommand:       strings.TrimSpace(*flags.runtimeTestCommand),
+		AdvisorySourcePath:       state.advisorySourcePath,
+		AdvisorySourceTrustRoot:  state.advisorySourceTrustRoot,
+		VulnerabilityExceptions:  append([]report.VulnerabilityException{}, state.vulnerabilityExceptions...),
+		LicenseExceptions:        append([]report.LicenseException{}, state.licenseExceptions...),
+		PolicyRules:              append([]report.PolicyRule{}, state.policyRules...),
+		IncludePatterns:          resolveScopePatterns(state.visited, "include", flags.includePatterns.Values(), state.scope.Include),
+		ExcludePatterns:          resolveScopePatterns(state.visited, "exclude", flags.excludePatterns.Values(), state.scope.Exclude),
+		ConfigPath:               state.configPath,
+		PolicySources:            state.policySources,
+		PolicyTrace:              state.policyTrace,
+		Features:                 state.features,
+		Thresholds:               state.thresholds,
+		Notifications:            state.notifications,
+		Watch:                    *flags.watch,
+		ExplainExit:              *flags.explainExit,
 	}
 
 	return req
@@ -202,3 +225,54 @@ func validateCodemodApplyFlags(suggestOnly bool, applyCodemod bool, applyConfirm
 	}
 	return nil
 }
+
+func validateManifestCodemodFlags(removeUnused bool, manifestAction string, manifestActionSet bool, dependency string, top int) error {
+	if !removeUnused {
+		if manifestActionSet {
+			return fmt.Errorf("--manifest-action requires --remove-unused-dependencies")
+		}
+		return nil
+	}
+	if top > 0 {
+		return fmt.Errorf("--remove-unused-dependencies requires a specific dependency target")
+	}
+	if strings.TrimSpace(dependency) == "" {
+		return fmt.Errorf("--remove-unused-dependencies requires a dependency argument")
+	}
+	switch strings.ToLower(strings.TrimSpace(manifestAction)) {
+	case app.ManifestActionRemove, app.ManifestActionDemote:
+		return nil
+	default:
+		return fmt.Errorf("invalid --manifest-action %q (expected remove or demote)", manifestAction)
+	}
+}
+
+func validateTemplateFlags(format report.Format, templatePath string) error {
+	hasTemplate := strings.TrimSpace(templatePath) != ""
+	if format == report.FormatTemplate && !hasTemplate {
+		return fmt.Errorf("--format template requires --template")
+	}
+	if format != report.FormatTemplate && hasTemplate {
+		return fmt.Errorf("--template requires --format template")
+	}
+	return nil
+}
+
+func validateWatchFlags(watch bool, format report.Format, outputPath string, mutates bool, saveBaseline bool) error {
+	if !watch {
+		return nil
+	}
+	if format != report.FormatTable && format != report.FormatJSON {
+		return fmt.Errorf("--watch supports --format table or json")
+	}
+	if outputPath != "" {
+		return fmt.Errorf("--watch cannot be combined with --output")
+	}
+	if mutates {
+		return fmt.Errorf("--watch cannot be combined with --apply-codemod or --remove-unused-dependencies")
+	}
+	if saveBaseline {
+		return fmt.Errorf("--watch cannot be combined with --save-baseline")
+	}
+	return nil
+}
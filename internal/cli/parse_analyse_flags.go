@@ -15,10 +15,13 @@ type analyseFlagValues struct {
 	applyCodemod                   *bool
 	applyCodemodConfirm            *bool
 	allowDirty                     *bool
+	removeUnusedDependencies       *bool
+	manifestAction                 *string
 	scopeMode                      *string
 	formatFlag                     *string
 	outputFlag                     *string
 	outputShortFlag                *string
+	templatePath                   *string
 	cacheEnabled                   *bool
 	cachePath                      *string
 	cacheReadOnly                  *bool
@@ -32,6 +35,8 @@ type analyseFlagValues struct {
 	scoreWeightImpact              *float64
 	scoreWeightConfidence          *float64
 	licenseDeny                    *string
+	licenseAllow                   *string
+	licenseUnknown                 *string
 	licenseFailOnDeny              *bool
 	licenseIncludeRegistryProv     *bool
 	languageFlag                   *string
@@ -53,6 +58,8 @@ type analyseFlagValues struct {
 	notifyOn                       *string
 	notifySlack                    *string
 	notifyTeams                    *string
+	watch                          *bool
+	explainExit                    *bool
 }
 
 func newAnalyseFlagSet(req app.Request) (*flag.FlagSet, analyseFlagValues) {
@@ -70,10 +77,13 @@ func newAnalyseFlagSet(req app.Request) (*flag.FlagSet, analyseFlagValues) {
 		applyCodemod:                   fs.Bool("apply-codemod", req.Analyse.ApplyCodemod, "apply deterministic codemod patch previews for safe remediation suggestions"),
 		applyCodemodConfirm:            fs.Bool("apply-codemod-confirm", false, "confirm codemod apply mode will mutate source files"),
 		allowDirty:                     fs.Bool("allow-dirty", req.Analyse.AllowDirty, "allow codemod apply mode to run in a dirty git worktree"),
+		removeUnusedDependencies:       fs.Bool("remove-unused-dependencies", req.Analyse.RemoveUnusedDependencies, "generate manifest patches that remove or demote a confirmed-unused dependency"),
+		manifestAction:                 fs.String("manifest-action", app.ManifestActionRemove, "manifest edit for --remove-unused-dependencies (remove, demote)"),
 		scopeMode:                      fs.String("scope-mode", req.Analyse.ScopeMode, "analysis scope mode"),
 		formatFlag:                     fs.String("format", string(req.Analyse.Format), "output format"),
 		outputFlag:                     fs.String("output", req.Analyse.OutputPath, "output file path"),
 		outputShortFlag:                fs.String("o", req.Analyse.OutputPath, "output file path"),
+		templatePath:                   fs.String("template", req.Analyse.TemplatePath, "text/template file for --format template"),
 		cacheEnabled:                   fs.Bool("cache", req.Analyse.CacheEnabled, "enable incremental analysis cache"),
 		cachePath:                      fs.String("cache-path", req.Analyse.CachePath, "analysis cache directory path"),
 		cacheReadOnly:                  fs.Bool("cache-readonly", req.Analyse.CacheReadOnly, "read cache without writing new entries"),
@@ -87,6 +97,8 @@ func newAnalyseFlagSet(req app.Request) (*flag.FlagSet, analyseFlagValues) {
 		scoreWeightImpact:              fs.Float64("score-weight-impact", req.Analyse.Thresholds.RemovalCandidateWeightImpact, "relative weight for removal-candidate impact signal"),
 		scoreWeightConfidence:          fs.Float64("score-weight-confidence", req.Analyse.Thresholds.RemovalCandidateWeightConfidence, "relative weight for removal-candidate confidence signal"),
 		licenseDeny:                    fs.String("license-deny", strings.Join(req.Analyse.Thresholds.LicenseDenyList, ","), "comma-separated SPDX identifiers to deny"),
+		licenseAllow:                   fs.String("license-allow", strings.Join(req.Analyse.Thresholds.LicenseAllowList, ","), "comma-separated SPDX identifiers or patterns to allow"),
+		licenseUnknown:                 fs.String("license-unknown", req.Analyse.Thresholds.LicenseUnknownPolicy, "unknown license policy (allow, warn, deny)"),
 		licenseFailOnDeny:              fs.Bool("license-fail-on-deny", req.Analyse.Thresholds.LicenseFailOnDeny, "fail when denied licenses are detected"),
 		licenseIncludeRegistryProv:     fs.Bool("license-provenance-registry", req.Analyse.Thresholds.LicenseIncludeRegistryProvenance, "opt-in registry provenance heuristics for JS/TS dependencies"),
 		languageFlag:                   fs.String("language", req.Analyse.Language, "language adapter"),
@@ -108,6 +120,8 @@ func newAnalyseFlagSet(req app.Request) (*flag.FlagSet, analyseFlagValues) {
 		notifyOn:                       fs.String("notify-on", string(req.Analyse.Notifications.Slack.Trigger), "notification trigger"),
 		notifySlack:                    fs.String("notify-slack", req.Analyse.Notifications.Slack.WebhookURL, "Slack webhook URL"),
 		notifyTeams:                    fs.String("notify-teams", req.Analyse.Notifications.Teams.WebhookURL, "Teams webhook URL"),
+		watch:                          fs.Bool("watch", req.Analyse.Watch, "re-run analysis whenever watched files change"),
+		explainExit:                    fs.Bool("explain-exit", req.Analyse.ExplainExit, "write the exit code and gate breakdown to stderr"),
 	}
 	fs.Var(enableFeatures, "enable-feature", "comma-separated feature flag names to enable (repeatable)")
 	fs.Var(disableFeatures, "disable-feature", "comma-separated feature flag names to disable (repeatable)")
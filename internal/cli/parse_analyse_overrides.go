@@ -20,20 +20,20 @@ var (
 	featureReleaseLockProvider = featureflags.DefaultReleaseLock
 )
 
-func resolveAnalyseThresholds(values analyseFlagValues, visited map[string]bool) (thresholds.Values, thresholds.PathScope, []string, []report.PolicyMergeTrace, string, string, []report.VulnerabilityException, thresholds.FeatureConfig, string, error) {
+func resolveAnalyseThresholds(values analyseFlagValues, visited map[string]bool) (thresholds.Values, thresholds.PathScope, []string, []report.PolicyMergeTrace, string, string, []report.VulnerabilityException, []report.LicenseException, []report.PolicyRule, thresholds.FeatureConfig, string, error) {
 	loadResult, err := thresholds.LoadWithPolicy(strings.TrimSpace(*values.repoPath), strings.TrimSpace(*values.configPath))
 	if err != nil {
-		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, thresholds.FeatureConfig{}, "", err
+		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, nil, nil, thresholds.FeatureConfig{}, "", err
 	}
 
 	resolvedThresholds := loadResult.Resolved
 	cliOverrides, err := cliThresholdOverrides(visited, values)
 	if err != nil {
-		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, thresholds.FeatureConfig{}, "", err
+		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, nil, nil, thresholds.FeatureConfig{}, "", err
 	}
 	resolvedThresholds = cliOverrides.Apply(resolvedThresholds)
 	if err := resolvedThresholds.Validate(); err != nil {
-		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, thresholds.FeatureConfig{}, "", err
+		return thresholds.Values{}, thresholds.PathScope{}, nil, nil, "", "", nil, nil, nil, thresholds.FeatureConfig{}, "", err
 	}
 
 	policySources := append([]string{}, loadResult.PolicySources...)
@@ -62,7 +62,7 @@ func resolveAnalyseThresholds(values analyseFlagValues, visited map[string]bool)
 		policyTrace = mergePolicyTraceItems(policyTrace, report.PolicyMergeTrace{Field: "advisories.source", Source: "cli"})
 	}
 
-	return resolvedThresholds, loadResult.Scope, policySources, policyTrace, advisorySourcePath, root, append([]report.VulnerabilityException{}, loadResult.VulnerabilityExceptions...), loadResult.Features, loadResult.ConfigPath, nil
+	return resolvedThresholds, loadResult.Scope, policySources, policyTrace, advisorySourcePath, root, append([]report.VulnerabilityException{}, loadResult.VulnerabilityExceptions...), append([]report.LicenseException{}, loadResult.LicenseExceptions...), append([]report.PolicyRule{}, loadResult.PolicyRules...), loadResult.Features, loadResult.ConfigPath, nil
 }
 
 func prependUniquePolicySource(source string, sources []string) []string {
@@ -221,6 +221,12 @@ func applyLicenseThresholdOverrides(overrides *thresholds.Overrides, visited map
 	if visited["license-deny"] {
 		overrides.SetLicenseDenyList(splitPatternList(*values.licenseDeny))
 	}
+	if visited["license-allow"] {
+		overrides.SetLicenseAllowList(splitPatternList(*values.licenseAllow))
+	}
+	if visited["license-unknown"] {
+		overrides.LicenseUnknownPolicy = values.licenseUnknown
+	}
 	if visited["license-fail-on-deny"] {
 		overrides.LicenseFailOnDeny = values.licenseFailOnDeny
 	}
@@ -268,6 +274,8 @@ func hasThresholdOverrides(overrides thresholds.Overrides) bool {
 		overrides.RemovalCandidateWeightImpact != nil ||
 		overrides.RemovalCandidateWeightConfidence != nil ||
 		overrides.HasLicenseDenyListOverride() ||
+		overrides.HasLicenseAllowListOverride() ||
+		overrides.LicenseUnknownPolicy != nil ||
 		overrides.LicenseFailOnDeny != nil ||
 		overrides.LicenseIncludeRegistryProvenance != nil ||
 		overrides.ReachableVulnerabilityPriority != nil ||
@@ -306,6 +314,12 @@ func cliPolicyTrace(overrides thresholds.Overrides) []report.PolicyMergeTrace {
 	if overrides.HasLicenseDenyListOverride() {
 		trace = append(trace, report.PolicyMergeTrace{Field: "license.deny", Source: "cli"})
 	}
+	if overrides.HasLicenseAllowListOverride() {
+		trace = append(trace, report.PolicyMergeTrace{Field: "license.allow", Source: "cli"})
+	}
+	if overrides.LicenseUnknownPolicy != nil {
+		trace = append(trace, report.PolicyMergeTrace{Field: "license.unknown", Source: "cli"})
+	}
 	if overrides.LicenseFailOnDeny != nil {
 		trace = append(trace, report.PolicyMergeTrace{Field: "license.fail_on_deny", Source: "cli"})
 	}
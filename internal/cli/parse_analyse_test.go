@@ -127,6 +127,10 @@ func TestParseArgsAnalyseTop(t *testing.T) {
 	}{
 		{name: "csv", format: "csv", want: report.FormatCSV},
 		{name: "json", format: "json", want: report.FormatJSON},
+		{name: "ndjson", format: "ndjson", want: report.FormatNDJSON},
+		{name: "html", format: "html", want: report.FormatHTML},
+		{name: "junit", format: "junit", want: report.FormatJUnit},
+		{name: "gitlab codequality", format: "gitlab-codequality", want: report.FormatCodeQuality},
 		{name: "sarif", format: "sarif", want: report.FormatSARIF},
 		{name: "pr_comment", format: "pr-comment", want: report.FormatPRComment},
 		{name: "cyclonedx_json", format: "cyclonedx-json", want: report.FormatCycloneDX},
@@ -156,6 +160,22 @@ func TestParseArgsAnalyseOutputFlags(t *testing.T) {
 	}
 }
 
+func TestParseArgsAnalyseTemplateFlags(t *testing.T) {
+	req := mustParseArgs(t, []string{"analyse", "--top", "5", formatFlagName, "template", "--template", " digest.tmpl "})
+	if req.Analyse.Format != report.FormatTemplate || req.Analyse.TemplatePath != "digest.tmpl" {
+		t.Fatalf("expected template format and path, got %q %q", req.Analyse.Format, req.Analyse.TemplatePath)
+	}
+
+	err := expectParseArgsError(t, []string{"analyse", "--top", "5", formatFlagName, "template"}, "expected missing template error")
+	if !strings.Contains(err.Error(), "--format template requires --template") {
+		t.Fatalf("unexpected missing template error: %v", err)
+	}
+	err = expectParseArgsError(t, []string{"analyse", "--top", "5", "--template", "digest.tmpl"}, "expected template without format error")
+	if !strings.Contains(err.Error(), "--template requires --format template") {
+		t.Fatalf("unexpected template without format error: %v", err)
+	}
+}
+
 func TestParseArgsAnalyseLanguage(t *testing.T) {
 	req := mustParseArgs(t, []string{"analyse", "lodash", languageFlagName, "js-ts"})
 	if req.Analyse.Language != "js-ts" {
@@ -437,6 +457,56 @@ func TestValidateCodemodApplyRequiresDependency(t *testing.T) {
 	}
 }
 
+func TestParseArgsAnalyseRemoveUnusedDependencies(t *testing.T) {
+	req := mustParseArgs(t, []string{"analyse", "lodash", "--remove-unused-dependencies", "--manifest-action", "Demote"})
+	if !req.Analyse.RemoveUnusedDependencies {
+		t.Fatalf("expected remove-unused-dependencies to be enabled")
+	}
+	if req.Analyse.ManifestAction != app.ManifestActionDemote {
+		t.Fatalf("expected demote manifest action, got %q", req.Analyse.ManifestAction)
+	}
+
+	req = mustParseArgs(t, []string{"analyse", "lodash", "--remove-unused-dependencies"})
+	if req.Analyse.ManifestAction != app.ManifestActionRemove {
+		t.Fatalf("expected default remove manifest action, got %q", req.Analyse.ManifestAction)
+	}
+}
+
+func TestParseArgsAnalyseRemoveUnusedDependenciesValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "action_without_remove",
+			args: []string{"analyse", "lodash", "--manifest-action", "demote"},
+			want: "--manifest-action requires --remove-unused-dependencies",
+		},
+		{
+			name: "top_not_supported",
+			args: []string{"analyse", "--top", "5", "--remove-unused-dependencies"},
+			want: "--remove-unused-dependencies requires a specific dependency target",
+		},
+		{
+			name: "invalid_action",
+			args: []string{"analyse", "lodash", "--remove-unused-dependencies", "--manifest-action", "prune"},
+			want: `invalid --manifest-action "prune"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := expectParseArgsError(t, tc.args, "expected manifest codemod validation error")
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+	if err := validateManifestCodemodFlags(true, app.ManifestActionRemove, false, "", 0); err == nil || !strings.Contains(err.Error(), "requires a dependency argument") {
+		t.Fatalf("expected dependency argument validation error, got %v", err)
+	}
+}
+
 func TestParseArgsAnalyseThresholdDefaults(t *testing.T) {
 	req := mustParseArgs(t, []string{"analyse", "--top", "3"})
 	if !reflect.DeepEqual(req.Analyse.Thresholds, thresholds.Defaults()) {
@@ -659,6 +729,61 @@ func TestParseArgsAnalyseLicenseDenyCanClearConfigAndPreserveCLITrace(t *testing
 	}
 }
 
+func TestParseArgsAnalyseLicenseAllowUnknownAndExceptions(t *testing.T) {
+	repo := t.TempDir()
+	config := `thresholds:
+  license_allow: [MIT]
+  license_unknown: deny
+license_exceptions:
+  - package: left-pad
+    owner: legal
+    reason: reviewed
+    expires: "2027-01-01"
+`
+	testutil.MustWriteFile(t, filepath.Join(repo, parseConfigFileName), config)
+
+	req := mustParseArgs(t, []string{"analyse", "--top", "1", repoFlagName, repo, "--license-allow", "mit,bsd-*", "--license-unknown", "warn"})
+	if strings.Join(req.Analyse.Thresholds.LicenseAllowList, ",") != "BSD-*,MIT" {
+		t.Fatalf("unexpected license allow list: %#v", req.Analyse.Thresholds.LicenseAllowList)
+	}
+	if req.Analyse.Thresholds.LicenseUnknownPolicy != "warn" {
+		t.Fatalf("expected CLI unknown policy to override config, got %q", req.Analyse.Thresholds.LicenseUnknownPolicy)
+	}
+	if len(req.Analyse.LicenseExceptions) != 1 || req.Analyse.LicenseExceptions[0].Package != "left-pad" {
+		t.Fatalf("expected config license exceptions, got %#v", req.Analyse.LicenseExceptions)
+	}
+	trace := map[string]string{}
+	for _, item := range req.Analyse.PolicyTrace {
+		trace[item.Field] = item.Source
+	}
+	if trace["license.allow"] != "cli" || trace["license.unknown"] != "cli" || trace["license.exceptions"] != filepath.Join(repo, parseConfigFileName) {
+		t.Fatalf("unexpected license policy trace: %#v", trace)
+	}
+
+	if _, err := ParseArgs([]string{"analyse", "--top", "1", repoFlagName, repo, "--license-unknown", "review"}); err == nil || !strings.Contains(err.Error(), "license_unknown") {
+		t.Fatalf("expected invalid --license-unknown to fail, got %v", err)
+	}
+}
+
+func TestParseArgsAnalysePolicyRulesFromConfig(t *testing.T) {
+	repo := t.TempDir()
+	config := `rules:
+  - id: runtime-critical-risk
+    severity: error
+    message: runtime-only dependency has a critical risk cue
+    expr: has(dependency.runtimeUsage) && dependency.runtimeUsage.runtimeOnly && has(dependency.riskCues) && dependency.riskCues.exists(c, c.severity == "critical")
+`
+	testutil.MustWriteFile(t, filepath.Join(repo, parseConfigFileName), config)
+
+	req := mustParseArgs(t, []string{"analyse", "--top", "1", repoFlagName, repo})
+	if len(req.Analyse.PolicyRules) != 1 || req.Analyse.PolicyRules[0].ID != "runtime-critical-risk" || req.Analyse.PolicyRules[0].Scope != "dependency" {
+		t.Fatalf("expected config policy rules, got %#v", req.Analyse.PolicyRules)
+	}
+	if req.Analyse.PolicyRules[0].Source != filepath.Join(repo, parseConfigFileName) {
+		t.Fatalf("expected rule source to be the config file, got %q", req.Analyse.PolicyRules[0].Source)
+	}
+}
+
 func TestParseArgsAnalyseNotificationPrecedence(t *testing.T) {
 	repo := t.TempDir()
 	config := `notifications:
@@ -869,3 +994,33 @@ func TestParseArgsVisitedFlagThresholdAliasMatch(t *testing.T) {
 		t.Fatalf("expected resolved fail threshold 3, got %d", req.Analyse.Thresholds.FailOnIncreasePercent)
 	}
 }
+
+func TestParseArgsAnalyseWatch(t *testing.T) {
+	req := mustParseArgs(t, []string{"analyse", "--top", "5", "--watch", "--format", "json", "--enable-feature", app.AnalyseWatchFeature})
+	if !req.Analyse.Watch || req.Analyse.Format != report.FormatJSON {
+		t.Fatalf("expected analyse watch request, got %#v", req.Analyse)
+	}
+
+	for args, want := range map[string]string{
+		"--format sarif":    "--watch supports --format table or json",
+		"--output out.json": "--watch cannot be combined with --output",
+		"--save-baseline":   "--watch cannot be combined with --save-baseline",
+	} {
+		fullArgs := append([]string{"analyse", "--top", "5", "--watch"}, strings.Fields(args)...)
+		if _, err := ParseArgs(fullArgs); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseArgs(%v) error=%v, want %q", fullArgs, err, want)
+		}
+	}
+}
+
+func TestParseArgsAnalyseExplainExit(t *testing.T) {
+	req := mustParseArgs(t, []string{"analyse", "--top", "5", "--explain-exit"})
+	if !req.Analyse.ExplainExit {
+		t.Fatalf("expected --explain-exit to be parsed")
+	}
+
+	err := expectParseArgsError(t, []string{"analyse", "--top", "5", "--watch", "--explain-exit"}, "expected watch with explain-exit error")
+	if !strings.Contains(err.Error(), "--watch cannot be combined with --explain-exit") {
+		t.Fatalf("unexpected watch with explain-exit error: %v", err)
+	}
+}
@@ -15,6 +15,8 @@ type resolvedAnalysisPolicy struct {
 	advisorySourcePath      string
 	advisorySourceTrustRoot string
 	vulnerabilityExceptions []report.VulnerabilityException
+	licenseExceptions       []report.LicenseException
+	policyRules             []report.PolicyRule
 	configPath              string
 	features                featureflags.Set
 	notifications           notify.Config
@@ -34,7 +36,7 @@ func resolveAnalysisPolicy(visited map[string]bool, flags analyseFlagValues) (re
 }
 
 func resolveAnalysisPolicyCore(visited map[string]bool, flags analyseFlagValues) (resolvedAnalysisPolicy, error) {
-	resolvedThresholds, resolvedScope, policySources, policyTrace, advisorySourcePath, root, vulnerabilityExceptions, configFeatures, resolvedConfigPath, err := resolveAnalyseThresholds(flags, visited)
+	resolvedThresholds, resolvedScope, policySources, policyTrace, advisorySourcePath, root, vulnerabilityExceptions, licenseExceptions, policyRules, configFeatures, resolvedConfigPath, err := resolveAnalyseThresholds(flags, visited)
 	if err != nil {
 		return resolvedAnalysisPolicy{}, err
 	}
@@ -50,6 +52,8 @@ func resolveAnalysisPolicyCore(visited map[string]bool, flags analyseFlagValues)
 		advisorySourcePath:      advisorySourcePath,
 		advisorySourceTrustRoot: root,
 		vulnerabilityExceptions: vulnerabilityExceptions,
+		licenseExceptions:       licenseExceptions,
+		policyRules:             policyRules,
 		configPath:              resolvedConfigPath,
 		features:                resolvedFeatures,
 	}, nil
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/app"
+)
+
+func parseCache(args []string, req app.Request) (app.Request, error) {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		return req, ErrHelpRequested
+	}
+	command := strings.ToLower(strings.TrimSpace(args[0]))
+	switch command {
+	case "stats", "verify", "clear":
+		return parseCacheCommand(command, args[1:], req, false)
+	case "prune":
+		return parseCacheCommand(command, args[1:], req, true)
+	case "export", "import":
+		return parseCacheCommand(command, args[1:], req, false)
+	default:
+		return req, fmt.Errorf("unknown cache command: %s", args[0])
+	}
+}
+
+func parseCacheCommand(command string, args []string, req app.Request, prune bool) (app.Request, error) {
+	normalizedArgs, err := normalizeArgs(args)
+	if err != nil {
+		return req, err
+	}
+	fs := flag.NewFlagSet("cache "+command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	repoPath := fs.String("repo", req.RepoPath, "repository path")
+	cachePath := fs.String("cache-path", "", "cache directory path")
+	format := fs.String("format", req.Cache.Format, "output format (table or json)")
+	enableFeatures := newPatternListFlag(nil)
+	disableFeatures := newPatternListFlag(nil)
+	fs.Var(enableFeatures, "enable-feature", "comma-separated feature flag names to enable (repeatable)")
+	fs.Var(disableFeatures, "disable-feature", "comma-separated feature flag names to disable (repeatable)")
+	var outputFlag, outputShortFlag *string
+	if command == "export" {
+		outputFlag = fs.String("output", "", "bundle file to write")
+		outputShortFlag = fs.String("o", "", "bundle file to write")
+	}
+	var maxAge, maxBytes *string
+	if prune {
+		maxAge = fs.String("max-age", "", "remove entries unused for longer than this duration (for example 72h or 30d)")
+		maxBytes = fs.String("max-bytes", "", "evict least recently used entries until the cache fits (for example 512MB)")
+	}
+	if err := parseFlagSet(fs, normalizedArgs); err != nil {
+		return req, err
+	}
+	var bundlePath string
+	switch {
+	case command == "import" && fs.NArg() == 0:
+		return req, fmt.Errorf("cache import requires a bundle path")
+	case command == "import" && fs.NArg() == 1:
+		bundlePath = strings.TrimSpace(fs.Arg(0))
+	case fs.NArg() > 0:
+		return req, fmt.Errorf("too many arguments for cache %s", command)
+	case command == "export":
+		if bundlePath, err = resolveOutputPath(*outputFlag, *outputShortFlag); err != nil {
+			return req, err
+		}
+		if bundlePath == "" || bundlePath == "-" {
+			return req, fmt.Errorf("cache export requires --output")
+		}
+	}
+	normalizedFormat := strings.ToLower(strings.TrimSpace(*format))
+	if normalizedFormat != "table" && normalizedFormat != "json" {
+		return req, fmt.Errorf("invalid cache format: %s", *format)
+	}
+	features, err := resolveFeatureRefs(enableFeatures.Values(), disableFeatures.Values())
+	if err != nil {
+		return req, err
+	}
+
+	cacheRequest := app.CacheRequest{
+		Command:    command,
+		CachePath:  strings.TrimSpace(*cachePath),
+		BundlePath: bundlePath,
+		Format:     normalizedFormat,
+		Features:   features,
+	}
+	if cacheRequest.CachePath == "" {
+		cacheRequest.CachePath = filepath.Join(strings.TrimSpace(*repoPath), ".lopper-cache")
+	}
+	if prune {
+		if cacheRequest.MaxAge, err = parseCacheMaxAge(*maxAge); err != nil {
+			return req, err
+		}
+		if cacheRequest.MaxBytes, err = parseCacheMaxBytes(*maxBytes); err != nil {
+			return req, err
+		}
+		if cacheRequest.MaxAge == 0 && cacheRequest.MaxBytes == 0 {
+			return req, fmt.Errorf("cache prune requires --max-age or --max-bytes")
+		}
+	}
+	req.Mode = app.ModeCache
+	req.RepoPath = strings.TrimSpace(*repoPath)
+	req.Cache = cacheRequest
+	return req, nil
+}
+
+// parseCacheMaxAge accepts Go durations plus a whole-day "d" suffix.
+func parseCacheMaxAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid --max-age: %s", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid --max-age: %s", value)
+	}
+	return duration, nil
+}
+
+// parseCacheMaxBytes accepts a byte count with an optional binary KB, MB, or
+// GB suffix.
+func parseCacheMaxBytes(value string) (int64, error) {
+	raw := strings.TrimSpace(value)
+	value = strings.ToUpper(raw)
+	if value == "" {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, multiplier = strings.TrimSpace(trimmed), unit.multiplier
+			break
+		}
+	}
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || count <= 0 || count > (1<<62)/multiplier {
+		return 0, fmt.Errorf("invalid --max-bytes: %s", raw)
+	}
+	return count * multiplier, nil
+}
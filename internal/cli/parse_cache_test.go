@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/app"
+)
+
+func TestParseCachePrune(t *testing.T) {
+	t.Parallel()
+
+	req := mustParseArgs(t, []string{"cache", "prune", "--repo", "repo", "--max-age", "30d", "--max-bytes", "512MB", "--format", "json", "--enable-feature", app.CacheMaintenanceFeature})
+	if req.Mode != app.ModeCache {
+		t.Fatalf(modeMismatchFmt, app.ModeCache, req.Mode)
+	}
+	if req.Cache.Command != "prune" || req.Cache.Format != "json" || req.Cache.CachePath != filepath.Join("repo", ".lopper-cache") {
+		t.Fatalf("unexpected cache request: %#v", req.Cache)
+	}
+	if req.Cache.MaxAge != 30*24*time.Hour || req.Cache.MaxBytes != 512<<20 {
+		t.Fatalf("unexpected prune bounds: %#v", req.Cache)
+	}
+	if !req.Cache.Features.Enabled(app.CacheMaintenanceFeature) {
+		t.Fatalf("expected cache maintenance feature to be enabled")
+	}
+
+	req = mustParseArgs(t, []string{"cache", "stats", "--cache-path", "/tmp/lopper-cache"})
+	if req.Cache.Command != "stats" || req.Cache.CachePath != "/tmp/lopper-cache" || req.Cache.Format != "table" {
+		t.Fatalf("unexpected stats request: %#v", req.Cache)
+	}
+}
+
+func TestParseCacheBundleCommands(t *testing.T) {
+	t.Parallel()
+
+	req := mustParseArgs(t, []string{"cache", "export", "-o", "cache.tar", "--cache-path", "ci-cache"})
+	if req.Cache.Command != "export" || req.Cache.BundlePath != "cache.tar" || req.Cache.CachePath != "ci-cache" {
+		t.Fatalf("unexpected export request: %#v", req.Cache)
+	}
+
+	req = mustParseArgs(t, []string{"cache", "import", "cache.tar", "--cache-path", "ci-cache"})
+	if req.Cache.Command != "import" || req.Cache.BundlePath != "cache.tar" || req.Cache.CachePath != "ci-cache" {
+		t.Fatalf("unexpected import request: %#v", req.Cache)
+	}
+}
+
+func TestParseCacheValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"cache", "compact"}, want: "unknown cache command"},
+		{args: []string{"cache", "stats", "extra"}, want: "too many arguments"},
+		{args: []string{"cache", "verify", "--format", "yaml"}, want: "invalid cache format"},
+		{args: []string{"cache", "clear", "--max-age", "1h"}, want: "flag provided but not defined"},
+		{args: []string{"cache", "prune"}, want: "requires --max-age or --max-bytes"},
+		{args: []string{"cache", "prune", "--max-age", "soon"}, want: "invalid --max-age"},
+		{args: []string{"cache", "prune", "--max-age", "-2d"}, want: "invalid --max-age"},
+		{args: []string{"cache", "prune", "--max-bytes", "lots"}, want: "invalid --max-bytes: lots"},
+		{args: []string{"cache", "prune", "--max-bytes"}, want: "flag needs an argument"},
+		{args: []string{"cache", "export"}, want: "requires --output"},
+		{args: []string{"cache", "export", "--output", "a.tar", "-o", "b.tar"}, want: "must match"},
+		{args: []string{"cache", "import"}, want: "requires a bundle path"},
+		{args: []string{"cache", "import", "a.tar", "b.tar"}, want: "too many arguments"},
+	}
+	for _, tc := range tests {
+		if _, err := ParseArgs(tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("ParseArgs(%v) error=%v, want %q", tc.args, err, tc.want)
+		}
+	}
+	for _, args := range [][]string{{"cache"}, {"cache", "help"}, {"cache", "prune", "--help"}} {
+		if _, err := ParseArgs(args); !errors.Is(err, ErrHelpRequested) {
+			t.Fatalf("ParseArgs(%v) error=%v, want help", args, err)
+		}
+	}
+}
+
+func TestParseCacheMaxBytesUnits(t *testing.T) {
+	t.Parallel()
+
+	for value, want := range map[string]int64{"4096": 4096, "10b": 10, "2KB": 2 << 10, "1 gb": 1 << 30} {
+		got, err := parseCacheMaxBytes(value)
+		if err != nil || got != want {
+			t.Fatalf("parseCacheMaxBytes(%q)=%d, %v; want %d", value, got, err, want)
+		}
+	}
+}
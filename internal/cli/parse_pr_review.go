@@ -43,6 +43,8 @@ func parsePRReview(args []string, req app.Request) (app.Request, error) {
 	scoreWeightImpactFlag := fs.Float64("score-weight-impact", req.PRReview.Thresholds.RemovalCandidateWeightImpact, "relative weight for removal-candidate impact signal")
 	scoreWeightConfidenceFlag := fs.Float64("score-weight-confidence", req.PRReview.Thresholds.RemovalCandidateWeightConfidence, "relative weight for removal-candidate confidence signal")
 	licenseDenyFlag := fs.String("license-deny", strings.Join(req.PRReview.Thresholds.LicenseDenyList, ","), "comma-separated SPDX identifiers to deny")
+	licenseAllowFlag := fs.String("license-allow", strings.Join(req.PRReview.Thresholds.LicenseAllowList, ","), "comma-separated SPDX identifiers or patterns to allow")
+	licenseUnknownFlag := fs.String("license-unknown", req.PRReview.Thresholds.LicenseUnknownPolicy, "unknown license policy (allow, warn, deny)")
 	licenseIncludeRegistryProvFlag := fs.Bool("license-provenance-registry", req.PRReview.Thresholds.LicenseIncludeRegistryProvenance, "opt-in registry provenance heuristics for JS/TS dependencies")
 	failOnRegressionFlag := fs.Bool("fail-on-regression", req.PRReview.FailOnRegression, "fail when new PR regressions are detected")
 	materialWasteBytesFlag := fs.Int64("material-waste-bytes", req.PRReview.MaterialWasteBytes, "estimated unused byte delta required for a material waste regression")
@@ -93,6 +95,8 @@ func parsePRReview(args []string, req app.Request) (app.Request, error) {
 		scoreWeightImpact:              scoreWeightImpactFlag,
 		scoreWeightConfidence:          scoreWeightConfidenceFlag,
 		licenseDeny:                    licenseDenyFlag,
+		licenseAllow:                   licenseAllowFlag,
+		licenseUnknown:                 licenseUnknownFlag,
 		licenseIncludeRegistryProv:     licenseIncludeRegistryProvFlag,
 		enableFeatures:                 enableFeatures,
 		disableFeatures:                disableFeatures,
@@ -117,6 +121,7 @@ func parsePRReview(args []string, req app.Request) (app.Request, error) {
 		PolicySources:           append([]string{}, resolvedPolicy.policySources...),
 		PolicyTrace:             append([]report.PolicyMergeTrace{}, resolvedPolicy.policyTrace...),
 		VulnerabilityExceptions: append([]report.VulnerabilityException{}, resolvedPolicy.vulnerabilityExceptions...),
+		LicenseExceptions:       append([]report.LicenseException{}, resolvedPolicy.licenseExceptions...),
 		IncludePatterns:         resolveScopePatterns(visited, "include", includePatterns.Values(), resolvedPolicy.scope.Include),
 		ExcludePatterns:         resolveScopePatterns(visited, "exclude", excludePatterns.Values(), resolvedPolicy.scope.Exclude),
 		FailOnRegression:        *failOnRegressionFlag,
@@ -209,7 +209,7 @@ func flagNeedsValue(arg string) bool {
 		return false
 	}
 	switch arg {
-	case "--repo", "--top", "--scope-mode", "--format", "--channel", "--release", "--cache-path", "--fail-on-increase", "--threshold-fail-on-increase", "--threshold-low-confidence-warning", "--threshold-min-usage-percent", "--threshold-max-uncertain-imports", "--threshold-reachable-vuln-priority", "--score-weight-usage", "--score-weight-impact", "--score-weight-confidence", "--license-deny", "--language", "--runtime-profile", "--baseline", "--baseline-store", "--baseline-key", "--baseline-label", "--runtime-trace", "--runtime-test-command", "--advisory-source", "--config", "--enable-feature", "--disable-feature", "--include", "--exclude", "--lockfile-drift-policy", "--notify-on", "--notify-slack", "--notify-teams", "--snapshot", "--filter", "--sort", "--page-size", "--repos", "--store", "--limit", "--base", "--head", "--material-waste-bytes", "--max-rows", "--output", "-o":
+	case "--repo", "--top", "--scope-mode", "--format", "--channel", "--release", "--cache-path", "--fail-on-increase", "--threshold-fail-on-increase", "--threshold-low-confidence-warning", "--threshold-min-usage-percent", "--threshold-max-uncertain-imports", "--threshold-reachable-vuln-priority", "--score-weight-usage", "--score-weight-impact", "--score-weight-confidence", "--license-deny", "--license-allow", "--license-unknown", "--language", "--runtime-profile", "--baseline", "--baseline-store", "--baseline-key", "--baseline-label", "--runtime-trace", "--runtime-test-command", "--advisory-source", "--config", "--enable-feature", "--disable-feature", "--include", "--exclude", "--lockfile-drift-policy", "--manifest-action", "--notify-on", "--notify-slack", "--notify-teams", "--snapshot", "--filter", "--sort", "--page-size", "--repos", "--store", "--limit", "--base", "--head", "--material-waste-bytes", "--max-rows", "--max-age", "--max-bytes", "--output", "-o":
 		return true
 	default:
 		return false
@@ -33,6 +33,11 @@ func parseTUI(args []string, req app.Request) (app.Request, error) {
 	baselinePath := fs.String("baseline", req.TUI.BaselinePath, "baseline report path")
 	baselineStorePath := fs.String("baseline-store", req.TUI.BaselineStorePath, "baseline snapshot directory")
 	baselineKey := fs.String("baseline-key", req.TUI.BaselineKey, "baseline snapshot key for comparison")
+	watch := fs.Bool("watch", req.TUI.Watch, "re-analyse whenever watched files change")
+	enableFeatures := newPatternListFlag(nil)
+	disableFeatures := newPatternListFlag(nil)
+	fs.Var(enableFeatures, "enable-feature", "comma-separated feature flag names to enable (repeatable)")
+	fs.Var(disableFeatures, "disable-feature", "comma-separated feature flag names to disable (repeatable)")
 
 	if err := parseFlagSet(fs, args); err != nil {
 		return req, err
@@ -54,6 +59,13 @@ func parseTUI(args []string, req app.Request) (app.Request, error) {
 	if err != nil {
 		return req, err
 	}
+	if *watch && snapshotPath != "" {
+		return req, fmt.Errorf("--watch cannot be combined with --snapshot")
+	}
+	features, err := resolveFeatureRefs(enableFeatures.Values(), disableFeatures.Values())
+	if err != nil {
+		return req, err
+	}
 
 	req.Mode = app.ModeTUI
 	req.RepoPath = *repoPath
@@ -67,6 +79,8 @@ func parseTUI(args []string, req app.Request) (app.Request, error) {
 		BaselinePath:      strings.TrimSpace(*baselinePath),
 		BaselineStorePath: strings.TrimSpace(*baselineStorePath),
 		BaselineKey:       strings.TrimSpace(*baselineKey),
+		Watch:             *watch,
+		Features:          features,
 	}
 
 	return req, nil
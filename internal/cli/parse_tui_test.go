@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ben-ranford/lopper/internal/app"
@@ -73,3 +74,15 @@ func TestParseArgsTUIOutputAlias(t *testing.T) {
 		t.Fatalf("expected snapshot/output conflict, got %v", err)
 	}
 }
+
+func TestParseArgsTUIWatch(t *testing.T) {
+	req := mustParseArgs(t, []string{"tui", "--watch", "--enable-feature", app.AnalyseWatchFeature})
+	if !req.TUI.Watch || !req.TUI.Features.Enabled(app.AnalyseWatchFeature) {
+		t.Fatalf("expected watch with preview feature, got %#v", req.TUI)
+	}
+
+	err := expectParseArgsError(t, []string{"tui", "--watch", "--snapshot", "summary.txt"}, "expected watch/snapshot conflict")
+	if err == nil || !strings.Contains(err.Error(), "--watch cannot be combined with --snapshot") {
+		t.Fatalf("expected watch/snapshot conflict, got %v", err)
+	}
+}
@@ -2,16 +2,20 @@ package cli
 
 const usage = `Usage:
   lopper [--version] [tui]
-  lopper tui [--repo PATH] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--top N] [--filter TEXT] [--sort name|waste] [--page-size N] [--snapshot PATH] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY]
-  lopper analyse <dependency> [--repo PATH] [--scope-mode repo|package|changed-packages] [--format table|csv|json|sarif|pr-comment|cyclonedx-json] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--cache=true|false] [--cache-path PATH] [--cache-readonly] [--runtime-profile node-import|node-require|browser-import|browser-require] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY] [--save-baseline] [--baseline-label LABEL] [--runtime-trace PATH] [--runtime-test-command CMD] [--advisory-source PATH] [--config PATH] [--include GLOBS] [--exclude GLOBS] [--lockfile-drift-policy off|warn|fail] [--license-deny SPDXS] [--license-fail-on-deny] [--license-provenance-registry] [--notify-on always|breach|regression|improvement] [--notify-slack URL] [--notify-teams URL] [--enable-feature NAME] [--disable-feature NAME] [--suggest-only | (--apply-codemod --apply-codemod-confirm [--allow-dirty])]
-  lopper analyse --top N [--repo PATH] [--scope-mode repo|package|changed-packages] [--format table|csv|json|sarif|pr-comment|cyclonedx-json] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--cache=true|false] [--cache-path PATH] [--cache-readonly] [--runtime-profile node-import|node-require|browser-import|browser-require] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY] [--save-baseline] [--baseline-label LABEL] [--runtime-trace PATH] [--runtime-test-command CMD] [--advisory-source PATH] [--config PATH] [--include GLOBS] [--exclude GLOBS] [--lockfile-drift-policy off|warn|fail] [--license-deny SPDXS] [--license-fail-on-deny] [--license-provenance-registry] [--notify-on always|breach|regression|improvement] [--notify-slack URL] [--notify-teams URL] [--enable-feature NAME] [--disable-feature NAME] [--fail-on-increase PERCENT]
+  lopper tui [--repo PATH] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--top N] [--filter TEXT] [--sort name|waste] [--page-size N] [--snapshot PATH] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY] [--watch --enable-feature analyse-watch-preview]
+  lopper analyse <dependency> [--repo PATH] [--scope-mode repo|package|changed-packages] [--format table|csv|json|ndjson|html|sarif|pr-comment|junit|gitlab-codequality|template|cyclonedx-json] [--template PATH] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--cache=true|false] [--cache-path PATH] [--cache-readonly] [--runtime-profile node-import|node-require|browser-import|browser-require] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY] [--save-baseline] [--baseline-label LABEL] [--runtime-trace PATH] [--runtime-test-command CMD] [--advisory-source PATH] [--config PATH] [--include GLOBS] [--exclude GLOBS] [--lockfile-drift-policy off|warn|fail] [--license-deny SPDXS] [--license-allow SPDXS] [--license-unknown allow|warn|deny] [--license-fail-on-deny] [--license-provenance-registry] [--notify-on always|breach|regression|improvement] [--notify-slack URL] [--notify-teams URL] [--enable-feature NAME] [--disable-feature NAME] [--explain-exit] [--suggest-only | (--apply-codemod --apply-codemod-confirm [--allow-dirty])] [--remove-unused-dependencies [--manifest-action remove|demote]]
+  lopper analyse --top N [--repo PATH] [--scope-mode repo|package|changed-packages] [--format table|csv|json|ndjson|html|sarif|pr-comment|junit|gitlab-codequality|template|cyclonedx-json] [--template PATH] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--cache=true|false] [--cache-path PATH] [--cache-readonly] [--runtime-profile node-import|node-require|browser-import|browser-require] [--baseline PATH] [--baseline-store DIR] [--baseline-key KEY] [--save-baseline] [--baseline-label LABEL] [--runtime-trace PATH] [--runtime-test-command CMD] [--advisory-source PATH] [--config PATH] [--include GLOBS] [--exclude GLOBS] [--lockfile-drift-policy off|warn|fail] [--license-deny SPDXS] [--license-allow SPDXS] [--license-unknown allow|warn|deny] [--license-fail-on-deny] [--license-provenance-registry] [--notify-on always|breach|regression|improvement] [--notify-slack URL] [--notify-teams URL] [--enable-feature NAME] [--disable-feature NAME] [--fail-on-increase PERCENT] [--watch] [--explain-exit]
   lopper dashboard --repos PATH1,PATH2 [--format json|csv|html] [--top N] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--output PATH] [--baseline-store DIR] [--baseline-key KEY] [--baseline-label LABEL] [--save-baseline] [--enable-feature NAME] [--disable-feature NAME]
   lopper dashboard --config lopper-org.yml [--format json|csv|html] [--top N] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--output PATH] [--baseline-store DIR] [--baseline-key KEY] [--baseline-label LABEL] [--save-baseline] [--enable-feature NAME] [--disable-feature NAME]
   lopper baseline list [--store DIR] [--format table|json] [--limit N]
   lopper baseline show KEY [--store DIR] [--format table|json]
   lopper advisory sync osv --cache-path PATH [--source-url URL] [--output PATH] [--enable-feature advisory-osv-sync-preview] [--disable-feature NAME]
   lopper advisory status --cache-path PATH [--output PATH] [--enable-feature advisory-osv-sync-preview] [--disable-feature NAME]
-  lopper pr-review --base SHA --head SHA [--repo PATH] [--format markdown|json] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--top N] [--scope-mode repo|package|changed-packages] [--advisory-source PATH] [--license-deny SPDXS] [--material-waste-bytes N] [--max-rows N] [--fail-on-regression] [--enable-feature dependency-surface-pr-review-preview]
+  lopper cache stats|verify|clear [--repo PATH] [--cache-path PATH] [--format table|json] [--enable-feature analysis-cache-maintenance-preview]
+  lopper cache prune (--max-age DURATION | --max-bytes SIZE) [--repo PATH] [--cache-path PATH] [--format table|json] [--enable-feature analysis-cache-maintenance-preview]
+  lopper cache export --output BUNDLE [--repo PATH] [--cache-path PATH] [--format table|json] [--enable-feature analysis-cache-maintenance-preview]
+  lopper cache import BUNDLE [--repo PATH] [--cache-path PATH] [--format table|json] [--enable-feature analysis-cache-maintenance-preview]
+  lopper pr-review --base SHA --head SHA [--repo PATH] [--format markdown|json] [--language auto|all|js-ts|python|cpp|jvm|kotlin-android|go|php|ruby|rust|dotnet|elixir|swift|dart|powershell] [--top N] [--scope-mode repo|package|changed-packages] [--advisory-source PATH] [--license-deny SPDXS] [--license-allow SPDXS] [--license-unknown allow|warn|deny] [--material-waste-bytes N] [--max-rows N] [--fail-on-regression] [--enable-feature dependency-surface-pr-review-preview]
   lopper features [--format table|json] [--channel dev|rolling|release] [--release VERSION]
   lopper profile apply strict|balanced|noise-reduction [--output PATH] [--force] [--enable-feature threshold-profiles]
   lopper mcp
@@ -20,16 +24,25 @@ Options:
   --repo PATH                Repository path (default: .)
   --top N                    Rank top N dependencies by waste
   --scope-mode MODE          Analysis scope mode: repo, package, or changed-packages (default: package)
-  --format table|csv|json|sarif|pr-comment|cyclonedx-json
+  --format table|csv|json|ndjson|html|sarif|pr-comment|junit|gitlab-codequality|template|cyclonedx-json
                              Output format for analyse (default: table)
                              cyclonedx-json is preview-gated by sbom-attestation-exports-preview
+                             ndjson streams header, dependency, and trailer records (preview-gated by ndjson-report-stream-preview)
+                             html writes a self-contained offline report (preview-gated by html-report-preview)
+                             junit and gitlab-codequality feed CI test and code quality widgets (preview-gated by ci-report-formats-preview)
+                             template renders --template with Go text/template (preview-gated by custom-output-templates-preview)
+  --template PATH            text/template file used by --format template
   --language ID              Language adapter (default: auto)
                              Supported IDs: auto, all, js-ts, python, cpp, jvm, kotlin-android, go, php, ruby, rust, dotnet, elixir, swift, dart, powershell
   --cache=true|false         Enable or disable incremental analysis cache (default: true)
   --cache-path PATH          Cache directory path (default: <repo>/.lopper-cache)
+                             cache import: imported entries only produce hits in analyse runs that pass the same explicit --cache-path
   --cache-readonly           Read cache entries but do not write misses
+  --watch                    Keep running and re-analyse when watched files change (table re-renders, json emits NDJSON deltas;
+                             preview-gated by analyse-watch-preview)
+  --explain-exit             Write the exit code and the gate that decided it to stderr (preview-gated by exit-code-contract-preview)
   --runtime-profile PROFILE  Conditional exports runtime profile (default: node-import)
-  --baseline PATH            Baseline report (JSON) for comparison
+  --baseline PATH            Baseline report (JSON or NDJSON) for comparison
   --baseline-store DIR       Directory for immutable keyed baseline snapshots
   --baseline-key KEY         Key to load from baseline snapshot directory
   --save-baseline            Save current run as immutable baseline snapshot
@@ -57,12 +70,18 @@ Options:
   --save-baseline            Save current dashboard run as an immutable baseline snapshot
   --store DIR                Baseline discovery store (default: .artifacts/lopper-baselines)
   --limit N                  Maximum snapshots returned by baseline list (default: 50)
+  --max-age DURATION         cache prune: remove entries unused for longer than DURATION (for example 72h or 30d)
+  --max-bytes SIZE           cache prune: evict least recently used entries until the cache fits in SIZE (for example 512MB)
   --include GLOBS            Comma-separated include path globs (repeatable; CLI overrides config scope.include)
   --exclude GLOBS            Comma-separated exclude path globs (repeatable; CLI overrides config scope.exclude)
   --suggest-only             Generate deterministic patch previews for safe remediation suggestions
   --apply-codemod            Apply deterministic patch previews for safe remediation suggestions
   --apply-codemod-confirm    Required confirmation flag for --apply-codemod
   --allow-dirty              Allow --apply-codemod to run in a dirty git worktree
+  --remove-unused-dependencies
+                             Add manifest patches for a confirmed-unused dependency to the codemod preview (preview-gated by manifest-codemod-preview)
+  --manifest-action remove|demote
+                             Remove the manifest entry or move it to the dev section (default: remove)
   --config PATH              Config file path (default: repo .lopper.yml/.lopper.yaml/lopper.json)
   --enable-feature NAME      Feature flag name or code to enable (repeatable, comma-separated)
   --disable-feature NAME     Feature flag name or code to disable (repeatable, comma-separated)
@@ -71,6 +90,8 @@ Options:
   --lockfile-drift-policy MODE
                               Lockfile drift policy (off, warn, fail; default: warn)
   --license-deny SPDXS        Comma-separated denied SPDX IDs (e.g. GPL-3.0-only,AGPL-3.0-only)
+  --license-allow SPDXS       Comma-separated allowed SPDX IDs or patterns (e.g. MIT,Apache-2.0,BSD-*)
+  --license-unknown MODE      Unknown license policy (allow, warn, deny; default: allow)
   --license-fail-on-deny      Fail when denied licenses are detected
   --license-provenance-registry
                               Opt in to registry provenance heuristics for JS/TS dependencies
@@ -94,7 +115,7 @@ Options:
   --filter TEXT              Filter dependency names (TUI)
   --sort name|waste          Sort TUI output (default: waste)
   --page-size N              TUI page size (default: 10)
-  --baseline PATH            Baseline report (JSON) for TUI comparison
+  --baseline PATH            Baseline report (JSON or NDJSON) for TUI comparison
   --baseline-store DIR       Directory for immutable keyed TUI baseline snapshots
   --baseline-key KEY         Baseline snapshot key for TUI comparison
   TUI commands:
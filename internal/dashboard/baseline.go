@@ -3,11 +3,13 @@ package dashboard
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	baselineutil "github.com/ben-ranford/lopper/internal/baseline"
+	"github.com/ben-ranford/lopper/internal/report"
 )
 
 const BaselineSnapshotSchemaVersion = baselineutil.SnapshotSchemaVersion
@@ -34,8 +36,71 @@ func Load(path string) (Report, error) {
 	return rep, nil
 }
 
+// LoadWithKey loads a dashboard baseline snapshot or report, or an analyse
+// NDJSON report as a one-repo dashboard baseline.
 func LoadWithKey(path string) (Report, string, error) {
-	return baselineutil.LoadConfiguredSnapshot(path, baselineSnapshots)
+	rep, isNDJSON, err := loadNDJSONBaseline(path)
+	if err != nil {
+		return Report{}, "", err
+	}
+	if !isNDJSON {
+		return baselineutil.LoadConfiguredSnapshot(path, baselineSnapshots)
+	}
+	return rep, "", nil
+}
+
+// loadNDJSONBaseline folds each result record of an analyse NDJSON report into
+// the repo's counts as it is decoded, so the dependencies themselves are never
+// held in memory. Per-root dependency records are skipped by the reader.
+func loadNDJSONBaseline(path string) (Report, bool, error) {
+	var contribution repoSummaryContribution
+	topRiskSeverity := ""
+	topRiskRank := -1
+	deniedLicenses := 0
+	analysed, isNDJSON, err := report.LoadNDJSON(path, func(dependency report.DependencyReport) error {
+		single := report.Report{Dependencies: []report.DependencyReport{dependency}}
+		addRepoContribution(&contribution, summarizeRepo(single))
+		if severity, _ := scanRiskSignals(single.Dependencies); severity != "" && riskSeverityRank(severity) > topRiskRank {
+			topRiskSeverity, topRiskRank = severity, riskSeverityRank(severity)
+		}
+		deniedLicenses += countDeniedLicenses(single)
+		return nil
+	})
+	if err != nil || !isNDJSON {
+		return Report{}, isNDJSON, err
+	}
+	if analysed.Summary != nil && analysed.Summary.Vulnerabilities != nil {
+		contribution.VulnerabilityFindings, contribution.ReachableVulnerabilities = countVulnerabilities(analysed)
+	}
+	contribution.RuntimeRegressionCount = runtimeRegressionCount(analysed)
+
+	repoPath := strings.TrimSpace(analysed.RepoPath)
+	result := RepoResult{Name: ndjsonBaselineRepoName(repoPath), Path: repoPath}
+	populateRepoResult(&result, analysed, contribution)
+	result.TopRiskSeverity = topRiskSeverity
+	if analysed.Summary == nil {
+		result.DeniedLicenseCount = deniedLicenses
+	}
+	summary := Summary{TotalRepos: 1}
+	addSummaryContribution(&summary, contribution)
+	return Report{GeneratedAt: analysed.GeneratedAt, Repos: []RepoResult{result}, Summary: summary}, true, nil
+}
+
+func addRepoContribution(total *repoSummaryContribution, part repoSummaryContribution) {
+	total.TotalDeps += part.TotalDeps
+	total.TotalWasteCandidates += part.TotalWasteCandidates
+	total.CriticalCVEs += part.CriticalCVEs
+	total.VulnerabilityFindings += part.VulnerabilityFindings
+	total.ReachableVulnerabilities += part.ReachableVulnerabilities
+	total.RuntimeTraceData = total.RuntimeTraceData || part.RuntimeTraceData
+}
+
+func ndjsonBaselineRepoName(repoPath string) string {
+	base := filepath.Base(repoPath)
+	if base == "." || base == string(filepath.Separator) {
+		return repoPath
+	}
+	return base
 }
 
 func LoadSnapshot(dir, key string) (Report, string, string, error) {
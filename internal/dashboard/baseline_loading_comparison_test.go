@@ -11,6 +11,7 @@ import (
 	"time"
 
 	baselineutil "github.com/ben-ranford/lopper/internal/baseline"
+	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/testutil"
 )
 
@@ -430,3 +431,56 @@ func newBaselineComparison(baselineKey, currentKey string, summary SummaryDelta,
 		RepoDeltas:   []RepoDelta{repoDelta},
 	}
 }
+
+func TestDashboardBaselineLoadFoldsNDJSONReport(t *testing.T) {
+	t.Parallel()
+
+	dependencies := map[string][]report.DependencyReport{
+		"packages/a": {
+			{Name: "lodash", Recommendations: []report.Recommendation{{Code: "remove-unused-dependency"}}},
+			{Name: "left-pad", RiskCues: []report.RiskCue{{Code: "known-cve", Severity: "critical", Message: "CVE-2026-0001"}}},
+		},
+		"packages/b": {
+			{Name: "gpl-lib", License: &report.DependencyLicense{SPDX: "GPL-3.0-only"}, RiskCues: []report.RiskCue{{Code: "stale", Severity: "medium"}}},
+			{Name: "lodash"},
+		},
+	}
+	var output strings.Builder
+	stream := report.NewNDJSONWriter(&output)
+	if err := stream.WriteHeader(report.Report{}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	for _, root := range []string{"packages/a", "packages/b"} {
+		if err := stream.WriteDependencies(root, dependencies[root]); err != nil {
+			t.Fatalf("write %s: %v", root, err)
+		}
+	}
+	// The finished report merges lodash from both roots and applies license
+	// policy, which the per-root records do not carry.
+	final := report.Report{RepoPath: "/work/web", GeneratedAt: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)}
+	final.Dependencies = append(final.Dependencies, dependencies["packages/a"]...)
+	final.Dependencies = append(final.Dependencies, report.DependencyReport{Name: "gpl-lib", License: &report.DependencyLicense{SPDX: "GPL-3.0-only", Denied: true}, RiskCues: []report.RiskCue{{Code: "stale", Severity: "medium"}}})
+	if err := stream.WriteTrailer(final); err != nil {
+		t.Fatalf("write trailer: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "web.ndjson")
+	testutil.MustWriteFile(t, path, output.String())
+
+	loaded, key, err := LoadWithKey(path)
+	if err != nil {
+		t.Fatalf("LoadWithKey(ndjson) error = %v", err)
+	}
+	if key != "" || len(loaded.Repos) != 1 || loaded.Summary.TotalRepos != 1 || loaded.Summary.TotalDeps != 3 {
+		t.Fatalf("expected one folded repo, key=%q report=%#v", key, loaded)
+	}
+	repo := loaded.Repos[0]
+	if repo.Name != "web" || repo.Path != "/work/web" || repo.DependencyCount != 3 || repo.WasteCandidateCount != 1 || repo.CriticalCVEs != 1 || repo.DeniedLicenseCount != 1 || repo.TopRiskSeverity != "critical" {
+		t.Fatalf("unexpected folded repo: %#v", repo)
+	}
+
+	truncated := strings.TrimSuffix(output.String(), "\n")
+	testutil.MustWriteFile(t, path, truncated[:strings.LastIndex(truncated, "\n")+1])
+	if _, _, err := LoadWithKey(path); !errors.Is(err, report.ErrInvalidNDJSON) {
+		t.Fatalf("expected truncated ndjson baseline to fail, got %v", err)
+	}
+}
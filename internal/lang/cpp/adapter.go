@@ -35,17 +35,34 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 	}
 	result.Warnings = append(result.Warnings, compileInfo.Warnings...)
 
-	catalog, catalogWarnings, err := loadDependencyCatalog(repoPath)
+	catalog, catalogWarnings, err := loadDependencyCatalog(repoPath, req.Features.Enabled(cppPackageRecipesPreviewFeature))
 	if err != nil {
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, catalogWarnings...)
 
+	var project *cmakeProject
+	if req.Features.Enabled(cppCMakeTargetsPreviewFeature) {
+		var cmakeWarnings []string
+		project, cmakeWarnings, err = loadCMakeProject(ctx, repoPath)
+		if err != nil {
+			return report.Report{}, err
+		}
+		result.Warnings = append(result.Warnings, cmakeWarnings...)
+		project.addDeclarations(&catalog)
+		compileInfo.IncludeDirs = append(compileInfo.IncludeDirs, project.IncludeDirs...)
+	}
+
 	scan, err := scanRepo(ctx, repoPath, compileInfo, catalog)
 	if err != nil {
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, scan.Warnings...)
+	if project != nil {
+		var linkWarnings []string
+		scan.LinkFindings, linkWarnings = evaluateCMakeLinks(project, repoPath, compileInfo.IncludeDirs, catalog)
+		result.Warnings = append(result.Warnings, linkWarnings...)
+	}
 
 	dependencies, warnings := buildRequestedCPPDependencies(req, scan)
 	result.Dependencies = dependencies
@@ -0,0 +1,525 @@
+package cpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	cppCMakeTargetsPreviewFeature = "cpp-cmake-targets-preview"
+	maxCMakeFiles                 = 256
+	maxCMakeFileBytes             = 1 << 20
+
+	cmakeFindPackageSource  = "CMake find_package"
+	cmakeFetchContentSource = "CMake FetchContent"
+	cmakeCPMSource          = "CPM.cmake"
+	cmakePkgConfigSource    = "pkg-config"
+)
+
+var (
+	cmakeVariablePattern   = regexp.MustCompile(`^\$\{([A-Za-z0-9_.+-]+)\}$`)
+	cmakeLinkVarPattern    = regexp.MustCompile(`^\$\{([A-Za-z0-9_.+-]+?)_(?:LINK_LIBRARIES|LIBRARIES|LIBRARY|LIBS|LDFLAGS)\}$`)
+	pkgConfigVersionSuffix = regexp.MustCompile(`-[0-9][0-9.]*$`)
+)
+
+// cmakeHelperPackages are packages found for build tooling rather than code
+// the project compiles against.
+var cmakeHelperPackages = map[string]bool{
+	"doxygen": true, "git": true, "perl": true, "pkgconfig": true, "python": true,
+	"python2": true, "python3": true, "pythoninterp": true, "threads": true,
+}
+
+var cmakeLinkKeywords = map[string]bool{
+	"PUBLIC": true, "PRIVATE": true, "INTERFACE": true, "LINK_PUBLIC": true, "LINK_PRIVATE": true,
+	"LINK_INTERFACE_LIBRARIES": true, "debug": true, "optimized": true, "general": true,
+}
+
+var cmakeSourceKeywords = map[string]bool{
+	"PUBLIC": true, "PRIVATE": true, "INTERFACE": true, "FILE_SET": true, "TYPE": true,
+	"BASE_DIRS": true, "FILES": true, "HEADERS": true, "CXX_MODULES": true,
+	"WIN32": true, "MACOSX_BUNDLE": true, "EXCLUDE_FROM_ALL": true,
+	"STATIC": true, "SHARED": true, "MODULE": true, "OBJECT": true, "UNKNOWN": true,
+}
+
+// cmakeCommand is one command invocation with its arguments split the way
+// CMake splits unquoted and quoted arguments.
+type cmakeCommand struct {
+	Name string
+	Args []string
+	Line int
+}
+
+type cmakeDeclaration struct {
+	Dependency string
+	Source     string
+}
+
+// cmakeTarget is an executable or library the project builds. Opaque targets
+// list sources through generator expressions or unknown variables.
+type cmakeTarget struct {
+	Name    string
+	Sources []string
+	Opaque  bool
+	Links   []cmakeLink
+}
+
+type cmakeLink struct {
+	Item     string
+	Location report.Location
+}
+
+// cmakeProject is what the repository's CMakeLists.txt and .cmake files
+// declare: package dependencies, targets, the libraries they link, the
+// module lists behind each pkg_check_modules prefix, and target include
+// directories.
+type cmakeProject struct {
+	Declarations []cmakeDeclaration
+	Targets      map[string]*cmakeTarget
+	PkgConfig    map[string][]string
+	IncludeDirs  []string
+}
+
+type cmakeFileParser struct {
+	repoPath   string
+	dir        string
+	projectDir string
+	relPath    string
+	variables  map[string][]string
+	project    *cmakeProject
+}
+
+func loadCMakeProject(ctx context.Context, repoPath string) (*cmakeProject, []string, error) {
+	files := make([]string, 0)
+	warnings := make([]string, 0)
+	err := shared.WalkRepoFiles(ctx, repoPath, 0, shouldSkipCMakeDir, func(path string, entry fs.DirEntry) error {
+		if !isCMakeProjectFile(entry.Name()) {
+			return nil
+		}
+		if len(files) >= maxCMakeFiles {
+			warnings = append(warnings, fmt.Sprintf("skipped remaining CMake files after reaching limit of %d; CMake dependency data is incomplete", maxCMakeFiles))
+			return fs.SkipAll
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.SkipAll) {
+		return nil, warnings, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		left, right := strings.Count(files[i], string(filepath.Separator)), strings.Count(files[j], string(filepath.Separator))
+		if left != right {
+			return left < right
+		}
+		return files[i] < files[j]
+	})
+
+	project := &cmakeProject{Targets: make(map[string]*cmakeTarget), PkgConfig: make(map[string][]string)}
+	projectDirs := map[string]string{repoPath: repoPath}
+	for _, path := range files {
+		content, err := safeio.ReadFileUnderLimit(repoPath, path, maxCMakeFileBytes)
+		switch {
+		case err == nil:
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case shared.IsPureSentinelError(err, safeio.ErrFileTooLarge):
+			warnings = append(warnings, oversizedCPPInputWarning(relOrBase(repoPath, path), maxCMakeFileBytes))
+			continue
+		default:
+			return nil, warnings, fmt.Errorf("read %s: %w", relOrBase(repoPath, path), err)
+		}
+		commands := parseCMakeCommands(content)
+		dir := filepath.Dir(path)
+		parser := cmakeFileParser{
+			repoPath:   repoPath,
+			dir:        dir,
+			projectDir: nearestCMakeProjectDir(projectDirs, dir, repoPath),
+			relPath:    relOrBase(repoPath, path),
+			variables:  make(map[string][]string),
+			project:    project,
+		}
+		for _, command := range commands {
+			if command.Name == "project" {
+				projectDirs[dir] = dir
+				parser.projectDir = dir
+				break
+			}
+		}
+		for _, command := range commands {
+			parser.apply(command)
+		}
+	}
+	return project, warnings, nil
+}
+
+func shouldSkipCMakeDir(name string) bool {
+	lower := strings.ToLower(name)
+	return shared.ShouldSkipCommonDir(name) || lower == "_deps" || strings.HasPrefix(lower, "cmake-build-")
+}
+
+// isCMakeProjectFile accepts CMakeLists.txt and included .cmake scripts, but
+// not find modules or package config files, which describe other projects.
+func isCMakeProjectFile(name string) bool {
+	if name == cmakeListsFile {
+		return true
+	}
+	lower := strings.ToLower(name)
+	if !strings.HasSuffix(lower, ".cmake") || strings.HasPrefix(name, "Find") || lower == "cpm.cmake" {
+		return false
+	}
+	return !strings.HasSuffix(lower, "config.cmake") && !strings.HasSuffix(lower, "configversion.cmake") && !strings.HasSuffix(lower, "config-version.cmake")
+}
+
+func nearestCMakeProjectDir(projectDirs map[string]string, dir, repoPath string) string {
+	for current := dir; ; current = filepath.Dir(current) {
+		if projectDir, ok := projectDirs[current]; ok {
+			return projectDir
+		}
+		if current == repoPath || filepath.Dir(current) == current {
+			return repoPath
+		}
+	}
+}
+
+func (p *cmakeFileParser) apply(command cmakeCommand) {
+	args := command.Args
+	if len(args) == 0 {
+		return
+	}
+	switch command.Name {
+	case "set":
+		p.setVariable(args)
+	case "list":
+		if len(args) > 2 && strings.EqualFold(args[0], "APPEND") {
+			if values, ok := p.expand(args[2:]); ok {
+				p.variables[args[1]] = append(p.variables[args[1]], values...)
+			}
+		}
+	case "find_package":
+		p.declare(args[0], cmakeFindPackageSource)
+	case "fetchcontent_declare":
+		p.declare(args[0], cmakeFetchContentSource)
+	case "cpmaddpackage", "cpmfindpackage":
+		p.declare(cpmPackageName(args), cmakeCPMSource)
+	case "pkg_check_modules", "pkg_search_module":
+		p.declarePkgConfig(args)
+	case "add_executable", "add_library":
+		p.addTarget(args)
+	case "target_sources":
+		if target := p.project.Targets[args[0]]; target != nil {
+			p.addSources(target, args[1:])
+		}
+	case "target_link_libraries":
+		p.addLinks(args, command.Line)
+	case "target_include_directories":
+		p.addIncludeDirs(args[1:])
+	}
+}
+
+func (p *cmakeFileParser) setVariable(args []string) {
+	if strings.Contains(args[0], "${") {
+		return
+	}
+	values := args[1:]
+	for index, value := range values {
+		if value == "CACHE" || value == "PARENT_SCOPE" {
+			values = values[:index]
+			break
+		}
+	}
+	expanded, ok := p.expand(values)
+	if !ok {
+		delete(p.variables, args[0])
+		return
+	}
+	p.variables[args[0]] = expanded
+}
+
+func (p *cmakeFileParser) declare(name, source string) {
+	dependency := normalizeCPPDependencyID(name)
+	if dependency == "" || strings.Contains(name, "${") || cmakeHelperPackages[dependency] {
+		return
+	}
+	p.project.Declarations = append(p.project.Declarations, cmakeDeclaration{Dependency: dependency, Source: source})
+}
+
+// cpmPackageName reads the package name from CPMAddPackage's NAME keyword or
+// from its shorthand form, e.g. "gh:fmtlib/fmt#10.1.0".
+func cpmPackageName(args []string) string {
+	for index := 0; index+1 < len(args); index++ {
+		if args[index] == "NAME" {
+			return args[index+1]
+		}
+	}
+	if len(args) != 1 {
+		return ""
+	}
+	name := args[0]
+	if cut := strings.IndexAny(name, "#@"); cut >= 0 {
+		name = name[:cut]
+	}
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimSuffix(name, ".git")
+}
+
+func (p *cmakeFileParser) declarePkgConfig(args []string) {
+	if len(args) < 2 {
+		return
+	}
+	prefix := args[0]
+	for _, arg := range args[1:] {
+		switch arg {
+		case "REQUIRED", "QUIET", "IMPORTED_TARGET", "GLOBAL", "NO_CMAKE_PATH", "NO_CMAKE_ENVIRONMENT_PATH":
+			continue
+		}
+		module := arg
+		if cut := strings.IndexAny(module, "<>="); cut >= 0 {
+			module = module[:cut]
+		}
+		dependency := normalizeCPPDependencyID(pkgConfigVersionSuffix.ReplaceAllString(module, ""))
+		if dependency == "" || strings.Contains(module, "${") {
+			continue
+		}
+		p.project.Declarations = append(p.project.Declarations, cmakeDeclaration{Dependency: dependency, Source: cmakePkgConfigSource})
+		p.project.PkgConfig[prefix] = append(p.project.PkgConfig[prefix], dependency)
+	}
+}
+
+func (p *cmakeFileParser) addTarget(args []string) {
+	for _, arg := range args[1:] {
+		if arg == "IMPORTED" || arg == "ALIAS" {
+			return
+		}
+	}
+	target := p.project.Targets[args[0]]
+	if target == nil {
+		target = &cmakeTarget{Name: args[0]}
+		p.project.Targets[args[0]] = target
+	}
+	p.addSources(target, args[1:])
+}
+
+func (p *cmakeFileParser) addSources(target *cmakeTarget, args []string) {
+	for _, arg := range args {
+		if cmakeSourceKeywords[arg] {
+			continue
+		}
+		values, ok := p.expand([]string{arg})
+		if !ok {
+			target.Opaque = true
+			continue
+		}
+		for _, value := range values {
+			if isCPPSourceOrHeader(value) {
+				target.Sources = append(target.Sources, p.resolvePath(value))
+			}
+		}
+	}
+}
+
+func (p *cmakeFileParser) addLinks(args []string, line int) {
+	target := p.project.Targets[args[0]]
+	if target == nil {
+		target = &cmakeTarget{Name: args[0]}
+		p.project.Targets[args[0]] = target
+	}
+	for _, arg := range args[1:] {
+		if cmakeLinkKeywords[arg] || strings.HasPrefix(arg, "$<") {
+			continue
+		}
+		items := []string{arg}
+		if values, ok := p.expand(items); ok {
+			items = values
+		}
+		for _, item := range items {
+			target.Links = append(target.Links, cmakeLink{Item: item, Location: report.Location{File: p.relPath, Line: line}})
+		}
+	}
+}
+
+func (p *cmakeFileParser) addIncludeDirs(args []string) {
+	for _, arg := range args {
+		switch arg {
+		case "SYSTEM", "AFTER", "BEFORE", "PUBLIC", "PRIVATE", "INTERFACE":
+			continue
+		}
+		if inner, ok := strings.CutPrefix(arg, "$<BUILD_INTERFACE:"); ok {
+			arg = strings.TrimSuffix(inner, ">")
+		}
+		values, ok := p.expand([]string{arg})
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			p.project.IncludeDirs = append(p.project.IncludeDirs, p.resolvePath(value))
+		}
+	}
+}
+
+// expand substitutes directory variables and variables this file set from
+// literal values. It fails for anything else, which CMake only knows at
+// configure time.
+func (p *cmakeFileParser) expand(args []string) ([]string, bool) {
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		if match := cmakeVariablePattern.FindStringSubmatch(arg); match != nil {
+			if known, ok := p.variables[match[1]]; ok {
+				values = append(values, known...)
+				continue
+			}
+		}
+		arg = strings.NewReplacer(
+			"${CMAKE_CURRENT_SOURCE_DIR}", p.dir,
+			"${CMAKE_CURRENT_LIST_DIR}", p.dir,
+			"${PROJECT_SOURCE_DIR}", p.projectDir,
+			"${CMAKE_SOURCE_DIR}", p.repoPath,
+		).Replace(arg)
+		if strings.Contains(arg, "${") || strings.Contains(arg, "$<") {
+			return nil, false
+		}
+		values = append(values, arg)
+	}
+	return values, true
+}
+
+func (p *cmakeFileParser) resolvePath(value string) string {
+	if filepath.IsAbs(value) {
+		return filepath.Clean(value)
+	}
+	return filepath.Join(p.dir, filepath.FromSlash(value))
+}
+
+// addDeclarations records the project's CMake package declarations in the
+// dependency catalog.
+func (p *cmakeProject) addDeclarations(catalog *dependencyCatalog) {
+	for _, declaration := range p.Declarations {
+		catalog.add(declaration.Dependency, declaration.Source)
+	}
+}
+
+// parseCMakeCommands splits CMake source into command invocations, skipping
+// line and bracket comments.
+func parseCMakeCommands(content []byte) []cmakeCommand {
+	text := string(content)
+	commands := make([]cmakeCommand, 0)
+	line := 1
+	for index := 0; index < len(text); {
+		switch ch := text[index]; {
+		case ch == '\n':
+			line++
+			index++
+		case ch == '#':
+			next := skipCMakeComment(text, index)
+			line += strings.Count(text[index:next], "\n")
+			index = next
+		case isCMakeIdentifierStart(ch):
+			start := index
+			for index < len(text) && isCMakeIdentifierByte(text[index]) {
+				index++
+			}
+			name := text[start:index]
+			open := index
+			for open < len(text) && (text[open] == ' ' || text[open] == '\t') {
+				open++
+			}
+			if open >= len(text) || text[open] != '(' {
+				continue
+			}
+			args, next := readCMakeArguments(text, open+1)
+			commands = append(commands, cmakeCommand{Name: strings.ToLower(name), Args: args, Line: line})
+			line += strings.Count(text[index:next], "\n")
+			index = next
+		default:
+			index++
+		}
+	}
+	return commands
+}
+
+func skipCMakeComment(text string, index int) int {
+	if strings.HasPrefix(text[index:], "#[") {
+		rest := text[index+2:]
+		equals := len(rest) - len(strings.TrimLeft(rest, "="))
+		if strings.HasPrefix(rest[equals:], "[") {
+			closing := "]" + strings.Repeat("=", equals) + "]"
+			if end := strings.Index(rest[equals+1:], closing); end >= 0 {
+				return index + 2 + equals + 1 + end + len(closing)
+			}
+			return len(text)
+		}
+	}
+	if end := strings.IndexByte(text[index:], '\n'); end >= 0 {
+		return index + end
+	}
+	return len(text)
+}
+
+func readCMakeArguments(text string, index int) ([]string, int) {
+	args := make([]string, 0)
+	var current strings.Builder
+	quoted := false
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			args = append(args, current.String())
+		}
+		current.Reset()
+		quoted = false
+	}
+	depth := 1
+	for index < len(text) {
+		ch := text[index]
+		switch {
+		case ch == '"':
+			end := index + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' && end+1 < len(text) {
+					end++
+				}
+				end++
+			}
+			current.WriteString(text[index+1 : min(end, len(text))])
+			quoted = true
+			index = end + 1
+			continue
+		case ch == '#':
+			index = skipCMakeComment(text, index)
+			flush()
+			continue
+		case ch == '(':
+			depth++
+			current.WriteByte(ch)
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				flush()
+				return args, index + 1
+			}
+			current.WriteByte(ch)
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == ';':
+			flush()
+		default:
+			current.WriteByte(ch)
+		}
+		index++
+	}
+	flush()
+	return args, len(text)
+}
+
+func isCMakeIdentifierStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isCMakeIdentifierByte(ch byte) bool {
+	return isCMakeIdentifierStart(ch) || (ch >= '0' && ch <= '9')
+}
@@ -0,0 +1,224 @@
+package cpp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const maxTargetIncludeFiles = 1024
+
+// cmakeIncludePrefixes lists include directories of packages whose headers
+// are not named after the package.
+var cmakeIncludePrefixes = map[string][]string{
+	"eigen":    {"eigen", "unsupported"},
+	"grpc":     {"grpc"},
+	"protobuf": {"google"},
+	"qt":       {"q"},
+}
+
+// cmakeLinkFinding is a library a target links although neither the target's
+// sources nor the project headers they include include its headers.
+type cmakeLinkFinding struct {
+	Target   string
+	Item     string
+	Location report.Location
+}
+
+type cmakeFileIncludes struct {
+	Dependencies []string
+	Headers      []string
+	Readable     bool
+}
+
+type cmakeIncludeIndex struct {
+	resolver includeResolver
+	files    map[string]cmakeFileIncludes
+}
+
+// evaluateCMakeLinks reports, per dependency, the targets that link it
+// without including any of its headers.
+func evaluateCMakeLinks(project *cmakeProject, repoPath string, includeDirs []string, catalog dependencyCatalog) (map[string][]cmakeLinkFinding, []string) {
+	index := cmakeIncludeIndex{
+		resolver: includeResolver{repoPath: repoPath, includeDirs: includeDirs, catalog: catalog},
+		files:    make(map[string]cmakeFileIncludes),
+	}
+	findings := make(map[string][]cmakeLinkFinding)
+	skipped := make([]string, 0)
+	for _, name := range sortedCMakeTargets(project.Targets) {
+		target := project.Targets[name]
+		if len(target.Links) == 0 || (len(target.Sources) == 0 && !target.Opaque) {
+			continue
+		}
+		if target.Opaque {
+			skipped = append(skipped, name)
+			continue
+		}
+		included, ok := index.targetDependencies(target.Sources)
+		if !ok {
+			skipped = append(skipped, name)
+			continue
+		}
+		for _, link := range target.Links {
+			for _, dependency := range project.linkDependencies(link.Item, catalog) {
+				if includesDependency(included, dependency) {
+					continue
+				}
+				findings[dependency] = append(findings[dependency], cmakeLinkFinding{Target: name, Item: link.Item, Location: link.Location})
+			}
+		}
+	}
+	if len(skipped) == 0 {
+		return findings, nil
+	}
+	return findings, []string{fmt.Sprintf("skipped CMake link analysis for %d target(s) with generated, missing, or variable sources: %s", len(skipped), strings.Join(skipped, ", "))}
+}
+
+func sortedCMakeTargets(targets map[string]*cmakeTarget) []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// linkDependencies maps a target_link_libraries item to declared packages:
+// imported targets such as fmt::fmt by namespace, PkgConfig:: targets and
+// ${PREFIX_LIBRARIES} through pkg_check_modules, and bare names directly.
+// Targets the project builds itself are never packages.
+func (p *cmakeProject) linkDependencies(item string, catalog dependencyCatalog) []string {
+	if _, ok := p.Targets[item]; ok {
+		return nil
+	}
+	candidate := item
+	if prefix, ok := strings.CutPrefix(item, "PkgConfig::"); ok {
+		return p.PkgConfig[prefix]
+	}
+	if match := cmakeLinkVarPattern.FindStringSubmatch(item); match != nil {
+		if modules, ok := p.PkgConfig[match[1]]; ok {
+			return modules
+		}
+		candidate = match[1]
+	} else if namespace, _, ok := strings.Cut(item, "::"); ok {
+		candidate = namespace
+	}
+	if strings.ContainsAny(candidate, "${}/") {
+		return nil
+	}
+	dependency := correlateDeclaredDependency(candidate, catalog)
+	if !catalog.contains(dependency) || cmakeHelperPackages[dependency] {
+		return nil
+	}
+	return []string{dependency}
+}
+
+// includesDependency matches include-derived dependencies against a linked
+// package, ignoring lib prefixes and version digits (libpng, eigen3).
+func includesDependency(included map[string]struct{}, dependency string) bool {
+	if _, ok := included[dependency]; ok {
+		return true
+	}
+	linked := comparableCPPPackageName(dependency)
+	for include := range included {
+		name := comparableCPPPackageName(include)
+		if name == linked {
+			return true
+		}
+		for _, prefix := range cmakeIncludePrefixes[linked] {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func comparableCPPPackageName(value string) string {
+	value = strings.TrimPrefix(value, "lib")
+	value = strings.TrimRight(value, "0123456789")
+	return strings.TrimRight(value, "-_+")
+}
+
+// targetDependencies collects the dependencies a target's sources include,
+// following includes of project headers. It fails when a source cannot be
+// read, since its includes are then unknown.
+func (i *cmakeIncludeIndex) targetDependencies(sources []string) (map[string]struct{}, bool) {
+	dependencies := make(map[string]struct{})
+	visited := make(map[string]struct{})
+	queue := append([]string{}, sources...)
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[path]; ok {
+			continue
+		}
+		visited[path] = struct{}{}
+		if len(visited) > maxTargetIncludeFiles {
+			return nil, false
+		}
+		includes := i.file(path)
+		if !includes.Readable {
+			return nil, false
+		}
+		for _, dependency := range includes.Dependencies {
+			dependencies[dependency] = struct{}{}
+		}
+		queue = append(queue, includes.Headers...)
+	}
+	return dependencies, true
+}
+
+func (i *cmakeIncludeIndex) file(path string) cmakeFileIncludes {
+	if cached, ok := i.files[path]; ok {
+		return cached
+	}
+	result := cmakeFileIncludes{}
+	content, err := safeio.ReadFileUnderLimit(i.resolver.repoPath, path, maxScannableCPPFile)
+	if err == nil {
+		result.Readable = true
+		for _, include := range parseIncludes(content) {
+			if dependency, _ := i.resolver.mapIncludeToDependency(path, include); dependency != "" {
+				result.Dependencies = append(result.Dependencies, dependency)
+				continue
+			}
+			if header, ok := i.resolver.repoHeaderPath(includeLookup{sourcePath: path, header: strings.TrimSpace(include.Path)}); ok {
+				result.Headers = append(result.Headers, header)
+			}
+		}
+	}
+	i.files[path] = result
+	return result
+}
+
+// applyCMakeLinkFindings flags the targets that link the dependency without
+// including its headers.
+func applyCMakeLinkFindings(reportData *report.DependencyReport, findings []cmakeLinkFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	targets := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		location := fmt.Sprintf("%s:%d", filepath.ToSlash(finding.Location.File), finding.Location.Line)
+		reportData.RiskCues = append(reportData.RiskCues, report.RiskCue{
+			Code:     "linked-without-include",
+			Severity: "medium",
+			Message:  fmt.Sprintf("target %s links %s at %s but none of its sources include %s headers", finding.Target, finding.Item, location, reportData.Name),
+		})
+		targets = append(targets, fmt.Sprintf("%s (%s)", finding.Target, location))
+	}
+	rationale := "A linked library that no source includes is the CMake equivalent of an unused dependency; it adds link time and binary size."
+	if reportData.TotalExportsCount == 0 {
+		rationale += " No source in the repository includes its headers, so its package declaration may be removable too."
+	}
+	reportData.Recommendations = append(reportData.Recommendations, report.Recommendation{
+		Code:      "remove-unused-link",
+		Priority:  "medium",
+		Message:   fmt.Sprintf("Remove %s from target_link_libraries of %s.", reportData.Name, strings.Join(targets, ", ")),
+		Rationale: rationale,
+	})
+}
@@ -0,0 +1,148 @@
+package cpp
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+const testCMakeProject = `cmake_minimum_required(VERSION 3.24)
+project(demo CXX)
+
+#[[ find_package(Ignored) ]]
+find_package(OpenSSL REQUIRED) # crypto
+find_package(Threads REQUIRED)
+find_package(PkgConfig REQUIRED)
+pkg_check_modules(CURL REQUIRED IMPORTED_TARGET libcurl>=7.80)
+
+include(FetchContent)
+FetchContent_Declare(
+  fmt
+  GIT_REPOSITORY https://github.com/fmtlib/fmt.git
+  GIT_TAG "10.2.1"
+)
+FetchContent_MakeAvailable(fmt)
+CPMAddPackage("gh:gabime/spdlog#v1.12.0")
+
+set(CORE_SOURCES src/core.cpp)
+add_library(core STATIC ${CORE_SOURCES})
+target_include_directories(core PUBLIC $<BUILD_INTERFACE:${CMAKE_CURRENT_SOURCE_DIR}/include>)
+target_link_libraries(core PUBLIC OpenSSL::Crypto)
+
+add_executable(app src/main.cpp)
+target_link_libraries(app
+  PRIVATE
+    core
+    fmt::fmt
+    spdlog::spdlog
+    PkgConfig::CURL
+    OpenSSL::SSL
+    Threads::Threads
+)
+
+add_executable(generated ${GENERATED_SOURCES})
+target_link_libraries(generated PRIVATE fmt::fmt)
+`
+
+func TestParseCMakeCommandsSplitsArgumentsAndSkipsComments(t *testing.T) {
+	commands := parseCMakeCommands([]byte(testCMakeProject))
+	var fetch, link cmakeCommand
+	for _, command := range commands {
+		switch {
+		case command.Name == "find_package" && command.Args[0] == "Ignored":
+			t.Fatalf("expected bracket comment to be skipped")
+		case command.Name == "fetchcontent_declare":
+			fetch = command
+		case command.Name == "target_link_libraries" && command.Args[0] == "app":
+			link = command
+		}
+	}
+	if fetch.Line != 11 || !slices.Equal(fetch.Args, []string{"fmt", "GIT_REPOSITORY", "https://github.com/fmtlib/fmt.git", "GIT_TAG", "10.2.1"}) {
+		t.Fatalf("unexpected FetchContent_Declare %#v", fetch)
+	}
+	if link.Line != 25 || len(link.Args) != 8 {
+		t.Fatalf("unexpected multi-line target_link_libraries %#v", link)
+	}
+}
+
+func TestCPMPackageName(t *testing.T) {
+	cases := map[string][]string{
+		"spdlog": {"gh:gabime/spdlog#v1.12.0"},
+		"json":   {"gh:nlohmann/json@3.11.2"},
+		"catch2": {"NAME", "catch2", "GITHUB_REPOSITORY", "catchorg/Catch2"},
+		"":       {"VERSION", "1.0"},
+	}
+	for want, args := range cases {
+		if got := cpmPackageName(args); got != want {
+			t.Fatalf("expected %q from %#v, got %q", want, args, got)
+		}
+	}
+}
+
+func TestAnalyseCMakeTargetsReportsLinksWithoutIncludes(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, cmakeListsFile), testCMakeProject)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main.cpp"), "#include <core/core.h>\n#include <fmt/core.h>\n\nint main() { return 0; }\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "core.cpp"), "#include <core/core.h>\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "include", "core", "core.h"), "#pragma once\n#include <openssl/sha.h>\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "build", "_deps", "spdlog-src", cmakeListsFile), "find_package(Unrelated)\n")
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10, Features: cmakeTargetsPreview(t)})
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+	if got := dependencyNames(reportData.Dependencies); !slices.Equal(got, []string{"fmt", "openssl", "libcurl", "spdlog"}) {
+		t.Fatalf("expected CMake declarations in the catalog, got %#v", got)
+	}
+
+	spdlog := requireDependencyReport(t, reportData.Dependencies, "spdlog")
+	if !hasRiskCue(spdlog.RiskCues, "linked-without-include") || !hasRecommendation(spdlog.Recommendations, "remove-unused-link") {
+		t.Fatalf("expected spdlog link finding, got %#v", spdlog)
+	}
+	if !strings.Contains(spdlog.Recommendations[0].Message, "target_link_libraries of app (CMakeLists.txt:25)") {
+		t.Fatalf("expected target and location, got %q", spdlog.Recommendations[0].Message)
+	}
+	curl := requireDependencyReport(t, reportData.Dependencies, "libcurl")
+	if !hasRiskCue(curl.RiskCues, "linked-without-include") || !strings.Contains(curl.RiskCues[0].Message, "links PkgConfig::CURL") {
+		t.Fatalf("expected pkg-config link finding, got %#v", curl.RiskCues)
+	}
+	for _, name := range []string{"fmt", "openssl"} {
+		if dep := requireDependencyReport(t, reportData.Dependencies, name); hasRiskCue(dep.RiskCues, "linked-without-include") {
+			t.Fatalf("expected %s headers to be found through sources and project headers, got %#v", name, dep.RiskCues)
+		}
+	}
+	if !hasWarning(reportData.Warnings, "skipped CMake link analysis for 1 target(s) with generated, missing, or variable sources: generated") {
+		t.Fatalf("expected opaque target warning, got %#v", reportData.Warnings)
+	}
+
+	baseline, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10})
+	if err != nil {
+		t.Fatalf("analyse without preview: %v", err)
+	}
+	if slices.Contains(dependencyNames(baseline.Dependencies), "spdlog") {
+		t.Fatalf("expected no CMake declarations without the preview flag, got %#v", dependencyNames(baseline.Dependencies))
+	}
+}
+
+func cmakeTargetsPreview(t *testing.T) featureflags.Set {
+	t.Helper()
+	return cppPreviewFeatures(t, cppCMakeTargetsPreviewFeature)
+}
+
+func cppPreviewFeatures(t *testing.T, names ...string) featureflags.Set {
+	t.Helper()
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  names,
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	return features
+}
@@ -261,7 +261,7 @@ func TestCPPAdditionalZeroHitBranches(t *testing.T) {
 	for i := 0; i <= maxManifestFiles; i++ {
 		testutil.MustWriteFile(t, filepath.Join(repo, fmt.Sprintf("pkg-%03d", i), vcpkgManifestFile), `{"dependencies":["fmt"]}`)
 	}
-	_, warnings, err := loadDependencyCatalog(repo)
+	_, warnings, err := loadDependencyCatalog(repo, false)
 	if err != nil {
 		t.Fatalf("expected manifest discovery cap to stop cleanly, got %v", err)
 	}
@@ -0,0 +1,92 @@
+package cpp
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+)
+
+const (
+	cppPackageRecipesPreviewFeature = "cpp-package-recipes-preview"
+	conanRecipeFile                 = "conanfile.py"
+)
+
+var (
+	conanRequiresAttributePattern = regexp.MustCompile(`^\s*(requires|test_requires|build_requires|tool_requires)\s*=\s*(.*)$`)
+	conanRequiresCallPattern      = regexp.MustCompile(`\bself\.(requires|test_requires|build_requires|tool_requires)\s*\(\s*[fFrR]?(?:"([^"]*)"|'([^']*)')`)
+	pythonStringLiteralPattern    = regexp.MustCompile(`[fFrR]?(?:"([^"]*)"|'([^']*)')`)
+)
+
+// parseConanfilePy reads requirement declarations from a Conan recipe without
+// executing it: `requires`/`test_requires` class attributes holding a string,
+// tuple, or list, and literal `self.requires(...)`/`self.test_requires(...)`
+// calls. Tool and build requirements are skipped, as in conanfile.txt.
+func parseConanfilePy(content []byte) ([]string, []string) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	dependencies := make(map[string]struct{})
+	lines := strings.Split(string(content), "\n")
+	for index := 0; index < len(lines); index++ {
+		line := stripPythonComment(lines[index])
+		if match := conanRequiresAttributePattern.FindStringSubmatch(line); match != nil {
+			value := match[2]
+			for depth := pythonBracketDepth(value); depth > 0 && index+1 < len(lines); depth += pythonBracketDepth(lines[index]) {
+				index++
+				lines[index] = stripPythonComment(lines[index])
+				value += "\n" + lines[index]
+			}
+			if isConanRuntimeRequirement(match[1]) {
+				for _, literal := range pythonStringLiteralPattern.FindAllStringSubmatch(value, -1) {
+					addConanReference(literal[1]+literal[2], dependencies)
+				}
+			}
+			continue
+		}
+		for _, call := range conanRequiresCallPattern.FindAllStringSubmatch(line, -1) {
+			if isConanRuntimeRequirement(call[1]) {
+				addConanReference(call[2]+call[3], dependencies)
+			}
+		}
+	}
+	return shared.SortedKeys(dependencies), nil
+}
+
+func isConanRuntimeRequirement(kind string) bool {
+	return kind == "requires" || kind == "test_requires"
+}
+
+func stripPythonComment(line string) string {
+	var quote byte
+	for index := 0; index < len(line); index++ {
+		switch ch := line[index]; {
+		case quote != 0 && ch == '\\':
+			index++
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && (ch == '"' || ch == '\''):
+			quote = ch
+		case quote == 0 && ch == '#':
+			return line[:index]
+		}
+	}
+	return line
+}
+
+func pythonBracketDepth(value string) int {
+	depth := 0
+	for _, literal := range pythonStringLiteralPattern.FindAllStringIndex(value, -1) {
+		value = value[:literal[0]] + strings.Repeat(" ", literal[1]-literal[0]) + value[literal[1]:]
+	}
+	for _, ch := range value {
+		switch ch {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		}
+	}
+	return depth
+}
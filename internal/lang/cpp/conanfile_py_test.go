@@ -0,0 +1,78 @@
+package cpp
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestParseConanfilePyReadsRequirementDeclarations(t *testing.T) {
+	content := []byte(`from conan import ConanFile
+
+
+class DemoConan(ConanFile):
+    name = "demo"
+    requires = (
+        "fmt/10.2.1",  # formatting
+        'spdlog/1.13.0',
+    )
+    test_requires = "gtest/1.14.0"
+    tool_requires = "cmake/3.27.0"
+
+    def requirements(self):
+        self.requires("boost/1.83.0", transitive_headers=True)
+        self.requires(f"protobuf/{self.protobuf_version}")
+        self.requires(self.tested_reference_str)
+        # self.requires("ignored/1.0")
+        if self.options.with_ssl:
+            self.requires("openssl/[>=3 <4]")
+
+    def build_requirements(self):
+        self.tool_requires("ninja/1.11.1")
+        self.test_requires("catch2/3.5.0")
+`)
+	dependencies, warnings := parseConanfilePy(content)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %#v", warnings)
+	}
+	want := []string{"boost", "catch2", "fmt", "gtest", "openssl", "protobuf", "spdlog"}
+	if !slices.Equal(dependencies, want) {
+		t.Fatalf("expected %#v, got %#v", want, dependencies)
+	}
+}
+
+func TestLoadDependencyCatalogReadsConanfilePy(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, conanRecipeFile), "class App(ConanFile):\n    requires = [\"zlib/1.3\", \"protobuf/3.21.12\"]\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main.cpp"), "#include <google/protobuf/message.h>\n#include <zlib.h>\n")
+
+	catalog, _, err := loadDependencyCatalog(repo, true)
+	if err != nil {
+		t.Fatalf("load catalog: %v", err)
+	}
+	if got := catalog.sources("protobuf"); !slices.Equal(got, []string{conanRecipeFile}) {
+		t.Fatalf("expected protobuf from conanfile.py, got %#v", got)
+	}
+	scan, err := scanRepo(context.Background(), repo, compileContext{}, catalog)
+	if err != nil {
+		t.Fatalf("scan repo: %v", err)
+	}
+	got := make([]string, 0)
+	for _, include := range scan.Files[0].Includes {
+		got = append(got, include.Dependency)
+	}
+	if !slices.Equal(got, []string{"protobuf", "zlib"}) {
+		t.Fatalf("expected google/ headers attributed to protobuf, got %#v", got)
+	}
+
+	baseline, _, err := loadDependencyCatalog(repo, false)
+	if err != nil {
+		t.Fatalf("load catalog without preview: %v", err)
+	}
+	if len(baseline.list()) != 0 || len(baseline.IncludeRoots) != 0 {
+		t.Fatalf("expected no conanfile.py declarations without the preview flag, got %#v", baseline.list())
+	}
+}
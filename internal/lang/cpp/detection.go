@@ -25,6 +25,8 @@ var cppRootSignals = []shared.RootSignal{
 	{Name: vcpkgLockFile, Confidence: 20},
 	{Name: conanManifestFile, Confidence: 35},
 	{Name: conanLockFile, Confidence: 20},
+	{Name: conanRecipeFile, Confidence: 20},
+	{Name: mesonBuildFile, Confidence: 35},
 }
 
 func (a *Adapter) DetectWithConfidence(ctx context.Context, repoPath string) (language.Detection, error) {
@@ -55,9 +57,9 @@ func updateDetection(path string, detection *language.Detection, roots map[strin
 		markDetection(detection, roots, filepath.Dir(path), 12)
 	case "Makefile", "makefile", "GNUmakefile":
 		markDetection(detection, roots, filepath.Dir(path), 10)
-	case vcpkgManifestFile, conanManifestFile:
+	case vcpkgManifestFile, conanManifestFile, mesonBuildFile:
 		markDetection(detection, roots, filepath.Dir(path), 12)
-	case vcpkgLockFile, conanLockFile:
+	case vcpkgLockFile, conanLockFile, conanRecipeFile:
 		markDetection(detection, roots, filepath.Dir(path), 8)
 	}
 
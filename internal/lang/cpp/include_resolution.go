@@ -44,6 +44,7 @@ type scanResult struct {
 	UnresolvedSamples []string
 	SkippedLargeFiles int
 	Catalog           dependencyCatalog
+	LinkFindings      map[string][]cmakeLinkFinding
 }
 
 type includeResolver struct {
@@ -148,6 +149,9 @@ func (s *scanStage) process(ctx context.Context, path string) error {
 	if ctx != nil && ctx.Err() != nil {
 		return ctx.Err()
 	}
+	if s.scanner.catalog.packageDirDependency(path) != "" {
+		return nil
+	}
 
 	scanFile, unresolvedSamples, unresolvedCount, err := s.scanner.scanFile(path)
 	if err != nil {
@@ -348,11 +352,11 @@ func (r *includeResolver) mapIncludeToDependency(sourcePath string, include pars
 	if isLikelyStdHeader(header) {
 		return "", false
 	}
-	if r.includeResolvesWithinRepo(includeLookup{
+	if path, ok := r.repoHeaderPath(includeLookup{
 		sourcePath: sourcePath,
 		header:     header,
-	}) {
-		return "", false
+	}); ok {
+		return r.catalog.packageDirDependency(path), false
 	}
 	if include.Delimiter == '"' {
 		return "", true
@@ -362,10 +366,13 @@ func (r *includeResolver) mapIncludeToDependency(sourcePath string, include pars
 	if dependency == "" {
 		return "", true
 	}
+	if owner := r.catalog.includeOwner(dependency); owner != "" {
+		return owner, false
+	}
 	return correlateDeclaredDependency(dependency, r.catalog), false
 }
 
-func (r *includeResolver) includeResolvesWithinRepo(include includeLookup) bool {
+func (r *includeResolver) repoHeaderPath(include includeLookup) (string, bool) {
 	sourceDir := filepath.Dir(include.sourcePath)
 	candidates := []string{filepath.Join(sourceDir, filepath.FromSlash(include.header))}
 	for _, includeDir := range r.includeDirs {
@@ -377,10 +384,10 @@ func (r *includeResolver) includeResolvesWithinRepo(include includeLookup) bool
 			continue
 		}
 		if shared.IsPathWithin(r.repoPath, candidate) {
-			return true
+			return candidate, true
 		}
 	}
-	return false
+	return "", false
 }
 
 func dependencyFromIncludePath(header string) string {
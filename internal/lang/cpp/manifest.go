@@ -27,6 +27,8 @@ const (
 
 type dependencyCatalog struct {
 	Declarations map[string]declaredDependency
+	IncludeRoots map[string]string
+	PackageDirs  map[string]string
 	Incomplete   bool
 }
 
@@ -39,7 +41,11 @@ type vcpkgManifest struct {
 }
 
 func newDependencyCatalog() dependencyCatalog {
-	return dependencyCatalog{Declarations: make(map[string]declaredDependency)}
+	return dependencyCatalog{
+		Declarations: make(map[string]declaredDependency),
+		IncludeRoots: make(map[string]string),
+		PackageDirs:  make(map[string]string),
+	}
 }
 
 func (c *dependencyCatalog) add(dependency, source string) {
@@ -82,14 +88,30 @@ func (c *dependencyCatalog) sources(dependency string) []string {
 	return items
 }
 
-func loadDependencyCatalog(repoPath string) (dependencyCatalog, []string, error) {
+// loadDependencyCatalog reads vcpkg and Conan manifests. packageRecipes adds
+// conanfile.py requirements, Meson wraps and dependency() calls, and the
+// include roots of packages whose headers are not named after them.
+func loadDependencyCatalog(repoPath string, packageRecipes bool) (dependencyCatalog, []string, error) {
 	catalog := newDependencyCatalog()
 	warnings := make([]string, 0)
 	manifestCount := 0
+	meson := mesonFiles{}
 
 	err := shared.WalkRepoFiles(context.Background(), repoPath, 0, shared.ShouldSkipCommonDir, func(path string, entry fs.DirEntry) error {
-		switch filepath.Base(path) {
-		case vcpkgManifestFile, vcpkgLockFile, conanManifestFile, conanLockFile:
+		if packageRecipes && meson.collect(repoPath, path) {
+			if meson.count() > maxMesonFiles {
+				catalog.Incomplete = true
+				warnings = append(warnings, fmt.Sprintf("skipped remaining Meson files after reaching limit of %d; dependency catalog is incomplete", maxMesonFiles))
+				return fs.SkipAll
+			}
+			return nil
+		}
+		name := filepath.Base(path)
+		if name == conanRecipeFile && !packageRecipes {
+			return nil
+		}
+		switch name {
+		case vcpkgManifestFile, vcpkgLockFile, conanManifestFile, conanLockFile, conanRecipeFile:
 			manifestCount++
 			if manifestCount > maxManifestFiles {
 				catalog.Incomplete = true
@@ -108,6 +130,14 @@ func loadDependencyCatalog(repoPath string) (dependencyCatalog, []string, error)
 		return catalog, warnings, err
 	}
 
+	if packageRecipes {
+		mesonWarnings, err := loadMesonDeclarations(repoPath, meson, &catalog)
+		warnings = append(warnings, mesonWarnings...)
+		if err != nil {
+			return catalog, warnings, err
+		}
+		catalog.applyKnownIncludeRoots()
+	}
 	return catalog, dedupeCPPWarnings(warnings), nil
 }
 
@@ -144,6 +174,12 @@ func loadDependencyManifest(repoPath, path string, catalog *dependencyCatalog) (
 	case conanLockFile:
 		dependencies, warnings = parseConanLock(content)
 		source = "conan.lock"
+	case conanRecipeFile:
+		if isInsideMesonSubproject(filepath.ToSlash(relPath)) {
+			return nil, nil
+		}
+		dependencies, warnings = parseConanfilePy(content)
+		source = conanRecipeFile
 	default:
 		return nil, nil
 	}
@@ -50,7 +50,7 @@ gtest/1.14.0
   }
 }`)
 
-	catalog, warnings, err := loadDependencyCatalog(repo)
+	catalog, warnings, err := loadDependencyCatalog(repo, false)
 	if err != nil {
 		t.Fatalf("loadDependencyCatalog: %v", err)
 	}
@@ -75,7 +75,7 @@ func TestLoadDependencyCatalogInvalidManifestWarning(t *testing.T) {
 	repo := t.TempDir()
 	testutil.MustWriteFile(t, filepath.Join(repo, vcpkgManifestFile), `{`)
 
-	_, warnings, err := loadDependencyCatalog(repo)
+	_, warnings, err := loadDependencyCatalog(repo, false)
 	if err != nil {
 		t.Fatalf("loadDependencyCatalog: %v", err)
 	}
@@ -100,7 +100,7 @@ func TestLoadDependencyCatalogSkipsOversizedManifests(t *testing.T) {
 			repo := t.TempDir()
 			testutil.MustWriteFile(t, filepath.Join(repo, manifestName), oversizedManifestContent())
 
-			catalog, warnings, err := loadDependencyCatalog(repo)
+			catalog, warnings, err := loadDependencyCatalog(repo, false)
 			if err != nil {
 				t.Fatalf("loadDependencyCatalog: %v", err)
 			}
@@ -194,7 +194,7 @@ func TestLoadDependencyCatalogAcceptsExactSizeManifestLimit(t *testing.T) {
 	content += strings.Repeat("#", testMaxManifestBytes-len(content))
 	testutil.MustWriteFile(t, filepath.Join(repo, conanManifestFile), content)
 
-	catalog, warnings, err := loadDependencyCatalog(repo)
+	catalog, warnings, err := loadDependencyCatalog(repo, false)
 	if err != nil {
 		t.Fatalf("loadDependencyCatalog: %v", err)
 	}
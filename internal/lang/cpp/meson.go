@@ -0,0 +1,186 @@
+package cpp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	mesonBuildFile        = "meson.build"
+	mesonSubprojectsDir   = "subprojects"
+	mesonWrapExtension    = ".wrap"
+	mesonWrapSource       = "Meson wrap"
+	mesonDependencySource = "meson.build"
+	maxMesonFiles         = 256
+)
+
+var mesonDependencyPattern = regexp.MustCompile(`(?:^|[^.\w])dependency\s*\(\s*\[?\s*'([^']+)'`)
+
+// mesonBuiltinDependencies are dependency() names Meson resolves itself
+// rather than through a package.
+var mesonBuiltinDependencies = map[string]bool{
+	"appleframeworks": true, "dl": true, "openmp": true, "threads": true,
+}
+
+// mesonWrap is a subprojects/*.wrap file: the subproject it fetches, the
+// directory it unpacks into, and the dependency names it provides.
+type mesonWrap struct {
+	Directory string
+	Provides  []string
+}
+
+type mesonFiles struct {
+	Wraps  []string
+	Builds []string
+}
+
+// collect records wrap files directly under a subprojects directory and the
+// project's own meson.build files; build files of unpacked subprojects
+// belong to those packages.
+func (m *mesonFiles) collect(repoPath, path string) bool {
+	relPath := filepath.ToSlash(relOrBase(repoPath, path))
+	switch {
+	case strings.HasSuffix(relPath, mesonWrapExtension) && filepath.Base(filepath.Dir(path)) == mesonSubprojectsDir:
+		m.Wraps = append(m.Wraps, path)
+	case filepath.Base(path) == mesonBuildFile && !isInsideMesonSubproject(relPath):
+		m.Builds = append(m.Builds, path)
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *mesonFiles) count() int {
+	return len(m.Wraps) + len(m.Builds)
+}
+
+func isInsideMesonSubproject(relPath string) bool {
+	return strings.HasPrefix(relPath, mesonSubprojectsDir+"/") || strings.Contains(relPath, "/"+mesonSubprojectsDir+"/")
+}
+
+// loadMesonDeclarations adds wrap subprojects and dependency() calls to the
+// catalog. Wraps are read first so dependency names they provide resolve to
+// the subproject, and unpacked subproject directories map their include
+// directories to it.
+func loadMesonDeclarations(repoPath string, files mesonFiles, catalog *dependencyCatalog) ([]string, error) {
+	warnings := make([]string, 0)
+	aliases := make(map[string]string)
+	for _, path := range files.Wraps {
+		content, warning, err := readMesonFile(repoPath, path, catalog)
+		if err != nil {
+			return warnings, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), mesonWrapExtension)
+		wrap, ok := parseMesonWrap(content, name)
+		dependency := normalizeMesonDependency(name)
+		if !ok || dependency == "" {
+			continue
+		}
+		catalog.add(dependency, mesonWrapSource)
+		for _, provided := range wrap.Provides {
+			aliases[normalizeMesonDependency(provided)] = dependency
+		}
+		catalog.addPackageDir(filepath.Join(filepath.Dir(path), filepath.FromSlash(wrap.Directory)), dependency)
+	}
+	for _, path := range files.Builds {
+		content, warning, err := readMesonFile(repoPath, path, catalog)
+		if err != nil {
+			return warnings, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+			continue
+		}
+		for _, name := range parseMesonDependencies(content) {
+			dependency := normalizeMesonDependency(name)
+			if alias, ok := aliases[dependency]; ok {
+				dependency = alias
+			}
+			if dependency != "" && !mesonBuiltinDependencies[dependency] {
+				catalog.add(dependency, mesonDependencySource)
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func readMesonFile(repoPath, path string, catalog *dependencyCatalog) ([]byte, string, error) {
+	content, err := safeio.ReadFileUnderLimit(repoPath, path, maxManifestBytes)
+	switch {
+	case err == nil:
+		return content, "", nil
+	case errors.Is(err, os.ErrNotExist):
+		return nil, "", nil
+	case shared.IsPureSentinelError(err, safeio.ErrFileTooLarge):
+		catalog.Incomplete = true
+		return nil, fmt.Sprintf("skipped oversized %s: %v", relOrBase(repoPath, path), err), nil
+	default:
+		return nil, "", fmt.Errorf("read %s: %w", relOrBase(repoPath, path), err)
+	}
+}
+
+// parseMesonWrap reads a wrap file's unpack directory and [provide] section.
+// Redirect wraps point at another subproject's wrap and declare nothing.
+func parseMesonWrap(content []byte, name string) (mesonWrap, bool) {
+	wrap := mesonWrap{Directory: name}
+	section := ""
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if section == "wrap-redirect" {
+				return mesonWrap{}, false
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(section, "wrap-") && key == "directory" && value != "":
+			wrap.Directory = value
+		case section == "provide" && key == "dependency_names":
+			for _, provided := range strings.Split(value, ",") {
+				if provided = strings.TrimSpace(provided); provided != "" {
+					wrap.Provides = append(wrap.Provides, provided)
+				}
+			}
+		case section == "provide" && key != "program_names":
+			wrap.Provides = append(wrap.Provides, key)
+		}
+	}
+	return wrap, true
+}
+
+func parseMesonDependencies(content []byte) []string {
+	names := make([]string, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		line = shared.StripLineComment(line, "#")
+		for _, match := range mesonDependencyPattern.FindAllStringSubmatch(line, -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// normalizeMesonDependency drops pkg-config API version suffixes, so
+// dependency('glib-2.0') and glib.wrap name the same package.
+func normalizeMesonDependency(name string) string {
+	return normalizeCPPDependencyID(pkgConfigVersionSuffix.ReplaceAllString(strings.TrimSpace(name), ""))
+}
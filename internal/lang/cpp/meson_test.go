@@ -0,0 +1,83 @@
+package cpp
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestParseMesonWrapAndDependencies(t *testing.T) {
+	wrap, ok := parseMesonWrap([]byte(`[wrap-file]
+directory = glib-2.78.0
+source_url = https://download.gnome.org/sources/glib/2.78/glib-2.78.0.tar.xz
+
+[provide]
+dependency_names = glib-2.0, gobject-2.0
+gio-2.0 = gio_dep
+program_names = glib-compile-resources
+`), "glib")
+	if !ok || wrap.Directory != "glib-2.78.0" || !slices.Equal(wrap.Provides, []string{"glib-2.0", "gobject-2.0", "gio-2.0"}) {
+		t.Fatalf("unexpected wrap %#v", wrap)
+	}
+	if _, ok := parseMesonWrap([]byte("[wrap-redirect]\nfilename = other/subprojects/foo.wrap\n"), "foo"); ok {
+		t.Fatalf("expected redirect wraps to be skipped")
+	}
+
+	names := parseMesonDependencies([]byte(`zlib_dep = dependency('zlib', version: '>=1.2')
+# dependency('commented')
+gobject_dep = dependency('gobject-2.0')
+own_dep = declare_dependency(include_directories: inc)
+threads_dep = dependency('threads')
+`))
+	if !slices.Equal(names, []string{"zlib", "gobject-2.0", "threads"}) {
+		t.Fatalf("unexpected dependency names %#v", names)
+	}
+}
+
+func TestAnalyseMesonProjectAttributesSubprojectHeaders(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonBuildFile), `project('demo', 'cpp')
+fmt_dep = dependency('fmt')
+gobject_dep = dependency('gobject-2.0')
+threads_dep = dependency('threads')
+subdir('src')
+`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", mesonBuildFile), "json_dep = dependency('nlohmann_json')\nexecutable('demo', 'main.cpp')\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main.cpp"), "#include <fmt/core.h>\n#include <glib-object.h>\n#include \"format.h\"\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "format.h"), "#include <nlohmann/json.hpp>\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "fmt.wrap"), "[wrap-file]\ndirectory = fmt-10.2.0\n\n[provide]\nfmt = fmt_dep\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "glib.wrap"), "[wrap-file]\ndirectory = glib-2.78.0\n\n[provide]\ndependency_names = glib-2.0, gobject-2.0\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "glib-2.78.0", "glib-object.h"), "#include <gobject/gobject.h>\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "glib-2.78.0", "gobject", "gobject.c"), "#include <stdio.h>\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "glib-2.78.0", mesonBuildFile), "dependency('libffi')\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "fmt-10.2.0", "include", "fmt", "core.h"), "#pragma once\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, mesonSubprojectsDir, "fmt-10.2.0", "src", "format.cc"), "#include <fmt/core.h>\n")
+
+	catalog, _, err := loadDependencyCatalog(repo, true)
+	if err != nil {
+		t.Fatalf("load catalog: %v", err)
+	}
+	if got := catalog.list(); !slices.Equal(got, []string{"fmt", "glib", "nlohmann_json"}) {
+		t.Fatalf("expected wraps and dependency() calls without builtins or subproject files, got %#v", got)
+	}
+	if got := catalog.sources("glib"); !slices.Equal(got, []string{mesonWrapSource, mesonDependencySource}) {
+		t.Fatalf("expected gobject-2.0 to resolve to the glib wrap, got %#v", got)
+	}
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10, Features: cppPreviewFeatures(t, cppPackageRecipesPreviewFeature)})
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+	for _, name := range []string{"fmt", "glib"} {
+		if dep := requireDependencyReport(t, reportData.Dependencies, name); dep.UsedExportsCount != 1 {
+			t.Fatalf("expected %s headers attributed to the subproject, got %#v", name, dep)
+		}
+	}
+	if got := dependencyNames(reportData.Dependencies); slices.Contains(got, "stdio") || len(got) != 3 {
+		t.Fatalf("expected subproject sources to be excluded from the scan, got %#v", got)
+	}
+}
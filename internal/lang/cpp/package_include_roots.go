@@ -0,0 +1,127 @@
+package cpp
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+)
+
+// knownPackageIncludeRoots lists the top-level include directories or headers
+// of packages whose headers are not named after the package, keyed by the
+// Conan, vcpkg, or Meson package name. Tokens are in dependencyFromIncludePath
+// form: the first path segment, lowercased, without a file extension.
+var knownPackageIncludeRoots = map[string][]string{
+	"abseil":        {"absl"},
+	"bzip2":         {"bzlib"},
+	"eigen":         {"eigen", "unsupported"},
+	"gflags":        {"gflags"},
+	"glib":          {"glib", "gio", "gmodule", "gobject"},
+	"grpc":          {"grpc", "grpcpp"},
+	"gtest":         {"gmock", "gtest"},
+	"libcurl":       {"curl"},
+	"libevent":      {"event", "event2"},
+	"libjpeg":       {"jconfig", "jerror", "jmorecfg", "jpeglib"},
+	"libjpeg-turbo": {"jconfig", "jerror", "jmorecfg", "jpeglib", "turbojpeg"},
+	"libpng":        {"png", "pngconf"},
+	"libsodium":     {"sodium"},
+	"libuv":         {"uv"},
+	"libxml2":       {"libxml"},
+	"lz4":           {"lz4", "lz4frame", "lz4hc"},
+	"ms-gsl":        {"gsl"},
+	"nlohmann_json": {"nlohmann"},
+	"protobuf":      {"google"},
+	"range-v3":      {"meta", "range"},
+	"xz_utils":      {"lzma"},
+	"zlib":          {"zconf", "zlib"},
+	"zstd":          {"zdict", "zstd"},
+}
+
+var packageIncludeSkippedDirs = map[string]bool{
+	"doc": true, "docs": true, "example": true, "examples": true, "src": true,
+	"subprojects": true, "test": true, "tests": true,
+}
+
+func (c *dependencyCatalog) addIncludeRoot(token, dependency string) {
+	if token == "" || dependency == "" {
+		return
+	}
+	if c.IncludeRoots == nil {
+		c.IncludeRoots = make(map[string]string)
+	}
+	if _, ok := c.IncludeRoots[token]; !ok {
+		c.IncludeRoots[token] = dependency
+	}
+}
+
+// includeOwner returns the declared package that provides headers under the
+// include token, if a package other than the token's namesake does.
+func (c *dependencyCatalog) includeOwner(token string) string {
+	if c.contains(token) {
+		return ""
+	}
+	return c.IncludeRoots[token]
+}
+
+// addPackageDir records an unpacked package source directory inside the repo
+// and maps the top-level entries of its include directory, or the headers at
+// its root when it has none, to the package.
+func (c *dependencyCatalog) addPackageDir(dir, dependency string) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	if c.PackageDirs == nil {
+		c.PackageDirs = make(map[string]string)
+	}
+	c.PackageDirs[filepath.Clean(dir)] = dependency
+
+	includeDir := filepath.Join(dir, "include")
+	entries, err := os.ReadDir(includeDir)
+	headersOnly := err != nil
+	if headersOnly {
+		entries, err = os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir() && !headersOnly && !packageIncludeSkippedDirs[strings.ToLower(name)]:
+			c.addIncludeRoot(dependencyFromIncludePath(name), dependency)
+		case !entry.IsDir() && isCPPSourceOrHeader(name) && !isCPPSourceFile(name):
+			c.addIncludeRoot(dependencyFromIncludePath(name), dependency)
+		}
+	}
+}
+
+// packageDirDependency returns the package whose unpacked sources contain path.
+func (c *dependencyCatalog) packageDirDependency(path string) string {
+	if len(c.PackageDirs) == 0 {
+		return ""
+	}
+	dirs := make([]string, 0, len(c.PackageDirs))
+	for dir := range c.PackageDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if shared.IsPathWithin(dir, path) {
+			return c.PackageDirs[dir]
+		}
+	}
+	return ""
+}
+
+// applyKnownIncludeRoots maps the include roots of well-known packages for
+// each declared package.
+func (c *dependencyCatalog) applyKnownIncludeRoots() {
+	for _, dependency := range c.list() {
+		for _, token := range knownPackageIncludeRoots[dependency] {
+			c.addIncludeRoot(token, dependency)
+		}
+	}
+}
@@ -56,6 +56,7 @@ func buildDependencyReport(dependency string, scan scanResult, warnOnNoUsage boo
 
 	warnings := buildDependencyUsageWarnings(dependency, scan.Catalog, declared, reportData.TotalExportsCount, warnOnNoUsage)
 	addUndeclaredUsageSignals(&reportData, dependency, declared, scan.Catalog.Incomplete, &warnings)
+	applyCMakeLinkFindings(&reportData, scan.LinkFindings[dependency])
 	return reportData, warnings
 }
 
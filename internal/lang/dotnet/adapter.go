@@ -18,6 +18,7 @@ const (
 	csharpProjectExt    = ".csproj"
 	fsharpProjectExt    = ".fsproj"
 	solutionFileExt     = ".sln"
+	xmlSolutionFileExt  = ".slnx"
 	csharpSourceExt     = ".cs"
 	fsharpSourceExt     = ".fs"
 	maxDetectFiles      = 1024
@@ -41,7 +42,15 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		RepoPath:    repoPath,
 	}
 
-	scan, err := scanRepo(ctx, repoPath)
+	options := scanOptions{
+		projectGraph:  req.Features.Enabled(dotnetProjectGraphPreviewFeature),
+		projectUsings: req.Features.Enabled(dotnetProjectUsingsPreviewFeature),
+		fileCache:     req.FileCache,
+	}
+	if req.Features.Enabled(dotnetAssemblyIndexPreviewFeature) {
+		options.nugetPackagesRoot = defaultNuGetPackagesRoot()
+	}
+	scan, err := scanRepoWithOptions(ctx, repoPath, options)
 	if err != nil {
 		return report.Report{}, err
 	}
@@ -67,11 +76,13 @@ type fileScan struct {
 }
 
 type scanResult struct {
-	Files                  []fileScan
-	DeclaredDependencies   []string
-	Warnings               []string
-	AmbiguousByDependency  map[string]int
-	UndeclaredByDependency map[string]int
-	SkippedGeneratedFiles  int
-	SkippedFileLimit       bool
+	Files                         []fileScan
+	DeclaredDependencies          []string
+	Warnings                      []string
+	Assemblies                    assemblyIndex
+	AmbiguousByDependency         map[string]int
+	UndeclaredByDependency        map[string]int
+	ProjectUndeclaredByDependency map[string]int
+	SkippedGeneratedFiles         int
+	SkippedFileLimit              bool
 }
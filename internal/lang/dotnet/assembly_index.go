@@ -0,0 +1,284 @@
+package dotnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	dotnetAssemblyIndexPreviewFeature = "dotnet-assembly-index-preview"
+	maxIndexedAssemblyBytes           = 64 << 20
+	maxIndexedAssembliesPerPackage    = 64
+)
+
+// packageAssemblies is the export surface of one declared package, read from
+// the lib/<tfm> assemblies of its newest version in the NuGet global packages
+// folder.
+type packageAssemblies struct {
+	Dependency  string
+	Location    string
+	Namespaces  []string
+	PublicTypes int
+}
+
+// assemblyIndex maps namespaces and public types to the declared packages
+// whose assemblies define them, so usings resolve exactly instead of by name
+// similarity.
+type assemblyIndex struct {
+	packages   map[string]packageAssemblies
+	namespaces map[string][]string
+	types      map[string]map[string]string
+}
+
+func defaultNuGetPackagesRoot() string {
+	if value := strings.TrimSpace(os.Getenv("NUGET_PACKAGES")); value != "" {
+		return value
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".nuget", "packages")
+}
+
+// indexNuGetAssemblies reads the assemblies of each declared package found in
+// the global packages folder. Packages that are not restored keep the
+// name-similarity mapping.
+func indexNuGetAssemblies(dependencies []string, root string) (assemblyIndex, []string) {
+	index := assemblyIndex{
+		packages:   make(map[string]packageAssemblies),
+		namespaces: make(map[string][]string),
+		types:      make(map[string]map[string]string),
+	}
+	warnings := make([]string, 0)
+	if root == "" {
+		return index, warnings
+	}
+	for _, dependency := range dependencies {
+		dependency = normalizeDependencyID(dependency)
+		if dependency == "" || strings.ContainsAny(dependency, `/\`) {
+			continue
+		}
+		libDir, location := nugetPackageLibDir(root, dependency)
+		if libDir == "" {
+			continue
+		}
+		types, assemblyWarnings := readPackageAssemblies(root, libDir)
+		warnings = append(warnings, assemblyWarnings...)
+		if len(types) == 0 {
+			continue
+		}
+		index.add(dependency, location, types)
+	}
+	return index, warnings
+}
+
+// nugetPackageLibDir returns the lib folder for the preferred target
+// framework of the newest restored version of a package.
+func nugetPackageLibDir(root, dependency string) (string, string) {
+	version := newestNuGetVersion(listDirNames(filepath.Join(root, dependency)))
+	if version == "" {
+		return "", ""
+	}
+	libDir := filepath.Join(root, dependency, version, "lib")
+	framework := preferredTargetFramework(listDirNames(libDir))
+	if framework == "" {
+		return "", ""
+	}
+	return filepath.Join(libDir, framework), filepath.ToSlash(filepath.Join("NUGET_PACKAGES", dependency, version, "lib", framework))
+}
+
+func listDirNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func readPackageAssemblies(root, libDir string) ([]assemblyType, []string) {
+	matches, err := filepath.Glob(filepath.Join(libDir, "*.dll"))
+	if err != nil || len(matches) == 0 {
+		return nil, nil
+	}
+	sort.Strings(matches)
+	if len(matches) > maxIndexedAssembliesPerPackage {
+		matches = matches[:maxIndexedAssembliesPerPackage]
+	}
+	types := make([]assemblyType, 0)
+	warnings := make([]string, 0)
+	for _, path := range matches {
+		content, err := safeio.ReadFileUnderLimit(root, path, maxIndexedAssemblyBytes)
+		if err == nil {
+			var assemblyTypes []assemblyType
+			assemblyTypes, err = readAssemblyTypes(content)
+			types = append(types, assemblyTypes...)
+		}
+		if err != nil && !errors.Is(err, errNotManagedAssembly) {
+			warnings = append(warnings, fmt.Sprintf("unable to index assembly %s: %v", assemblyLabel(root, path), err))
+		}
+	}
+	return types, warnings
+}
+
+func assemblyLabel(root, path string) string {
+	relative, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(filepath.Join("NUGET_PACKAGES", relative))
+}
+
+// add registers a package's types. A namespace split across packages keeps
+// every owner, and a type defined twice resolves to the first package by name.
+func (idx assemblyIndex) add(dependency, location string, types []assemblyType) {
+	namespaces := make(map[string]struct{})
+	publicTypes := make(map[string]struct{})
+	for _, item := range types {
+		name := metadataTypeName(item.Name)
+		namespaces[item.Namespace] = struct{}{}
+		publicTypes[item.Namespace+"."+name] = struct{}{}
+		if item.Namespace == "" {
+			continue
+		}
+		byName, ok := idx.types[item.Namespace]
+		if !ok {
+			byName = make(map[string]string)
+			idx.types[item.Namespace] = byName
+		}
+		if _, ok := byName[name]; !ok {
+			byName[name] = dependency
+		}
+	}
+	pkg := packageAssemblies{Dependency: dependency, Location: location, PublicTypes: len(publicTypes)}
+	for namespace := range namespaces {
+		if namespace == "" {
+			continue
+		}
+		pkg.Namespaces = append(pkg.Namespaces, namespace)
+		idx.namespaces[namespace] = append(idx.namespaces[namespace], dependency)
+		sort.Strings(idx.namespaces[namespace])
+	}
+	sort.Strings(pkg.Namespaces)
+	idx.packages[dependency] = pkg
+}
+
+// metadataTypeName drops the generic arity suffix metadata adds to type names.
+func metadataTypeName(name string) string {
+	if index := strings.IndexByte(name, '`'); index > 0 {
+		return name[:index]
+	}
+	return name
+}
+
+func (idx assemblyIndex) pkg(dependency string) (packageAssemblies, bool) {
+	pkg, ok := idx.packages[normalizeDependencyID(dependency)]
+	return pkg, ok
+}
+
+func (idx assemblyIndex) hasNamespace(namespace string) bool {
+	return len(idx.namespaces[namespace]) > 0
+}
+
+// namespaceOwner resolves a using target to the package that defines it: an
+// indexed namespace, or a type for `using static` and alias directives.
+func (idx assemblyIndex) namespaceOwner(module string) string {
+	if owners := idx.namespaces[module]; len(owners) > 0 {
+		return owners[0]
+	}
+	if index := strings.LastIndexByte(module, '.'); index > 0 {
+		return idx.typeOwner(module[:index], module[index+1:])
+	}
+	return ""
+}
+
+func (idx assemblyIndex) typeOwner(namespace, name string) string {
+	return idx.types[namespace][name]
+}
+
+func (idx assemblyIndex) empty() bool {
+	return len(idx.packages) == 0
+}
+
+// applyAssemblyExportSurface measures usage against the public types of the
+// package's assemblies rather than the imports observed in source.
+func applyAssemblyExportSurface(dep *report.DependencyReport, pkg packageAssemblies, referenced bool) {
+	dep.TotalExportsCount = pkg.PublicTypes
+	dep.UsedExportsCount = min(dep.UsedExportsCount, pkg.PublicTypes)
+	dep.UsedPercent = float64(dep.UsedExportsCount) / float64(pkg.PublicTypes) * 100
+	if referenced {
+		return
+	}
+	dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+		Code:     "unreferenced-package",
+		Severity: "medium",
+		Message:  fmt.Sprintf("none of the %d public types in %s are referenced", pkg.PublicTypes, pkg.Location),
+	})
+}
+
+// newestNuGetVersion picks the highest version folder, ranking releases above
+// prereleases of the same version.
+func newestNuGetVersion(versions []string) string {
+	best := ""
+	for _, version := range versions {
+		if best == "" || shared.CompareVersionStrings(version, best) > 0 {
+			best = version
+		}
+	}
+	return best
+}
+
+// preferredTargetFramework picks the lib folder a modern SDK project would
+// most likely consume: net5.0+ over netcoreapp over netstandard over .NET
+// Framework, the highest version within a family, and portable builds over
+// platform-specific ones.
+func preferredTargetFramework(frameworks []string) string {
+	best := ""
+	bestRank, bestVersion := -1, ""
+	for _, framework := range frameworks {
+		rank, version := targetFrameworkRank(framework)
+		if rank > bestRank || (rank == bestRank && shared.CompareVersionStrings(version, bestVersion) > 0) {
+			best, bestRank, bestVersion = framework, rank, version
+		}
+	}
+	return best
+}
+
+func targetFrameworkRank(framework string) (int, string) {
+	lower := strings.ToLower(framework)
+	name, platform, _ := strings.Cut(lower, "-")
+	family, version := 0, ""
+	switch {
+	case strings.HasPrefix(name, "netcoreapp"):
+		family, version = 3, strings.TrimPrefix(name, "netcoreapp")
+	case strings.HasPrefix(name, "netstandard"):
+		family, version = 2, strings.TrimPrefix(name, "netstandard")
+	case strings.HasPrefix(name, "net") && strings.Contains(name, "."):
+		family, version = 4, strings.TrimPrefix(name, "net")
+	case strings.HasPrefix(name, "net"):
+		family, version = 1, strings.Join(strings.Split(strings.TrimPrefix(name, "net"), ""), ".")
+	}
+	if _, err := strconv.Atoi(strings.ReplaceAll(version, ".", "")); err != nil {
+		return 0, ""
+	}
+	rank := family * 2
+	if platform == "" {
+		rank++
+	}
+	return rank, version
+}
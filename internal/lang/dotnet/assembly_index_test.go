@@ -0,0 +1,266 @@
+package dotnet
+
+import (
+	"bytes"
+	"context"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+const (
+	testSectionRVA    = 0x2000
+	testSectionOffset = 0x200
+	testCLIHeaderSize = 72
+)
+
+type testTypeDef struct {
+	flags     uint32
+	namespace string
+	name      string
+}
+
+func publicType(namespace, name string) testTypeDef {
+	return testTypeDef{flags: typeDefVisibilityPublic, namespace: namespace, name: name}
+}
+
+// buildTestAssembly writes a minimal PE32 image whose metadata holds a Module
+// row and the given TypeDef rows.
+func buildTestAssembly(t *testing.T, types []testTypeDef) []byte {
+	t.Helper()
+	stringsHeap := []byte{0}
+	intern := func(value string) uint16 {
+		if value == "" {
+			return 0
+		}
+		index := len(stringsHeap)
+		stringsHeap = append(stringsHeap, value...)
+		stringsHeap = append(stringsHeap, 0)
+		return uint16(index)
+	}
+
+	var tables bytes.Buffer
+	write := func(value any) { _ = binary.Write(&tables, binary.LittleEndian, value) }
+	write(uint32(0))
+	write([]byte{2, 0, 0, 1})
+	write(uint64(1<<tableModule | 1<<tableTypeDef))
+	write(uint64(0))
+	write(uint32(1))
+	write(uint32(len(types) + 1))
+	write([]uint16{0, intern("Test.dll"), 0, 0, 0})
+	rows := append([]testTypeDef{{name: "<Module>"}}, types...)
+	for _, row := range rows {
+		write(row.flags)
+		write([]uint16{intern(row.name), intern(row.namespace), 0, 1, 1})
+	}
+	for len(stringsHeap)%4 != 0 {
+		stringsHeap = append(stringsHeap, 0)
+	}
+
+	var metadata bytes.Buffer
+	version := []byte("v4.0.30319\x00\x00")
+	streamStart := uint32(16 + len(version) + 4 + 12 + 20)
+	_ = binary.Write(&metadata, binary.LittleEndian, []uint32{metadataRootSignature, 0x00010001, 0, uint32(len(version))})
+	metadata.Write(version)
+	_ = binary.Write(&metadata, binary.LittleEndian, []uint16{0, 2})
+	_ = binary.Write(&metadata, binary.LittleEndian, []uint32{streamStart, uint32(tables.Len())})
+	metadata.WriteString("#~\x00\x00")
+	_ = binary.Write(&metadata, binary.LittleEndian, []uint32{streamStart + uint32(tables.Len()), uint32(len(stringsHeap))})
+	metadata.WriteString("#Strings\x00\x00\x00\x00")
+	metadata.Write(tables.Bytes())
+	metadata.Write(stringsHeap)
+
+	text := make([]byte, testCLIHeaderSize)
+	binary.LittleEndian.PutUint32(text, testCLIHeaderSize)
+	binary.LittleEndian.PutUint32(text[8:], testSectionRVA+testCLIHeaderSize)
+	binary.LittleEndian.PutUint32(text[12:], uint32(metadata.Len()))
+	text = append(text, metadata.Bytes()...)
+	return buildTestPE(t, text, true)
+}
+
+func buildTestPE(t *testing.T, text []byte, managed bool) []byte {
+	t.Helper()
+	rawSize := (len(text) + 0x1FF) &^ 0x1FF
+	optional := pe.OptionalHeader32{
+		Magic:               0x10b,
+		SectionAlignment:    0x2000,
+		FileAlignment:       0x200,
+		SizeOfImage:         uint32(testSectionRVA + rawSize),
+		SizeOfHeaders:       testSectionOffset,
+		NumberOfRvaAndSizes: 16,
+	}
+	if managed {
+		optional.DataDirectory[clrRuntimeHeaderDirectory] = pe.DataDirectory{VirtualAddress: testSectionRVA, Size: testCLIHeaderSize}
+	}
+	section := pe.SectionHeader32{
+		VirtualSize:      uint32(len(text)),
+		VirtualAddress:   testSectionRVA,
+		SizeOfRawData:    uint32(rawSize),
+		PointerToRawData: testSectionOffset,
+	}
+	copy(section.Name[:], ".text")
+
+	var image bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	image.Write(dos)
+	image.WriteString("PE\x00\x00")
+	_ = binary.Write(&image, binary.LittleEndian, pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_I386,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(optional)),
+		Characteristics:      0x2102,
+	})
+	_ = binary.Write(&image, binary.LittleEndian, optional)
+	_ = binary.Write(&image, binary.LittleEndian, section)
+	if image.Len() > testSectionOffset {
+		t.Fatalf("test PE headers overflow the first section: %d bytes", image.Len())
+	}
+	image.Write(make([]byte, testSectionOffset-image.Len()))
+	image.Write(text)
+	image.Write(make([]byte, rawSize-len(text)))
+	return image.Bytes()
+}
+
+func TestReadAssemblyTypesListsPublicTopLevelTypes(t *testing.T) {
+	content := buildTestAssembly(t, []testTypeDef{
+		publicType("Acme.Json", "JsonWriter"),
+		publicType("Acme.Json", "JsonArray`1"),
+		{flags: 0, namespace: "Acme.Json", name: "InternalHelper"},
+		{flags: 2, namespace: "", name: "NestedPublic"},
+	})
+	types, err := readAssemblyTypes(content)
+	if err != nil {
+		t.Fatalf("read assembly types: %v", err)
+	}
+	want := []assemblyType{{Namespace: "Acme.Json", Name: "JsonWriter"}, {Namespace: "Acme.Json", Name: "JsonArray`1"}}
+	if !slices.Equal(types, want) {
+		t.Fatalf("unexpected types: %#v", types)
+	}
+}
+
+func TestReadAssemblyTypesRejectsUnmanagedAndCorruptImages(t *testing.T) {
+	if _, err := readAssemblyTypes(buildTestPE(t, make([]byte, testCLIHeaderSize), false)); !errors.Is(err, errNotManagedAssembly) {
+		t.Fatalf("expected unmanaged image error, got %v", err)
+	}
+
+	corrupt := buildTestAssembly(t, []testTypeDef{publicType("Acme", "Widget")})
+	binary.LittleEndian.PutUint32(corrupt[testSectionOffset+12:], 1<<20)
+	if _, err := readAssemblyTypes(corrupt); !errors.Is(err, errInvalidMetadata) {
+		t.Fatalf("expected invalid metadata error, got %v", err)
+	}
+
+	if _, err := readAssemblyTypes([]byte("not a PE file")); err == nil {
+		t.Fatalf("expected error for non-PE content")
+	}
+}
+
+func TestPreferredTargetFrameworkAndNewestVersion(t *testing.T) {
+	cases := []struct {
+		frameworks []string
+		want       string
+	}{
+		{frameworks: []string{"net45", "netstandard2.0", "net6.0", "net8.0"}, want: "net8.0"},
+		{frameworks: []string{"net8.0-windows7.0", "net6.0"}, want: "net6.0"},
+		{frameworks: []string{"netstandard1.3", "netstandard2.1", "net472"}, want: "netstandard2.1"},
+		{frameworks: []string{"net40", "net48"}, want: "net48"},
+	}
+	for _, tc := range cases {
+		if got := preferredTargetFramework(tc.frameworks); got != tc.want {
+			t.Fatalf("preferredTargetFramework(%v) = %q, want %q", tc.frameworks, got, tc.want)
+		}
+	}
+	if got := newestNuGetVersion([]string{"9.0.1", "13.0.3", "13.0.4-beta1", "13.0.3-rc.2"}); got != "13.0.4-beta1" {
+		t.Fatalf("unexpected newest version %q", got)
+	}
+	if got := newestNuGetVersion([]string{"2.0.0-preview", "2.0.0"}); got != "2.0.0" {
+		t.Fatalf("expected release to outrank prerelease, got %q", got)
+	}
+	if got := newestNuGetVersion([]string{"3.0.0-rc.10", "3.0.0-rc.2"}); got != "3.0.0-rc.10" {
+		t.Fatalf("expected numeric prerelease identifiers to compare numerically, got %q", got)
+	}
+}
+
+func writeTestNuGetPackage(t *testing.T, root, id, version, framework string, types []testTypeDef) {
+	t.Helper()
+	path := filepath.Join(root, id, version, "lib", framework, id+".dll")
+	testutil.MustWriteFile(t, path, string(buildTestAssembly(t, types)))
+}
+
+func TestAdapterAnalyseAttributesTypesFromNuGetAssemblies(t *testing.T) {
+	packages := t.TempDir()
+	t.Setenv("NUGET_PACKAGES", packages)
+	writeTestNuGetPackage(t, packages, "acme.json", "1.0.0", "net8.0", []testTypeDef{
+		publicType("Acme.Json", "JsonWriter"),
+		publicType("Acme.Json", "JsonReader"),
+		publicType("Acme.Json.Linq", "JToken"),
+		publicType("Acme.Json.Linq", "JArray`1"),
+	})
+	writeTestNuGetPackage(t, packages, "acme.json", "0.9.0", "net8.0", []testTypeDef{publicType("Acme.Json", "Legacy")})
+	writeTestNuGetPackage(t, packages, "acme.unused", "2.0.0", "netstandard2.0", []testTypeDef{publicType("Acme.Unused", "Widget")})
+
+	repo := t.TempDir()
+	writeManifestFixture(t, filepath.Join(repo, "src", "App", appProjectFileName), `
+<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Acme.Json" Version="1.0.0" />
+    <PackageReference Include="Acme.Json.Linq" Version="1.0.0" />
+    <PackageReference Include="Acme.Unused" Version="2.0.0" />
+  </ItemGroup>
+</Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "App", "GlobalUsings.cs"), "global using Acme.Json;\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "App", programSourceFileName), `using Acme.Json.Linq;
+using Acme.Unused;
+
+var writer = new JsonWriter();
+var token = new JArray<int>();
+`)
+
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{dotnetAssemblyIndexPreviewFeature, dotnetProjectUsingsPreviewFeature},
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10, Features: features})
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+
+	byName := dependencyReportsByName(reportData.Dependencies)
+	acmeJSON, ok := byName["acme.json"]
+	if !ok {
+		t.Fatalf("expected acme.json report, got %#v", reportData.Dependencies)
+	}
+	if acmeJSON.TotalExportsCount != 4 || acmeJSON.UsedExportsCount != 2 {
+		t.Fatalf("expected 2 of 4 public types used, got %d of %d", acmeJSON.UsedExportsCount, acmeJSON.TotalExportsCount)
+	}
+	if len(acmeJSON.UsedImports) != 2 || acmeJSON.UsedImports[0].Module != "Acme.Json" || acmeJSON.UsedImports[1].Module != "Acme.Json.Linq" {
+		t.Fatalf("expected global and local usings attributed to acme.json, got %#v", acmeJSON.UsedImports)
+	}
+	if linq := byName["acme.json.linq"]; len(linq.UsedImports) != 0 {
+		t.Fatalf("expected no name-similarity attribution to acme.json.linq, got %#v", linq.UsedImports)
+	}
+	unused := byName["acme.unused"]
+	if unused.UsedExportsCount != 0 || len(unused.UnusedImports) != 1 || unused.UnusedImports[0].Module != "Acme.Unused" {
+		t.Fatalf("expected unused using for acme.unused, got %#v", unused)
+	}
+}
+
+func dependencyReportsByName(dependencies []report.DependencyReport) map[string]report.DependencyReport {
+	byName := make(map[string]report.DependencyReport, len(dependencies))
+	for _, dep := range dependencies {
+		byName[dep.Name] = dep
+	}
+	return byName
+}
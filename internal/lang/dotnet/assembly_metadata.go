@@ -0,0 +1,245 @@
+package dotnet
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	clrRuntimeHeaderDirectory = 14
+	metadataRootSignature     = 0x424A5342
+	typeDefVisibilityMask     = 0x7
+	typeDefVisibilityPublic   = 0x1
+
+	tableModule              = 0x00
+	tableTypeRef             = 0x01
+	tableTypeDef             = 0x02
+	tableField               = 0x04
+	tableMethodDef           = 0x06
+	tableModuleRef           = 0x1A
+	tableTypeSpec            = 0x1B
+	tableAssemblyRef         = 0x23
+	heapSizeLargeStrings     = 0x01
+	heapSizeLargeGUIDs       = 0x02
+	heapSizeExtraData        = 0x40
+	metadataTableCount       = 64
+	metadataTablesHeaderSize = 24
+)
+
+var (
+	errNotManagedAssembly = errors.New("not a managed assembly")
+	errInvalidMetadata    = errors.New("invalid assembly metadata")
+)
+
+// assemblyType is a public top-level type defined in an assembly.
+type assemblyType struct {
+	Namespace string
+	Name      string
+}
+
+// readAssemblyTypes lists the public top-level types an assembly defines by
+// reading the TypeDef table of its ECMA-335 metadata. Nested and non-public
+// types are skipped because source files cannot name them through a using.
+func readAssemblyTypes(content []byte) ([]assemblyType, error) {
+	file, err := pe.NewFile(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	cliHeader, err := readPEDirectory(file, clrRuntimeHeaderDirectory)
+	if err != nil {
+		return nil, err
+	}
+	if len(cliHeader) < 16 {
+		return nil, errInvalidMetadata
+	}
+	metadata, err := readPERange(file, binary.LittleEndian.Uint32(cliHeader[8:]), binary.LittleEndian.Uint32(cliHeader[12:]))
+	if err != nil {
+		return nil, err
+	}
+	tables, stringsHeap, err := metadataStreams(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return readTypeDefs(tables, stringsHeap)
+}
+
+func readPEDirectory(file *pe.File, index int) ([]byte, error) {
+	var directories []pe.DataDirectory
+	switch header := file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		directories = header.DataDirectory[:min(int(header.NumberOfRvaAndSizes), len(header.DataDirectory))]
+	case *pe.OptionalHeader64:
+		directories = header.DataDirectory[:min(int(header.NumberOfRvaAndSizes), len(header.DataDirectory))]
+	}
+	if index >= len(directories) || directories[index].VirtualAddress == 0 {
+		return nil, errNotManagedAssembly
+	}
+	return readPERange(file, directories[index].VirtualAddress, directories[index].Size)
+}
+
+func readPERange(file *pe.File, rva, size uint32) ([]byte, error) {
+	for _, section := range file.Sections {
+		extent := max(section.VirtualSize, section.Size)
+		if rva < section.VirtualAddress || rva >= section.VirtualAddress+extent {
+			continue
+		}
+		data, err := section.Data()
+		if err != nil {
+			return nil, err
+		}
+		start := uint64(rva - section.VirtualAddress)
+		end := start + uint64(size)
+		if end > uint64(len(data)) {
+			return nil, errInvalidMetadata
+		}
+		return data[start:end], nil
+	}
+	return nil, errInvalidMetadata
+}
+
+// metadataStreams returns the table stream ("#~", or "#-" for unoptimised
+// metadata) and the #Strings heap from a metadata root.
+func metadataStreams(metadata []byte) ([]byte, []byte, error) {
+	if len(metadata) < 16 || binary.LittleEndian.Uint32(metadata) != metadataRootSignature {
+		return nil, nil, errInvalidMetadata
+	}
+	offset := 16 + int(binary.LittleEndian.Uint32(metadata[12:]))
+	if offset+4 > len(metadata) || offset < 16 {
+		return nil, nil, errInvalidMetadata
+	}
+	streamCount := int(binary.LittleEndian.Uint16(metadata[offset+2:]))
+	offset += 4
+
+	var tables, stringsHeap []byte
+	for i := 0; i < streamCount; i++ {
+		if offset+8 > len(metadata) {
+			return nil, nil, errInvalidMetadata
+		}
+		streamOffset := uint64(binary.LittleEndian.Uint32(metadata[offset:]))
+		streamSize := uint64(binary.LittleEndian.Uint32(metadata[offset+4:]))
+		nameEnd := bytes.IndexByte(metadata[offset+8:], 0)
+		if nameEnd < 0 {
+			return nil, nil, errInvalidMetadata
+		}
+		name := string(metadata[offset+8 : offset+8+nameEnd])
+		offset += 8 + (nameEnd+4)&^3
+		if streamOffset+streamSize > uint64(len(metadata)) {
+			return nil, nil, errInvalidMetadata
+		}
+		stream := metadata[streamOffset : streamOffset+streamSize]
+		switch name {
+		case "#~", "#-":
+			tables = stream
+		case "#Strings":
+			stringsHeap = stream
+		}
+	}
+	if tables == nil || stringsHeap == nil {
+		return nil, nil, errInvalidMetadata
+	}
+	return tables, stringsHeap, nil
+}
+
+// metadataTables holds the row counts and index widths needed to locate rows
+// in the table stream.
+type metadataTables struct {
+	rows        [metadataTableCount]uint32
+	stringIndex int
+	guidIndex   int
+}
+
+func (t metadataTables) tableIndex(table int) int {
+	if t.rows[table] < 1<<16 {
+		return 2
+	}
+	return 4
+}
+
+func (t metadataTables) codedIndex(tagBits uint, tables ...int) int {
+	for _, table := range tables {
+		if t.rows[table] >= 1<<(16-tagBits) {
+			return 4
+		}
+	}
+	return 2
+}
+
+func readTypeDefs(stream, stringsHeap []byte) ([]assemblyType, error) {
+	if len(stream) < metadataTablesHeaderSize {
+		return nil, errInvalidMetadata
+	}
+	heapSizes := stream[6]
+	valid := binary.LittleEndian.Uint64(stream[8:])
+	tables := metadataTables{stringIndex: 2, guidIndex: 2}
+	if heapSizes&heapSizeLargeStrings != 0 {
+		tables.stringIndex = 4
+	}
+	if heapSizes&heapSizeLargeGUIDs != 0 {
+		tables.guidIndex = 4
+	}
+	offset := metadataTablesHeaderSize
+	for table := 0; table < metadataTableCount; table++ {
+		if valid&(1<<uint(table)) == 0 {
+			continue
+		}
+		if offset+4 > len(stream) {
+			return nil, errInvalidMetadata
+		}
+		tables.rows[table] = binary.LittleEndian.Uint32(stream[offset:])
+		offset += 4
+	}
+	if heapSizes&heapSizeExtraData != 0 {
+		offset += 4
+	}
+
+	moduleRow := 2 + tables.stringIndex + 3*tables.guidIndex
+	typeRefRow := tables.codedIndex(2, tableModule, tableModuleRef, tableAssemblyRef, tableTypeRef) + 2*tables.stringIndex
+	typeDefRow := 4 + 2*tables.stringIndex + tables.codedIndex(2, tableTypeDef, tableTypeRef, tableTypeSpec) + tables.tableIndex(tableField) + tables.tableIndex(tableMethodDef)
+
+	start := uint64(offset) + uint64(tables.rows[tableModule])*uint64(moduleRow) + uint64(tables.rows[tableTypeRef])*uint64(typeRefRow)
+	if start+uint64(tables.rows[tableTypeDef])*uint64(typeDefRow) > uint64(len(stream)) {
+		return nil, errInvalidMetadata
+	}
+	types := make([]assemblyType, 0, tables.rows[tableTypeDef])
+	for i := uint64(0); i < uint64(tables.rows[tableTypeDef]); i++ {
+		row := stream[start+i*uint64(typeDefRow):]
+		if binary.LittleEndian.Uint32(row)&typeDefVisibilityMask != typeDefVisibilityPublic {
+			continue
+		}
+		name, err := metadataString(stringsHeap, heapIndex(row[4:], tables.stringIndex))
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := metadataString(stringsHeap, heapIndex(row[4+tables.stringIndex:], tables.stringIndex))
+		if err != nil {
+			return nil, err
+		}
+		if name == "" || name == "<Module>" {
+			continue
+		}
+		types = append(types, assemblyType{Namespace: namespace, Name: name})
+	}
+	return types, nil
+}
+
+func heapIndex(content []byte, size int) uint32 {
+	if size == 4 {
+		return binary.LittleEndian.Uint32(content)
+	}
+	return uint32(binary.LittleEndian.Uint16(content))
+}
+
+func metadataString(heap []byte, index uint32) (string, error) {
+	if uint64(index) >= uint64(len(heap)) {
+		return "", errInvalidMetadata
+	}
+	end := bytes.IndexByte(heap[index:], 0)
+	if end < 0 {
+		return "", errInvalidMetadata
+	}
+	return string(heap[index : int(index)+end]), nil
+}
@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
@@ -24,16 +25,34 @@ type sourceDocument struct {
 
 type scanInputs struct {
 	DeclaredDependencies []string
+	Projects             []projectScope
 	SourceFiles          []sourceDocument
 	Warnings             []string
 	SkippedGenerated     int
 	SkippedFileLimit     bool
 }
 
+// scanOptions enables optional inputs to a scan. An empty nugetPackagesRoot
+// leaves namespace mapping to name similarity. projectGraph adds
+// packages.config, Directory.Build.props/.targets inheritance, target
+// framework conditions, and per-project declared packages. projectUsings
+// applies global usings, <Using> items, and SDK implicit usings to every
+// source file of their project. fileCache reuses parsed source files.
+type scanOptions struct {
+	nugetPackagesRoot string
+	projectGraph      bool
+	projectUsings     bool
+	fileCache         language.FileCache
+}
+
 func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
+	return scanRepoWithOptions(ctx, repoPath, scanOptions{})
+}
+
+func scanRepoWithOptions(ctx context.Context, repoPath string, options scanOptions) (scanResult, error) {
 	result := newScanResult()
 
-	inputs, err := discoverScanInputs(ctx, repoPath)
+	inputs, err := discoverScanInputs(ctx, repoPath, options)
 	if err != nil {
 		return result, err
 	}
@@ -44,15 +63,31 @@ func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
 	result.SkippedFileLimit = inputs.SkippedFileLimit
 
 	mapper := newDependencyMapper(inputs.DeclaredDependencies)
+	if options.nugetPackagesRoot != "" {
+		var indexWarnings []string
+		mapper.assemblies, indexWarnings = indexNuGetAssemblies(inputs.DeclaredDependencies, options.nugetPackagesRoot)
+		result.Assemblies = mapper.assemblies
+		result.Warnings = append(result.Warnings, indexWarnings...)
+	}
+	var globalSources []sourceDocument
+	if options.projectUsings {
+		globalSources = inputs.SourceFiles
+	}
+	resolver := newProjectUsingResolver(mapper, newProjectScopes(inputs.Projects, globalSources), options.projectGraph)
+	sourceCache := newSourceFileCache(options.fileCache, mapper.assemblies)
 	for _, source := range inputs.SourceFiles {
-		parsed := parseSourceDocument(source, mapper)
+		parsed := sourceCache.parse(source, resolver.mapperFor(source.RelativePath))
+		resolver.applyToFile(&parsed.File, source.Content)
 		result.Files = append(result.Files, parsed.File)
 		addMappingMeta(&result, parsed.Mapping)
 	}
+	projectMeta := newMappingMetadata()
+	result.Files = resolver.finish(result.Files, &projectMeta)
+	addMappingMeta(&result, projectMeta)
 	return result, nil
 }
 
-func discoverScanInputs(ctx context.Context, repoPath string) (scanInputs, error) {
+func discoverScanInputs(ctx context.Context, repoPath string, options scanOptions) (scanInputs, error) {
 	inputs := scanInputs{}
 	if repoPath == "" {
 		return inputs, fs.ErrInvalid
@@ -60,6 +95,8 @@ func discoverScanInputs(ctx context.Context, repoPath string) (scanInputs, error
 
 	sourceScan := sourceDiscovery{}
 	scanner := newScanInputDiscoverer(repoPath, &sourceScan)
+	scanner.projectGraph = options.projectGraph
+	scanner.projectUsings = options.projectUsings
 
 	err := filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, walkErr error) error {
 		if ctx != nil && ctx.Err() != nil {
@@ -73,7 +110,14 @@ func discoverScanInputs(ctx context.Context, repoPath string) (scanInputs, error
 
 	appendSourceDiscoveryWarnings(&sourceScan)
 
+	if scanner.projectGraph {
+		resolved := scanner.graph.resolve()
+		for i := range scanner.projects {
+			scanner.projects[i].Declared = resolved[scanner.projects[i].Manifest]
+		}
+	}
 	inputs.DeclaredDependencies = sortedDependencies(scanner.dependencySet)
+	inputs.Projects = scanner.projects
 	inputs.SourceFiles = sourceScan.Files
 	inputs.SkippedGenerated = sourceScan.SkippedGeneratedFiles
 	inputs.SkippedFileLimit = sourceScan.SkippedFileLimit
@@ -83,8 +127,9 @@ func discoverScanInputs(ctx context.Context, repoPath string) (scanInputs, error
 
 func newScanResult() scanResult {
 	return scanResult{
-		AmbiguousByDependency:  make(map[string]int),
-		UndeclaredByDependency: make(map[string]int),
+		AmbiguousByDependency:         make(map[string]int),
+		UndeclaredByDependency:        make(map[string]int),
+		ProjectUndeclaredByDependency: make(map[string]int),
 	}
 }
 
@@ -95,6 +140,9 @@ func addMappingMeta(result *scanResult, meta mappingMetadata) {
 	for dep, count := range meta.undeclaredByDependency {
 		result.UndeclaredByDependency[dep] += count
 	}
+	for dep, count := range meta.projectUndeclaredByDependency {
+		result.ProjectUndeclaredByDependency[dep] += count
+	}
 }
 
 type sourceDiscovery struct {
@@ -112,6 +160,10 @@ type sourceDiscoverer struct {
 
 type scanInputDiscoverer struct {
 	dependencySet     map[string]struct{}
+	graph             *projectGraph
+	projects          []projectScope
+	projectGraph      bool
+	projectUsings     bool
 	sourceDiscoverer  sourceDiscoverer
 	sourceScanLimited bool
 }
@@ -126,6 +178,7 @@ func newSourceDiscoverer(repoPath string, discovery *sourceDiscovery) sourceDisc
 func newScanInputDiscoverer(repoPath string, source *sourceDiscovery) scanInputDiscoverer {
 	return scanInputDiscoverer{
 		dependencySet:    make(map[string]struct{}),
+		graph:            newProjectGraph(),
 		sourceDiscoverer: newSourceDiscoverer(repoPath, source),
 	}
 }
@@ -141,16 +194,14 @@ func (d *scanInputDiscoverer) walk(path string, entry fs.DirEntry, walkErr error
 		return nil
 	}
 
-	dependencies, err := parseManifestDependenciesForEntry(d.sourceDiscoverer.repoPath, path, entry.Name())
-	if err != nil {
+	if err := d.discoverManifest(path, entry.Name()); err != nil {
 		return err
 	}
-	addDependencies(d.dependencySet, dependencies)
 
 	if d.sourceScanLimited {
 		return nil
 	}
-	err = d.sourceDiscoverer.discoverFile(path)
+	err := d.sourceDiscoverer.discoverFile(path)
 	if errors.Is(err, fs.SkipAll) {
 		d.sourceScanLimited = true
 		return nil
@@ -158,6 +209,73 @@ func (d *scanInputDiscoverer) walk(path string, entry fs.DirEntry, walkErr error
 	return err
 }
 
+// discoverManifest records project files and Directory.Packages.props. With
+// the project graph enabled it also records packages.config and the
+// Directory.Build.props/.targets files whose references projects inherit.
+func (d *scanInputDiscoverer) discoverManifest(path, name string) error {
+	lower := strings.ToLower(name)
+	isProject := isProjectManifestName(lower)
+	isCentral := strings.EqualFold(name, centralPackagesFile)
+	isGraphInput := strings.EqualFold(name, directoryBuildPropsFile) || strings.EqualFold(name, directoryBuildTargetsFile) || lower == packagesConfigFile
+	if !isProject && !isCentral && (!isGraphInput || !d.projectGraph) {
+		return nil
+	}
+	repoPath := d.sourceDiscoverer.repoPath
+	content, err := safeio.ReadFileUnder(repoPath, path)
+	if err != nil {
+		return err
+	}
+	relativePath, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		relativePath = path
+	}
+	dir := filepath.Dir(relativePath)
+
+	if lower == packagesConfigFile {
+		references, err := parsePackagesConfig(content)
+		if err != nil {
+			return err
+		}
+		d.graph.addPackagesConfig(dir, references)
+		addPackageReferences(d.dependencySet, references)
+		return nil
+	}
+	manifest, err := parseProjectManifest(content, relativePath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case isProject:
+		var usings []projectUsing
+		if d.projectUsings {
+			if usings, err = parseProjectUsings(content, relativePath); err != nil {
+				return err
+			}
+		}
+		packages := manifest.Packages
+		if d.projectGraph {
+			d.graph.addProject(manifest)
+			packages = manifest.applicablePackages()
+		}
+		addPackageReferences(d.dependencySet, packages)
+		d.projects = append(d.projects, projectScope{Dir: dir, Manifest: relativePath, Usings: usings})
+	case isCentral:
+		versions, err := parseXMLManifestIncludes(content, "PackageVersion")
+		if err != nil {
+			return err
+		}
+		addDependencies(d.dependencySet, versions)
+		if !d.projectGraph {
+			return nil
+		}
+		fallthrough
+	default:
+		d.graph.addInherited(name, dir, manifest.Packages)
+		addPackageReferences(d.dependencySet, manifest.Packages)
+	}
+	return nil
+}
+
 func (d *sourceDiscoverer) walk(path string, entry fs.DirEntry, walkErr error) error {
 	if walkErr != nil {
 		return walkErr
@@ -253,6 +371,10 @@ func parseManifestDependenciesForEntry(repoPath, path, name string) ([]string, e
 		return parsePackageReferences(repoPath, path)
 	case strings.EqualFold(name, centralPackagesFile):
 		return parsePackageVersions(repoPath, path)
+	case strings.EqualFold(name, directoryBuildPropsFile), strings.EqualFold(name, directoryBuildTargetsFile):
+		return parsePackageReferences(repoPath, path)
+	case lower == packagesConfigFile:
+		return parsePackagesConfigDependencies(repoPath, path)
 	default:
 		return nil, nil
 	}
@@ -266,6 +388,20 @@ func parsePackageVersions(repoPath, manifestPath string) ([]string, error) {
 	return parseManifestDependencies(repoPath, manifestPath, "PackageVersion")
 }
 
+func parsePackagesConfigDependencies(repoPath, manifestPath string) ([]string, error) {
+	content, err := safeio.ReadFileUnder(repoPath, manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	references, err := parsePackagesConfig(content)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(references))
+	addPackageReferences(set, references)
+	return sortedDependencies(set), nil
+}
+
 func parseManifestDependencies(repoPath, manifestPath string, elementName string) ([]string, error) {
 	content, err := safeio.ReadFileUnder(repoPath, manifestPath)
 	if err != nil {
@@ -388,7 +524,7 @@ func isProjectManifestName(lowerName string) bool {
 }
 
 func isSolutionFileName(lowerName string) bool {
-	return strings.HasSuffix(lowerName, solutionFileExt)
+	return strings.HasSuffix(lowerName, solutionFileExt) || strings.HasSuffix(lowerName, xmlSolutionFileExt)
 }
 
 func isSourceFileName(lowerName string) bool {
@@ -400,12 +536,14 @@ func addSolutionRoots(repoPath string, solutionPath string, roots map[string]str
 	if err != nil {
 		return err
 	}
-	matches := solutionProjectPattern.FindAllSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
+	projectPaths := solutionProjectPaths(content)
+	if strings.EqualFold(filepath.Ext(solutionPath), xmlSolutionFileExt) {
+		if projectPaths, err = xmlSolutionProjectPaths(content); err != nil {
+			return err
 		}
-		relPath := strings.TrimSpace(string(match[1]))
+	}
+	for _, entry := range projectPaths {
+		relPath := strings.TrimSpace(entry)
 		if relPath == "" {
 			continue
 		}
@@ -419,6 +557,40 @@ func addSolutionRoots(repoPath string, solutionPath string, roots map[string]str
 	return nil
 }
 
+func solutionProjectPaths(content []byte) []string {
+	matches := solutionProjectPattern.FindAllSubmatch(content, -1)
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if len(match) >= 2 {
+			paths = append(paths, string(match[1]))
+		}
+	}
+	return paths
+}
+
+// xmlSolutionProjectPaths reads <Project Path="..."> entries from an .slnx
+// solution, including projects nested in solution folders.
+func xmlSolutionProjectPaths(content []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	paths := make([]string, 0)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return paths, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || !strings.EqualFold(start.Name.Local, "Project") {
+			continue
+		}
+		if path := xmlAttribute(start, "Path"); isProjectManifestName(strings.ToLower(path)) {
+			paths = append(paths, path)
+		}
+	}
+}
+
 func isRepoBoundedPath(repoPath, candidatePath string) bool {
 	repoAbs, err := filepath.Abs(repoPath)
 	if err != nil {
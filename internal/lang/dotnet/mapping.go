@@ -13,6 +13,15 @@ func resolveImportDependency(module string, mapper dependencyMapper, meta *mappi
 	if dependency == "" {
 		return "", false
 	}
+	if undeclared && mapper.repository != nil {
+		if repoDependency, repoAmbiguous, repoUndeclared := mapper.repository.resolve(module); repoDependency != "" && !repoUndeclared {
+			if meta.projectUndeclaredByDependency == nil {
+				meta.projectUndeclaredByDependency = make(map[string]int)
+			}
+			meta.projectUndeclaredByDependency[repoDependency]++
+			dependency, ambiguous, undeclared = repoDependency, repoAmbiguous, false
+		}
+	}
 	if ambiguous {
 		meta.ambiguousByDependency[dependency]++
 	}
@@ -36,8 +45,13 @@ func normalizeDependencyID(value string) string {
 	return strings.ToLower(strings.TrimSpace(value))
 }
 
+// dependencyMapper resolves namespaces against a declared package set. A
+// project-scoped mapper keeps the repository-wide mapper so usage of packages
+// declared only by other projects can be told apart from undeclared usage.
 type dependencyMapper struct {
-	declared []declaredDependency
+	declared   []declaredDependency
+	assemblies assemblyIndex
+	repository *dependencyMapper
 }
 
 func newDependencyMapper(declared []string) dependencyMapper {
@@ -70,6 +84,9 @@ func (m *dependencyMapper) resolve(module string) (dependency string, ambiguous
 	if module == "" {
 		return "", false, false
 	}
+	if owner := m.assemblies.namespaceOwner(module); owner != "" {
+		return owner, false, m.repository != nil && !m.declaresPackage(owner)
+	}
 	moduleID := normalizeDependencyID(module)
 	if moduleID == "system" || strings.HasPrefix(moduleID, "system.") {
 		return "", false, false
@@ -101,6 +118,39 @@ func (m *dependencyMapper) resolve(module string) (dependency string, ambiguous
 	return bestID, bestMatches > 1, false
 }
 
+// declaresPackage reports whether a package is declared under exactly the
+// namespace's name.
+func (m *dependencyMapper) declaresPackage(module string) bool {
+	moduleID := normalizeDependencyID(normalizeNamespace(module))
+	for _, dep := range m.declared {
+		if dep.id == moduleID {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredIDs lists the packages the mapper resolves against, followed by the
+// repository-wide set a project mapper falls back to.
+func (m dependencyMapper) declaredIDs() [][]string {
+	ids := make([]string, 0, len(m.declared))
+	for _, dep := range m.declared {
+		ids = append(ids, dep.id)
+	}
+	if m.repository == nil {
+		return [][]string{ids}
+	}
+	return append([][]string{ids}, m.repository.declaredIDs()...)
+}
+
+// projectMapper scopes resolution to one project's declared packages.
+func (m dependencyMapper) projectMapper(declared []string) dependencyMapper {
+	project := newDependencyMapper(declared)
+	project.assemblies = m.assemblies
+	project.repository = &m
+	return project
+}
+
 func matchScore(module, dependency string) int {
 	return matchScoreWithSegments(module, dependency, splitNamespace(module), splitNamespace(dependency))
 }
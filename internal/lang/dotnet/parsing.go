@@ -2,15 +2,26 @@ package dotnet
 
 import (
 	"bytes"
+	"path/filepath"
 	"strings"
 
 	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/report"
 )
 
 type mappingMetadata struct {
-	ambiguousByDependency  map[string]int
-	undeclaredByDependency map[string]int
+	ambiguousByDependency         map[string]int
+	undeclaredByDependency        map[string]int
+	projectUndeclaredByDependency map[string]int
+}
+
+func newMappingMetadata() mappingMetadata {
+	return mappingMetadata{
+		ambiguousByDependency:         make(map[string]int),
+		undeclaredByDependency:        make(map[string]int),
+		projectUndeclaredByDependency: make(map[string]int),
+	}
 }
 
 type parsedSourceFile struct {
@@ -30,11 +41,66 @@ func parseSourceDocument(source sourceDocument, mapper dependencyMapper) parsedS
 	}
 }
 
-func parseImports(content []byte, relativePath string, mapper dependencyMapper) ([]importBinding, mappingMetadata) {
-	meta := mappingMetadata{
-		ambiguousByDependency:  make(map[string]int),
-		undeclaredByDependency: make(map[string]int),
+// sourceScanCacheSchema versions the cachedSourceFile layout and the using
+// parsing that produces it; bump it whenever either changes.
+const sourceScanCacheSchema = "dotnet-sourcescan-v1"
+
+type cachedSourceFile struct {
+	File              fileScan
+	Ambiguous         map[string]int
+	Undeclared        map[string]int
+	ProjectUndeclared map[string]int
+}
+
+// sourceFileCache reuses parsed source files through the analysis file cache.
+// Entries are keyed by the packages and assembly index each file's mapper
+// resolves against, so manifest or restored package changes miss. Project
+// usings are applied after loading, as they depend on other files.
+type sourceFileCache struct {
+	cache      language.FileCache
+	assemblies string
+}
+
+func newSourceFileCache(cache language.FileCache, assemblies assemblyIndex) sourceFileCache {
+	if cache == nil {
+		return sourceFileCache{}
 	}
+	digest, ok := language.FileCacheInputs([]any{assemblies.namespaces, assemblies.types})
+	if !ok {
+		return sourceFileCache{}
+	}
+	return sourceFileCache{cache: cache, assemblies: digest}
+}
+
+func (c sourceFileCache) parse(source sourceDocument, mapper dependencyMapper) parsedSourceFile {
+	if c.cache == nil {
+		return parseSourceDocument(source, mapper)
+	}
+	inputs, ok := language.FileCacheInputs([]any{c.assemblies, mapper.declaredIDs()})
+	if !ok {
+		return parseSourceDocument(source, mapper)
+	}
+	key := language.FileCacheKey{Adapter: "dotnet", Schema: sourceScanCacheSchema, Path: filepath.ToSlash(source.RelativePath), Content: source.Content, Inputs: inputs}
+	var cached cachedSourceFile
+	if c.cache.Load(key, &cached) {
+		return parsedSourceFile{File: cached.File, Mapping: mappingMetadata{
+			ambiguousByDependency:         cached.Ambiguous,
+			undeclaredByDependency:        cached.Undeclared,
+			projectUndeclaredByDependency: cached.ProjectUndeclared,
+		}}
+	}
+	parsed := parseSourceDocument(source, mapper)
+	c.cache.Store(key, cachedSourceFile{
+		File:              parsed.File,
+		Ambiguous:         parsed.Mapping.ambiguousByDependency,
+		Undeclared:        parsed.Mapping.undeclaredByDependency,
+		ProjectUndeclared: parsed.Mapping.projectUndeclaredByDependency,
+	})
+	return parsed
+}
+
+func parseImports(content []byte, relativePath string, mapper dependencyMapper) ([]importBinding, mappingMetadata) {
+	meta := newMappingMetadata()
 	imports := make([]importBinding, 0)
 	inBlockComment := false
 	forEachSourceLine(content, func(lineNo int, raw, _ []byte) {
@@ -0,0 +1,273 @@
+package dotnet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	dotnetProjectGraphPreviewFeature = "dotnet-project-graph-preview"
+	packagesConfigFile               = "packages.config"
+	directoryBuildPropsFile          = "Directory.Build.props"
+	directoryBuildTargetsFile        = "Directory.Build.targets"
+)
+
+var targetFrameworkConditionPattern = regexp.MustCompile(`'\$\(TargetFramework\)'\s*==\s*'([^']*)'`)
+
+// packageReference is a package a project file declares. Frameworks lists the
+// TargetFramework values its Condition limits it to; empty applies to all.
+type packageReference struct {
+	ID         string
+	Frameworks []string
+}
+
+// projectManifest is the package inputs of one MSBuild file: its target
+// frameworks, package references, and repo-relative project references.
+type projectManifest struct {
+	Path              string
+	Frameworks        []string
+	Packages          []packageReference
+	ProjectReferences []string
+}
+
+// parseProjectManifest reads PackageReference, GlobalPackageReference, and
+// ProjectReference items, honouring TargetFramework conditions on items and
+// their ItemGroups.
+func parseProjectManifest(content []byte, relativePath string) (projectManifest, error) {
+	manifest := projectManifest{Path: relativePath}
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	conditions := make([][]string, 0)
+	current := ""
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return projectManifest{}, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			frameworks := conditionFrameworks(xmlAttribute(element, "Condition"))
+			if len(frameworks) == 0 && len(conditions) > 0 {
+				frameworks = conditions[len(conditions)-1]
+			}
+			conditions = append(conditions, frameworks)
+			current = strings.ToLower(element.Name.Local)
+			manifest.addItem(current, xmlAttribute(element, "Include"), frameworks)
+		case xml.CharData:
+			if current == "targetframework" || current == "targetframeworks" {
+				manifest.Frameworks = appendFrameworks(manifest.Frameworks, string(element))
+			}
+		case xml.EndElement:
+			if len(conditions) > 0 {
+				conditions = conditions[:len(conditions)-1]
+			}
+			current = ""
+		}
+	}
+	return manifest, nil
+}
+
+func (m *projectManifest) addItem(element, include string, frameworks []string) {
+	if include == "" {
+		return
+	}
+	switch element {
+	case "packagereference", "globalpackagereference":
+		if id := normalizeDependencyID(include); id != "" {
+			m.Packages = append(m.Packages, packageReference{ID: id, Frameworks: frameworks})
+		}
+	case "projectreference":
+		target := filepath.Clean(filepath.Join(filepath.Dir(m.Path), filepath.FromSlash(strings.ReplaceAll(include, "\\", "/"))))
+		if target != ".." && !strings.HasPrefix(target, ".."+string(filepath.Separator)) {
+			m.ProjectReferences = append(m.ProjectReferences, target)
+		}
+	}
+}
+
+// applicablePackages drops references whose TargetFramework condition
+// matches none of the frameworks the project targets.
+func (m projectManifest) applicablePackages() []packageReference {
+	applicable := make([]packageReference, 0, len(m.Packages))
+	for _, reference := range m.Packages {
+		if frameworksOverlap(reference.Frameworks, m.Frameworks) {
+			applicable = append(applicable, reference)
+		}
+	}
+	return applicable
+}
+
+func conditionFrameworks(condition string) []string {
+	matches := targetFrameworkConditionPattern.FindAllStringSubmatch(condition, -1)
+	frameworks := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if framework := strings.ToLower(strings.TrimSpace(match[1])); framework != "" {
+			frameworks = append(frameworks, framework)
+		}
+	}
+	return frameworks
+}
+
+func appendFrameworks(frameworks []string, value string) []string {
+	for _, framework := range strings.Split(value, ";") {
+		if framework = strings.ToLower(strings.TrimSpace(framework)); framework != "" && !strings.Contains(framework, "$(") {
+			frameworks = append(frameworks, framework)
+		}
+	}
+	return frameworks
+}
+
+// parsePackagesConfig reads the legacy NuGet packages.config format.
+func parsePackagesConfig(content []byte) ([]packageReference, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	references := make([]packageReference, 0)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || !strings.EqualFold(start.Name.Local, "package") {
+			continue
+		}
+		if id := normalizeDependencyID(xmlAttribute(start, "id")); id != "" {
+			references = append(references, packageReference{ID: id})
+		}
+	}
+	return references, nil
+}
+
+// projectGraph collects the package inputs of a repository so each project's
+// declared set can include what it inherits from Directory.Build.props/.targets
+// and Directory.Packages.props global references, its packages.config, and
+// the projects it references.
+type projectGraph struct {
+	projects       map[string]projectManifest
+	packagesConfig map[string][]packageReference
+	inherited      map[string]map[string][]packageReference
+}
+
+func newProjectGraph() *projectGraph {
+	return &projectGraph{
+		projects:       make(map[string]projectManifest),
+		packagesConfig: make(map[string][]packageReference),
+		inherited:      make(map[string]map[string][]packageReference),
+	}
+}
+
+func (g *projectGraph) addProject(manifest projectManifest) {
+	g.projects[manifest.Path] = manifest
+}
+
+func (g *projectGraph) addPackagesConfig(dir string, references []packageReference) {
+	g.packagesConfig[dir] = append(g.packagesConfig[dir], references...)
+}
+
+// addInherited registers references that MSBuild imports into every project
+// below dir. Only the nearest file of each name applies, as MSBuild stops at
+// the first Directory.Build.* it finds.
+func (g *projectGraph) addInherited(fileName, dir string, references []packageReference) {
+	kind := strings.ToLower(fileName)
+	if g.inherited[kind] == nil {
+		g.inherited[kind] = make(map[string][]packageReference)
+	}
+	g.inherited[kind][dir] = append(g.inherited[kind][dir], references...)
+	if g.inherited[kind][dir] == nil {
+		g.inherited[kind][dir] = []packageReference{}
+	}
+}
+
+// resolve returns each project's declared packages, including those of the
+// projects it references transitively.
+func (g *projectGraph) resolve() map[string][]string {
+	direct := make(map[string]map[string]struct{}, len(g.projects))
+	for path, manifest := range g.projects {
+		direct[path] = g.directPackages(manifest)
+	}
+	resolved := make(map[string][]string, len(g.projects))
+	for path := range g.projects {
+		set := make(map[string]struct{})
+		g.collectTransitive(path, direct, set, make(map[string]struct{}))
+		resolved[path] = sortedDependencies(set)
+	}
+	return resolved
+}
+
+func (g *projectGraph) directPackages(manifest projectManifest) map[string]struct{} {
+	set := make(map[string]struct{})
+	dir := filepath.Dir(manifest.Path)
+	references := append([]packageReference(nil), manifest.Packages...)
+	references = append(references, g.packagesConfig[dir]...)
+	for _, kind := range sortedInheritedKinds(g.inherited) {
+		references = append(references, nearestInherited(g.inherited[kind], dir)...)
+	}
+	manifest.Packages = references
+	addPackageReferences(set, manifest.applicablePackages())
+	return set
+}
+
+func (g *projectGraph) collectTransitive(path string, direct map[string]map[string]struct{}, set, visiting map[string]struct{}) {
+	if _, ok := visiting[path]; ok {
+		return
+	}
+	visiting[path] = struct{}{}
+	for dependency := range direct[path] {
+		set[dependency] = struct{}{}
+	}
+	for _, reference := range g.projects[path].ProjectReferences {
+		if _, ok := g.projects[reference]; ok {
+			g.collectTransitive(reference, direct, set, visiting)
+		}
+	}
+}
+
+func addPackageReferences(set map[string]struct{}, references []packageReference) {
+	for _, reference := range references {
+		set[reference.ID] = struct{}{}
+	}
+}
+
+func nearestInherited(byDir map[string][]packageReference, dir string) []packageReference {
+	for {
+		if references, ok := byDir[dir]; ok {
+			return references
+		}
+		if dir == "." || dir == string(filepath.Separator) || dir == "" {
+			return nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func sortedInheritedKinds(inherited map[string]map[string][]packageReference) []string {
+	kinds := make([]string, 0, len(inherited))
+	for kind := range inherited {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func frameworksOverlap(conditions, frameworks []string) bool {
+	if len(conditions) == 0 || len(frameworks) == 0 {
+		return true
+	}
+	for _, condition := range conditions {
+		for _, framework := range frameworks {
+			if condition == framework {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package dotnet
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestParseProjectManifestHonoursTargetFrameworkConditions(t *testing.T) {
+	manifest, err := parseProjectManifest([]byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFrameworks>net48;net8.0</TargetFrameworks>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Acme.Core" />
+    <ProjectReference Include="..\Lib\Lib.csproj" />
+    <ProjectReference Include="..\..\..\Outside\Outside.csproj" />
+  </ItemGroup>
+  <ItemGroup Condition="'$(TargetFramework)' == 'net48'">
+    <PackageReference Include="System.ValueTuple" />
+  </ItemGroup>
+  <ItemGroup Condition=" '$(TargetFramework)' == 'net6.0' ">
+    <PackageReference Include="Legacy.Shim" />
+  </ItemGroup>
+  <ItemGroup>
+    <PackageReference Include="Modern.Only" Condition="'$(TargetFramework)' == 'net8.0'" />
+  </ItemGroup>
+</Project>`), filepath.Join("src", "App", "App.csproj"))
+	if err != nil {
+		t.Fatalf("parse project manifest: %v", err)
+	}
+	if !slices.Equal(manifest.Frameworks, []string{"net48", "net8.0"}) {
+		t.Fatalf("unexpected frameworks %#v", manifest.Frameworks)
+	}
+	if !slices.Equal(manifest.ProjectReferences, []string{filepath.Join("src", "Lib", "Lib.csproj")}) {
+		t.Fatalf("expected one repo-bounded project reference, got %#v", manifest.ProjectReferences)
+	}
+	applicable := make([]string, 0)
+	for _, reference := range manifest.applicablePackages() {
+		applicable = append(applicable, reference.ID)
+	}
+	if !slices.Equal(applicable, []string{"acme.core", "system.valuetuple", "modern.only"}) {
+		t.Fatalf("unexpected applicable packages %#v", applicable)
+	}
+}
+
+func TestDiscoverScanInputsResolvesProjectGraph(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "Directory.Build.props"), `
+<Project>
+  <ItemGroup>
+    <PackageReference Include="StyleCop.Analyzers" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Legacy", "Directory.Build.props"), `<Project />`)
+	testutil.MustWriteFile(t, filepath.Join(repo, centralPackagesFile), `
+<Project>
+  <ItemGroup>
+    <PackageVersion Include="Acme.Core" Version="1.0.0" />
+    <GlobalPackageReference Include="Acme.Analyzers" Version="2.0.0" />
+  </ItemGroup>
+</Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Lib", "Lib.csproj"), `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><PackageReference Include="Acme.Core" /></ItemGroup></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "App", "App.csproj"), `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><ProjectReference Include="..\Lib\Lib.csproj" /></ItemGroup></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Legacy", "Legacy.csproj"), `<Project ToolsVersion="15.0"></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Legacy", packagesConfigFile), `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="EntityFramework" version="6.4.4" targetFramework="net48" />
+</packages>`)
+
+	inputs, err := discoverScanInputs(context.Background(), repo, scanOptions{projectGraph: true})
+	if err != nil {
+		t.Fatalf("discover scan inputs: %v", err)
+	}
+	if !slices.Equal(inputs.DeclaredDependencies, []string{"acme.analyzers", "acme.core", "entityframework", "stylecop.analyzers"}) {
+		t.Fatalf("unexpected declared dependencies %#v", inputs.DeclaredDependencies)
+	}
+	declared := make(map[string][]string)
+	for _, project := range inputs.Projects {
+		declared[project.Manifest] = project.Declared
+	}
+	if got := declared[filepath.Join("src", "App", "App.csproj")]; !slices.Equal(got, []string{"acme.analyzers", "acme.core", "stylecop.analyzers"}) {
+		t.Fatalf("expected App to inherit and receive Lib packages, got %#v", got)
+	}
+	if got := declared[filepath.Join("src", "Legacy", "Legacy.csproj")]; !slices.Equal(got, []string{"acme.analyzers", "entityframework"}) {
+		t.Fatalf("expected Legacy to use packages.config and the nearest Directory.Build.props, got %#v", got)
+	}
+
+	inputs, err = discoverScanInputs(context.Background(), repo, scanOptions{})
+	if err != nil {
+		t.Fatalf("discover scan inputs without project graph: %v", err)
+	}
+	if !slices.Equal(inputs.DeclaredDependencies, []string{"acme.core"}) {
+		t.Fatalf("expected only project and central declarations without the preview flag, got %#v", inputs.DeclaredDependencies)
+	}
+	for _, project := range inputs.Projects {
+		if project.Declared != nil {
+			t.Fatalf("expected no per-project declarations without the preview flag, got %#v", project)
+		}
+	}
+}
+
+func TestScanRepoFlagsPackagesDeclaredByOtherProjects(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Lib", "Lib.csproj"), `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><PackageReference Include="Acme.Core" /></ItemGroup></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "App", "App.csproj"), `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup><ProjectReference Include="../Lib/Lib.csproj" /><ProjectReference Include="../App/App.csproj" /></ItemGroup></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Tool", "Tool.csproj"), `<Project Sdk="Microsoft.NET.Sdk"></Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "App", programSourceFileName), "using Acme.Core;\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Tool", programSourceFileName), "using Acme.Core.Tools;\n")
+
+	scan, err := scanRepo(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("scan repo: %v", err)
+	}
+	if len(scan.ProjectUndeclaredByDependency) != 0 {
+		t.Fatalf("expected no project-scoped usage without the preview flag, got %#v", scan.ProjectUndeclaredByDependency)
+	}
+
+	scan, err = scanRepoWithOptions(context.Background(), repo, scanOptions{projectGraph: true})
+	if err != nil {
+		t.Fatalf("scan repo with project graph: %v", err)
+	}
+	if got := scan.ProjectUndeclaredByDependency["acme.core"]; got != 1 {
+		t.Fatalf("expected one import from a project without acme.core, got %d", got)
+	}
+	if len(scan.UndeclaredByDependency) != 0 {
+		t.Fatalf("expected no repository-level undeclared packages, got %#v", scan.UndeclaredByDependency)
+	}
+	dep, warnings := buildDependencyReport("acme.core", scan, 40)
+	if len(dep.UsedImports) != 2 {
+		t.Fatalf("expected both imports attributed to acme.core, got %#v", dep.UsedImports)
+	}
+	if !hasRiskCue(dep, "project-undeclared-package-usage") || !hasRecommendation(dep, "declare-dependency-explicitly") || len(warnings) != 1 {
+		t.Fatalf("expected project-undeclared cue and recommendation, got cues=%#v warnings=%#v", dep.RiskCues, warnings)
+	}
+}
+
+func TestDetectWithXMLSolutionAddsProjectRoots(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "App.slnx"), `<Solution>
+  <Folder Name="/src/">
+    <Project Path="src/App/App.csproj" />
+    <Project Path="src\Lib\Lib.fsproj" />
+  </Folder>
+  <Project Path="../Outside/Outside.csproj" />
+</Solution>`)
+
+	detection, err := NewAdapter().DetectWithConfidence(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("detect: %v", err)
+	}
+	if !detection.Matched {
+		t.Fatalf("expected .slnx to match dotnet detection")
+	}
+	for _, want := range []string{filepath.Join(repo, "src", "App"), filepath.Join(repo, "src", "Lib")} {
+		if !slices.Contains(detection.Roots, want) {
+			t.Fatalf("expected root %q, got %#v", want, detection.Roots)
+		}
+	}
+	if slices.Contains(detection.Roots, filepath.Join(filepath.Dir(repo), "Outside")) {
+		t.Fatalf("expected out-of-repo project to be ignored, got %#v", detection.Roots)
+	}
+}
+
+func hasRiskCue(dep report.DependencyReport, code string) bool {
+	for _, cue := range dep.RiskCues {
+		if cue.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -76,6 +76,9 @@ func buildDependencyReport(dependency string, scan scanResult, minUsagePercentFo
 		UsedImports:       stats.UsedImports,
 		UnusedImports:     stats.UnusedImports,
 	}
+	if pkg, ok := scan.Assemblies.pkg(dependency); ok && pkg.PublicTypes > 0 {
+		applyAssemblyExportSurface(&dep, pkg, stats.HasImports)
+	}
 
 	ambiguousCount := scan.AmbiguousByDependency[dependency]
 	undeclaredCount := scan.UndeclaredByDependency[dependency]
@@ -96,6 +99,15 @@ func buildDependencyReport(dependency string, scan scanResult, minUsagePercentFo
 		})
 		warnings = append(warnings, fmt.Sprintf("dependency %q appears in source imports but is not declared in project manifests", dependency))
 	}
+	if projectUndeclaredCount := scan.ProjectUndeclaredByDependency[dependency]; projectUndeclaredCount > 0 {
+		dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+			Code:     "project-undeclared-package-usage",
+			Severity: "medium",
+			Message:  "imports use this package from projects that neither reference it nor inherit it through project references",
+		})
+		warnings = append(warnings, fmt.Sprintf("dependency %q is imported in %d import(s) from projects that do not declare it", dependency, projectUndeclaredCount))
+		undeclaredCount += projectUndeclaredCount
+	}
 	dep.Recommendations = buildRecommendations(dep, ambiguousCount, undeclaredCount, minUsagePercentForRecommendations)
 	return dep, warnings
 }
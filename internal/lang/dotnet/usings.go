@@ -0,0 +1,473 @@
+package dotnet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+const dotnetProjectUsingsPreviewFeature = "dotnet-project-usings-preview"
+
+var (
+	baseImplicitUsings    = []string{"System", "System.Collections.Generic", "System.IO", "System.Linq", "System.Net.Http", "System.Threading", "System.Threading.Tasks"}
+	hostingImplicitUsings = []string{"Microsoft.Extensions.Configuration", "Microsoft.Extensions.DependencyInjection", "Microsoft.Extensions.Hosting", "Microsoft.Extensions.Logging"}
+	webImplicitUsings     = []string{"System.Net.Http.Json", "Microsoft.AspNetCore.Builder", "Microsoft.AspNetCore.Hosting", "Microsoft.AspNetCore.Http", "Microsoft.AspNetCore.Routing"}
+)
+
+// projectUsing is a namespace imported into every source file of a project:
+// a `global using` directive, a <Using> item, or an SDK implicit using.
+type projectUsing struct {
+	Namespace string
+	Alias     string
+	Location  report.Location
+	Implicit  bool
+}
+
+// projectScope is a C#/F# project, the usings it applies to the sources
+// under its directory, and the packages it declares directly, inherits, or
+// receives through project references.
+type projectScope struct {
+	Dir      string
+	Manifest string
+	Usings   []projectUsing
+	Declared []string
+}
+
+// parseProjectUsings reads <Using> items and, for C# projects with
+// ImplicitUsings enabled, the namespaces the project SDK imports implicitly.
+// <Using Remove> drops either kind.
+func parseProjectUsings(content []byte, manifest string) ([]projectUsing, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	sdk := ""
+	implicitLine := 0
+	inImplicitUsings := false
+	implicitEnabled := false
+	usings := make([]projectUsing, 0)
+	removed := make(map[string]struct{})
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := decoder.InputPos()
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch strings.ToLower(element.Name.Local) {
+			case "project":
+				sdk = xmlAttribute(element, "Sdk")
+			case "implicitusings":
+				inImplicitUsings = true
+				implicitLine = line
+			case "using":
+				if namespace := normalizeNamespace(xmlAttribute(element, "Include")); namespace != "" {
+					usings = append(usings, projectUsing{
+						Namespace: namespace,
+						Alias:     xmlAttribute(element, "Alias"),
+						Location:  report.Location{File: manifest, Line: line},
+					})
+				}
+				if namespace := normalizeNamespace(xmlAttribute(element, "Remove")); namespace != "" {
+					removed[namespace] = struct{}{}
+				}
+			}
+		case xml.CharData:
+			if inImplicitUsings {
+				value := strings.ToLower(strings.TrimSpace(string(element)))
+				implicitEnabled = value == "enable" || value == "true"
+			}
+		case xml.EndElement:
+			if strings.EqualFold(element.Name.Local, "ImplicitUsings") {
+				inImplicitUsings = false
+			}
+		}
+	}
+	if implicitEnabled && strings.HasSuffix(strings.ToLower(manifest), csharpProjectExt) {
+		for _, namespace := range sdkImplicitUsings(sdk) {
+			usings = append(usings, projectUsing{
+				Namespace: namespace,
+				Location:  report.Location{File: manifest, Line: implicitLine},
+				Implicit:  true,
+			})
+		}
+	}
+	kept := usings[:0]
+	for _, using := range usings {
+		if _, ok := removed[using.Namespace]; !ok {
+			kept = append(kept, using)
+		}
+	}
+	return kept, nil
+}
+
+func xmlAttribute(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// sdkImplicitUsings lists the namespaces an SDK imports when ImplicitUsings
+// is enabled. Versioned SDK references ("Microsoft.NET.Sdk.Web/8.0.0") use
+// the same set as the unversioned name.
+func sdkImplicitUsings(sdk string) []string {
+	name, _, _ := strings.Cut(sdk, "/")
+	usings := append([]string(nil), baseImplicitUsings...)
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "microsoft.net.sdk.web":
+		usings = append(usings, webImplicitUsings...)
+		usings = append(usings, hostingImplicitUsings...)
+	case "microsoft.net.sdk.worker":
+		usings = append(usings, hostingImplicitUsings...)
+	}
+	return usings
+}
+
+// collectSourceGlobalUsings finds `global using` directives in a C# file.
+func collectSourceGlobalUsings(source sourceDocument) []projectUsing {
+	if !strings.EqualFold(filepath.Ext(source.RelativePath), csharpSourceExt) {
+		return nil
+	}
+	usings := make([]projectUsing, 0)
+	inBlockComment := false
+	forEachSourceLine(source.Content, func(lineNo int, raw, _ []byte) {
+		line, column, nextBlockComment := stripSourceCommentsBytes(raw, inBlockComment)
+		inBlockComment = nextBlockComment
+		if _, global := consumeKeyword(line, "global"); !global {
+			return
+		}
+		module, alias, ok := parseCSharpUsingBytes(line)
+		if !ok {
+			return
+		}
+		usings = append(usings, projectUsing{
+			Namespace: module,
+			Alias:     alias,
+			Location:  report.Location{File: source.RelativePath, Line: lineNo, Column: column},
+		})
+	})
+	return usings
+}
+
+type projectScopes []projectScope
+
+// newProjectScopes attaches source global usings to the project that owns
+// each file. Nested projects take precedence over the projects around them.
+func newProjectScopes(projects []projectScope, sources []sourceDocument) projectScopes {
+	scopes := append(projectScopes(nil), projects...)
+	sort.SliceStable(scopes, func(i, j int) bool {
+		if depth, other := scopeDepth(scopes[i].Dir), scopeDepth(scopes[j].Dir); depth != other {
+			return depth > other
+		}
+		return scopes[i].Manifest < scopes[j].Manifest
+	})
+	for _, source := range sources {
+		if scope := scopes.scopeFor(source.RelativePath); scope != nil {
+			scope.Usings = append(scope.Usings, collectSourceGlobalUsings(source)...)
+		}
+	}
+	return scopes
+}
+
+func scopeDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return len(dir)
+}
+
+func (s projectScopes) scopeFor(relativePath string) *projectScope {
+	dir := filepath.Dir(relativePath)
+	for i := range s {
+		if s[i].Dir == "." || dir == s[i].Dir || strings.HasPrefix(dir, s[i].Dir+string(filepath.Separator)) {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+func (s *projectScope) declaresGlobal(location report.Location) bool {
+	if s == nil {
+		return false
+	}
+	for _, using := range s.Usings {
+		if using.Location.File == location.File && using.Location.Line == location.Line {
+			return true
+		}
+	}
+	return false
+}
+
+// projectUsingResolver applies project-wide usings to each file and, with an
+// assembly index, attributes the types a file references to the packages that
+// define them.
+type projectUsingResolver struct {
+	mapper      dependencyMapper
+	mappers     map[string]dependencyMapper
+	assemblies  assemblyIndex
+	scopes      projectScopes
+	usedGlobals map[string]struct{}
+}
+
+// newProjectUsingResolver scopes each project's imports to the packages it
+// declares when projectScoped is set; otherwise every file resolves against
+// the repository-wide mapper.
+func newProjectUsingResolver(mapper dependencyMapper, scopes projectScopes, projectScoped bool) *projectUsingResolver {
+	mappers := make(map[string]dependencyMapper, len(scopes))
+	if projectScoped {
+		for _, scope := range scopes {
+			mappers[scope.Manifest] = mapper.projectMapper(scope.Declared)
+		}
+	}
+	return &projectUsingResolver{
+		mapper:      mapper,
+		mappers:     mappers,
+		assemblies:  mapper.assemblies,
+		scopes:      scopes,
+		usedGlobals: make(map[string]struct{}),
+	}
+}
+
+// mapperFor returns the mapper for the project that owns a file, or the
+// repository-wide mapper for files outside any project.
+func (r *projectUsingResolver) mapperFor(relativePath string) dependencyMapper {
+	if scope := r.scopes.scopeFor(relativePath); scope != nil {
+		return r.projectMapper(scope.Manifest)
+	}
+	return r.mapper
+}
+
+func (r *projectUsingResolver) projectMapper(manifest string) dependencyMapper {
+	if mapper, ok := r.mappers[manifest]; ok {
+		return mapper
+	}
+	return r.mapper
+}
+
+type namespaceUse struct {
+	namespace string
+	location  report.Location
+	global    bool
+}
+
+// applyToFile adds aliases declared by project-wide usings elsewhere and
+// replaces namespace wildcards for indexed namespaces with one import per
+// referenced type. Local usings of indexed namespaces whose types the file
+// never references are reported as unused.
+func (r *projectUsingResolver) applyToFile(file *fileScan, content []byte) {
+	scope := r.scopes.scopeFor(file.Path)
+	inherited := make([]projectUsing, 0)
+	if scope != nil {
+		for _, using := range scope.Usings {
+			if using.Location.File != file.Path {
+				inherited = append(inherited, using)
+			}
+		}
+	}
+	if len(inherited) == 0 && r.assemblies.empty() {
+		return
+	}
+
+	references := typeReferenceCounts(content)
+	imports := make([]importBinding, 0, len(file.Imports))
+	namespaces := make([]namespaceUse, 0)
+	for _, imported := range file.Imports {
+		if imported.Wildcard && r.assemblies.hasNamespace(imported.Module) {
+			namespaces = append(namespaces, namespaceUse{namespace: imported.Module, location: imported.Location, global: scope.declaresGlobal(imported.Location)})
+			continue
+		}
+		imports = append(imports, imported)
+	}
+	for _, using := range inherited {
+		switch {
+		case using.Alias != "":
+			imports = r.appendInheritedAlias(imports, file, using, references)
+		case r.assemblies.hasNamespace(using.Namespace):
+			namespaces = append(namespaces, namespaceUse{namespace: using.Namespace, location: using.Location, global: true})
+		}
+	}
+
+	names := sortedReferenceNames(references)
+	attributed := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	for _, use := range namespaces {
+		if _, ok := seen[use.namespace]; ok {
+			continue
+		}
+		seen[use.namespace] = struct{}{}
+		used := false
+		for _, name := range names {
+			dependency := r.assemblies.typeOwner(use.namespace, name)
+			if _, ok := attributed[name]; ok || dependency == "" {
+				continue
+			}
+			attributed[name] = struct{}{}
+			used = true
+			imports = append(imports, buildImportBinding(importBindingArgs{
+				dependency:   dependency,
+				module:       use.namespace,
+				name:         name,
+				local:        name,
+				relativePath: use.location.File,
+				lineNumber:   use.location.Line,
+				column:       use.location.Column,
+			}))
+			setUsage(file, name, references[name])
+		}
+		switch {
+		case used && use.global && scope != nil:
+			r.usedGlobals[scope.Manifest+"\x00"+use.namespace] = struct{}{}
+		case !used && !use.global:
+			imports = append(imports, r.unusedNamespaceBinding(use.namespace, use.location))
+		}
+	}
+	file.Imports = imports
+}
+
+func (r *projectUsingResolver) appendInheritedAlias(imports []importBinding, file *fileScan, using projectUsing, references map[string]int) []importBinding {
+	if references[using.Alias] == 0 {
+		return imports
+	}
+	mapper := r.mapperFor(file.Path)
+	dependency, resolved := resolveImportDependency(using.Namespace, mapper, &mappingMetadata{})
+	if !resolved {
+		return imports
+	}
+	setUsage(file, using.Alias, references[using.Alias])
+	return append(imports, buildImportBinding(importBindingArgs{
+		dependency:   dependency,
+		module:       using.Namespace,
+		name:         using.Alias,
+		local:        using.Alias,
+		relativePath: using.Location.File,
+		lineNumber:   using.Location.Line,
+		column:       using.Location.Column,
+	}))
+}
+
+func (r *projectUsingResolver) unusedNamespaceBinding(namespace string, location report.Location) importBinding {
+	return buildImportBinding(importBindingArgs{
+		dependency:   r.assemblies.namespaceOwner(namespace),
+		module:       namespace,
+		name:         "*",
+		relativePath: location.File,
+		lineNumber:   location.Line,
+		column:       location.Column,
+	})
+}
+
+func setUsage(file *fileScan, name string, count int) {
+	if file.Usage == nil {
+		file.Usage = make(map[string]int)
+	}
+	file.Usage[name] = count
+}
+
+// finish reports project-wide usings that no file of the project used: once
+// at the source directive or, for <Using> items and implicit usings, as
+// imports of the project file. Without assembly metadata, <Using> items
+// resolve like source usings; implicit usings never credit a package, as the
+// SDK namespaces they import ship with the framework rather than NuGet.
+func (r *projectUsingResolver) finish(files []fileScan, meta *mappingMetadata) []fileScan {
+	fileIndex := make(map[string]int, len(files))
+	for i, file := range files {
+		fileIndex[file.Path] = i
+	}
+	for _, scope := range r.scopes {
+		manifestFile := fileScan{Path: scope.Manifest, Usage: make(map[string]int)}
+		for _, using := range scope.Usings {
+			binding, ok := r.projectUsingBinding(scope, using, meta)
+			if !ok {
+				continue
+			}
+			if i, ok := fileIndex[using.Location.File]; ok {
+				files[i].Imports = append(files[i].Imports, binding)
+				continue
+			}
+			manifestFile.Imports = append(manifestFile.Imports, binding)
+		}
+		if len(manifestFile.Imports) > 0 {
+			files = append(files, manifestFile)
+		}
+	}
+	return files
+}
+
+func (r *projectUsingResolver) projectUsingBinding(scope projectScope, using projectUsing, meta *mappingMetadata) (importBinding, bool) {
+	if using.Alias == "" && r.assemblies.hasNamespace(using.Namespace) {
+		if _, used := r.usedGlobals[scope.Manifest+"\x00"+using.Namespace]; used {
+			return importBinding{}, false
+		}
+		return r.unusedNamespaceBinding(using.Namespace, using.Location), true
+	}
+	if using.Location.File != scope.Manifest {
+		return importBinding{}, false
+	}
+	if using.Implicit {
+		return importBinding{}, false
+	}
+	mapper := r.projectMapper(scope.Manifest)
+	dependency, resolved := resolveImportDependency(using.Namespace, mapper, meta)
+	if !resolved {
+		return importBinding{}, false
+	}
+	args := importBindingArgs{
+		dependency:   dependency,
+		module:       using.Namespace,
+		name:         "*",
+		wildcard:     true,
+		relativePath: using.Location.File,
+		lineNumber:   using.Location.Line,
+	}
+	if using.Alias != "" {
+		args.name, args.local, args.wildcard = using.Alias, using.Alias, false
+	}
+	return buildImportBinding(args), true
+}
+
+// typeReferenceCounts counts identifiers outside comments, strings, and
+// using/open directives.
+func typeReferenceCounts(content []byte) map[string]int {
+	counts := make(map[string]int)
+	forEachSourceLine(shared.MaskCommentsAndStrings(content), func(_ int, raw, _ []byte) {
+		line := bytes.TrimSpace(raw)
+		if _, _, ok := parseCSharpUsingBytes(line); ok {
+			return
+		}
+		if _, ok := parseFSharpOpenBytes(line); ok {
+			return
+		}
+		for i := 0; i < len(line); {
+			if !isNamespaceStartByte(line[i]) {
+				i++
+				continue
+			}
+			start := i
+			for i < len(line) && (isNamespaceStartByte(line[i]) || (line[i] >= '0' && line[i] <= '9')) {
+				i++
+			}
+			counts[string(line[start:i])]++
+		}
+	})
+	return counts
+}
+
+func sortedReferenceNames(references map[string]int) []string {
+	names := make([]string, 0, len(references))
+	for name := range references {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
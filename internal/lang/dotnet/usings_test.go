@@ -0,0 +1,151 @@
+package dotnet
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/language/languagetest"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestParseProjectUsingsExpandsImplicitUsingsAndItems(t *testing.T) {
+	usings, err := parseProjectUsings([]byte(`<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <ImplicitUsings>enable</ImplicitUsings>
+  </PropertyGroup>
+  <ItemGroup>
+    <Using Include="Acme.Core" />
+    <Using Include="Acme.Text.Formatter" Alias="Fmt" />
+    <Using Remove="System.Linq" />
+  </ItemGroup>
+</Project>`), "App.csproj")
+	if err != nil {
+		t.Fatalf("parse project usings: %v", err)
+	}
+	namespaces := make([]string, 0, len(usings))
+	for _, using := range usings {
+		namespaces = append(namespaces, using.Namespace)
+	}
+	for _, want := range []string{"Acme.Core", "Acme.Text.Formatter", "System", "Microsoft.AspNetCore.Http", "Microsoft.Extensions.Logging"} {
+		if !slices.Contains(namespaces, want) {
+			t.Fatalf("expected %q in project usings, got %v", want, namespaces)
+		}
+	}
+	if slices.Contains(namespaces, "System.Linq") {
+		t.Fatalf("expected <Using Remove> to drop System.Linq, got %v", namespaces)
+	}
+	if usings[1].Alias != "Fmt" || usings[1].Location.Line != 7 || usings[1].Implicit {
+		t.Fatalf("unexpected alias using %#v", usings[1])
+	}
+	if !usings[len(usings)-1].Implicit || usings[len(usings)-1].Location.Line != 3 {
+		t.Fatalf("expected implicit usings located at ImplicitUsings, got %#v", usings[len(usings)-1])
+	}
+
+	fsharp, err := parseProjectUsings([]byte(`<Project Sdk="Microsoft.NET.Sdk"><PropertyGroup><ImplicitUsings>enable</ImplicitUsings></PropertyGroup></Project>`), "Lib.fsproj")
+	if err != nil || len(fsharp) != 0 {
+		t.Fatalf("expected no implicit usings for F# projects, got %v (%v)", fsharp, err)
+	}
+}
+
+func TestScanRepoAppliesProjectUsings(t *testing.T) {
+	repo := t.TempDir()
+	writeManifestFixture(t, filepath.Join(repo, "src", "Worker", "Worker.csproj"), `
+<Project Sdk="Microsoft.NET.Sdk.Worker">
+  <PropertyGroup>
+    <ImplicitUsings>enable</ImplicitUsings>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Microsoft.Extensions.Hosting" Version="8.0.0" />
+    <PackageReference Include="Microsoft.Extensions.Logging.Console" Version="8.0.0" />
+    <PackageReference Include="Acme.Text" Version="1.0.0" />
+    <Using Include="Acme.Text.Formatter" Alias="Fmt" />
+  </ItemGroup>
+</Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Worker", "Usings.cs"), "global using Cli = Acme.Text.Cli;\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "Worker", "Jobs", "Job.cs"), "public class Job { string Run() => Fmt.Format(Cli.Args); }\n")
+
+	scan, err := scanRepoWithOptions(context.Background(), repo, scanOptions{projectUsings: true})
+	if err != nil {
+		t.Fatalf("scan repo: %v", err)
+	}
+
+	for _, sdkPackage := range []string{"microsoft.extensions.hosting", "microsoft.extensions.logging.console"} {
+		if dependency, _ := buildDependencyReport(sdkPackage, scan, 40); len(dependency.UsedImports) != 0 {
+			t.Fatalf("expected implicit usings not to credit %s without an assembly index, got %#v", sdkPackage, dependency.UsedImports)
+		}
+	}
+	if _, ok := scan.UndeclaredByDependency["microsoft.extensions"]; ok {
+		t.Fatalf("expected implicit usings not to report undeclared packages, got %#v", scan.UndeclaredByDependency)
+	}
+
+	text, _ := buildDependencyReport("acme.text", scan, 40)
+	used := make([]string, 0, len(text.UsedImports))
+	for _, imported := range text.UsedImports {
+		used = append(used, imported.Name)
+	}
+	if !slices.Equal(used, []string{"Cli", "Fmt"}) || len(text.UnusedImports) != 0 {
+		t.Fatalf("expected project-wide aliases used from Job.cs, got used=%v unused=%#v", used, text.UnusedImports)
+	}
+}
+
+func TestScanRepoIgnoresProjectUsingsWithoutPreview(t *testing.T) {
+	repo := t.TempDir()
+	writeManifestFixture(t, filepath.Join(repo, "App.csproj"), `
+<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Acme.Text" Version="1.0.0" />
+    <Using Include="Acme.Text.Formatter" Alias="Fmt" />
+  </ItemGroup>
+</Project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "Usings.cs"), "global using Cli = Acme.Text.Cli;\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "Job.cs"), "public class Job { string Run() => Fmt.Format(Cli.Args); }\n")
+
+	scan, err := scanRepo(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("scan repo: %v", err)
+	}
+	text, _ := buildDependencyReport("acme.text", scan, 40)
+	for _, imported := range append(text.UsedImports, text.UnusedImports...) {
+		if imported.Name == "Fmt" || len(imported.Locations) > 0 && imported.Locations[0].File == "Job.cs" {
+			t.Fatalf("expected project-wide usings to stay off without the preview flag, got %#v", imported)
+		}
+	}
+}
+
+func TestScanRepoWithFileCacheKeysSourcesByDeclaredPackages(t *testing.T) {
+	repo := t.TempDir()
+	project := func(packages ...string) string {
+		items := ""
+		for _, name := range packages {
+			items += `<PackageReference Include="` + name + `" Version="1.0.0" />`
+		}
+		return `<Project Sdk="Microsoft.NET.Sdk"><ItemGroup>` + items + `</ItemGroup></Project>`
+	}
+	writeManifestFixture(t, filepath.Join(repo, "App.csproj"), project("Acme.Text"))
+	testutil.MustWriteFile(t, filepath.Join(repo, "Program.cs"), "using Acme.Text.Json;\npublic class Program { object Run() => Json.Parse(); }\n")
+	cache := languagetest.NewMemoryFileCache()
+
+	scan := func() scanResult {
+		t.Helper()
+		result, err := scanRepoWithOptions(context.Background(), repo, scanOptions{fileCache: cache})
+		if err != nil {
+			t.Fatalf("scan repo: %v", err)
+		}
+		return result
+	}
+
+	first := scan()
+	second := scan()
+	if cache.Writes != 1 || cache.Hits != 1 || !reflect.DeepEqual(first.Files, second.Files) {
+		t.Fatalf("expected the second scan to reuse the cached file, got writes=%d hits=%d files=%#v", cache.Writes, cache.Hits, second.Files)
+	}
+
+	writeManifestFixture(t, filepath.Join(repo, "App.csproj"), project("Acme.Text", "Acme.Text.Json"))
+	third := scan()
+	if cache.Writes != 2 || third.Files[0].Imports[0].Dependency != "acme.text.json" {
+		t.Fatalf("expected a declared package change to rescan, got writes=%d files=%#v", cache.Writes, third.Files)
+	}
+}
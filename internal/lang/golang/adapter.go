@@ -47,7 +47,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		return report.Report{}, err
 	}
 
-	scanResult, err := scanRepo(ctx, repoPath, moduleInfo)
+	scanResult, err := scanRepoWithFileCache(ctx, repoPath, moduleInfo, req.FileCache)
 	if err != nil {
 		return report.Report{}, err
 	}
@@ -62,7 +62,19 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 }
 
 func scanRepo(ctx context.Context, repoPath string, moduleInfo moduleInfo) (scanResult, error) {
+	return scanRepoWithFileCache(ctx, repoPath, moduleInfo, nil)
+}
+
+// scanRepoWithFileCache scans like scanRepo but reuses per-file import scans
+// held in fileCache. Entries are keyed by the module info imports resolve
+// against, so go.mod, go.work, or vendoring changes miss every file.
+func scanRepoWithFileCache(ctx context.Context, repoPath string, moduleInfo moduleInfo, fileCache language.FileCache) (scanResult, error) {
 	result := newScanResult()
+	if fileCache != nil {
+		if inputs, ok := language.FileCacheInputs(moduleInfo); ok {
+			result.fileCache, result.fileCacheInputs = fileCache, inputs
+		}
+	}
 	if repoPath == "" {
 		return result, fs.ErrInvalid
 	}
@@ -176,16 +188,28 @@ func scanGoSourceFile(repoPath, path string, moduleInfo moduleInfo, result *scan
 		relativePath = path
 	}
 
-	imports, metadata := parseImports(content, relativePath, moduleInfo)
+	scanned := scanGoFileContent(result, content, relativePath, moduleInfo)
 	result.Files = append(result.Files, fileScan{
 		Path:    relativePath,
-		Imports: imports,
-		Usage:   shared.CountUsage(content, imports),
+		Imports: scanned.Imports,
+		Usage:   scanned.Usage,
 	})
-	applyImportMetadata(metadata, result)
+	applyImportMetadata(scanned.Metadata, result)
 	return nil
 }
 
+func scanGoFileContent(result *scanResult, content []byte, relativePath string, moduleInfo moduleInfo) cachedGoFile {
+	key := language.FileCacheKey{Adapter: "go", Schema: fileScanCacheSchema, Path: filepath.ToSlash(relativePath), Content: content, Inputs: result.fileCacheInputs}
+	var scanned cachedGoFile
+	if language.LoadCachedFile(result.fileCache, key, &scanned) {
+		return scanned
+	}
+	imports, metadata := parseImports(content, relativePath, moduleInfo)
+	scanned = cachedGoFile{Imports: imports, Metadata: metadata, Usage: shared.CountUsage(content, imports)}
+	language.StoreCachedFile(result.fileCache, key, scanned)
+	return scanned
+}
+
 type importMetadata struct {
 	Dependency string
 	IsBlank    bool
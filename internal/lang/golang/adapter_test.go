@@ -7,12 +7,14 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"slices"
 	"strings"
 	"testing"
 
 	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/language/languagetest"
 	"github.com/ben-ranford/lopper/internal/report"
 )
 
@@ -1610,3 +1612,38 @@ func writeFile(t *testing.T, path string, content string) {
 		t.Fatalf("write %s: %v", path, err)
 	}
 }
+
+func TestScanRepoWithFileCacheReusesScansUntilModuleInfoChanges(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoGoMod(t, repo, goModDemoWithUUID)
+	writeRepoMain(t, repo, mainUUIDNoopProgram)
+	cache := languagetest.NewMemoryFileCache()
+
+	scan := func() scanResult {
+		t.Helper()
+		info, err := loadGoModuleInfo(repo)
+		if err != nil {
+			t.Fatalf("loadGoModuleInfo: %v", err)
+		}
+		result, err := scanRepoWithFileCache(context.Background(), repo, info, cache)
+		if err != nil {
+			t.Fatalf("scanRepoWithFileCache: %v", err)
+		}
+		return result
+	}
+
+	first := scan()
+	second := scan()
+	if cache.Writes != 1 || cache.Hits != 1 {
+		t.Fatalf("expected the second scan to reuse the cached file, got writes=%d hits=%d", cache.Writes, cache.Hits)
+	}
+	if !reflect.DeepEqual(first.Files, second.Files) || !reflect.DeepEqual(first.UndeclaredImportsByDependency, second.UndeclaredImportsByDependency) {
+		t.Fatalf("expected cached scan to match a fresh one, got %#v and %#v", first, second)
+	}
+
+	writeRepoGoMod(t, repo, goModDemo)
+	third := scan()
+	if cache.Writes != 2 || third.UndeclaredImportsByDependency[depUUID] != 1 {
+		t.Fatalf("expected a go.mod change to rescan and report the undeclared import, got writes=%d %#v", cache.Writes, third.UndeclaredImportsByDependency)
+	}
+}
@@ -1,6 +1,9 @@
 package golang
 
-import "github.com/ben-ranford/lopper/internal/lang/shared"
+import (
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
+)
 
 type importBinding = shared.ImportRecord
 
@@ -20,6 +23,18 @@ type scanResult struct {
 	SkippedBuildTaggedFiles       int
 	SkippedLargeFiles             int
 	SkippedNestedModuleDirs       int
+	fileCache                     language.FileCache
+	fileCacheInputs               string
+}
+
+// fileScanCacheSchema versions the cachedGoFile layout and the import
+// resolution that produces it; bump it whenever either changes.
+const fileScanCacheSchema = "go-filescan-v1"
+
+type cachedGoFile struct {
+	Imports  []importBinding
+	Metadata []importMetadata
+	Usage    map[string]int
 }
 
 type moduleInfo struct {
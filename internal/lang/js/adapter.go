@@ -31,7 +31,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		RepoPath:    repoPath,
 	}
 
-	scanResult, err := ScanRepo(ctx, repoPath)
+	scanResult, err := ScanRepoWithFileCache(ctx, repoPath, req.FileCache)
 	if err != nil {
 		return report.Report{}, err
 	}
@@ -51,6 +51,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 			MinUsagePercentForRecommendations: resolveMinUsageRecommendationThreshold(req.MinUsagePercentForRecommendations),
 			SuggestOnly:                       req.SuggestOnly,
 			IncludeRegistryProvenance:         req.IncludeRegistryProvenance,
+			LicenseTemplates:                  req.Features.Enabled(shared.LicenseTemplatesPreviewFeature),
 		})
 		result.Dependencies = []report.DependencyReport{depReport}
 		result.Warnings = append(result.Warnings, warnings...)
@@ -59,7 +60,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		}
 		result.Summary = report.ComputeSummary(result.Dependencies)
 	case req.TopN > 0:
-		deps, warnings := buildTopDependencies(repoPath, scanResult, req.TopN, req.RuntimeProfile, resolveMinUsageRecommendationThreshold(req.MinUsagePercentForRecommendations), shared.ResolveRemovalCandidateWeights(req.RemovalCandidateWeights), req.IncludeRegistryProvenance)
+		deps, warnings := buildTopDependencies(repoPath, scanResult, req.TopN, req.RuntimeProfile, resolveMinUsageRecommendationThreshold(req.MinUsagePercentForRecommendations), shared.ResolveRemovalCandidateWeights(req.RemovalCandidateWeights), req.IncludeRegistryProvenance, req.Features.Enabled(shared.LicenseTemplatesPreviewFeature))
 		result.Dependencies = deps
 		result.Warnings = append(result.Warnings, warnings...)
 		if len(deps) == 0 {
@@ -7,26 +7,37 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ben-ranford/lopper/internal/lang/shared"
 	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
-func detectLicenseAndProvenance(depRoot string, includeRegistryProvenance bool) (*report.DependencyLicense, *report.DependencyProvenance, []string) {
+func detectLicenseAndProvenance(depRoot string, includeRegistryProvenance, licenseTemplates bool) (*report.DependencyLicense, *report.DependencyProvenance, []string) {
 	if strings.TrimSpace(depRoot) == "" {
 		return unknownDependencyLicense(), unknownDependencyProvenance(), []string{"unable to resolve dependency root for license/provenance detection"}
 	}
 	pkg, warnings := loadDependencyPackageJSON(depRoot)
-	license := detectLicenseFromMetadataOrFiles(pkg, depRoot)
+	license := detectLicenseFromMetadataOrFiles(pkg, depRoot, licenseTemplates)
 	provenance := buildProvenance(pkg, includeRegistryProvenance)
 	return license, provenance, warnings
 }
 
-func detectLicenseFromMetadataOrFiles(pkg packageJSON, depRoot string) *report.DependencyLicense {
-	if license := detectLicenseFromPackageJSON(pkg); license != nil {
+// detectLicenseFromMetadataOrFiles prefers a declared license. With templates
+// it also falls back to classifying bundled license files when package.json
+// declares nothing usable, such as "SEE LICENSE IN LICENSE.md".
+func detectLicenseFromMetadataOrFiles(pkg packageJSON, depRoot string, templates bool) *report.DependencyLicense {
+	declared := detectLicenseFromPackageJSON(pkg)
+	if declared != nil && (!declared.Unknown || !templates) {
+		return declared
+	}
+	if license := detectLicenseFromFiles(depRoot, templates); license != nil {
+		if declared != nil {
+			license.Raw = declared.Raw
+		}
 		return license
 	}
-	if license := detectLicenseFromFiles(depRoot); license != nil {
-		return license
+	if declared != nil {
+		return declared
 	}
 	return unknownDependencyLicense()
 }
@@ -68,7 +79,10 @@ func packageJSONLicenseRaw(pkg packageJSON) string {
 }
 
 func synthesizePackageJSONLicense(raw string) *report.DependencyLicense {
-	spdx := normalizeSPDXExpression(raw)
+	spdx := ""
+	if !isLicenseFileReference(raw) {
+		spdx = normalizeSPDXExpression(raw)
+	}
 	unknown := strings.TrimSpace(spdx) == ""
 	if unknown {
 		spdx = ""
@@ -86,6 +100,12 @@ func synthesizePackageJSONLicense(raw string) *report.DependencyLicense {
 	}
 }
 
+// isLicenseFileReference reports npm's "SEE LICENSE IN <file>" form, which
+// points at a bundled license file instead of declaring an SPDX expression.
+func isLicenseFileReference(raw string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(raw)), "SEE LICENSE IN ")
+}
+
 func parsePackageJSONLicense(value any) string {
 	switch typed := value.(type) {
 	case string:
@@ -104,105 +124,65 @@ func parsePackageJSONLicense(value any) string {
 }
 
 func normalizeSPDXExpression(raw string) string {
-	replaced := strings.TrimSpace(raw)
-	replaced = strings.ReplaceAll(replaced, "(", " ( ")
-	replaced = strings.ReplaceAll(replaced, ")", " ) ")
-	replaced = strings.ReplaceAll(replaced, " and ", " AND ")
-	replaced = strings.ReplaceAll(replaced, " or ", " OR ")
-	replaced = strings.ReplaceAll(replaced, "\t", " ")
-	replaced = strings.ReplaceAll(replaced, "\n", " ")
-	replaced = strings.ReplaceAll(replaced, "\r", " ")
-	parts := strings.Fields(replaced)
-	if len(parts) == 0 {
-		return ""
-	}
-
-	normalized := make([]string, 0, len(parts))
-	for _, part := range parts {
-		upper := strings.ToUpper(part)
-		switch upper {
-		case "AND", "OR", "WITH", "(", ")", "+":
-			normalized = append(normalized, upper)
-			continue
-		}
-		id := normalizeSPDXToken(part)
-		if id == "" {
-			continue
-		}
-		normalized = append(normalized, id)
-	}
-	if len(normalized) == 0 {
-		return ""
-	}
-	return strings.Join(normalized, " ")
-}
-
-func normalizeSPDXToken(value string) string {
-	var b strings.Builder
-	for _, r := range value {
-		switch {
-		case r >= 'a' && r <= 'z':
-			b.WriteRune(r - 'a' + 'A')
-		case r >= 'A' && r <= 'Z':
-			b.WriteRune(r)
-		case r >= '0' && r <= '9':
-			b.WriteRune(r)
-		case r == '-', r == '.', r == '+':
-			b.WriteRune(r)
-		}
-	}
-	return b.String()
+	return shared.NormalizeSPDXExpression(raw)
 }
 
 type licenseFileProbe struct {
-	path       string
-	spdx       string
-	confidence string
+	path  string
+	match shared.LicenseTextMatch
 }
 
-func detectLicenseFromFiles(depRoot string) *report.DependencyLicense {
-	probe := probeLicenseFiles(depRoot)
+func detectLicenseFromFiles(depRoot string, templates bool) *report.DependencyLicense {
+	probe := probeLicenseFiles(depRoot, templates)
 	if probe == nil {
 		return nil
 	}
 	return synthesizeLicenseFromFileProbe(*probe)
 }
 
-func probeLicenseFiles(depRoot string) *licenseFileProbe {
-	return probeLicenseCandidates(depRoot, findLicenseFiles(depRoot))
+func probeLicenseFiles(depRoot string, templates bool) *licenseFileProbe {
+	return probeLicenseCandidates(depRoot, findLicenseFiles(depRoot), templates)
 }
 
-func probeLicenseCandidates(depRoot string, candidates []string) *licenseFileProbe {
+func probeLicenseCandidates(depRoot string, candidates []string, templates bool) *licenseFileProbe {
 	for _, candidate := range candidates {
-		if probe := probeLicenseCandidate(depRoot, candidate); probe != nil {
+		if probe := probeLicenseCandidate(depRoot, candidate, templates); probe != nil {
 			return probe
 		}
 	}
 	return nil
 }
 
-func probeLicenseCandidate(depRoot, candidate string) *licenseFileProbe {
+// probeLicenseCandidate reads NOTICE files only with templates, since keyword
+// heuristics misread the third-party notices they usually hold.
+func probeLicenseCandidate(depRoot, candidate string, templates bool) *licenseFileProbe {
+	if !templates && shared.IsLicenseNoticeFileName(candidate) {
+		return nil
+	}
 	content, err := safeio.ReadFileUnder(depRoot, candidate)
 	if err != nil {
 		return nil
 	}
-	spdx, confidence := detectSPDXFromLicenseContent(string(content))
-	if spdx == "" {
+	match, ok := shared.DetectLicenseText(string(content), templates)
+	if !ok {
 		return nil
 	}
 	return &licenseFileProbe{
-		path:       candidate,
-		spdx:       spdx,
-		confidence: confidence,
+		path:  candidate,
+		match: match,
 	}
 }
 
 func synthesizeLicenseFromFileProbe(probe licenseFileProbe) *report.DependencyLicense {
+	evidence := []string{filepath.Base(probe.path)}
+	if template := probe.match.Evidence(); template != "" {
+		evidence = append(evidence, template)
+	}
 	return &report.DependencyLicense{
-		SPDX:       probe.spdx,
+		SPDX:       probe.match.SPDX,
 		Source:     "license-file",
-		Confidence: probe.confidence,
-		Evidence:   []string{filepath.Base(probe.path)},
+		Confidence: probe.match.Confidence,
+		Evidence:   evidence,
 	}
 }
 
@@ -238,28 +218,11 @@ func shouldSkipLicenseDir(depRoot, path string, d fs.DirEntry) bool {
 }
 
 func isLicenseCandidate(path string) bool {
-	base := strings.ToUpper(filepath.Base(path))
-	return strings.HasPrefix(base, "LICENSE") || strings.HasPrefix(base, "COPYING")
+	return shared.IsLicenseFileName(path) || shared.IsLicenseNoticeFileName(path)
 }
 
 func detectSPDXFromLicenseContent(content string) (string, string) {
-	text := strings.ToLower(content)
-	switch {
-	case strings.Contains(text, "mit license"):
-		return "MIT", "medium"
-	case strings.Contains(text, "apache license") && strings.Contains(text, "version 2.0"):
-		return "APACHE-2.0", "medium"
-	case strings.Contains(text, "gnu general public license"):
-		return "GPL-3.0-OR-LATER", "low"
-	case strings.Contains(text, "mozilla public license"):
-		return "MPL-2.0", "low"
-	case strings.Contains(text, "isc license"):
-		return "ISC", "medium"
-	case strings.Contains(text, "redistribution and use in source and binary forms"):
-		return "BSD-3-CLAUSE", "low"
-	default:
-		return "", ""
-	}
+	return shared.DetectSPDXFromLicenseText(content)
 }
 
 func buildProvenance(pkg packageJSON, includeRegistryProvenance bool) *report.DependencyProvenance {
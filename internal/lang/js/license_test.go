@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/ben-ranford/lopper/internal/testutil"
@@ -24,7 +25,7 @@ func TestDetectLicenseAndProvenanceFromPackageJSON(t *testing.T) {
   "license": "MIT OR Apache-2.0"
 }`)
 
-	license, provenance, warnings := detectLicenseAndProvenance(depRoot, false)
+	license, provenance, warnings := detectLicenseAndProvenance(depRoot, false, false)
 	if len(warnings) != 0 {
 		t.Fatalf("expected no warnings, got %#v", warnings)
 	}
@@ -41,12 +42,55 @@ func TestDetectLicenseFromFallbackLicenseFile(t *testing.T) {
 	testutil.MustWriteFile(t, filepath.Join(depRoot, licenseTestPackageJSONFileName), `{"name":"demo","version":"0.1.0"}`)
 	testutil.MustWriteFile(t, filepath.Join(depRoot, "LICENSE"), "MIT License\nPermission is hereby granted...")
 
-	license, _, _ := detectLicenseAndProvenance(depRoot, false)
+	license, _, _ := detectLicenseAndProvenance(depRoot, false, false)
 	if license == nil || license.SPDX != "MIT" || license.Source != "license-file" {
 		t.Fatalf("expected MIT fallback from LICENSE file, got %#v", license)
 	}
 }
 
+func TestDetectLicenseClassifiesFileWhenPackageJSONLicenseIsUnknown(t *testing.T) {
+	depRoot := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(depRoot, licenseTestPackageJSONFileName), `{"name":"demo","version":"0.1.0","license":"SEE LICENSE IN LICENSE.md"}`)
+	testutil.MustWriteFile(t, filepath.Join(depRoot, "LICENSE.md"), `ISC License
+
+Copyright (c) 2024 Demo Authors
+
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+`)
+
+	if license, _, _ := detectLicenseAndProvenance(depRoot, false, false); license == nil || !license.Unknown || license.Source != "package.json" {
+		t.Fatalf("expected unknown package.json license without templates, got %#v", license)
+	}
+
+	license, _, _ := detectLicenseAndProvenance(depRoot, false, true)
+	if license == nil || license.SPDX != "ISC" || license.Source != "license-file" || license.Confidence != "high" || license.Unknown {
+		t.Fatalf("expected ISC detected from LICENSE.md, got %#v", license)
+	}
+	if license.Raw != "SEE LICENSE IN LICENSE.md" || strings.Join(license.Evidence, ",") != "LICENSE.md,template:ISC similarity=1.00" {
+		t.Fatalf("expected declared raw value and template evidence, got %#v", license)
+	}
+}
+
+func TestDetectLicenseKeepsUnknownDeclarationWithoutLicenseFile(t *testing.T) {
+	depRoot := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(depRoot, licenseTestPackageJSONFileName), `{"name":"demo","version":"0.1.0","license":"SEE LICENSE IN EULA"}`)
+
+	license, _, _ := detectLicenseAndProvenance(depRoot, false, false)
+	if license == nil || !license.Unknown || license.Source != "package.json" || license.Raw != "SEE LICENSE IN EULA" {
+		t.Fatalf("expected unknown package.json license to be kept, got %#v", license)
+	}
+}
+
 func TestDetectProvenanceWithRegistryHeuristics(t *testing.T) {
 	depRoot := t.TempDir()
 	testutil.MustWriteFile(t, filepath.Join(depRoot, licenseTestPackageJSONFileName), `{
@@ -58,7 +102,7 @@ func TestDetectProvenanceWithRegistryHeuristics(t *testing.T) {
   "publishConfig": { "registry": "https://registry.npmjs.org/" }
 }`)
 
-	_, provenance, _ := detectLicenseAndProvenance(depRoot, true)
+	_, provenance, _ := detectLicenseAndProvenance(depRoot, true, false)
 	if provenance == nil || provenance.Source != "local+registry-heuristics" {
 		t.Fatalf("expected registry provenance source, got %#v", provenance)
 	}
@@ -130,7 +174,7 @@ func TestHasRepositorySignal(t *testing.T) {
 }
 
 func TestDetectLicenseAndProvenanceMissingRoot(t *testing.T) {
-	license, provenance, warnings := detectLicenseAndProvenance("", false)
+	license, provenance, warnings := detectLicenseAndProvenance("", false, false)
 	if license == nil || !license.Unknown {
 		t.Fatalf("expected unknown license for missing root, got %#v", license)
 	}
@@ -237,7 +281,7 @@ func TestFindLicenseFilesLimit(t *testing.T) {
 func TestDetectLicenseFromFilesNoMatch(t *testing.T) {
 	root := t.TempDir()
 	testutil.MustWriteFile(t, filepath.Join(root, "LICENSE"), "custom internal license text")
-	if got := detectLicenseFromFiles(root); got != nil {
+	if got := detectLicenseFromFiles(root, false); got != nil {
 		t.Fatalf("expected nil fallback for unknown license text, got %#v", got)
 	}
 }
@@ -249,11 +293,11 @@ func TestProbeLicenseCandidatesSkipsUnknownUntilMatch(t *testing.T) {
 	testutil.MustWriteFile(t, unknown, "custom internal license text")
 	testutil.MustWriteFile(t, known, "MIT License\nPermission is hereby granted...")
 
-	probe := probeLicenseCandidates(root, []string{unknown, known})
+	probe := probeLicenseCandidates(root, []string{unknown, known}, false)
 	if probe == nil {
 		t.Fatalf("expected probe result for known license candidate")
 	}
-	if probe.path != known || probe.spdx != "MIT" || probe.confidence != "medium" {
+	if probe.path != known || probe.match.SPDX != "MIT" || probe.match.Confidence != "medium" {
 		t.Fatalf("unexpected probe result: %#v", probe)
 	}
 }
@@ -40,6 +40,7 @@ type dependencyReportOptions struct {
 	MinUsagePercentForRecommendations int
 	SuggestOnly                       bool
 	IncludeRegistryProvenance         bool
+	LicenseTemplates                  bool
 }
 
 func buildDependencyReport(opts dependencyReportOptions) (report.DependencyReport, []string) {
@@ -61,7 +62,7 @@ func buildDependencyReport(opts dependencyReportOptions) (report.DependencyRepor
 
 	riskCues, riskWarnings := assessRiskCues(opts.RepoPath, opts.Dependency, opts.DependencyRootPath, surface)
 	warnings = append(warnings, riskWarnings...)
-	license, provenance, licenseWarnings := detectLicenseAndProvenance(opts.DependencyRootPath, opts.IncludeRegistryProvenance)
+	license, provenance, licenseWarnings := detectLicenseAndProvenance(opts.DependencyRootPath, opts.IncludeRegistryProvenance, opts.LicenseTemplates)
 	warnings = append(warnings, licenseWarnings...)
 	coverageIncomplete := opts.ScanResult.UsageIncomplete || surface.CoverageIncomplete
 
@@ -446,7 +447,7 @@ func matchesDependency(module, dependency string) bool {
 	return false
 }
 
-func buildTopDependencies(repoPath string, scanResult ScanResult, topN int, runtimeProfile string, minUsagePercentForRecommendations int, weights report.RemovalCandidateWeights, includeRegistryProvenance bool) ([]report.DependencyReport, []string) {
+func buildTopDependencies(repoPath string, scanResult ScanResult, topN int, runtimeProfile string, minUsagePercentForRecommendations int, weights report.RemovalCandidateWeights, includeRegistryProvenance, licenseTemplates bool) ([]report.DependencyReport, []string) {
 	dependencies, dependencyRoots, warnings := listDependencies(repoPath, scanResult)
 	if len(dependencies) == 0 {
 		return nil, warnings
@@ -463,6 +464,7 @@ func buildTopDependencies(repoPath string, scanResult ScanResult, topN int, runt
 			MinUsagePercentForRecommendations: minUsagePercentForRecommendations,
 			SuggestOnly:                       false,
 			IncludeRegistryProvenance:         includeRegistryProvenance,
+			LicenseTemplates:                  licenseTemplates,
 		})
 		reports = append(reports, depReport)
 		warnings = append(warnings, depWarnings...)
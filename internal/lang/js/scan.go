@@ -10,6 +10,7 @@ import (
 
 	sitter "github.com/smacker/go-tree-sitter"
 
+	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/report"
 )
 
@@ -73,6 +74,13 @@ var skipDirectories = map[string]bool{
 }
 
 func ScanRepo(ctx context.Context, repoPath string) (ScanResult, error) {
+	return ScanRepoWithFileCache(ctx, repoPath, nil)
+}
+
+// ScanRepoWithFileCache scans like ScanRepo but reuses the per-file scans held
+// in fileCache, parsing only files whose content changed since they were
+// stored.
+func ScanRepoWithFileCache(ctx context.Context, repoPath string, fileCache language.FileCache) (ScanResult, error) {
 	result := ScanResult{}
 	if repoPath == "" {
 		return result, errors.New("repo path is empty")
@@ -80,9 +88,10 @@ func ScanRepo(ctx context.Context, repoPath string) (ScanResult, error) {
 
 	parser := newSourceParser()
 	state := scanRepoState{
-		parser:   parser,
-		repoPath: repoPath,
-		result:   &result,
+		parser:    parser,
+		repoPath:  repoPath,
+		result:    &result,
+		fileCache: fileCache,
 	}
 
 	err := filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
@@ -56,30 +56,37 @@ func (p *sourceParser) languageForPath(path string) (*sitter.Language, error) {
 }
 
 func readAndParseFile(ctx context.Context, parser *sourceParser, repoPath string, path string) ([]byte, *sitter.Tree, string, error) {
-	var (
-		content []byte
-		readErr error
-	)
-	if strings.TrimSpace(repoPath) == "" {
-		content, readErr = safeio.ReadFileLimit(path, maxScannableJSFile)
-	} else {
-		content, readErr = safeio.ReadFileUnderLimit(repoPath, path, maxScannableJSFile)
-	}
+	content, readErr := readSourceFile(repoPath, path)
 	if readErr != nil {
 		return nil, nil, "", readErr
 	}
+	tree, relPath, err := parseSourceFile(ctx, parser, repoPath, path, content)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return content, tree, relPath, nil
+}
+
+func readSourceFile(repoPath string, path string) ([]byte, error) {
+	if strings.TrimSpace(repoPath) == "" {
+		return safeio.ReadFileLimit(path, maxScannableJSFile)
+	}
+	return safeio.ReadFileUnderLimit(repoPath, path, maxScannableJSFile)
+}
+
+func parseSourceFile(ctx context.Context, parser *sourceParser, repoPath string, path string, content []byte) (*sitter.Tree, string, error) {
 	tree, langErr := parser.Parse(ctx, path, content)
 	if langErr != nil {
-		return nil, nil, "", langErr
+		return nil, "", langErr
 	}
 	if tree == nil {
-		return nil, nil, "", fmt.Errorf("tree-sitter returned nil tree for %s", path)
+		return nil, "", fmt.Errorf("tree-sitter returned nil tree for %s", path)
 	}
 	relPath, relErr := filepath.Rel(repoPath, path)
 	if relErr != nil {
 		relPath = path
 	}
-	return content, tree, relPath, nil
+	return tree, relPath, nil
 }
 
 func isSupportedFile(path string) bool {
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
@@ -18,6 +19,16 @@ type scanRepoState struct {
 	parseErrorFiles []string
 	oversizedCount  int
 	oversizedFiles  []string
+	fileCache       language.FileCache
+}
+
+// fileScanCacheSchema versions the cachedFileScan layout and the analysis
+// that produces it; bump it whenever either changes.
+const fileScanCacheSchema = "js-filescan-v1"
+
+type cachedFileScan struct {
+	Scan       FileScan
+	ParseError bool
 }
 
 func scanRepoEntry(ctx context.Context, state *scanRepoState, path string, entry fs.DirEntry) error {
@@ -31,7 +42,7 @@ func scanRepoEntry(ctx context.Context, state *scanRepoState, path string, entry
 		return nil
 	}
 
-	content, tree, relPath, err := readAndParseFile(ctx, state.parser, state.repoPath, path)
+	content, err := readSourceFile(state.repoPath, path)
 	if err != nil {
 		if errors.Is(err, safeio.ErrFileTooLarge) {
 			state.oversizedCount++
@@ -46,14 +57,36 @@ func scanRepoEntry(ctx context.Context, state *scanRepoState, path string, entry
 		}
 		return err
 	}
-	if tree.RootNode().HasError() {
+	scanned, err := scanSourceFile(ctx, state, path, content)
+	if err != nil {
+		return err
+	}
+	if scanned.ParseError {
 		state.parseErrorCount++
-		appendParseErrorFile(&state.parseErrorFiles, relPath)
+		appendParseErrorFile(&state.parseErrorFiles, scanned.Scan.Path)
 	}
-	state.result.Files = append(state.result.Files, analyzeFile(tree, content, relPath))
+	state.result.Files = append(state.result.Files, scanned.Scan)
 	return nil
 }
 
+func scanSourceFile(ctx context.Context, state *scanRepoState, path string, content []byte) (cachedFileScan, error) {
+	key := language.FileCacheKey{Adapter: "js-ts", Schema: fileScanCacheSchema, Path: path, Content: content}
+	if relPath, relErr := filepath.Rel(state.repoPath, path); relErr == nil {
+		key.Path = filepath.ToSlash(relPath)
+	}
+	var scanned cachedFileScan
+	if language.LoadCachedFile(state.fileCache, key, &scanned) {
+		return scanned, nil
+	}
+	tree, relPath, err := parseSourceFile(ctx, state.parser, state.repoPath, path, content)
+	if err != nil {
+		return cachedFileScan{}, err
+	}
+	scanned = cachedFileScan{Scan: analyzeFile(tree, content, relPath), ParseError: tree.RootNode().HasError()}
+	language.StoreCachedFile(state.fileCache, key, scanned)
+	return scanned, nil
+}
+
 func appendParseErrorFile(parseErrorFiles *[]string, relPath string) {
 	if len(*parseErrorFiles) < 5 {
 		*parseErrorFiles = append(*parseErrorFiles, relPath)
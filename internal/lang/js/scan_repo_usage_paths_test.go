@@ -121,7 +121,7 @@ func TestJSIncompleteScanDisablesTopNRemovalRanking(t *testing.T) {
 		},
 	}}
 
-	topReports, topWarnings := buildTopDependencies(repo, result, 1, "", 1, report.DefaultRemovalCandidateWeights(), false)
+	topReports, topWarnings := buildTopDependencies(repo, result, 1, "", 1, report.DefaultRemovalCandidateWeights(), false, false)
 	if len(topReports) != 2 || topReports[0].Name != "alpha" || topReports[1].Name != "beta" {
 		t.Fatalf("expected deterministic unranked reports from incomplete usage, got %#v", topReports)
 	}
@@ -160,7 +160,7 @@ func TestJSIncompleteDependencySurfaceDisablesTopNRemovalRanking(t *testing.T) {
 	if err != nil {
 		t.Fatalf("scan repo: %v", err)
 	}
-	topReports, topWarnings := buildTopDependencies(repo, result, 1, "", 1, report.DefaultRemovalCandidateWeights(), false)
+	topReports, topWarnings := buildTopDependencies(repo, result, 1, "", 1, report.DefaultRemovalCandidateWeights(), false, false)
 	if len(topReports) != 2 || topReports[0].Name != "alpha" || topReports[1].Name != "beta" {
 		t.Fatalf("expected deterministic unranked reports from incomplete dependency coverage, got %#v", topReports)
 	}
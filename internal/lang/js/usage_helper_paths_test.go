@@ -29,7 +29,7 @@ func TestJSLicenseAndStringHelperAdditionalBranches(t *testing.T) {
 	}
 	testutil.MustWriteFile(t, filepath.Join(root, "COPYING"), "Mozilla Public License")
 
-	license := detectLicenseFromFiles(root)
+	license := detectLicenseFromFiles(root, false)
 	if license == nil || license.SPDX != "MPL-2.0" || license.Source != "license-file" {
 		t.Fatalf("expected license fallback to continue past unreadable candidate, got %#v", license)
 	}
@@ -263,7 +263,7 @@ func TestJSLicenseAndProvenanceAdditionalBranches(t *testing.T) {
 	}
 	testutil.MustWriteFile(t, filepath.Join(root, "LICENSE"), "Apache License Version 2.0")
 
-	license := detectLicenseFromFiles(root)
+	license := detectLicenseFromFiles(root, false)
 	if license == nil || license.SPDX != "APACHE-2.0" {
 		t.Fatalf("expected license detection to continue past unreadable candidate, got %#v", license)
 	}
@@ -73,10 +73,19 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (result rep
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, declarationWarnings...)
-	scanResult, err := scanRepoWithinRoot(ctx, repoPath, root, depPrefixes, depAliases)
+	var artifacts jarIndex
+	if req.Features.Enabled(jvmJarIndexPreviewFeature) {
+		var indexWarnings []string
+		artifacts, indexWarnings = indexDeclaredJars(declaredDependencies, defaultJarCacheRoots())
+		artifacts.addPackagePrefixes(depPrefixes)
+		result.Warnings = append(result.Warnings, indexWarnings...)
+	}
+	scanResult, err := scanRepoWithinRootWithFileCache(ctx, repoPath, root, depPrefixes, depAliases, req.Features.Enabled(shared.JVMRuntimeUsagePreviewFeature), req.FileCache)
 	if err != nil {
 		return report.Report{}, err
 	}
+	scanResult.Artifacts = artifacts
+	recordDeclaredRuntimeUsage(&scanResult, declaredDependencies)
 	result.Warnings = append(result.Warnings, scanResult.Warnings...)
 
 	dependencies, warnings := buildRequestedJVMDependencies(req, scanResult)
@@ -338,7 +338,7 @@ func TestJVMSourceScanSkipsLeafSymlinkViaPinnedPathReader(t *testing.T) {
 		t.Fatalf("expected actual symlink entry to classify as ELOOP and target symlink, got %v", err)
 	}
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil)
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil, false)
 	if err != nil {
 		t.Fatalf("expected leaf symlink read to be downgraded, got %v", err)
 	}
@@ -15,9 +15,11 @@ import (
 )
 
 type dependencyDescriptor struct {
-	Name     string
-	Group    string
-	Artifact string
+	Name                string
+	Group               string
+	Artifact            string
+	Version             string
+	AnnotationProcessor bool
 }
 
 type pomProjectModel struct {
@@ -30,6 +32,16 @@ type pomProjectModel struct {
 	DependencyManagement struct {
 		Dependencies []pomDependencyModel `xml:"dependencies>dependency"`
 	} `xml:"dependencyManagement"`
+	Build struct {
+		Plugins          []pomPluginModel `xml:"plugins>plugin"`
+		PluginManagement struct {
+			Plugins []pomPluginModel `xml:"plugins>plugin"`
+		} `xml:"pluginManagement"`
+	} `xml:"build"`
+}
+
+type pomPluginModel struct {
+	AnnotationProcessorPaths []pomDependencyModel `xml:"configuration>annotationProcessorPaths>path"`
 }
 
 type pomParentModel struct {
@@ -93,9 +105,11 @@ func collectBuildDescriptors(repoPath string) ([]dependencyDescriptor, []string)
 			catalogDescriptors, catalogWarnings := catalogResolver.ParseDependencyReferences(path, content)
 			for _, descriptor := range catalogDescriptors {
 				descriptors = append(descriptors, dependencyDescriptor{
-					Name:     descriptor.Artifact,
-					Group:    descriptor.Group,
-					Artifact: descriptor.Artifact,
+					Name:                descriptor.Artifact,
+					Group:               descriptor.Group,
+					Artifact:            descriptor.Artifact,
+					Version:             descriptor.Version,
+					AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(descriptor.Configuration),
 				})
 			}
 			return dedupeAndSortDescriptors(descriptors), catalogWarnings
@@ -123,9 +137,11 @@ func collectBuildDescriptorsWithinRoot(ctx context.Context, repoPath string, roo
 			catalogDescriptors, catalogWarnings := catalogResolver.ParseDependencyReferences(path, content)
 			for _, descriptor := range catalogDescriptors {
 				descriptors = append(descriptors, dependencyDescriptor{
-					Name:     descriptor.Artifact,
-					Group:    descriptor.Group,
-					Artifact: descriptor.Artifact,
+					Name:                descriptor.Artifact,
+					Group:               descriptor.Group,
+					Artifact:            descriptor.Artifact,
+					Version:             descriptor.Version,
+					AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(descriptor.Configuration),
 				})
 			}
 			return dedupeAndSortDescriptors(descriptors), catalogWarnings
@@ -149,6 +165,12 @@ func dedupeAndSortDescriptors(descriptors []dependencyDescriptor) []dependencyDe
 		if descriptor.Group == "" {
 			key = descriptor.Name
 		}
+		if existing, ok := unique[key]; ok {
+			descriptor.AnnotationProcessor = descriptor.AnnotationProcessor || existing.AnnotationProcessor
+			if descriptor.Version == "" {
+				descriptor.Version = existing.Version
+			}
+		}
 		unique[key] = descriptor
 	}
 	items := make([]dependencyDescriptor, 0, len(unique))
@@ -246,10 +268,12 @@ func parsePomDependencyContent(relativePath, content string) ([]dependencyDescri
 	propertyMap := buildPomPropertyMap(project)
 	directDescriptors, directWarnings := parsePomDependencyList(project.Dependencies, propertyMap, pomDependencyDirect, relativePath)
 	managedDescriptors, managedWarnings := parsePomDependencyList(project.DependencyManagement.Dependencies, propertyMap, pomDependencyManaged, relativePath)
+	processorDescriptors := parsePomAnnotationProcessorPaths(project, propertyMap)
 
-	descriptors := make([]dependencyDescriptor, 0, len(directDescriptors)+len(managedDescriptors))
+	descriptors := make([]dependencyDescriptor, 0, len(directDescriptors)+len(managedDescriptors)+len(processorDescriptors))
 	descriptors = append(descriptors, directDescriptors...)
 	descriptors = append(descriptors, managedDescriptors...)
+	descriptors = append(descriptors, processorDescriptors...)
 
 	warnings := make([]string, 0, len(directWarnings)+len(managedWarnings))
 	warnings = append(warnings, directWarnings...)
@@ -285,11 +309,14 @@ func parsePomDependency(dependency pomDependencyModel, propertyMap map[string]st
 		Group:    group,
 		Artifact: artifact,
 	}
+	version, unresolvedVersion := resolvePomPropertyValue(dependency.Version, propertyMap)
+	if !unresolvedVersion {
+		descriptor.Version = version
+	}
 	if kind != pomDependencyManaged {
 		return descriptor, ""
 	}
 
-	version, unresolvedVersion := resolvePomPropertyValue(dependency.Version, propertyMap)
 	if !isPomImportedBOM(dependency) {
 		if version == "" || unresolvedVersion {
 			return descriptor, fmt.Sprintf("unable to resolve managed Maven version for %s:%s in %s", group, artifact, relativePath)
@@ -303,6 +330,25 @@ func parsePomDependency(dependency pomDependencyModel, propertyMap map[string]st
 	return descriptor, ""
 }
 
+// parsePomAnnotationProcessorPaths reads maven-compiler-plugin
+// annotationProcessorPaths, which put processors on the compiler's processor
+// path without declaring them as project dependencies.
+func parsePomAnnotationProcessorPaths(project pomProjectModel, propertyMap map[string]string) []dependencyDescriptor {
+	plugins := append(append([]pomPluginModel{}, project.Build.Plugins...), project.Build.PluginManagement.Plugins...)
+	descriptors := make([]dependencyDescriptor, 0)
+	for _, plugin := range plugins {
+		for _, path := range plugin.AnnotationProcessorPaths {
+			descriptor, _ := parsePomDependency(path, propertyMap, pomDependencyDirect, "")
+			if descriptor.Group == "" || descriptor.Artifact == "" {
+				continue
+			}
+			descriptor.AnnotationProcessor = true
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	return descriptors
+}
+
 func isPomImportedBOM(dependency pomDependencyModel) bool {
 	return strings.EqualFold(strings.TrimSpace(dependency.Type), "pom") &&
 		strings.EqualFold(strings.TrimSpace(dependency.Scope), "import")
@@ -426,9 +472,11 @@ func parseGradleDependenciesWithWarnings(repoPath string) ([]dependencyDescripto
 		catalogDescriptors, catalogWarnings := catalogResolver.ParseDependencyReferences(path, content)
 		for _, descriptor := range catalogDescriptors {
 			descriptors = append(descriptors, dependencyDescriptor{
-				Name:     descriptor.Artifact,
-				Group:    descriptor.Group,
-				Artifact: descriptor.Artifact,
+				Name:                descriptor.Artifact,
+				Group:               descriptor.Group,
+				Artifact:            descriptor.Artifact,
+				Version:             descriptor.Version,
+				AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(descriptor.Configuration),
 			})
 		}
 		return dedupeAndSortDescriptors(descriptors), catalogWarnings
@@ -443,9 +491,11 @@ func parseGradleDependencyContent(path, content string) []dependencyDescriptor {
 	descriptors := make([]dependencyDescriptor, 0, len(coordinates))
 	for _, coordinate := range coordinates {
 		descriptors = append(descriptors, dependencyDescriptor{
-			Name:     coordinate.Artifact,
-			Group:    coordinate.Group,
-			Artifact: coordinate.Artifact,
+			Name:                coordinate.Artifact,
+			Group:               coordinate.Group,
+			Artifact:            coordinate.Artifact,
+			Version:             coordinate.Version,
+			AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(coordinate.Configuration),
 		})
 	}
 	return descriptors
@@ -0,0 +1,326 @@
+package jvm
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	jvmJarIndexPreviewFeature = "jvm-jar-index-preview"
+	maxIndexedJarBytes        = 64 << 20
+	maxIndexedJarClasses      = 65536
+	maxIndexedClassBytes      = 1 << 20
+	javaClassMagic            = 0xCAFEBABE
+	javaClassAccessPublic     = 0x0001
+)
+
+var errInvalidClassFile = errors.New("invalid class file")
+
+// jarArtifact is the export surface of one declared dependency, read from its
+// jar in a local artifact cache. Services lists the descriptors through which
+// the jar registers providers that are loaded without imports.
+type jarArtifact struct {
+	Dependency    string
+	Jar           string
+	Packages      []string
+	PublicClasses int
+	Services      []string
+}
+
+// jarIndex maps declared dependencies to the packages and public classes their
+// jars actually contain, so imports resolve exactly instead of by groupId.
+type jarIndex struct {
+	artifacts map[string]jarArtifact
+}
+
+// jarCacheRoot is a read-only artifact cache and the layout used to find a
+// dependency's jar inside it.
+type jarCacheRoot struct {
+	label  string
+	path   string
+	locate func(root string, descriptor dependencyDescriptor) []cachedJar
+}
+
+func defaultJarCacheRoots() []jarCacheRoot {
+	roots := make([]jarCacheRoot, 0, 2)
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		roots = append(roots, jarCacheRoot{label: "M2", path: filepath.Join(home, ".m2", "repository"), locate: mavenRepositoryJars})
+	}
+	gradleHome := strings.TrimSpace(os.Getenv("GRADLE_USER_HOME"))
+	if gradleHome == "" {
+		if home, err := os.UserHomeDir(); err == nil && home != "" {
+			gradleHome = filepath.Join(home, ".gradle")
+		}
+	}
+	if gradleHome != "" {
+		roots = append(roots, jarCacheRoot{label: "GRADLE_USER_HOME", path: filepath.Join(gradleHome, "caches", "modules-2", "files-2.1"), locate: gradleCacheJars})
+	}
+	return roots
+}
+
+// indexDeclaredJars reads the cached jar matching each dependency's declared
+// version. Dependencies without a declared version or a cached jar of that
+// version keep the groupId prefix heuristics.
+func indexDeclaredJars(descriptors []dependencyDescriptor, roots []jarCacheRoot) (jarIndex, []string) {
+	index := jarIndex{artifacts: make(map[string]jarArtifact)}
+	warnings := make([]string, 0)
+	for _, descriptor := range descriptors {
+		if descriptor.Group == "" || descriptor.Artifact == "" || descriptor.Version == "" {
+			continue
+		}
+		dependency := normalizeDependencyID(descriptor.Name)
+		if _, ok := index.artifacts[dependency]; ok {
+			continue
+		}
+		for _, root := range roots {
+			jarPath := declaredVersionJar(root.locate(root.path, descriptor), descriptor.Version)
+			if jarPath == "" {
+				continue
+			}
+			artifact, err := readJarArtifact(root.path, jarPath)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("unable to index jar %s: %v", jarLabel(root, jarPath), err))
+				continue
+			}
+			artifact.Dependency = dependency
+			artifact.Jar = jarLabel(root, jarPath)
+			index.artifacts[dependency] = artifact
+			break
+		}
+	}
+	return index, warnings
+}
+
+func (idx jarIndex) artifact(dependency string) (jarArtifact, bool) {
+	artifact, ok := idx.artifacts[normalizeDependencyID(dependency)]
+	return artifact, ok
+}
+
+// addPackagePrefixes registers each indexed package as an exact lookup prefix.
+// Packages are longer than the groupId prefixes they replace, so
+// resolveDependency prefers them; a package split across artifacts resolves to
+// the first artifact by name.
+func (idx jarIndex) addPackagePrefixes(prefixes map[string]string) {
+	for _, dependency := range idx.dependencies() {
+		for _, pkg := range idx.artifacts[dependency].Packages {
+			if owner, ok := prefixes[pkg]; ok && owner != dependency {
+				if _, indexed := idx.artifacts[owner]; indexed {
+					continue
+				}
+			}
+			prefixes[pkg] = dependency
+		}
+	}
+}
+
+func (idx jarIndex) dependencies() []string {
+	names := make([]string, 0, len(idx.artifacts))
+	for name := range idx.artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cachedJar is one version of a dependency's main jar in an artifact cache.
+type cachedJar struct {
+	path    string
+	version string
+}
+
+func mavenRepositoryJars(root string, descriptor dependencyDescriptor) []cachedJar {
+	groupPath := filepath.Join(strings.Split(descriptor.Group, ".")...)
+	matches, _ := filepath.Glob(filepath.Join(root, groupPath, descriptor.Artifact, "*", descriptor.Artifact+"-*.jar"))
+	return mainArtifactJars(matches, descriptor.Artifact, 1)
+}
+
+func gradleCacheJars(root string, descriptor dependencyDescriptor) []cachedJar {
+	matches, _ := filepath.Glob(filepath.Join(root, descriptor.Group, descriptor.Artifact, "*", "*", descriptor.Artifact+"-*.jar"))
+	return mainArtifactJars(matches, descriptor.Artifact, 2)
+}
+
+// mainArtifactJars keeps the main jar of each version directory, skipping
+// sources, javadoc, and other classifier jars. depth is how many directories
+// separate a jar from its version directory.
+func mainArtifactJars(matches []string, artifact string, depth int) []cachedJar {
+	jars := make([]cachedJar, 0, len(matches))
+	for _, match := range matches {
+		versionDir := match
+		for i := 0; i < depth; i++ {
+			versionDir = filepath.Dir(versionDir)
+		}
+		version := filepath.Base(versionDir)
+		if filepath.Base(match) == artifact+"-"+version+".jar" {
+			jars = append(jars, cachedJar{path: match, version: version})
+		}
+	}
+	return jars
+}
+
+func declaredVersionJar(jars []cachedJar, version string) string {
+	for _, jar := range jars {
+		if jar.version == version {
+			return jar.path
+		}
+	}
+	return ""
+}
+
+func jarLabel(root jarCacheRoot, path string) string {
+	rel, err := filepath.Rel(root.path, path)
+	if err != nil {
+		rel = path
+	}
+	return root.label + ":" + filepath.ToSlash(rel)
+}
+
+// readJarArtifact lists the packages of a jar's top-level classes and counts
+// the public ones. Inner classes, module/package descriptors, and
+// multi-release overlays are not part of the export surface. Service and
+// Spring auto-configuration descriptors are recorded alongside.
+func readJarArtifact(root, path string) (jarArtifact, error) {
+	content, err := safeio.ReadFileUnderLimit(root, path, maxIndexedJarBytes)
+	if err != nil {
+		return jarArtifact{}, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return jarArtifact{}, err
+	}
+	packages := make(map[string]struct{})
+	artifact := jarArtifact{}
+	classes := 0
+	for _, file := range reader.File {
+		if isJarServiceDescriptor(file.Name) {
+			artifact.Services = append(artifact.Services, file.Name)
+			continue
+		}
+		pkg, ok := jarClassPackage(file.Name)
+		if !ok {
+			continue
+		}
+		classes++
+		if classes > maxIndexedJarClasses {
+			return jarArtifact{}, fmt.Errorf("more than %d classes", maxIndexedJarClasses)
+		}
+		public, err := jarClassIsPublic(file)
+		if err != nil {
+			return jarArtifact{}, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		if !public {
+			continue
+		}
+		artifact.PublicClasses++
+		packages[pkg] = struct{}{}
+	}
+	artifact.Packages = make([]string, 0, len(packages))
+	for pkg := range packages {
+		artifact.Packages = append(artifact.Packages, pkg)
+	}
+	sort.Strings(artifact.Packages)
+	sort.Strings(artifact.Services)
+	return artifact, nil
+}
+
+func isJarServiceDescriptor(name string) bool {
+	switch {
+	case strings.HasSuffix(name, "/"):
+		return false
+	case strings.HasPrefix(name, "META-INF/services/"):
+		return !strings.Contains(strings.TrimPrefix(name, "META-INF/services/"), "/")
+	case name == "META-INF/spring.factories":
+		return true
+	default:
+		return strings.HasPrefix(name, "META-INF/spring/") && strings.HasSuffix(name, ".imports")
+	}
+}
+
+func jarClassPackage(name string) (string, bool) {
+	if !strings.HasSuffix(name, ".class") || strings.HasPrefix(name, "META-INF/") {
+		return "", false
+	}
+	dir, base := "", name
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		dir, base = name[:slash], name[slash+1:]
+	}
+	if dir == "" || strings.Contains(base, "$") || base == "package-info.class" || base == "module-info.class" {
+		return "", false
+	}
+	return strings.ReplaceAll(dir, "/", "."), true
+}
+
+func jarClassIsPublic(file *zip.File) (bool, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	content, err := io.ReadAll(io.LimitReader(reader, maxIndexedClassBytes))
+	if err != nil {
+		return false, err
+	}
+	flags, err := classAccessFlags(content)
+	if err != nil {
+		return false, err
+	}
+	return flags&javaClassAccessPublic != 0, nil
+}
+
+// classAccessFlags skips a class file's constant pool to reach the class
+// access_flags, per JVMS §4.1.
+func classAccessFlags(content []byte) (uint16, error) {
+	if len(content) < 10 || binary.BigEndian.Uint32(content) != javaClassMagic {
+		return 0, errInvalidClassFile
+	}
+	count := int(binary.BigEndian.Uint16(content[8:10]))
+	offset := 10
+	for i := 1; i < count; i++ {
+		if offset >= len(content) {
+			return 0, errInvalidClassFile
+		}
+		size, wide, ok := constantPoolEntrySize(content, offset)
+		if !ok {
+			return 0, errInvalidClassFile
+		}
+		offset += size
+		if wide {
+			i++
+		}
+	}
+	if offset+2 > len(content) {
+		return 0, errInvalidClassFile
+	}
+	return binary.BigEndian.Uint16(content[offset : offset+2]), nil
+}
+
+func constantPoolEntrySize(content []byte, offset int) (int, bool, bool) {
+	switch content[offset] {
+	case 1: // Utf8
+		if offset+3 > len(content) {
+			return 0, false, false
+		}
+		return 3 + int(binary.BigEndian.Uint16(content[offset+1:offset+3])), false, true
+	case 7, 8, 16, 19, 20: // Class, String, MethodType, Module, Package
+		return 3, false, true
+	case 15: // MethodHandle
+		return 4, false, true
+	case 3, 4, 9, 10, 11, 12, 17, 18: // Integer, Float, refs, NameAndType, Dynamic, InvokeDynamic
+		return 5, false, true
+	case 5, 6: // Long, Double take two pool slots
+		return 9, true, true
+	default:
+		return 0, false, false
+	}
+}
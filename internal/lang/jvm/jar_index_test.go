@@ -0,0 +1,264 @@
+package jvm
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+// testClassFile builds a minimal class file whose constant pool exercises the
+// variable-width and two-slot entries the access-flag reader has to skip.
+func testClassFile(public bool) []byte {
+	var buf bytes.Buffer
+	write := func(value any) {
+		_ = binary.Write(&buf, binary.BigEndian, value)
+	}
+	write(uint32(javaClassMagic))
+	write(uint16(0))
+	write(uint16(61))
+	write(uint16(5)) // Utf8, Long (two slots), Class
+	buf.WriteByte(1)
+	write(uint16(3))
+	buf.WriteString("App")
+	buf.WriteByte(5)
+	write(uint64(42))
+	buf.WriteByte(7)
+	write(uint16(1))
+	flags := uint16(0x0020)
+	if public {
+		flags |= javaClassAccessPublic
+	}
+	write(flags)
+	return buf.Bytes()
+}
+
+func writeTestJar(t *testing.T, path string, classes map[string]bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, public := range classes {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("create jar entry: %v", err)
+		}
+		if _, err := entry.Write(testClassFile(public)); err != nil {
+			t.Fatalf("write jar entry: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close jar: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir jar dir: %v", err)
+	}
+	testutil.MustWriteFile(t, path, buf.String())
+}
+
+func isolateJarCaches(t *testing.T) (string, string) {
+	t.Helper()
+	home := t.TempDir()
+	gradleHome := filepath.Join(home, "gradle-home")
+	t.Setenv("HOME", home)
+	t.Setenv("GRADLE_USER_HOME", gradleHome)
+	return filepath.Join(home, ".m2", "repository"), filepath.Join(gradleHome, "caches", "modules-2", "files-2.1")
+}
+
+func mustJarIndexFeatureSet(t *testing.T, enabled bool) featureflags.Set {
+	t.Helper()
+	opts := featureflags.ResolveOptions{Channel: featureflags.ChannelDev}
+	if enabled {
+		opts.Enable = []string{jvmJarIndexPreviewFeature}
+	}
+	resolved, err := featureflags.DefaultRegistry().Resolve(opts)
+	if err != nil {
+		t.Fatalf("resolve feature set: %v", err)
+	}
+	return resolved
+}
+
+func TestReadJarArtifactCountsPublicTopLevelClasses(t *testing.T) {
+	root := t.TempDir()
+	jar := filepath.Join(root, "lib.jar")
+	writeTestJar(t, jar, map[string]bool{
+		"org/apache/commons/lang3/StringUtils.class":      true,
+		"org/apache/commons/lang3/StringUtils$1.class":    true,
+		"org/apache/commons/lang3/tuple/Pair.class":       true,
+		"org/apache/commons/lang3/internal/Helper.class":  false,
+		"org/apache/commons/lang3/package-info.class":     true,
+		"META-INF/versions/9/module-info.class":           true,
+		"META-INF/versions/11/org/apache/Override.class":  true,
+		"org/apache/commons/lang3/resources/strings.prop": true,
+	})
+
+	artifact, err := readJarArtifact(root, jar)
+	if err != nil {
+		t.Fatalf("read jar: %v", err)
+	}
+	if artifact.PublicClasses != 2 {
+		t.Fatalf("expected two public top-level classes, got %d", artifact.PublicClasses)
+	}
+	if strings.Join(artifact.Packages, ",") != "org.apache.commons.lang3,org.apache.commons.lang3.tuple" {
+		t.Fatalf("unexpected packages: %#v", artifact.Packages)
+	}
+}
+
+func TestReadJarArtifactRejectsInvalidContent(t *testing.T) {
+	root := t.TempDir()
+	notZip := filepath.Join(root, "broken.jar")
+	testutil.MustWriteFile(t, notZip, "not a jar")
+	if _, err := readJarArtifact(root, notZip); err == nil {
+		t.Fatalf("expected invalid jar error")
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, _ := writer.Create("com/example/Bad.class")
+	_, _ = entry.Write([]byte{0xCA, 0xFE, 0xBA, 0xBE, 0, 0, 0, 61, 0, 2, 99})
+	_ = writer.Close()
+	badClass := filepath.Join(root, "bad-class.jar")
+	testutil.MustWriteFile(t, badClass, buf.String())
+	if _, err := readJarArtifact(root, badClass); err == nil || !strings.Contains(err.Error(), "com/example/Bad.class") {
+		t.Fatalf("expected invalid class file error, got %v", err)
+	}
+}
+
+func TestClassAccessFlagsRejectsTruncatedInput(t *testing.T) {
+	valid := testClassFile(true)
+	for _, content := range [][]byte{nil, []byte("not a class"), valid[:12], valid[:len(valid)-1]} {
+		if _, err := classAccessFlags(content); err == nil {
+			t.Fatalf("expected truncated class file to fail: %v", content)
+		}
+	}
+	if flags, err := classAccessFlags(valid); err != nil || flags&javaClassAccessPublic == 0 {
+		t.Fatalf("expected public flags, got %x (%v)", flags, err)
+	}
+}
+
+func TestIndexDeclaredJarsPicksDeclaredVersionMainJar(t *testing.T) {
+	m2, gradle := isolateJarCaches(t)
+	base := filepath.Join(m2, "org", "apache", "commons", "commons-lang3")
+	writeTestJar(t, filepath.Join(base, "3.9", "commons-lang3-3.9.jar"), map[string]bool{"org/apache/commons/lang3/Old.class": true})
+	writeTestJar(t, filepath.Join(base, "3.12.0", "commons-lang3-3.12.0.jar"), map[string]bool{"org/apache/commons/lang3/StringUtils.class": true})
+	writeTestJar(t, filepath.Join(base, "3.12.0", "commons-lang3-3.12.0-sources.jar"), map[string]bool{"org/apache/commons/lang3/Sources.class": true})
+	writeTestJar(t, filepath.Join(gradle, "com.google.guava", "guava", "33.0.0-jre", "abc123", "guava-33.0.0-jre.jar"), map[string]bool{"com/google/common/collect/ImmutableList.class": true})
+	testutil.MustWriteFile(t, filepath.Join(gradle, "com.broken", "broken", "1.0", "def456", "broken-1.0.jar"), "not a jar")
+
+	index, warnings := indexDeclaredJars([]dependencyDescriptor{
+		{Name: "commons-lang3", Group: "org.apache.commons", Artifact: "commons-lang3", Version: "3.12.0"},
+		{Name: "guava", Group: "com.google.guava", Artifact: "guava", Version: "33.0.0-jre"},
+		{Name: "broken", Group: "com.broken", Artifact: "broken", Version: "1.0"},
+		{Name: "missing", Group: "com.example", Artifact: "missing", Version: "1.0"},
+		{Name: "unpinned", Group: "org.apache.commons", Artifact: "commons-lang3"},
+		{Name: "mismatched", Group: "com.google.guava", Artifact: "guava", Version: "32.1.0-jre"},
+	}, defaultJarCacheRoots())
+
+	lang, ok := index.artifact("commons-lang3")
+	if !ok || lang.Jar != "M2:org/apache/commons/commons-lang3/3.12.0/commons-lang3-3.12.0.jar" || strings.Join(lang.Packages, ",") != "org.apache.commons.lang3" {
+		t.Fatalf("expected declared main commons-lang3 jar, got %#v", lang)
+	}
+	guava, ok := index.artifact("guava")
+	if !ok || guava.Jar != "GRADLE_USER_HOME:com.google.guava/guava/33.0.0-jre/abc123/guava-33.0.0-jre.jar" {
+		t.Fatalf("expected guava from Gradle cache, got %#v", guava)
+	}
+	for _, name := range []string{"missing", "unpinned", "mismatched"} {
+		if _, ok := index.artifact(name); ok {
+			t.Fatalf("did not expect %s to be indexed without an exact cached version", name)
+		}
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "unable to index jar GRADLE_USER_HOME:com.broken/broken/1.0/def456/broken-1.0.jar") {
+		t.Fatalf("expected broken jar warning, got %#v", warnings)
+	}
+}
+
+func TestAdapterAnalyseResolvesImportsThroughJarIndex(t *testing.T) {
+	m2, _ := isolateJarCaches(t)
+	writeTestJar(t, filepath.Join(m2, "com", "google", "guava", "guava", "33.0.0-jre", "guava-33.0.0-jre.jar"), map[string]bool{
+		"com/google/common/collect/ImmutableList.class": true,
+		"com/google/common/collect/ImmutableMap.class":  true,
+		"com/google/common/base/Strings.class":          true,
+		"com/google/common/base/Internal.class":         false,
+	})
+	writeTestJar(t, filepath.Join(m2, "com", "google", "code", "gson", "gson", "2.10.1", "gson-2.10.1.jar"), map[string]bool{
+		"com/google/gson/Gson.class": true,
+	})
+	writeTestJar(t, filepath.Join(m2, "com", "fasterxml", "jackson", "core", "jackson-databind", "2.17.0", "jackson-databind-2.17.0.jar"), map[string]bool{
+		"com/fasterxml/jackson/databind/ObjectMapper.class": true,
+		"com/fasterxml/jackson/databind/JsonNode.class":     true,
+	})
+
+	repo := t.TempDir()
+	writeJVMPomFile(t, repo, `<project><dependencies>
+  <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>33.0.0-jre</version></dependency>
+  <dependency><groupId>com.google.code.gson</groupId><artifactId>gson</artifactId><version>2.10.1</version></dependency>
+  <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>2.17.0</version></dependency>
+</dependencies></project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "App.java"), `
+import com.google.common.collect.ImmutableList;
+import com.google.gson.Gson;
+
+class App {
+  Object run() {
+    return ImmutableList.of(new Gson());
+  }
+}
+`)
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{
+		RepoPath: repo,
+		TopN:     10,
+		Features: mustJarIndexFeatureSet(t, true),
+	})
+	if err != nil {
+		t.Fatalf(errAnalyseFmt, err)
+	}
+	deps := map[string]report.DependencyReport{}
+	for _, dep := range reportData.Dependencies {
+		deps[dep.Name] = dep
+	}
+	guava, gson, databind := deps["guava"], deps["gson"], deps["jackson-databind"]
+	if guava.TotalExportsCount != 3 || guava.UsedExportsCount != 1 || len(guava.UsedImports) != 1 {
+		t.Fatalf("expected guava resolved by jar packages with three public classes, got %#v", guava)
+	}
+	if gson.TotalExportsCount != 1 || gson.UsedPercent != 100 {
+		t.Fatalf("expected gson resolved by jar packages, got %#v", gson)
+	}
+	if databind.TotalExportsCount != 2 || !hasRiskCue(databind, "unreferenced-artifact") || len(databind.Recommendations) == 0 || databind.Recommendations[0].Code != "remove-unused-dependency" {
+		t.Fatalf("expected unreferenced jackson-databind to be flagged, got %#v", databind)
+	}
+}
+
+func TestAdapterAnalyseSkipsJarIndexWhenPreviewDisabled(t *testing.T) {
+	m2, _ := isolateJarCaches(t)
+	writeTestJar(t, filepath.Join(m2, "com", "fasterxml", "jackson", "core", "jackson-databind", "2.17.0", "jackson-databind-2.17.0.jar"), map[string]bool{
+		"com/fasterxml/jackson/databind/ObjectMapper.class": true,
+	})
+	repo := t.TempDir()
+	writeJVMPomFile(t, repo, `<project><dependencies>
+  <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId></dependency>
+</dependencies></project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "App.java"), "class App {}\n")
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{
+		RepoPath: repo,
+		TopN:     10,
+		Features: mustJarIndexFeatureSet(t, false),
+	})
+	if err != nil {
+		t.Fatalf(errAnalyseFmt, err)
+	}
+	for _, dep := range reportData.Dependencies {
+		if dep.Name == "jackson-databind" {
+			t.Fatalf("did not expect unreferenced artifact without the preview flag, got %#v", dep)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package jvm
 
 import (
+	"fmt"
+
 	"github.com/ben-ranford/lopper/internal/lang/shared"
 	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/report"
@@ -12,7 +14,7 @@ func buildRequestedJVMDependencies(req language.Request, scan scanResult) ([]rep
 
 func buildTopJVMDependencies(topN int, scan scanResult, weights report.RemovalCandidateWeights) ([]report.DependencyReport, []string) {
 	fileUsages := shared.MapFileUsages(scan.Files, func(file fileScan) []shared.ImportRecord { return file.Imports }, func(file fileScan) map[string]int { return file.Usage })
-	dependencies := shared.ListDependencies(fileUsages, normalizeDependencyID)
+	dependencies := appendUnreferencedArtifacts(shared.ListDependencies(fileUsages, normalizeDependencyID), scan.Artifacts)
 	reportBuilder := func(dependency string) (report.DependencyReport, []string) {
 		return buildDependencyReport(dependency, scan)
 	}
@@ -38,6 +40,9 @@ func buildDependencyReport(dependency string, scan scanResult) (report.Dependenc
 		UsedImports:          stats.UsedImports,
 		UnusedImports:        stats.UnusedImports,
 	}
+	if artifact, ok := scan.Artifacts.artifact(dependency); ok && artifact.PublicClasses > 0 {
+		applyJarExportSurface(&dep, artifact, stats.HasImports)
+	}
 	if stats.WildcardImports > 0 {
 		dep.RiskCues = append(dep.RiskCues, report.RiskCue{
 			Code:     "wildcard-import",
@@ -45,13 +50,49 @@ func buildDependencyReport(dependency string, scan scanResult) (report.Dependenc
 			Message:  "found wildcard imports for this dependency",
 		})
 	}
+	dep.RiskCues = append(dep.RiskCues, scan.RuntimeUsage.RiskCues(dependency)...)
 	dep.Recommendations = buildRecommendations(dep)
 	return dep, warnings
 }
 
+// appendUnreferencedArtifacts adds indexed artifacts that no source file
+// references, so declared-but-unused jars still reach top-N ranking.
+func appendUnreferencedArtifacts(dependencies []string, artifacts jarIndex) []string {
+	seen := make(map[string]struct{}, len(dependencies))
+	for _, dependency := range dependencies {
+		seen[dependency] = struct{}{}
+	}
+	for _, dependency := range artifacts.dependencies() {
+		if _, ok := seen[dependency]; !ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies
+}
+
+// applyJarExportSurface measures usage against the public classes of the
+// dependency's jar instead of the symbols its imports happen to name.
+func applyJarExportSurface(dep *report.DependencyReport, artifact jarArtifact, referenced bool) {
+	dep.TotalExportsCount = artifact.PublicClasses
+	dep.UsedExportsCount = min(dep.UsedExportsCount, artifact.PublicClasses)
+	dep.UsedPercent = float64(dep.UsedExportsCount) / float64(artifact.PublicClasses) * 100
+	if referenced {
+		return
+	}
+	dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+		Code:     "unreferenced-artifact",
+		Severity: "medium",
+		Message:  fmt.Sprintf("none of the %d public classes in %s are referenced", artifact.PublicClasses, artifact.Jar),
+	})
+}
+
 func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 	recommendations := make([]report.Recommendation, 0, 2)
-	if len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0 {
+	unused := len(dep.UsedImports) == 0 && (len(dep.UnusedImports) > 0 || hasRiskCue(dep, "unreferenced-artifact"))
+	switch {
+	case unused && shared.HasJVMRuntimeUsageCue(dep.RiskCues):
+		recommendations = append(recommendations, shared.JVMRuntimeUsageRecommendation())
+	case unused:
 		recommendations = append(recommendations, report.Recommendation{
 			Code:      "remove-unused-dependency",
 			Priority:  "high",
@@ -69,3 +110,12 @@ func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 	}
 	return recommendations
 }
+
+func hasRiskCue(dep report.DependencyReport, code string) bool {
+	for _, cue := range dep.RiskCues {
+		if cue.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -8,12 +8,14 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ben-ranford/lopper/internal/lang/shared"
 	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/language/languagetest"
 	"github.com/ben-ranford/lopper/internal/safeio"
 	"github.com/ben-ranford/lopper/internal/testutil"
 )
@@ -122,7 +124,7 @@ func TestScanRepoWithinRootPropagatesDirectoryCloseError(t *testing.T) {
 		},
 	}
 
-	_, err = scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	_, err = scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if !errors.Is(err, closeErr) {
 		t.Fatalf("expected source directory close error, got %v", err)
 	}
@@ -133,7 +135,7 @@ func TestScanRepoWithinRootPropagatesTraversalLimitCloseError(t *testing.T) {
 	closeErr := errors.New("close overflowing source directory")
 	root := newJVMRootedTraversalLimitRoot(t, repo, closeErr)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if !errors.Is(err, closeErr) || !strings.Contains(err.Error(), "rooted walk traversal limit exceeded") {
 		t.Fatalf("expected joined source traversal-limit and close error, got %v", err)
 	}
@@ -146,7 +148,7 @@ func TestScanRepoWithinRootDowngradesPureTraversalLimitToWarning(t *testing.T) {
 	repo := t.TempDir()
 	root := newJVMRootedTraversalLimitRoot(t, repo, nil)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if err != nil {
 		t.Fatalf("expected pure rooted source traversal limit to downgrade to warnings, got %v", err)
 	}
@@ -181,7 +183,7 @@ func TestScanRepoWithinRootPropagatesFileLimitCloseError(t *testing.T) {
 		},
 	})
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if !errors.Is(err, safeio.ErrFileTooLarge) || !errors.Is(err, closeErr) {
 		t.Fatalf("expected joined source file-limit and close error, got %v", err)
 	}
@@ -308,7 +310,7 @@ func TestScanRepoWithinRootSuccessAndEmptyRepoGuards(t *testing.T) {
 	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "com", "example", "Main.java"), "package com.example;\nimport org.junit.jupiter.api.Test;\nclass Main {}\n")
 	root := openJVMTestRoot(t, repo)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{"org.junit.jupiter": "junit-jupiter-api"}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{"org.junit.jupiter": "junit-jupiter-api"}, map[string]string{}, false)
 	if err != nil {
 		t.Fatalf("scan rooted repo: %v", err)
 	}
@@ -316,11 +318,39 @@ func TestScanRepoWithinRootSuccessAndEmptyRepoGuards(t *testing.T) {
 		t.Fatalf("expected rooted scan result, got %#v", result)
 	}
 
-	if _, err := scanRepoWithinRoot(context.Background(), "", root, map[string]string{}, map[string]string{}); !errors.Is(err, fs.ErrInvalid) {
+	if _, err := scanRepoWithinRoot(context.Background(), "", root, map[string]string{}, map[string]string{}, false); !errors.Is(err, fs.ErrInvalid) {
 		t.Fatalf("expected invalid empty repo path, got %v", err)
 	}
 }
 
+func TestScanRepoWithinRootWithFileCacheKeysScansByDependencyPrefixes(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "com", "example", "Main.java"), "package com.example;\nimport org.junit.jupiter.api.Test;\nclass Main {}\n")
+	root := openJVMTestRoot(t, repo)
+	cache := languagetest.NewMemoryFileCache()
+
+	scan := func(depPrefixes map[string]string) scanResult {
+		t.Helper()
+		result, err := scanRepoWithinRootWithFileCache(context.Background(), repo, root, depPrefixes, map[string]string{}, false, cache)
+		if err != nil {
+			t.Fatalf("scan rooted repo: %v", err)
+		}
+		return result
+	}
+
+	junit := map[string]string{"org.junit.jupiter": "junit-jupiter-api"}
+	first := scan(junit)
+	second := scan(junit)
+	if cache.Writes != 1 || cache.Hits != 1 || !reflect.DeepEqual(first.Files, second.Files) {
+		t.Fatalf("expected the second scan to reuse the cached file, got writes=%d hits=%d files=%#v", cache.Writes, cache.Hits, second.Files)
+	}
+
+	third := scan(map[string]string{"org.junit": "junit-bom"})
+	if cache.Writes != 2 || third.Files[0].Imports[0].Dependency != "junit-bom" {
+		t.Fatalf("expected changed dependency prefixes to rescan, got writes=%d files=%#v", cache.Writes, third.Files)
+	}
+}
+
 func TestJVMAnalyseIgnoresUnrelatedFileFloodForCandidateBudgets(t *testing.T) {
 	repo := t.TempDir()
 	testutil.MustWriteFile(t, filepath.Join(repo, buildGradleName), `dependencies { implementation("org.junit.jupiter:junit-jupiter-api:5.10.0") }`)
@@ -358,7 +388,7 @@ func TestScanRepoWithinRootIgnoresSourceSymlinkDecoyFloodForCandidateBudget(t *t
 	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "com", "example", "Main.java"), "package com.example;\nclass Main {}\n")
 	root := openJVMTestRoot(t, repo)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if err != nil {
 		t.Fatalf("scan rooted repo with source symlink flood: %v", err)
 	}
@@ -435,7 +465,7 @@ func TestScanRepoWithinRootReportsSkippedAndEmptyWarnings(t *testing.T) {
 	}
 	root := openJVMTestRoot(t, repo)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{})
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, map[string]string{}, map[string]string{}, false)
 	if err != nil {
 		t.Fatalf("scan rooted repo warnings: %v", err)
 	}
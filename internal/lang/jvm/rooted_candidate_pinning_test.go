@@ -28,7 +28,7 @@ func TestRootedAnalysisCandidateReadsHaveBoundedOperationCounts(t *testing.T) {
 		})
 		counts, root := openCountingJVMRoot(t, repo)
 
-		result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil)
+		result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil, false)
 		if err != nil {
 			t.Fatalf("scan deep-wide rooted sources: %v", err)
 		}
@@ -98,7 +98,7 @@ func testRootedSourceCandidateReadSurvivesSwap(t *testing.T) {
 	writeJVMRootedCandidate(t, filepath.Join(repo, parentRel, leaf), "package original;\nclass Main {}\n")
 	swap, root := openSwappingJVMRoot(t, repo, parentRel, leaf, replacement, 1)
 
-	result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil)
+	result, err := scanRepoWithinRoot(context.Background(), repo, root, nil, nil, false)
 	if err != nil {
 		t.Fatalf("scan source through swapped namespace: %v", err)
 	}
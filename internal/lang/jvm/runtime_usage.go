@@ -0,0 +1,70 @@
+package jvm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+)
+
+const javaAnnotationProcessorService = "META-INF/services/javax.annotation.processing.Processor"
+
+// recordRuntimeReferences attributes class names a file hands to the JVM by
+// name. Unlike imports, references that match no declared dependency are
+// dropped rather than attributed by package fallback.
+func recordRuntimeReferences(result *scanResult, relativePath, filePackage string, content []byte, depPrefixes map[string]string, depAliases map[string]string) {
+	if result == nil || result.RuntimeUsage == nil {
+		return
+	}
+	for _, reference := range shared.ScanJVMRuntimeReferences(relativePath, content) {
+		dependency := runtimeReferenceDependency(reference, filePackage, depPrefixes, depAliases)
+		if dependency == "" {
+			continue
+		}
+		result.addRuntimeUsage(dependency, reference.Code, fmt.Sprintf("%s:%d %s", relativePath, reference.Line, reference.ClassName))
+	}
+}
+
+func runtimeReferenceDependency(reference shared.JVMRuntimeReference, filePackage string, depPrefixes map[string]string, depAliases map[string]string) string {
+	if !shouldIgnoreImport(reference.ClassName, filePackage) {
+		if dependency := resolveDependency(reference.ClassName, depPrefixes, depAliases); dependency != "" {
+			return dependency
+		}
+	}
+	for _, artifact := range reference.Artifacts {
+		if dependency, ok := depAliases[strings.ReplaceAll(artifact, "-", ".")]; ok {
+			return dependency
+		}
+	}
+	return ""
+}
+
+func (s *scanResult) addRuntimeUsage(dependency, code, evidence string) {
+	s.RuntimeUsage.Add(normalizeDependencyID(dependency), code, evidence)
+}
+
+// recordDeclaredRuntimeUsage marks dependencies declared as annotation
+// processors and indexed jars that register service providers.
+func recordDeclaredRuntimeUsage(result *scanResult, descriptors []dependencyDescriptor) {
+	if result.RuntimeUsage == nil {
+		return
+	}
+	for _, descriptor := range descriptors {
+		switch {
+		case descriptor.AnnotationProcessor:
+			result.addRuntimeUsage(descriptor.Name, shared.JVMAnnotationProcessorCue, "declared as an annotation processor")
+		case shared.IsKnownJVMAnnotationProcessorArtifact(descriptor.Group, descriptor.Artifact):
+			result.addRuntimeUsage(descriptor.Name, shared.JVMAnnotationProcessorCue, "known annotation processor")
+		}
+	}
+	for _, dependency := range result.Artifacts.dependencies() {
+		artifact := result.Artifacts.artifacts[dependency]
+		for _, service := range artifact.Services {
+			code := shared.JVMServiceProviderCue
+			if service == javaAnnotationProcessorService {
+				code = shared.JVMAnnotationProcessorCue
+			}
+			result.addRuntimeUsage(dependency, code, service+" in "+artifact.Jar)
+		}
+	}
+}
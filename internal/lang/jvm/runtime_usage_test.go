@@ -0,0 +1,146 @@
+package jvm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestAdapterAnalyseDetectsRuntimeUsageChannels(t *testing.T) {
+	m2, _ := isolateJarCaches(t)
+	writeTestJar(t, filepath.Join(m2, "org", "postgresql", "postgresql", "42.7.3", "postgresql-42.7.3.jar"), map[string]bool{
+		"org/postgresql/Driver.class":       true,
+		"META-INF/services/java.sql.Driver": true,
+	})
+	writeTestJar(t, filepath.Join(m2, "com", "h2database", "h2", "2.2.224", "h2-2.2.224.jar"), map[string]bool{
+		"org/h2/Driver.class": true,
+	})
+	writeTestJar(t, filepath.Join(m2, "org", "apache", "commons", "commons-lang3", "3.14.0", "commons-lang3-3.14.0.jar"), map[string]bool{
+		"org/apache/commons/lang3/StringUtils.class": true,
+	})
+	writeTestJar(t, filepath.Join(m2, "org", "mapstruct", "mapstruct-processor", "1.5.5.Final", "mapstruct-processor-1.5.5.Final.jar"), map[string]bool{
+		"org/mapstruct/ap/MappingProcessor.class": true,
+	})
+	writeTestJar(t, filepath.Join(m2, "com", "fasterxml", "jackson", "core", "jackson-databind", "2.17.0", "jackson-databind-2.17.0.jar"), map[string]bool{
+		"com/fasterxml/jackson/databind/ObjectMapper.class": true,
+	})
+
+	repo := t.TempDir()
+	writeJVMPomFile(t, repo, `<project><dependencies>
+  <dependency><groupId>org.postgresql</groupId><artifactId>postgresql</artifactId><version>42.7.3</version></dependency>
+  <dependency><groupId>com.h2database</groupId><artifactId>h2</artifactId><version>2.2.224</version></dependency>
+  <dependency><groupId>org.apache.commons</groupId><artifactId>commons-lang3</artifactId><version>3.14.0</version></dependency>
+  <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>2.17.0</version></dependency>
+</dependencies>
+<build><plugins><plugin>
+  <artifactId>maven-compiler-plugin</artifactId>
+  <configuration><annotationProcessorPaths>
+    <path><groupId>org.mapstruct</groupId><artifactId>mapstruct-processor</artifactId><version>1.5.5.Final</version></path>
+  </annotationProcessorPaths></configuration>
+</plugin></plugins></build></project>`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "java", "App.java"), `
+class App {
+  Object load() throws Exception {
+    return Class.forName("org.apache.commons.lang3.StringUtils");
+  }
+}
+`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "src", "main", "resources", "application.properties"), "spring.datasource.url=jdbc:h2:mem:app\n")
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{
+		RepoPath: repo,
+		TopN:     10,
+		Features: mustRuntimeUsageFeatureSet(t, jvmJarIndexPreviewFeature, shared.JVMRuntimeUsagePreviewFeature),
+	})
+	if err != nil {
+		t.Fatalf(errAnalyseFmt, err)
+	}
+	deps := map[string]report.DependencyReport{}
+	for _, dep := range reportData.Dependencies {
+		deps[dep.Name] = dep
+	}
+
+	for name, cue := range map[string]string{
+		"postgresql":          "service-provider",
+		"h2":                  "jdbc-driver-url",
+		"commons-lang3":       "reflective-class-reference",
+		"mapstruct-processor": "annotation-processor",
+	} {
+		dep := deps[name]
+		if !hasRiskCue(dep, cue) {
+			t.Fatalf("expected %s cue on %s, got %#v", cue, name, dep.RiskCues)
+		}
+		if len(dep.Recommendations) == 0 || dep.Recommendations[0].Code != "review-runtime-loading" {
+			t.Fatalf("expected %s to be reviewed instead of removed, got %#v", name, dep.Recommendations)
+		}
+	}
+	if databind := deps["jackson-databind"]; len(databind.Recommendations) == 0 || databind.Recommendations[0].Code != "remove-unused-dependency" {
+		t.Fatalf("expected jackson-databind without runtime usage to stay a removal candidate, got %#v", databind.Recommendations)
+	}
+
+	baseline, err := NewAdapter().Analyse(context.Background(), language.Request{
+		RepoPath: repo,
+		TopN:     10,
+		Features: mustJarIndexFeatureSet(t, true),
+	})
+	if err != nil {
+		t.Fatalf(errAnalyseFmt, err)
+	}
+	for _, dep := range baseline.Dependencies {
+		if shared.HasJVMRuntimeUsageCue(dep.RiskCues) {
+			t.Fatalf("expected no runtime usage cues without the preview flag, got %#v on %s", dep.RiskCues, dep.Name)
+		}
+	}
+}
+
+func mustRuntimeUsageFeatureSet(t *testing.T, names ...string) featureflags.Set {
+	t.Helper()
+	resolved, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{Channel: featureflags.ChannelDev, Enable: names})
+	if err != nil {
+		t.Fatalf("resolve feature set: %v", err)
+	}
+	return resolved
+}
+
+func TestParsePomDependencyContentMarksAnnotationProcessorPaths(t *testing.T) {
+	descriptors, warnings := parsePomDependencyContent("pom.xml", `<project>
+  <properties><lombok.version>1.18.32</lombok.version></properties>
+  <dependencies>
+    <dependency><groupId>org.projectlombok</groupId><artifactId>lombok</artifactId><version>${lombok.version}</version></dependency>
+  </dependencies>
+  <build><pluginManagement><plugins><plugin>
+    <configuration><annotationProcessorPaths>
+      <path><groupId>org.projectlombok</groupId><artifactId>lombok</artifactId><version>${lombok.version}</version></path>
+    </annotationProcessorPaths></configuration>
+  </plugin></plugins></pluginManagement></build>
+</project>`)
+	if len(warnings) != 0 || len(descriptors) != 1 || !descriptors[0].AnnotationProcessor {
+		t.Fatalf("expected lombok merged into one annotation processor descriptor, got %#v warnings=%#v", descriptors, warnings)
+	}
+}
+
+func TestParsePomDependencyContentCarriesDeclaredVersions(t *testing.T) {
+	descriptors, warnings := parsePomDependencyContent("pom.xml", `<project>
+  <properties><guava.version>33.0.0-jre</guava.version></properties>
+  <dependencies>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId></dependency>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>${missing.version}</version></dependency>
+  </dependencies>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>${guava.version}</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`)
+	versions := make(map[string]string)
+	for _, descriptor := range descriptors {
+		versions[descriptor.Artifact] = descriptor.Version
+	}
+	if len(warnings) != 0 || versions["guava"] != "33.0.0-jre" || versions["slf4j-api"] != "" {
+		t.Fatalf("expected managed guava version and no unresolved slf4j version, got %#v warnings=%#v", descriptors, warnings)
+	}
+}
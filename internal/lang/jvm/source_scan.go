@@ -13,6 +13,7 @@ import (
 
 	kotlinlang "github.com/ben-ranford/lopper/internal/lang/kotlin"
 	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
@@ -27,27 +28,60 @@ type fileScan struct {
 
 type scanResult struct {
 	Files             []fileScan
+	Artifacts         jarIndex
+	RuntimeUsage      shared.JVMRuntimeUsage
 	Warnings          []string
 	SkippedLargeFiles int
 	SkippedSymlinks   int
+	fileCache         language.FileCache
+	fileCacheInputs   string
+}
+
+// fileScanCacheSchema versions the cachedJVMFile layout and the import
+// parsing that produces it; bump it whenever either changes.
+const fileScanCacheSchema = "jvm-filescan-v1"
+
+type cachedJVMFile struct {
+	Package string
+	Imports []importBinding
+	Usage   map[string]int
 }
 
 func scanRepo(ctx context.Context, repoPath string, depPrefixes map[string]string, depAliases map[string]string) (scanResult, error) {
 	return scanRepoWithSourceReader(ctx, repoPath, depPrefixes, depAliases, safeio.ReadFileUnderLimit)
 }
 
-func scanRepoWithinRoot(ctx context.Context, repoPath string, root safeio.Root, depPrefixes map[string]string, depAliases map[string]string) (scanResult, error) {
+// scanRepoWithinRoot scans Java and Kotlin sources. With runtimeUsage it also
+// records class names that sources and runtime configuration files load by
+// name.
+func scanRepoWithinRoot(ctx context.Context, repoPath string, root safeio.Root, depPrefixes map[string]string, depAliases map[string]string, runtimeUsage bool) (scanResult, error) {
+	return scanRepoWithinRootWithFileCache(ctx, repoPath, root, depPrefixes, depAliases, runtimeUsage, nil)
+}
+
+// scanRepoWithinRootWithFileCache scans like scanRepoWithinRoot but reuses
+// per-file import scans held in fileCache. Entries are keyed by the
+// dependency prefixes and aliases imports resolve against, so build file
+// changes miss every file. Runtime usage cues are always recomputed.
+func scanRepoWithinRootWithFileCache(ctx context.Context, repoPath string, root safeio.Root, depPrefixes map[string]string, depAliases map[string]string, runtimeUsage bool, fileCache language.FileCache) (scanResult, error) {
 	result := scanResult{}
 	if repoPath == "" {
 		return result, fs.ErrInvalid
 	}
+	if fileCache != nil {
+		if inputs, ok := language.FileCacheInputs([]map[string]string{depPrefixes, depAliases}); ok {
+			result.fileCache, result.fileCacheInputs = fileCache, inputs
+		}
+	}
+	if runtimeUsage {
+		result.RuntimeUsage = make(shared.JVMRuntimeUsage)
+	}
 
 	budget := shared.RootedWalkBudget{
 		MaxTraversalEntries: maxJVMSourceTraversalEntries,
 		MaxFiles:            maxJVMSourceFiles,
 		MaxWorkItems:        maxJVMSourceWorkItems,
 		CountCandidate: func(path string, _ fs.DirEntry) bool {
-			return isSourceFile(path)
+			return isSourceFile(path) || (runtimeUsage && shared.IsJVMRuntimeConfigPath(path))
 		},
 	}
 	err := shared.WalkRepoFilesWithinRootPinned(ctx, repoPath, root, budget, shouldSkipDir, func(file shared.RootedWalkFile) error {
@@ -124,7 +158,8 @@ func scanJVMSourceFile(repoPath string, path string, depPrefixes map[string]stri
 }
 
 func scanJVMSourceFileWithReader(repoPath string, path string, entry fs.DirEntry, depPrefixes map[string]string, depAliases map[string]string, result *scanResult, readSource jvmSourceReader) error {
-	if !isSourceFile(path) {
+	runtimeConfig := result != nil && result.RuntimeUsage != nil && shared.IsJVMRuntimeConfigPath(path)
+	if !isSourceFile(path) && !runtimeConfig {
 		return nil
 	}
 	var (
@@ -156,18 +191,35 @@ func scanJVMSourceFileWithReader(repoPath string, path string, entry fs.DirEntry
 	if err != nil {
 		relativePath = path
 	}
+	if runtimeConfig {
+		recordRuntimeReferences(result, relativePath, "", content, depPrefixes, depAliases)
+		return nil
+	}
 
-	filePackage := parsePackage(content)
-	imports := parseImports(content, relativePath, filePackage, depPrefixes, depAliases)
+	scanned := scanJVMFileContent(result, content, relativePath, depPrefixes, depAliases)
+	recordRuntimeReferences(result, relativePath, scanned.Package, content, depPrefixes, depAliases)
 	result.Files = append(result.Files, fileScan{
 		Path:    relativePath,
-		Package: filePackage,
-		Imports: imports,
-		Usage:   countUsage(content, imports),
+		Package: scanned.Package,
+		Imports: scanned.Imports,
+		Usage:   scanned.Usage,
 	})
 	return nil
 }
 
+func scanJVMFileContent(result *scanResult, content []byte, relativePath string, depPrefixes map[string]string, depAliases map[string]string) cachedJVMFile {
+	key := language.FileCacheKey{Adapter: "jvm", Schema: fileScanCacheSchema, Path: filepath.ToSlash(relativePath), Content: content, Inputs: result.fileCacheInputs}
+	var scanned cachedJVMFile
+	if language.LoadCachedFile(result.fileCache, key, &scanned) {
+		return scanned
+	}
+	filePackage := parsePackage(content)
+	imports := parseImports(content, relativePath, filePackage, depPrefixes, depAliases)
+	scanned = cachedJVMFile{Package: filePackage, Imports: imports, Usage: countUsage(content, imports)}
+	language.StoreCachedFile(result.fileCache, key, scanned)
+	return scanned
+}
+
 func classifySkippableJVMSourceReadError(repoPath, path string, entry fs.DirEntry, err error) (string, bool) {
 	if err == nil || errors.Is(err, context.Canceled) {
 		return "", false
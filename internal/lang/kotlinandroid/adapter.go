@@ -54,11 +54,12 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 	descriptors, lookups, declarationWarnings := collectDeclaredDependencies(repoPath)
 	result.Warnings = append(result.Warnings, declarationWarnings...)
 
-	scanResult, err := scanRepo(ctx, repoPath, lookups)
+	scanResult, err := scanRepo(ctx, repoPath, lookups, req.Features.Enabled(shared.JVMRuntimeUsagePreviewFeature))
 	if err != nil {
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, scanResult.Warnings...)
+	recordDeclaredRuntimeUsage(&scanResult, descriptors)
 
 	dependencies, warnings := buildRequestedKotlinAndroidDependencies(req, scanResult)
 	result.Dependencies = dependencies
@@ -12,12 +12,13 @@ import (
 const gradleReadWarningFormat = "unable to read %s: %v"
 
 type dependencyDescriptor struct {
-	Name         string
-	Group        string
-	Artifact     string
-	Version      string
-	FromManifest bool
-	FromLockfile bool
+	Name                string
+	Group               string
+	Artifact            string
+	Version             string
+	FromManifest        bool
+	FromLockfile        bool
+	AnnotationProcessor bool
 }
 
 type dependencyLookups struct {
@@ -137,6 +138,7 @@ func dedupeDescriptors(descriptors []dependencyDescriptor) []dependencyDescripto
 		if current.Version == "" && descriptor.Version != "" {
 			current.Version = descriptor.Version
 		}
+		current.AnnotationProcessor = current.AnnotationProcessor || descriptor.AnnotationProcessor
 		items[key] = current
 	}
 	deduped := make([]dependencyDescriptor, 0, len(items))
@@ -41,10 +41,11 @@ func parseGradleDependencyContentForPath(path, content string) []dependencyDescr
 	descriptors := make([]dependencyDescriptor, 0)
 	for _, coordinate := range coordinates {
 		descriptors = append(descriptors, dependencyDescriptor{
-			Name:     coordinate.Artifact,
-			Group:    coordinate.Group,
-			Artifact: coordinate.Artifact,
-			Version:  coordinate.Version,
+			Name:                coordinate.Artifact,
+			Group:               coordinate.Group,
+			Artifact:            coordinate.Artifact,
+			Version:             coordinate.Version,
+			AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(coordinate.Configuration),
 		})
 	}
 	return dedupeDescriptors(descriptors)
@@ -55,10 +56,11 @@ func parseGradleDependencyContentWithCatalog(path string, content string, catalo
 	catalogDescriptors, warnings := catalogResolver.ParseDependencyReferences(path, content)
 	for _, descriptor := range catalogDescriptors {
 		descriptors = append(descriptors, dependencyDescriptor{
-			Name:     descriptor.Artifact,
-			Group:    descriptor.Group,
-			Artifact: descriptor.Artifact,
-			Version:  descriptor.Version,
+			Name:                descriptor.Artifact,
+			Group:               descriptor.Group,
+			Artifact:            descriptor.Artifact,
+			Version:             descriptor.Version,
+			AnnotationProcessor: shared.IsGradleAnnotationProcessorConfiguration(descriptor.Configuration),
 		})
 	}
 	return dedupeDescriptors(descriptors), warnings
@@ -79,7 +81,7 @@ func parseBuildFilesWithWarnings(repoPath string, parser func(content string) []
 }
 
 func parseDiscoveredBuildFiles(files []discoveredGradleFile, parser func(content string) []dependencyDescriptor) []dependencyDescriptor {
-	seen := make(map[string]struct{})
+	seen := make(map[string]int)
 	descriptors := make([]dependencyDescriptor, 0)
 	for _, file := range files {
 		for _, descriptor := range parser(file.Content) {
@@ -90,7 +92,7 @@ func parseDiscoveredBuildFiles(files []discoveredGradleFile, parser func(content
 }
 
 func parseDiscoveredBuildFilesWithPath(files []discoveredGradleFile, parser func(path, content string) ([]dependencyDescriptor, []string)) ([]dependencyDescriptor, []string) {
-	seen := make(map[string]struct{})
+	seen := make(map[string]int)
 	descriptors := make([]dependencyDescriptor, 0)
 	warnings := make([]string, 0)
 	for _, file := range files {
@@ -103,12 +105,13 @@ func parseDiscoveredBuildFilesWithPath(files []discoveredGradleFile, parser func
 	return descriptors, shared.DedupeWarnings(warnings)
 }
 
-func appendManifestDescriptor(descriptors []dependencyDescriptor, seen map[string]struct{}, descriptor dependencyDescriptor) []dependencyDescriptor {
+func appendManifestDescriptor(descriptors []dependencyDescriptor, seen map[string]int, descriptor dependencyDescriptor) []dependencyDescriptor {
 	key := descriptorKey(descriptor)
-	if _, ok := seen[key]; ok {
+	if index, ok := seen[key]; ok {
+		descriptors[index].AnnotationProcessor = descriptors[index].AnnotationProcessor || descriptor.AnnotationProcessor
 		return descriptors
 	}
-	seen[key] = struct{}{}
+	seen[key] = len(descriptors)
 	descriptor.FromManifest = true
 	return append(descriptors, descriptor)
 }
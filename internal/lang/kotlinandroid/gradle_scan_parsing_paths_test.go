@@ -17,7 +17,7 @@ import (
 )
 
 func TestScanRepoAndDetectErrorBranches(t *testing.T) {
-	if _, err := scanRepo(context.Background(), "", dependencyLookups{}); !errors.Is(err, fs.ErrInvalid) {
+	if _, err := scanRepo(context.Background(), "", dependencyLookups{}, false); !errors.Is(err, fs.ErrInvalid) {
 		t.Fatalf("expected fs.ErrInvalid for empty repo path, got %v", err)
 	}
 
@@ -296,7 +296,7 @@ func TestAnalyseWarningsAndScanBranches(t *testing.T) {
 		t.Fatalf("expected analyse error for invalid repo path")
 	}
 
-	scanResult, err := scanRepo(context.Background(), t.TempDir(), dependencyLookups{})
+	scanResult, err := scanRepo(context.Background(), t.TempDir(), dependencyLookups{}, false)
 	if err != nil {
 		t.Fatalf("scan empty repo: %v", err)
 	}
@@ -304,11 +304,11 @@ func TestAnalyseWarningsAndScanBranches(t *testing.T) {
 		t.Fatalf("expected no-source warning, got %#v", scanResult.Warnings)
 	}
 
-	if _, err := scanRepo(context.Background(), filepath.Join(repo, "missing"), dependencyLookups{}); err == nil {
+	if _, err := scanRepo(context.Background(), filepath.Join(repo, "missing"), dependencyLookups{}, false); err == nil {
 		t.Fatalf("expected scan error for missing repo path")
 	}
 
-	if _, err := scanRepo(testutil.CanceledContext(), repo, dependencyLookups{}); !errors.Is(err, context.Canceled) {
+	if _, err := scanRepo(testutil.CanceledContext(), repo, dependencyLookups{}, false); !errors.Is(err, context.Canceled) {
 		t.Fatalf("expected canceled context error from scanRepo, got %v", err)
 	}
 }
@@ -542,7 +542,7 @@ func TestScanRepoAndImportBranches(t *testing.T) {
 	repo := t.TempDir()
 	scanState := newScanResult()
 	testutil.MustWriteFile(t, filepath.Join(repo, ".git", "src", "Ignored.kt"), "package ignored\n")
-	scanResult, err := scanRepo(context.Background(), repo, dependencyLookups{})
+	scanResult, err := scanRepo(context.Background(), repo, dependencyLookups{}, false)
 	if err != nil {
 		t.Fatalf("scan repo with skipped dirs: %v", err)
 	}
@@ -63,12 +63,17 @@ func kotlinAndroidRiskCues(dependency string, scan scanResult, stats shared.Depe
 			Message:  "dependency inferred from imports but not declared in Gradle manifests",
 		})
 	}
+	cues = append(cues, scan.RuntimeUsage.RiskCues(dependency)...)
 	return cues
 }
 
 func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 	recommendations := make([]report.Recommendation, 0, 4)
-	if len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0 {
+	unused := len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0
+	switch {
+	case unused && shared.HasJVMRuntimeUsageCue(dep.RiskCues):
+		recommendations = append(recommendations, shared.JVMRuntimeUsageRecommendation())
+	case unused:
 		recommendations = append(recommendations, report.Recommendation{
 			Code:      "remove-unused-dependency",
 			Priority:  "high",
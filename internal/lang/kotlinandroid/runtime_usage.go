@@ -0,0 +1,54 @@
+package kotlinandroid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+)
+
+// recordRuntimeReferences attributes class names a file hands to the runtime
+// by name. References that match no declared Gradle dependency are dropped
+// rather than attributed by package fallback.
+func recordRuntimeReferences(result *scanResult, relativePath, filePackage string, content []byte, lookups dependencyLookups) {
+	if result == nil || result.RuntimeUsage == nil {
+		return
+	}
+	for _, reference := range shared.ScanJVMRuntimeReferences(relativePath, content) {
+		dependency := runtimeReferenceDependency(reference, filePackage, lookups)
+		if dependency == "" {
+			continue
+		}
+		result.RuntimeUsage.Add(normalizeDependencyID(dependency), reference.Code, fmt.Sprintf("%s:%d %s", relativePath, reference.Line, reference.ClassName))
+	}
+}
+
+func runtimeReferenceDependency(reference shared.JVMRuntimeReference, filePackage string, lookups dependencyLookups) string {
+	if !shouldIgnoreImport(reference.ClassName, filePackage) {
+		if dependency, _ := resolveDependency(reference.ClassName, lookups); dependency != "" {
+			return dependency
+		}
+	}
+	for _, artifact := range reference.Artifacts {
+		if dependency, ok := lookups.Aliases[strings.ReplaceAll(artifact, "-", ".")]; ok {
+			return dependency
+		}
+	}
+	return ""
+}
+
+// recordDeclaredRuntimeUsage marks dependencies declared in kapt, ksp, or
+// annotationProcessor configurations and well-known processors.
+func recordDeclaredRuntimeUsage(result *scanResult, descriptors []dependencyDescriptor) {
+	if result.RuntimeUsage == nil {
+		return
+	}
+	for _, descriptor := range descriptors {
+		switch {
+		case descriptor.AnnotationProcessor:
+			result.RuntimeUsage.Add(normalizeDependencyID(descriptor.Name), shared.JVMAnnotationProcessorCue, "declared as an annotation processor")
+		case shared.IsKnownJVMAnnotationProcessorArtifact(descriptor.Group, descriptor.Artifact):
+			result.RuntimeUsage.Add(normalizeDependencyID(descriptor.Name), shared.JVMAnnotationProcessorCue, "known annotation processor")
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package kotlinandroid
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func TestAnalyseKeepsRuntimeLoadedDependenciesOutOfRemoval(t *testing.T) {
+	repo := t.TempDir()
+	writeRepoFiles(t, repo, map[string]string{
+		filepath.Join("app", buildGradleKTSName): `
+plugins { id("com.android.application") }
+dependencies {
+  kapt("com.google.auto.service:auto-service:1.1.1")
+  implementation("org.postgresql:postgresql:42.7.3")
+  implementation("com.squareup.okhttp3:okhttp:4.12.0")
+}
+`,
+		filepath.Join("app", "src", "main", "AndroidManifest.xml"): testAppManifest,
+		filepath.Join("app", "src", "main", "kotlin", testMainSourceFileName): `package com.example
+
+import com.google.auto.service.AutoService
+import okhttp3.OkHttpClient
+import org.postgresql.PGProperty
+
+const val DATABASE_URL = "jdbc:postgresql://localhost/app"
+`,
+	})
+
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{Channel: featureflags.ChannelDev, Enable: []string{shared.JVMRuntimeUsagePreviewFeature}})
+	if err != nil {
+		t.Fatalf("resolve feature set: %v", err)
+	}
+	reportData := mustAnalyse(t, language.Request{RepoPath: repo, TopN: 10, Features: features})
+	deps := map[string]report.DependencyReport{}
+	for _, dep := range reportData.Dependencies {
+		deps[dep.Name] = dep
+	}
+
+	requireRuntimeReview(t, deps["auto-service"], "annotation-processor")
+	requireRuntimeReview(t, deps["postgresql"], "jdbc-driver-url")
+	if codes := recommendationCodes(deps["okhttp"].Recommendations); !slices.Contains(codes, "remove-unused-dependency") {
+		t.Fatalf("expected okhttp without runtime usage to stay a removal candidate, got %#v", codes)
+	}
+
+	for _, dep := range mustAnalyse(t, language.Request{RepoPath: repo, TopN: 10}).Dependencies {
+		if shared.HasJVMRuntimeUsageCue(dep.RiskCues) {
+			t.Fatalf("expected no runtime usage cues without the preview flag, got %#v on %s", dep.RiskCues, dep.Name)
+		}
+	}
+}
+
+func TestParseGradleDependencyContentMarksAnnotationProcessors(t *testing.T) {
+	descriptors := parseGradleDependencyContentForPath(buildGradleKTSName, `
+dependencies {
+  implementation("androidx.room:room-runtime:2.6.1")
+  ksp("androidx.room:room-compiler:2.6.1")
+}
+`)
+	processors := map[string]bool{}
+	for _, descriptor := range descriptors {
+		processors[descriptor.Name] = descriptor.AnnotationProcessor
+	}
+	if processors["room-runtime"] || !processors["room-compiler"] {
+		t.Fatalf("expected only the ksp dependency to be an annotation processor, got %#v", processors)
+	}
+}
+
+func requireRuntimeReview(t *testing.T, dep report.DependencyReport, cue string) {
+	t.Helper()
+	if !hasRiskCue(dep, cue) {
+		t.Fatalf("expected %s risk cue on %q, got %#v", cue, dep.Name, dep.RiskCues)
+	}
+	codes := recommendationCodes(dep.Recommendations)
+	if slices.Contains(codes, "remove-unused-dependency") || !slices.Contains(codes, "review-runtime-loading") {
+		t.Fatalf("expected %q to be reviewed instead of removed, got %#v", dep.Name, codes)
+	}
+}
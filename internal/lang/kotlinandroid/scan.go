@@ -28,6 +28,7 @@ type scanResult struct {
 	Warnings               []string
 	AmbiguousDependencies  map[string]struct{}
 	UndeclaredDependencies map[string]struct{}
+	RuntimeUsage           shared.JVMRuntimeUsage
 
 	fallbackModules  map[string]string
 	ambiguousModules map[string][]string
@@ -103,11 +104,16 @@ func (s *scanResult) appendInferenceWarnings() {
 	}
 }
 
-func scanRepo(ctx context.Context, repoPath string, lookups dependencyLookups) (scanResult, error) {
+// scanRepo scans Kotlin and Java sources. With runtimeUsage it also records
+// class names that sources and runtime configuration files load by name.
+func scanRepo(ctx context.Context, repoPath string, lookups dependencyLookups, runtimeUsage bool) (scanResult, error) {
 	result := newScanResult()
 	if repoPath == "" {
 		return result, fs.ErrInvalid
 	}
+	if runtimeUsage {
+		result.RuntimeUsage = make(shared.JVMRuntimeUsage)
+	}
 
 	err := shared.WalkRepoFiles(ctx, repoPath, 0, shouldSkipDir, func(path string, entry fs.DirEntry) error {
 		return scanKotlinAndroidSourceFile(repoPath, path, lookups, &result)
@@ -124,15 +130,21 @@ func scanRepo(ctx context.Context, repoPath string, lookups dependencyLookups) (
 }
 
 func scanKotlinAndroidSourceFile(repoPath string, path string, lookups dependencyLookups, result *scanResult) error {
-	if !isSourceFile(path) {
+	runtimeConfig := result.RuntimeUsage != nil && shared.IsJVMRuntimeConfigPath(path)
+	if !isSourceFile(path) && !runtimeConfig {
 		return nil
 	}
 	content, relativePath, err := readKotlinAndroidSource(repoPath, path)
 	if err != nil {
 		return err
 	}
+	if runtimeConfig {
+		recordRuntimeReferences(result, relativePath, "", content, lookups)
+		return nil
+	}
 	filePackage := parsePackage(content)
 	imports := parseImports(content, relativePath, filePackage, lookups, result)
+	recordRuntimeReferences(result, relativePath, filePackage, content, lookups)
 	result.Files = append(result.Files, fileScan{
 		Path:    relativePath,
 		Package: filePackage,
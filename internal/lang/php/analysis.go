@@ -9,10 +9,11 @@ import (
 )
 
 type analysisPipelineState struct {
-	repoPath string
-	composer composerData
-	scan     scanResult
-	warnings []string
+	repoPath       string
+	indexInstalled bool
+	composer       composerData
+	scan           scanResult
+	warnings       []string
 }
 
 func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Result, error) {
@@ -21,7 +22,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		return report.Report{}, err
 	}
 
-	state := analysisPipelineState{repoPath: repoPath}
+	state := analysisPipelineState{repoPath: repoPath, indexInstalled: req.Features.Enabled(phpComposerAutoloadPreviewFeature)}
 	if err := runComposerIngestionStage(&state); err != nil {
 		return report.Report{}, err
 	}
@@ -38,6 +39,14 @@ func runComposerIngestionStage(state *analysisPipelineState) error {
 	}
 	state.composer = composerData
 	state.warnings = append(state.warnings, warnings...)
+	if !state.indexInstalled {
+		return nil
+	}
+	installedWarnings, err := loadInstalledPackages(state.repoPath, &state.composer)
+	if err != nil {
+		return err
+	}
+	state.warnings = append(state.warnings, installedWarnings...)
 	return nil
 }
 
@@ -15,6 +15,9 @@ type composerData struct {
 	DeclaredDependencies map[string]struct{}
 	NamespaceToDep       map[string]string
 	LocalNamespaces      map[string]struct{}
+	ClassToDep           map[string]string
+	Installed            map[string]installedPackage
+	VendorDir            string
 }
 
 type composerManifest struct {
@@ -23,10 +26,16 @@ type composerManifest struct {
 	RequireDev  map[string]string `json:"require-dev"`
 	Autoload    composerAutoload  `json:"autoload"`
 	AutoloadDev composerAutoload  `json:"autoload-dev"`
+	Config      struct {
+		VendorDir string `json:"vendor-dir"`
+	} `json:"config"`
 }
 
 type composerAutoload struct {
-	PSR4 map[string]any `json:"psr-4"`
+	PSR4     map[string]any `json:"psr-4"`
+	PSR0     map[string]any `json:"psr-0"`
+	Classmap []string       `json:"classmap"`
+	Files    []string       `json:"files"`
 }
 
 type composerLock struct {
@@ -44,6 +53,9 @@ func loadComposerData(repoPath string) (composerData, []string, error) {
 		DeclaredDependencies: make(map[string]struct{}),
 		NamespaceToDep:       make(map[string]string),
 		LocalNamespaces:      make(map[string]struct{}),
+		ClassToDep:           make(map[string]string),
+		Installed:            make(map[string]installedPackage),
+		VendorDir:            defaultComposerVendorDir,
 	}
 	warnings := make([]string, 0)
 
@@ -57,6 +69,9 @@ func loadComposerData(repoPath string) (composerData, []string, error) {
 	if hasManifest {
 		collectDeclaredDependencies(manifest, data.DeclaredDependencies)
 		collectLocalNamespaces(manifest, data.LocalNamespaces)
+		if vendorDir := strings.TrimSpace(manifest.Config.VendorDir); vendorDir != "" && !filepath.IsAbs(vendorDir) {
+			data.VendorDir = filepath.ToSlash(filepath.Clean(vendorDir))
+		}
 	}
 
 	if err := loadComposerLockMappings(repoPath, &data); err != nil {
@@ -4,6 +4,7 @@ import "strings"
 
 type composerResolver struct {
 	namespaceToDep map[string]string
+	classToDep     map[string]string
 	localNamespace map[string]struct{}
 	declared       map[string]struct{}
 }
@@ -11,6 +12,7 @@ type composerResolver struct {
 func newComposerResolver(data composerData) composerResolver {
 	return composerResolver{
 		namespaceToDep: data.NamespaceToDep,
+		classToDep:     data.ClassToDep,
 		localNamespace: data.LocalNamespaces,
 		declared:       data.DeclaredDependencies,
 	}
@@ -24,6 +26,9 @@ func (r *composerResolver) dependencyFromModule(module string) (string, bool) {
 	if r.isLocalNamespace(module) {
 		return "", false
 	}
+	if dep := r.classToDep[module]; dep != "" {
+		return dep, true
+	}
 	if dep := r.resolveWithPSR4(module); dep != "" {
 		return dep, true
 	}
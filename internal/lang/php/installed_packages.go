@@ -0,0 +1,253 @@
+package php
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	phpComposerAutoloadPreviewFeature = "php-composer-autoload-preview"
+	defaultComposerVendorDir          = "vendor"
+	composerInstalledName             = "composer/installed.json"
+	maxIndexedPackageFiles            = 4096
+)
+
+var (
+	phpNamespaceDeclPattern = regexp.MustCompile(`(?m)^\s*namespace\s+([A-Za-z_\\][A-Za-z0-9_\\]*)\s*[;{]`)
+	phpClassDeclPattern     = regexp.MustCompile(`(?m)^\s*(?:(?:abstract|final|readonly)\s+)*(?:class|interface|trait|enum)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// installedPackage is the export surface of a package Composer installed into
+// the vendor directory: the classes its autoload rules cover and the files it
+// loads on every request.
+type installedPackage struct {
+	Name          string
+	Location      string
+	PublicClasses int
+	Files         []string
+}
+
+type composerInstalledPackage struct {
+	Name        string           `json:"name"`
+	InstallPath string           `json:"install-path"`
+	Autoload    composerAutoload `json:"autoload"`
+}
+
+// loadInstalledPackages reads vendor/composer/installed.json and indexes each
+// package's psr-4, psr-0, and classmap autoload rules, mapping namespaces and
+// the classes declared under them to the package, so packages whose
+// namespace differs from their vendor/package name resolve exactly.
+func loadInstalledPackages(repoPath string, data *composerData) ([]string, error) {
+	vendorDir := filepath.Join(repoPath, filepath.FromSlash(data.VendorDir))
+	installedPath := filepath.Join(vendorDir, filepath.FromSlash(composerInstalledName))
+	relPath := filepath.ToSlash(filepath.Join(data.VendorDir, composerInstalledName))
+	content, err := safeio.ReadFileUnderLimit(repoPath, installedPath, maxComposerLockBytes)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return []string{relPath + " not found; run composer install for exact class attribution"}, nil
+	case shared.IsPureSentinelError(err, safeio.ErrFileTooLarge):
+		return []string{fmt.Sprintf("%s skipped: %v", relPath, err)}, nil
+	case err != nil:
+		return nil, err
+	}
+	packages, err := parseComposerInstalled(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", relPath, err)
+	}
+
+	warnings := make([]string, 0)
+	truncated := make([]string, 0)
+	for _, pkg := range packages {
+		dep, ok := normalizeComposerDependency(pkg.Name)
+		if !ok {
+			continue
+		}
+		installPath := filepath.Join(vendorDir, filepath.FromSlash(dep))
+		if pkg.InstallPath != "" {
+			installPath = filepath.Join(filepath.Dir(installedPath), filepath.FromSlash(pkg.InstallPath))
+		}
+		if !shared.IsPathWithin(repoPath, installPath) {
+			continue
+		}
+		for _, namespace := range autoloadNamespaces(pkg.Autoload) {
+			data.NamespaceToDep[namespace] = dep
+		}
+		indexed := installedPackage{Name: dep, Location: filepath.ToSlash(relOrAbs(repoPath, installPath))}
+		classes, complete := indexPackageClasses(repoPath, installPath, pkg.Autoload)
+		if !complete {
+			truncated = append(truncated, dep)
+		}
+		for _, class := range classes {
+			data.ClassToDep[class] = dep
+		}
+		indexed.PublicClasses = len(classes)
+		for _, file := range pkg.Autoload.Files {
+			indexed.Files = append(indexed.Files, filepath.ToSlash(file))
+		}
+		data.Installed[dep] = indexed
+	}
+	if len(truncated) > 0 {
+		warnings = append(warnings, fmt.Sprintf("class index capped at %d files for %d installed package(s): %s", maxIndexedPackageFiles, len(truncated), strings.Join(truncated, ", ")))
+	}
+	return warnings, nil
+}
+
+// parseComposerInstalled accepts both the Composer 2 {"packages": [...]}
+// document and the bare package array Composer 1 writes.
+func parseComposerInstalled(content []byte) ([]composerInstalledPackage, error) {
+	document := struct {
+		Packages []composerInstalledPackage `json:"packages"`
+	}{}
+	if err := json.Unmarshal(content, &document); err == nil {
+		return document.Packages, nil
+	}
+	packages := make([]composerInstalledPackage, 0)
+	if err := json.Unmarshal(content, &packages); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+func autoloadNamespaces(autoload composerAutoload) []string {
+	namespaces := make([]string, 0, len(autoload.PSR4)+len(autoload.PSR0))
+	for _, rules := range []map[string]any{autoload.PSR4, autoload.PSR0} {
+		for namespace := range rules {
+			if normalized := normalizeNamespace(namespace); normalized != "" {
+				namespaces = append(namespaces, normalized)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// indexPackageClasses collects the fully qualified names of the classes,
+// interfaces, traits, and enums declared in the package's autoloaded
+// directories and classmap entries.
+func indexPackageClasses(repoPath, installPath string, autoload composerAutoload) ([]string, bool) {
+	roots := make([]string, 0)
+	for _, rules := range []map[string]any{autoload.PSR4, autoload.PSR0} {
+		for _, value := range rules {
+			roots = append(roots, autoloadPaths(value)...)
+		}
+	}
+	roots = append(roots, autoload.Classmap...)
+	sort.Strings(roots)
+
+	classes := make(map[string]struct{})
+	visited := 0
+	for _, root := range roots {
+		rootPath := filepath.Join(installPath, filepath.FromSlash(root))
+		if !shared.IsPathWithin(installPath, rootPath) {
+			continue
+		}
+		err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return nil
+			}
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".php") {
+				return nil
+			}
+			if visited++; visited > maxIndexedPackageFiles {
+				return fs.SkipAll
+			}
+			content, err := safeio.ReadFileUnderLimit(repoPath, path, maxScannablePHPFile)
+			if err != nil {
+				return nil
+			}
+			for _, class := range declaredPHPClasses(content, path) {
+				classes[class] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil || visited > maxIndexedPackageFiles {
+			return shared.SortedKeys(classes), false
+		}
+	}
+	return shared.SortedKeys(classes), true
+}
+
+func relOrAbs(repoPath, path string) string {
+	relPath, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		return path
+	}
+	return relPath
+}
+
+func autoloadPaths(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []any:
+		paths := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if path, ok := item.(string); ok {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	default:
+		return nil
+	}
+}
+
+// declaredPHPClasses returns the fully qualified names of the types a file
+// declares, qualified by the namespace statement that precedes each one.
+func declaredPHPClasses(content []byte, filePath string) []string {
+	text := string(shared.MaskCommentsAndStringsForFile(content, filePath))
+	namespaces := phpNamespaceDeclPattern.FindAllStringSubmatchIndex(text, -1)
+	classes := make([]string, 0)
+	for _, match := range phpClassDeclPattern.FindAllStringSubmatchIndex(text, -1) {
+		namespace := ""
+		for _, candidate := range namespaces {
+			if candidate[0] > match[0] {
+				break
+			}
+			namespace = normalizeNamespace(text[candidate[2]:candidate[3]])
+		}
+		name := text[match[2]:match[3]]
+		if namespace != "" {
+			name = namespace + `\` + name
+		}
+		classes = append(classes, name)
+	}
+	return classes
+}
+
+// applyComposerExportSurface measures usage against the public classes of the
+// installed package instead of the symbols its imports happen to name.
+func applyComposerExportSurface(dep *report.DependencyReport, pkg installedPackage, referenced bool) {
+	if pkg.PublicClasses > 0 {
+		dep.TotalExportsCount = pkg.PublicClasses
+		dep.UsedExportsCount = min(dep.UsedExportsCount, pkg.PublicClasses)
+		dep.UsedPercent = float64(dep.UsedExportsCount) / float64(pkg.PublicClasses) * 100
+	}
+	if len(pkg.Files) > 0 {
+		dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+			Code:     "autoloaded-files",
+			Severity: "low",
+			Message:  fmt.Sprintf("Composer loads %d file(s) from this package on every request (%s); their global functions are used without imports", len(pkg.Files), strings.Join(pkg.Files, ", ")),
+		})
+		return
+	}
+	if referenced || pkg.PublicClasses == 0 {
+		return
+	}
+	dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+		Code:     "unreferenced-package",
+		Severity: "medium",
+		Message:  fmt.Sprintf("none of the %d public classes in %s are referenced", pkg.PublicClasses, pkg.Location),
+	})
+}
@@ -0,0 +1,152 @@
+package php
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+const testInstalledComposerJSON = `{
+  "name": "acme/app",
+  "require": {
+    "nesbot/carbon": "^3.0",
+    "legacy/tcpdf": "^6.0",
+    "symfony/polyfill-mbstring": "^1.28",
+    "guzzlehttp/guzzle": "^7.8"
+  },
+  "autoload": {"psr-4": {"App\\": "src/"}},
+  "config": {"vendor-dir": "lib/vendor"}
+}
+`
+
+const testInstalledJSON = `{
+  "packages": [
+    {"name": "nesbot/carbon", "install-path": "../nesbot/carbon", "autoload": {"psr-4": {"Carbon\\": "src/Carbon/"}}},
+    {"name": "legacy/tcpdf", "install-path": "../legacy/tcpdf", "autoload": {"classmap": ["tcpdf.php", "include"]}},
+    {"name": "symfony/polyfill-mbstring", "install-path": "../symfony/polyfill-mbstring", "autoload": {"psr-4": {"Symfony\\Polyfill\\Mbstring\\": ""}, "files": ["bootstrap.php"]}},
+    {"name": "guzzlehttp/guzzle", "install-path": "../guzzlehttp/guzzle", "autoload": {"psr-4": {"GuzzleHttp\\": ["src/"]}}}
+  ],
+  "dev": true
+}
+`
+
+func TestDeclaredPHPClassesQualifiesByNamespace(t *testing.T) {
+	content := "<?php\nnamespace Carbon;\n\n// class Commented {}\nfinal class Carbon {}\ninterface CarbonInterface {}\nnamespace Carbon\\Traits;\ntrait Date {}\n"
+	got := declaredPHPClasses([]byte(content), "Carbon.php")
+	if !slices.Equal(got, []string{`Carbon\Carbon`, `Carbon\CarbonInterface`, `Carbon\Traits\Date`}) {
+		t.Fatalf("unexpected declared classes %#v", got)
+	}
+}
+
+func TestPHPAdapterIndexesInstalledComposerPackages(t *testing.T) {
+	repo := t.TempDir()
+	vendor := filepath.Join(repo, "lib", "vendor")
+	writeFile(t, filepath.Join(repo, testComposerJSON), testInstalledComposerJSON)
+	writeFile(t, filepath.Join(vendor, "composer", "installed.json"), testInstalledJSON)
+	writeFile(t, filepath.Join(vendor, "nesbot", "carbon", "src", "Carbon", "Carbon.php"), "<?php\nnamespace Carbon;\nclass Carbon {}\n")
+	writeFile(t, filepath.Join(vendor, "nesbot", "carbon", "src", "Carbon", "CarbonImmutable.php"), "<?php\nnamespace Carbon;\nclass CarbonImmutable {}\n")
+	writeFile(t, filepath.Join(vendor, "nesbot", "carbon", "src", "Carbon", "CarbonInterval.php"), "<?php\nnamespace Carbon;\nclass CarbonInterval {}\n")
+	writeFile(t, filepath.Join(vendor, "nesbot", "carbon", "src", "Carbon", "Factory.php"), "<?php\nnamespace Carbon;\nclass Factory {}\n")
+	writeFile(t, filepath.Join(vendor, "legacy", "tcpdf", "tcpdf.php"), "<?php\nclass TCPDF {}\n")
+	writeFile(t, filepath.Join(vendor, "legacy", "tcpdf", "include", "tcpdf_fonts.php"), "<?php\nclass TCPDF_FONTS {}\n")
+	writeFile(t, filepath.Join(vendor, "symfony", "polyfill-mbstring", "Mbstring.php"), "<?php\nnamespace Symfony\\Polyfill\\Mbstring;\nfinal class Mbstring {}\n")
+	writeFile(t, filepath.Join(vendor, "symfony", "polyfill-mbstring", "bootstrap.php"), "<?php\nfunction mb_str_pad() {}\n")
+	writeFile(t, filepath.Join(vendor, "guzzlehttp", "guzzle", "src", "Client.php"), "<?php\nnamespace GuzzleHttp;\nclass Client {}\n")
+	writeFile(t, filepath.Join(repo, "src", testIndexPHP), testPHPHeader+"namespace App;\n\nuse Carbon\\Carbon;\nuse TCPDF;\n\n$now = Carbon::now();\n$pdf = new TCPDF();\n")
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10, Features: composerAutoloadPreview(t)})
+	if err != nil {
+		t.Fatalf(testAnalyseErrFmt, err)
+	}
+
+	carbon := requirePHPDependency(t, reportData.Dependencies, "nesbot/carbon")
+	if carbon.TotalExportsCount != 4 || carbon.UsedExportsCount != 1 || len(carbon.UsedImports) == 0 {
+		t.Fatalf("expected Carbon attributed through its installed namespace and measured against 4 classes, got %#v", carbon)
+	}
+	tcpdf := requirePHPDependency(t, reportData.Dependencies, "legacy/tcpdf")
+	if tcpdf.TotalExportsCount != 2 || len(tcpdf.UsedImports) == 0 {
+		t.Fatalf("expected classmap class attributed to legacy/tcpdf, got %#v", tcpdf)
+	}
+	polyfill := requirePHPDependency(t, reportData.Dependencies, "symfony/polyfill-mbstring")
+	if !hasRiskCue(polyfill.RiskCues, "autoloaded-files") || hasRecommendation(polyfill, "remove-unused-dependency") {
+		t.Fatalf("expected files-autoload package to count as implicitly used, got %#v", polyfill)
+	}
+	guzzle := requirePHPDependency(t, reportData.Dependencies, "guzzlehttp/guzzle")
+	if !hasRiskCue(guzzle.RiskCues, "unreferenced-package") || !hasRecommendation(guzzle, "remove-unused-dependency") {
+		t.Fatalf("expected unreferenced installed package to be flagged, got %#v", guzzle)
+	}
+	if !strings.Contains(guzzle.RiskCues[0].Message, "lib/vendor/guzzlehttp/guzzle") {
+		t.Fatalf("expected install location in cue, got %q", guzzle.RiskCues[0].Message)
+	}
+	for _, warning := range reportData.Warnings {
+		if strings.Contains(warning, "symfony/polyfill-mbstring") || strings.Contains(warning, "unable to map") {
+			t.Fatalf("unexpected warning %q", warning)
+		}
+	}
+
+	baseline, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, Dependency: "nesbot/carbon"})
+	if err != nil {
+		t.Fatalf(testAnalyseErrFmt, err)
+	}
+	if len(baseline.Dependencies[0].UsedImports) != 0 {
+		t.Fatalf("expected Carbon to stay unattributed without the preview flag, got %#v", baseline.Dependencies[0])
+	}
+}
+
+func TestLoadInstalledPackagesWarnsWhenVendorIsMissing(t *testing.T) {
+	repo := t.TempDir()
+	data := composerData{NamespaceToDep: map[string]string{}, ClassToDep: map[string]string{}, Installed: map[string]installedPackage{}, VendorDir: defaultComposerVendorDir}
+	warnings, err := loadInstalledPackages(repo, &data)
+	if err != nil {
+		t.Fatalf("load installed packages: %v", err)
+	}
+	if !slices.Equal(warnings, []string{"vendor/composer/installed.json not found; run composer install for exact class attribution"}) {
+		t.Fatalf("unexpected warnings %#v", warnings)
+	}
+
+	writeFile(t, filepath.Join(repo, "vendor", "composer", "installed.json"), `[{"name": "monolog/monolog", "autoload": {"psr-4": {"Monolog\\": "src/Monolog"}}}]`)
+	if _, err := loadInstalledPackages(repo, &data); err != nil {
+		t.Fatalf("load Composer 1 installed.json: %v", err)
+	}
+	if data.NamespaceToDep["Monolog"] != testMonologDependency {
+		t.Fatalf("expected Composer 1 package namespace, got %#v", data.NamespaceToDep)
+	}
+}
+
+func requirePHPDependency(t *testing.T, dependencies []report.DependencyReport, name string) report.DependencyReport {
+	t.Helper()
+	for _, dep := range dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	t.Fatalf("expected dependency %q in %#v", name, dependencies)
+	return report.DependencyReport{}
+}
+
+func hasRecommendation(dep report.DependencyReport, code string) bool {
+	for _, recommendation := range dep.Recommendations {
+		if recommendation.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func composerAutoloadPreview(t *testing.T) featureflags.Set {
+	t.Helper()
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{phpComposerAutoloadPreviewFeature},
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	return features
+}
@@ -58,8 +58,9 @@ func allDependencies(scan scanResult) []string {
 
 func buildDependencyReport(dependency string, scan scanResult, minUsagePercent int) (report.DependencyReport, []string) {
 	stats := shared.BuildDependencyStats(dependency, phpFileUsages(scan), normalizeDependencyID)
+	installed, indexed := scan.Installed[dependency]
 	warnings := make([]string, 0)
-	if !stats.HasImports {
+	if !stats.HasImports && len(installed.Files) == 0 {
 		warnings = append(warnings, fmt.Sprintf("no imports found for dependency %q", dependency))
 	}
 
@@ -88,13 +89,17 @@ func buildDependencyReport(dependency string, scan scanResult, minUsagePercent i
 			Message:  fmt.Sprintf("found %d file(s) with dynamic/reflection usage that may hide dependency references", dynamic),
 		})
 	}
+	if indexed {
+		applyComposerExportSurface(&dep, installed, stats.HasImports)
+	}
 	dep.Recommendations = buildRecommendations(dep, minUsagePercent)
 	return dep, warnings
 }
 
 func buildRecommendations(dep report.DependencyReport, minUsagePercent int) []report.Recommendation {
 	recs := make([]report.Recommendation, 0, 3)
-	if len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0 {
+	unused := len(dep.UsedImports) == 0 && (len(dep.UnusedImports) > 0 || hasRiskCue(dep.RiskCues, "unreferenced-package"))
+	if unused && !hasRiskCue(dep.RiskCues, "autoloaded-files") {
 		recs = append(recs, report.Recommendation{
 			Code:      "remove-unused-dependency",
 			Priority:  "high",
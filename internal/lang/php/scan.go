@@ -21,6 +21,7 @@ type scanResult struct {
 	DeclaredDependencies       map[string]struct{}
 	GroupedImportsByDependency map[string]int
 	DynamicUsageByDependency   map[string]int
+	Installed                  map[string]installedPackage
 }
 
 type fileScan struct {
@@ -53,6 +54,7 @@ func newScanCoordinator(repoPath string, composer composerData) scanCoordinator
 			DeclaredDependencies:       composer.DeclaredDependencies,
 			GroupedImportsByDependency: make(map[string]int),
 			DynamicUsageByDependency:   make(map[string]int),
+			Installed:                  composer.Installed,
 		},
 	}
 }
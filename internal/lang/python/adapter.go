@@ -37,7 +37,7 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		RepoPath:    repoPath,
 	}
 
-	scanResult, err := scanRepo(ctx, repoPath)
+	scanResult, err := scanRepoWithFileCache(ctx, repoPath, req.FileCache)
 	if err != nil {
 		return report.Report{}, err
 	}
@@ -78,9 +78,27 @@ type scanResult struct {
 	Warnings             []string
 	DeclaredDependencies map[string]struct{}
 	ImportedDependencies map[string]struct{}
+	fileCache            language.FileCache
+	fileCacheInputs      string
+}
+
+// fileScanCacheSchema versions the cachedPythonFile layout and the import
+// parsing that produces it; bump it whenever either changes.
+const fileScanCacheSchema = "python-filescan-v1"
+
+type cachedPythonFile struct {
+	Imports []importBinding
+	Usage   map[string]int
 }
 
 func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
+	return scanRepoWithFileCache(ctx, repoPath, nil)
+}
+
+// scanRepoWithFileCache scans like scanRepo but reuses per-file import scans
+// held in fileCache. Entries are keyed by the repository's local module
+// names, so adding or removing a local module misses every file.
+func scanRepoWithFileCache(ctx context.Context, repoPath string, fileCache language.FileCache) (scanResult, error) {
 	result := scanResult{
 		DeclaredDependencies: make(map[string]struct{}),
 		ImportedDependencies: make(map[string]struct{}),
@@ -88,6 +106,11 @@ func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
 	if repoPath == "" {
 		return result, fmt.Errorf("repo path is empty")
 	}
+	if fileCache != nil {
+		if inputs, ok := language.FileCacheInputs(localModuleNames(repoPath)); ok {
+			result.fileCache, result.fileCacheInputs = fileCache, inputs
+		}
+	}
 	declaredDependencies, warnings, err := collectDeclaredDependencies(ctx, repoPath)
 	if err != nil {
 		return result, err
@@ -131,18 +154,30 @@ func scanPythonRepoEntry(repoPath string, path string, entry fs.DirEntry, result
 	if err != nil {
 		return err
 	}
-	imports := parseImports(content, relativePath, repoPath)
-	for _, imported := range imports {
+	scanned := scanPythonFileContent(result, content, relativePath, repoPath)
+	for _, imported := range scanned.Imports {
 		result.ImportedDependencies[imported.Dependency] = struct{}{}
 	}
 	result.Files = append(result.Files, fileScan{
 		Path:    relativePath,
-		Imports: imports,
-		Usage:   shared.CountUsage(content, imports),
+		Imports: scanned.Imports,
+		Usage:   scanned.Usage,
 	})
 	return nil
 }
 
+func scanPythonFileContent(result *scanResult, content []byte, relativePath, repoPath string) cachedPythonFile {
+	key := language.FileCacheKey{Adapter: "python", Schema: fileScanCacheSchema, Path: filepath.ToSlash(relativePath), Content: content, Inputs: result.fileCacheInputs}
+	var scanned cachedPythonFile
+	if language.LoadCachedFile(result.fileCache, key, &scanned) {
+		return scanned
+	}
+	imports := parseImports(content, relativePath, repoPath)
+	scanned = cachedPythonFile{Imports: imports, Usage: shared.CountUsage(content, imports)}
+	language.StoreCachedFile(result.fileCache, key, scanned)
+	return scanned
+}
+
 func enforceRepoBoundary(repoPath, path string) (string, error) {
 	cleanRepo := filepath.Clean(repoPath)
 	cleanPath := filepath.Clean(path)
@@ -423,6 +458,33 @@ func localModuleSearchRoots(repoPath string) []string {
 	return roots
 }
 
+// localModuleNames lists the modules and packages isLocalModule recognises
+// at the top of each local module search root.
+func localModuleNames(repoPath string) []string {
+	names := make([]string, 0)
+	for _, searchRoot := range localModuleSearchRoots(repoPath) {
+		entries, err := os.ReadDir(searchRoot)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			switch {
+			case strings.HasSuffix(name, ".py"):
+				names = append(names, strings.TrimSuffix(name, ".py"))
+			case entry.IsDir():
+				if marker, err := os.Lstat(filepath.Join(searchRoot, name, "__init__.py")); err == nil && marker.Mode()&os.ModeSymlink == 0 {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
 func normalizeDependencyID(value string) string {
 	normalized := report.CanonicalPackageNameForEcosystem("pypi", shared.NormalizeDependencyID(value))
 	if canonical, ok := pythonKnownImportAliases[normalized]; ok {
@@ -6,10 +6,12 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"testing"
 
 	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/language/languagetest"
 	"github.com/ben-ranford/lopper/internal/report"
 	"github.com/ben-ranford/lopper/internal/testutil"
 )
@@ -315,3 +317,33 @@ func TestPythonDetectAndWalkBranches(t *testing.T) {
 		t.Fatalf("expected fs.SkipAll when maxFiles exceeded, got %v", err)
 	}
 }
+
+func TestScanRepoWithFileCacheRescansWhenLocalModulesChange(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "app.py"), "import requests\nimport helpers\nrequests.get(helpers.URL)\n")
+	cache := languagetest.NewMemoryFileCache()
+
+	scan := func() scanResult {
+		t.Helper()
+		result, err := scanRepoWithFileCache(context.Background(), repo, cache)
+		if err != nil {
+			t.Fatalf("scan repo: %v", err)
+		}
+		return result
+	}
+
+	first := scan()
+	second := scan()
+	if cache.Writes != 1 || cache.Hits != 1 || !reflect.DeepEqual(first.Files, second.Files) {
+		t.Fatalf("expected the second scan to reuse the cached file, got writes=%d hits=%d files=%#v", cache.Writes, cache.Hits, second.Files)
+	}
+	if _, ok := second.ImportedDependencies["helpers"]; !ok {
+		t.Fatalf("expected helpers imported as a dependency before it is local, got %#v", second.ImportedDependencies)
+	}
+
+	testutil.MustWriteFile(t, filepath.Join(repo, "helpers.py"), "URL = \"https://example.com\"\n")
+	third := scan()
+	if _, ok := third.ImportedDependencies["helpers"]; ok {
+		t.Fatalf("expected a new local module to invalidate cached scans, got %#v", third.ImportedDependencies)
+	}
+}
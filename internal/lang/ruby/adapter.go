@@ -25,7 +25,11 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		return report.Report{}, err
 	}
 
-	scan, err := scanRepo(ctx, repoPath)
+	options := scanOptions{}
+	if req.Features.Enabled(rubyBundlerRequirePreviewFeature) {
+		options = scanOptions{bundlerRequire: true, gemRoots: defaultGemRoots(repoPath)}
+	}
+	scan, err := scanRepoWithOptions(ctx, repoPath, options)
 	if err != nil {
 		return report.Report{}, err
 	}
@@ -0,0 +1,440 @@
+package ruby
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+const (
+	rubyBundlerRequirePreviewFeature = "ruby-bundler-require-preview"
+	bundlerDefaultGroup              = "default"
+)
+
+var (
+	gemGroupBlockPattern   = regexp.MustCompile(`^\s*group\s*\(?\s*(.+?)\s*\)?\s+do\b`)
+	gemGroupOptionPattern  = regexp.MustCompile(`(?:\bgroups?\s*:|:groups?\s*=>)\s*(\[[^\]]*\]|:[A-Za-z_]+|["'][A-Za-z_]+["'])`)
+	gemRequireFalsePattern = regexp.MustCompile(`(?:\brequire\s*:|:require\s*=>)\s*(?:false|nil)\b`)
+	rubySymbolPattern      = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)|["']([A-Za-z_][A-Za-z0-9_]*)["']`)
+	rubyBlockOpenPattern   = regexp.MustCompile(`\bdo\s*(?:\|[^|]*\|)?\s*$|^\s*(?:if|unless|case|begin|while|until)\b`)
+	rubyBlockEndPattern    = regexp.MustCompile(`^\s*end\b`)
+	bundlerRequirePattern  = regexp.MustCompile(`\bBundler\.require\b(?:\s*\(([^)]*)\))?`)
+	constantReferenceRegex = regexp.MustCompile(`(?:::)?\b[A-Z][A-Za-z0-9_]*`)
+)
+
+// gemfileGem is one `gem` entry of the Gemfile, the Bundler groups it
+// belongs to, and whether Bundler.require loads it (`require: false` opts out).
+type gemfileGem struct {
+	Dependency  string
+	Name        string
+	Groups      []string
+	AutoRequire bool
+	Line        int
+}
+
+// bundlerRequireCall is a `Bundler.require` call. All is set for splats such
+// as `*Rails.groups` or `Rails.env`, whose groups are only known at runtime.
+type bundlerRequireCall struct {
+	All      bool
+	Groups   []string
+	Location report.Location
+}
+
+// constantReference is how often one file names a top-level constant, and
+// where it first does.
+type constantReference struct {
+	Count int
+	Line  int
+}
+
+// bundlerRequireScan is the state for attributing gems that Bundler.require
+// loads through the top-level constants they define rather than requires.
+type bundlerRequireScan struct {
+	Gems         map[string]gemfileGem
+	Constants    map[string]gemConstants
+	Calls        []bundlerRequireCall
+	AppConstants map[string]struct{}
+	References   []map[string]constantReference
+	RuntimeFile  []bool
+	Groups       map[string][]string
+	Railties     map[string]bool
+	RuntimeUses  map[string][]string
+}
+
+func newBundlerRequireScan(repoPath string, gemRoots []string) (*bundlerRequireScan, []string) {
+	content, err := readBundlerFile(repoPath, gemfileName)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	lockContent, err := readBundlerFile(repoPath, gemfileLockName)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	gems := parseGemfileGroups(content)
+	constants, warnings := indexGemConstants(gems, parseGemfileLockVersions(lockContent), gemRoots)
+	bundler := &bundlerRequireScan{
+		Gems:         gems,
+		Constants:    constants,
+		AppConstants: make(map[string]struct{}),
+		Groups:       make(map[string][]string, len(gems)),
+		Railties:     make(map[string]bool),
+		RuntimeUses:  make(map[string][]string),
+	}
+	for dependency, gem := range gems {
+		bundler.Groups[dependency] = gem.Groups
+	}
+	return bundler, warnings
+}
+
+// parseGemfileGroups reads the groups of each Gemfile gem from enclosing
+// `group ... do` blocks and inline `group:`/`groups:` options.
+func parseGemfileGroups(content []byte) map[string]gemfileGem {
+	gems := make(map[string]gemfileGem)
+	blocks := make([][]string, 0)
+	for index, line := range strings.Split(string(content), "\n") {
+		line = shared.StripLineComment(line, "#")
+		if rubyBlockEndPattern.MatchString(line) {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		if match := gemGroupBlockPattern.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, rubySymbols(match[1]))
+			continue
+		}
+		if rubyBlockOpenPattern.MatchString(line) {
+			blocks = append(blocks, nil)
+			continue
+		}
+		dependency, _, ok := parseGemfileDependencyLine(line)
+		if !ok {
+			continue
+		}
+		groups := make([]string, 0)
+		for _, block := range blocks {
+			groups = append(groups, block...)
+		}
+		if match := gemGroupOptionPattern.FindStringSubmatch(line); match != nil {
+			groups = append(groups, rubySymbols(match[1])...)
+		}
+		if len(groups) == 0 {
+			groups = append(groups, bundlerDefaultGroup)
+		}
+		gem := gems[dependency]
+		if gem.Dependency == "" {
+			gem = gemfileGem{Dependency: dependency, Name: gemDeclarationPattern.FindStringSubmatch(line)[1], AutoRequire: true, Line: index + 1}
+		}
+		gem.Groups = uniqueSortedStrings(append(gem.Groups, groups...))
+		gem.AutoRequire = gem.AutoRequire && !gemRequireFalsePattern.MatchString(line)
+		gems[dependency] = gem
+	}
+	return gems
+}
+
+func rubySymbols(value string) []string {
+	symbols := make([]string, 0)
+	for _, match := range rubySymbolPattern.FindAllStringSubmatch(value, -1) {
+		symbol := match[1]
+		if symbol == "" {
+			symbol = match[2]
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// parseBundlerRequireCalls finds `Bundler.require` calls. A bare call loads
+// the default group.
+func parseBundlerRequireCalls(content []byte, filePath string) []bundlerRequireCall {
+	calls := make([]bundlerRequireCall, 0)
+	for index, line := range strings.Split(string(content), "\n") {
+		line = shared.StripLineComment(line, "#")
+		match := bundlerRequirePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		call := bundlerRequireCall{Location: shared.LocationFromLine(filePath, index, line)}
+		arguments := strings.TrimSpace(match[1])
+		switch {
+		case arguments == "":
+			call.Groups = []string{bundlerDefaultGroup}
+		case strings.Contains(arguments, "*") || strings.Contains(arguments, "Rails.env"):
+			call.All = true
+		default:
+			call.Groups = rubySymbols(arguments)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// autoRequired reports whether any Bundler.require call loads the gem.
+func (b *bundlerRequireScan) autoRequired(gem gemfileGem) bool {
+	if !gem.AutoRequire {
+		return false
+	}
+	for _, call := range b.Calls {
+		if call.All {
+			return true
+		}
+		for _, group := range call.Groups {
+			if slices.Contains(gem.Groups, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordFile collects the Bundler.require calls, Zeitwerk-autoloaded app
+// constants, and top-level constant references of one Ruby file.
+func (b *bundlerRequireScan) recordFile(content []byte, relPath string) {
+	b.Calls = append(b.Calls, parseBundlerRequireCalls(content, relPath)...)
+	if constant := zeitwerkAppConstant(relPath); constant != "" {
+		b.AppConstants[constant] = struct{}{}
+	}
+	b.References = append(b.References, topLevelConstantReferences(shared.MaskCommentsAndStringsForFile(content, relPath)))
+	b.RuntimeFile = append(b.RuntimeFile, isRuntimeRubyFile(relPath))
+}
+
+// zeitwerkAppConstant returns the top-level constant Zeitwerk expects a file
+// under an autoload root (app/*, app/*/concerns, lib) to define.
+func zeitwerkAppConstant(relPath string) string {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	switch {
+	case len(parts) >= 4 && parts[0] == "app" && parts[2] == "concerns":
+		parts = parts[3:]
+	case len(parts) >= 3 && parts[0] == "app":
+		parts = parts[2:]
+	case len(parts) >= 2 && parts[0] == "lib":
+		parts = parts[1:]
+	default:
+		return ""
+	}
+	return camelizeRubyName(strings.TrimSuffix(parts[0], ".rb"))
+}
+
+func topLevelConstantReferences(masked []byte) map[string]constantReference {
+	references := make(map[string]constantReference)
+	for index, line := range strings.Split(string(masked), "\n") {
+		for _, location := range constantReferenceRegex.FindAllStringIndex(line, -1) {
+			start := location[0]
+			if start > 0 && (line[start-1] == ':' || line[start-1] == '.' || isRubyIdentifierByte(line[start-1])) {
+				continue
+			}
+			constant := strings.TrimPrefix(line[start:location[1]], "::")
+			reference := references[constant]
+			if reference.Count == 0 {
+				reference.Line = index + 1
+			}
+			reference.Count++
+			references[constant] = reference
+		}
+	}
+	return references
+}
+
+func isRubyIdentifierByte(value byte) bool {
+	return value == '_' || value >= '0' && value <= '9' || value >= 'a' && value <= 'z' || value >= 'A' && value <= 'Z'
+}
+
+// isRuntimeRubyFile reports whether a file ships with the application rather
+// than only running in tests.
+func isRuntimeRubyFile(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	first := strings.SplitN(relPath, "/", 2)[0]
+	switch first {
+	case "spec", "test", "features", "db", "script", "bin":
+		return false
+	}
+	base := filepath.Base(relPath)
+	return !strings.HasSuffix(base, "_spec.rb") && !strings.HasSuffix(base, "_test.rb")
+}
+
+// attribute turns constant references into import bindings on each scanned
+// file for gems that Bundler.require loads, and adds an unused binding at the
+// Gemfile entry for every constant of those gems so unreferenced gems surface.
+func (b *bundlerRequireScan) attribute(scan *scanResult, filePaths []string) {
+	if len(b.Calls) == 0 {
+		return
+	}
+	owners := b.constantOwners()
+	for index := range b.References {
+		file := &scan.Files[index]
+		for _, constant := range sortedConstantKeys(b.References[index]) {
+			dependency, ok := owners[constant]
+			if !ok {
+				continue
+			}
+			reference := b.References[index][constant]
+			location := report.Location{File: filePaths[index], Line: reference.Line}
+			file.Imports = append(file.Imports, importBinding{Dependency: dependency, Module: constant, Name: constant, Local: constant, Location: location})
+			if file.Usage == nil {
+				file.Usage = make(map[string]int)
+			}
+			file.Usage[constant] += reference.Count
+			scan.ImportedDependencies[dependency] = struct{}{}
+			if b.RuntimeFile[index] && !runtimeGroup(b.Gems[dependency].Groups) {
+				b.RuntimeUses[dependency] = append(b.RuntimeUses[dependency], fmt.Sprintf("%s:%d", location.File, location.Line))
+			}
+		}
+	}
+	declarations := fileScan{Usage: make(map[string]int)}
+	for _, dependency := range sortedGemKeys(b.Gems) {
+		gem := b.Gems[dependency]
+		constants, ok := b.Constants[dependency]
+		if !ok || constants.Guessed || !b.autoRequired(gem) {
+			continue
+		}
+		b.Railties[dependency] = constants.Railtie
+		for _, constant := range constants.Constants {
+			if owners[constant] != dependency {
+				continue
+			}
+			declarations.Imports = append(declarations.Imports, importBinding{
+				Dependency: dependency,
+				Module:     constant,
+				Name:       constant,
+				Local:      constant,
+				Location:   report.Location{File: gemfileName, Line: gem.Line},
+			})
+		}
+	}
+	if len(declarations.Imports) > 0 {
+		scan.Files = append(scan.Files, declarations)
+	}
+	if guessed := b.guessedGems(); len(guessed) > 0 {
+		scan.Warnings = append(scan.Warnings, fmt.Sprintf("Bundler.require attribution used Zeitwerk naming for %d gem(s) not installed in vendor/bundle, GEM_HOME, or GEM_PATH: %s", len(guessed), strings.Join(guessed, ", ")))
+	}
+}
+
+func (b *bundlerRequireScan) guessedGems() []string {
+	guessed := make([]string, 0)
+	for _, dependency := range sortedGemKeys(b.Gems) {
+		if b.Constants[dependency].Guessed && b.autoRequired(b.Gems[dependency]) {
+			guessed = append(guessed, dependency)
+		}
+	}
+	return guessed
+}
+
+// constantOwners maps each constant to the auto-required gem that defines
+// it. A constant several gems reopen goes to the gem whose name Zeitwerk
+// would camelize to it, and is otherwise left unattributed. Constants the
+// application autoloads itself are never attributed to gems.
+func (b *bundlerRequireScan) constantOwners() map[string]string {
+	candidates := make(map[string][]string)
+	for _, dependency := range sortedGemKeys(b.Gems) {
+		constants, ok := b.Constants[dependency]
+		if !ok || !b.autoRequired(b.Gems[dependency]) {
+			continue
+		}
+		for _, constant := range constants.Constants {
+			if _, ok := b.AppConstants[constant]; ok {
+				continue
+			}
+			candidates[constant] = append(candidates[constant], dependency)
+		}
+	}
+	owners := make(map[string]string, len(candidates))
+	for constant, dependencies := range candidates {
+		if len(dependencies) == 1 {
+			owners[constant] = dependencies[0]
+			continue
+		}
+		for _, dependency := range dependencies {
+			if slices.Contains(zeitwerkGemConstants(dependency), constant) {
+				owners[constant] = dependency
+				break
+			}
+		}
+	}
+	return owners
+}
+
+func runtimeGroup(groups []string) bool {
+	if len(groups) == 0 {
+		return true
+	}
+	for _, group := range groups {
+		if group != "development" && group != "test" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyBundlerRequireUsage records the gem's Gemfile groups as provenance
+// signals and flags Railtie gems and test-only gems used by runtime code.
+func applyBundlerRequireUsage(dep *report.DependencyReport, dependency string, bundler *bundlerRequireScan) {
+	groups := bundler.Groups[dependency]
+	if len(groups) > 0 {
+		if dep.Provenance == nil {
+			dep.Provenance = &report.DependencyProvenance{Source: rubyDependencySourceBundler}
+		}
+		for _, group := range groups {
+			dep.Provenance.Signals = append(dep.Provenance.Signals, "group:"+group)
+		}
+	}
+	if bundler.Railties[dependency] {
+		dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+			Code:     "railtie-integration",
+			Severity: "low",
+			Message:  "gem registers a Rails::Railtie or Rails::Engine and can be used without constant references",
+		})
+	}
+	if uses := bundler.RuntimeUses[dependency]; len(uses) > 0 {
+		dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+			Code:     "non-runtime-group-reference",
+			Severity: "medium",
+			Message:  fmt.Sprintf("gem is only in Bundler groups %s but runtime code references it at %s", strings.Join(groups, ", "), strings.Join(uses, ", ")),
+		})
+	}
+	dep.Recommendations = buildRecommendations(*dep)
+}
+
+func nonRuntimeGemGroups(dep report.DependencyReport) bool {
+	if dep.Provenance == nil {
+		return false
+	}
+	groups := make([]string, 0)
+	for _, signal := range dep.Provenance.Signals {
+		if group, ok := strings.CutPrefix(signal, "group:"); ok {
+			groups = append(groups, group)
+		}
+	}
+	return len(groups) > 0 && !runtimeGroup(groups)
+}
+
+func sortedConstantKeys(values map[string]constantReference) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedGemKeys(values map[string]gemfileGem) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func uniqueSortedStrings(values []string) []string {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	return shared.SortedKeys(set)
+}
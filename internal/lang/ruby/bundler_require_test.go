@@ -0,0 +1,188 @@
+package ruby
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+const bundlerRequireGemfile = `source 'https://rubygems.org'
+
+gem 'rails'
+gem 'faraday'
+gem 'sidekiq-cron'
+gem 'bootsnap', require: false
+gem 'lograge'
+
+group :development, :test do
+  gem 'rspec-rails'
+  gem 'factory_bot'
+end
+
+gem 'rubocop', group: :development
+`
+
+func TestParseGemfileGroups(t *testing.T) {
+	gems := parseGemfileGroups([]byte(bundlerRequireGemfile))
+	cases := map[string][]string{
+		"rails":       {"default"},
+		"rspec-rails": {"development", "test"},
+		"factory-bot": {"development", "test"},
+		"rubocop":     {"development"},
+	}
+	for dependency, want := range cases {
+		if got := gems[dependency].Groups; !slices.Equal(got, want) {
+			t.Fatalf("expected %s groups %#v, got %#v", dependency, want, got)
+		}
+	}
+	if gems["bootsnap"].AutoRequire || !gems["faraday"].AutoRequire {
+		t.Fatalf("expected require: false to opt bootsnap out of Bundler.require, got %#v", gems)
+	}
+	if gems["rubocop"].Line != 14 {
+		t.Fatalf("expected rubocop declared on line 14, got %d", gems["rubocop"].Line)
+	}
+}
+
+func TestParseBundlerRequireCalls(t *testing.T) {
+	calls := parseBundlerRequireCalls([]byte("Bundler.require(*Rails.groups)\nBundler.require\nBundler.require(:default, :assets)\n"), "config/application.rb")
+	if len(calls) != 3 || !calls[0].All || !slices.Equal(calls[1].Groups, []string{"default"}) || !slices.Equal(calls[2].Groups, []string{"default", "assets"}) {
+		t.Fatalf("unexpected Bundler.require calls %#v", calls)
+	}
+}
+
+func TestRubyAdapterAnalyseAttributesBundlerRequireGems(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, gemfileName), bundlerRequireGemfile)
+	testutil.MustWriteFile(t, filepath.Join(repo, gemfileLockName), `GEM
+  remote: https://rubygems.org/
+  specs:
+    faraday (2.9.0)
+    factory_bot (6.4.5)
+    lograge (0.14.0)
+    sidekiq-cron (1.12.0)
+
+PLATFORMS
+  ruby
+`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "config", "application.rb"), "require 'rails/all'\n\nBundler.require(*Rails.groups)\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "app", "services", "weather_client.rb"), `class WeatherClient
+  def fetch
+    Faraday.get("https://example.test")
+  end
+
+  def build_fixture
+    FactoryBot.build(:forecast)
+  end
+end
+`)
+	testutil.MustWriteFile(t, filepath.Join(repo, "app", "jobs", "report_job.rb"), "class ReportJob\n  def perform\n    WeatherClient.new.fetch\n    Faraday.new\n  end\nend\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "spec", "weather_client_spec.rb"), "RSpec.describe WeatherClient do\nend\n")
+
+	gems := filepath.Join(repo, "vendor", "bundle", "ruby", "3.3.0", "gems")
+	testutil.MustWriteFile(t, filepath.Join(gems, "faraday-2.9.0", "lib", "faraday.rb"), "module Faraday\nend\nclass Hash\nend\n")
+	testutil.MustWriteFile(t, filepath.Join(gems, "sidekiq-cron-1.12.0", "lib", "sidekiq", "cron.rb"), "module Sidekiq\n  module Cron\n  end\nend\n")
+	testutil.MustWriteFile(t, filepath.Join(gems, "lograge-0.14.0", "lib", "lograge", "railtie.rb"), "module Lograge\n  class Railtie < Rails::Railtie\n  end\nend\n")
+	gemHome := t.TempDir()
+	t.Setenv("GEM_HOME", gemHome)
+	t.Setenv("GEM_PATH", "")
+	testutil.MustWriteFile(t, filepath.Join(gemHome, "gems", "factory_bot-6.4.5", "lib", "factory_bot.rb"), "module FactoryBot\nend\n")
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 20, Features: bundlerRequirePreview(t)})
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+
+	faraday := rubyDependencyReport(t, reportData.Dependencies, "faraday")
+	if faraday.UsedExportsCount != 1 || rubyRecommendation(faraday, "remove-unused-gem") != nil {
+		t.Fatalf("expected Faraday constant references to mark faraday used, got %#v", faraday)
+	}
+	if faraday.Provenance == nil || !slices.Contains(faraday.Provenance.Signals, "group:default") {
+		t.Fatalf("expected default group provenance signal, got %#v", faraday.Provenance)
+	}
+
+	cron := rubyDependencyReport(t, reportData.Dependencies, "sidekiq-cron")
+	remove := rubyRecommendation(cron, "remove-unused-gem")
+	if remove == nil || remove.Priority != "high" || len(cron.UnusedImports) == 0 || cron.UnusedImports[0].Locations[0].File != gemfileName {
+		t.Fatalf("expected unreferenced auto-required gem to be removable, got %#v", cron)
+	}
+
+	lograge := rubyDependencyReport(t, reportData.Dependencies, "lograge")
+	if rubyRecommendation(lograge, "remove-unused-gem") != nil || rubyRecommendation(lograge, "review-auto-required-gem") == nil || !hasRiskCue(lograge, "railtie-integration") {
+		t.Fatalf("expected Railtie gem to be reviewed rather than removed, got %#v", lograge)
+	}
+
+	factoryBot := rubyDependencyReport(t, reportData.Dependencies, "factory-bot")
+	move := rubyRecommendation(factoryBot, "move-gem-to-runtime-group")
+	if move == nil || !hasRiskCue(factoryBot, "non-runtime-group-reference") {
+		t.Fatalf("expected test-group gem referenced from app code to be flagged, got %#v", factoryBot)
+	}
+	if !strings.Contains(factoryBot.RiskCues[0].Message, filepath.Join("app", "services", "weather_client.rb")+":7") {
+		t.Fatalf("expected runtime reference location, got %#v", factoryBot.RiskCues)
+	}
+
+	baseline, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 20})
+	if err != nil {
+		t.Fatalf("analyse without preview: %v", err)
+	}
+	if hasRiskCue(rubyDependencyReport(t, baseline.Dependencies, "factory-bot"), "non-runtime-group-reference") {
+		t.Fatalf("expected no Bundler.require attribution without the preview flag")
+	}
+}
+
+func TestBundlerRequireScanWarnsAboutGuessedGems(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, gemfileName), "source 'https://rubygems.org'\ngem 'http_client'\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "boot.rb"), "Bundler.require\nHttpClient.get\n")
+
+	scan, err := scanRepoWithOptions(context.Background(), repo, scanOptions{bundlerRequire: true, gemRoots: []string{filepath.Join(repo, "missing")}})
+	if err != nil {
+		t.Fatalf(rubyScanRepoErrFmt, err)
+	}
+	if _, ok := scan.ImportedDependencies["http-client"]; !ok {
+		t.Fatalf("expected Zeitwerk-named constant to attribute the gem, got %#v", scan.ImportedDependencies)
+	}
+	if !slices.ContainsFunc(scan.Warnings, func(warning string) bool {
+		return strings.Contains(warning, "used Zeitwerk naming for 1 gem(s) not installed in vendor/bundle, GEM_HOME, or GEM_PATH: http-client")
+	}) {
+		t.Fatalf("expected guessed gem warning, got %#v", scan.Warnings)
+	}
+}
+
+func bundlerRequirePreview(t *testing.T) featureflags.Set {
+	t.Helper()
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{rubyBundlerRequirePreviewFeature},
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	return features
+}
+
+func rubyDependencyReport(t *testing.T, dependencies []report.DependencyReport, name string) report.DependencyReport {
+	t.Helper()
+	for _, dep := range dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	t.Fatalf("expected %s report, got %#v", name, dependencies)
+	return report.DependencyReport{}
+}
+
+func rubyRecommendation(dep report.DependencyReport, code string) *report.Recommendation {
+	for index := range dep.Recommendations {
+		if dep.Recommendations[index].Code == code {
+			return &dep.Recommendations[index]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package ruby
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	maxGemLibFiles     = 512
+	maxGemLibFileBytes = 1 << 20
+)
+
+var (
+	rubyTopLevelDefinitionPattern = regexp.MustCompile(`^(?:module|class)\s+(?:::)?([A-Z][A-Za-z0-9_]*)`)
+	railtieSuperclassPattern      = regexp.MustCompile(`<\s*(?:::)?Rails::(?:Railtie|Engine)\b`)
+	gemLockVersionPattern         = regexp.MustCompile(`^\s{4}([A-Za-z0-9_.-]+)\s+\(([^)]+)\)`)
+)
+
+// rubyCoreConstants are constants from Ruby, its standard library, and
+// Bundler that gems routinely reopen; they never identify a gem.
+var rubyCoreConstants = map[string]bool{
+	"Array": true, "BasicObject": true, "Benchmark": true, "Bundler": true, "Class": true,
+	"Comparable": true, "Date": true, "DateTime": true, "Digest": true, "Dir": true,
+	"Encoding": true, "Enumerable": true, "Exception": true, "FalseClass": true, "File": true,
+	"Float": true, "Forwardable": true, "Gem": true, "Hash": true, "IO": true,
+	"Integer": true, "JSON": true, "Kernel": true, "Logger": true, "Math": true,
+	"Module": true, "Net": true, "NilClass": true, "Numeric": true, "Object": true,
+	"OpenSSL": true, "Pathname": true, "Proc": true, "Process": true, "Range": true,
+	"Regexp": true, "Set": true, "StandardError": true, "String": true, "StringIO": true,
+	"Struct": true, "Symbol": true, "Thread": true, "Time": true, "TrueClass": true,
+	"URI": true, "YAML": true,
+}
+
+// gemConstants is the top-level constants a gem's lib/ files define, read
+// from its installed copy. Guessed constants come from Zeitwerk naming
+// conventions when no installed copy was found.
+type gemConstants struct {
+	Constants []string
+	Railtie   bool
+	Guessed   bool
+}
+
+// defaultGemRoots lists the gem directories Bundler and RubyGems install
+// into: vendor/bundle inside the repository, then GEM_HOME and GEM_PATH.
+func defaultGemRoots(repoPath string) []string {
+	roots, _ := filepath.Glob(filepath.Join(repoPath, "vendor", "bundle", "ruby", "*", "gems"))
+	sort.Strings(roots)
+	homes := []string{strings.TrimSpace(os.Getenv("GEM_HOME"))}
+	homes = append(homes, filepath.SplitList(os.Getenv("GEM_PATH"))...)
+	for _, home := range homes {
+		if home = strings.TrimSpace(home); home != "" {
+			roots = append(roots, filepath.Join(home, "gems"))
+		}
+	}
+	return roots
+}
+
+// parseGemfileLockVersions maps each top-level spec in Gemfile.lock to its
+// locked version, including any platform suffix.
+func parseGemfileLockVersions(content []byte) map[string]string {
+	versions := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		match := gemLockVersionPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if _, ok := versions[match[1]]; !ok {
+			versions[match[1]] = match[2]
+		}
+	}
+	return versions
+}
+
+func indexGemConstants(gems map[string]gemfileGem, versions map[string]string, roots []string) (map[string]gemConstants, []string) {
+	index := make(map[string]gemConstants, len(gems))
+	warnings := make([]string, 0)
+	for _, dependency := range sortedGemKeys(gems) {
+		gem := gems[dependency]
+		root, dir := locateInstalledGem(roots, gem.Name, versions[gem.Name])
+		if dir == "" {
+			index[dependency] = gemConstants{Constants: zeitwerkGemConstants(gem.Name), Guessed: true}
+			continue
+		}
+		constants, err := readGemConstants(root, dir)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to index gem %s: %v", filepath.Base(dir), err))
+			index[dependency] = gemConstants{Constants: zeitwerkGemConstants(gem.Name), Guessed: true}
+			continue
+		}
+		index[dependency] = constants
+	}
+	return index, warnings
+}
+
+// locateInstalledGem finds the gem's directory in the first root holding it,
+// at the locked version when known and otherwise at any installed version.
+func locateInstalledGem(roots []string, name, version string) (string, string) {
+	for _, root := range roots {
+		pattern := name + "-" + version
+		if version == "" {
+			pattern = name + "-[0-9]*"
+		}
+		matches, _ := filepath.Glob(filepath.Join(root, pattern))
+		sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+		for _, match := range matches {
+			if info, err := os.Stat(filepath.Join(match, "lib")); err == nil && info.IsDir() {
+				return root, match
+			}
+		}
+	}
+	return "", ""
+}
+
+// readGemConstants collects the modules and classes defined at the top level
+// of the gem's lib/ files and whether it registers a Railtie or Engine.
+func readGemConstants(root, dir string) (gemConstants, error) {
+	constants := gemConstants{}
+	seen := make(map[string]struct{})
+	files := 0
+	err := filepath.WalkDir(filepath.Join(dir, "lib"), func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".rb") {
+			return nil
+		}
+		if files++; files > maxGemLibFiles {
+			return fs.SkipAll
+		}
+		content, err := safeio.ReadFileUnderLimit(root, path, maxGemLibFileBytes)
+		if errors.Is(err, safeio.ErrFileTooLarge) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		masked := shared.MaskCommentsAndStringsForFile(content, path)
+		for _, line := range strings.Split(string(masked), "\n") {
+			if match := rubyTopLevelDefinitionPattern.FindStringSubmatch(line); match != nil && !rubyCoreConstants[match[1]] {
+				seen[match[1]] = struct{}{}
+			}
+		}
+		constants.Railtie = constants.Railtie || railtieSuperclassPattern.Match(masked)
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.SkipAll) {
+		return gemConstants{}, err
+	}
+	constants.Constants = shared.SortedKeys(seen)
+	return constants, nil
+}
+
+// zeitwerkGemConstants returns the top-level constants Zeitwerk's gem
+// conventions expect from a gem name: `foo_bar` defines FooBar, and `foo-bar`
+// defines FooBar or the Foo namespace.
+func zeitwerkGemConstants(name string) []string {
+	candidates := []string{
+		camelizeRubyName(strings.ReplaceAll(name, "-", "_")),
+		camelizeRubyName(strings.SplitN(name, "-", 2)[0]),
+	}
+	constants := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate != "" && !rubyCoreConstants[candidate] && !slices.Contains(constants, candidate) {
+			constants = append(constants, candidate)
+		}
+	}
+	return constants
+}
+
+// camelizeRubyName converts a snake_case file or gem name to the constant
+// Zeitwerk's default inflector expects.
+func camelizeRubyName(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
@@ -35,7 +35,23 @@ func shapeRubyDependencyWarnings(dependency string, stats shared.DependencyStats
 
 func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 	recs := make([]report.Recommendation, 0, 2)
-	if len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0 {
+	unused := len(dep.UsedImports) == 0 && len(dep.UnusedImports) > 0
+	switch {
+	case unused && hasRiskCue(dep, "railtie-integration"):
+		recs = append(recs, report.Recommendation{
+			Code:      "review-auto-required-gem",
+			Priority:  "medium",
+			Message:   fmt.Sprintf("No constant references were found for %q, but Bundler.require loads its Railtie; verify before removing it.", dep.Name),
+			Rationale: "Railties and engines hook into Rails without being referenced by application code.",
+		})
+	case unused && nonRuntimeGemGroups(dep):
+		recs = append(recs, report.Recommendation{
+			Code:      "remove-unused-gem",
+			Priority:  "low",
+			Message:   fmt.Sprintf("No require usage was detected for %q; consider removing it.", dep.Name),
+			Rationale: "Development and test gems are often used by tooling rather than application code.",
+		})
+	case unused:
 		recs = append(recs, report.Recommendation{
 			Code:      "remove-unused-gem",
 			Priority:  "high",
@@ -43,7 +59,15 @@ func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 			Rationale: "Unused gems add maintenance and security overhead.",
 		})
 	}
-	if len(dep.RiskCues) > 0 {
+	if hasRiskCue(dep, "non-runtime-group-reference") {
+		recs = append(recs, report.Recommendation{
+			Code:      "move-gem-to-runtime-group",
+			Priority:  "medium",
+			Message:   fmt.Sprintf("Move %q out of its development or test group; runtime code references it.", dep.Name),
+			Rationale: "Gems outside the default group are not installed or loaded in production.",
+		})
+	}
+	if hasRiskCue(dep, "dynamic-require") {
 		recs = append(recs, report.Recommendation{
 			Code:      "review-runtime-requires",
 			Priority:  "medium",
@@ -53,3 +77,12 @@ func buildRecommendations(dep report.DependencyReport) []report.Recommendation {
 	}
 	return recs
 }
+
+func hasRiskCue(dep report.DependencyReport, code string) bool {
+	for _, cue := range dep.RiskCues {
+		if cue.Code == code {
+			return true
+		}
+	}
+	return false
+}
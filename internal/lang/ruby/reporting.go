@@ -20,7 +20,11 @@ func buildTopRubyDependencies(topN int, scan scanResult, weights report.RemovalC
 
 func buildDependencyReport(dependency string, scan scanResult) (report.DependencyReport, []string) {
 	stats := collectRubyDependencyStats(dependency, scan.Files)
-	return shapeRubyDependencyReport(dependency, stats, scan.DeclaredSources[dependency])
+	dep, warnings := shapeRubyDependencyReport(dependency, stats, scan.DeclaredSources[dependency])
+	if scan.Bundler != nil {
+		applyBundlerRequireUsage(&dep, dependency, scan.Bundler)
+	}
+	return dep, warnings
 }
 
 func collectRubyDependencyStats(dependency string, files []fileScan) shared.DependencyStats {
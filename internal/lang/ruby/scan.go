@@ -10,7 +10,18 @@ import (
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
+// scanOptions enables optional analyses. bundlerRequire attributes gems that
+// Bundler.require loads through constants defined in the gems under gemRoots.
+type scanOptions struct {
+	bundlerRequire bool
+	gemRoots       []string
+}
+
 func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
+	return scanRepoWithOptions(ctx, repoPath, scanOptions{})
+}
+
+func scanRepoWithOptions(ctx context.Context, repoPath string, options scanOptions) (scanResult, error) {
 	scan := scanResult{
 		DeclaredDependencies: make(map[string]struct{}),
 		DeclaredSources:      make(map[string]rubyDependencySource),
@@ -26,6 +37,14 @@ func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
 		scan.Warnings = append(scan.Warnings, "no gem declarations found in Gemfile, Gemfile.lock, or .gemspec files")
 	}
 
+	var bundler *bundlerRequireScan
+	if options.bundlerRequire {
+		var bundlerWarnings []string
+		bundler, bundlerWarnings = newBundlerRequireScan(repoPath, options.gemRoots)
+		scan.Warnings = append(scan.Warnings, bundlerWarnings...)
+	}
+	filePaths := make([]string, 0)
+
 	foundRuby := false
 	err = walkRubyRepoFiles(ctx, repoPath, func(path string, entry fs.DirEntry) error {
 		if !strings.EqualFold(filepath.Ext(entry.Name()), ".rb") {
@@ -47,6 +66,10 @@ func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
 			Imports: imports,
 			Usage:   shared.CountUsage(content, imports),
 		})
+		if bundler != nil {
+			bundler.recordFile(content, relPath)
+			filePaths = append(filePaths, relPath)
+		}
 		foundRuby = true
 		return nil
 	})
@@ -56,6 +79,10 @@ func scanRepo(ctx context.Context, repoPath string) (scanResult, error) {
 	if !foundRuby {
 		scan.Warnings = append(scan.Warnings, "no Ruby files found for analysis")
 	}
+	if bundler != nil {
+		bundler.attribute(&scan, filePaths)
+		scan.Bundler = bundler
+	}
 	return scan, nil
 }
 
@@ -52,6 +52,7 @@ type scanResult struct {
 	DeclaredDependencies map[string]struct{}
 	DeclaredSources      map[string]rubyDependencySource
 	ImportedDependencies map[string]struct{}
+	Bundler              *bundlerRequireScan
 }
 
 type rubyDependencySource struct {
@@ -30,11 +30,17 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 	}
 	result.Warnings = append(result.Warnings, warnings...)
 
-	scan, err := scanRepo(ctx, repoPath, manifestPaths, depLookup, renamedAliases)
+	cargoFeatures := req.Features.Enabled(rustCargoFeaturesPreviewFeature)
+	scan, err := scanRepoWithOptions(ctx, repoPath, manifestPaths, depLookup, renamedAliases, scanOptions{referencedPaths: cargoFeatures})
 	if err != nil {
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, scan.Warnings...)
+	if cargoFeatures {
+		var featureWarnings []string
+		scan.CargoFeatures, featureWarnings = analyseCargoFeatures(repoPath, manifestPaths, scan.ReferencedPaths, defaultCargoRegistrySources())
+		result.Warnings = append(result.Warnings, featureWarnings...)
+	}
 
 	dependencies, dependencyWarnings := buildRequestedRustDependencies(req, scan)
 	result.Dependencies = dependencies
@@ -0,0 +1,408 @@
+package rust
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+var (
+	cfgFeaturePattern      = regexp.MustCompile(`feature\s*=\s*"([^"]+)"`)
+	macroRulesPattern      = regexp.MustCompile(`^macro_rules!\s*([A-Za-z_][A-Za-z0-9_]*)`)
+	gateMacroInvokePattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)!\s*\{`)
+	moduleBlockPattern     = regexp.MustCompile(`^(pub\s+)?mod\s+([A-Za-z_][A-Za-z0-9_]*)\s*\{`)
+	publicItemPattern      = regexp.MustCompile(`^pub\s+(?:(?:unsafe|async|const|extern(?:\s+"[^"]*")?)\s+)*(?:mod|fn|struct|enum|trait|type|const|static|union)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	publicUsePattern       = regexp.MustCompile(`^pub\s+use\s+`)
+)
+
+// crateFeatureGates is what a crate's library sources put behind each Cargo
+// feature: the public item paths, relative to the crate root, that exist only
+// when the feature is enabled. Opaque marks features that gate glob
+// re-exports, whose items cannot be named.
+type crateFeatureGates struct {
+	Table  map[string][]string
+	Items  map[string][]gatedItem
+	Opaque map[string]bool
+}
+
+type gatedItem struct {
+	Path     string
+	Location string
+}
+
+type crateSourceFile struct {
+	Path    string
+	Module  string
+	Content []byte
+	Masked  []byte
+}
+
+// gateFrame is one open brace block. Only module-level frames (the file,
+// inline `mod` blocks, and feature-gating macro invocations) record items.
+type gateFrame struct {
+	module   bool
+	path     string
+	features []string
+}
+
+// readCrateFeatureGates reads the [features] table of the crate in dir and
+// scans its library sources for `#[cfg(feature = ...)]`-gated public items,
+// including items wrapped in `macro_rules!` helpers that apply such a cfg to
+// each `$item` they are given.
+func readCrateFeatureGates(dir string) (crateFeatureGates, error) {
+	gates := crateFeatureGates{
+		Table:  make(map[string][]string),
+		Items:  make(map[string][]gatedItem),
+		Opaque: make(map[string]bool),
+	}
+	content, err := safeio.ReadFileUnderLimit(dir, filepath.Join(dir, cargoTomlName), maxFeatureCrateFileBytes)
+	if err != nil {
+		return crateFeatureGates{}, err
+	}
+	document, err := parseCargoManifestDocument(content)
+	if err != nil {
+		return crateFeatureGates{}, err
+	}
+	if table, ok := document["features"].(map[string]any); ok {
+		for feature, entries := range table {
+			gates.Table[feature] = tomlStringSlice(entries)
+		}
+	}
+	libPath := filepath.Join("src", "lib.rs")
+	if lib, ok := document["lib"].(map[string]any); ok && tomlString(lib["path"]) != "" {
+		libPath = filepath.FromSlash(tomlString(lib["path"]))
+	}
+	files, err := readCrateLibrarySources(dir, libPath)
+	if err != nil {
+		return crateFeatureGates{}, err
+	}
+	gateMacros := collectGateMacros(files)
+	for _, file := range files {
+		gates.scanFile(file, gateMacros)
+	}
+	return gates, nil
+}
+
+func readCrateLibrarySources(dir, libPath string) ([]crateSourceFile, error) {
+	srcDir := filepath.Join(dir, filepath.Dir(libPath))
+	libFile := filepath.Join(dir, libPath)
+	paths := make([]string, 0)
+	err := filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() {
+			if entry.Name() == "bin" && filepath.Dir(path) == srcDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".rs") {
+			paths = append(paths, path)
+		}
+		if len(paths) >= maxFeatureCrateFiles {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.SkipAll) {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Strings(paths)
+	files := make([]crateSourceFile, 0, len(paths))
+	for _, path := range paths {
+		if filepath.Dir(path) == srcDir && path != libFile && filepath.Base(path) == "main.rs" {
+			continue
+		}
+		content, err := safeio.ReadFileUnderLimit(dir, path, maxFeatureCrateFileBytes)
+		if errors.Is(err, safeio.ErrFileTooLarge) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			relative = path
+		}
+		files = append(files, crateSourceFile{
+			Path:    filepath.ToSlash(relative),
+			Module:  crateModulePath(srcDir, libFile, path),
+			Content: content,
+			Masked:  shared.MaskCommentsAndStringsForFile(content, path),
+		})
+	}
+	return files, nil
+}
+
+// crateModulePath maps a source file to the module path it defines, so
+// src/lib.rs is the crate root and src/fs/mod.rs and src/fs.rs are `fs`.
+func crateModulePath(srcDir, libFile, path string) string {
+	if path == libFile {
+		return ""
+	}
+	relative, err := filepath.Rel(srcDir, path)
+	if err != nil {
+		return ""
+	}
+	relative = strings.TrimSuffix(filepath.ToSlash(relative), ".rs")
+	if relative == "mod" {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(relative, "/mod"), "/", "::")
+}
+
+// collectGateMacros finds `macro_rules!` helpers such as tokio's `cfg_fs!`
+// that wrap each `$item` argument in a feature cfg.
+func collectGateMacros(files []crateSourceFile) map[string][]string {
+	gateMacros := make(map[string][]string)
+	for _, file := range files {
+		raw := strings.Split(string(file.Content), "\n")
+		masked := strings.Split(string(file.Masked), "\n")
+		for index := 0; index < len(masked) && index < len(raw); index++ {
+			match := macroRulesPattern.FindStringSubmatch(strings.TrimSpace(masked[index]))
+			if match == nil {
+				continue
+			}
+			features, itemMacro, end := macroBodyFeatures(raw, masked, index)
+			if itemMacro && len(features) > 0 {
+				gateMacros[match[1]] = mergeFeatureNames(gateMacros[match[1]], features)
+			}
+			index = end
+		}
+	}
+	return gateMacros
+}
+
+func macroBodyFeatures(raw, masked []string, start int) ([]string, bool, int) {
+	depth := 0
+	opened := false
+	itemMacro := false
+	features := make([]string, 0)
+	for index := start; index < len(masked) && index < len(raw); index++ {
+		line := strings.TrimSpace(masked[index])
+		if strings.Contains(line, ":item") {
+			itemMacro = true
+		}
+		if strings.HasPrefix(line, "#[cfg(") {
+			features = mergeFeatureNames(features, cfgAttributeFeatures(raw[index]))
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		opened = opened || strings.Contains(line, "{")
+		if opened && depth <= 0 {
+			return features, itemMacro, index
+		}
+	}
+	return features, itemMacro, len(masked)
+}
+
+func (g *crateFeatureGates) scanFile(file crateSourceFile, gateMacros map[string][]string) {
+	raw := strings.Split(string(file.Content), "\n")
+	masked := strings.Split(string(file.Masked), "\n")
+	frames := []gateFrame{{module: true, path: file.Module}}
+	var pending []string
+	exported := false
+	var use *gatedUse
+	for index := 0; index < len(masked) && index < len(raw); index++ {
+		line := strings.TrimSpace(masked[index])
+		location := file.Path + ":" + strconv.Itoa(index+1)
+		if use != nil {
+			use.text += " " + line
+			if strings.Contains(line, ";") {
+				g.recordUse(*use)
+				use = nil
+			}
+			continue
+		}
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#[cfg("):
+			pending = mergeFeatureNames(pending, cfgAttributeFeatures(raw[index]))
+			continue
+		case strings.HasPrefix(line, "#!["):
+			continue
+		case strings.HasPrefix(line, "#["):
+			exported = exported || strings.Contains(line, "macro_export")
+			continue
+		}
+
+		top := frames[len(frames)-1]
+		features := mergeFeatureNames(top.features, pending)
+		opensModule := false
+		childPath := top.path
+		if top.module {
+			switch {
+			case moduleBlockPattern.MatchString(line):
+				match := moduleBlockPattern.FindStringSubmatch(line)
+				opensModule = match[1] != ""
+				childPath = joinCratePath(top.path, match[2])
+				if opensModule {
+					g.record(features, childPath, location)
+				}
+			case gateMacroInvokePattern.MatchString(line) && len(gateMacros[gateMacroInvokePattern.FindStringSubmatch(line)[1]]) > 0:
+				opensModule = true
+				features = mergeFeatureNames(features, gateMacros[gateMacroInvokePattern.FindStringSubmatch(line)[1]])
+			case publicItemPattern.MatchString(line):
+				g.record(features, joinCratePath(top.path, publicItemPattern.FindStringSubmatch(line)[1]), location)
+			case publicUsePattern.MatchString(line) && len(features) > 0:
+				use = &gatedUse{features: features, module: top.path, location: location, text: publicUsePattern.ReplaceAllString(line, "")}
+				if strings.Contains(line, ";") {
+					g.recordUse(*use)
+					use = nil
+				}
+			case macroRulesPattern.MatchString(line) && exported:
+				g.record(features, macroRulesPattern.FindStringSubmatch(line)[1], location)
+			}
+		}
+		pending = nil
+		exported = false
+		frames = applyGateBraces(frames, line, gateFrame{module: opensModule, path: childPath, features: features})
+	}
+}
+
+// applyGateBraces pushes a frame for each `{` and pops one for each `}`. The
+// first brace on a line opens opener; any further braces open plain blocks.
+func applyGateBraces(frames []gateFrame, line string, opener gateFrame) []gateFrame {
+	first := true
+	for _, char := range line {
+		switch char {
+		case '{':
+			if first {
+				frames = append(frames, opener)
+				first = false
+				continue
+			}
+			frames = append(frames, gateFrame{})
+		case '}':
+			if len(frames) > 1 {
+				frames = frames[:len(frames)-1]
+			}
+		}
+	}
+	return frames
+}
+
+type gatedUse struct {
+	features []string
+	module   string
+	location string
+	text     string
+}
+
+// recordUse records the names a gated `pub use` re-exports. A glob re-export
+// makes its features opaque, since the names it brings in are unknown.
+func (g *crateFeatureGates) recordUse(use gatedUse) {
+	text := strings.TrimSpace(strings.SplitN(use.text, ";", 2)[0])
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '{' || r == '}' }) {
+		part = strings.TrimSpace(part)
+		name := lastPathSegment(part)
+		if fields := strings.Fields(part); len(fields) == 3 && fields[1] == "as" {
+			name = fields[2]
+		}
+		switch name {
+		case "", "self", "_":
+			continue
+		case "*":
+			for _, feature := range use.features {
+				g.Opaque[feature] = true
+			}
+			continue
+		}
+		g.record(use.features, joinCratePath(use.module, name), use.location)
+	}
+}
+
+func (g *crateFeatureGates) record(features []string, path, location string) {
+	for _, feature := range features {
+		g.Items[feature] = append(g.Items[feature], gatedItem{Path: path, Location: location})
+	}
+}
+
+// enables lists the features a feature turns on, including the implicit
+// feature of an optional dependency. `dep:` entries and weak `x?/y` entries
+// enable no feature of this crate.
+func (g crateFeatureGates) enables(feature string) []string {
+	enabled := make([]string, 0)
+	for _, entry := range g.Table[feature] {
+		switch {
+		case strings.HasPrefix(entry, "dep:"):
+			continue
+		case strings.Contains(entry, "?/"):
+			continue
+		case strings.Contains(entry, "/"):
+			if dependency := strings.SplitN(entry, "/", 2)[0]; g.hasFeature(dependency) {
+				enabled = append(enabled, dependency)
+			}
+		default:
+			enabled = append(enabled, entry)
+		}
+	}
+	return enabled
+}
+
+func (g crateFeatureGates) hasFeature(feature string) bool {
+	_, ok := g.Table[feature]
+	return ok
+}
+
+// closure returns feature and every feature it enables transitively.
+func (g crateFeatureGates) closure(feature string) []string {
+	seen := map[string]struct{}{feature: {}}
+	queue := []string{feature}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, enabled := range g.enables(current) {
+			if _, ok := seen[enabled]; ok {
+				continue
+			}
+			seen[enabled] = struct{}{}
+			queue = append(queue, enabled)
+		}
+	}
+	return shared.SortedKeys(seen)
+}
+
+// aggregate reports whether feature only bundles other features, like
+// tokio's "full", rather than gating items itself.
+func (g crateFeatureGates) aggregate(feature string) bool {
+	return len(g.Items[feature]) == 0 && !g.Opaque[feature] && len(g.enables(feature)) > 0
+}
+
+func cfgAttributeFeatures(line string) []string {
+	if strings.Contains(line, "not(") {
+		return nil
+	}
+	features := make([]string, 0)
+	for _, match := range cfgFeaturePattern.FindAllStringSubmatch(line, -1) {
+		features = append(features, match[1])
+	}
+	return features
+}
+
+func mergeFeatureNames(left, right []string) []string {
+	if len(right) == 0 {
+		return left
+	}
+	merged := append(append([]string(nil), left...), right...)
+	sort.Strings(merged)
+	return dedupeStrings(merged)
+}
+
+func joinCratePath(module, name string) string {
+	if module == "" {
+		return name
+	}
+	return module + "::" + name
+}
@@ -0,0 +1,565 @@
+package rust
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+const (
+	rustCargoFeaturesPreviewFeature = "rust-cargo-features-preview"
+	maxFeatureCrateFiles            = 1024
+	maxFeatureCrateFileBytes        = 1 << 20
+	maxFeatureEvidenceSamples       = 3
+	defaultCargoFeature             = "default"
+)
+
+// cargoFeatureRequest is what one manifest entry asks of a crate: the
+// features it enables and whether it keeps the crate's default features.
+type cargoFeatureRequest struct {
+	Alias           string
+	Dependency      string
+	Package         string
+	Declaration     string
+	Path            string
+	Features        []string
+	DefaultFeatures bool
+}
+
+// cargoFeatureUsage is the evidence-backed outcome for one request: features
+// to drop, aggregate features to narrow, and whether default features can be
+// turned off.
+type cargoFeatureUsage struct {
+	Declaration    string
+	Source         string
+	Drop           []string
+	Narrow         []cargoFeatureNarrowing
+	DisableDefault bool
+	KeepDefault    []string
+	Referenced     []string
+	Unreferenced   []string
+}
+
+type cargoFeatureNarrowing struct {
+	Feature string
+	With    []string
+}
+
+type cargoLockedPackage struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+}
+
+// defaultCargoRegistrySources returns the extracted crate cache Cargo fills
+// when it downloads registry dependencies.
+func defaultCargoRegistrySources() string {
+	cargoHome := strings.TrimSpace(os.Getenv("CARGO_HOME"))
+	if cargoHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return ""
+		}
+		cargoHome = filepath.Join(home, ".cargo")
+	}
+	return filepath.Join(cargoHome, "registry", "src")
+}
+
+// analyseCargoFeatures compares the features each manifest enables with the
+// cfg-gated items of the crate's vendored or cached sources and the crate
+// paths the repository references. Crates without local sources are skipped.
+func analyseCargoFeatures(repoPath string, manifestPaths []string, referenced map[string]map[string]struct{}, registrySources string) (map[string][]cargoFeatureUsage, []string) {
+	requests := collectCargoFeatureRequests(repoPath, manifestPaths)
+	locked := readCargoLockVersions(repoPath, manifestPaths)
+	usages := make(map[string][]cargoFeatureUsage)
+	gatesByDir := make(map[string]crateFeatureGates)
+	missing := make(map[string]struct{})
+	warnings := make([]string, 0)
+	for _, request := range requests {
+		dir, source := locateCrateSource(repoPath, request, locked[request.Package], registrySources)
+		if dir == "" {
+			missing[request.Package] = struct{}{}
+			continue
+		}
+		gates, ok := gatesByDir[dir]
+		if !ok {
+			var err error
+			gates, err = readCrateFeatureGates(dir)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("unable to read Cargo features of %s: %v", source, err))
+				continue
+			}
+			gatesByDir[dir] = gates
+		}
+		usage := evaluateCargoFeatures(request, gates, referenced[request.Dependency])
+		if usage.hasFindings() {
+			usage.Source = source
+			usages[request.Dependency] = append(usages[request.Dependency], usage)
+		}
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) > maxWarningSamples {
+			names = append(names[:maxWarningSamples], "...")
+		}
+		warnings = append(warnings, fmt.Sprintf("Cargo feature analysis skipped %d crate(s) without vendored or registry sources: %s", len(missing), strings.Join(names, ", ")))
+	}
+	return usages, warnings
+}
+
+// collectCargoFeatureRequests reads every dependency entry of the manifests,
+// resolving `workspace = true` entries against the root [workspace.dependencies].
+func collectCargoFeatureRequests(repoPath string, manifestPaths []string) []cargoFeatureRequest {
+	workspace := make(map[string]cargoFeatureRequest)
+	rootManifest := filepath.Join(repoPath, cargoTomlName)
+	if content, err := safeio.ReadFileUnder(repoPath, rootManifest); err == nil {
+		if document, err := parseCargoManifestDocument(content); err == nil {
+			if table, ok := document["workspace"].(map[string]any); ok {
+				for _, request := range cargoFeatureRequestsFromTable(repoPath, rootManifest, string(content), table[dependenciesSection], nil) {
+					workspace[normalizeDependencyID(request.Alias)] = request
+				}
+			}
+		}
+	}
+	requests := make([]cargoFeatureRequest, 0)
+	for _, manifestPath := range manifestPaths {
+		content, err := safeio.ReadFileUnder(repoPath, manifestPath)
+		if err != nil {
+			continue
+		}
+		document, err := parseCargoManifestDocument(content)
+		if err != nil {
+			continue
+		}
+		tables := []any{document[dependenciesSection], document[devDependenciesSection], document[buildDependenciesSection]}
+		if target, ok := document["target"].(map[string]any); ok {
+			for _, key := range sortedMapKeys(target) {
+				if targetTable, ok := target[key].(map[string]any); ok {
+					tables = append(tables, targetTable[dependenciesSection], targetTable[devDependenciesSection], targetTable[buildDependenciesSection])
+				}
+			}
+		}
+		for _, table := range tables {
+			requests = append(requests, cargoFeatureRequestsFromTable(repoPath, manifestPath, string(content), table, workspace)...)
+		}
+	}
+	return requests
+}
+
+func cargoFeatureRequestsFromTable(repoPath, manifestPath, content string, value any, workspace map[string]cargoFeatureRequest) []cargoFeatureRequest {
+	table, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	requests := make([]cargoFeatureRequest, 0, len(table))
+	for _, alias := range sortedMapKeys(table) {
+		request := cargoFeatureRequest{Alias: alias, Dependency: normalizeDependencyID(alias), Package: alias, DefaultFeatures: true}
+		if fields, ok := table[alias].(map[string]any); ok {
+			if inherited, ok := fields["workspace"].(bool); ok && inherited {
+				base, found := workspace[request.Dependency]
+				if !found {
+					continue
+				}
+				request = base
+				request.Alias = alias
+				request.Features = append([]string(nil), base.Features...)
+			}
+			applyCargoFeatureFields(&request, fields, filepath.Dir(manifestPath))
+		}
+		request.Declaration = relativeManifestPath(repoPath, manifestPath) + ":" + strconv.Itoa(cargoDependencyLine(content, alias))
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+func applyCargoFeatureFields(request *cargoFeatureRequest, fields map[string]any, manifestDir string) {
+	if pkg := tomlString(fields["package"]); pkg != "" {
+		request.Dependency = normalizeDependencyID(pkg)
+		request.Package = pkg
+	}
+	if path := tomlString(fields["path"]); path != "" {
+		request.Path = filepath.Join(manifestDir, filepath.FromSlash(path))
+	}
+	for _, key := range []string{"default-features", "default_features"} {
+		if enabled, ok := fields[key].(bool); ok {
+			request.DefaultFeatures = enabled
+		}
+	}
+	request.Features = mergeFeatureNames(request.Features, tomlStringSlice(fields["features"]))
+}
+
+// cargoDependencyLine finds the line declaring alias, either as a key inside a
+// dependency table or as its own [dependencies.alias] table.
+func cargoDependencyLine(content, alias string) int {
+	quoted := regexp.QuoteMeta(alias)
+	pattern := regexp.MustCompile(`(?m)^\s*(?:\[[^\]\n]*dependencies\.` + quoted + `\s*\]|"?` + quoted + `"?\s*=)`)
+	location := pattern.FindStringIndex(content)
+	if location == nil {
+		return 1
+	}
+	return strings.Count(content[:location[0]], "\n") + 1
+}
+
+// readCargoLockVersions lists the locked versions of each package from the
+// Cargo.lock files next to the repository root and each manifest.
+func readCargoLockVersions(repoPath string, manifestPaths []string) map[string][]string {
+	dirs := []string{repoPath}
+	for _, manifestPath := range manifestPaths {
+		dirs = append(dirs, filepath.Dir(manifestPath))
+	}
+	versions := make(map[string][]string)
+	for _, dir := range uniquePaths(dirs) {
+		content, err := safeio.ReadFileUnder(repoPath, filepath.Join(dir, cargoLockName))
+		if err != nil {
+			continue
+		}
+		var lockfile struct {
+			Package []cargoLockedPackage `toml:"package"`
+		}
+		if toml.Unmarshal(content, &lockfile) != nil {
+			continue
+		}
+		for _, pkg := range lockfile.Package {
+			versions[pkg.Name] = dedupeStrings(append(versions[pkg.Name], pkg.Version))
+		}
+	}
+	return versions
+}
+
+// locateCrateSource finds a crate's sources in a path dependency, `cargo
+// vendor` output, or the registry source cache, preferring locked versions
+// and otherwise the newest extracted version.
+func locateCrateSource(repoPath string, request cargoFeatureRequest, locked []string, registrySources string) (string, string) {
+	if request.Path != "" {
+		if isSubPath(repoPath, request.Path) && hasCargoManifest(request.Path) {
+			return request.Path, relativeManifestPath(repoPath, request.Path)
+		}
+		return "", ""
+	}
+	locked = sortedCrateVersions(locked)
+	vendorDir := filepath.Join(repoPath, "vendor")
+	candidates := make([]string, 0, len(locked)+1)
+	for _, version := range locked {
+		candidates = append(candidates, filepath.Join(vendorDir, request.Package+"-"+version))
+	}
+	candidates = append(candidates, filepath.Join(vendorDir, request.Package))
+	for _, candidate := range candidates {
+		if hasCargoManifest(candidate) {
+			return candidate, relativeManifestPath(repoPath, candidate)
+		}
+	}
+	if registrySources == "" {
+		return "", ""
+	}
+	matches := make([]string, 0)
+	for _, version := range locked {
+		found, _ := filepath.Glob(filepath.Join(registrySources, "*", request.Package+"-"+version))
+		sort.Strings(found)
+		matches = append(matches, found...)
+	}
+	if len(locked) == 0 {
+		matches = newestRegistryCrates(registrySources, request.Package)
+	}
+	for _, match := range matches {
+		if hasCargoManifest(match) {
+			label, err := filepath.Rel(registrySources, match)
+			if err != nil {
+				label = match
+			}
+			return match, "CARGO_HOME/registry/src/" + filepath.ToSlash(label)
+		}
+	}
+	return "", ""
+}
+
+func newestRegistryCrates(registrySources, name string) []string {
+	found, _ := filepath.Glob(filepath.Join(registrySources, "*", name+"-*"))
+	byVersion := make(map[string][]string)
+	versions := make([]string, 0, len(found))
+	for _, match := range found {
+		version := strings.TrimPrefix(filepath.Base(match), name+"-")
+		if version == "" || version[0] < '0' || version[0] > '9' {
+			continue
+		}
+		byVersion[version] = append(byVersion[version], match)
+		versions = append(versions, version)
+	}
+	matches := make([]string, 0, len(found))
+	for _, version := range sortedCrateVersions(versions) {
+		sort.Strings(byVersion[version])
+		matches = append(matches, byVersion[version]...)
+	}
+	return matches
+}
+
+// sortedCrateVersions orders versions newest first. A release outranks its
+// prereleases.
+func sortedCrateVersions(versions []string) []string {
+	sorted := dedupeStrings(versions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return shared.CompareVersionStrings(sorted[i], sorted[j]) > 0
+	})
+	return sorted
+}
+
+func hasCargoManifest(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, cargoTomlName))
+	return err == nil && !info.IsDir()
+}
+
+// evaluateCargoFeatures judges each requested feature and each default
+// feature by whether any item it gates, directly or through the features it
+// enables, is referenced. Features that gate nothing the scan can name are
+// kept, as there is no evidence they are unused.
+func evaluateCargoFeatures(request cargoFeatureRequest, gates crateFeatureGates, referenced map[string]struct{}) cargoFeatureUsage {
+	evaluation := newFeatureEvaluation(gates, referenced)
+	usage := cargoFeatureUsage{Declaration: request.Declaration}
+	considered := make([]string, 0)
+	for _, feature := range request.Features {
+		if feature == defaultCargoFeature {
+			continue
+		}
+		considered = append(considered, feature)
+		used, judged := evaluation.status(feature)
+		switch {
+		case !used && judged:
+			usage.Drop = append(usage.Drop, feature)
+		case used && gates.aggregate(feature):
+			if keep, narrowed := evaluation.narrow(feature); narrowed {
+				usage.Narrow = append(usage.Narrow, cargoFeatureNarrowing{Feature: feature, With: keep})
+			}
+		}
+	}
+	if request.DefaultFeatures && len(gates.Table[defaultCargoFeature]) > 0 {
+		keep := make([]string, 0)
+		for _, feature := range gates.enables(defaultCargoFeature) {
+			considered = append(considered, feature)
+			if used, judged := evaluation.status(feature); !used && judged {
+				usage.DisableDefault = true
+				continue
+			}
+			if !slices.Contains(request.Features, feature) {
+				keep = append(keep, feature)
+			}
+		}
+		if usage.DisableDefault {
+			usage.KeepDefault = keep
+		}
+	}
+	if usage.hasFindings() {
+		usage.Referenced, usage.Unreferenced = evaluation.evidence(considered)
+	}
+	return usage
+}
+
+func (u cargoFeatureUsage) hasFindings() bool {
+	return len(u.Drop) > 0 || len(u.Narrow) > 0 || u.DisableDefault
+}
+
+type featureEvaluation struct {
+	gates      crateFeatureGates
+	referenced map[string][]string
+}
+
+func newFeatureEvaluation(gates crateFeatureGates, referenced map[string]struct{}) featureEvaluation {
+	paths := make([]string, 0, len(referenced))
+	for path := range referenced {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	evaluation := featureEvaluation{gates: gates, referenced: make(map[string][]string)}
+	for feature, items := range gates.Items {
+		for _, item := range items {
+			if path, ok := referencedCratePath(item.Path, paths); ok {
+				evaluation.referenced[feature] = dedupeStrings(append(evaluation.referenced[feature], path))
+			}
+		}
+	}
+	return evaluation
+}
+
+// referencedCratePath reports whether any referenced path names item, lies
+// inside it, or imports a module that contains it. An empty path is a glob
+// import of the crate root and references everything.
+func referencedCratePath(item string, paths []string) (string, bool) {
+	for _, path := range paths {
+		if path == "" || path == item || strings.HasPrefix(path, item+"::") || strings.HasPrefix(item, path+"::") {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// status reports whether feature is used, and whether an unused verdict is
+// backed by evidence: every feature it enables gates nameable items or only
+// bundles other features.
+func (e featureEvaluation) status(feature string) (bool, bool) {
+	closure := e.gates.closure(feature)
+	for _, enabled := range closure {
+		if len(e.referenced[enabled]) > 0 {
+			return true, true
+		}
+	}
+	gated := false
+	for _, enabled := range closure {
+		if e.gates.Opaque[enabled] || (len(e.gates.Items[enabled]) == 0 && !e.gates.aggregate(enabled)) {
+			return false, false
+		}
+		gated = gated || len(e.gates.Items[enabled]) > 0
+	}
+	return false, gated
+}
+
+// narrow returns the smallest set of features enabled by an aggregate feature
+// that keeps every referenced or unjudged feature, and whether it is smaller
+// than the aggregate.
+func (e featureEvaluation) narrow(feature string) ([]string, bool) {
+	leaves := make([]string, 0)
+	keep := make([]string, 0)
+	for _, enabled := range e.gates.closure(feature) {
+		if e.gates.aggregate(enabled) {
+			continue
+		}
+		leaves = append(leaves, enabled)
+		if used, judged := e.status(enabled); used || !judged {
+			keep = append(keep, enabled)
+		}
+	}
+	if len(keep) == len(leaves) {
+		return nil, false
+	}
+	minimal := make([]string, 0, len(keep))
+	for _, candidate := range keep {
+		implied := false
+		for _, other := range keep {
+			if other != candidate && slices.Contains(e.gates.closure(other), candidate) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			minimal = append(minimal, candidate)
+		}
+	}
+	return minimal, true
+}
+
+func (e featureEvaluation) evidence(features []string) ([]string, []string) {
+	seen := make(map[string]struct{})
+	referenced := make([]string, 0)
+	unreferenced := make([]string, 0)
+	for _, feature := range features {
+		for _, enabled := range e.gates.closure(feature) {
+			if _, ok := seen[enabled]; ok || len(e.gates.Items[enabled]) == 0 {
+				continue
+			}
+			seen[enabled] = struct{}{}
+			if paths := e.referenced[enabled]; len(paths) > 0 {
+				referenced = append(referenced, fmt.Sprintf("%s (%s)", enabled, strings.Join(limitStrings(paths, maxFeatureEvidenceSamples), ", ")))
+				continue
+			}
+			items := e.gates.Items[enabled]
+			unreferenced = append(unreferenced, fmt.Sprintf("%s (%d gated item(s), e.g. %s at %s)", enabled, len(items), items[0].Path, items[0].Location))
+		}
+	}
+	sort.Strings(referenced)
+	sort.Strings(unreferenced)
+	return referenced, unreferenced
+}
+
+// applyCargoFeatureUsage adds feature recommendations for each manifest entry
+// of the dependency, with the referenced and unreferenced gates as evidence.
+func applyCargoFeatureUsage(dep *report.DependencyReport, usages []cargoFeatureUsage) {
+	unused := make([]string, 0)
+	for _, usage := range usages {
+		rationale := usage.rationale()
+		if len(usage.Drop) > 0 {
+			unused = append(unused, usage.Drop...)
+			dep.Recommendations = append(dep.Recommendations, report.Recommendation{
+				Code:      "drop-unused-cargo-features",
+				Priority:  "medium",
+				Message:   fmt.Sprintf("Remove Cargo features %s of %q in %s; none of the items they gate are referenced.", tomlList(usage.Drop), dep.Name, usage.Declaration),
+				Rationale: rationale,
+			})
+		}
+		for _, narrowing := range usage.Narrow {
+			unused = append(unused, narrowing.Feature)
+			dep.Recommendations = append(dep.Recommendations, report.Recommendation{
+				Code:      "narrow-cargo-features",
+				Priority:  "medium",
+				Message:   fmt.Sprintf("Replace Cargo feature %q of %q in %s with %s.", narrowing.Feature, dep.Name, usage.Declaration, tomlList(narrowing.With)),
+				Rationale: rationale,
+			})
+		}
+		if usage.DisableDefault {
+			unused = append(unused, defaultCargoFeature)
+			message := fmt.Sprintf("Set default-features = false for %q in %s", dep.Name, usage.Declaration)
+			if len(usage.KeepDefault) > 0 {
+				message += fmt.Sprintf(" and enable only %s", tomlList(usage.KeepDefault))
+			}
+			dep.Recommendations = append(dep.Recommendations, report.Recommendation{
+				Code:      "disable-default-features",
+				Priority:  "medium",
+				Message:   message + ".",
+				Rationale: rationale,
+			})
+		}
+	}
+	if len(unused) == 0 {
+		return
+	}
+	sort.Strings(unused)
+	dep.RiskCues = append(dep.RiskCues, report.RiskCue{
+		Code:     "unused-cargo-features",
+		Severity: "medium",
+		Message:  fmt.Sprintf("enabled Cargo features gate items the code does not reference: %s", strings.Join(dedupeStrings(unused), ", ")),
+	})
+}
+
+func (u cargoFeatureUsage) rationale() string {
+	parts := []string{"Unused features add compile time and code size; evidence from " + u.Source}
+	if len(u.Referenced) > 0 {
+		parts = append(parts, "referenced: "+strings.Join(u.Referenced, "; "))
+	}
+	if len(u.Unreferenced) > 0 {
+		parts = append(parts, "unreferenced: "+strings.Join(u.Unreferenced, "; "))
+	}
+	return strings.Join(parts, ". ") + "."
+}
+
+func tomlList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func limitStrings(values []string, limit int) []string {
+	if len(values) > limit {
+		return values[:limit]
+	}
+	return values
+}
+
+func sortedMapKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
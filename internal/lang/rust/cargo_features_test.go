@@ -0,0 +1,248 @@
+package rust
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+func TestReadCrateFeatureGatesScansGatedItems(t *testing.T) {
+	crate := t.TempDir()
+	writeFile(t, filepath.Join(crate, testCargoToml), `[package]
+name = "acme-rt"
+version = "1.2.0"
+
+[features]
+default = ["std", "fs"]
+full = ["fs", "net", "sync", "macros"]
+std = []
+fs = []
+net = []
+sync = []
+macros = ["dep:acme-rt-macros"]
+`)
+	writeFile(t, filepath.Join(crate, "src", testRustLibRS), `#[macro_use]
+mod macros;
+
+cfg_net! {
+    pub mod net;
+}
+
+#[cfg(feature = "fs")]
+#[cfg_attr(docsrs, doc(cfg(feature = "fs")))]
+pub mod fs;
+
+#[cfg(feature = "sync")]
+pub mod sync;
+
+#[cfg(not(feature = "sync"))]
+pub mod unsync;
+
+#[cfg(feature = "macros")]
+pub use acme_rt_macros::{
+    main,
+    test as test_attr,
+};
+
+pub struct Runtime;
+
+impl Runtime {
+    #[cfg(feature = "fs")]
+    pub fn block_on_fs(&self) {}
+}
+`)
+	writeFile(t, filepath.Join(crate, "src", "macros.rs"), `macro_rules! cfg_net {
+    ($($item:item)*) => {
+        $(
+            #[cfg(feature = "net")]
+            $item
+        )*
+    }
+}
+`)
+	writeFile(t, filepath.Join(crate, "src", "net", "mod.rs"), "pub struct TcpStream;\n")
+
+	gates, err := readCrateFeatureGates(crate)
+	if err != nil {
+		t.Fatalf("read crate feature gates: %v", err)
+	}
+	paths := func(feature string) []string {
+		result := make([]string, 0)
+		for _, item := range gates.Items[feature] {
+			result = append(result, item.Path)
+		}
+		return result
+	}
+	if got := paths("fs"); !slices.Equal(got, []string{"fs"}) {
+		t.Fatalf("expected only the fs module gated by fs, got %#v", got)
+	}
+	if got := paths("net"); !slices.Equal(got, []string{"net"}) {
+		t.Fatalf("expected cfg_net! to gate the net module, got %#v", got)
+	}
+	if got := paths("macros"); !slices.Equal(got, []string{"main", "test_attr"}) {
+		t.Fatalf("expected multi-line re-exports gated by macros, got %#v", got)
+	}
+	if got := paths("sync"); !slices.Equal(got, []string{"sync"}) {
+		t.Fatalf("expected negated cfg to be ignored, got %#v", got)
+	}
+	if !gates.aggregate("full") || gates.aggregate("std") {
+		t.Fatalf("expected full to be an aggregate feature and std not")
+	}
+	if got := gates.closure(defaultCargoFeature); !slices.Equal(got, []string{"default", "fs", "std"}) {
+		t.Fatalf("unexpected default closure %#v", got)
+	}
+}
+
+func TestAdapterAnalyseRecommendsCargoFeatureChanges(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, testCargoToml), strings.Join([]string{
+		testCargoSectionPackage,
+		`name = "app"`,
+		`version = "0.1.0"`,
+		"",
+		testCargoSectionDependencies,
+		`acme-rt = { version = "1", features = ["full"] }`,
+		`acme_extra = { version = "0.3", default-features = false, features = ["json", "client"] }`,
+		"",
+	}, "\n"))
+	writeFile(t, filepath.Join(repo, cargoLockName), `version = 3
+
+[[package]]
+name = "acme-rt"
+version = "1.1.0"
+
+[[package]]
+name = "acme-rt"
+version = "1.2.0"
+`)
+	writeFile(t, filepath.Join(repo, "src", testRustMainRS), `use acme_rt::sync::Mutex;
+use acme_extra::Client;
+
+#[acme_rt::main]
+async fn main() {
+    let _lock = Mutex::new(Client::default());
+}
+`)
+
+	rt := filepath.Join(repo, "vendor", "acme-rt-1.2.0")
+	writeFile(t, filepath.Join(rt, testCargoToml), `[package]
+name = "acme-rt"
+
+[features]
+default = ["std", "fs"]
+full = ["fs", "net", "sync", "macros"]
+std = []
+fs = []
+net = []
+sync = []
+macros = []
+`)
+	writeFile(t, filepath.Join(rt, "src", testRustLibRS), `#[cfg(feature = "fs")]
+pub mod fs;
+#[cfg(feature = "net")]
+pub mod net;
+#[cfg(feature = "sync")]
+pub mod sync;
+#[cfg(feature = "macros")]
+pub use acme_rt_macros::main;
+`)
+
+	cargoHome := t.TempDir()
+	t.Setenv("CARGO_HOME", cargoHome)
+	registry := filepath.Join(cargoHome, "registry", "src", "index.crates.io-6f17d22bba15001f")
+	writeFile(t, filepath.Join(registry, "acme_extra-0.2.0", testCargoToml), "[package]\nname = \"acme_extra\"\n")
+	extra := filepath.Join(registry, "acme_extra-0.3.1")
+	writeFile(t, filepath.Join(extra, testCargoToml), `[package]
+name = "acme_extra"
+
+[features]
+json = []
+client = []
+`)
+	writeFile(t, filepath.Join(extra, "src", testRustLibRS), `#[cfg(feature = "client")]
+pub struct Client;
+#[cfg(feature = "json")]
+pub fn to_json() {}
+`)
+
+	reportData, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10, Features: cargoFeaturesPreview(t)})
+	if err != nil {
+		t.Fatalf("analyse: %v", err)
+	}
+	rtReport := rustDependencyReport(t, reportData.Dependencies, "acme-rt")
+	narrow := rustRecommendation(rtReport, "narrow-cargo-features")
+	if narrow == nil || !strings.Contains(narrow.Message, `Replace Cargo feature "full" of "acme-rt" in Cargo.toml:6 with ["macros", "sync"]`) {
+		t.Fatalf("expected full narrowed to macros and sync, got %#v", rtReport.Recommendations)
+	}
+	if !strings.Contains(narrow.Rationale, filepath.Join("vendor", "acme-rt-1.2.0")) || !strings.Contains(narrow.Rationale, "sync (sync::Mutex)") || !strings.Contains(narrow.Rationale, "net (1 gated item(s), e.g. net at src/lib.rs:4)") {
+		t.Fatalf("expected vendored source evidence, got %q", narrow.Rationale)
+	}
+	disable := rustRecommendation(rtReport, "disable-default-features")
+	if disable == nil || !strings.Contains(disable.Message, `and enable only ["std"]`) {
+		t.Fatalf("expected default features disabled keeping unjudged std, got %#v", rtReport.Recommendations)
+	}
+
+	extraReport := rustDependencyReport(t, reportData.Dependencies, "acme-extra")
+	drop := rustRecommendation(extraReport, "drop-unused-cargo-features")
+	if drop == nil || !strings.Contains(drop.Message, `Remove Cargo features ["json"]`) || !strings.Contains(drop.Rationale, "CARGO_HOME/registry/src/index.crates.io-6f17d22bba15001f/acme_extra-0.3.1") {
+		t.Fatalf("expected json dropped using the newest registry source, got %#v", extraReport.Recommendations)
+	}
+	if rustRecommendation(extraReport, "disable-default-features") != nil {
+		t.Fatalf("did not expect default-features advice when already disabled")
+	}
+
+	baseline, err := NewAdapter().Analyse(context.Background(), language.Request{RepoPath: repo, TopN: 10})
+	if err != nil {
+		t.Fatalf("analyse without preview: %v", err)
+	}
+	if rustRecommendation(rustDependencyReport(t, baseline.Dependencies, "acme-rt"), "narrow-cargo-features") != nil {
+		t.Fatalf("expected no Cargo feature advice without the preview flag")
+	}
+}
+
+func TestAnalyseCargoFeaturesWarnsAboutMissingSources(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, testCargoToml), "[package]\nname = \"app\"\n\n[dependencies]\nserde = \"1\"\n")
+	_, warnings := analyseCargoFeatures(repo, []string{filepath.Join(repo, testCargoToml)}, nil, filepath.Join(t.TempDir(), "missing"))
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipped 1 crate(s) without vendored or registry sources: serde") {
+		t.Fatalf("expected missing source warning, got %#v", warnings)
+	}
+}
+
+func cargoFeaturesPreview(t *testing.T) featureflags.Set {
+	t.Helper()
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{rustCargoFeaturesPreviewFeature},
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	return features
+}
+
+func rustDependencyReport(t *testing.T, dependencies []report.DependencyReport, name string) report.DependencyReport {
+	t.Helper()
+	for _, dep := range dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	t.Fatalf("expected %s report, got %#v", name, dependencies)
+	return report.DependencyReport{}
+}
+
+func rustRecommendation(dep report.DependencyReport, code string) *report.Recommendation {
+	for index := range dep.Recommendations {
+		if dep.Recommendations[index].Code == code {
+			return &dep.Recommendations[index]
+		}
+	}
+	return nil
+}
@@ -98,6 +98,7 @@ func buildDependencyReport(dependency string, scan scanResult, minUsageThreshold
 			Rationale: "Unused dependencies increase attack and maintenance surface.",
 		})
 	}
+	applyCargoFeatureUsage(&dep, scan.CargoFeatures[dependency])
 	shared.SortRiskCues(dep.RiskCues)
 	shared.SortRecommendations(dep.Recommendations, recommendationPriorityRank)
 	return dep
@@ -6,17 +6,32 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"strings"
 
 	"github.com/ben-ranford/lopper/internal/lang/shared"
 	"github.com/ben-ranford/lopper/internal/safeio"
 )
 
+// scanOptions enables optional collection during the source scan.
+// referencedPaths records every crate path the sources name, for Cargo
+// feature analysis.
+type scanOptions struct {
+	referencedPaths bool
+}
+
 func scanRepo(ctx context.Context, repoPath string, manifestPaths []string, depLookup map[string]dependencyInfo, renamedAliases map[string][]string) (scanResult, error) {
+	return scanRepoWithOptions(ctx, repoPath, manifestPaths, depLookup, renamedAliases, scanOptions{})
+}
+
+func scanRepoWithOptions(ctx context.Context, repoPath string, manifestPaths []string, depLookup map[string]dependencyInfo, renamedAliases map[string][]string, options scanOptions) (scanResult, error) {
 	result := scanResult{
 		UnresolvedImports:   make(map[string]int),
 		RenamedAliasesByDep: renamedAliases,
 		LocalModuleCache:    make(map[string]bool),
 	}
+	if options.referencedPaths {
+		result.ReferencedPaths = make(map[string]map[string]struct{})
+	}
 	roots := scanRoots(manifestPaths, repoPath)
 	scannedFiles := make(map[string]struct{})
 	fileCount := 0
@@ -71,5 +86,36 @@ func scanRustSourceFile(repoPath string, crateRoot string, path string, depLooku
 	if macroInvokePattern.Match(content) {
 		result.MacroAmbiguityDetected = true
 	}
+	if result.ReferencedPaths != nil {
+		recordReferencedPaths(content, relativePath, crateRoot, imports, depLookup, result)
+	}
 	return nil
 }
+
+// recordReferencedPaths collects the crate-relative paths each dependency is
+// used through, from `use` declarations and fully qualified paths in code. A
+// glob import of the crate root is recorded as the empty path.
+func recordReferencedPaths(content []byte, filePath, crateRoot string, imports []importBinding, depLookup map[string]dependencyInfo, result *scanResult) {
+	for _, imported := range imports {
+		_, rest, _ := strings.Cut(imported.Module, "::")
+		if rest != "" || imported.Wildcard {
+			addReferencedPath(result, imported.Dependency, rest)
+		}
+	}
+	masked := shared.MaskCommentsAndStringsForFile(content, filePath)
+	for _, match := range qualifiedPathPattern.FindAllSubmatch(masked, -1) {
+		root := string(match[1])
+		info, ok := depLookup[normalizeDependencyID(root)]
+		if !ok || info.LocalPath || isLocalRustModuleWithCache(result, crateRoot, root) {
+			continue
+		}
+		addReferencedPath(result, info.Canonical, strings.TrimPrefix(string(match[2]), "::"))
+	}
+}
+
+func addReferencedPath(result *scanResult, dependency, path string) {
+	if result.ReferencedPaths[dependency] == nil {
+		result.ReferencedPaths[dependency] = make(map[string]struct{})
+	}
+	result.ReferencedPaths[dependency][path] = struct{}{}
+}
@@ -47,6 +47,8 @@ type scanResult struct {
 	MacroAmbiguityDetected   bool
 	SkippedLargeFiles        int
 	SkippedFilesByBoundLimit bool
+	ReferencedPaths          map[string]map[string]struct{}
+	CargoFeatures            map[string][]cargoFeatureUsage
 }
 
 type useImportContext struct {
@@ -67,7 +69,8 @@ type usePathEntry struct {
 }
 
 var (
-	macroInvokePattern = regexp.MustCompile(`(?m)\b[A-Za-z_][A-Za-z0-9_]*!\s*(?:\(|\{|\[)`)
+	macroInvokePattern   = regexp.MustCompile(`(?m)\b[A-Za-z_][A-Za-z0-9_]*!\s*(?:\(|\{|\[)`)
+	qualifiedPathPattern = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)((?:::[A-Za-z_][A-Za-z0-9_]*)+)`)
 )
 
 var rustStdRoots = map[string]bool{
@@ -72,3 +72,23 @@ func uniqueNormalizedStrings(values []string, normalize func(string) string) []s
 	}
 	return unique
 }
+
+// CompareVersionStrings orders versions by semver precedence. Versions semver
+// rejects, such as legacy four-part NuGet versions, sort below valid ones and
+// by text among themselves, so sorts over mixed folders stay total.
+func CompareVersionStrings(left, right string) int {
+	order, ok := report.CompareSemanticVersions(left, right)
+	if ok {
+		return order
+	}
+	_, leftValid := report.CompareSemanticVersions(left, left)
+	_, rightValid := report.CompareSemanticVersions(right, right)
+	switch {
+	case leftValid && !rightValid:
+		return 1
+	case rightValid && !leftValid:
+		return -1
+	default:
+		return cmp.Compare(left, right)
+	}
+}
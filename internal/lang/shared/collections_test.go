@@ -54,6 +54,15 @@ func TestSortRecommendations(t *testing.T) {
 	}
 }
 
+func TestCompareVersionStrings(t *testing.T) {
+	versions := []string{"4.0.0.1", "1.0.0-beta.10", "1.0.0", "1.0.0-beta.2", "0.9", "4.0.0.0"}
+	slices.SortFunc(versions, CompareVersionStrings)
+	want := []string{"4.0.0.0", "4.0.0.1", "0.9", "1.0.0-beta.2", "1.0.0-beta.10", "1.0.0"}
+	if !slices.Equal(versions, want) {
+		t.Fatalf("unexpected version order: %#v", versions)
+	}
+}
+
 func TestTopCountKeys(t *testing.T) {
 	if got := TopCountKeys(nil, 3); len(got) != 0 {
 		t.Fatalf("expected nil result for empty counts, got %#v", got)
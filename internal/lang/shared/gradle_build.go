@@ -11,15 +11,17 @@ import (
 )
 
 type GradleDependencyCoordinate struct {
-	Group    string
-	Artifact string
-	Version  string
+	Group         string
+	Artifact      string
+	Version       string
+	Configuration string
 }
 
 type gradleCatalogReference struct {
 	catalogName           string
 	alias                 string
 	bundle                bool
+	configuration         string
 	unsupportedExpression string
 }
 
@@ -35,6 +37,8 @@ var gradleDependencyConfigurations = map[string]struct{}{
 	"kaptAndroidTest":           {},
 	"kaptTest":                  {},
 	"ksp":                       {},
+	"kspAndroidTest":            {},
+	"kspTest":                   {},
 	"releaseImplementation":     {},
 	"runtimeOnly":               {},
 	"testAnnotationProcessor":   {},
@@ -43,6 +47,25 @@ var gradleDependencyConfigurations = map[string]struct{}{
 	"testRuntimeOnly":           {},
 }
 
+var gradleAnnotationProcessorConfigurations = map[string]struct{}{
+	"annotationProcessor":     {},
+	"kapt":                    {},
+	"kaptAndroidTest":         {},
+	"kaptTest":                {},
+	"ksp":                     {},
+	"kspAndroidTest":          {},
+	"kspTest":                 {},
+	"testAnnotationProcessor": {},
+}
+
+// IsGradleAnnotationProcessorConfiguration reports whether a Gradle
+// configuration only feeds the compiler's annotation processors, so its
+// dependencies are used at build time without any source imports.
+func IsGradleAnnotationProcessorConfiguration(configuration string) bool {
+	_, ok := gradleAnnotationProcessorConfigurations[configuration]
+	return ok
+}
+
 func ParseGradleDependencyCoordinatesForFile(path, content string) []GradleDependencyCoordinate {
 	return ParseGradleDependencyCoordinates(content, gradleLanguageForPath(path))
 }
@@ -64,6 +87,7 @@ func ParseGradleDependencyCoordinates(content string, language *sitter.Language)
 			return
 		}
 		if coordinate, ok := gradleCoordinateFromCall(node, source); ok {
+			coordinate.Configuration, _ = gradleCallName(node, source)
 			coordinates = append(coordinates, coordinate)
 		}
 	})
@@ -87,10 +111,12 @@ func parseGradleCatalogReferencesForFile(path, content string) []gradleCatalogRe
 		if !isGradleDependencyCall(node, source) {
 			return
 		}
+		configuration, _ := gradleCallName(node, source)
 		for _, arg := range gradleCallArguments(node) {
 			for _, expression := range gradleDependencyArgumentExpressions(arg, source) {
 				reference, ok := parseGradleCatalogReferenceExpression(expression)
 				if ok {
+					reference.configuration = configuration
 					references = append(references, reference)
 				}
 			}
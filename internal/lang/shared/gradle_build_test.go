@@ -35,6 +35,28 @@ dependencies {
 	}
 }
 
+func TestParseGradleDependencyCoordinatesRecordsConfiguration(t *testing.T) {
+	coordinates := ParseGradleDependencyCoordinatesForFile("build.gradle.kts", `
+dependencies {
+  implementation("org.mapstruct:mapstruct:1.5.5.Final")
+  annotationProcessor("org.mapstruct:mapstruct-processor:1.5.5.Final")
+  kapt("com.google.dagger:dagger-compiler:2.51")
+}
+`)
+	configurations := make(map[string]string, len(coordinates))
+	for _, coordinate := range coordinates {
+		configurations[coordinate.Artifact] = coordinate.Configuration
+	}
+	if configurations["mapstruct"] != "implementation" || configurations["mapstruct-processor"] != "annotationProcessor" || configurations["dagger-compiler"] != "kapt" {
+		t.Fatalf("expected dependency configurations to be recorded, got %#v", configurations)
+	}
+	for configuration, want := range map[string]bool{"annotationProcessor": true, "kapt": true, "kspTest": true, "implementation": false, "compileOnly": false} {
+		if got := IsGradleAnnotationProcessorConfiguration(configuration); got != want {
+			t.Fatalf("IsGradleAnnotationProcessorConfiguration(%q) = %t, want %t", configuration, got, want)
+		}
+	}
+}
+
 func TestParseGradleCatalogReferencesFromDependencyCalls(t *testing.T) {
 	references := parseGradleCatalogReferencesForFile("build.gradle.kts", `
 dependencies {
@@ -35,11 +35,12 @@ const (
 )
 
 type GradleCatalogLibrary struct {
-	Alias    string
-	Catalog  string
-	Group    string
-	Artifact string
-	Version  string
+	Alias         string
+	Catalog       string
+	Group         string
+	Artifact      string
+	Version       string
+	Configuration string
 }
 
 type GradleCatalogResolver struct {
@@ -601,21 +602,25 @@ func (c *gradleCatalogReferenceCollector) collectReferences(content string) {
 			continue
 		}
 		if reference.bundle {
-			c.addBundleReference(reference.catalogName, reference.alias)
+			c.addBundleReference(reference.catalogName, reference.alias, reference.configuration)
 			continue
 		}
-		c.addLibraryReference(reference.catalogName, reference.alias)
+		c.addLibraryReference(reference.catalogName, reference.alias, reference.configuration)
 	}
 }
 
-func (c *gradleCatalogReferenceCollector) addLibraryReference(catalogName, alias string) {
+func (c *gradleCatalogReferenceCollector) addLibraryReference(catalogName, alias, configuration string) {
 	library, warning := c.resolver.resolveLibraryReference(c.buildFilePath, catalogName, alias)
+	library.Configuration = configuration
 	c.appendLibrary(library)
 	c.appendWarning(warning)
 }
 
-func (c *gradleCatalogReferenceCollector) addBundleReference(catalogName, alias string) {
+func (c *gradleCatalogReferenceCollector) addBundleReference(catalogName, alias, configuration string) {
 	libraries, warning := c.resolver.resolveBundleReference(c.buildFilePath, catalogName, alias)
+	for i := range libraries {
+		libraries[i].Configuration = configuration
+	}
 	c.appendLibraries(libraries)
 	c.appendWarning(warning)
 }
@@ -630,7 +635,7 @@ func (c *gradleCatalogReferenceCollector) appendLibrary(library GradleCatalogLib
 	if library.Group == "" || library.Artifact == "" {
 		return
 	}
-	key := library.Group + ":" + library.Artifact
+	key := library.Group + ":" + library.Artifact + "@" + library.Configuration
 	if _, ok := c.seen[key]; ok {
 		return
 	}
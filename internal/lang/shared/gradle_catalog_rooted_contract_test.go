@@ -58,7 +58,7 @@ dependencyResolutionManagement {
 	}
 
 	libraries, parseWarnings := resolver.ParseDependencyReferences(filepath.Join(repo, "build.gradle.kts"), "dependencies { implementation(tools.cli) }")
-	want := GradleCatalogLibrary{Alias: "cli", Catalog: "tools", Group: "dev.example", Artifact: "cli", Version: "1.0.0"}
+	want := GradleCatalogLibrary{Alias: "cli", Catalog: "tools", Group: "dev.example", Artifact: "cli", Version: "1.0.0", Configuration: "implementation"}
 	if len(parseWarnings) != 0 || len(libraries) != 1 || libraries[0] != want {
 		t.Fatalf("expected configured skipped-directory dependency %#v, got libraries=%#v warnings=%#v", want, libraries, parseWarnings)
 	}
@@ -0,0 +1,237 @@
+package shared
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report"
+)
+
+// JVMRuntimeUsagePreviewFeature enables runtime usage cues in the JVM and
+// Kotlin/Android adapters.
+const JVMRuntimeUsagePreviewFeature = "jvm-runtime-usage-preview"
+
+// Risk cue codes for JVM dependencies that the compiler or runtime loads by
+// name, so import scanning alone sees them as unused.
+const (
+	JVMAnnotationProcessorCue = "annotation-processor"
+	JVMServiceProviderCue     = "service-provider"
+	JVMReflectiveClassCue     = "reflective-class-reference"
+	JVMJDBCDriverCue          = "jdbc-driver-url"
+)
+
+var jvmRuntimeUsageCues = []struct {
+	code     string
+	severity string
+	message  string
+}{
+	{code: JVMAnnotationProcessorCue, severity: "high", message: "runs as an annotation processor during compilation"},
+	{code: JVMServiceProviderCue, severity: "high", message: "is discovered through service descriptors"},
+	{code: JVMReflectiveClassCue, severity: "medium", message: "is referenced by class name"},
+	{code: JVMJDBCDriverCue, severity: "medium", message: "is loaded as a JDBC driver"},
+}
+
+var knownJVMAnnotationProcessorArtifacts = map[string]struct{}{
+	"auto-service":                        {},
+	"auto-value":                          {},
+	"compiler":                            {},
+	"dagger-compiler":                     {},
+	"hibernate-jpamodelgen":               {},
+	"hilt-android-compiler":               {},
+	"hilt-compiler":                       {},
+	"lombok":                              {},
+	"mapstruct-processor":                 {},
+	"micronaut-inject-java":               {},
+	"moshi-kotlin-codegen":                {},
+	"room-compiler":                       {},
+	"spring-boot-configuration-processor": {},
+	"value":                               {},
+}
+
+// jdbcDrivers maps JDBC URL subprotocols to the driver class DriverManager
+// loads and the artifacts that usually ship it.
+var jdbcDrivers = map[string]struct {
+	className string
+	artifacts []string
+}{
+	"derby":      {className: "org.apache.derby.jdbc.EmbeddedDriver", artifacts: []string{"derby", "derbyclient"}},
+	"h2":         {className: "org.h2.Driver", artifacts: []string{"h2"}},
+	"hsqldb":     {className: "org.hsqldb.jdbc.JDBCDriver", artifacts: []string{"hsqldb"}},
+	"mariadb":    {className: "org.mariadb.jdbc.Driver", artifacts: []string{"mariadb-java-client"}},
+	"mysql":      {className: "com.mysql.cj.jdbc.Driver", artifacts: []string{"mysql-connector-j", "mysql-connector-java"}},
+	"oracle":     {className: "oracle.jdbc.OracleDriver", artifacts: []string{"ojdbc8", "ojdbc10", "ojdbc11", "ojdbc17"}},
+	"postgresql": {className: "org.postgresql.Driver", artifacts: []string{"postgresql"}},
+	"sqlite":     {className: "org.sqlite.JDBC", artifacts: []string{"sqlite-jdbc"}},
+	"sqlserver":  {className: "com.microsoft.sqlserver.jdbc.SQLServerDriver", artifacts: []string{"mssql-jdbc"}},
+}
+
+var (
+	jvmQuotedClassNamePattern = regexp.MustCompile(`"((?:[a-z_][a-z0-9_]*\.){2,}[A-Z][A-Za-z0-9_$]*)"`)
+	jvmConfigClassNamePattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.$])((?:[a-z_][a-z0-9_]*\.){2,}[A-Z][A-Za-z0-9_$]*)`)
+	jdbcURLPattern            = regexp.MustCompile(`jdbc:([a-z0-9]+):`)
+)
+
+// JVMRuntimeReference is a class a source or config file hands to the JVM by
+// name. Artifacts lists conventional owners for when the class name alone
+// does not resolve to a declared dependency.
+type JVMRuntimeReference struct {
+	Code      string
+	ClassName string
+	Artifacts []string
+	Line      int
+}
+
+// ScanJVMRuntimeReferences finds class names loaded by name: quoted class
+// names in sources, class names in properties/YAML/Spring descriptors, service
+// interfaces in META-INF/services, and drivers implied by JDBC URLs.
+func ScanJVMRuntimeReferences(path string, content []byte) []JVMRuntimeReference {
+	slashPath := filepath.ToSlash(path)
+	references := make([]JVMRuntimeReference, 0)
+	if service, ok := jvmServiceDescriptorInterface(slashPath); ok {
+		references = append(references, JVMRuntimeReference{Code: JVMServiceProviderCue, ClassName: service, Line: 1})
+	}
+	classPattern := jvmConfigClassNamePattern
+	if IsJVMSourcePath(slashPath) {
+		classPattern = jvmQuotedClassNamePattern
+	} else if !IsJVMRuntimeConfigPath(slashPath) {
+		return references
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		for _, match := range classPattern.FindAllStringSubmatch(text, -1) {
+			references = append(references, JVMRuntimeReference{Code: JVMReflectiveClassCue, ClassName: match[1], Line: line})
+		}
+		for _, match := range jdbcURLPattern.FindAllStringSubmatch(text, -1) {
+			driver, ok := jdbcDrivers[match[1]]
+			if !ok {
+				continue
+			}
+			references = append(references, JVMRuntimeReference{Code: JVMJDBCDriverCue, ClassName: driver.className, Artifacts: driver.artifacts, Line: line})
+		}
+	}
+	return references
+}
+
+// IsJVMSourcePath reports whether a path is Java, Kotlin, or Groovy source.
+func IsJVMSourcePath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".java", ".kt", ".kts", ".groovy":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsJVMRuntimeConfigPath reports whether a path is configuration that
+// frameworks read class names from at runtime.
+func IsJVMRuntimeConfigPath(path string) bool {
+	slashPath := filepath.ToSlash(path)
+	if _, ok := jvmServiceDescriptorInterface(slashPath); ok {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(slashPath)) {
+	case ".properties", ".yml", ".yaml", ".factories", ".imports":
+		return true
+	default:
+		return false
+	}
+}
+
+func jvmServiceDescriptorInterface(slashPath string) (string, bool) {
+	dir, name := filepath.ToSlash(filepath.Dir(slashPath)), filepath.Base(slashPath)
+	if dir != "META-INF/services" && !strings.HasSuffix(dir, "/META-INF/services") {
+		return "", false
+	}
+	if !strings.Contains(name, ".") {
+		return "", false
+	}
+	return name, true
+}
+
+// IsKnownJVMAnnotationProcessorArtifact reports whether an artifact is a
+// widely used annotation processor that is typically declared on the compile
+// classpath rather than in a dedicated processor configuration.
+func IsKnownJVMAnnotationProcessorArtifact(group, artifact string) bool {
+	if _, ok := knownJVMAnnotationProcessorArtifacts[artifact]; !ok {
+		return false
+	}
+	switch artifact {
+	case "compiler":
+		return group == "com.github.bumptech.glide"
+	case "value":
+		return group == "org.immutables"
+	default:
+		return true
+	}
+}
+
+// JVMRuntimeUsage records the first evidence of each runtime usage channel
+// per dependency.
+type JVMRuntimeUsage map[string]map[string]string
+
+// Add records evidence for a dependency unless the channel already has some.
+func (u JVMRuntimeUsage) Add(dependency, code, evidence string) {
+	if dependency == "" || code == "" {
+		return
+	}
+	channels, ok := u[dependency]
+	if !ok {
+		channels = make(map[string]string)
+		u[dependency] = channels
+	}
+	if _, ok := channels[code]; !ok {
+		channels[code] = evidence
+	}
+}
+
+// RiskCues describes each recorded channel for a dependency in a stable order.
+func (u JVMRuntimeUsage) RiskCues(dependency string) []report.RiskCue {
+	channels := u[dependency]
+	if len(channels) == 0 {
+		return nil
+	}
+	cues := make([]report.RiskCue, 0, len(channels))
+	for _, cue := range jvmRuntimeUsageCues {
+		evidence, ok := channels[cue.code]
+		if !ok {
+			continue
+		}
+		message := "dependency " + cue.message
+		if evidence != "" {
+			message = fmt.Sprintf("%s (%s)", message, evidence)
+		}
+		cues = append(cues, report.RiskCue{Code: cue.code, Severity: cue.severity, Message: message})
+	}
+	return cues
+}
+
+// HasJVMRuntimeUsageCue reports whether any cue marks runtime or compiler
+// usage that import scanning cannot see.
+func HasJVMRuntimeUsageCue(cues []report.RiskCue) bool {
+	for _, cue := range cues {
+		for _, runtimeCue := range jvmRuntimeUsageCues {
+			if cue.Code == runtimeCue.code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JVMRuntimeUsageRecommendation replaces a removal recommendation for
+// dependencies with runtime usage cues.
+func JVMRuntimeUsageRecommendation() report.Recommendation {
+	return report.Recommendation{
+		Code:      "review-runtime-loading",
+		Priority:  "high",
+		Message:   "No imports were detected, but this dependency is loaded by name, by service discovery, or by the compiler; review runtime usage before removing it.",
+		Rationale: "Static import analysis cannot see reflection, ServiceLoader, JDBC driver, or annotation processor usage.",
+	}
+}
@@ -0,0 +1,67 @@
+package shared
+
+import (
+	"testing"
+)
+
+func TestScanJVMRuntimeReferences(t *testing.T) {
+	source := ScanJVMRuntimeReferences("src/main/java/App.java", []byte(`
+import org.example.Ignored;
+class App {
+  Object load() throws Exception {
+    Class.forName("com.mysql.cj.jdbc.Driver");
+    String label = "not.a.Class name";
+    return DriverManager.getConnection("jdbc:postgresql://db/app");
+  }
+}
+`))
+	assertJVMRuntimeReference(t, source, JVMReflectiveClassCue, "com.mysql.cj.jdbc.Driver", 5)
+	assertJVMRuntimeReference(t, source, JVMJDBCDriverCue, "org.postgresql.Driver", 7)
+	if len(source) != 2 {
+		t.Fatalf("expected only quoted class names and JDBC URLs from source, got %#v", source)
+	}
+
+	config := ScanJVMRuntimeReferences("src/main/resources/application.yml", []byte("spring:\n  datasource:\n    driver-class-name: org.mariadb.jdbc.Driver\n    url: jdbc:unknown:thing\n"))
+	if len(config) != 1 || config[0].ClassName != "org.mariadb.jdbc.Driver" || config[0].Line != 3 {
+		t.Fatalf("expected unquoted class names from config and unknown JDBC subprotocols ignored, got %#v", config)
+	}
+
+	services := ScanJVMRuntimeReferences("src/main/resources/META-INF/services/com.fasterxml.jackson.databind.Module", []byte("com.example.app.JsonModule\n"))
+	assertJVMRuntimeReference(t, services, JVMServiceProviderCue, "com.fasterxml.jackson.databind.Module", 1)
+
+	if got := ScanJVMRuntimeReferences("README.md", []byte(`"com.example.app.Main"`)); len(got) != 0 {
+		t.Fatalf("expected unrelated files to be ignored, got %#v", got)
+	}
+}
+
+func TestJVMRuntimeUsageRiskCues(t *testing.T) {
+	usage := JVMRuntimeUsage{}
+	usage.Add("postgresql", JVMJDBCDriverCue, "a.properties:1 org.postgresql.Driver")
+	usage.Add("postgresql", JVMJDBCDriverCue, "b.properties:1 org.postgresql.Driver")
+	usage.Add("postgresql", JVMServiceProviderCue, "")
+	usage.Add("", JVMServiceProviderCue, "ignored")
+
+	cues := usage.RiskCues("postgresql")
+	if len(cues) != 2 || cues[0].Code != JVMServiceProviderCue || cues[1].Code != JVMJDBCDriverCue {
+		t.Fatalf("expected cues in channel order, got %#v", cues)
+	}
+	if cues[1].Message != "dependency is loaded as a JDBC driver (a.properties:1 org.postgresql.Driver)" {
+		t.Fatalf("expected first evidence to be kept, got %q", cues[1].Message)
+	}
+	if !HasJVMRuntimeUsageCue(cues) || HasJVMRuntimeUsageCue(nil) || usage.RiskCues("missing") != nil {
+		t.Fatalf("unexpected runtime usage cue lookups")
+	}
+	if !IsKnownJVMAnnotationProcessorArtifact("org.projectlombok", "lombok") || IsKnownJVMAnnotationProcessorArtifact("org.example", "compiler") || !IsKnownJVMAnnotationProcessorArtifact("org.immutables", "value") {
+		t.Fatalf("unexpected known annotation processor classification")
+	}
+}
+
+func assertJVMRuntimeReference(t *testing.T, references []JVMRuntimeReference, code, className string, line int) {
+	t.Helper()
+	for _, reference := range references {
+		if reference.Code == code && reference.ClassName == className && reference.Line == line {
+			return
+		}
+	}
+	t.Fatalf("expected %s reference to %s on line %d in %#v", code, className, line, references)
+}
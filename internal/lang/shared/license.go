@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// NormalizeSPDXExpression upper-cases SPDX identifiers and operators in a
+// declared license expression, dropping characters SPDX IDs cannot contain.
+func NormalizeSPDXExpression(raw string) string {
+	replaced := strings.TrimSpace(raw)
+	replaced = strings.ReplaceAll(replaced, "(", " ( ")
+	replaced = strings.ReplaceAll(replaced, ")", " ) ")
+	replaced = strings.ReplaceAll(replaced, " and ", " AND ")
+	replaced = strings.ReplaceAll(replaced, " or ", " OR ")
+	replaced = strings.ReplaceAll(replaced, "\t", " ")
+	replaced = strings.ReplaceAll(replaced, "\n", " ")
+	replaced = strings.ReplaceAll(replaced, "\r", " ")
+	parts := strings.Fields(replaced)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	normalized := make([]string, 0, len(parts))
+	for _, part := range parts {
+		upper := strings.ToUpper(part)
+		switch upper {
+		case "AND", "OR", "WITH", "(", ")", "+":
+			normalized = append(normalized, upper)
+			continue
+		}
+		id := NormalizeSPDXToken(part)
+		if id == "" {
+			continue
+		}
+		normalized = append(normalized, id)
+	}
+	if len(normalized) == 0 {
+		return ""
+	}
+	return strings.Join(normalized, " ")
+}
+
+func NormalizeSPDXToken(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-', r == '.', r == '+':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsLicenseFileName reports whether a file looks like a bundled license text.
+func IsLicenseFileName(path string) bool {
+	base := strings.ToUpper(filepath.Base(path))
+	return strings.HasPrefix(base, "LICENSE") || strings.HasPrefix(base, "LICENCE") || strings.HasPrefix(base, "COPYING")
+}
+
+// IsLicenseNoticeFileName reports whether a file is a NOTICE file, which
+// template classification also reads.
+func IsLicenseNoticeFileName(path string) bool {
+	return strings.HasPrefix(strings.ToUpper(filepath.Base(path)), "NOTICE")
+}
+
+// DetectSPDXFromLicenseText returns a best-effort SPDX ID and confidence for
+// common license texts, or empty strings when the text is not recognised.
+func DetectSPDXFromLicenseText(content string) (string, string) {
+	text := strings.ToLower(content)
+	switch {
+	case strings.Contains(text, "mit license"):
+		return "MIT", "medium"
+	case strings.Contains(text, "apache license") && strings.Contains(text, "version 2.0"):
+		return "APACHE-2.0", "medium"
+	case strings.Contains(text, "gnu general public license"):
+		return "GPL-3.0-OR-LATER", "low"
+	case strings.Contains(text, "mozilla public license"):
+		return "MPL-2.0", "low"
+	case strings.Contains(text, "isc license"):
+		return "ISC", "medium"
+	case strings.Contains(text, "redistribution and use in source and binary forms"):
+		return "BSD-3-CLAUSE", "low"
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,180 @@
+package shared
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// LicenseTemplatesPreviewFeature enables classifying license files against
+// the bundled SPDX templates.
+const LicenseTemplatesPreviewFeature = "license-templates-preview"
+
+const (
+	licenseTemplateHighSimilarity   = 0.9
+	licenseTemplateMediumSimilarity = 0.75
+)
+
+//go:embed licensetemplates/*.txt
+var licenseTemplateFiles embed.FS
+
+// licenseTemplate is a bundled, normalized license text. Short permissive
+// licenses ship in full and are compared symmetrically; long copyleft licenses
+// ship only their distinctive opening sections, so for those the score is how
+// much of the excerpt appears in the candidate text.
+type licenseTemplate struct {
+	spdx    string
+	file    string
+	excerpt bool
+	bigrams map[string]struct{}
+}
+
+var licenseTemplateCatalog = []licenseTemplate{
+	{spdx: "0BSD", file: "0BSD.txt"},
+	{spdx: "AGPL-3.0-ONLY", file: "AGPL-3.0-only.txt", excerpt: true},
+	{spdx: "APACHE-2.0", file: "Apache-2.0.txt", excerpt: true},
+	{spdx: "BSD-2-CLAUSE", file: "BSD-2-Clause.txt"},
+	{spdx: "BSD-3-CLAUSE", file: "BSD-3-Clause.txt"},
+	{spdx: "BSL-1.0", file: "BSL-1.0.txt"},
+	{spdx: "GPL-2.0-ONLY", file: "GPL-2.0-only.txt", excerpt: true},
+	{spdx: "GPL-3.0-ONLY", file: "GPL-3.0-only.txt", excerpt: true},
+	{spdx: "ISC", file: "ISC.txt"},
+	{spdx: "LGPL-2.1-ONLY", file: "LGPL-2.1-only.txt", excerpt: true},
+	{spdx: "LGPL-3.0-ONLY", file: "LGPL-3.0-only.txt", excerpt: true},
+	{spdx: "MIT", file: "MIT.txt"},
+	{spdx: "MPL-2.0", file: "MPL-2.0.txt", excerpt: true},
+	{spdx: "UNLICENSE", file: "Unlicense.txt"},
+	{spdx: "ZLIB", file: "Zlib.txt"},
+}
+
+var loadLicenseTemplates = sync.OnceValue(func() []licenseTemplate {
+	templates := make([]licenseTemplate, 0, len(licenseTemplateCatalog))
+	for _, template := range licenseTemplateCatalog {
+		content, err := licenseTemplateFiles.ReadFile(path.Join("licensetemplates", template.file))
+		if err != nil {
+			continue
+		}
+		template.bigrams = licenseTextBigrams(string(content))
+		templates = append(templates, template)
+	}
+	return templates
+})
+
+// LicenseTextMatch is the outcome of classifying a license text. Similarity
+// is zero when the SPDX ID came from keyword heuristics rather than a
+// template match.
+type LicenseTextMatch struct {
+	SPDX       string
+	Confidence string
+	Similarity float64
+}
+
+// ClassifyLicenseText compares a license text against the bundled SPDX
+// templates and returns the closest match when it is similar enough to trust.
+func ClassifyLicenseText(content string) (LicenseTextMatch, bool) {
+	bigrams := licenseTextBigrams(content)
+	if len(bigrams) == 0 {
+		return LicenseTextMatch{}, false
+	}
+	best := LicenseTextMatch{}
+	for _, template := range loadLicenseTemplates() {
+		score := licenseTemplateSimilarity(template, bigrams)
+		if score > best.Similarity {
+			best = LicenseTextMatch{SPDX: template.spdx, Similarity: score}
+		}
+	}
+	switch {
+	case best.Similarity >= licenseTemplateHighSimilarity:
+		best.Confidence = "high"
+	case best.Similarity >= licenseTemplateMediumSimilarity:
+		best.Confidence = "medium"
+	default:
+		return LicenseTextMatch{}, false
+	}
+	return best, true
+}
+
+// DetectLicenseText classifies a license text against the bundled templates
+// when templates is set, falling back to keyword heuristics for short or
+// heavily modified texts. Without templates only the keyword heuristics run.
+func DetectLicenseText(content string, templates bool) (LicenseTextMatch, bool) {
+	if templates {
+		if match, ok := ClassifyLicenseText(content); ok {
+			return match, true
+		}
+	}
+	spdx, confidence := DetectSPDXFromLicenseText(content)
+	if spdx == "" {
+		return LicenseTextMatch{}, false
+	}
+	return LicenseTextMatch{SPDX: spdx, Confidence: confidence}, true
+}
+
+// Evidence describes a template match for a license's evidence list, or
+// returns an empty string for keyword matches.
+func (m LicenseTextMatch) Evidence() string {
+	if m.Similarity <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("template:%s similarity=%.2f", m.SPDX, m.Similarity)
+}
+
+func licenseTemplateSimilarity(template licenseTemplate, bigrams map[string]struct{}) float64 {
+	if len(template.bigrams) == 0 {
+		return 0
+	}
+	shared := 0
+	for bigram := range template.bigrams {
+		if _, ok := bigrams[bigram]; ok {
+			shared++
+		}
+	}
+	if template.excerpt {
+		return float64(shared) / float64(len(template.bigrams))
+	}
+	return 2 * float64(shared) / float64(len(template.bigrams)+len(bigrams))
+}
+
+// licenseTextBigrams normalizes a license text the way SPDX matching
+// guidelines allow (case, punctuation, whitespace, copyright notices and
+// licence/license spelling) and returns its set of adjacent word pairs.
+func licenseTextBigrams(content string) map[string]struct{} {
+	words := make([]string, 0, 256)
+	for _, line := range strings.Split(strings.ToLower(content), "\n") {
+		if isCopyrightNoticeLine(line) {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(line, isLicenseWordSeparator) {
+			words = append(words, normalizeLicenseWord(word))
+		}
+	}
+	bigrams := make(map[string]struct{}, len(words))
+	for i := 1; i < len(words); i++ {
+		bigrams[words[i-1]+" "+words[i]] = struct{}{}
+	}
+	return bigrams
+}
+
+func isCopyrightNoticeLine(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t*#/-")
+	return strings.HasPrefix(trimmed, "copyright") || strings.HasPrefix(trimmed, "(c)") || strings.HasPrefix(trimmed, "©")
+}
+
+func isLicenseWordSeparator(r rune) bool {
+	return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+}
+
+func normalizeLicenseWord(word string) string {
+	switch word {
+	case "licence":
+		return "license"
+	case "licences":
+		return "licenses"
+	case "licenced":
+		return "licensed"
+	default:
+		return word
+	}
+}
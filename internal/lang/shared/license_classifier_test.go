@@ -0,0 +1,138 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+)
+
+const classifierBSD3Text = `Copyright (c) 2009 The Go Authors. All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google Inc. nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+`
+
+func classifierTemplateText(t *testing.T, file string) string {
+	t.Helper()
+	content, err := licenseTemplateFiles.ReadFile("licensetemplates/" + file)
+	if err != nil {
+		t.Fatalf("read template %s: %v", file, err)
+	}
+	return string(content)
+}
+
+func TestClassifyLicenseTextMatchesBundledTemplates(t *testing.T) {
+	for _, template := range licenseTemplateCatalog {
+		text := "Copyright (c) 2024 Example Corp\n\n" + classifierTemplateText(t, template.file)
+		if template.excerpt {
+			text += "\n  2. Further terms and conditions follow in the full license text.\n"
+		}
+		match, ok := ClassifyLicenseText(text)
+		if !ok || match.SPDX != template.spdx || match.Confidence != "high" {
+			t.Fatalf("expected %s template to classify as itself with high confidence, got %#v", template.spdx, match)
+		}
+	}
+}
+
+func TestClassifyLicenseTextToleratesRealWorldVariation(t *testing.T) {
+	match, ok := ClassifyLicenseText(classifierBSD3Text)
+	if !ok || match.SPDX != "BSD-3-CLAUSE" {
+		t.Fatalf("expected BSD-3-Clause for reworded Go license, got %#v", match)
+	}
+	if match.Similarity >= 1 || match.Similarity < licenseTemplateMediumSimilarity {
+		t.Fatalf("expected partial similarity for reworded license, got %.2f", match.Similarity)
+	}
+
+	mit := strings.ReplaceAll(classifierTemplateText(t, "MIT.txt"), "MIT License", "The MIT Licence (MIT)")
+	if match, ok := ClassifyLicenseText(mit); !ok || match.SPDX != "MIT" || match.Confidence != "high" {
+		t.Fatalf("expected MIT with British spelling to classify, got %#v", match)
+	}
+}
+
+func TestClassifyLicenseTextSeparatesRelatedLicenses(t *testing.T) {
+	cases := map[string]string{
+		"BSD-2-Clause.txt":  "BSD-2-CLAUSE",
+		"ISC.txt":           "ISC",
+		"0BSD.txt":          "0BSD",
+		"LGPL-2.1-only.txt": "LGPL-2.1-ONLY",
+		"GPL-2.0-only.txt":  "GPL-2.0-ONLY",
+		"AGPL-3.0-only.txt": "AGPL-3.0-ONLY",
+	}
+	for file, want := range cases {
+		if match, ok := ClassifyLicenseText(classifierTemplateText(t, file)); !ok || match.SPDX != want {
+			t.Fatalf("expected %s to classify as %s, got %#v", file, want, match)
+		}
+	}
+}
+
+func TestClassifyLicenseTextRejectsUnrelatedText(t *testing.T) {
+	for _, text := range []string{"", "Copyright (c) 2024 Example", "All rights reserved. Internal use only; contact legal before redistribution.", "MIT License"} {
+		if match, ok := ClassifyLicenseText(text); ok {
+			t.Fatalf("did not expect %q to match a template, got %#v", text, match)
+		}
+	}
+}
+
+func TestDetectLicenseTextFallsBackToKeywords(t *testing.T) {
+	match, ok := DetectLicenseText("MIT License\nPermission is hereby granted...", true)
+	if !ok || match.SPDX != "MIT" || match.Confidence != "medium" || match.Similarity != 0 || match.Evidence() != "" {
+		t.Fatalf("expected keyword fallback without template evidence, got %#v", match)
+	}
+	if _, ok := DetectLicenseText("custom internal license text", true); ok {
+		t.Fatalf("did not expect unknown text to be detected")
+	}
+
+	match, ok = DetectLicenseText(classifierTemplateText(t, "Apache-2.0.txt"), true)
+	if !ok || match.SPDX != "APACHE-2.0" || match.Evidence() != "template:APACHE-2.0 similarity=1.00" {
+		t.Fatalf("expected template evidence for Apache-2.0, got %#v (%q)", match, match.Evidence())
+	}
+	if match, ok := DetectLicenseText(classifierTemplateText(t, "GPL-3.0-only.txt"), true); !ok || match.SPDX != "GPL-3.0-ONLY" || match.Confidence != "high" {
+		t.Fatalf("expected GPL-3.0-only from license text, got %#v", match)
+	}
+}
+
+func TestDetectLicenseTextWithoutTemplatesUsesKeywords(t *testing.T) {
+	match, ok := DetectLicenseText(classifierTemplateText(t, "GPL-3.0-only.txt"), false)
+	if !ok || match.SPDX != "GPL-3.0-OR-LATER" || match.Confidence != "low" || match.Evidence() != "" {
+		t.Fatalf("expected keyword detection without templates, got %#v", match)
+	}
+	if spdx, confidence := DetectSPDXFromLicenseText(classifierTemplateText(t, "GPL-3.0-only.txt")); spdx != match.SPDX || confidence != match.Confidence {
+		t.Fatalf("expected DetectSPDXFromLicenseText to stay keyword-only, got %s/%s", spdx, confidence)
+	}
+}
+
+func TestIsLicenseFileNameSeparatesNotice(t *testing.T) {
+	for _, name := range []string{"LICENSE", "licence.md", "COPYING.txt"} {
+		if !IsLicenseFileName(name) {
+			t.Fatalf("expected %s to be a license file", name)
+		}
+	}
+	if IsLicenseFileName("README.md") || IsLicenseFileName("NOTICE") {
+		t.Fatalf("did not expect README.md or NOTICE to be a license file")
+	}
+	if !IsLicenseNoticeFileName("NOTICE.txt") || IsLicenseNoticeFileName("LICENSE") {
+		t.Fatalf("expected only NOTICE files to be notice files")
+	}
+}
@@ -693,6 +693,9 @@ func (r *sharedPinnedChildRoot) Lstat(name string) (fs.FileInfo, error) {
 
 func (*sharedPinnedChildRoot) Mkdir(string, os.FileMode) error { return errors.New("unexpected mkdir") }
 func (*sharedPinnedChildRoot) Chmod(string, os.FileMode) error { return errors.New("unexpected chmod") }
+func (*sharedPinnedChildRoot) Chtimes(string, time.Time, time.Time) error {
+	return errors.New("unexpected chtimes")
+}
 func (*sharedPinnedChildRoot) MkdirAll(string, os.FileMode) error {
 	return errors.New("unexpected mkdir all")
 }
@@ -730,6 +733,9 @@ func (r *sharedWalkTestRoot) Lstat(name string) (fs.FileInfo, error) {
 
 func (*sharedWalkTestRoot) Mkdir(string, os.FileMode) error { return errors.New("unexpected mkdir") }
 func (*sharedWalkTestRoot) Chmod(string, os.FileMode) error { return errors.New("unexpected chmod") }
+func (*sharedWalkTestRoot) Chtimes(string, time.Time, time.Time) error {
+	return errors.New("unexpected chtimes")
+}
 func (*sharedWalkTestRoot) MkdirAll(string, os.FileMode) error {
 	return errors.New("unexpected mkdir all")
 }
@@ -789,6 +795,10 @@ func (r *countingSharedWalkRoot) Chmod(name string, perm os.FileMode) error {
 	return r.underlying.Chmod(name, perm)
 }
 
+func (r *countingSharedWalkRoot) Chtimes(name string, atime, mtime time.Time) error {
+	return r.underlying.Chtimes(name, atime, mtime)
+}
+
 func (r *countingSharedWalkRoot) MkdirAll(name string, perm os.FileMode) error {
 	return r.underlying.MkdirAll(name, perm)
 }
@@ -25,7 +25,8 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 	}
 
 	catalogOptions := dependencyCatalogOptions{
-		EnableCarthage: req.Features.Enabled(swiftCarthagePreviewFlagName),
+		EnableCarthage:      req.Features.Enabled(swiftCarthagePreviewFlagName),
+		EnableXcodeProjects: req.Features.Enabled(swiftXcodePreviewFlagName),
 	}
 	catalog, catalogWarnings, err := buildDependencyCatalogWithOptions(repoPath, catalogOptions)
 	if err != nil {
@@ -38,6 +39,11 @@ func (a *Adapter) Analyse(ctx context.Context, req language.Request) (report.Res
 		return report.Report{}, err
 	}
 	result.Warnings = append(result.Warnings, scan.Warnings...)
+	if catalogOptions.EnableXcodeProjects {
+		findings, warnings := evaluateXcodeTargets(catalog, scan)
+		scan.ProductFindings = findings
+		result.Warnings = append(result.Warnings, warnings...)
+	}
 
 	dependencies, warnings := buildRequestedSwiftDependencies(req, scan, catalog)
 	result.Dependencies = dependencies
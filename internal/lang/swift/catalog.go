@@ -20,7 +20,8 @@ func buildDependencyCatalog(repoPath string) (dependencyCatalog, []string, error
 }
 
 type dependencyCatalogOptions struct {
-	EnableCarthage bool
+	EnableCarthage      bool
+	EnableXcodeProjects bool
 }
 
 func buildDependencyCatalogWithOptions(repoPath string, opts dependencyCatalogOptions) (dependencyCatalog, []string, error) {
@@ -54,10 +55,20 @@ func buildDependencyCatalogWithOptions(repoPath string, opts dependencyCatalogOp
 		warnings = append(warnings, carthageWarnings...)
 	}
 
+	xcode := packageManagerCatalogState{}
+	if opts.EnableXcodeProjects {
+		xcodeWarnings := []string(nil)
+		xcode, xcodeWarnings, err = loadPackageManagerCatalog(repoPath, &catalog, loadXcodeProjectData, loadXcodeResolvedData)
+		if err != nil {
+			return dependencyCatalog{}, nil, err
+		}
+		warnings = append(warnings, xcodeWarnings...)
+	}
+
 	catalog.HasSwiftPM = swiftPM.Active()
 	catalog.HasCocoaPods = cocoaPods.Active()
 	catalog.HasCarthage = opts.EnableCarthage && carthage.Active()
-	warnings = append(warnings, missingCatalogWarnings(swiftPM, cocoaPods, carthage, opts.EnableCarthage)...)
+	warnings = append(warnings, missingCatalogWarnings(swiftPM, cocoaPods, carthage, xcode, opts.EnableCarthage)...)
 
 	if len(catalog.Dependencies) == 0 {
 		warnings = append(warnings, "no Swift dependencies were discovered from "+strings.Join(discoveredSwiftCatalogSources(opts), ", "))
@@ -94,10 +105,10 @@ func loadPackageManagerCatalog(repoPath string, catalog *dependencyCatalog, mani
 	}, append(manifestWarnings, lockWarnings...), nil
 }
 
-func missingCatalogWarnings(swiftPM, cocoaPods, carthage packageManagerCatalogState, includeCarthage bool) []string {
+func missingCatalogWarnings(swiftPM, cocoaPods, carthage, xcode packageManagerCatalogState, includeCarthage bool) []string {
 	warnings := make([]string, 0, 4)
 	activeManagers := 0
-	for _, state := range []packageManagerCatalogState{swiftPM, cocoaPods} {
+	for _, state := range []packageManagerCatalogState{swiftPM, cocoaPods, xcode} {
 		if state.Active() {
 			activeManagers++
 		}
@@ -133,6 +144,9 @@ func discoveredSwiftCatalogSources(opts dependencyCatalogOptions) []string {
 	if opts.EnableCarthage {
 		sources = append(sources, carthageManifestName, carthageResolvedName)
 	}
+	if opts.EnableXcodeProjects {
+		sources = append(sources, xcodeProjectFileName)
+	}
 	return sources
 }
 
@@ -510,6 +524,8 @@ func ensureDeclaredDependencyForManager(catalog *dependencyCatalog, depID string
 		meta.DeclaredViaCocoaPods = true
 	case carthageManager:
 		meta.DeclaredViaCarthage = true
+	case xcodeManager:
+		meta.DeclaredViaXcode = true
 	}
 	catalog.Dependencies[depID] = meta
 }
@@ -0,0 +1,252 @@
+package swift
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pbxParser reads the OpenStep-style property list Xcode writes to
+// project.pbxproj. Values are strings, []any arrays, or map[string]any
+// dictionaries; data literals are kept as their raw text.
+type pbxParser struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+const maxPBXNesting = 64
+
+func parsePBXProj(content []byte) (map[string]any, error) {
+	parser := pbxParser{data: content}
+	value, err := parser.value()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a dictionary at the top level")
+	}
+	if parser.skipSpace(); parser.pos < len(parser.data) {
+		return nil, parser.errorf("unexpected trailing content")
+	}
+	return root, nil
+}
+
+func (p *pbxParser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch p.data[p.pos] {
+	case '{', '(':
+		return p.container()
+	case '"':
+		p.pos++
+		return p.quoted()
+	case '<':
+		return p.dataLiteral()
+	default:
+		return p.unquoted()
+	}
+}
+
+func (p *pbxParser) container() (any, error) {
+	if p.depth >= maxPBXNesting {
+		return nil, p.errorf("nesting deeper than %d levels", maxPBXNesting)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	open := p.data[p.pos]
+	p.pos++
+	if open == '{' {
+		return p.dictionary()
+	}
+	return p.array()
+}
+
+func (p *pbxParser) dictionary() (map[string]any, error) {
+	dict := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == '}' {
+			p.pos++
+			return dict, nil
+		}
+		key, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		keyText, ok := key.(string)
+		if !ok {
+			return nil, p.errorf("expected a string dictionary key")
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+		dict[keyText] = value
+	}
+}
+
+func (p *pbxParser) array() ([]any, error) {
+	items := make([]any, 0)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == ')' {
+			p.pos++
+			return items, nil
+		}
+		item, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+}
+
+func (p *pbxParser) quoted() (string, error) {
+	builder := strings.Builder{}
+	for p.pos < len(p.data) {
+		ch := p.data[p.pos]
+		p.pos++
+		switch ch {
+		case '"':
+			return builder.String(), nil
+		case '\\':
+			if p.pos >= len(p.data) {
+				return "", p.errorf("unterminated escape")
+			}
+			p.unescape(&builder)
+		default:
+			builder.WriteByte(ch)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *pbxParser) unescape(builder *strings.Builder) {
+	ch := p.data[p.pos]
+	p.pos++
+	switch ch {
+	case 'n':
+		builder.WriteByte('\n')
+	case 't':
+		builder.WriteByte('\t')
+	case 'r':
+		builder.WriteByte('\r')
+	case 'U':
+		end := min(p.pos+4, len(p.data))
+		if code, err := strconv.ParseUint(string(p.data[p.pos:end]), 16, 32); err == nil && end-p.pos == 4 {
+			builder.WriteRune(rune(code))
+			p.pos = end
+			return
+		}
+		builder.WriteByte(ch)
+	default:
+		builder.WriteByte(ch)
+	}
+}
+
+func (p *pbxParser) dataLiteral() (string, error) {
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		return "", p.errorf("unterminated data literal")
+	}
+	literal := string(p.data[p.pos : p.pos+end+1])
+	p.pos += end + 1
+	return literal, nil
+}
+
+func (p *pbxParser) unquoted() (string, error) {
+	start := p.pos
+	for p.pos < len(p.data) && isPBXUnquotedByte(p.data[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("unexpected %q", p.data[p.pos])
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+func isPBXUnquotedByte(ch byte) bool {
+	switch {
+	case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		return true
+	default:
+		return strings.IndexByte("_$+/:.-", ch) >= 0
+	}
+}
+
+func (p *pbxParser) expect(ch byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.data) || p.data[p.pos] != ch {
+		return p.errorf("expected %q", ch)
+	}
+	p.pos++
+	return nil
+}
+
+// skipSpace skips whitespace and the /* */ and // comments Xcode writes,
+// including the `// !$*UTF8*$!` header.
+func (p *pbxParser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch rest := p.data[p.pos:]; {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			p.pos++
+		case len(rest) > 1 && rest[0] == '/' && rest[1] == '*':
+			end := bytes.Index(rest[2:], []byte("*/"))
+			if end < 0 {
+				p.pos = len(p.data)
+				return
+			}
+			p.pos += end + 4
+		case len(rest) > 1 && rest[0] == '/' && rest[1] == '/':
+			end := bytes.IndexByte(rest, '\n')
+			if end < 0 {
+				p.pos = len(p.data)
+				return
+			}
+			p.pos += end + 1
+		default:
+			return
+		}
+	}
+}
+
+func (p *pbxParser) errorf(format string, args ...any) error {
+	line := 1 + bytes.Count(p.data[:min(p.pos, len(p.data))], []byte("\n"))
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func pbxString(object map[string]any, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+func pbxStrings(object map[string]any, key string) []string {
+	items, _ := object[key].([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
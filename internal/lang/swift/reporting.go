@@ -63,6 +63,7 @@ func buildDependencyReport(dependency string, scan scanResult, catalog dependenc
 	meta := catalog.Dependencies[dependency]
 	depReport.RiskCues = buildDependencyRiskCues(meta)
 	depReport.Recommendations = buildRecommendations(depReport, meta, minUsagePercent)
+	applyXcodeProductFindings(&depReport, scan.ProductFindings[dependency])
 	if meta.Source != "" {
 		depReport.Provenance = &report.DependencyProvenance{
 			Source:     "manifest/lockfile",
@@ -159,6 +160,16 @@ func dependencyLockfileIssues(meta dependencyMeta) []dependencyLockfileIssue {
 			Rationale:          "Keeping Cartfile.resolved aligned improves reproducibility and Carthage framework attribution fidelity.",
 		})
 	}
+	if meta.DeclaredViaXcode && !meta.DeclaredViaSwiftPM && !meta.ResolvedViaSwiftPM {
+		issues = append(issues, dependencyLockfileIssue{
+			Code:               "missing-lock-resolution",
+			RecommendationCode: "refresh-package-resolved",
+			Manifest:           xcodeProjectFileName,
+			Lockfile:           packageResolvedName,
+			Message:            "dependency is declared in project.pbxproj but missing from the Xcode workspace Package.resolved",
+			Rationale:          "Keeping Package.resolved aligned improves reproducibility and supply-chain traceability.",
+		})
+	}
 	if usesManagerSpecificMetadata(meta) {
 		return issues
 	}
@@ -176,7 +187,7 @@ func dependencyLockfileIssues(meta dependencyMeta) []dependencyLockfileIssue {
 }
 
 func usesManagerSpecificMetadata(meta dependencyMeta) bool {
-	return meta.DeclaredViaSwiftPM || meta.ResolvedViaSwiftPM || meta.DeclaredViaCocoaPods || meta.ResolvedViaCocoaPods || meta.DeclaredViaCarthage || meta.ResolvedViaCarthage
+	return meta.DeclaredViaSwiftPM || meta.ResolvedViaSwiftPM || meta.DeclaredViaCocoaPods || meta.ResolvedViaCocoaPods || meta.DeclaredViaCarthage || meta.ResolvedViaCarthage || meta.DeclaredViaXcode
 }
 
 func resolveMinUsageRecommendationThreshold(value *int) int {
@@ -5,6 +5,7 @@ import "github.com/ben-ranford/lopper/internal/lang/shared"
 const (
 	swiftAdapterID               = "swift"
 	swiftCarthagePreviewFlagName = "swift-carthage"
+	swiftXcodePreviewFlagName    = "swift-xcode-projects-preview"
 	packageManifestName          = "Package.swift"
 	packageResolvedName          = "Package.resolved"
 	podManifestName              = "Podfile"
@@ -22,6 +23,7 @@ const (
 	swiftPackageManager          = "swiftpm"
 	cocoaPodsManager             = "cocoapods"
 	carthageManager              = "carthage"
+	xcodeManager                 = "xcode"
 )
 
 type importBinding = shared.ImportRecord
@@ -44,6 +46,7 @@ type dependencyMeta struct {
 	ResolvedViaCocoaPods bool
 	DeclaredViaCarthage  bool
 	ResolvedViaCarthage  bool
+	DeclaredViaXcode     bool
 }
 
 type dependencyCatalog struct {
@@ -54,6 +57,7 @@ type dependencyCatalog struct {
 	HasSwiftPM         bool
 	HasCocoaPods       bool
 	HasCarthage        bool
+	XcodeProjects      []xcodeProject
 }
 
 type scanResult struct {
@@ -61,6 +65,7 @@ type scanResult struct {
 	Warnings             []string
 	KnownDependencies    map[string]struct{}
 	ImportedDependencies map[string]struct{}
+	ProductFindings      map[string][]xcodeProductFinding
 }
 
 type unqualifiedUsageContext struct {
@@ -0,0 +1,406 @@
+package swift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/lang/shared"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const (
+	xcodeProjectFileName      = "project.pbxproj"
+	xcodeProjectExtension     = ".xcodeproj"
+	xcodeWorkspaceExtension   = ".xcworkspace"
+	xcodePluginProductPrefix  = "plugin:"
+	maxXcodeProjects          = 32
+	maxXcodeProjectBytes      = 16 * 1024 * 1024
+	maxXcodeGroupNesting      = 64
+	xcodeEmbeddedResolvedPath = "xcshareddata/swiftpm/" + packageResolvedName
+)
+
+// xcodeProject is the package and target information read from one
+// project.pbxproj. Paths are repo-relative and slash-separated.
+type xcodeProject struct {
+	Path           string
+	RemotePackages int
+	Targets        []xcodeTarget
+}
+
+// xcodeTarget is a native target with the SwiftPM package products it links
+// and the Swift sources it compiles, listed either file by file in its
+// sources build phase or as folders synchronised with the file system.
+type xcodeTarget struct {
+	Name       string
+	Products   []xcodeTargetProduct
+	Sources    []string
+	SourceDirs []string
+}
+
+type xcodeTargetProduct struct {
+	Name       string
+	Dependency string
+}
+
+// xcodeProductFinding is a package product a target links although none of
+// the target's Swift sources import it.
+type xcodeProductFinding struct {
+	Project     string
+	Target      string
+	Product     string
+	SourceFiles int
+}
+
+func loadXcodeProjectData(repoPath string, catalog *dependencyCatalog) (bool, []string, error) {
+	paths, warnings, err := findXcodeProjects(repoPath)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, path := range paths {
+		relPath := xcodeRelativePath(repoPath, path)
+		content, err := safeio.ReadFileUnderLimit(repoPath, path, maxXcodeProjectBytes)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case shared.IsPureSentinelError(err, safeio.ErrFileTooLarge):
+			warnings = append(warnings, fmt.Sprintf("skipped oversized %s: %v", relPath, err))
+			continue
+		case err != nil:
+			return false, nil, fmt.Errorf("read %s: %w", relPath, err)
+		}
+		root, err := parsePBXProj(content)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped unparseable %s: %v", relPath, err))
+			continue
+		}
+		catalog.XcodeProjects = append(catalog.XcodeProjects, readXcodeProject(repoPath, path, root, catalog))
+	}
+	return len(catalog.XcodeProjects) > 0, warnings, nil
+}
+
+func findXcodeProjects(repoPath string) ([]string, []string, error) {
+	paths := make([]string, 0)
+	capped := false
+	err := shared.WalkRepoFiles(context.Background(), repoPath, 0, shouldSkipDir, func(path string, entry fs.DirEntry) error {
+		if entry.Name() != xcodeProjectFileName || !strings.EqualFold(filepath.Ext(filepath.Dir(path)), xcodeProjectExtension) {
+			return nil
+		}
+		if len(paths) >= maxXcodeProjects {
+			capped = true
+			return fs.SkipAll
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if capped {
+		return paths, []string{fmt.Sprintf("Xcode project discovery capped at %d projects", maxXcodeProjects)}, nil
+	}
+	return paths, nil, nil
+}
+
+// loadXcodeResolvedData reads the Package.resolved files Xcode keeps inside
+// the project's embedded workspace or a sibling .xcworkspace.
+func loadXcodeResolvedData(repoPath string, catalog *dependencyCatalog) (bool, []string, error) {
+	found := false
+	remotePackages := 0
+	for _, project := range catalog.XcodeProjects {
+		remotePackages += project.RemotePackages
+		for _, path := range xcodeResolvedCandidates(repoPath, project) {
+			content, err := safeio.ReadFileUnder(repoPath, path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			relPath := xcodeRelativePath(repoPath, path)
+			if err != nil {
+				return false, nil, fmt.Errorf("read %s: %w", relPath, err)
+			}
+			pins, err := parseResolvedPins(content)
+			if err != nil {
+				return false, nil, fmt.Errorf("parse %s: %w", relPath, err)
+			}
+			found = true
+			for _, pin := range pins {
+				depID := resolvedPinDependencyID(pin)
+				if depID == "" {
+					continue
+				}
+				source := resolvedPinSource(pin)
+				ensureResolvedDependencyForManager(catalog, depID, pin.State.Version, pin.State.Revision, source, swiftPackageManager)
+				addResolvedPinMappings(catalog, depID, pin, source)
+			}
+		}
+	}
+	if !found && remotePackages > 0 {
+		return false, []string{packageResolvedName + " not found in Xcode project or workspace shared data; version/resolution mapping may be incomplete"}, nil
+	}
+	return found, nil, nil
+}
+
+func xcodeResolvedCandidates(repoPath string, project xcodeProject) []string {
+	bundle := filepath.Dir(filepath.Join(repoPath, filepath.FromSlash(project.Path)))
+	candidates := []string{filepath.Join(bundle, "project"+xcodeWorkspaceExtension, filepath.FromSlash(xcodeEmbeddedResolvedPath))}
+	workspaces, _ := filepath.Glob(filepath.Join(filepath.Dir(bundle), "*"+xcodeWorkspaceExtension))
+	for _, workspace := range workspaces {
+		candidates = append(candidates, filepath.Join(workspace, filepath.FromSlash(xcodeEmbeddedResolvedPath)))
+	}
+	return candidates
+}
+
+// readXcodeProject adds the project's remote package references to the
+// catalog, maps linked product names to them, and records each native
+// target's products and Swift sources. Products of local packages and the
+// targets themselves are local modules.
+func readXcodeProject(repoPath, path string, root map[string]any, catalog *dependencyCatalog) xcodeProject {
+	project := xcodeProject{Path: xcodeRelativePath(repoPath, path)}
+	objects, _ := root["objects"].(map[string]any)
+	object := func(id string) map[string]any {
+		value, _ := objects[id].(map[string]any)
+		return value
+	}
+	ids := slices.Sorted(maps.Keys(objects))
+
+	packages := make(map[string]string)
+	for _, id := range ids {
+		obj := object(id)
+		if pbxString(obj, "isa") != "XCRemoteSwiftPackageReference" {
+			continue
+		}
+		url := strings.TrimSpace(pbxString(obj, "repositoryURL"))
+		depID := normalizeDependencyID(derivePackageIdentity(url))
+		if depID == "" {
+			continue
+		}
+		ensureDependency(catalog, depID, true, false, "", "", url)
+		ensureDeclaredDependencyForManager(catalog, depID, xcodeManager)
+		mapAlias(catalog, depID, depID)
+		mapModule(catalog, depID, depID)
+		packages[id] = depID
+		project.RemotePackages++
+	}
+
+	projectDir := filepath.Dir(filepath.Dir(path))
+	rootObject := object(pbxString(root, "rootObject"))
+	if dirPath := pbxString(rootObject, "projectDirPath"); dirPath != "" && !filepath.IsAbs(dirPath) {
+		projectDir = filepath.Join(projectDir, filepath.FromSlash(dirPath))
+	}
+	paths := make(map[string]string)
+	resolvePBXPaths(object, pbxString(rootObject, "mainGroup"), projectDir, projectDir, paths, 0)
+
+	for _, id := range ids {
+		obj := object(id)
+		if pbxString(obj, "isa") != "PBXNativeTarget" {
+			continue
+		}
+		target := xcodeTarget{Name: pbxString(obj, "name")}
+		addLocalModule(catalog, target.Name)
+		for _, productID := range pbxStrings(obj, "packageProductDependencies") {
+			product := object(productID)
+			name := pbxString(product, "productName")
+			if name == "" || strings.HasPrefix(name, xcodePluginProductPrefix) {
+				continue
+			}
+			depID, ok := packages[pbxString(product, "package")]
+			if !ok {
+				addLocalModule(catalog, name)
+				continue
+			}
+			mapModule(catalog, name, depID)
+			target.Products = append(target.Products, xcodeTargetProduct{Name: name, Dependency: depID})
+		}
+		for _, phaseID := range pbxStrings(obj, "buildPhases") {
+			phase := object(phaseID)
+			if pbxString(phase, "isa") != "PBXSourcesBuildPhase" {
+				continue
+			}
+			for _, buildFileID := range pbxStrings(phase, "files") {
+				source, ok := paths[pbxString(object(buildFileID), "fileRef")]
+				if ok && strings.EqualFold(filepath.Ext(source), ".swift") {
+					target.Sources = appendRepoPath(target.Sources, repoPath, source)
+				}
+			}
+		}
+		for _, groupID := range pbxStrings(obj, "fileSystemSynchronizedGroups") {
+			if dir, ok := paths[groupID]; ok {
+				target.SourceDirs = appendRepoPath(target.SourceDirs, repoPath, dir)
+			}
+		}
+		project.Targets = append(project.Targets, target)
+	}
+	sort.Slice(project.Targets, func(i, j int) bool { return project.Targets[i].Name < project.Targets[j].Name })
+	return project
+}
+
+// resolvePBXPaths walks the group tree from the main group and records the
+// file-system path of every group, file reference, and synchronised folder
+// whose location is relative to its group or the project directory.
+func resolvePBXPaths(object func(string) map[string]any, id, parentDir, projectDir string, paths map[string]string, depth int) {
+	obj := object(id)
+	if obj == nil || depth > maxXcodeGroupNesting {
+		return
+	}
+	base := ""
+	switch pbxString(obj, "sourceTree") {
+	case "<group>":
+		base = parentDir
+	case "SOURCE_ROOT":
+		base = projectDir
+	default:
+		return
+	}
+	path := base
+	if relPath := pbxString(obj, "path"); relPath != "" {
+		path = filepath.Join(base, filepath.FromSlash(relPath))
+	}
+	paths[id] = path
+	for _, child := range pbxStrings(obj, "children") {
+		resolvePBXPaths(object, child, path, projectDir, paths, depth+1)
+	}
+}
+
+func appendRepoPath(values []string, repoPath, path string) []string {
+	relPath, err := filepath.Rel(repoPath, path)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return values
+	}
+	return append(values, filepath.ToSlash(relPath))
+}
+
+func addLocalModule(catalog *dependencyCatalog, name string) {
+	if key := lookupKey(name); key != "" {
+		catalog.LocalModules[key] = struct{}{}
+	}
+}
+
+func xcodeRelativePath(repoPath, path string) string {
+	relPath, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// evaluateXcodeTargets reports, per dependency, the package products targets
+// link without any of their Swift sources importing them. A target is only
+// judged when every source it lists was scanned.
+func evaluateXcodeTargets(catalog dependencyCatalog, scan scanResult) (map[string][]xcodeProductFinding, []string) {
+	files := make(map[string]fileScan, len(scan.Files))
+	for _, file := range scan.Files {
+		files[filepath.ToSlash(file.Path)] = file
+	}
+	findings := make(map[string][]xcodeProductFinding)
+	skipped := make([]string, 0)
+	for _, project := range catalog.XcodeProjects {
+		for _, target := range project.Targets {
+			if len(target.Products) == 0 {
+				continue
+			}
+			targetFiles, ok := xcodeTargetFiles(target, files)
+			if !ok {
+				skipped = append(skipped, target.Name)
+				continue
+			}
+			for _, product := range unusedXcodeProducts(target, targetFiles) {
+				findings[product.Dependency] = append(findings[product.Dependency], xcodeProductFinding{
+					Project:     project.Path,
+					Target:      target.Name,
+					Product:     product.Name,
+					SourceFiles: len(targetFiles),
+				})
+			}
+		}
+	}
+	if len(skipped) == 0 {
+		return findings, nil
+	}
+	return findings, []string{fmt.Sprintf("skipped Xcode product analysis for %d target(s) with unscanned or missing Swift sources: %s", len(skipped), strings.Join(skipped, ", "))}
+}
+
+func xcodeTargetFiles(target xcodeTarget, files map[string]fileScan) ([]fileScan, bool) {
+	targetFiles := make([]fileScan, 0, len(target.Sources))
+	for _, source := range target.Sources {
+		file, ok := files[source]
+		if !ok {
+			return nil, false
+		}
+		targetFiles = append(targetFiles, file)
+	}
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		for _, dir := range target.SourceDirs {
+			if strings.HasPrefix(path, dir+"/") {
+				targetFiles = append(targetFiles, files[path])
+				break
+			}
+		}
+	}
+	return targetFiles, len(targetFiles) > 0
+}
+
+// unusedXcodeProducts returns the products no target source imports. A
+// product also counts as used when a source imports another module of its
+// package that the target does not link as a product of its own, since one
+// product can vend several modules.
+func unusedXcodeProducts(target xcodeTarget, files []fileScan) []xcodeTargetProduct {
+	linked := make(map[string]struct{}, len(target.Products))
+	for _, product := range target.Products {
+		linked[lookupKey(product.Name)] = struct{}{}
+	}
+	imported := make(map[string]struct{})
+	viaOtherModules := make(map[string]struct{})
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			key := lookupKey(imp.Module)
+			imported[key] = struct{}{}
+			if _, ok := linked[key]; !ok && imp.Dependency != "" {
+				viaOtherModules[imp.Dependency] = struct{}{}
+			}
+		}
+	}
+	unused := make([]xcodeTargetProduct, 0)
+	for _, product := range target.Products {
+		_, direct := imported[lookupKey(product.Name)]
+		_, indirect := viaOtherModules[product.Dependency]
+		if !direct && !indirect {
+			unused = append(unused, product)
+		}
+	}
+	return unused
+}
+
+// applyXcodeProductFindings flags the targets that link one of the
+// dependency's products without importing it.
+func applyXcodeProductFindings(reportData *report.DependencyReport, findings []xcodeProductFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	targets := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		reportData.RiskCues = append(reportData.RiskCues, report.RiskCue{
+			Code:     "unused-target-product",
+			Severity: "medium",
+			Message:  fmt.Sprintf("target %s in %s links package product %s but none of its %d Swift source file(s) import it", finding.Target, finding.Project, finding.Product, finding.SourceFiles),
+		})
+		targets = append(targets, fmt.Sprintf("%s from target %s (%s)", finding.Product, finding.Target, finding.Project))
+	}
+	rationale := "A package product linked into a target that never imports it is still resolved, built, and linked, adding build time and binary size."
+	if reportData.TotalExportsCount == 0 {
+		rationale += " No Swift source in the repository imports the package, so its package reference may be removable too."
+	}
+	reportData.Recommendations = append(reportData.Recommendations, report.Recommendation{
+		Code:      "remove-target-product-link",
+		Priority:  "medium",
+		Message:   fmt.Sprintf("Remove package product %s.", strings.Join(targets, ", ")),
+		Rationale: rationale,
+	})
+}
@@ -0,0 +1,182 @@
+package swift
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/featureflags"
+	"github.com/ben-ranford/lopper/internal/language"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+const testXcodeProject = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	objects = {
+
+/* Begin PBXBuildFile section */
+		B1 /* AppDelegate.swift in Sources */ = {isa = PBXBuildFile; fileRef = F1 /* AppDelegate.swift */; };
+		B2 /* ContentView.swift in Sources */ = {isa = PBXBuildFile; fileRef = F2 /* ContentView.swift */; };
+		B3 /* Alamofire in Frameworks */ = {isa = PBXBuildFile; productRef = D1 /* Alamofire */; };
+/* End PBXBuildFile section */
+
+		F1 = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = AppDelegate.swift; sourceTree = "<group>"; };
+		F2 = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = "Views/ContentView.swift"; sourceTree = "<group>"; };
+		F3 = {isa = PBXFileReference; explicitFileType = wrapper.application; path = Demo.app; sourceTree = BUILT_PRODUCTS_DIR; };
+		G0 = {isa = PBXGroup; children = (G1, S1, F3, ); sourceTree = "<group>"; };
+		G1 /* App */ = {isa = PBXGroup; children = (F1, F2, ); path = App; sourceTree = "<group>"; };
+		S1 /* AppTests */ = {isa = PBXFileSystemSynchronizedRootGroup; path = AppTests; sourceTree = "<group>"; };
+		P0 /* Sources */ = {isa = PBXSourcesBuildPhase; files = (B1, B2, ); };
+		T1 /* Demo */ = {
+			isa = PBXNativeTarget;
+			buildPhases = (P0, );
+			name = Demo;
+			packageProductDependencies = (D1, D2, D3, D4, );
+		};
+		T2 /* DemoTests */ = {
+			isa = PBXNativeTarget;
+			buildPhases = ( );
+			fileSystemSynchronizedGroups = (S1, );
+			name = DemoTests;
+			packageProductDependencies = (D5, );
+		};
+		R0 /* Project object */ = {isa = PBXProject; mainGroup = G0; packageReferences = (K1, K2, K3, L1, ); projectDirPath = ""; targets = (T1, T2, ); };
+		K1 = {isa = XCRemoteSwiftPackageReference; repositoryURL = "https://github.com/Alamofire/Alamofire.git"; requirement = {kind = upToNextMajorVersion; minimumVersion = 5.8.0; }; };
+		K2 = {isa = XCRemoteSwiftPackageReference; repositoryURL = "https://github.com/onevcat/Kingfisher"; requirement = {kind = upToNextMajorVersion; minimumVersion = 7.0.0; }; };
+		K3 = {isa = XCRemoteSwiftPackageReference; repositoryURL = "https://github.com/Quick/Nimble.git"; requirement = {branch = main; kind = branch; }; };
+		L1 = {isa = XCLocalSwiftPackageReference; relativePath = Packages/CoreKit; };
+		D1 = {isa = XCSwiftPackageProductDependency; package = K1; productName = Alamofire; };
+		D2 = {isa = XCSwiftPackageProductDependency; package = K2; productName = Kingfisher; };
+		D3 = {isa = XCSwiftPackageProductDependency; package = L1; productName = CoreKit; };
+		D4 = {isa = XCSwiftPackageProductDependency; package = K1; productName = "plugin:Lint"; };
+		D5 = {isa = XCSwiftPackageProductDependency; package = K3; productName = Nimble; };
+	};
+	rootObject = R0 /* Project object */;
+}
+`
+
+const testXcodeResolved = `{
+  "pins" : [
+    {"identity" : "alamofire", "kind" : "remoteSourceControl", "location" : "https://github.com/Alamofire/Alamofire.git", "state" : {"revision" : "abc", "version" : "5.8.1"}},
+    {"identity" : "kingfisher", "kind" : "remoteSourceControl", "location" : "https://github.com/onevcat/Kingfisher", "state" : {"revision" : "def", "version" : "7.10.0"}}
+  ],
+  "version" : 2
+}
+`
+
+func TestParsePBXProjReadsObjectsAndEscapes(t *testing.T) {
+	root, err := parsePBXProj([]byte(`{ a = "x\"y\U00e9"; b = (one, "two", ); c = <0a0b>; }`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := pbxString(root, "a"); got != "x\"yé" {
+		t.Fatalf("unexpected escaped string %q", got)
+	}
+	if got := pbxStrings(root, "b"); !slices.Equal(got, []string{"one", "two"}) {
+		t.Fatalf("unexpected array %#v", got)
+	}
+	if got := pbxString(root, "c"); got != "<0a0b>" {
+		t.Fatalf("unexpected data literal %q", got)
+	}
+	for _, content := range []string{`{ a = b }`, `{ a = "b; }`, `( a )`, strings.Repeat("(", maxPBXNesting+1)} {
+		if _, err := parsePBXProj([]byte(content)); err == nil {
+			t.Fatalf("expected parse error for %q", content)
+		}
+	}
+}
+
+func TestSwiftXcodeProjectReportsUnusedTargetProducts(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "Demo.xcodeproj", xcodeProjectFileName), testXcodeProject)
+	testutil.MustWriteFile(t, filepath.Join(repo, "Demo.xcodeproj", "project.xcworkspace", "xcshareddata", "swiftpm", packageResolvedName), testXcodeResolved)
+	testutil.MustWriteFile(t, filepath.Join(repo, "App", "AppDelegate.swift"), "import UIKit\nimport CoreKit\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "App", "Views", "ContentView.swift"), "import Alamofire\n\nlet session = Session.default\n")
+	testutil.MustWriteFile(t, filepath.Join(repo, "AppTests", "DemoTests.swift"), "import XCTest\n@testable import Demo\nimport Nimble\n\nfunc check() { expect(1).to(equal(1)) }\n")
+
+	reportData := mustAnalyseSwiftRequest(t, language.Request{RepoPath: repo, TopN: 10, Features: xcodeProjectsPreview(t)})
+	names := make([]string, 0, len(reportData.Dependencies))
+	for _, dep := range reportData.Dependencies {
+		names = append(names, dep.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"alamofire", "kingfisher", "nimble"}) {
+		t.Fatalf("expected pbxproj package references in the catalog, got %#v", names)
+	}
+
+	kingfisher := requireSwiftDependency(t, reportData.Dependencies, "kingfisher")
+	if !hasRiskCueCode(kingfisher, "unused-target-product") {
+		t.Fatalf("expected unused Kingfisher product cue, got %#v", kingfisher.RiskCues)
+	}
+	if len(kingfisher.Recommendations) == 0 || kingfisher.Recommendations[len(kingfisher.Recommendations)-1].Message != "Remove package product Kingfisher from target Demo (Demo.xcodeproj/project.pbxproj)." {
+		t.Fatalf("expected target-scoped removal recommendation, got %#v", kingfisher.Recommendations)
+	}
+	if kingfisher.Provenance == nil || kingfisher.Provenance.Signals[0] != "https://github.com/onevcat/Kingfisher" {
+		t.Fatalf("expected repository URL provenance, got %#v", kingfisher.Provenance)
+	}
+	for _, name := range []string{"alamofire", "nimble"} {
+		if dep := requireSwiftDependency(t, reportData.Dependencies, name); hasRiskCueCode(dep, "unused-target-product") {
+			t.Fatalf("expected %s product to be imported by its target, got %#v", name, dep.RiskCues)
+		}
+	}
+	nimble := requireSwiftDependency(t, reportData.Dependencies, "nimble")
+	if !hasRiskCueCode(nimble, "missing-lock-resolution") || !strings.Contains(nimble.RiskCues[0].Message, "project.pbxproj") {
+		t.Fatalf("expected missing Xcode resolution cue for nimble, got %#v", nimble.RiskCues)
+	}
+	for _, warning := range reportData.Warnings {
+		if strings.Contains(warning, "CoreKit") || strings.Contains(warning, packageManifestName+" not found") {
+			t.Fatalf("expected local products and Xcode-only catalogs to stay quiet, got %q", warning)
+		}
+	}
+
+	baseline := mustAnalyseSwiftRequest(t, language.Request{RepoPath: repo, TopN: 10})
+	for _, dep := range baseline.Dependencies {
+		if dep.Name == "kingfisher" {
+			t.Fatalf("expected no pbxproj declarations without the preview flag")
+		}
+	}
+}
+
+func TestEvaluateXcodeTargetsSkipsTargetsWithUnscannedSources(t *testing.T) {
+	catalog := dependencyCatalog{XcodeProjects: []xcodeProject{{
+		Path: "Demo.xcodeproj/project.pbxproj",
+		Targets: []xcodeTarget{
+			{Name: "Widget", Products: []xcodeTargetProduct{{Name: "Kingfisher", Dependency: "kingfisher"}}, Sources: []string{"Widget/Missing.swift"}},
+			{Name: "Shared", Products: []xcodeTargetProduct{{Name: "FirebaseAnalytics", Dependency: "firebase-ios-sdk"}}, SourceDirs: []string{"Shared"}},
+		},
+	}}}
+	scan := scanResult{Files: []fileScan{{Path: "Shared/Tracking.swift", Imports: []importBinding{{Module: "FirebaseCore", Dependency: "firebase-ios-sdk"}}}}}
+
+	findings, warnings := evaluateXcodeTargets(catalog, scan)
+	if len(findings) != 0 {
+		t.Fatalf("expected a product used through another module of its package, got %#v", findings)
+	}
+	if !slices.Equal(warnings, []string{"skipped Xcode product analysis for 1 target(s) with unscanned or missing Swift sources: Widget"}) {
+		t.Fatalf("unexpected warnings %#v", warnings)
+	}
+}
+
+func requireSwiftDependency(t *testing.T, dependencies []report.DependencyReport, name string) report.DependencyReport {
+	t.Helper()
+	for _, dep := range dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	t.Fatalf("expected dependency %q in %#v", name, dependencies)
+	return report.DependencyReport{}
+}
+
+func xcodeProjectsPreview(t *testing.T) featureflags.Set {
+	t.Helper()
+	features, err := featureflags.DefaultRegistry().Resolve(featureflags.ResolveOptions{
+		Channel: featureflags.ChannelDev,
+		Enable:  []string{swiftXcodePreviewFlagName},
+	})
+	if err != nil {
+		t.Fatalf("resolve features: %v", err)
+	}
+	return features
+}
@@ -2,6 +2,9 @@ package language
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -22,6 +25,56 @@ type AnalysisOptions struct {
 	MinUsagePercentForRecommendations *int
 	RemovalCandidateWeights           *report.RemovalCandidateWeights
 	IncludeRegistryProvenance         bool
+	FileCache                         FileCache
+}
+
+// FileCache is the file-level tier of the analysis cache. Adapters keep the
+// parse result of one source file under its content, repo-relative path, and
+// an adapter schema version, so unchanged files are not parsed again when
+// other files in the root change. A nil FileCache caches nothing.
+type FileCache interface {
+	Load(key FileCacheKey, dest any) bool
+	Store(key FileCacheKey, value any)
+}
+
+// FileCacheKey identifies a per-file parse result. Schema must change
+// whenever the adapter changes what it stores or how it parses.
+type FileCacheKey struct {
+	Adapter string
+	Schema  string
+	Path    string
+	Content []byte
+	// Inputs is a FileCacheInputs digest of the repo-wide state the result
+	// depends on besides the file itself, such as the declared dependencies
+	// its imports resolve against. Empty when the file alone decides it.
+	Inputs string
+}
+
+// FileCacheInputs digests the repo-wide values a per-file result depends on.
+// It returns false when value cannot be encoded; callers should then skip
+// the cache rather than risk reusing results computed under other inputs.
+func FileCacheInputs(value any) (string, bool) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// LoadCachedFile reads a parse result from cache, treating a nil cache as a miss.
+func LoadCachedFile(cache FileCache, key FileCacheKey, dest any) bool {
+	if cache == nil {
+		return false
+	}
+	return cache.Load(key, dest)
+}
+
+// StoreCachedFile writes a parse result to cache when one is configured.
+func StoreCachedFile(cache FileCache, key FileCacheKey, value any) {
+	if cache != nil {
+		cache.Store(key, value)
+	}
 }
 
 // Request is kept as a compatibility alias for older adapter tests and callers.
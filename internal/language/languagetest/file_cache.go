@@ -0,0 +1,51 @@
+// Package languagetest provides test doubles for language adapters.
+package languagetest
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ben-ranford/lopper/internal/language"
+)
+
+// MemoryFileCache is an in-memory language.FileCache. Values round-trip
+// through JSON like the on-disk tier, so results that do not survive
+// encoding fail tests instead of production runs.
+type MemoryFileCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	Hits    int
+	Misses  int
+	Writes  int
+}
+
+func NewMemoryFileCache() *MemoryFileCache {
+	return &MemoryFileCache{entries: make(map[string][]byte)}
+}
+
+func (c *MemoryFileCache) Load(key language.FileCacheKey, dest any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.entries[entryKey(key)]
+	if !ok || json.Unmarshal(data, dest) != nil {
+		c.Misses++
+		return false
+	}
+	c.Hits++
+	return true
+}
+
+func (c *MemoryFileCache) Store(key language.FileCacheKey, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[entryKey(key)] = data
+	c.Writes++
+}
+
+func entryKey(key language.FileCacheKey) string {
+	return key.Adapter + "\x00" + key.Schema + "\x00" + key.Path + "\x00" + key.Inputs + "\x00" + string(key.Content)
+}
@@ -91,6 +91,8 @@ type mutationAnalysisArguments struct {
 	ScoreWeightImpact                 *float64 `json:"scoreWeightImpact,omitempty"`
 	ScoreWeightConfidence             *float64 `json:"scoreWeightConfidence,omitempty"`
 	LicenseDeny                       []string `json:"licenseDeny,omitempty"`
+	LicenseAllow                      []string `json:"licenseAllow,omitempty"`
+	LicenseUnknown                    *string  `json:"licenseUnknown,omitempty"`
 	LicenseFailOnDeny                 *bool    `json:"licenseFailOnDeny,omitempty"`
 	LicenseProvenanceRegistry         *bool    `json:"licenseProvenanceRegistry,omitempty"`
 	TimeoutMillis                     int      `json:"timeoutMillis,omitempty"`
@@ -403,6 +405,8 @@ func analysisArgsFromMutation(args mutationAnalysisArguments) analysisToolArgume
 		ScoreWeightImpact:                 args.ScoreWeightImpact,
 		ScoreWeightConfidence:             args.ScoreWeightConfidence,
 		LicenseDeny:                       append([]string{}, args.LicenseDeny...),
+		LicenseAllow:                      append([]string{}, args.LicenseAllow...),
+		LicenseUnknown:                    args.LicenseUnknown,
 		LicenseFailOnDeny:                 args.LicenseFailOnDeny,
 		LicenseProvenanceRegistry:         args.LicenseProvenanceRegistry,
 		TimeoutMillis:                     args.TimeoutMillis,
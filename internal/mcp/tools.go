@@ -81,6 +81,8 @@ type analysisToolArguments struct {
 	ScoreWeightImpact                 *float64 `json:"scoreWeightImpact,omitempty"`
 	ScoreWeightConfidence             *float64 `json:"scoreWeightConfidence,omitempty"`
 	LicenseDeny                       []string `json:"licenseDeny,omitempty"`
+	LicenseAllow                      []string `json:"licenseAllow,omitempty"`
+	LicenseUnknown                    *string  `json:"licenseUnknown,omitempty"`
 	LicenseFailOnDeny                 *bool    `json:"licenseFailOnDeny,omitempty"`
 	LicenseProvenanceRegistry         *bool    `json:"licenseProvenanceRegistry,omitempty"`
 	BaselinePath                      string   `json:"baselinePath,omitempty"`
@@ -402,6 +404,8 @@ func newAnalysisRequest(args analysisToolArguments, req analysisRequestContext)
 		MinUsagePercentForRecommendations: &minUsage,
 		RemovalCandidateWeights:           &weights,
 		LicenseDenyList:                   append([]string{}, req.thresholds.LicenseDenyList...),
+		LicenseAllowList:                  append([]string{}, req.thresholds.LicenseAllowList...),
+		LicenseUnknownPolicy:              req.thresholds.LicenseUnknownPolicy,
 		IncludeRegistryProvenance:         req.thresholds.LicenseIncludeRegistryProvenance,
 		Cache:                             cacheOptions,
 	}
@@ -665,6 +669,8 @@ func resolveThresholds(repoPath string, args analysisToolArguments) (thresholds.
 		RemovalCandidateWeightImpact:      args.ScoreWeightImpact,
 		RemovalCandidateWeightConfidence:  args.ScoreWeightConfidence,
 		LicenseDenyList:                   append([]string{}, args.LicenseDeny...),
+		LicenseAllowList:                  append([]string{}, args.LicenseAllow...),
+		LicenseUnknownPolicy:              args.LicenseUnknown,
 		LicenseFailOnDeny:                 args.LicenseFailOnDeny,
 		LicenseIncludeRegistryProvenance:  args.LicenseProvenanceRegistry,
 		ReachableVulnerabilityPriority:    args.ReachableVulnerabilityPriority,
@@ -693,6 +699,8 @@ func hasMCPPolicyOverrides(args analysisToolArguments) bool {
 		args.ScoreWeightImpact != nil ||
 		args.ScoreWeightConfidence != nil ||
 		len(args.LicenseDeny) > 0 ||
+		len(args.LicenseAllow) > 0 ||
+		args.LicenseUnknown != nil ||
 		args.LicenseFailOnDeny != nil ||
 		args.LicenseProvenanceRegistry != nil ||
 		args.ReachableVulnerabilityPriority != nil ||
@@ -759,6 +767,12 @@ func mcpPolicyTrace(args analysisToolArguments) []report.PolicyMergeTrace {
 	if len(args.LicenseDeny) > 0 {
 		trace = append(trace, report.PolicyMergeTrace{Field: "license.deny", Source: "mcp"})
 	}
+	if len(args.LicenseAllow) > 0 {
+		trace = append(trace, report.PolicyMergeTrace{Field: "license.allow", Source: "mcp"})
+	}
+	if args.LicenseUnknown != nil {
+		trace = append(trace, report.PolicyMergeTrace{Field: "license.unknown", Source: "mcp"})
+	}
 	if args.LicenseFailOnDeny != nil {
 		trace = append(trace, report.PolicyMergeTrace{Field: "license.fail_on_deny", Source: "mcp"})
 	}
@@ -860,6 +874,8 @@ func decorateReport(reportData *report.Report, values thresholds.Values, policyS
 func licensePolicy(values thresholds.Values) report.LicensePolicy {
 	return report.LicensePolicy{
 		Deny:                      report.SortedDenyList(values.LicenseDenyList),
+		Allow:                     report.SortedLicensePatterns(values.LicenseAllowList),
+		Unknown:                   report.NormalizeLicenseUnknownPolicy(values.LicenseUnknownPolicy),
 		FailOnDenied:              values.LicenseFailOnDeny,
 		IncludeRegistryProvenance: values.LicenseIncludeRegistryProvenance,
 	}
@@ -1096,6 +1112,8 @@ func commonAnalysisProperties() map[string]any {
 		"scoreWeightImpact":                 map[string]any{"type": "number", "minimum": 0},
 		"scoreWeightConfidence":             map[string]any{"type": "number", "minimum": 0},
 		"licenseDeny":                       stringArraySchema(),
+		"licenseAllow":                      stringArraySchema(),
+		"licenseUnknown":                    map[string]any{"type": "string", "enum": []string{report.LicenseUnknownAllow, report.LicenseUnknownWarn, report.LicenseUnknownDeny}, "default": report.LicenseUnknownAllow},
 		"licenseFailOnDeny":                 map[string]any{"type": "boolean"},
 		"licenseProvenanceRegistry":         map[string]any{"type": "boolean"},
 		"timeoutMillis":                     map[string]any{"type": "integer", "minimum": 1, "maximum": maxTimeoutMillis},
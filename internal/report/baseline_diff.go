@@ -98,6 +98,7 @@ func computeBaselineComparisonFromPairs(current, baseline Report, pairs []Depend
 	appendDependencyInstanceDeltas(&comparison, pairs)
 	comparison.NewDeniedLicenses = newlyDeniedLicensesFromPairs(pairs)
 	comparison.NewReachableVulnerabilities = newlyReachableVulnerabilitiesFromPairs(pairs)
+	comparison.NewRuleFindings = newRuleFindings(current.RuleFindings, baseline.RuleFindings)
 
 	return comparison
 }
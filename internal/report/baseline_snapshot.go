@@ -33,8 +33,37 @@ func Load(path string) (Report, error) {
 	return rep, nil
 }
 
+// LoadWithKey loads a JSON report, a baseline snapshot, or an NDJSON report.
+// NDJSON reports are decoded record by record; their result records, the
+// finished report's merged and annotated rows, keep only the dependency
+// fields baseline comparison reads.
 func LoadWithKey(path string) (Report, string, error) {
-	return baselineutil.LoadConfiguredSnapshot(path, baselineSnapshots)
+	var dependencies []DependencyReport
+	rep, isNDJSON, err := LoadNDJSON(path, func(dependency DependencyReport) error {
+		dependencies = append(dependencies, baselineDependency(dependency))
+		return nil
+	})
+	if err != nil {
+		return Report{}, "", err
+	}
+	if !isNDJSON {
+		return baselineutil.LoadConfiguredSnapshot(path, baselineSnapshots)
+	}
+	rep.Dependencies = dependencies
+	return repairSnapshotReport(rep), "", nil
+}
+
+// baselineDependency drops the per-import and per-symbol evidence that
+// dominates large reports; baseline comparison only reads counts, identity,
+// license, vulnerability, and runtime fields.
+func baselineDependency(dependency DependencyReport) DependencyReport {
+	dependency.TopUsedSymbols = nil
+	dependency.UsedImports = nil
+	dependency.UnusedImports = nil
+	dependency.UnusedExports = nil
+	dependency.SuppressedUnusedImports = nil
+	dependency.Codemod = nil
+	return dependency
 }
 
 func LoadSnapshot(dir, key string) (Report, string, string, error) {
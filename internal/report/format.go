@@ -18,6 +18,10 @@ func (f *Formatter) Format(report Report, format Format) (string, error) {
 		return formatCSV(report)
 	case FormatJSON:
 		return formatJSON(report)
+	case FormatNDJSON:
+		return formatNDJSON(report)
+	case FormatHTML:
+		return formatHTML(report), nil
 	case FormatSARIF:
 		return formatSARIF(report)
 	case FormatPRComment:
@@ -28,6 +32,12 @@ func (f *Formatter) Format(report Report, format Format) (string, error) {
 		return formatSPDXJSON(report)
 	case FormatVEX:
 		return formatCycloneDXVEXJSON(report)
+	case FormatJUnit:
+		return formatJUnit(report)
+	case FormatCodeQuality:
+		return formatGitLabCodeQuality(report)
+	case FormatTemplate:
+		return "", ErrTemplateRequired
 	default:
 		return "", ErrUnknownFormat
 	}
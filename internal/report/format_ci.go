@@ -0,0 +1,59 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+const CIReportFormatsPreviewFeature = "ci-report-formats-preview"
+
+// ciDependencyFailures lists why a dependency should fail a CI gate: a denied
+// license or a reachable, unsuppressed vulnerability. When a reachable
+// priority threshold is configured only findings at or above it count.
+func ciDependencyFailures(rep Report, dep DependencyReport) []string {
+	failures := make([]string, 0)
+	if dep.License != nil && dep.License.Denied {
+		failures = append(failures, fmt.Sprintf("license %s is denied by policy", ciLicenseLabel(dep.License)))
+	}
+	thresholds, _ := gateThresholds(rep)
+	for _, finding := range ciReachableVulnerabilities(dep, thresholds.ReachableVulnerabilityPriority) {
+		failures = append(failures, fmt.Sprintf("reachable vulnerability %s with %s priority", finding.AdvisoryID, finding.Priority))
+	}
+	return failures
+}
+
+func ciReachableVulnerabilities(dep DependencyReport, threshold string) []VulnerabilityFinding {
+	if NormalizeVulnerabilityPriorityThreshold(threshold) != VulnerabilityPriorityOff {
+		return reachableVulnerabilitiesAtOrAbove(dep, threshold)
+	}
+	var findings []VulnerabilityFinding
+	for _, finding := range dep.Vulnerabilities {
+		if finding.Reachable && !FindingSuppressedByException(finding) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func ciLicenseLabel(license *DependencyLicense) string {
+	for _, value := range []string{license.SPDX, license.Raw} {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return "unknown"
+}
+
+// ciManifestPath returns the manifest that declared dep when identity
+// enrichment recorded one, so findings without an import location can still
+// point at a file.
+func ciManifestPath(dep DependencyReport) string {
+	if dep.Identity == nil {
+		return ""
+	}
+	source := strings.TrimSpace(dep.Identity.Source)
+	if source == "" || source == "language-adapter" || strings.Contains(source, "://") {
+		return ""
+	}
+	return strings.ReplaceAll(source, "\\", "/")
+}
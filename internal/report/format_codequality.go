@@ -0,0 +1,216 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// codeQualityIssue is one entry of a GitLab Code Quality (Code Climate) report.
+type codeQualityIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// formatGitLabCodeQuality maps the SARIF signals, plus denied licenses, onto
+// Code Climate issues. Issues sit on the first import of the dependency, or
+// on its manifest when no import was recorded. Fingerprints hash the rule,
+// dependency, finding identity, and file but not the line or message, so an
+// issue keeps its fingerprint when surrounding code moves.
+func formatGitLabCodeQuality(rep Report) (string, error) {
+	issues := make([]codeQualityIssue, 0)
+	baselineDeltas := baselineDependencyDeltasForDependencies(rep.Dependencies, rep.BaselineComparison)
+	rules := newSARIFRuleBuilder()
+	for index, dep := range rep.Dependencies {
+		fallback := codeQualityManifestLocation(dep)
+		for _, result := range dependencySARIFResults(dep, rules, baselineDeltas[index]) {
+			issues = append(issues, codeQualityIssueFromResult(rep, result, fallback))
+		}
+		if dep.License != nil && dep.License.Denied {
+			issues = append(issues, codeQualityDeniedLicenseIssue(rep, dep, fallback))
+		}
+	}
+	var wasteResults []sarifResult
+	appendWasteIncreaseResult(&wasteResults, rules, rep.WasteIncreasePercent, rep.BaselineComparison)
+	for _, result := range wasteResults {
+		issues = append(issues, codeQualityIssueFromResult(rep, result, codeQualityLocation{Path: ".", Lines: codeQualityLines{Begin: 1}}))
+	}
+
+	sortCodeQualityIssues(issues)
+	assignCodeQualityFingerprints(issues)
+
+	payload, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(payload) + "\n", nil
+}
+
+func codeQualityIssueFromResult(rep Report, result sarifResult, fallback codeQualityLocation) codeQualityIssue {
+	location := fallback
+	if len(result.Locations) > 0 {
+		location = codeQualityLocationFromSARIF(rep.RepoPath, result.Locations[0])
+	}
+	return codeQualityIssue{
+		Type:        "issue",
+		CheckName:   result.RuleID,
+		Description: result.Message.Text,
+		Categories:  []string{codeQualityCategory(result.RuleID)},
+		Severity:    codeQualitySeverity(result.Level),
+		Fingerprint: codeQualityFingerprintSeed(
+			result.RuleID,
+			codeQualityProperty(result.Properties, "language"),
+			codeQualityProperty(result.Properties, "dependency"),
+			codeQualityProperty(result.Properties, "module"),
+			codeQualityProperty(result.Properties, "symbol"),
+			codeQualityProperty(result.Properties, "advisoryId"),
+			location.Path,
+		),
+		Location: location,
+	}
+}
+
+func codeQualityDeniedLicenseIssue(rep Report, dep DependencyReport, fallback codeQualityLocation) codeQualityIssue {
+	location := fallback
+	if anchor := dependencyAnchorLocation(dep); anchor != nil && fallback.Path == "." {
+		location = codeQualityLocationFromSARIF(rep.RepoPath, *anchor)
+	}
+	ruleID := "lopper/license/denied"
+	return codeQualityIssue{
+		Type:        "issue",
+		CheckName:   ruleID,
+		Description: fmt.Sprintf("%s: license %s is denied by policy.", dep.Name, ciLicenseLabel(dep.License)),
+		Categories:  []string{codeQualityCategory(ruleID)},
+		Severity:    "major",
+		Fingerprint: codeQualityFingerprintSeed(ruleID, dep.Language, dep.Name, ciLicenseLabel(dep.License), location.Path),
+		Location:    location,
+	}
+}
+
+// codeQualityManifestLocation points at the manifest line for dep. Lopper
+// does not record manifest line numbers, so the issue sits on line 1.
+func codeQualityManifestLocation(dep DependencyReport) codeQualityLocation {
+	manifest := ciManifestPath(dep)
+	if manifest == "" {
+		manifest = "."
+	}
+	return codeQualityLocation{Path: manifest, Lines: codeQualityLines{Begin: 1}}
+}
+
+func codeQualityLocationFromSARIF(repoPath string, location sarifLocation) codeQualityLocation {
+	line := 1
+	if region := location.PhysicalLocation.Region; region != nil && region.StartLine > 0 {
+		line = region.StartLine
+	}
+	return codeQualityLocation{
+		Path:  codeQualityRelativePath(repoPath, location.PhysicalLocation.ArtifactLocation.URI),
+		Lines: codeQualityLines{Begin: line},
+	}
+}
+
+// codeQualityRelativePath turns a SARIF artifact URI back into a
+// repository-relative path, which is what GitLab uses to place issues.
+func codeQualityRelativePath(repoPath, uri string) string {
+	if !strings.HasPrefix(uri, "file://") {
+		return uri
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	absolute := filepath.FromSlash(parsed.Path)
+	root, err := filepath.Abs(strings.TrimSpace(repoPath))
+	if err != nil {
+		return parsed.Path
+	}
+	relative, err := filepath.Rel(root, absolute)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return parsed.Path
+	}
+	return path.Clean(filepath.ToSlash(relative))
+}
+
+func codeQualityCategory(ruleID string) string {
+	switch {
+	case strings.HasPrefix(ruleID, "lopper/vulnerability/"), strings.HasPrefix(ruleID, "lopper/risk/"):
+		return "Security"
+	case strings.HasPrefix(ruleID, "lopper/license/"):
+		return "Bug Risk"
+	case strings.HasPrefix(ruleID, "lopper/waste/"):
+		return "Performance"
+	default:
+		return "Clarity"
+	}
+}
+
+func codeQualitySeverity(level string) string {
+	switch level {
+	case "error":
+		return "major"
+	case "warning":
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+func codeQualityProperty(properties map[string]any, key string) string {
+	value, _ := properties[key].(string)
+	return value
+}
+
+func codeQualityFingerprintSeed(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+func sortCodeQualityIssues(issues []codeQualityIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Location.Path != issues[j].Location.Path {
+			return issues[i].Location.Path < issues[j].Location.Path
+		}
+		if issues[i].Location.Lines.Begin != issues[j].Location.Lines.Begin {
+			return issues[i].Location.Lines.Begin < issues[j].Location.Lines.Begin
+		}
+		if issues[i].CheckName != issues[j].CheckName {
+			return issues[i].CheckName < issues[j].CheckName
+		}
+		return issues[i].Description < issues[j].Description
+	})
+}
+
+// assignCodeQualityFingerprints replaces each seed with its digest. Seeds that
+// collide, such as two risk cues with the same code on one dependency, are
+// disambiguated by their order among the sorted issues.
+func assignCodeQualityFingerprints(issues []codeQualityIssue) {
+	seen := make(map[string]int, len(issues))
+	for i := range issues {
+		seed := issues[i].Fingerprint
+		ordinal := seen[seed]
+		seen[seed] = ordinal + 1
+		if ordinal > 0 {
+			seed = fmt.Sprintf("%s\x00%d", seed, ordinal)
+		}
+		digest := sha256.Sum256([]byte(seed))
+		issues[i].Fingerprint = hex.EncodeToString(digest[:])
+	}
+}
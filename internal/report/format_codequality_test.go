@@ -0,0 +1,96 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func formatCodeQualityIssues(t *testing.T, rep Report) []codeQualityIssue {
+	t.Helper()
+	output, err := NewFormatter().Format(rep, FormatCodeQuality)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("code quality output is not a JSON array: %v\n%s", err, output)
+	}
+	return issues
+}
+
+func TestFormatGitLabCodeQualityMapsSARIFSignals(t *testing.T) {
+	issues := formatCodeQualityIssues(t, sampleCIReport())
+	byCheck := map[string]codeQualityIssue{}
+	seen := map[string]struct{}{}
+	for _, issue := range issues {
+		if issue.Type != "issue" || len(issue.Fingerprint) != 64 {
+			t.Fatalf("unexpected issue shape: %#v", issue)
+		}
+		if _, duplicate := seen[issue.Fingerprint]; duplicate {
+			t.Fatalf("duplicate fingerprint %s", issue.Fingerprint)
+		}
+		seen[issue.Fingerprint] = struct{}{}
+		byCheck[issue.CheckName] = issue
+	}
+
+	vulnerability := byCheck["lopper/vulnerability/ghsa-test"]
+	if vulnerability.Severity != "major" || vulnerability.Categories[0] != "Security" || vulnerability.Location.Path != "src/app.js" || vulnerability.Location.Lines.Begin != 3 {
+		t.Fatalf("unexpected vulnerability issue: %#v", vulnerability)
+	}
+	unusedImport := byCheck["lopper/waste/unused-import"]
+	if unusedImport.Severity != "minor" || unusedImport.Location.Path != "src/util.js" || unusedImport.Location.Lines.Begin != 7 {
+		t.Fatalf("unexpected unused import issue: %#v", unusedImport)
+	}
+	license := byCheck["lopper/license/denied"]
+	if license.Location.Path != "package.json" || license.Location.Lines.Begin != 1 {
+		t.Fatalf("expected denied license at the manifest, got %#v", license)
+	}
+	if waste := byCheck["lopper/waste/increase"]; waste.Location.Path != "." {
+		t.Fatalf("expected waste increase at the repository root, got %#v", waste)
+	}
+}
+
+func TestFormatGitLabCodeQualityFingerprintsSurviveLineShifts(t *testing.T) {
+	before := formatCodeQualityIssues(t, sampleCIReport())
+	shifted := sampleCIReport()
+	shifted.Dependencies[0].UsedImports[0].Locations[0].Line = 30
+	shifted.Dependencies[0].UnusedImports[0].Locations[0].Line = 70
+	after := formatCodeQualityIssues(t, shifted)
+
+	fingerprints := func(issues []codeQualityIssue) map[string]string {
+		out := map[string]string{}
+		for _, issue := range issues {
+			out[issue.Fingerprint] = issue.CheckName
+		}
+		return out
+	}
+	want, got := fingerprints(before), fingerprints(after)
+	if len(want) != len(got) {
+		t.Fatalf("issue count changed: %d vs %d", len(want), len(got))
+	}
+	for fingerprint, check := range want {
+		if got[fingerprint] != check {
+			t.Fatalf("fingerprint for %s changed after a line shift", check)
+		}
+	}
+}
+
+func TestFormatGitLabCodeQualityEmptyReport(t *testing.T) {
+	output, err := NewFormatter().Format(Report{}, FormatCodeQuality)
+	if err != nil || output != "[]\n" {
+		t.Fatalf("expected empty issue array, got %q, %v", output, err)
+	}
+}
+
+func TestCodeQualityRelativePath(t *testing.T) {
+	cases := map[string]string{
+		"src/app.js":              "src/app.js",
+		"file:///repo/src/app.js": "src/app.js",
+		"file:///elsewhere/x.js":  "/elsewhere/x.js",
+	}
+	for uri, want := range cases {
+		if got := codeQualityRelativePath("/repo", uri); got != want {
+			t.Fatalf("codeQualityRelativePath(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
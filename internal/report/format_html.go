@@ -0,0 +1,430 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const HTMLReportPreviewFeature = "html-report-preview"
+
+const (
+	reportHTMLTableBodyOpen  = "</tr></thead><tbody>"
+	reportHTMLTableBodyClose = "</tbody></table>"
+)
+
+// reportHTMLStyle and reportHTMLScript are inlined so the report renders
+// offline; the Content-Security-Policy forbids every external fetch.
+const reportHTMLStyle = "body{font-family:system-ui,-apple-system,Segoe UI,Roboto,sans-serif;margin:24px;color:#111827;background:#f8fafc}" +
+	"h1,h2,h3{margin:0 0 12px}h3{font-size:15px;margin-top:14px}" +
+	".meta{margin:0 0 20px;color:#475569}" +
+	"table{width:100%;border-collapse:collapse;background:#fff;border:1px solid #e2e8f0;margin-bottom:20px}" +
+	"th,td{padding:8px 10px;border-bottom:1px solid #e2e8f0;text-align:left;font-size:14px;vertical-align:top}" +
+	"th{background:#f1f5f9}th[data-sort]{cursor:pointer;user-select:none}" +
+	"th[aria-sort=ascending]::after{content:\" \\25B2\"}th[aria-sort=descending]::after{content:\" \\25BC\"}" +
+	".card{background:#fff;border:1px solid #e2e8f0;padding:16px;margin-bottom:20px}" +
+	".grid{display:grid;grid-template-columns:repeat(auto-fit,minmax(180px,1fr));gap:12px}" +
+	".metric{background:#f8fafc;border:1px solid #e2e8f0;padding:12px;border-radius:8px}" +
+	".metric strong{display:block;font-size:22px}" +
+	".drawer td{background:#f8fafc}.drawer summary{cursor:pointer;color:#334155}" +
+	".drawer table{margin:6px 0 12px}.drawer ul{margin:4px 0 12px;padding-left:20px}" +
+	".num{text-align:right;font-variant-numeric:tabular-nums}" +
+	".high,.critical,.denied{color:#b91c1c;font-weight:600}.medium{color:#b45309}.low{color:#475569}" +
+	"pre{background:#0f172a;color:#e2e8f0;padding:10px;overflow-x:auto;font-size:12px;margin:4px 0 12px}" +
+	".warnings li{margin-bottom:4px}"
+
+const reportHTMLScript = "document.querySelectorAll(\"th[data-sort]\").forEach(function(th){" +
+	"th.addEventListener(\"click\",function(){" +
+	"var table=th.closest(\"table\"),index=Array.prototype.indexOf.call(th.parentNode.children,th);" +
+	"var numeric=th.getAttribute(\"data-sort\")===\"number\",ascending=th.getAttribute(\"aria-sort\")!==\"ascending\";" +
+	"table.querySelectorAll(\"th[data-sort]\").forEach(function(other){other.removeAttribute(\"aria-sort\")});" +
+	"th.setAttribute(\"aria-sort\",ascending?\"ascending\":\"descending\");" +
+	"var groups=Array.prototype.slice.call(table.tBodies);" +
+	"groups.sort(function(a,b){var x=a.rows[0].cells[index].getAttribute(\"data-value\"),y=b.rows[0].cells[index].getAttribute(\"data-value\");" +
+	"var order=numeric?parseFloat(x)-parseFloat(y):x.localeCompare(y);return ascending?order:-order});" +
+	"groups.forEach(function(group){table.appendChild(group)})})});"
+
+// formatHTML renders a single-file report. Each dependency is its own tbody
+// holding a summary row and a drawer row, so sorting moves both together and
+// drawers open without script via details elements.
+func formatHTML(report Report) string {
+	var buffer strings.Builder
+	buffer.WriteString("<!doctype html><html lang=\"en\"><head><meta charset=\"utf-8\">")
+	buffer.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">")
+	buffer.WriteString("<meta http-equiv=\"Content-Security-Policy\" content=\"default-src 'none'; style-src 'unsafe-inline'; script-src 'unsafe-inline'\">")
+	buffer.WriteString("<title>Lopper Report</title>")
+	buffer.WriteString("<style>" + reportHTMLStyle + "</style></head><body>")
+	buffer.WriteString("<h1>Lopper Report</h1>")
+	buffer.WriteString("<p class=\"meta\">" + html.EscapeString(formatHTMLReportMeta(report)) + "</p>")
+
+	writeHTMLSummary(&buffer, report)
+	writeHTMLWarnings(&buffer, report.Warnings)
+	writeHTMLBaselineSummary(&buffer, report.BaselineComparison)
+	writeHTMLDependencies(&buffer, report)
+
+	buffer.WriteString("<script>" + reportHTMLScript + "</script>")
+	buffer.WriteString("</body></html>\n")
+	return buffer.String()
+}
+
+func formatHTMLReportMeta(report Report) string {
+	parts := []string{"Repository " + emptyDash(report.RepoPath)}
+	if !report.GeneratedAt.IsZero() {
+		parts = append(parts, "generated at "+report.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	if report.Scope != nil && strings.TrimSpace(report.Scope.Mode) != "" {
+		parts = append(parts, "scope "+report.Scope.Mode)
+	}
+	if strings.TrimSpace(report.SchemaVersion) != "" {
+		parts = append(parts, "schema "+report.SchemaVersion)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func writeHTMLSummary(buffer *strings.Builder, report Report) {
+	summary := report.Summary
+	if summary == nil {
+		summary = ComputeSummary(report.Dependencies)
+	}
+	if summary == nil {
+		summary = &Summary{}
+	}
+	var unusedBytes int64
+	for _, dependency := range report.Dependencies {
+		unusedBytes += dependency.EstimatedUnusedBytes
+	}
+	buffer.WriteString("<section class=\"card\"><div class=\"grid\">")
+	buffer.WriteString(reportMetricHTML("Dependencies", strconv.Itoa(summary.DependencyCount)))
+	buffer.WriteString(reportMetricHTML("Used Exports", fmt.Sprintf("%d / %d", summary.UsedExportsCount, summary.TotalExportsCount)))
+	buffer.WriteString(reportMetricHTML("Used", fmt.Sprintf("%.1f%%", summary.UsedPercent)))
+	buffer.WriteString(reportMetricHTML("Estimated Unused", formatBytes(unusedBytes)))
+	buffer.WriteString(reportMetricHTML("Licenses Known / Unknown / Denied", fmt.Sprintf("%d / %d / %d", summary.KnownLicenseCount, summary.UnknownLicenseCount, summary.DeniedLicenseCount)))
+	if summary.Vulnerabilities != nil {
+		buffer.WriteString(reportMetricHTML("Vulnerabilities (Reachable)", fmt.Sprintf("%d (%d)", summary.Vulnerabilities.TotalFindings, summary.Vulnerabilities.ReachableFindings)))
+	}
+	if report.Cache != nil && report.Cache.Enabled {
+		buffer.WriteString(reportMetricHTML("Cache Hits / Misses", fmt.Sprintf("%d / %d", report.Cache.Hits, report.Cache.Misses)))
+	}
+	buffer.WriteString("</div></section>")
+}
+
+func writeHTMLWarnings(buffer *strings.Builder, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	buffer.WriteString("<h2>Warnings</h2><section class=\"card\"><ul class=\"warnings\">")
+	for _, warning := range warnings {
+		buffer.WriteString("<li>" + html.EscapeString(warning) + "</li>")
+	}
+	buffer.WriteString("</ul></section>")
+}
+
+func writeHTMLBaselineSummary(buffer *strings.Builder, comparison *BaselineComparison) {
+	if comparison == nil {
+		return
+	}
+	delta := comparison.SummaryDelta
+	buffer.WriteString("<h2>Baseline Comparison</h2><section class=\"card\"><div class=\"grid\">")
+	buffer.WriteString(reportMetricHTML("Baseline", emptyDash(comparison.BaselineKey)))
+	buffer.WriteString(reportMetricHTML("Dependencies Δ", signedInt(delta.DependencyCountDelta)))
+	buffer.WriteString(reportMetricHTML("Used Δ", signedPct(delta.UsedPercentDelta)))
+	buffer.WriteString(reportMetricHTML("Waste Δ", signedPct(delta.WastePercentDelta)))
+	buffer.WriteString(reportMetricHTML("Unused Bytes Δ", signedBytes(delta.UnusedBytesDelta)))
+	buffer.WriteString(reportMetricHTML("Denied Licenses Δ", signedInt(delta.DeniedLicenseCountDelta)))
+	buffer.WriteString("</div></section>")
+	if len(comparison.Removed) == 0 {
+		return
+	}
+	buffer.WriteString("<h3>Removed Since Baseline</h3><table><thead><tr><th>Dependency</th><th>Language</th><th>Waste Δ</th>")
+	buffer.WriteString(reportHTMLTableBodyOpen)
+	for _, removed := range comparison.Removed {
+		buffer.WriteString("<tr><td>" + html.EscapeString(removed.Name) + "</td><td>" + html.EscapeString(emptyDash(removed.Language)) + "</td><td class=\"num\">" + signedPct(removed.WastePercentDelta) + "</td></tr>")
+	}
+	buffer.WriteString(reportHTMLTableBodyClose)
+}
+
+func writeHTMLDependencies(buffer *strings.Builder, report Report) {
+	buffer.WriteString("<h2>Dependencies</h2>")
+	if len(report.Dependencies) == 0 {
+		buffer.WriteString("<p class=\"meta\">No dependencies found.</p>")
+		return
+	}
+	baselineDeltas := htmlBaselineDeltasByDependency(report.BaselineComparison)
+	hasBaseline := report.BaselineComparison != nil
+	buffer.WriteString("<table id=\"dependencies\"><thead><tr>")
+	buffer.WriteString("<th data-sort=\"text\">Dependency</th><th data-sort=\"text\">Language</th><th data-sort=\"number\">Used / Total</th>")
+	buffer.WriteString("<th data-sort=\"number\">Used %</th><th data-sort=\"number\">Waste %</th><th data-sort=\"number\">Est. Unused</th>")
+	buffer.WriteString("<th data-sort=\"number\">Candidate Score</th><th data-sort=\"number\">Risk Cues</th><th data-sort=\"number\">Vulnerabilities</th><th data-sort=\"text\">License</th>")
+	if hasBaseline {
+		buffer.WriteString("<th data-sort=\"number\">Waste Δ</th>")
+	}
+	buffer.WriteString("</tr></thead>")
+
+	columns := 10
+	if hasBaseline {
+		columns++
+	}
+	for _, dependency := range report.Dependencies {
+		key := dependency.Language + "\x00" + dependency.Name
+		var delta *DependencyDelta
+		if queue := baselineDeltas[key]; len(queue) > 0 {
+			delta, baselineDeltas[key] = &queue[0], queue[1:]
+		}
+		buffer.WriteString("<tbody>")
+		writeHTMLDependencyRow(buffer, dependency, delta, hasBaseline)
+		buffer.WriteString("<tr class=\"drawer\"><td colspan=\"" + strconv.Itoa(columns) + "\"><details><summary>Details for " + html.EscapeString(dependency.Name) + "</summary>")
+		writeHTMLDependencyDrawer(buffer, dependency, delta)
+		buffer.WriteString("</details></td></tr></tbody>")
+	}
+	buffer.WriteString("</table>")
+}
+
+func writeHTMLDependencyRow(buffer *strings.Builder, dependency DependencyReport, delta *DependencyDelta, hasBaseline bool) {
+	wastePercent := 0.0
+	if dependency.TotalExportsCount > 0 {
+		wastePercent = 100 - dependency.UsedPercent
+	}
+	candidateScore := -1.0
+	if dependency.RemovalCandidate != nil {
+		candidateScore = dependency.RemovalCandidate.Score
+	}
+	license := formatDependencyLicense(dependency.License)
+	licenseClass := ""
+	if dependency.License != nil && dependency.License.Denied {
+		licenseClass = " class=\"denied\""
+	}
+
+	buffer.WriteString("<tr>")
+	buffer.WriteString(htmlSortCell(dependency.Name, html.EscapeString(dependency.Name), ""))
+	buffer.WriteString(htmlSortCell(dependency.Language, html.EscapeString(emptyDash(dependency.Language)), ""))
+	buffer.WriteString(htmlSortCell(strconv.Itoa(dependency.UsedExportsCount), fmt.Sprintf("%d / %d", dependency.UsedExportsCount, dependency.TotalExportsCount), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(formatHTMLFloat(dependency.UsedPercent), fmt.Sprintf("%.1f%%", dependency.UsedPercent), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(formatHTMLFloat(wastePercent), fmt.Sprintf("%.1f%%", wastePercent), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(strconv.FormatInt(dependency.EstimatedUnusedBytes, 10), formatBytes(dependency.EstimatedUnusedBytes), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(formatHTMLFloat(candidateScore), formatCandidateScore(dependency.RemovalCandidate), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(strconv.Itoa(len(dependency.RiskCues)), strconv.Itoa(len(dependency.RiskCues)), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(strconv.Itoa(len(dependency.Vulnerabilities)), strconv.Itoa(len(dependency.Vulnerabilities)), " class=\"num\""))
+	buffer.WriteString(htmlSortCell(license, html.EscapeString(license), licenseClass))
+	if hasBaseline {
+		switch {
+		case delta == nil:
+			buffer.WriteString(htmlSortCell("0", "-", " class=\"num\""))
+		case delta.Kind == DependencyDeltaAdded:
+			buffer.WriteString(htmlSortCell(formatHTMLFloat(delta.WastePercentDelta), "new", " class=\"num\""))
+		default:
+			buffer.WriteString(htmlSortCell(formatHTMLFloat(delta.WastePercentDelta), signedPct(delta.WastePercentDelta), " class=\"num\""))
+		}
+	}
+	buffer.WriteString("</tr>")
+}
+
+func writeHTMLDependencyDrawer(buffer *strings.Builder, dependency DependencyReport, delta *DependencyDelta) {
+	writeHTMLIdentity(buffer, dependency)
+	writeHTMLImports(buffer, "Used Imports", dependency.UsedImports)
+	writeHTMLImports(buffer, "Unused Imports", dependency.UnusedImports)
+	if len(dependency.UnusedExports) > 0 {
+		buffer.WriteString("<h3>Unused Exports</h3><ul>")
+		for _, export := range dependency.UnusedExports {
+			buffer.WriteString("<li>" + html.EscapeString(formatHTMLSymbol(export.Name, export.Module)) + "</li>")
+		}
+		buffer.WriteString("</ul>")
+	}
+	if len(dependency.RiskCues) > 0 {
+		buffer.WriteString("<h3>Risk Cues</h3><table><thead><tr><th>Severity</th><th>Code</th><th>Message</th>" + reportHTMLTableBodyOpen)
+		for _, cue := range dependency.RiskCues {
+			buffer.WriteString("<tr><td class=\"" + html.EscapeString(strings.ToLower(cue.Severity)) + "\">" + html.EscapeString(cue.Severity) + "</td><td>" + html.EscapeString(cue.Code) + "</td><td>" + html.EscapeString(cue.Message) + "</td></tr>")
+		}
+		buffer.WriteString(reportHTMLTableBodyClose)
+	}
+	if len(dependency.Recommendations) > 0 {
+		buffer.WriteString("<h3>Recommendations</h3><table><thead><tr><th>Priority</th><th>Code</th><th>Message</th><th>Rationale</th>" + reportHTMLTableBodyOpen)
+		for _, recommendation := range dependency.Recommendations {
+			buffer.WriteString("<tr><td class=\"" + html.EscapeString(strings.ToLower(recommendation.Priority)) + "\">" + html.EscapeString(recommendation.Priority) + "</td><td>" + html.EscapeString(recommendation.Code) + "</td><td>" + html.EscapeString(recommendation.Message) + "</td><td>" + html.EscapeString(emptyDash(recommendation.Rationale)) + "</td></tr>")
+		}
+		buffer.WriteString(reportHTMLTableBodyClose)
+	}
+	writeHTMLVulnerabilities(buffer, dependency.Vulnerabilities)
+	writeHTMLLicense(buffer, dependency)
+	if dependency.RuntimeUsage != nil {
+		buffer.WriteString("<h3>Runtime Usage</h3><p>" + html.EscapeString(formatRuntimeUsage(dependency.RuntimeUsage)) + "</p>")
+	}
+	if dependency.RemovalCandidate != nil || dependency.ReachabilityConfidence != nil {
+		buffer.WriteString("<h3>Scoring</h3><ul>")
+		if dependency.RemovalCandidate != nil {
+			buffer.WriteString("<li>Removal candidate " + html.EscapeString(formatCandidateScore(dependency.RemovalCandidate)+" ("+formatScoreComponents(dependency.RemovalCandidate)+")") + "</li>")
+		}
+		if dependency.ReachabilityConfidence != nil {
+			buffer.WriteString("<li>Reachability " + html.EscapeString(formatReachabilityConfidence(dependency.ReachabilityConfidence)) + "</li>")
+		}
+		buffer.WriteString("</ul>")
+	}
+	writeHTMLCodemod(buffer, dependency.Codemod)
+	writeHTMLDependencyDelta(buffer, delta)
+}
+
+func writeHTMLIdentity(buffer *strings.Builder, dependency DependencyReport) {
+	if dependency.Identity == nil {
+		return
+	}
+	parts := make([]string, 0, 3)
+	if dependency.Identity.PURL != "" {
+		parts = append(parts, dependency.Identity.PURL)
+	}
+	if dependency.Identity.Version != "" {
+		parts = append(parts, "version "+dependency.Identity.Version)
+	}
+	if dependency.Identity.Confidence != "" {
+		parts = append(parts, "confidence "+dependency.Identity.Confidence)
+	}
+	if len(parts) == 0 {
+		return
+	}
+	buffer.WriteString("<h3>Identity</h3><p>" + html.EscapeString(strings.Join(parts, " · ")) + "</p>")
+}
+
+func writeHTMLImports(buffer *strings.Builder, title string, imports []ImportUse) {
+	if len(imports) == 0 {
+		return
+	}
+	buffer.WriteString("<h3>" + title + "</h3><table><thead><tr><th>Import</th><th>Locations</th>" + reportHTMLTableBodyOpen)
+	for _, imported := range imports {
+		locations := make([]string, 0, len(imported.Locations))
+		for _, location := range imported.Locations {
+			locations = append(locations, formatHTMLLocation(location))
+		}
+		buffer.WriteString("<tr><td>" + html.EscapeString(formatHTMLSymbol(imported.Name, imported.Module)) + "</td><td>" + html.EscapeString(emptyDash(strings.Join(locations, ", "))) + "</td></tr>")
+	}
+	buffer.WriteString(reportHTMLTableBodyClose)
+}
+
+func writeHTMLVulnerabilities(buffer *strings.Builder, findings []VulnerabilityFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	sorted := append([]VulnerabilityFinding{}, findings...)
+	sortVulnerabilityFindings(sorted)
+	buffer.WriteString("<h3>Vulnerabilities</h3><table><thead><tr><th>Advisory</th><th>Severity</th><th>Priority</th><th>Reachable</th><th>Fixed In</th><th>Decision</th>" + reportHTMLTableBodyOpen)
+	for _, finding := range sorted {
+		decision := "-"
+		if finding.Decision != nil {
+			decision = finding.Decision.Status
+			if finding.Decision.Expired {
+				decision += " (expired)"
+			}
+		}
+		buffer.WriteString("<tr><td>" + html.EscapeString(finding.AdvisoryID) + "</td>")
+		buffer.WriteString("<td class=\"" + html.EscapeString(strings.ToLower(finding.Severity)) + "\">" + html.EscapeString(finding.Severity) + "</td>")
+		buffer.WriteString("<td>" + html.EscapeString(fmt.Sprintf("%s %.1f", finding.Priority, finding.PriorityScore)) + "</td>")
+		buffer.WriteString("<td>" + strconv.FormatBool(finding.Reachable) + "</td>")
+		buffer.WriteString("<td>" + html.EscapeString(emptyDash(finding.FixedVersion)) + "</td>")
+		buffer.WriteString("<td>" + html.EscapeString(decision) + "</td></tr>")
+	}
+	buffer.WriteString(reportHTMLTableBodyClose)
+}
+
+func writeHTMLLicense(buffer *strings.Builder, dependency DependencyReport) {
+	if dependency.License == nil && dependency.Provenance == nil {
+		return
+	}
+	buffer.WriteString("<h3>License</h3><ul>")
+	buffer.WriteString("<li>" + html.EscapeString(formatDependencyLicense(dependency.License)) + "</li>")
+	if license := dependency.License; license != nil {
+		if license.Source != "" {
+			buffer.WriteString("<li>Source " + html.EscapeString(license.Source+" ("+emptyDash(license.Confidence)+")") + "</li>")
+		}
+		if license.PolicyReason != "" {
+			buffer.WriteString("<li>Policy " + html.EscapeString(license.PolicyReason) + "</li>")
+		}
+		if license.Exception != nil {
+			buffer.WriteString("<li>Exception owned by " + html.EscapeString(emptyDash(license.Exception.Owner)+": "+license.Exception.Reason) + "</li>")
+		}
+	}
+	if dependency.Provenance != nil {
+		buffer.WriteString("<li>Provenance " + html.EscapeString(formatDependencyProvenance(dependency.Provenance)) + "</li>")
+	}
+	buffer.WriteString("</ul>")
+}
+
+func writeHTMLCodemod(buffer *strings.Builder, codemod *CodemodReport) {
+	if codemod == nil || (len(codemod.Suggestions) == 0 && len(codemod.Skips) == 0 && codemod.Apply == nil) {
+		return
+	}
+	buffer.WriteString("<h3>Codemod (" + html.EscapeString(codemod.Mode) + ")</h3>")
+	for _, suggestion := range codemod.Suggestions {
+		buffer.WriteString("<p>" + html.EscapeString(fmt.Sprintf("%s:%d %s → %s", suggestion.File, suggestion.Line, suggestion.FromModule, suggestion.ToModule)) + "</p>")
+		buffer.WriteString("<pre>" + html.EscapeString(suggestion.Patch) + "</pre>")
+	}
+	if len(codemod.Skips) > 0 {
+		buffer.WriteString("<ul>")
+		for _, skip := range codemod.Skips {
+			buffer.WriteString("<li>Skipped " + html.EscapeString(fmt.Sprintf("%s:%d (%s): %s", skip.File, skip.Line, skip.ReasonCode, skip.Message)) + "</li>")
+		}
+		buffer.WriteString("</ul>")
+	}
+	if apply := codemod.Apply; apply != nil {
+		buffer.WriteString("<p>" + html.EscapeString(fmt.Sprintf("Applied %d patch(es) in %d file(s); skipped %d; failed %d.", apply.AppliedPatches, apply.AppliedFiles, apply.SkippedPatches, apply.FailedPatches)) + "</p>")
+	}
+}
+
+func writeHTMLDependencyDelta(buffer *strings.Builder, delta *DependencyDelta) {
+	if delta == nil {
+		return
+	}
+	buffer.WriteString("<h3>Baseline Delta</h3><ul>")
+	buffer.WriteString("<li>" + html.EscapeString(string(delta.Kind)) + "</li>")
+	buffer.WriteString("<li>Used exports " + signedInt(delta.UsedExportsCountDelta) + " of " + signedInt(delta.TotalExportsCountDelta) + ", used " + signedPct(delta.UsedPercentDelta) + ", waste " + signedPct(delta.WastePercentDelta) + ", unused bytes " + signedBytes(delta.EstimatedUnusedBytesDelta) + "</li>")
+	if delta.DeniedIntroduced {
+		buffer.WriteString("<li class=\"denied\">Denied license introduced</li>")
+	}
+	if delta.ReachableVulnerabilitiesIntroduced {
+		buffer.WriteString("<li class=\"high\">Reachable vulnerabilities " + signedInt(delta.ReachableVulnerabilityCountDelta) + "</li>")
+	}
+	if delta.RuntimeDelta != nil {
+		buffer.WriteString("<li>Runtime " + html.EscapeString(formatRuntimeDelta(delta.RuntimeDelta)) + "</li>")
+	}
+	buffer.WriteString("</ul>")
+}
+
+// htmlBaselineDeltasByDependency queues deltas per language and name in report
+// order, so repeated names (for example several versions) pair up in turn.
+func htmlBaselineDeltasByDependency(comparison *BaselineComparison) map[string][]DependencyDelta {
+	deltas := make(map[string][]DependencyDelta)
+	if comparison == nil {
+		return deltas
+	}
+	for _, delta := range comparison.Dependencies {
+		if delta.Kind == DependencyDeltaRemoved {
+			continue
+		}
+		key := delta.Language + "\x00" + delta.Name
+		deltas[key] = append(deltas[key], delta)
+	}
+	return deltas
+}
+
+func htmlSortCell(sortValue, content, attributes string) string {
+	return "<td" + attributes + " data-value=\"" + html.EscapeString(sortValue) + "\">" + content + "</td>"
+}
+
+func reportMetricHTML(label, value string) string {
+	return "<div class=\"metric\"><span>" + html.EscapeString(label) + "</span><strong>" + html.EscapeString(value) + "</strong></div>"
+}
+
+func formatHTMLFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func formatHTMLSymbol(name, module string) string {
+	if module == "" || module == name {
+		return name
+	}
+	return name + " from " + module
+}
+
+func formatHTMLLocation(location Location) string {
+	if location.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", location.File, location.Line, location.Column)
+	}
+	return fmt.Sprintf("%s:%d", location.File, location.Line)
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleHTMLReport() Report {
+	dependencies := []DependencyReport{
+		{
+			Language:             "js-ts",
+			Name:                 "lodash",
+			UsedExportsCount:     1,
+			TotalExportsCount:    4,
+			UsedPercent:          25,
+			EstimatedUnusedBytes: 2048,
+			UsedImports:          []ImportUse{{Name: "map", Module: "lodash", Locations: []Location{{File: "src/app.js", Line: 3, Column: 9}}}},
+			UnusedImports:        []ImportUse{{Name: "filter", Module: "lodash", Locations: []Location{{File: "src/util.js", Line: 7}}}},
+			UnusedExports:        []SymbolRef{{Name: "chunk", Module: "lodash"}},
+			RiskCues:             []RiskCue{{Code: "dynamic-loader", Severity: "high", Message: "dynamic require <script>"}},
+			Recommendations:      []Recommendation{{Code: "prefer-subpath-imports", Priority: "medium", Message: "Import lodash/map"}},
+			Vulnerabilities:      []VulnerabilityFinding{{AdvisoryID: "GHSA-test", Severity: "high", Priority: "high", PriorityScore: 8.5, Reachable: true, FixedVersion: "4.17.21"}},
+			License:              &DependencyLicense{SPDX: "GPL-3.0-only", Denied: true, Source: "package.json", Confidence: "high"},
+			RemovalCandidate:     &RemovalCandidate{Score: 72.5, Usage: 75, Impact: 60, Confidence: 80},
+			Codemod: &CodemodReport{Mode: "suggest-only", Suggestions: []CodemodSuggestion{{
+				File: "src/app.js", Line: 3, FromModule: "lodash", ToModule: "lodash/map", Patch: "-import { map } from \"lodash\"\n+import map from \"lodash/map\"",
+			}}},
+		},
+		{Language: "python", Name: "requests", UsedExportsCount: 2, TotalExportsCount: 2, UsedPercent: 100},
+	}
+	return Report{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+		RepoPath:      "/repo",
+		Scope:         &ScopeMetadata{Mode: "repo"},
+		Dependencies:  dependencies,
+		Summary:       ComputeSummary(dependencies),
+		Warnings:      []string{"partial <b>analysis</b>"},
+		BaselineComparison: &BaselineComparison{
+			BaselineKey:  "label:main",
+			SummaryDelta: SummaryDelta{DependencyCountDelta: 1, WastePercentDelta: 2.5},
+			Dependencies: []DependencyDelta{
+				{Kind: DependencyDeltaChanged, Language: "js-ts", Name: "lodash", WastePercentDelta: 5, UsedPercentDelta: -5},
+				{Kind: DependencyDeltaAdded, Language: "python", Name: "requests"},
+				{Kind: DependencyDeltaRemoved, Language: "js-ts", Name: "left-pad", WastePercentDelta: -100},
+			},
+			Removed: []DependencyDelta{{Kind: DependencyDeltaRemoved, Language: "js-ts", Name: "left-pad", WastePercentDelta: -100}},
+		},
+	}
+}
+
+func TestFormatHTMLIsSelfContained(t *testing.T) {
+	output, err := NewFormatter().Format(sampleHTMLReport(), FormatHTML)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	assertOutputContains(t, output,
+		"<!doctype html>",
+		"Content-Security-Policy",
+		"<style>",
+		"<script>",
+		`<table id="dependencies">`,
+		`data-sort="number"`,
+		"</html>\n",
+	)
+	assertOutputNotContains(t, output, "http://", "https://", "<link", "src=")
+}
+
+func TestFormatHTMLRendersDependencyDrawers(t *testing.T) {
+	output, err := NewFormatter().Format(sampleHTMLReport(), FormatHTML)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	if got := strings.Count(output, "<tbody><tr><td data-value="); got != 2 {
+		t.Fatalf("expected one tbody per dependency, got %d", got)
+	}
+	assertOutputContains(t, output,
+		"<summary>Details for lodash</summary>",
+		"src/app.js:3:9",
+		"src/util.js:7",
+		"chunk from lodash",
+		"dynamic require &lt;script&gt;",
+		"prefer-subpath-imports",
+		"GHSA-test",
+		"GPL-3.0-only (denied)",
+		"-import { map } from &#34;lodash&#34;",
+		"Removal candidate 72.5",
+		"<h3>Baseline Delta</h3>",
+		"waste +5.0%",
+		"Removed Since Baseline",
+		"left-pad",
+		`data-value="72.50"`,
+		">new</td>",
+		"partial &lt;b&gt;analysis&lt;/b&gt;",
+	)
+	assertOutputNotContains(t, output, "<script>dynamic", "<b>analysis</b>")
+}
+
+func TestFormatHTMLWithoutDependencies(t *testing.T) {
+	output, err := NewFormatter().Format(Report{RepoPath: "."}, FormatHTML)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	assertOutputContains(t, output, "No dependencies found.", "Repository .")
+	assertOutputNotContains(t, output, "Baseline Comparison", `<table id="dependencies">`)
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	junitDependencySuite = "lopper.dependencies"
+	junitPolicySuite     = "lopper.policy"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// formatJUnit renders one testcase per dependency and one per gate.
+// Dependencies fail on denied licenses and reachable vulnerabilities; gate
+// testcases mirror the report's gate results. Every other SARIF signal for a
+// dependency is listed in its system-out.
+func formatJUnit(rep Report) (string, error) {
+	timestamp := ""
+	if !rep.GeneratedAt.IsZero() {
+		timestamp = rep.GeneratedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+	suites := junitTestSuites{
+		Name: "lopper",
+		Suites: []junitTestSuite{
+			junitDependencyTestSuite(rep),
+			junitPolicyTestSuite(rep),
+		},
+	}
+	for i := range suites.Suites {
+		suite := &suites.Suites[i]
+		suite.Timestamp = timestamp
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	payload, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(payload) + "\n", nil
+}
+
+func junitDependencyTestSuite(rep Report) junitTestSuite {
+	suite := junitTestSuite{Name: junitDependencySuite, Cases: make([]junitTestCase, 0, len(rep.Dependencies))}
+	baselineDeltas := baselineDependencyDeltasForDependencies(rep.Dependencies, rep.BaselineComparison)
+	rules := newSARIFRuleBuilder()
+	for index, dep := range rep.Dependencies {
+		testCase := junitTestCase{
+			ClassName: junitDependencySuite + "." + junitClassToken(dep.Language),
+			Name:      dep.Name,
+			SystemOut: junitSignalOutput(dependencySARIFResults(dep, rules, baselineDeltas[index])),
+		}
+		if failures := ciDependencyFailures(rep, dep); len(failures) > 0 {
+			testCase.Failure = &junitFailure{
+				Type:    "dependency-policy",
+				Message: failures[0],
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+	return suite
+}
+
+func junitPolicyTestSuite(rep Report) junitTestSuite {
+	suite := junitTestSuite{Name: junitPolicySuite}
+	for _, gate := range reportGates(rep) {
+		testCase := junitTestCase{ClassName: junitPolicySuite, Name: gate.Gate}
+		switch gate.Status {
+		case GateStatusSkipped:
+			testCase.Skipped = &junitSkipped{Message: gate.Detail}
+			suite.Skipped++
+		case GateStatusFail:
+			testCase.Failure = &junitFailure{Type: gate.Gate, Message: gate.Detail, Text: gate.Detail}
+			suite.Failures++
+		case GateStatusError:
+			testCase.Error = &junitFailure{Type: gate.Gate, Message: gate.Detail, Text: gate.Detail}
+			suite.Errors++
+		default:
+			testCase.SystemOut = gate.Detail
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+	return suite
+}
+
+func junitSignalOutput(results []sarifResult) string {
+	if len(results) == 0 {
+		return ""
+	}
+	sortSARIFResults(results)
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		line := fmt.Sprintf("[%s] %s: %s", result.Level, result.RuleID, result.Message.Text)
+		if location := resultLocationKey(result); location != "" {
+			line += " (" + location + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func junitClassToken(language string) string {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return "unknown"
+	}
+	return strings.ReplaceAll(language, ".", "-")
+}
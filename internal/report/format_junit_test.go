@@ -0,0 +1,114 @@
+package report
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func sampleCIReport() Report {
+	rep := sampleHTMLReport()
+	rep.Dependencies[0].Identity = &DependencyIdentity{Source: "package.json"}
+	rep.Dependencies[1].Identity = &DependencyIdentity{Source: "requirements.txt"}
+	rep.EffectiveThresholds = &EffectiveThresholds{
+		FailOnIncreasePercent:          5,
+		MaxUncertainImportCount:        -1,
+		ReachableVulnerabilityPriority: VulnerabilityPriorityHigh,
+	}
+	rep.EffectivePolicy = &EffectivePolicy{License: LicensePolicy{FailOnDenied: true}}
+	rep.BaselineComparison = nil
+	waste := 7.5
+	rep.WasteIncreasePercent = &waste
+	return rep
+}
+
+func TestFormatJUnitReportsDependenciesAndPolicyRules(t *testing.T) {
+	output, err := NewFormatter().Format(sampleCIReport(), FormatJUnit)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatalf("junit output is not valid XML: %v\n%s", err, output)
+	}
+	if len(suites.Suites) != 2 || suites.Tests != 7 || suites.Failures != 4 || suites.Skipped != 2 {
+		t.Fatalf("unexpected junit totals: tests=%d failures=%d skipped=%d", suites.Tests, suites.Failures, suites.Skipped)
+	}
+
+	dependencies := suites.Suites[0]
+	if dependencies.Name != junitDependencySuite || len(dependencies.Cases) != 2 || dependencies.Failures != 1 {
+		t.Fatalf("unexpected dependency suite: %#v", dependencies)
+	}
+	lodash := dependencies.Cases[0]
+	if lodash.ClassName != "lopper.dependencies.js-ts" || lodash.Failure == nil {
+		t.Fatalf("expected lodash testcase to fail, got %#v", lodash)
+	}
+	assertOutputContains(t, lodash.Failure.Text, "license GPL-3.0-only is denied by policy", "reachable vulnerability GHSA-test with high priority")
+	assertOutputContains(t, lodash.SystemOut, "[error] lopper/vulnerability/ghsa-test", "lopper/waste/unused-import", "(src/util.js:7:0)")
+	if dependencies.Cases[1].Failure != nil {
+		t.Fatalf("expected requests testcase to pass, got %#v", dependencies.Cases[1].Failure)
+	}
+
+	policy := map[string]junitTestCase{}
+	for _, testCase := range suites.Suites[1].Cases {
+		policy[testCase.Name] = testCase
+	}
+	for _, name := range []string{GateFailOnIncrease, GateLicenseFailOnDeny, GateReachableVulnerabilityPriority} {
+		if policy[name].Failure == nil {
+			t.Fatalf("expected policy rule %s to fail, got %#v", name, policy[name])
+		}
+	}
+	if policy[GateMaxUncertainImports].Skipped == nil {
+		t.Fatalf("expected disabled uncertainty rule to be skipped, got %#v", policy[GateMaxUncertainImports])
+	}
+	assertOutputContains(t, output, `<?xml version="1.0" encoding="UTF-8"?>`, `timestamp="2026-10-01T12:00:00"`)
+}
+
+func TestFormatJUnitPassesWithinThresholds(t *testing.T) {
+	rep := sampleCIReport()
+	rep.Dependencies[0].License = nil
+	rep.Dependencies[0].Vulnerabilities[0].Reachable = false
+	waste := 1.0
+	rep.WasteIncreasePercent = &waste
+
+	output, err := NewFormatter().Format(rep, FormatJUnit)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	if strings.Contains(output, "<failure") {
+		t.Fatalf("expected no failures, got:\n%s", output)
+	}
+	assertOutputContains(t, output, "waste increase 1.0% is within the 5% threshold", "no reachable vulnerabilities at or above high priority")
+}
+
+func TestFormatJUnitWithoutPolicyMetadataSkipsRules(t *testing.T) {
+	output, err := NewFormatter().Format(Report{RepoPath: "."}, FormatJUnit)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatalf("junit output is not valid XML: %v", err)
+	}
+	if suites.Tests != 5 || suites.Skipped != 5 || suites.Failures != 0 {
+		t.Fatalf("expected every policy rule skipped, got tests=%d skipped=%d failures=%d", suites.Tests, suites.Skipped, suites.Failures)
+	}
+}
+
+func TestHasReachableVulnerabilityAtOrAboveUsesBaselineWhenPresent(t *testing.T) {
+	rep := sampleCIReport()
+	if !HasReachableVulnerabilityAtOrAbove(rep, VulnerabilityPriorityHigh) || HasReachableVulnerabilityAtOrAbove(rep, VulnerabilityPriorityCritical) {
+		t.Fatalf("unexpected threshold evaluation for current findings")
+	}
+	if HasReachableVulnerabilityAtOrAbove(rep, VulnerabilityPriorityOff) {
+		t.Fatalf("expected off threshold never to breach")
+	}
+	rep.BaselineComparison = &BaselineComparison{NewReachableVulnerabilities: []VulnerabilityDelta{{Priority: "low", VersionStatus: "unevaluable"}}}
+	if !HasReachableVulnerabilityAtOrAbove(rep, VulnerabilityPriorityCritical) {
+		t.Fatalf("expected unevaluable new finding to breach any threshold")
+	}
+	rep.BaselineComparison.NewReachableVulnerabilities = nil
+	if HasReachableVulnerabilityAtOrAbove(rep, VulnerabilityPriorityLow) || HasDeniedLicenseBreach(rep) {
+		t.Fatalf("expected baseline comparison without new findings not to breach")
+	}
+}
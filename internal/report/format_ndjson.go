@@ -0,0 +1,320 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const NDJSONReportStreamPreviewFeature = "ndjson-report-stream-preview"
+
+// NDJSON record discriminators. A stream is exactly one header record, zero or
+// more per-root dependency records, zero or more result records, and exactly
+// one trailer record, one JSON object per line. Dependency records are
+// progress: each is one adapter root's result before roots are merged and
+// annotated. Result records are the finished report's dependency rows.
+const (
+	NDJSONRecordHeader     = "header"
+	NDJSONRecordDependency = "dependency"
+	NDJSONRecordResult     = "result"
+	NDJSONRecordTrailer    = "trailer"
+)
+
+var ErrInvalidNDJSON = errors.New("invalid ndjson report")
+
+// ndjsonPeekBytes leaves room for leading whitespace before the header prefix.
+const ndjsonPeekBytes = 64
+
+// ndjsonHeaderPrefix is how every stream written by WriteNDJSON begins; the
+// baseline loader uses it to tell NDJSON reports apart from JSON documents.
+var ndjsonHeaderPrefix = []byte(`{"record":"` + NDJSONRecordHeader + `"`)
+
+type ndjsonHeaderRecord struct {
+	Record              string               `json:"record"`
+	SchemaVersion       string               `json:"schemaVersion"`
+	Scope               *ScopeMetadata       `json:"scope,omitempty"`
+	Cache               *CacheMetadata       `json:"cache,omitempty"`
+	EffectiveThresholds *EffectiveThresholds `json:"effectiveThresholds,omitempty"`
+	EffectivePolicy     *EffectivePolicy     `json:"effectivePolicy,omitempty"`
+}
+
+type ndjsonDependencyRecord struct {
+	Record string `json:"record"`
+	Root   string `json:"root,omitempty"`
+	DependencyReport
+}
+
+type ndjsonResultRecord struct {
+	Record string `json:"record"`
+	DependencyReport
+}
+
+type ndjsonTrailerRecord struct {
+	Record               string              `json:"record"`
+	DependencyCount      int                 `json:"dependencyCount"`
+	ResultCount          int                 `json:"resultCount"`
+	GeneratedAt          time.Time           `json:"generatedAt"`
+	RepoPath             string              `json:"repoPath"`
+	UsageUncertainty     *UsageUncertainty   `json:"usageUncertainty,omitempty"`
+	Summary              *Summary            `json:"summary,omitempty"`
+	LanguageBreakdown    []LanguageSummary   `json:"languageBreakdown,omitempty"`
+	Warnings             []string            `json:"warnings,omitempty"`
+	WasteIncreasePercent *float64            `json:"wasteIncreasePercent,omitempty"`
+	BaselineComparison   *BaselineComparison `json:"baselineComparison,omitempty"`
+	RuleFindings         []RuleFinding       `json:"ruleFindings,omitempty"`
+	GateResults          []GateResult        `json:"gateResults,omitempty"`
+}
+
+// NDJSONWriter writes a report stream in three phases: the header once the
+// roots to analyse are known, dependency records as each analysed root
+// completes, and the result records and trailer once the finished report is
+// known. Every phase is flushed to the underlying writer before it returns.
+type NDJSONWriter struct {
+	buffered      *bufio.Writer
+	encoder       *json.Encoder
+	dependencies  int
+	headerWritten bool
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	buffered := bufio.NewWriter(w)
+	return &NDJSONWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}
+}
+
+// WriteHeader writes the header record from the fields of report that are
+// settled before analysis: the schema version, scope, cache settings and
+// effective policy.
+func (s *NDJSONWriter) WriteHeader(report Report) error {
+	if s.headerWritten {
+		return errors.New("ndjson header already written")
+	}
+	s.headerWritten = true
+	schemaVersion := report.SchemaVersion
+	if schemaVersion == "" {
+		schemaVersion = SchemaVersion
+	}
+	if err := s.encoder.Encode(ndjsonHeaderRecord{
+		Record:              NDJSONRecordHeader,
+		SchemaVersion:       schemaVersion,
+		Scope:               report.Scope,
+		Cache:               report.Cache,
+		EffectiveThresholds: report.EffectiveThresholds,
+		EffectivePolicy:     report.EffectivePolicy,
+	}); err != nil {
+		return err
+	}
+	return s.buffered.Flush()
+}
+
+// WriteDependencies writes one record per dependency of an analysed root.
+// root is repo-relative and is omitted when empty.
+func (s *NDJSONWriter) WriteDependencies(root string, dependencies []DependencyReport) error {
+	if !s.headerWritten {
+		return errors.New("ndjson dependency records written before the header")
+	}
+	for _, dependency := range dependencies {
+		if err := s.encoder.Encode(ndjsonDependencyRecord{Record: NDJSONRecordDependency, Root: root, DependencyReport: dependency}); err != nil {
+			return err
+		}
+		s.dependencies++
+	}
+	return s.buffered.Flush()
+}
+
+// WriteTrailer closes the stream with one result record per dependency of
+// the finished report and a trailer with its report-level results. When the
+// header was not written yet it is written from report first, so the stream
+// is complete for callers that only have the finished report.
+func (s *NDJSONWriter) WriteTrailer(report Report) error {
+	if !s.headerWritten {
+		if err := s.WriteHeader(report); err != nil {
+			return err
+		}
+	}
+	for _, dependency := range report.Dependencies {
+		if err := s.encoder.Encode(ndjsonResultRecord{Record: NDJSONRecordResult, DependencyReport: dependency}); err != nil {
+			return err
+		}
+	}
+	if err := s.encoder.Encode(ndjsonTrailerRecord{
+		Record:               NDJSONRecordTrailer,
+		DependencyCount:      s.dependencies,
+		ResultCount:          len(report.Dependencies),
+		GeneratedAt:          report.GeneratedAt,
+		RepoPath:             report.RepoPath,
+		UsageUncertainty:     report.UsageUncertainty,
+		Summary:              report.Summary,
+		LanguageBreakdown:    report.LanguageBreakdown,
+		Warnings:             report.Warnings,
+		WasteIncreasePercent: report.WasteIncreasePercent,
+		BaselineComparison:   report.BaselineComparison,
+		RuleFindings:         report.RuleFindings,
+		GateResults:          report.GateResults,
+	}); err != nil {
+		return err
+	}
+	return s.buffered.Flush()
+}
+
+// WriteNDJSON writes an already finished report as a header record, one
+// result record per dependency, and a trailer record.
+func WriteNDJSON(w io.Writer, report Report) error {
+	return NewNDJSONWriter(w).WriteTrailer(report)
+}
+
+func formatNDJSON(report Report) (string, error) {
+	var output strings.Builder
+	if err := WriteNDJSON(&output, report); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// ReadNDJSON decodes a stream written by WriteNDJSON one record at a time.
+// Only result records become the report's dependencies: per-root dependency
+// records are validated and counted but skipped, because they are not merged
+// across roots or annotated. When visit is nil the results are collected into
+// the returned report; otherwise each is handed to visit as soon as it is
+// decoded and is not retained, so callers can aggregate very large reports
+// incrementally.
+func ReadNDJSON(r io.Reader, visit func(DependencyReport) error) (Report, error) {
+	decoder := json.NewDecoder(r)
+	var rep Report
+	var counts ndjsonRecordCounts
+	for line := 1; ; line++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return Report{}, ndjsonError(line, "missing trailer record")
+			}
+			return Report{}, ndjsonError(line, err.Error())
+		}
+		var envelope struct {
+			Record string `json:"record"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return Report{}, ndjsonError(line, err.Error())
+		}
+		switch {
+		case line == 1 && envelope.Record != NDJSONRecordHeader:
+			return Report{}, ndjsonError(line, "first record must be the header")
+		case line == 1:
+			if err := decodeNDJSONHeader(raw, &rep); err != nil {
+				return Report{}, ndjsonError(line, err.Error())
+			}
+		case envelope.Record == NDJSONRecordDependency:
+			if counts.results > 0 {
+				return Report{}, ndjsonError(line, "dependency record after result records")
+			}
+			var record ndjsonDependencyRecord
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return Report{}, ndjsonError(line, err.Error())
+			}
+			counts.dependencies++
+		case envelope.Record == NDJSONRecordResult:
+			var record ndjsonResultRecord
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return Report{}, ndjsonError(line, err.Error())
+			}
+			counts.results++
+			if visit == nil {
+				rep.Dependencies = append(rep.Dependencies, record.DependencyReport)
+			} else if err := visit(record.DependencyReport); err != nil {
+				return Report{}, err
+			}
+		case envelope.Record == NDJSONRecordTrailer:
+			if err := decodeNDJSONTrailer(raw, counts, &rep); err != nil {
+				return Report{}, ndjsonError(line, err.Error())
+			}
+			if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+				return Report{}, ndjsonError(line+1, "unexpected data after trailer record")
+			}
+			return rep, nil
+		default:
+			return Report{}, ndjsonError(line, fmt.Sprintf("unexpected record %q", envelope.Record))
+		}
+	}
+}
+
+// LoadNDJSON reads the NDJSON report at path with ReadNDJSON, handing each
+// dependency to visit. It reports false without reading further when the file
+// does not start with an NDJSON header, so callers can fall back to JSON.
+func LoadNDJSON(path string, visit func(DependencyReport) error) (_ Report, _ bool, returnErr error) {
+	file, err := safeio.OpenFile(path)
+	if err != nil {
+		return Report{}, false, err
+	}
+	defer func() {
+		returnErr = errors.Join(returnErr, file.Close())
+	}()
+	reader := bufio.NewReader(file)
+	prefix, _ := reader.Peek(ndjsonPeekBytes)
+	if !hasNDJSONHeaderPrefix(prefix) {
+		return Report{}, false, nil
+	}
+	rep, err := ReadNDJSON(reader, visit)
+	if err != nil {
+		return Report{}, true, err
+	}
+	return rep, true, nil
+}
+
+func decodeNDJSONHeader(raw []byte, rep *Report) error {
+	var header ndjsonHeaderRecord
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return err
+	}
+	rep.SchemaVersion = header.SchemaVersion
+	rep.Scope = header.Scope
+	rep.Cache = header.Cache
+	rep.EffectiveThresholds = header.EffectiveThresholds
+	rep.EffectivePolicy = header.EffectivePolicy
+	return nil
+}
+
+type ndjsonRecordCounts struct {
+	dependencies int
+	results      int
+}
+
+func decodeNDJSONTrailer(raw []byte, counts ndjsonRecordCounts, rep *Report) error {
+	var trailer ndjsonTrailerRecord
+	if err := json.Unmarshal(raw, &trailer); err != nil {
+		return err
+	}
+	if trailer.DependencyCount != counts.dependencies {
+		return fmt.Errorf("trailer declares %d dependencies, stream contains %d", trailer.DependencyCount, counts.dependencies)
+	}
+	if trailer.ResultCount != counts.results {
+		return fmt.Errorf("trailer declares %d results, stream contains %d", trailer.ResultCount, counts.results)
+	}
+	if counts.dependencies > 0 && counts.results == 0 {
+		return errors.New("stream has per-root dependency records but no result records")
+	}
+	rep.GeneratedAt = trailer.GeneratedAt
+	rep.RepoPath = trailer.RepoPath
+	rep.UsageUncertainty = trailer.UsageUncertainty
+	rep.Summary = trailer.Summary
+	rep.LanguageBreakdown = trailer.LanguageBreakdown
+	rep.Warnings = trailer.Warnings
+	rep.WasteIncreasePercent = trailer.WasteIncreasePercent
+	rep.BaselineComparison = trailer.BaselineComparison
+	rep.RuleFindings = trailer.RuleFindings
+	rep.GateResults = trailer.GateResults
+	return nil
+}
+
+func ndjsonError(line int, reason string) error {
+	return fmt.Errorf("%w: record %d: %s", ErrInvalidNDJSON, line, reason)
+}
+
+func hasNDJSONHeaderPrefix(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), ndjsonHeaderPrefix)
+}
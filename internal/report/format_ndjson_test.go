@@ -0,0 +1,297 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleNDJSONReport() Report {
+	waste := 12.5
+	dependencies := []DependencyReport{
+		{Language: "js-ts", Name: "alpha", UsedExportsCount: 1, TotalExportsCount: 4, UsedPercent: 25},
+		{Language: "python", Name: "beta", UsedExportsCount: 2, TotalExportsCount: 2, UsedPercent: 100},
+	}
+	return Report{
+		SchemaVersion:        SchemaVersion,
+		GeneratedAt:          time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+		RepoPath:             ".",
+		Scope:                &ScopeMetadata{Mode: "repo"},
+		Cache:                &CacheMetadata{Enabled: true, Hits: 3, Misses: 1},
+		EffectivePolicy:      sampleEffectivePolicy("defaults", 0, 40, 5, 0.5, 0.3, 0.2),
+		Dependencies:         dependencies,
+		Summary:              ComputeSummary(dependencies),
+		LanguageBreakdown:    ComputeLanguageBreakdown(dependencies),
+		Warnings:             []string{"partial analysis"},
+		WasteIncreasePercent: &waste,
+		RuleFindings:         []RuleFinding{{RuleID: "js-low-usage", Severity: RuleSeverityWarning, Message: "low usage", Language: "js-ts", Dependency: "lodash"}},
+		GateResults:          []GateResult{{Gate: GateFailOnIncrease, Status: GateStatusFail, Threshold: "0%", Observed: "12.5%", ExitCode: ExitCodeFailOnIncrease}},
+	}
+}
+
+func TestWriteNDJSONEmitsHeaderDependenciesAndTrailer(t *testing.T) {
+	var output bytes.Buffer
+	if err := WriteNDJSON(&output, sampleNDJSONReport()); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 records, got %d: %q", len(lines), output.String())
+	}
+	want := []string{NDJSONRecordHeader, NDJSONRecordResult, NDJSONRecordResult, NDJSONRecordTrailer}
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("record %d is not JSON: %v", i+1, err)
+		}
+		if record["record"] != want[i] {
+			t.Fatalf("record %d = %v, want %s", i+1, record["record"], want[i])
+		}
+	}
+	if !hasNDJSONHeaderPrefix(output.Bytes()) {
+		t.Fatalf("expected stream to start with the header prefix: %q", lines[0])
+	}
+	assertOutputContains(t, lines[0], `"effectivePolicy"`, `"scope"`, `"cache"`)
+	assertOutputNotContains(t, lines[0], `"dependencies"`, `"summary"`)
+	assertOutputContains(t, lines[1], `"name":"alpha"`)
+	assertOutputNotContains(t, lines[1], `"root"`)
+	assertOutputContains(t, lines[3], `"dependencyCount":0`, `"resultCount":2`, `"summary"`, `"warnings"`, `"repoPath"`)
+	assertOutputNotContains(t, lines[3], `"scope"`, `"cache"`)
+
+	formatted, err := NewFormatter().Format(sampleNDJSONReport(), FormatNDJSON)
+	if err != nil {
+		t.Fatalf("format ndjson: %v", err)
+	}
+	if formatted != output.String() {
+		t.Fatalf("Format and WriteNDJSON disagree:\n%s\n%s", formatted, output.String())
+	}
+}
+
+func TestNDJSONWriterStreamsRootsBeforeTheTrailer(t *testing.T) {
+	var output bytes.Buffer
+	stream := NewNDJSONWriter(&output)
+	if err := stream.WriteDependencies("packages/a", nil); err == nil {
+		t.Fatalf("expected dependency records before the header to fail")
+	}
+	scope := &ScopeMetadata{Mode: "package", Packages: []string{"packages/a", "packages/b"}}
+	if err := stream.WriteHeader(Report{Scope: scope, Cache: &CacheMetadata{Enabled: true}, EffectivePolicy: sampleEffectivePolicy("defaults", 0, 40, 5, 0.5, 0.3, 0.2)}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	assertOutputContains(t, output.String(), `"schemaVersion":"`+SchemaVersion+`"`, `"packages":["packages/a","packages/b"]`, `"cache":{"enabled":true`)
+	if err := stream.WriteHeader(Report{}); err == nil {
+		t.Fatalf("expected a second header to fail")
+	}
+	if err := stream.WriteDependencies("packages/a", []DependencyReport{{Language: "js-ts", Name: "alpha"}}); err != nil {
+		t.Fatalf("write root dependencies: %v", err)
+	}
+	assertOutputContains(t, output.String(), `{"record":"dependency","root":"packages/a","language":"js-ts","name":"alpha"`)
+	if err := stream.WriteDependencies("packages/b", nil); err != nil {
+		t.Fatalf("write empty root: %v", err)
+	}
+
+	final := sampleNDJSONReport()
+	if err := stream.WriteTrailer(final); err != nil {
+		t.Fatalf("write trailer: %v", err)
+	}
+	assertOutputContains(t, output.String(), `{"record":"result","language":"js-ts","name":"alpha"`, `"dependencyCount":1,"resultCount":2`)
+	got, err := ReadNDJSON(&output, nil)
+	if err != nil {
+		t.Fatalf("read streamed ndjson: %v", err)
+	}
+	if !reflect.DeepEqual(got.Dependencies, final.Dependencies) || got.RepoPath != final.RepoPath || !reflect.DeepEqual(got.Summary, final.Summary) || !reflect.DeepEqual(got.Scope, scope) {
+		t.Fatalf("expected the finished report's results with the final trailer, got %#v", got)
+	}
+}
+
+func TestReadNDJSONRoundTripsReport(t *testing.T) {
+	want := sampleNDJSONReport()
+	var output bytes.Buffer
+	if err := WriteNDJSON(&output, want); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+	got, err := ReadNDJSON(&output, nil)
+	if err != nil {
+		t.Fatalf("read ndjson: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch:\n got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestReadNDJSONVisitsDependenciesWithoutRetainingThem(t *testing.T) {
+	var output bytes.Buffer
+	if err := WriteNDJSON(&output, sampleNDJSONReport()); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+	var names []string
+	got, err := ReadNDJSON(&output, func(dependency DependencyReport) error {
+		names = append(names, dependency.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read ndjson: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"alpha", "beta"}) {
+		t.Fatalf("visited %v", names)
+	}
+	if len(got.Dependencies) != 0 || got.Summary == nil || got.Summary.DependencyCount != 2 {
+		t.Fatalf("unexpected report from visiting read: %#v", got)
+	}
+
+	visitErr := errors.New("stop")
+	if err := WriteNDJSON(&output, sampleNDJSONReport()); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+	if _, err := ReadNDJSON(&output, func(DependencyReport) error { return visitErr }); !errors.Is(err, visitErr) {
+		t.Fatalf("expected visit error, got %v", err)
+	}
+}
+
+func TestReadNDJSONRejectsMalformedStreams(t *testing.T) {
+	header := `{"record":"header","schemaVersion":"0.1.0","generatedAt":"2026-01-01T00:00:00Z","repoPath":"."}`
+	dependency := `{"record":"dependency","root":"web","language":"js-ts","name":"dep","usedExportsCount":1,"totalExportsCount":2,"usedPercent":50,"estimatedUnusedBytes":0}`
+	result := `{"record":"result","language":"js-ts","name":"dep","usedExportsCount":1,"totalExportsCount":2,"usedPercent":50,"estimatedUnusedBytes":0}`
+	trailer := `{"record":"trailer","dependencyCount":1,"resultCount":1}`
+	cases := map[string]string{
+		"empty":                   "",
+		"missing header":          dependency + "\n" + result + "\n" + trailer + "\n",
+		"missing trailer":         header + "\n" + dependency + "\n" + result + "\n",
+		"unknown record":          header + "\n" + `{"record":"other"}` + "\n" + trailer + "\n",
+		"count mismatch":          header + "\n" + `{"record":"trailer","dependencyCount":2}` + "\n",
+		"result count mismatch":   header + "\n" + dependency + "\n" + `{"record":"trailer","dependencyCount":1,"resultCount":1}` + "\n",
+		"only per-root records":   header + "\n" + dependency + "\n" + `{"record":"trailer","dependencyCount":1}` + "\n",
+		"dependency after result": header + "\n" + result + "\n" + dependency + "\n" + trailer + "\n",
+		"after trailer":           header + "\n" + dependency + "\n" + result + "\n" + trailer + "\n" + result + "\n",
+		"invalid json":            header + "\n{\n",
+		"non-object":              header + "\n[]\n",
+		"bad header field":        `{"record":"header","schemaVersion":7}` + "\n" + trailer + "\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadNDJSON(strings.NewReader(content), nil); !errors.Is(err, ErrInvalidNDJSON) {
+				t.Fatalf("expected ErrInvalidNDJSON, got %v", err)
+			}
+		})
+	}
+
+	rep, err := ReadNDJSON(strings.NewReader(header+"\n"+dependency+"\n"+result+"\n"+trailer+"\n"), nil)
+	if err != nil {
+		t.Fatalf("read valid stream: %v", err)
+	}
+	if len(rep.Dependencies) != 1 || rep.Dependencies[0].Name != "dep" {
+		t.Fatalf("unexpected dependencies: %#v", rep.Dependencies)
+	}
+}
+
+func TestLoadWithKeyReadsNDJSONReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.ndjson")
+	content := `{"record":"header","schemaVersion":"0.1.0"}` + "\n" +
+		`{"record":"dependency","root":"web","language":"js-ts","name":"dep","usedExportsCount":1,"totalExportsCount":2,"usedPercent":50,"estimatedUnusedBytes":0,"usedImports":[{"name":"map","module":"dep"}]}` + "\n" +
+		`{"record":"result","language":"js-ts","name":"dep","usedExportsCount":1,"totalExportsCount":2,"usedPercent":50,"estimatedUnusedBytes":0,"usedImports":[{"name":"map","module":"dep"}]}` + "\n" +
+		`{"record":"trailer","generatedAt":"2026-01-01T00:00:00Z","repoPath":".","dependencyCount":1,"resultCount":1}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write ndjson report: %v", err)
+	}
+	rep, key, err := LoadWithKey(path)
+	if err != nil {
+		t.Fatalf("load ndjson report: %v", err)
+	}
+	if key != "" {
+		t.Fatalf("expected empty key for ndjson report, got %q", key)
+	}
+	if len(rep.Dependencies) != 1 || rep.Summary == nil || rep.Summary.TotalExportsCount != 2 || len(rep.LanguageBreakdown) != 1 {
+		t.Fatalf("expected repaired ndjson baseline, got %#v", rep)
+	}
+	if rep.Dependencies[0].UsedImports != nil {
+		t.Fatalf("expected baseline loader to drop per-import detail, got %#v", rep.Dependencies[0].UsedImports)
+	}
+
+	if err := os.WriteFile(path, []byte(content[:strings.Index(content, `{"record":"trailer"`)]), 0o600); err != nil {
+		t.Fatalf("write truncated ndjson report: %v", err)
+	}
+	if _, _, err := LoadWithKey(path); !errors.Is(err, ErrInvalidNDJSON) {
+		t.Fatalf("expected truncated ndjson baseline to fail, got %v", err)
+	}
+}
+
+func TestJSONAndNDJSONBaselinesOfTheSameReportAgree(t *testing.T) {
+	lodash := func(root string, license *DependencyLicense) DependencyReport {
+		return DependencyReport{Language: "js-ts", Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 4, UsedPercent: 25, UnusedRoots: []string{root}, License: license}
+	}
+	policy := sampleEffectivePolicy("defaults", 0, 40, 5, 0.5, 0.3, 0.2)
+	policy.License.Deny = []string{"WTFPL"}
+	policy.License.FailOnDenied = true
+	merged := lodash("packages/a", &DependencyLicense{SPDX: "WTFPL", Denied: true})
+	merged.UnusedRoots = []string{"packages/a", "packages/b"}
+	finished := Report{
+		SchemaVersion:   SchemaVersion,
+		RepoPath:        ".",
+		EffectivePolicy: policy,
+		Dependencies:    []DependencyReport{merged},
+	}
+	finished.Summary = ComputeSummary(finished.Dependencies)
+	finished.LanguageBreakdown = ComputeLanguageBreakdown(finished.Dependencies)
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "baseline.json")
+	formatted, err := NewFormatter().Format(finished, FormatJSON)
+	if err != nil {
+		t.Fatalf("format json baseline: %v", err)
+	}
+	if err := os.WriteFile(jsonPath, []byte(formatted), 0o600); err != nil {
+		t.Fatalf("write json baseline: %v", err)
+	}
+	var streamed bytes.Buffer
+	stream := NewNDJSONWriter(&streamed)
+	if err := stream.WriteHeader(finished); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	for _, root := range []string{"packages/a", "packages/b"} {
+		if err := stream.WriteDependencies(root, []DependencyReport{lodash(root, &DependencyLicense{SPDX: "WTFPL"})}); err != nil {
+			t.Fatalf("write %s: %v", root, err)
+		}
+	}
+	if err := stream.WriteTrailer(finished); err != nil {
+		t.Fatalf("write trailer: %v", err)
+	}
+	ndjsonPath := filepath.Join(dir, "baseline.ndjson")
+	if err := os.WriteFile(ndjsonPath, streamed.Bytes(), 0o600); err != nil {
+		t.Fatalf("write ndjson baseline: %v", err)
+	}
+
+	compared := make([]Report, 0, 2)
+	for _, path := range []string{jsonPath, ndjsonPath} {
+		baseline, err := Load(path)
+		if err != nil {
+			t.Fatalf("load %s: %v", path, err)
+		}
+		current, err := ApplyBaseline(finished, baseline)
+		if err != nil {
+			t.Fatalf("apply %s: %v", path, err)
+		}
+		compared = append(compared, current)
+	}
+	if !reflect.DeepEqual(compared[0].BaselineComparison, compared[1].BaselineComparison) || !reflect.DeepEqual(compared[0].WasteIncreasePercent, compared[1].WasteIncreasePercent) {
+		t.Fatalf("baselines disagree on deltas:\njson   %#v\nndjson %#v", compared[0].BaselineComparison, compared[1].BaselineComparison)
+	}
+	jsonGates, ndjsonGates := EvaluateGates(compared[0]), EvaluateGates(compared[1])
+	if !reflect.DeepEqual(jsonGates, ndjsonGates) {
+		t.Fatalf("baselines disagree on gates:\njson   %#v\nndjson %#v", jsonGates, ndjsonGates)
+	}
+	if code := GatesExitCode(ndjsonGates); code != 0 || len(compared[1].BaselineComparison.NewDeniedLicenses) != 0 {
+		t.Fatalf("expected an unchanged denied license to pass against its own baseline, exit %d, comparison %#v", code, compared[1].BaselineComparison)
+	}
+}
+
+func TestParseFormatNDJSON(t *testing.T) {
+	format, err := ParseFormat(" NDJSON ")
+	if err != nil || format != FormatNDJSON {
+		t.Fatalf("ParseFormat(ndjson) = %q, %v", format, err)
+	}
+}
@@ -48,6 +48,7 @@ func formatTable(report Report) (string, error) {
 		return "", err
 	}
 	appendWarnings(&buffer, report)
+	appendGateResults(&buffer, report.GateResults)
 	return buffer.String(), nil
 }
 
@@ -80,6 +81,7 @@ func formatEmpty(report Report) (string, error) {
 	appendEffectivePolicy(&buffer, report)
 	appendCodemodApply(&buffer, report.Dependencies)
 	appendWarnings(&buffer, report)
+	appendGateResults(&buffer, report.GateResults)
 	return buffer.String(), nil
 }
 
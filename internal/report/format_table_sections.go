@@ -78,6 +78,11 @@ func appendCacheMetadata(buffer *bytes.Buffer, cache *CacheMetadata) {
 	writef(buffer, "- hits: %d\n", cache.Hits)
 	writef(buffer, "- misses: %d\n", cache.Misses)
 	writef(buffer, "- writes: %d\n", cache.Writes)
+	if cache.FileHits+cache.FileMisses+cache.FileWrites > 0 {
+		writef(buffer, "- file hits: %d\n", cache.FileHits)
+		writef(buffer, "- file misses: %d\n", cache.FileMisses)
+		writef(buffer, "- file writes: %d\n", cache.FileWrites)
+	}
 	if len(cache.Invalidations) > 0 {
 		for _, invalidation := range cache.Invalidations {
 			writef(buffer, "- invalidation: %s (%s)\n", invalidation.Key, invalidation.Reason)
@@ -118,6 +123,17 @@ func appendEffectivePolicy(buffer *bytes.Buffer, report Report) {
 		buffer.WriteString(strings.Join(sanitizeTerminalStrings(report.EffectivePolicy.License.Deny), ", "))
 		buffer.WriteString("\n")
 	}
+	if len(report.EffectivePolicy.License.Allow) > 0 {
+		buffer.WriteString("- license_allow: ")
+		buffer.WriteString(strings.Join(sanitizeTerminalStrings(report.EffectivePolicy.License.Allow), ", "))
+		buffer.WriteString("\n")
+	}
+	if unknown := report.EffectivePolicy.License.Unknown; unknown != "" && unknown != LicenseUnknownAllow {
+		writef(buffer, "- license_unknown: %s\n", unknown)
+	}
+	if len(report.EffectivePolicy.License.Exceptions) > 0 {
+		writef(buffer, "- license_exceptions: %d\n", len(report.EffectivePolicy.License.Exceptions))
+	}
 	writef(buffer, "- license_fail_on_deny: %t\n", report.EffectivePolicy.License.FailOnDenied)
 	writef(buffer, "- license_include_registry_provenance: %t\n", report.EffectivePolicy.License.IncludeRegistryProvenance)
 	if strings.TrimSpace(report.EffectivePolicy.Vulnerabilities.AdvisorySourcePath) != "" {
@@ -340,6 +356,17 @@ func appendWarnings(buffer *bytes.Buffer, report Report) {
 	}
 }
 
+func appendGateResults(buffer *bytes.Buffer, results []GateResult) {
+	if len(results) == 0 {
+		return
+	}
+	buffer.WriteString("\nGates:\n")
+	for _, result := range results {
+		writef(buffer, "- %s\n", FormatGateLine(result))
+	}
+	writef(buffer, "Exit code: %d\n", GatesExitCode(results))
+}
+
 func escapeTableWarning(warning string) string {
 	return sanitizeTerminalString(tableWarningReplacer.Replace(warning))
 }
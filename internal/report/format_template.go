@@ -0,0 +1,286 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/safeio"
+)
+
+const CustomOutputTemplatesPreviewFeature = "custom-output-templates-preview"
+
+const (
+	reportTemplateReadLimit   = 1 << 20
+	reportTemplateOutputLimit = 64 << 20
+	reportTemplateStepLimit   = 10_000_000
+	reportTemplateTimeout     = 30 * time.Second
+	// reportTemplateStepFunc is called at the start of every range iteration
+	// and template body to charge the execution budget.
+	reportTemplateStepFunc = "lopperTemplateStep"
+)
+
+var (
+	ErrTemplateRequired       = errors.New("template format requires --template")
+	ErrTemplateOutputTooLarge = errors.New("template output exceeds size limit")
+	ErrTemplateStepLimit      = errors.New("template exceeded its execution step limit")
+)
+
+// ReportTemplate renders a Report through a user-supplied text/template. The
+// template only sees the report value and the functions in
+// reportTemplateFuncs, none of which touch the filesystem, the environment,
+// or the network. Output is capped at reportTemplateOutputLimit bytes, and
+// execution at steps range iterations and template calls and at
+// reportTemplateTimeout.
+type ReportTemplate struct {
+	tmpl  *template.Template
+	steps int
+}
+
+// LoadReportTemplate reads and parses the template at path.
+func LoadReportTemplate(path string) (*ReportTemplate, error) {
+	content, err := safeio.ReadFileLimit(path, reportTemplateReadLimit)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	return ParseReportTemplate(path, string(content))
+}
+
+func ParseReportTemplate(name, text string) (*ReportTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(reportTemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	if err := insertTemplateSteps(tmpl); err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return &ReportTemplate{tmpl: tmpl, steps: reportTemplateStepLimit}, nil
+}
+
+// Render executes the template against report. It stops with
+// ErrTemplateStepLimit when the step budget runs out and with the context's
+// error when ctx is done or reportTemplateTimeout elapses.
+func (t *ReportTemplate) Render(ctx context.Context, report Report) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, reportTemplateTimeout)
+	defer cancel()
+	remaining := t.steps
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	tmpl.Funcs(template.FuncMap{reportTemplateStepFunc: func() (string, error) {
+		if remaining--; remaining < 0 {
+			return "", ErrTemplateStepLimit
+		}
+		return "", ctx.Err()
+	}})
+
+	var output strings.Builder
+	if err := tmpl.Execute(&limitedTemplateWriter{w: &output, remaining: reportTemplateOutputLimit}, report); err != nil {
+		for _, limit := range []error{ErrTemplateOutputTooLarge, ErrTemplateStepLimit} {
+			if errors.Is(err, limit) {
+				return "", limit
+			}
+		}
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return output.String(), nil
+}
+
+// insertTemplateSteps prepends a call to reportTemplateStepFunc to every
+// template body and range body, the only places where execution can repeat
+// without bound.
+func insertTemplateSteps(tmpl *template.Template) error {
+	step, err := template.New(tmpl.Name()).Funcs(reportTemplateFuncs()).Parse("{{" + reportTemplateStepFunc + "}}")
+	if err != nil {
+		return err
+	}
+	checkpoint := step.Tree.Root.Nodes[0]
+	for _, defined := range tmpl.Templates() {
+		if defined.Tree != nil && defined.Tree.Root != nil {
+			insertListSteps(defined.Tree.Root, checkpoint, true)
+		}
+	}
+	return nil
+}
+
+func insertListSteps(list *parse.ListNode, checkpoint parse.Node, prepend bool) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch typed := node.(type) {
+		case *parse.RangeNode:
+			insertListSteps(typed.List, checkpoint, true)
+			insertListSteps(typed.ElseList, checkpoint, false)
+		case *parse.IfNode:
+			insertListSteps(typed.List, checkpoint, false)
+			insertListSteps(typed.ElseList, checkpoint, false)
+		case *parse.WithNode:
+			insertListSteps(typed.List, checkpoint, false)
+			insertListSteps(typed.ElseList, checkpoint, false)
+		}
+	}
+	if prepend {
+		list.Nodes = append([]parse.Node{checkpoint.Copy()}, list.Nodes...)
+	}
+}
+
+type limitedTemplateWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedTemplateWriter) Write(p []byte) (int, error) {
+	if len(p) > l.remaining {
+		return 0, ErrTemplateOutputTooLarge
+	}
+	l.remaining -= len(p)
+	return l.w.Write(p)
+}
+
+func reportTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"sortBy":        templateSortDependencies,
+		"limit":         templateLimit,
+		"bytes":         templateBytes,
+		"signedBytes":   templateSignedBytes,
+		"percent":       func(value float64) string { return fmt.Sprintf("%.1f%%", value) },
+		"signedPercent": signedPct,
+		"wastePercent":  wasteFromDependency,
+		"mdEscape":      escapeMarkdownTable,
+		"mdCode":        markdownCodeCell,
+		"join":          func(sep string, values []string) string { return strings.Join(values, sep) },
+		"lower":         strings.ToLower,
+		"upper":         strings.ToUpper,
+		"trim":          strings.TrimSpace,
+		"json":          templateJSON,
+		// Render rebinds the step function to its own budget.
+		reportTemplateStepFunc: func() string { return "" },
+	}
+}
+
+// templateSortDependencies returns a sorted copy of deps. key is one of
+// name, language, usedPercent, wastePercent, unusedBytes, usedExports,
+// totalExports, or score; a leading "-" sorts descending. Ties fall back to
+// language and name so output is deterministic.
+func templateSortDependencies(key string, deps []DependencyReport) ([]DependencyReport, error) {
+	descending := strings.HasPrefix(key, "-")
+	compare, ok := templateDependencyOrder(strings.TrimPrefix(key, "-"))
+	if !ok {
+		return nil, fmt.Errorf("sortBy: unknown key %q", key)
+	}
+	sorted := append([]DependencyReport(nil), deps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		left, right := sorted[i], sorted[j]
+		if descending {
+			left, right = right, left
+		}
+		if cmp := compare(left, right); cmp != 0 {
+			return cmp < 0
+		}
+		if sorted[i].Language != sorted[j].Language {
+			return sorted[i].Language < sorted[j].Language
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
+
+func templateDependencyOrder(key string) (func(left, right DependencyReport) int, bool) {
+	switch key {
+	case "name":
+		return func(left, right DependencyReport) int { return strings.Compare(left.Name, right.Name) }, true
+	case "language":
+		return func(left, right DependencyReport) int { return strings.Compare(left.Language, right.Language) }, true
+	case "usedPercent":
+		return templateFloatOrder(func(dep DependencyReport) float64 { return dep.UsedPercent }), true
+	case "wastePercent":
+		return templateFloatOrder(wasteFromDependency), true
+	case "unusedBytes":
+		return templateFloatOrder(func(dep DependencyReport) float64 { return float64(dep.EstimatedUnusedBytes) }), true
+	case "usedExports":
+		return templateFloatOrder(func(dep DependencyReport) float64 { return float64(dep.UsedExportsCount) }), true
+	case "totalExports":
+		return templateFloatOrder(func(dep DependencyReport) float64 { return float64(dep.TotalExportsCount) }), true
+	case "score":
+		return templateFloatOrder(func(dep DependencyReport) float64 {
+			if dep.RemovalCandidate == nil {
+				return 0
+			}
+			return dep.RemovalCandidate.Score
+		}), true
+	default:
+		return nil, false
+	}
+}
+
+func templateFloatOrder(value func(DependencyReport) float64) func(left, right DependencyReport) int {
+	return func(left, right DependencyReport) int {
+		switch l, r := value(left), value(right); {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// templateLimit returns at most n leading elements of any slice.
+func templateLimit(n int, list any) (any, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("limit: expected a list, got %T", list)
+	}
+	if n < 0 || n >= value.Len() {
+		return list, nil
+	}
+	return value.Slice(0, n).Interface(), nil
+}
+
+func templateBytes(value any) (string, error) {
+	size, err := templateInt64(value)
+	if err != nil {
+		return "", fmt.Errorf("bytes: %w", err)
+	}
+	return formatBytes(size), nil
+}
+
+func templateSignedBytes(value any) (string, error) {
+	size, err := templateInt64(value)
+	if err != nil {
+		return "", fmt.Errorf("signedBytes: %w", err)
+	}
+	return signedBytes(size), nil
+}
+
+func templateInt64(value any) (int64, error) {
+	switch typed := value.(type) {
+	case int:
+		return int64(typed), nil
+	case int64:
+		return typed, nil
+	case float64:
+		return int64(typed), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+func templateJSON(value any) (string, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
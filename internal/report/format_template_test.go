@@ -0,0 +1,134 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func renderReportTemplate(t *testing.T, text string, rep Report) string {
+	t.Helper()
+	tmpl, err := ParseReportTemplate("test.tmpl", text)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	output, err := tmpl.Render(context.Background(), rep)
+	if err != nil {
+		t.Fatalf("render template: %v", err)
+	}
+	return output
+}
+
+func TestReportTemplateRendersWithCuratedFunctions(t *testing.T) {
+	rep := sampleHTMLReport()
+	rep.Dependencies = append(rep.Dependencies, DependencyReport{Language: "js-ts", Name: "a|b", UsedPercent: 50, TotalExportsCount: 2, UsedExportsCount: 1, EstimatedUnusedBytes: 3 << 20})
+	text := `{{ range sortBy "-unusedBytes" .Dependencies | limit 2 }}| {{ mdEscape .Name }} | {{ bytes .EstimatedUnusedBytes }} | {{ percent .UsedPercent }} | {{ wastePercent . | percent }} |
+{{ end }}{{ join "; " .Warnings | upper }}
+{{ signedPercent 2.5 }} {{ signedBytes -2048 }} {{ mdCode "x|y" }} {{ (index .Dependencies 0).License | json }}
+`
+	output := renderReportTemplate(t, text, rep)
+	assertOutputContains(t, output,
+		"| a\\|b | 3.0 MB | 50.0% | 50.0% |\n| lodash | 2.0 KB | 25.0% | 75.0% |\n",
+		"PARTIAL <B>ANALYSIS</B>",
+		"+2.5% -2.0 KB `x\\|y`",
+		`"spdx":"GPL-3.0-only"`,
+	)
+	assertOutputNotContains(t, output, "requests")
+
+	byName := renderReportTemplate(t, `{{ range sortBy "name" .Dependencies }}{{ .Name }},{{ end }}`, rep)
+	if byName != "a|b,lodash,requests," {
+		t.Fatalf("unexpected name order %q", byName)
+	}
+}
+
+func TestReportTemplateErrors(t *testing.T) {
+	if _, err := ParseReportTemplate("bad.tmpl", "{{ .Name "); err == nil || !strings.Contains(err.Error(), "parse template") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+	if _, err := ParseReportTemplate("fs.tmpl", `{{ readFile "/etc/passwd" }}`); err == nil {
+		t.Fatalf("expected undefined filesystem function to be rejected")
+	}
+
+	cases := map[string]string{
+		"unknown sort key": `{{ sortBy "size" .Dependencies }}`,
+		"limit non-list":   `{{ limit 1 .RepoPath }}`,
+		"bytes non-number": `{{ bytes .RepoPath }}`,
+		"unknown field":    `{{ .Missing }}`,
+	}
+	for name, text := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := ParseReportTemplate(name, text)
+			if err != nil {
+				t.Fatalf("parse template: %v", err)
+			}
+			if _, err := tmpl.Render(context.Background(), sampleHTMLReport()); err == nil || !strings.Contains(err.Error(), "render template") {
+				t.Fatalf("expected render error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewFormatter().Format(Report{}, FormatTemplate); !errors.Is(err, ErrTemplateRequired) {
+		t.Fatalf("expected ErrTemplateRequired from Formatter, got %v", err)
+	}
+}
+
+func TestReportTemplateCapsOutput(t *testing.T) {
+	tmpl, err := ParseReportTemplate("big.tmpl", `{{ range .Warnings }}{{ . }}{{ end }}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	big := strings.Repeat("x", reportTemplateOutputLimit/2+1)
+	if _, err := tmpl.Render(context.Background(), Report{Warnings: []string{big, big}}); !errors.Is(err, ErrTemplateOutputTooLarge) {
+		t.Fatalf("expected output limit error, got %v", err)
+	}
+}
+
+func TestReportTemplateStopsSilentLoops(t *testing.T) {
+	tmpl, err := ParseReportTemplate("loop.tmpl", `{{ define "spin" }}{{ range 1000 }}{{ end }}{{ end }}{{ range 1000000 }}{{ range $.Warnings }}{{ template "spin" }}{{ end }}{{ end }}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	rep := Report{Warnings: []string{"a", "b"}}
+	tmpl.steps = 10_000
+	if _, err := tmpl.Render(context.Background(), rep); !errors.Is(err, ErrTemplateStepLimit) {
+		t.Fatalf("expected step limit error, got %v", err)
+	}
+
+	tmpl.steps = reportTemplateStepLimit
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, err := tmpl.Render(ctx, rep); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected rendering to stop at the deadline, took %s", elapsed)
+	}
+}
+
+func TestLoadReportTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.tmpl")
+	if err := os.WriteFile(path, []byte("{{ .RepoPath }}"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	tmpl, err := LoadReportTemplate(path)
+	if err != nil {
+		t.Fatalf("load template: %v", err)
+	}
+	if output, err := tmpl.Render(context.Background(), Report{RepoPath: "/repo"}); err != nil || output != "/repo" {
+		t.Fatalf("render loaded template = %q, %v", output, err)
+	}
+	if _, err := LoadReportTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil || !strings.Contains(err.Error(), "read template") {
+		t.Fatalf("expected missing template error, got %v", err)
+	}
+}
+
+func TestParseFormatTemplate(t *testing.T) {
+	if format, err := ParseFormat("template"); err != nil || format != FormatTemplate {
+		t.Fatalf("ParseFormat(template) = %q, %v", format, err)
+	}
+}
@@ -0,0 +1,251 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const ExitCodeContractPreviewFeature = "exit-code-contract-preview"
+
+// Gate names used in gateResults, JUnit policy testcases, and --explain-exit.
+const (
+	GateFailOnIncrease                 = "fail-on-increase"
+	GateMaxUncertainImports            = "max-uncertain-imports"
+	GateLicenseFailOnDeny              = "license-fail-on-deny"
+	GateReachableVulnerabilityPriority = "reachable-vulnerability-priority"
+	GatePolicyRules                    = "policy-rules"
+	GateLockfileDrift                  = "lockfile-drift"
+	GatePRReviewRegressions            = "pr-review-regressions"
+)
+
+const (
+	GateStatusPass    = "pass"
+	GateStatusFail    = "fail"
+	GateStatusSkipped = "skipped"
+	GateStatusError   = "error"
+)
+
+// Exit codes of the exit-code contract. 0, 1 (runtime or configuration
+// error), and 2 (usage error) keep their meaning; each gate fails with its own
+// code so CI wrappers can route failures without parsing stderr.
+const (
+	ExitCodeFailOnIncrease                 = 10
+	ExitCodeMaxUncertainImports            = 11
+	ExitCodeLicenseFailOnDeny              = 12
+	ExitCodeReachableVulnerabilityPriority = 13
+	ExitCodeLockfileDrift                  = 14
+	ExitCodePRReviewRegressions            = 15
+	ExitCodePolicyRules                    = 16
+)
+
+// GateExitCode returns the contract exit code for gate, or 1 for an unknown
+// gate.
+func GateExitCode(gate string) int {
+	switch gate {
+	case GateFailOnIncrease:
+		return ExitCodeFailOnIncrease
+	case GateMaxUncertainImports:
+		return ExitCodeMaxUncertainImports
+	case GateLicenseFailOnDeny:
+		return ExitCodeLicenseFailOnDeny
+	case GateReachableVulnerabilityPriority:
+		return ExitCodeReachableVulnerabilityPriority
+	case GateLockfileDrift:
+		return ExitCodeLockfileDrift
+	case GatePRReviewRegressions:
+		return ExitCodePRReviewRegressions
+	case GatePolicyRules:
+		return ExitCodePolicyRules
+	default:
+		return 1
+	}
+}
+
+// EvaluateGates evaluates the threshold gates that can be decided from rep
+// alone, with the same semantics analyse uses to choose its exit code. Gates
+// without a configured threshold are reported as skipped.
+func EvaluateGates(rep Report) []GateResult {
+	thresholds, ok := gateThresholds(rep)
+	return []GateResult{
+		failOnIncreaseGate(rep, thresholds.FailOnIncreasePercent, ok),
+		uncertaintyGate(rep, thresholds.MaxUncertainImportCount, ok),
+		deniedLicenseGate(rep),
+		reachableVulnerabilityGate(rep, thresholds.ReachableVulnerabilityPriority),
+		policyRulesGate(rep),
+	}
+}
+
+// reportGates prefers the gate results recorded during analysis, which can
+// include gates such as lockfile drift that the report alone cannot decide.
+func reportGates(rep Report) []GateResult {
+	if len(rep.GateResults) > 0 {
+		return rep.GateResults
+	}
+	return EvaluateGates(rep)
+}
+
+func gateThresholds(rep Report) (EffectiveThresholds, bool) {
+	if rep.EffectiveThresholds != nil {
+		return *rep.EffectiveThresholds, true
+	}
+	if rep.EffectivePolicy != nil {
+		return rep.EffectivePolicy.Thresholds, true
+	}
+	return EffectiveThresholds{}, false
+}
+
+func newGateResult(gate string) GateResult {
+	return GateResult{Gate: gate, Status: GateStatusPass, ExitCode: GateExitCode(gate)}
+}
+
+func skipGate(result GateResult) GateResult {
+	result.Status = GateStatusSkipped
+	result.Detail = "disabled"
+	return result
+}
+
+func failOnIncreaseGate(rep Report, threshold int, configured bool) GateResult {
+	result := newGateResult(GateFailOnIncrease)
+	if !configured || threshold < 0 {
+		return skipGate(result)
+	}
+	result.Threshold = fmt.Sprintf("%d%%", threshold)
+	if rep.WasteIncreasePercent == nil {
+		result.Status = GateStatusError
+		result.Detail = "a baseline is required to evaluate waste increase"
+		return result
+	}
+	increase := *rep.WasteIncreasePercent
+	result.Observed = fmt.Sprintf("%.1f%%", increase)
+	if increase > float64(threshold) {
+		result.Status = GateStatusFail
+		result.Detail = fmt.Sprintf("waste increased by %.1f%%, above the %d%% threshold", increase, threshold)
+		return result
+	}
+	result.Detail = fmt.Sprintf("waste increase %.1f%% is within the %d%% threshold", increase, threshold)
+	return result
+}
+
+func uncertaintyGate(rep Report, threshold int, configured bool) GateResult {
+	result := newGateResult(GateMaxUncertainImports)
+	if !configured || threshold < 0 {
+		return skipGate(result)
+	}
+	uncertain := 0
+	if rep.UsageUncertainty != nil {
+		uncertain = rep.UsageUncertainty.UncertainImportUses
+	}
+	result.Threshold = strconv.Itoa(threshold)
+	result.Observed = strconv.Itoa(uncertain)
+	result.Detail = fmt.Sprintf("%d uncertain imports, threshold %d", uncertain, threshold)
+	if uncertain > threshold {
+		result.Status = GateStatusFail
+	}
+	return result
+}
+
+func deniedLicenseGate(rep Report) GateResult {
+	result := newGateResult(GateLicenseFailOnDeny)
+	if rep.EffectivePolicy == nil || !rep.EffectivePolicy.License.FailOnDenied {
+		return skipGate(result)
+	}
+	result.Threshold = "0"
+	if rep.BaselineComparison != nil {
+		denied := len(rep.BaselineComparison.NewDeniedLicenses)
+		result.Observed = strconv.Itoa(denied)
+		result.Detail = fmt.Sprintf("%d newly denied licenses since baseline", denied)
+	} else {
+		denied := CountDeniedLicenses(rep.Dependencies)
+		result.Observed = strconv.Itoa(denied)
+		result.Detail = fmt.Sprintf("%d dependencies with denied licenses", denied)
+	}
+	if HasDeniedLicenseBreach(rep) {
+		result.Status = GateStatusFail
+	}
+	return result
+}
+
+func reachableVulnerabilityGate(rep Report, threshold string) GateResult {
+	result := newGateResult(GateReachableVulnerabilityPriority)
+	normalized := NormalizeVulnerabilityPriorityThreshold(threshold)
+	if normalized == VulnerabilityPriorityOff {
+		return skipGate(result)
+	}
+	count := countReachableVulnerabilitiesAtOrAbove(rep, threshold)
+	result.Threshold = normalized
+	result.Observed = strconv.Itoa(count)
+	if count > 0 {
+		result.Status = GateStatusFail
+		result.Detail = fmt.Sprintf("reachable vulnerabilities at or above %s priority", normalized)
+		return result
+	}
+	result.Detail = fmt.Sprintf("no reachable vulnerabilities at or above %s priority", normalized)
+	return result
+}
+
+func policyRulesGate(rep Report) GateResult {
+	result := newGateResult(GatePolicyRules)
+	rules := policyRuleCount(rep)
+	if rules == 0 {
+		return skipGate(result)
+	}
+	breaches := countPolicyRuleBreaches(rep)
+	result.Threshold = "0"
+	result.Observed = strconv.Itoa(breaches)
+	if rep.BaselineComparison != nil {
+		result.Detail = fmt.Sprintf("%d new error-severity rule findings since baseline from %d rules", breaches, rules)
+	} else {
+		result.Detail = fmt.Sprintf("%d error-severity rule findings from %d rules", breaches, rules)
+	}
+	if breaches > 0 {
+		result.Status = GateStatusFail
+	}
+	return result
+}
+
+// FailedGates returns the gates in results that failed or could not be
+// evaluated.
+func FailedGates(results []GateResult) []GateResult {
+	failed := make([]GateResult, 0)
+	for _, result := range results {
+		if result.Status == GateStatusFail || result.Status == GateStatusError {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// GatesExitCode returns the exit code the contract assigns to results. Gates
+// are checked in evaluation order, so the first failing gate decides; a gate
+// that could not be evaluated is a configuration error.
+func GatesExitCode(results []GateResult) int {
+	for _, result := range results {
+		switch result.Status {
+		case GateStatusFail:
+			return result.ExitCode
+		case GateStatusError:
+			return 1
+		}
+	}
+	return 0
+}
+
+// FormatGateLine renders one gate as "gate: status (observed X, threshold Y)".
+func FormatGateLine(result GateResult) string {
+	var details []string
+	if result.Observed != "" {
+		details = append(details, "observed "+result.Observed)
+	}
+	if result.Threshold != "" {
+		details = append(details, "threshold "+result.Threshold)
+	}
+	line := result.Gate + ": " + result.Status
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	if result.Status == GateStatusFail {
+		line += fmt.Sprintf(" -> exit %d", result.ExitCode)
+	}
+	return line
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluateGates(t *testing.T) {
+	increase := 12.5
+	rep := Report{
+		WasteIncreasePercent: &increase,
+		UsageUncertainty:     &UsageUncertainty{UncertainImportUses: 2},
+		EffectiveThresholds: &EffectiveThresholds{
+			FailOnIncreasePercent:          10,
+			MaxUncertainImportCount:        5,
+			ReachableVulnerabilityPriority: VulnerabilityPriorityOff,
+		},
+	}
+
+	results := EvaluateGates(rep)
+	got := make([]string, 0, len(results))
+	for _, result := range results {
+		got = append(got, result.Gate+"="+result.Status)
+	}
+	want := "fail-on-increase=fail,max-uncertain-imports=pass,license-fail-on-deny=skipped,reachable-vulnerability-priority=skipped,policy-rules=skipped"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected gates %q", strings.Join(got, ","))
+	}
+	if results[0].Observed != "12.5%" || results[0].Threshold != "10%" {
+		t.Fatalf("unexpected fail-on-increase values %#v", results[0])
+	}
+	if code := GatesExitCode(results); code != ExitCodeFailOnIncrease {
+		t.Fatalf("expected exit code %d, got %d", ExitCodeFailOnIncrease, code)
+	}
+	if failed := FailedGates(results); len(failed) != 1 || failed[0].Gate != GateFailOnIncrease {
+		t.Fatalf("unexpected failed gates %#v", failed)
+	}
+
+	rep.WasteIncreasePercent = nil
+	results = EvaluateGates(rep)
+	if results[0].Status != GateStatusError || GatesExitCode(results) != 1 {
+		t.Fatalf("expected a missing baseline to be a gate error, got %#v", results[0])
+	}
+}
+
+func TestEvaluateGatesWithoutThresholds(t *testing.T) {
+	for _, result := range EvaluateGates(Report{}) {
+		if result.Status != GateStatusSkipped || result.ExitCode != GateExitCode(result.Gate) {
+			t.Fatalf("expected gate without thresholds to be skipped, got %#v", result)
+		}
+	}
+	if code := GatesExitCode(EvaluateGates(Report{})); code != 0 {
+		t.Fatalf("expected skipped gates to exit 0, got %d", code)
+	}
+	if code := GateExitCode("unknown"); code != 1 {
+		t.Fatalf("expected unknown gate to map to 1, got %d", code)
+	}
+}
+
+func TestFormatTableGateFooter(t *testing.T) {
+	rep := Report{
+		Dependencies: []DependencyReport{{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 2, UsedPercent: 50}},
+		GateResults: []GateResult{
+			{Gate: GateLockfileDrift, Status: GateStatusPass, Threshold: "warn", Observed: "0", ExitCode: ExitCodeLockfileDrift},
+			{Gate: GateLicenseFailOnDeny, Status: GateStatusFail, Threshold: "0", Observed: "1", ExitCode: ExitCodeLicenseFailOnDeny},
+		},
+	}
+	output, err := NewFormatter().Format(rep, FormatTable)
+	if err != nil {
+		t.Fatalf("format table: %v", err)
+	}
+	want := "\nGates:\n- lockfile-drift: pass (observed 0, threshold warn)\n- license-fail-on-deny: fail (observed 1, threshold 0) -> exit 12\nExit code: 12\n"
+	if !strings.Contains(output, want) {
+		t.Fatalf("expected gate footer %q in table output:\n%s", want, output)
+	}
+}
@@ -1,7 +1,5 @@
 package report
 
-import "sort"
-
 const (
 	licenseSourceUnknown = "unknown"
 )
@@ -20,18 +18,7 @@ func NormalizeDependencyLicenses(dependencies []DependencyReport) {
 }
 
 func ApplyLicensePolicy(dependencies []DependencyReport, denyList []string) {
-	normalizedDeny := normalizeDenyList(denyList)
-	if len(normalizedDeny) == 0 {
-		return
-	}
-
-	for i := range dependencies {
-		license := dependencies[i].License
-		if license == nil {
-			continue
-		}
-		license.Denied = spdxExpressionContainsDenied(license.SPDX, normalizedDeny)
-	}
+	ApplyLicensePolicyRules(dependencies, LicensePolicyRules{Deny: denyList})
 }
 
 func CountDeniedLicenses(dependencies []DependencyReport) int {
@@ -44,16 +31,14 @@ func CountDeniedLicenses(dependencies []DependencyReport) int {
 	return count
 }
 
-func normalizeDenyList(values []string) map[string]struct{} {
-	normalized := make(map[string]struct{}, len(values))
-	for _, value := range values {
-		id := normalizeSPDXID(value)
-		if id == "" {
-			continue
-		}
-		normalized[id] = struct{}{}
+// HasDeniedLicenseBreach reports whether rep carries a denied license that a
+// fail-on-deny policy should reject. With a baseline comparison only licenses
+// newly denied since the baseline count.
+func HasDeniedLicenseBreach(rep Report) bool {
+	if rep.BaselineComparison != nil {
+		return len(rep.BaselineComparison.NewDeniedLicenses) > 0
 	}
-	return normalized
+	return CountDeniedLicenses(rep.Dependencies) > 0
 }
 
 func normalizeSPDXID(value string) string {
@@ -75,48 +60,6 @@ func normalizeSPDXID(value string) string {
 	return string(out)
 }
 
-func spdxExpressionContainsDenied(expression string, deny map[string]struct{}) bool {
-	if len(deny) == 0 {
-		return false
-	}
-	token := make([]rune, 0, len(expression))
-	flush := func() bool {
-		if len(token) == 0 {
-			return false
-		}
-		id := normalizeSPDXID(string(token))
-		token = token[:0]
-		if id == "" {
-			return false
-		}
-		_, denied := deny[id]
-		return denied
-	}
-
-	for _, r := range expression {
-		switch {
-		case r >= 'a' && r <= 'z',
-			r >= 'A' && r <= 'Z',
-			r >= '0' && r <= '9',
-			r == '-',
-			r == '.',
-			r == '+':
-			token = append(token, r)
-		default:
-			if flush() {
-				return true
-			}
-		}
-	}
-	return flush()
-}
-
 func SortedDenyList(values []string) []string {
-	seen := normalizeDenyList(values)
-	items := make([]string, 0, len(seen))
-	for value := range seen {
-		items = append(items, value)
-	}
-	sort.Strings(items)
-	return items
+	return SortedLicensePatterns(values)
 }
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"errors"
+	"strings"
+)
+
+var errInvalidSPDXExpression = errors.New("invalid SPDX expression")
+
+// spdxNode is one node of a parsed SPDX license expression. Leaves carry a
+// license identifier and optional WITH exception; inner nodes combine their
+// operands with OR (a choice) or AND (all apply).
+type spdxNode struct {
+	operator  string
+	operands  []*spdxNode
+	license   string
+	exception string
+}
+
+func parseSPDXExpression(expression string) (*spdxNode, error) {
+	tokens := tokenizeSPDXExpression(expression)
+	if len(tokens) == 0 {
+		return nil, errInvalidSPDXExpression
+	}
+	parser := &spdxParser{tokens: tokens}
+	node, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos != len(parser.tokens) {
+		return nil, errInvalidSPDXExpression
+	}
+	return node, nil
+}
+
+func tokenizeSPDXExpression(expression string) []string {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range expression {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxParser) peekOperator(operator string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], operator)
+}
+
+func (p *spdxParser) parseOr() (*spdxNode, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *spdxParser) parseAnd() (*spdxNode, error) {
+	return p.parseBinary("AND", p.parseTerm)
+}
+
+func (p *spdxParser) parseBinary(operator string, operand func() (*spdxNode, error)) (*spdxNode, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*spdxNode{first}
+	for p.peekOperator(operator) {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &spdxNode{operator: operator, operands: operands}, nil
+}
+
+func (p *spdxParser) parseTerm() (*spdxNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errInvalidSPDXExpression
+	}
+	token := p.tokens[p.pos]
+	if token == "(" {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, errInvalidSPDXExpression
+		}
+		p.pos++
+		return node, nil
+	}
+	if token == ")" || isSPDXOperator(token) {
+		return nil, errInvalidSPDXExpression
+	}
+	p.pos++
+	leaf := &spdxNode{license: normalizeSPDXID(token)}
+	if leaf.license == "" {
+		return nil, errInvalidSPDXExpression
+	}
+	if p.peekOperator("WITH") {
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] == "(" || p.tokens[p.pos] == ")" || isSPDXOperator(p.tokens[p.pos]) {
+			return nil, errInvalidSPDXExpression
+		}
+		leaf.exception = normalizeSPDXID(p.tokens[p.pos])
+		p.pos++
+	}
+	return leaf, nil
+}
+
+func isSPDXOperator(token string) bool {
+	switch strings.ToUpper(token) {
+	case "OR", "AND", "WITH":
+		return true
+	default:
+		return false
+	}
+}
+
+// satisfiable reports whether a licensee can comply with the expression using
+// only leaves accepted by the predicate: any operand of OR suffices, while
+// every operand of AND must be accepted.
+func (n *spdxNode) satisfiable(accept func(*spdxNode) bool) bool {
+	switch n.operator {
+	case "OR":
+		for _, operand := range n.operands {
+			if operand.satisfiable(accept) {
+				return true
+			}
+		}
+		return false
+	case "AND":
+		for _, operand := range n.operands {
+			if !operand.satisfiable(accept) {
+				return false
+			}
+		}
+		return true
+	default:
+		return accept(n)
+	}
+}
+
+// everyLeaf reports whether the predicate accepts every leaf regardless of
+// operator, the conservative reading that treats OR like AND.
+func (n *spdxNode) everyLeaf(accept func(*spdxNode) bool) bool {
+	if n.operator == "" {
+		return accept(n)
+	}
+	for _, operand := range n.operands {
+		if !operand.everyLeaf(accept) {
+			return false
+		}
+	}
+	return true
+}
+
+// identifiers returns the match candidates for a leaf: the compound
+// "ID WITH EXCEPTION" form first so policies can target it explicitly, then
+// the bare license identifier.
+func (n *spdxNode) identifiers() []string {
+	if n.exception == "" {
+		return []string{n.license}
+	}
+	return []string{n.license + " WITH " + n.exception, n.license}
+}
+
+// spdxExpressionMentions reports whether license names the whole expression
+// or one of its leaves, so an exception for GPL-3.0-only also covers
+// "MIT OR GPL-3.0-only".
+func spdxExpressionMentions(expression, license string) bool {
+	target := normalizeSPDXID(license)
+	if target == "" {
+		return false
+	}
+	if normalizeSPDXID(expression) == target {
+		return true
+	}
+	node, err := parseSPDXExpression(expression)
+	if err != nil {
+		return false
+	}
+	return node.mentions(target)
+}
+
+func (n *spdxNode) mentions(target string) bool {
+	if n.operator != "" {
+		for _, operand := range n.operands {
+			if operand.mentions(target) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, identifier := range n.identifiers() {
+		if normalizeSPDXID(identifier) == target {
+			return true
+		}
+	}
+	return false
+}
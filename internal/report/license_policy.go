@@ -0,0 +1,299 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	LicenseUnknownAllow = "allow"
+	LicenseUnknownWarn  = "warn"
+	LicenseUnknownDeny  = "deny"
+
+	licensePolicyReasonDenied      = "denied by license_deny"
+	licensePolicyReasonNotAllowed  = "not covered by license_allow"
+	licensePolicyReasonUnknownDeny = "unknown license denied by license_unknown"
+)
+
+// LicensePolicyRules is the resolved license policy evaluated against each
+// dependency license. Deny and Allow entries are SPDX identifiers or glob
+// patterns such as BSD-*; Unknown controls dependencies without an SPDX value.
+// Expressions evaluates OR as a licensee's choice; without it every
+// identifier in an expression must pass the policy.
+type LicensePolicyRules struct {
+	Deny        []string
+	Allow       []string
+	Unknown     string
+	Expressions bool
+}
+
+// ApplyLicensePolicyRules marks dependency licenses denied when their SPDX
+// expression does not satisfy the policy. It returns review diagnostics for
+// unknown licenses when the unknown policy is warn.
+func ApplyLicensePolicyRules(dependencies []DependencyReport, rules LicensePolicyRules) []string {
+	deny := normalizeLicensePatterns(rules.Deny)
+	allow := normalizeLicensePatterns(rules.Allow)
+	unknown := NormalizeLicenseUnknownPolicy(rules.Unknown)
+	if len(deny) == 0 && len(allow) == 0 && unknown == LicenseUnknownAllow {
+		return nil
+	}
+
+	diagnostics := make([]string, 0)
+	for i := range dependencies {
+		license := dependencies[i].License
+		if license == nil {
+			continue
+		}
+		license.Denied, license.PolicyReason = evaluateLicensePolicy(*license, deny, allow, unknown, rules.Expressions)
+		if unknown == LicenseUnknownWarn && licenseIsUnknown(*license) {
+			diagnostics = append(diagnostics, fmt.Sprintf("unknown license requires review for %s", dependencies[i].Name))
+		}
+	}
+	return sortedUniqueStrings(diagnostics)
+}
+
+func evaluateLicensePolicy(license DependencyLicense, deny, allow []string, unknown string, expressions bool) (bool, string) {
+	if licenseIsUnknown(license) {
+		if unknown == LicenseUnknownDeny {
+			return true, licensePolicyReasonUnknownDeny
+		}
+		return false, ""
+	}
+
+	node, err := parseSPDXExpression(license.SPDX)
+	if err != nil {
+		// Malformed expressions cannot be evaluated, so any denied token denies
+		// them and they never satisfy an allow-list.
+		if licenseTokensMatch(license.SPDX, deny) {
+			return true, licensePolicyReasonDenied
+		}
+		if len(allow) > 0 {
+			return true, licensePolicyReasonNotAllowed
+		}
+		return false, ""
+	}
+
+	satisfies := node.everyLeaf
+	if expressions {
+		satisfies = node.satisfiable
+	}
+	notDenied := func(leaf *spdxNode) bool {
+		return !licenseIdentifiersMatch(leaf.identifiers(), deny)
+	}
+	if !satisfies(notDenied) {
+		return true, licensePolicyReasonDenied
+	}
+	if len(allow) == 0 {
+		return false, ""
+	}
+	allowed := func(leaf *spdxNode) bool {
+		return notDenied(leaf) && licenseIdentifiersMatch(leaf.identifiers(), allow)
+	}
+	if !satisfies(allowed) {
+		return true, licensePolicyReasonNotAllowed
+	}
+	return false, ""
+}
+
+func licenseIsUnknown(license DependencyLicense) bool {
+	return license.Unknown || strings.TrimSpace(license.SPDX) == ""
+}
+
+func licenseIdentifiersMatch(identifiers, patterns []string) bool {
+	for _, identifier := range identifiers {
+		for _, pattern := range patterns {
+			if licensePatternMatches(pattern, identifier) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func licenseTokensMatch(expression string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	tokens := strings.FieldsFunc(expression, func(r rune) bool {
+		return normalizeSPDXID(string(r)) == ""
+	})
+	for _, token := range tokens {
+		if licenseIdentifiersMatch([]string{normalizeSPDXID(token)}, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+func licensePatternMatches(pattern, identifier string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == identifier
+	}
+	matched, err := path.Match(pattern, identifier)
+	return err == nil && matched
+}
+
+// SortedLicensePatterns normalizes deny or allow entries for display in the
+// effective policy, keeping glob wildcards and WITH exception forms.
+func SortedLicensePatterns(values []string) []string {
+	patterns := normalizeLicensePatterns(values)
+	sort.Strings(patterns)
+	return patterns
+}
+
+func normalizeLicensePatterns(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	patterns := make([]string, 0, len(values))
+	for _, value := range values {
+		pattern := normalizeLicensePattern(value)
+		if pattern == "" {
+			continue
+		}
+		if _, ok := seen[pattern]; ok {
+			continue
+		}
+		seen[pattern] = struct{}{}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+func normalizeLicensePattern(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 3 && strings.EqualFold(fields[1], "WITH") {
+		license, exception := normalizeLicensePatternID(fields[0]), normalizeLicensePatternID(fields[2])
+		if license == "" || exception == "" {
+			return ""
+		}
+		return license + " WITH " + exception
+	}
+	return normalizeLicensePatternID(value)
+}
+
+func normalizeLicensePatternID(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r == '*' {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(normalizeSPDXID(string(r)))
+	}
+	if strings.Trim(b.String(), "*") == "" {
+		return ""
+	}
+	return b.String()
+}
+
+func NormalizeLicenseUnknownPolicy(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return LicenseUnknownAllow
+	}
+	return normalized
+}
+
+func ValidLicenseUnknownPolicy(value string) bool {
+	switch NormalizeLicenseUnknownPolicy(value) {
+	case LicenseUnknownAllow, LicenseUnknownWarn, LicenseUnknownDeny:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyLicenseExceptions lifts license denials for dependencies covered by an
+// approved, unexpired exception. Expired exceptions are recorded on the
+// license but leave the denial in place.
+func ApplyLicenseExceptions(reportData *Report, exceptions []LicenseException, now time.Time) []string {
+	if reportData == nil || len(exceptions) == 0 || len(reportData.Dependencies) == 0 {
+		return nil
+	}
+	diagnostics := make([]string, 0)
+	for i := range reportData.Dependencies {
+		dep := &reportData.Dependencies[i]
+		if dep.License == nil || !dep.License.Denied {
+			continue
+		}
+		decision, matched := licenseExceptionDecision(*dep, exceptions, now)
+		if !matched {
+			continue
+		}
+		dep.License.Exception = &decision
+		if decision.Expired {
+			diagnostics = append(diagnostics, fmt.Sprintf("expired license exception restored denial for %s", dep.Name))
+			continue
+		}
+		dep.License.Denied = false
+	}
+	return sortedUniqueStrings(diagnostics)
+}
+
+func licenseExceptionDecision(dep DependencyReport, exceptions []LicenseException, now time.Time) (LicenseExceptionDecision, bool) {
+	var best LicenseException
+	bestScore := -1
+	for _, exception := range exceptions {
+		if !licenseExceptionMatches(exception, dep) {
+			continue
+		}
+		score := licenseExceptionSpecificity(exception)
+		if score >= bestScore {
+			best = exception
+			bestScore = score
+		}
+	}
+	if bestScore < 0 {
+		return LicenseExceptionDecision{}, false
+	}
+	return LicenseExceptionDecision{
+		Owner:   strings.TrimSpace(best.Owner),
+		Reason:  strings.TrimSpace(best.Reason),
+		Scope:   licenseExceptionScope(best),
+		License: strings.TrimSpace(best.License),
+		Expires: strings.TrimSpace(best.Expires),
+		Source:  strings.TrimSpace(best.Source),
+		Expired: vulnerabilityExceptionExpired(best.Expires, now),
+	}, true
+}
+
+func licenseExceptionMatches(exception LicenseException, dep DependencyReport) bool {
+	purl := strings.TrimSpace(exception.PURL)
+	pkg := strings.TrimSpace(exception.Package)
+	if purl == "" && pkg == "" {
+		return false
+	}
+	if purl != "" && (dep.Identity == nil || !strings.EqualFold(CanonicalPURL(purl), CanonicalPURL(dep.Identity.PURL))) {
+		return false
+	}
+	if pkg != "" && !packageNamesMatch(pkg, dep.Name, dependencyAdvisoryEcosystem(dep)) {
+		return false
+	}
+	if license := strings.TrimSpace(exception.License); license != "" {
+		return dep.License != nil && spdxExpressionMentions(dep.License.SPDX, license)
+	}
+	return true
+}
+
+func licenseExceptionSpecificity(exception LicenseException) int {
+	score := 0
+	if strings.TrimSpace(exception.PURL) != "" {
+		score += 100
+	}
+	if strings.TrimSpace(exception.Package) != "" {
+		score += 10
+	}
+	if strings.TrimSpace(exception.License) != "" {
+		score += 5
+	}
+	return score
+}
+
+func licenseExceptionScope(exception LicenseException) string {
+	if purl := strings.TrimSpace(exception.PURL); purl != "" {
+		return "purl:" + purl
+	}
+	return "package:" + strings.TrimSpace(exception.Package)
+}
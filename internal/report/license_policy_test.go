@@ -0,0 +1,180 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyLicensePolicyRulesEvaluatesSPDXExpressions(t *testing.T) {
+	cases := []struct {
+		name   string
+		spdx   string
+		rules  LicensePolicyRules
+		denied bool
+		reason string
+	}{
+		{name: "or choice avoids denied", spdx: "(MIT OR GPL-2.0-only)", rules: LicensePolicyRules{Deny: []string{"GPL-2.0-only"}}},
+		{name: "or all denied", spdx: "GPL-2.0-only OR AGPL-3.0-only", rules: LicensePolicyRules{Deny: []string{"GPL-*", "AGPL-*"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "and any denied", spdx: "MIT AND GPL-3.0-only", rules: LicensePolicyRules{Deny: []string{"gpl-3.0-only"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "with exception matches base", spdx: "AGPL-3.0-only WITH GCC-exception-3.1", rules: LicensePolicyRules{Deny: []string{"AGPL-3.0-only"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "allow glob", spdx: "BSD-3-Clause", rules: LicensePolicyRules{Allow: []string{"MIT", "BSD-*"}}},
+		{name: "allow or choice", spdx: "GPL-3.0-only OR Apache-2.0", rules: LicensePolicyRules{Allow: []string{"Apache-2.0"}}},
+		{name: "allow and requires all", spdx: "MIT AND LGPL-2.1-only", rules: LicensePolicyRules{Allow: []string{"MIT"}}, denied: true, reason: licensePolicyReasonNotAllowed},
+		{name: "allow compound with form", spdx: "GPL-2.0-only WITH Classpath-exception-2.0", rules: LicensePolicyRules{Allow: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}}},
+		{name: "deny wins over allow", spdx: "MIT", rules: LicensePolicyRules{Allow: []string{"*IT"}, Deny: []string{"MIT"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "nested parentheses", spdx: "(MIT AND (ISC OR GPL-3.0-only))", rules: LicensePolicyRules{Allow: []string{"MIT", "ISC"}}},
+		{name: "malformed expression denied token", spdx: "MIT / GPL-2.0-only", rules: LicensePolicyRules{Deny: []string{"GPL-2.0-only"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "malformed expression never allowed", spdx: "MIT OR", rules: LicensePolicyRules{Allow: []string{"MIT"}}, denied: true, reason: licensePolicyReasonNotAllowed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := []DependencyReport{{Name: "dep", License: &DependencyLicense{SPDX: tc.spdx}}}
+			rules := tc.rules
+			rules.Expressions = true
+			ApplyLicensePolicyRules(deps, rules)
+			if deps[0].License.Denied != tc.denied || deps[0].License.PolicyReason != tc.reason {
+				t.Fatalf("expected denied=%t reason=%q, got %#v", tc.denied, tc.reason, deps[0].License)
+			}
+		})
+	}
+}
+
+func TestApplyLicensePolicyRulesWithoutExpressionsChecksEveryIdentifier(t *testing.T) {
+	cases := []struct {
+		name   string
+		spdx   string
+		rules  LicensePolicyRules
+		denied bool
+		reason string
+	}{
+		{name: "or choice still denied", spdx: "MIT OR GPL-2.0-only", rules: LicensePolicyRules{Deny: []string{"GPL-2.0-only"}}, denied: true, reason: licensePolicyReasonDenied},
+		{name: "no denied identifier", spdx: "MIT OR Apache-2.0", rules: LicensePolicyRules{Deny: []string{"GPL-*"}}},
+		{name: "allow requires every identifier", spdx: "GPL-3.0-only OR Apache-2.0", rules: LicensePolicyRules{Allow: []string{"Apache-2.0"}}, denied: true, reason: licensePolicyReasonNotAllowed},
+		{name: "allow covers every identifier", spdx: "MIT OR BSD-3-Clause", rules: LicensePolicyRules{Allow: []string{"MIT", "BSD-*"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := []DependencyReport{{Name: "dep", License: &DependencyLicense{SPDX: tc.spdx}}}
+			ApplyLicensePolicyRules(deps, tc.rules)
+			if deps[0].License.Denied != tc.denied || deps[0].License.PolicyReason != tc.reason {
+				t.Fatalf("expected denied=%t reason=%q, got %#v", tc.denied, tc.reason, deps[0].License)
+			}
+		})
+	}
+}
+
+func TestApplyLicensePolicyRulesUnknownPolicies(t *testing.T) {
+	newDeps := func() []DependencyReport {
+		return []DependencyReport{
+			{Name: "mystery", License: &DependencyLicense{Unknown: true, Source: "unknown"}},
+			{Name: "known", License: &DependencyLicense{SPDX: "MIT"}},
+		}
+	}
+
+	deps := newDeps()
+	if diagnostics := ApplyLicensePolicyRules(deps, LicensePolicyRules{Allow: []string{"MIT"}}); len(diagnostics) != 0 || deps[0].License.Denied {
+		t.Fatalf("expected unknown licenses allowed by default, got %#v %#v", diagnostics, deps[0].License)
+	}
+
+	deps = newDeps()
+	diagnostics := ApplyLicensePolicyRules(deps, LicensePolicyRules{Unknown: " WARN "})
+	if strings.Join(diagnostics, ";") != "unknown license requires review for mystery" || deps[0].License.Denied {
+		t.Fatalf("expected review diagnostic without denial, got %#v %#v", diagnostics, deps[0].License)
+	}
+
+	deps = newDeps()
+	ApplyLicensePolicyRules(deps, LicensePolicyRules{Unknown: LicenseUnknownDeny})
+	if !deps[0].License.Denied || deps[0].License.PolicyReason != licensePolicyReasonUnknownDeny || deps[1].License.Denied {
+		t.Fatalf("expected only unknown license denied, got %#v", deps)
+	}
+	if got := CountDeniedLicenses(deps); got != 1 {
+		t.Fatalf("expected one denied license, got %d", got)
+	}
+}
+
+func TestLicenseUnknownPolicyValidationAndPatterns(t *testing.T) {
+	for _, value := range []string{"", "allow", "WARN", " deny "} {
+		if !ValidLicenseUnknownPolicy(value) {
+			t.Fatalf("expected %q to be a valid unknown policy", value)
+		}
+	}
+	if ValidLicenseUnknownPolicy("review") {
+		t.Fatalf("did not expect review to be a valid unknown policy")
+	}
+	got := SortedLicensePatterns([]string{"bsd-*", "MIT", "mit", "*", "gpl-2.0-only with classpath-exception-2.0", "x WITH ##"})
+	if strings.Join(got, ",") != "BSD-*,GPL-2.0-ONLY WITH CLASSPATH-EXCEPTION-2.0,MIT" {
+		t.Fatalf("unexpected normalized patterns: %#v", got)
+	}
+}
+
+func TestParseSPDXExpressionRejectsMalformedInput(t *testing.T) {
+	for _, expression := range []string{"", "()", "MIT AND", "(MIT OR ISC", "MIT ISC", "OR MIT", "MIT WITH", "MIT WITH (X)", "MIT)"} {
+		if _, err := parseSPDXExpression(expression); err == nil {
+			t.Fatalf("expected %q to fail to parse", expression)
+		}
+	}
+	node, err := parseSPDXExpression("mit or (isc and apache-2.0 with llvm-exception)")
+	if err != nil {
+		t.Fatalf("parse expression: %v", err)
+	}
+	if node.operator != "OR" || len(node.operands) != 2 || node.operands[1].operator != "AND" {
+		t.Fatalf("unexpected expression tree: %#v", node)
+	}
+	if got := strings.Join(node.operands[1].operands[1].identifiers(), "|"); got != "APACHE-2.0 WITH LLVM-EXCEPTION|APACHE-2.0" {
+		t.Fatalf("unexpected WITH identifiers: %q", got)
+	}
+}
+
+func TestApplyLicenseExceptions(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	reportData := Report{Dependencies: []DependencyReport{
+		{Name: "left-pad", Language: "js-ts", License: &DependencyLicense{SPDX: "WTFPL", Denied: true}},
+		{Name: "chalk", Language: "js-ts", Identity: &DependencyIdentity{PURL: "pkg:npm/chalk@5.0.0"}, License: &DependencyLicense{SPDX: "GPL-3.0-only", Denied: true}},
+		{Name: "old", Language: "js-ts", License: &DependencyLicense{SPDX: "AGPL-3.0-only", Denied: true}},
+		{Name: "pinned", Language: "js-ts", License: &DependencyLicense{SPDX: "GPL-2.0-only", Denied: true}},
+		{Name: "allowed", Language: "js-ts", License: &DependencyLicense{SPDX: "MIT"}},
+		{Name: "dual", Language: "js-ts", License: &DependencyLicense{SPDX: "(Apache-2.0 AND GPL-3.0-only) OR GPL-2.0-only WITH Classpath-exception-2.0", Denied: true}},
+		{Name: "classpath", Language: "jvm", License: &DependencyLicense{SPDX: "MIT AND GPL-2.0-only WITH Classpath-exception-2.0", Denied: true}},
+	}}
+	exceptions := []LicenseException{
+		{Package: "left-pad", Owner: "legal", Reason: "reviewed", Expires: "2027-01-01", Source: ".lopper.yml"},
+		{Package: "chalk", Owner: "legal", Reason: "broad", Expires: "2027-01-01"},
+		{PURL: "pkg:npm/chalk@5.0.0", Owner: "platform", Reason: "vendored fork", Expires: "2027-01-01"},
+		{Package: "old", Owner: "legal", Reason: "lapsed", Expires: "2026-10-17"},
+		{Package: "pinned", License: "GPL-3.0-only", Owner: "legal", Reason: "other license", Expires: "2027-01-01"},
+		{Package: "allowed", Owner: "legal", Reason: "unused", Expires: "2027-01-01"},
+		{Package: "dual", License: "gpl-3.0-only", Owner: "legal", Reason: "compound leaf", Expires: "2027-01-01"},
+		{Package: "classpath", License: "GPL-2.0-only WITH Classpath-exception-2.0", Owner: "legal", Reason: "with leaf", Expires: "2027-01-01"},
+	}
+
+	diagnostics := ApplyLicenseExceptions(&reportData, exceptions, now)
+	deps := reportData.Dependencies
+	if deps[0].License.Denied || deps[0].License.Exception == nil || deps[0].License.Exception.Scope != "package:left-pad" || deps[0].License.Exception.Source != ".lopper.yml" {
+		t.Fatalf("expected package exception to lift denial, got %#v", deps[0].License)
+	}
+	if deps[1].License.Denied || deps[1].License.Exception == nil || deps[1].License.Exception.Owner != "platform" {
+		t.Fatalf("expected most specific purl exception, got %#v", deps[1].License)
+	}
+	if !deps[2].License.Denied || deps[2].License.Exception == nil || !deps[2].License.Exception.Expired {
+		t.Fatalf("expected expired exception to keep denial, got %#v", deps[2].License)
+	}
+	if !deps[3].License.Denied || deps[3].License.Exception != nil {
+		t.Fatalf("expected license-pinned exception not to match, got %#v", deps[3].License)
+	}
+	if deps[4].License.Exception != nil {
+		t.Fatalf("did not expect exceptions recorded on allowed licenses, got %#v", deps[4].License)
+	}
+	if deps[5].License.Denied || deps[5].License.Exception == nil || deps[5].License.Exception.Reason != "compound leaf" {
+		t.Fatalf("expected license-pinned exception to match a compound expression leaf, got %#v", deps[5].License)
+	}
+	if deps[6].License.Denied || deps[6].License.Exception == nil {
+		t.Fatalf("expected license-pinned exception to match a WITH leaf, got %#v", deps[6].License)
+	}
+	if strings.Join(diagnostics, ";") != "expired license exception restored denial for old" {
+		t.Fatalf("unexpected diagnostics: %#v", diagnostics)
+	}
+	if ApplyLicenseExceptions(nil, exceptions, now) != nil || ApplyLicenseExceptions(&reportData, nil, now) != nil {
+		t.Fatalf("expected nil diagnostics for empty inputs")
+	}
+}
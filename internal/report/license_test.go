@@ -65,17 +65,15 @@ func TestNormalizeSPDXIDAndSortedDenyList(t *testing.T) {
 	}
 }
 
-func TestSPDXExpressionContainsDeniedTokens(t *testing.T) {
-	deny := map[string]struct{}{
-		"GPL-2.0-ONLY": {},
-	}
-	if !spdxExpressionContainsDenied("(MIT OR GPL-2.0-only)", deny) {
+func TestLicenseTokensMatchDenied(t *testing.T) {
+	deny := []string{"GPL-2.0-ONLY"}
+	if !licenseTokensMatch("(MIT OR GPL-2.0-only)", deny) {
 		t.Fatalf("expected denied token match in expression")
 	}
-	if spdxExpressionContainsDenied("MIT OR Apache-2.0", deny) {
+	if licenseTokensMatch("MIT OR Apache-2.0", deny) {
 		t.Fatalf("did not expect denied token match")
 	}
-	if spdxExpressionContainsDenied("", nil) {
+	if licenseTokensMatch("", nil) {
 		t.Fatalf("did not expect denied match for empty expression/denylist")
 	}
 }
@@ -94,8 +92,7 @@ func TestLicenseAdditionalBranches(t *testing.T) {
 		t.Fatalf("expected invalid deny list to normalize to nil, got %#v", got)
 	}
 
-	deny := map[string]struct{}{"GPL-2.0-ONLY": {}}
-	if !spdxExpressionContainsDenied("MIT / GPL-2.0-only", deny) {
+	if !licenseTokensMatch("MIT / GPL-2.0-only", []string{"GPL-2.0-ONLY"}) {
 		t.Fatalf("expected denied token match after delimiter flush")
 	}
 
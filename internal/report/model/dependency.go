@@ -22,6 +22,10 @@ type DependencyReport struct {
 	Vulnerabilities        []VulnerabilityFinding  `json:"vulnerabilities,omitempty"`
 	License                *DependencyLicense      `json:"license,omitempty"`
 	Provenance             *DependencyProvenance   `json:"provenance,omitempty"`
+	// UnusedRoots lists the repo-relative adapter roots whose own analysis
+	// confirmed the dependency unused. Manifest codemods edit only the
+	// manifests of these roots.
+	UnusedRoots []string `json:"-"`
 	// SuppressedUnusedImports is conservative static and path evidence for unused findings suppressed by incomplete coverage.
 	// It must not be emitted as removal advice.
 	SuppressedUnusedImports []ImportUse `json:"-"`
@@ -82,13 +86,35 @@ type VulnerabilityException struct {
 }
 
 type DependencyLicense struct {
-	SPDX       string   `json:"spdx,omitempty"`
-	Raw        string   `json:"raw,omitempty"`
-	Source     string   `json:"source,omitempty"`
-	Confidence string   `json:"confidence,omitempty"`
-	Unknown    bool     `json:"unknown,omitempty"`
-	Denied     bool     `json:"denied,omitempty"`
-	Evidence   []string `json:"evidence,omitempty"`
+	SPDX         string                    `json:"spdx,omitempty"`
+	Raw          string                    `json:"raw,omitempty"`
+	Source       string                    `json:"source,omitempty"`
+	Confidence   string                    `json:"confidence,omitempty"`
+	Unknown      bool                      `json:"unknown,omitempty"`
+	Denied       bool                      `json:"denied,omitempty"`
+	PolicyReason string                    `json:"policyReason,omitempty"`
+	Exception    *LicenseExceptionDecision `json:"exception,omitempty"`
+	Evidence     []string                  `json:"evidence,omitempty"`
+}
+
+type LicenseExceptionDecision struct {
+	Owner   string `json:"owner,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	License string `json:"license,omitempty"`
+	Expires string `json:"expires,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Expired bool   `json:"expired,omitempty"`
+}
+
+type LicenseException struct {
+	PURL    string `json:"purl,omitempty" yaml:"purl,omitempty"`
+	Package string `json:"package,omitempty" yaml:"package,omitempty"`
+	License string `json:"license,omitempty" yaml:"license,omitempty"`
+	Owner   string `json:"owner" yaml:"owner"`
+	Reason  string `json:"reason" yaml:"reason"`
+	Expires string `json:"expires" yaml:"expires"`
+	Source  string `json:"source,omitempty" yaml:"source,omitempty"`
 }
 
 type DependencyProvenance struct {
@@ -98,10 +124,17 @@ type DependencyProvenance struct {
 }
 
 type CodemodReport struct {
-	Mode        string              `json:"mode"`
-	Suggestions []CodemodSuggestion `json:"suggestions,omitempty"`
-	Skips       []CodemodSkip       `json:"skips,omitempty"`
-	Apply       *CodemodApplyReport `json:"apply,omitempty"`
+	Mode            string                   `json:"mode"`
+	Suggestions     []CodemodSuggestion      `json:"suggestions,omitempty"`
+	Skips           []CodemodSkip            `json:"skips,omitempty"`
+	LockfileRefresh []CodemodLockfileRefresh `json:"lockfileRefresh,omitempty"`
+	Apply           *CodemodApplyReport      `json:"apply,omitempty"`
+}
+
+type CodemodLockfileRefresh struct {
+	Manifest string `json:"manifest"`
+	Manager  string `json:"manager"`
+	Command  string `json:"command"`
 }
 
 type CodemodSuggestion struct {
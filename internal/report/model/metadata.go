@@ -18,6 +18,7 @@ type BaselineComparison struct {
 	Removed                     []DependencyDelta    `json:"removed,omitempty"`
 	NewDeniedLicenses           []DeniedLicenseDelta `json:"newDeniedLicenses,omitempty"`
 	NewReachableVulnerabilities []VulnerabilityDelta `json:"newReachableVulnerabilities,omitempty"`
+	NewRuleFindings             []RuleFinding        `json:"newRuleFindings,omitempty"`
 	UnchangedRows               int                  `json:"unchangedRows,omitempty"`
 }
 
@@ -135,6 +136,9 @@ type CacheMetadata struct {
 	Hits          int                 `json:"hits"`
 	Misses        int                 `json:"misses"`
 	Writes        int                 `json:"writes"`
+	FileHits      int                 `json:"fileHits,omitempty"`
+	FileMisses    int                 `json:"fileMisses,omitempty"`
+	FileWrites    int                 `json:"fileWrites,omitempty"`
 	Invalidations []CacheInvalidation `json:"invalidations,omitempty"`
 }
 
@@ -151,6 +155,17 @@ type EffectiveThresholds struct {
 	ReachableVulnerabilityPriority    string `json:"reachableVulnerabilityPriority,omitempty"`
 }
 
+// GateResult records one CI gate: its configured threshold, the value
+// observed in this run, and the exit code used when it fails.
+type GateResult struct {
+	Gate      string `json:"gate"`
+	Status    string `json:"status"`
+	Threshold string `json:"threshold,omitempty"`
+	Observed  string `json:"observed,omitempty"`
+	ExitCode  int    `json:"exitCode"`
+	Detail    string `json:"detail,omitempty"`
+}
+
 type EffectivePolicy struct {
 	Sources                 []string                `json:"sources,omitempty"`
 	MergeTrace              []PolicyMergeTrace      `json:"mergeTrace,omitempty"`
@@ -158,6 +173,29 @@ type EffectivePolicy struct {
 	RemovalCandidateWeights RemovalCandidateWeights `json:"removalCandidateWeights"`
 	License                 LicensePolicy           `json:"license"`
 	Vulnerabilities         VulnerabilityPolicy     `json:"vulnerabilities,omitempty"`
+	Rules                   []PolicyRule            `json:"rules,omitempty"`
+}
+
+// PolicyRule is a policy-as-code rule from the rules section of .lopper.yml.
+// Expr is a boolean rule expression evaluated once per dependency, or once
+// per report when Scope is "report"; the rule matches when it is true.
+type PolicyRule struct {
+	ID       string `json:"id" yaml:"id"`
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+	Scope    string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Expr     string `json:"expr" yaml:"expr"`
+	Source   string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// RuleFinding is one match of a PolicyRule. Dependency-scoped findings name
+// the dependency they matched.
+type RuleFinding struct {
+	RuleID     string `json:"ruleId"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Language   string `json:"language,omitempty"`
+	Dependency string `json:"dependency,omitempty"`
 }
 
 type PolicyMergeTrace struct {
@@ -178,9 +216,12 @@ type Summary struct {
 }
 
 type LicensePolicy struct {
-	Deny                      []string `json:"deny,omitempty"`
-	FailOnDenied              bool     `json:"failOnDenied"`
-	IncludeRegistryProvenance bool     `json:"includeRegistryProvenance"`
+	Deny                      []string           `json:"deny,omitempty"`
+	Allow                     []string           `json:"allow,omitempty"`
+	Unknown                   string             `json:"unknown,omitempty"`
+	Exceptions                []LicenseException `json:"exceptions,omitempty"`
+	FailOnDenied              bool               `json:"failOnDenied"`
+	IncludeRegistryProvenance bool               `json:"includeRegistryProvenance"`
 }
 
 type VulnerabilityPolicy struct {
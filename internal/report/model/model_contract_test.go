@@ -69,9 +69,11 @@ func TestReportJSONContractShape(t *testing.T) {
 		"dependencies",
 		"effectivePolicy",
 		"effectiveThresholds",
+		"gateResults",
 		"generatedAt",
 		"languageBreakdown",
 		"repoPath",
+		"ruleFindings",
 		"schemaVersion",
 		"scope",
 		"summary",
@@ -135,6 +137,7 @@ func TestReportJSONContractShape(t *testing.T) {
 		"dependencies",
 		"newDeniedLicenses",
 		"newReachableVulnerabilities",
+		"newRuleFindings",
 		"regressions",
 		"runtimeRegressions",
 		"summaryDelta",
@@ -216,6 +219,7 @@ func representativeReport() Report {
 	baselineLoadCount := 2
 	currentLoadCount := 4
 	loadCountDelta := 2
+	finding := RuleFinding{RuleID: "tiny-js-deps", Severity: "warning", Message: "barely used", Language: "js", Dependency: "lodash"}
 	delta := DependencyDelta{
 		Kind:                      DependencyDeltaChanged,
 		Language:                  "js",
@@ -409,6 +413,7 @@ func representativeReport() Report {
 			Thresholds:              EffectiveThresholds{FailOnIncreasePercent: 5},
 			RemovalCandidateWeights: RemovalCandidateWeights{Usage: 0.5, Impact: 0.3, Confidence: 0.2},
 			License:                 LicensePolicy{Deny: []string{"GPL-3.0"}, FailOnDenied: true, IncludeRegistryProvenance: true},
+			Rules:                   []PolicyRule{{ID: "tiny-js-deps", Severity: "warning", Message: "barely used", Scope: "dependency", Expr: "dependency.usedPercent < 5", Source: ".lopper.yml"}},
 		},
 		Warnings:             []string{"dynamic import detected"},
 		WasteIncreasePercent: &wasteIncreasePercent,
@@ -441,8 +446,11 @@ func representativeReport() Report {
 				PriorityScore: 95,
 				Evidence:      []string{"version_match: package matched but installed version could not be evaluated"},
 			}},
-			UnchangedRows: 1,
+			NewRuleFindings: []RuleFinding{finding},
+			UnchangedRows:   1,
 		},
+		RuleFindings: []RuleFinding{finding},
+		GateResults:  []GateResult{{Gate: "fail-on-increase", Status: "fail", Threshold: "5%", Observed: "12.5%", ExitCode: 10}},
 	}
 }
 
@@ -19,4 +19,6 @@ type Report struct {
 	Warnings             []string             `json:"warnings,omitempty"`
 	WasteIncreasePercent *float64             `json:"wasteIncreasePercent,omitempty"`
 	BaselineComparison   *BaselineComparison  `json:"baselineComparison,omitempty"`
+	RuleFindings         []RuleFinding        `json:"ruleFindings,omitempty"`
+	GateResults          []GateResult         `json:"gateResults,omitempty"`
 }
@@ -10,10 +10,13 @@ type Result = model.Report
 type DependencyReport = model.DependencyReport
 type DependencyIdentity = model.DependencyIdentity
 type DependencyLicense = model.DependencyLicense
+type LicenseExceptionDecision = model.LicenseExceptionDecision
+type LicenseException = model.LicenseException
 type DependencyProvenance = model.DependencyProvenance
 type CodemodReport = model.CodemodReport
 type CodemodSuggestion = model.CodemodSuggestion
 type CodemodSkip = model.CodemodSkip
+type CodemodLockfileRefresh = model.CodemodLockfileRefresh
 type CodemodApplyReport = model.CodemodApplyReport
 type CodemodApplyResult = model.CodemodApplyResult
 type RemovalCandidate = model.RemovalCandidate
@@ -55,6 +58,9 @@ type CacheMetadata = model.CacheMetadata
 type CacheInvalidation = model.CacheInvalidation
 type EffectiveThresholds = model.EffectiveThresholds
 type EffectivePolicy = model.EffectivePolicy
+type GateResult = model.GateResult
+type PolicyRule = model.PolicyRule
+type RuleFinding = model.RuleFinding
 type PolicyMergeTrace = model.PolicyMergeTrace
 type Summary = model.Summary
 type LicensePolicy = model.LicensePolicy
@@ -9,14 +9,19 @@ import (
 type Format string
 
 const (
-	FormatTable     Format = "table"
-	FormatCSV       Format = "csv"
-	FormatJSON      Format = "json"
-	FormatSARIF     Format = "sarif"
-	FormatPRComment Format = "pr-comment"
-	FormatCycloneDX Format = "cyclonedx-json"
-	FormatSPDX      Format = "spdx-json"
-	FormatVEX       Format = "cyclonedx-vex-json"
+	FormatTable       Format = "table"
+	FormatCSV         Format = "csv"
+	FormatJSON        Format = "json"
+	FormatNDJSON      Format = "ndjson"
+	FormatHTML        Format = "html"
+	FormatSARIF       Format = "sarif"
+	FormatPRComment   Format = "pr-comment"
+	FormatCycloneDX   Format = "cyclonedx-json"
+	FormatSPDX        Format = "spdx-json"
+	FormatVEX         Format = "cyclonedx-vex-json"
+	FormatJUnit       Format = "junit"
+	FormatCodeQuality Format = "gitlab-codequality"
+	FormatTemplate    Format = "template"
 )
 
 const SBOMAttestationExportsPreviewFeature = "sbom-attestation-exports-preview"
@@ -37,6 +42,10 @@ func ParseFormat(value string) (Format, error) {
 		return FormatCSV, nil
 	case string(FormatJSON):
 		return FormatJSON, nil
+	case string(FormatNDJSON):
+		return FormatNDJSON, nil
+	case string(FormatHTML):
+		return FormatHTML, nil
 	case string(FormatSARIF):
 		return FormatSARIF, nil
 	case string(FormatPRComment):
@@ -47,6 +56,12 @@ func ParseFormat(value string) (Format, error) {
 		return FormatSPDX, nil
 	case string(FormatVEX):
 		return FormatVEX, nil
+	case string(FormatJUnit):
+		return FormatJUnit, nil
+	case string(FormatCodeQuality):
+		return FormatCodeQuality, nil
+	case string(FormatTemplate):
+		return FormatTemplate, nil
 	default:
 		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, value)
 	}
@@ -0,0 +1,110 @@
+// Package ruleexpr evaluates lopper's policy rule expressions, which are CEL
+// (https://cel.dev) expressions over JSON-shaped values.
+//
+// Declared variables are dynamically typed, so field typos surface when an
+// expression is evaluated rather than compiled. Values have the shapes
+// encoding/json produces: null, bool, double, string, list, and map. Numbers
+// from a report are therefore doubles; they compare with int literals, but
+// arithmetic needs double operands.
+//
+// Expressions cannot touch the filesystem, the network, the clock, or the
+// environment. Every evaluation runs under a cost limit, and parsing rejects
+// long or deeply nested expressions.
+package ruleexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/ext"
+	"cel.dev/cel-go/interpreter"
+)
+
+const (
+	maxExpressionLength = 4096
+	maxNestingDepth     = 64
+	maxEvaluationCost   = 1_000_000
+)
+
+// ErrEvaluationBudget reports an evaluation stopped at its cost limit.
+var ErrEvaluationBudget = errors.New("expression exceeded its evaluation budget")
+
+// Program is a compiled expression.
+type Program struct {
+	source  string
+	program cel.Program
+}
+
+// Compile parses and checks source. Identifiers other than the given
+// variables and macro variables are rejected, as are expressions that cannot
+// produce a bool.
+func Compile(source string, variables ...string) (*Program, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	options := []cel.EnvOption{
+		ext.Strings(),
+		cel.CrossTypeNumericComparisons(true),
+		cel.ParserExpressionSizeLimit(maxExpressionLength),
+		cel.ParserRecursionLimit(maxNestingDepth),
+	}
+	for _, variable := range variables {
+		options = append(options, cel.Variable(variable, cel.DynType))
+	}
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return nil, err
+	}
+	checked, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if output := checked.OutputType(); !output.IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("expression produces %s, not bool", output)
+	}
+	program, err := env.Program(checked, cel.CostLimit(maxEvaluationCost))
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: source, program: program}, nil
+}
+
+func (p *Program) String() string {
+	return p.source
+}
+
+// EvalBool evaluates the program with variables bound to JSON-shaped values,
+// as returned by Value.
+func (p *Program) EvalBool(variables map[string]any) (bool, error) {
+	value, _, err := p.program.Eval(variables)
+	if err != nil {
+		var cancelled interpreter.EvalCancelledError
+		if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+			return false, ErrEvaluationBudget
+		}
+		return false, err
+	}
+	result, ok := value.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression produced %s, not bool", value.Type())
+	}
+	return result, nil
+}
+
+// Value converts a Go value to its JSON shape, so expressions address fields
+// by their JSON names.
+func Value(value any) (any, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var shaped any
+	if err := json.Unmarshal(payload, &shaped); err != nil {
+		return nil, err
+	}
+	return shaped, nil
+}
@@ -0,0 +1,123 @@
+package ruleexpr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testVariables(t *testing.T) map[string]any {
+	t.Helper()
+	dependency, err := Value(map[string]any{
+		"name":                 "lodash",
+		"language":             "js-ts",
+		"usedPercent":          3.5,
+		"estimatedUnusedBytes": 204800,
+		"riskCues": []map[string]any{
+			{"code": "dynamic-require", "severity": "medium"},
+			{"code": "native-module", "severity": "critical"},
+		},
+		"runtimeUsage": map[string]any{"runtimeOnly": true},
+	})
+	if err != nil {
+		t.Fatalf("shape dependency: %v", err)
+	}
+	return map[string]any{"dependency": dependency}
+}
+
+func TestEvalBool(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: `dependency.language == "js-ts" && dependency.usedPercent < 5 && dependency.estimatedUnusedBytes > 100 * 1024`, want: true},
+		{expression: `dependency.runtimeUsage.runtimeOnly == true && dependency.riskCues.exists(c, c.severity == "critical")`, want: true},
+		{expression: `dependency.riskCues.all(c, c.severity in ["low", "medium"])`, want: false},
+		{expression: `dependency.riskCues.exists_one(c, c.code.startsWith("native"))`, want: true},
+		{expression: `size(dependency.riskCues.filter(c, c.severity == "medium")) == 1`, want: true},
+		{expression: `dependency.riskCues.map(c, c.code) == ["dynamic-require", "native-module"]`, want: true},
+		{expression: `has(dependency.vulnerabilities) && dependency.vulnerabilities.exists(v, v.reachable)`, want: false},
+		{expression: `!has(dependency.license) && "runtimeOnly" in dependency.runtimeUsage`, want: true},
+		{expression: `dependency.name.matches("^lod") && dependency.name.contains("das") && dependency.name.endsWith("sh")`, want: true},
+		{expression: `dependency.name.upperAscii().lowerAscii() + "!" == "lodash!"`, want: true},
+		{expression: `dependency.riskCues[1].code == "native-module" && dependency["name"] == "lodash"`, want: true},
+		{expression: `dependency.usedPercent > 50.0 ? false : int(dependency.usedPercent) == 3`, want: true},
+		{expression: `double("2.5") * 2.0 == 5.0 && string(7) == "7" && dependency.estimatedUnusedBytes == 204800`, want: true},
+		{expression: `dependency.missing > 1 || true`, want: true},
+		{expression: `false && dependency.missing > 1`, want: false},
+	}
+	variables := testVariables(t)
+	for _, tc := range cases {
+		program, err := Compile(tc.expression, "dependency")
+		if err != nil {
+			t.Fatalf("compile %q: %v", tc.expression, err)
+		}
+		got, err := program.EvalBool(variables)
+		if err != nil {
+			t.Fatalf("eval %q: %v", tc.expression, err)
+		}
+		if got != tc.want {
+			t.Fatalf("eval %q: want %v, got %v", tc.expression, tc.want, got)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := map[string]string{
+		``:                       "empty",
+		`report.name == "x"`:     "undeclared reference to 'report'",
+		`now()`:                  "undeclared reference to 'now'",
+		`dependency.name == "x`:  "Syntax error",
+		`dependency.usedPercent`: "",
+		`1 + 2`:                  "produces int, not bool",
+		strings.Repeat("(", 80) + "true" + strings.Repeat(")", 80): "recursion",
+		strings.Repeat("a", maxExpressionLength+1):                 "size",
+	}
+	for expression, want := range cases {
+		_, err := Compile(expression, "dependency")
+		if want == "" {
+			if err != nil {
+				t.Fatalf("compile %q: expected dynamic expression to compile, got %v", expression, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("compile %q: expected error containing %q, got %v", expression, want, err)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	cases := map[string]string{
+		`dependency.usedPercent > "5"`:      "no such overload",
+		`dependency.missing < 1`:            "no such key",
+		`dependency.usedPercent * 2 > 1.0`:  "no such overload",
+		`dependency.riskCues[5].code == ""`: "index out of bounds",
+		`dependency.name.matches("(")`:      "missing closing )",
+		`dependency.usedPercent`:            "not bool",
+	}
+	variables := testVariables(t)
+	for expression, want := range cases {
+		program, err := Compile(expression, "dependency")
+		if err != nil {
+			t.Fatalf("compile %q: %v", expression, err)
+		}
+		if _, err := program.EvalBool(variables); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("eval %q: expected error containing %q, got %v", expression, want, err)
+		}
+	}
+}
+
+func TestEvalBudget(t *testing.T) {
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = float64(i)
+	}
+	program, err := Compile(`items.all(a, items.all(b, items.all(c, a + b + c >= 0.0)))`, "items")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if _, err := program.EvalBool(map[string]any{"items": items}); !errors.Is(err, ErrEvaluationBudget) {
+		t.Fatalf("expected evaluation budget error, got %v", err)
+	}
+}
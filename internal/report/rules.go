@@ -0,0 +1,200 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ben-ranford/lopper/internal/report/ruleexpr"
+)
+
+const PolicyRulesPreviewFeature = "policy-rules-preview"
+
+const (
+	RuleSeverityError   = "error"
+	RuleSeverityWarning = "warning"
+	RuleSeverityNote    = "note"
+
+	RuleScopeDependency = "dependency"
+	RuleScopeReport     = "report"
+)
+
+// Variables visible to rule expressions. Dependency rules see the dependency
+// being checked and the whole report; report rules see only the report.
+const (
+	ruleDependencyVariable = "dependency"
+	ruleReportVariable     = "report"
+)
+
+var policyRuleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// NormalizePolicyRule trims rule fields, applies the default dependency
+// scope, and checks that the expression compiles.
+func NormalizePolicyRule(rule PolicyRule) (PolicyRule, error) {
+	normalized := PolicyRule{
+		ID:       strings.TrimSpace(rule.ID),
+		Severity: strings.ToLower(strings.TrimSpace(rule.Severity)),
+		Message:  strings.TrimSpace(rule.Message),
+		Scope:    strings.ToLower(strings.TrimSpace(rule.Scope)),
+		Expr:     strings.TrimSpace(rule.Expr),
+		Source:   strings.TrimSpace(rule.Source),
+	}
+	if normalized.Scope == "" {
+		normalized.Scope = RuleScopeDependency
+	}
+	switch {
+	case normalized.ID == "":
+		return PolicyRule{}, fmt.Errorf("id is required")
+	case !policyRuleIDPattern.MatchString(normalized.ID):
+		return PolicyRule{}, fmt.Errorf("id %q may only contain letters, digits, '.', '_', and '-'", normalized.ID)
+	case normalized.Message == "":
+		return PolicyRule{}, fmt.Errorf("message is required")
+	case normalized.Expr == "":
+		return PolicyRule{}, fmt.Errorf("expr is required")
+	}
+	switch normalized.Severity {
+	case RuleSeverityError, RuleSeverityWarning, RuleSeverityNote:
+	default:
+		return PolicyRule{}, fmt.Errorf("severity must be one of error, warning, note: %q", rule.Severity)
+	}
+	if _, err := compilePolicyRule(normalized); err != nil {
+		return PolicyRule{}, err
+	}
+	return normalized, nil
+}
+
+func compilePolicyRule(rule PolicyRule) (*ruleexpr.Program, error) {
+	var program *ruleexpr.Program
+	var err error
+	switch rule.Scope {
+	case RuleScopeDependency:
+		program, err = ruleexpr.Compile(rule.Expr, ruleDependencyVariable, ruleReportVariable)
+	case RuleScopeReport:
+		program, err = ruleexpr.Compile(rule.Expr, ruleReportVariable)
+	default:
+		return nil, fmt.Errorf("scope must be dependency or report: %q", rule.Scope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expr: %w", err)
+	}
+	return program, nil
+}
+
+// EvaluatePolicyRules runs rules against rep and returns their findings in
+// rule order. A rule that fails to evaluate for some dependency produces one
+// warning and no finding for that dependency, so a single bad field never
+// fails the run on its own.
+func EvaluatePolicyRules(rep Report, rules []PolicyRule) ([]RuleFinding, []string, error) {
+	if len(rules) == 0 {
+		return nil, nil, nil
+	}
+	reportValue, err := ruleexpr.Value(rep)
+	if err != nil {
+		return nil, nil, fmt.Errorf("prepare policy rule input: %w", err)
+	}
+	dependencyValues := make([]any, 0, len(rep.Dependencies))
+	for _, dep := range rep.Dependencies {
+		value, err := ruleexpr.Value(dep)
+		if err != nil {
+			return nil, nil, fmt.Errorf("prepare policy rule input: %w", err)
+		}
+		dependencyValues = append(dependencyValues, value)
+	}
+
+	findings := make([]RuleFinding, 0)
+	warnings := make([]string, 0)
+	for _, rule := range rules {
+		program, err := compilePolicyRule(rule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("policy rule %s: %w", rule.ID, err)
+		}
+		if rule.Scope == RuleScopeReport {
+			matched, err := program.EvalBool(map[string]any{ruleReportVariable: reportValue})
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("policy rule %s could not be evaluated: %v", rule.ID, err))
+				continue
+			}
+			if matched {
+				findings = append(findings, RuleFinding{RuleID: rule.ID, Severity: rule.Severity, Message: rule.Message})
+			}
+			continue
+		}
+
+		failures := 0
+		var firstFailure string
+		for index, dep := range rep.Dependencies {
+			matched, err := program.EvalBool(map[string]any{ruleDependencyVariable: dependencyValues[index], ruleReportVariable: reportValue})
+			if err != nil {
+				if failures == 0 {
+					firstFailure = fmt.Sprintf("%s: %v", dep.Name, err)
+				}
+				failures++
+				continue
+			}
+			if matched {
+				findings = append(findings, RuleFinding{RuleID: rule.ID, Severity: rule.Severity, Message: rule.Message, Language: dep.Language, Dependency: dep.Name})
+			}
+		}
+		if failures > 0 {
+			warnings = append(warnings, fmt.Sprintf("policy rule %s could not be evaluated for %d dependencies (first: %s)", rule.ID, failures, firstFailure))
+		}
+	}
+	return findings, warnings, nil
+}
+
+// HasPolicyRuleBreach reports whether rep has an error-severity rule finding.
+// With a baseline comparison only findings new since the baseline count.
+func HasPolicyRuleBreach(rep Report) bool {
+	return countPolicyRuleBreaches(rep) > 0
+}
+
+func countPolicyRuleBreaches(rep Report) int {
+	findings := rep.RuleFindings
+	if rep.BaselineComparison != nil {
+		findings = rep.BaselineComparison.NewRuleFindings
+	}
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == RuleSeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// newRuleFindings returns the current findings that the baseline did not
+// have. Findings are matched by rule, language, and dependency; repeated
+// matches are counted so a second instance of the same finding is still new.
+func newRuleFindings(current, baseline []RuleFinding) []RuleFinding {
+	if len(current) == 0 {
+		return nil
+	}
+	remaining := make(map[string]int, len(baseline))
+	for _, finding := range baseline {
+		remaining[ruleFindingKey(finding)]++
+	}
+	added := make([]RuleFinding, 0)
+	for _, finding := range current {
+		key := ruleFindingKey(finding)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		added = append(added, finding)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	return added
+}
+
+func ruleFindingKey(finding RuleFinding) string {
+	return strings.Join([]string{finding.RuleID, finding.Language, finding.Dependency}, "\x00")
+}
+
+func policyRuleCount(rep Report) int {
+	if rep.EffectivePolicy == nil {
+		return 0
+	}
+	return len(rep.EffectivePolicy.Rules)
+}
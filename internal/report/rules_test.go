@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func samplePolicyRuleReport() Report {
+	return Report{
+		RepoPath: ".",
+		Dependencies: []DependencyReport{
+			{Language: "js-ts", Name: "lodash", UsedPercent: 3, EstimatedUnusedBytes: 200 * 1024},
+			{
+				Language:     "js-ts",
+				Name:         "native-addon",
+				UsedPercent:  40,
+				RuntimeUsage: &RuntimeUsage{LoadCount: 2, RuntimeOnly: true},
+				RiskCues:     []RiskCue{{Code: "native-module", Severity: "critical", Message: "loads native code"}},
+			},
+			{Language: "python", Name: "requests", UsedPercent: 2, EstimatedUnusedBytes: 300 * 1024},
+		},
+	}
+}
+
+func mustNormalizePolicyRules(t *testing.T, rules ...PolicyRule) []PolicyRule {
+	t.Helper()
+	normalized := make([]PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		value, err := NormalizePolicyRule(rule)
+		if err != nil {
+			t.Fatalf("normalize rule %s: %v", rule.ID, err)
+		}
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
+
+func TestEvaluatePolicyRules(t *testing.T) {
+	rules := mustNormalizePolicyRules(t,
+		PolicyRule{ID: "runtime-critical-risk", Severity: "error", Message: "runtime-only dependency has a critical risk cue", Expr: `has(dependency.runtimeUsage) && dependency.runtimeUsage.runtimeOnly && has(dependency.riskCues) && dependency.riskCues.exists(c, c.severity == "critical")`},
+		PolicyRule{ID: "js-low-usage-weight", Severity: "warning", Message: "low-usage JS dependency adds more than 100 KB", Expr: `dependency.language == "js-ts" && dependency.usedPercent < 5 && dependency.estimatedUnusedBytes > 100 * 1024`},
+		PolicyRule{ID: "dependency-count", Severity: "note", Scope: "report", Message: "more than two dependencies", Expr: `size(report.dependencies) > 2`},
+		PolicyRule{ID: "broken", Severity: "error", Message: "never matches", Expr: `dependency.usedPercent > dependency.name`},
+	)
+
+	findings, warnings, err := EvaluatePolicyRules(samplePolicyRuleReport(), rules)
+	if err != nil {
+		t.Fatalf("evaluate rules: %v", err)
+	}
+	got := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		got = append(got, finding.RuleID+":"+finding.Severity+":"+finding.Dependency)
+	}
+	want := "runtime-critical-risk:error:native-addon,js-low-usage-weight:warning:lodash,dependency-count:note:"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected findings %q", strings.Join(got, ","))
+	}
+	if findings[0].Language != "js-ts" || findings[0].Message != "runtime-only dependency has a critical risk cue" {
+		t.Fatalf("unexpected finding details %#v", findings[0])
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "policy rule broken could not be evaluated for 3 dependencies (first: lodash:") {
+		t.Fatalf("expected one aggregated evaluation warning, got %#v", warnings)
+	}
+}
+
+func TestEvaluatePolicyRulesWithoutRules(t *testing.T) {
+	findings, warnings, err := EvaluatePolicyRules(samplePolicyRuleReport(), nil)
+	if err != nil || findings != nil || warnings != nil {
+		t.Fatalf("expected no work without rules, got %#v %#v %v", findings, warnings, err)
+	}
+}
+
+func TestNormalizePolicyRule(t *testing.T) {
+	rule, err := NormalizePolicyRule(PolicyRule{ID: " r1 ", Severity: " ERROR ", Message: " m ", Expr: " true "})
+	if err != nil {
+		t.Fatalf("normalize rule: %v", err)
+	}
+	if rule.ID != "r1" || rule.Severity != RuleSeverityError || rule.Scope != RuleScopeDependency || rule.Expr != "true" {
+		t.Fatalf("unexpected normalized rule %#v", rule)
+	}
+	cases := map[string]PolicyRule{
+		"id is required":          {Severity: "error", Message: "m", Expr: "true"},
+		"may only contain":        {ID: "a b", Severity: "error", Message: "m", Expr: "true"},
+		"message is required":     {ID: "r", Severity: "error", Expr: "true"},
+		"expr is required":        {ID: "r", Severity: "error", Message: "m"},
+		"severity must be one of": {ID: "r", Severity: "info", Message: "m", Expr: "true"},
+		"scope must be":           {ID: "r", Severity: "error", Message: "m", Scope: "module", Expr: "true"},
+		"undeclared reference":    {ID: "r", Severity: "error", Message: "m", Scope: "report", Expr: "dependency.usedPercent < 5"},
+	}
+	for want, rule := range cases {
+		if _, err := NormalizePolicyRule(rule); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestPolicyRuleFindingsTrackedAcrossBaseline(t *testing.T) {
+	finding := RuleFinding{RuleID: "runtime-critical-risk", Severity: RuleSeverityError, Message: "m", Language: "js-ts", Dependency: "native-addon"}
+	note := RuleFinding{RuleID: "dependency-count", Severity: RuleSeverityNote, Message: "n"}
+	baseline := samplePolicyRuleReport()
+	baseline.RuleFindings = []RuleFinding{finding}
+	current := samplePolicyRuleReport()
+	current.RuleFindings = []RuleFinding{finding, note}
+	current.EffectivePolicy = &EffectivePolicy{Rules: []PolicyRule{{ID: "runtime-critical-risk"}, {ID: "dependency-count"}}}
+
+	if !HasPolicyRuleBreach(current) {
+		t.Fatalf("expected error finding to breach without a baseline")
+	}
+	comparison := ComputeBaselineComparison(current, baseline)
+	if len(comparison.NewRuleFindings) != 1 || comparison.NewRuleFindings[0] != note {
+		t.Fatalf("expected only the note to be new, got %#v", comparison.NewRuleFindings)
+	}
+	current.BaselineComparison = &comparison
+	if HasPolicyRuleBreach(current) {
+		t.Fatalf("expected findings already in the baseline not to breach")
+	}
+	gate := policyRulesGate(current)
+	if gate.Status != GateStatusPass || gate.Observed != "0" || gate.ExitCode != ExitCodePolicyRules {
+		t.Fatalf("unexpected policy rules gate %#v", gate)
+	}
+
+	current.RuleFindings = append(current.RuleFindings, finding)
+	comparison = ComputeBaselineComparison(current, baseline)
+	current.BaselineComparison = &comparison
+	if gate := policyRulesGate(current); gate.Status != GateStatusFail || gate.Observed != "1" {
+		t.Fatalf("expected a second instance of a baseline finding to fail, got %#v", gate)
+	}
+}
+
+func TestFormatSARIFIncludesRuleFindings(t *testing.T) {
+	rep := samplePolicyRuleReport()
+	rep.Dependencies[1].UsedImports = []ImportUse{{Name: "addon", Module: "native-addon", Locations: []Location{{File: "src/index.js", Line: 3}}}}
+	rep.RuleFindings = []RuleFinding{
+		{RuleID: "runtime-critical-risk", Severity: RuleSeverityError, Message: "runtime-only dependency has a critical risk cue", Language: "js-ts", Dependency: "native-addon"},
+		{RuleID: "dependency-count", Severity: RuleSeverityNote, Message: "more than two dependencies"},
+	}
+	output, err := NewFormatter().Format(rep, FormatSARIF)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+	var decoded sarifLog
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("decode sarif: %v", err)
+	}
+	results := map[string]sarifResult{}
+	for _, result := range decoded.Runs[0].Results {
+		results[result.RuleID] = result
+	}
+	runtime, ok := results["lopper/policy/runtime-critical-risk"]
+	if !ok || runtime.Level != "error" || runtime.Message.Text != "native-addon: runtime-only dependency has a critical risk cue" {
+		t.Fatalf("unexpected runtime rule result %#v", runtime)
+	}
+	if len(runtime.Locations) != 1 || runtime.Locations[0].PhysicalLocation.ArtifactLocation.URI != "src/index.js" {
+		t.Fatalf("expected runtime rule result anchored on the import, got %#v", runtime.Locations)
+	}
+	count, ok := results["lopper/policy/dependency-count"]
+	if !ok || count.Level != "note" || len(count.Locations) != 0 {
+		t.Fatalf("unexpected report rule result %#v", count)
+	}
+}
@@ -53,6 +53,7 @@ func buildSARIFResults(rep Report, rules *sarifRuleBuilder) []sarifResult {
 	}
 
 	appendWasteIncreaseResult(&results, rules, rep.WasteIncreasePercent, rep.BaselineComparison)
+	results = appendRuleFindingResults(results, rules, rep)
 	sortSARIFResults(results)
 
 	return results
@@ -202,6 +203,50 @@ func appendWasteIncreaseResult(results *[]sarifResult, rules *sarifRuleBuilder,
 	})
 }
 
+// appendRuleFindingResults emits one result per policy rule finding under
+// lopper/policy/<rule id>, anchored on the matched dependency when it has a
+// location.
+func appendRuleFindingResults(results []sarifResult, rules *sarifRuleBuilder, rep Report) []sarifResult {
+	anchors := make(map[string]*sarifLocation, len(rep.Dependencies))
+	for _, dep := range rep.Dependencies {
+		key := dep.Language + "\x00" + dep.Name
+		if _, ok := anchors[key]; !ok {
+			anchors[key] = dependencyAnchorLocation(dep)
+		}
+	}
+	for _, finding := range rep.RuleFindings {
+		ruleID := "lopper/policy/" + normalizeRuleToken(finding.RuleID)
+		rules.add(sarifRule{
+			ID:               ruleID,
+			Name:             finding.RuleID,
+			ShortDescription: sarifMessage{Text: finding.Message},
+			Help:             &sarifMessage{Text: "Policy rule from the rules section of the Lopper configuration."},
+			Properties: map[string]any{
+				"category": "policy",
+				"severity": finding.Severity,
+			},
+		})
+		message := finding.Message
+		properties := map[string]any{"ruleId": finding.RuleID}
+		if finding.Dependency != "" {
+			message = finding.Dependency + ": " + message
+			properties["dependency"] = finding.Dependency
+			properties["language"] = finding.Language
+		}
+		result := sarifResult{
+			RuleID:     ruleID,
+			Level:      finding.Severity,
+			Message:    sarifMessage{Text: message},
+			Properties: properties,
+		}
+		if anchor := anchors[finding.Language+"\x00"+finding.Dependency]; anchor != nil {
+			result.Locations = []sarifLocation{*anchor}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 func sortSARIFResults(results []sarifResult) {
 	sort.SliceStable(results, func(i, j int) bool {
 		if results[i].RuleID != results[j].RuleID {
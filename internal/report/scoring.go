@@ -19,6 +19,26 @@ const (
 	removalCandidateImpactSaturation      = 100.0
 )
 
+var unusedDependencyRecommendationCodes = map[string]struct{}{
+	"remove-unused-dependency": {},
+	"remove-unused-gem":        {},
+	"remove-unused-module":     {},
+}
+
+// ConfirmedUnused reports whether an adapter recommended removing dep as
+// unused and its usage evidence is complete.
+func ConfirmedUnused(dep DependencyReport) bool {
+	if dep.UsageIncomplete {
+		return false
+	}
+	for _, recommendation := range dep.Recommendations {
+		if _, ok := unusedDependencyRecommendationCodes[recommendation.Code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func AnnotateRemovalCandidateScores(dependencies []DependencyReport) {
 	AnnotateRemovalCandidateScoresWithWeights(dependencies, DefaultRemovalCandidateWeights())
 }
@@ -257,8 +257,18 @@ func usageUncertaintyConfidenceSignal(usage *UsageUncertainty) evaluatedReachabi
 	}
 }
 
+// dynamicLoaderRiskCodes are cues for dependencies that are loaded by name,
+// by service discovery, or by the compiler rather than through imports.
+var dynamicLoaderRiskCodes = []string{
+	"dynamic-loader",
+	"annotation-processor",
+	"service-provider",
+	"reflective-class-reference",
+	"jdbc-driver-url",
+}
+
 func dynamicLoaderConfidenceSignal(cues []RiskCue) evaluatedReachabilitySignal {
-	if hasRiskCode(cues, "dynamic-loader") {
+	if hasAnyRiskCode(cues, dynamicLoaderRiskCodes) {
 		return evaluatedReachabilitySignal{
 			signal: ReachabilitySignal{
 				Code:      confidenceReasonDependencyDynamicLoader,
@@ -352,6 +362,15 @@ func hasRiskCode(cues []RiskCue, code string) bool {
 	return false
 }
 
+func hasAnyRiskCode(cues []RiskCue, codes []string) bool {
+	for _, code := range codes {
+		if hasRiskCode(cues, code) {
+			return true
+		}
+	}
+	return false
+}
+
 func highestRiskSeverity(cues []RiskCue) string {
 	highest := ""
 	weight := 0
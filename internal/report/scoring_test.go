@@ -215,6 +215,18 @@ func TestDependencyConfidenceSignalSeverityBranches(t *testing.T) {
 	}
 }
 
+func TestDynamicLoaderConfidenceSignalCoversRuntimeLoadingCues(t *testing.T) {
+	for _, code := range []string{"dynamic-loader", "annotation-processor", "service-provider", "reflective-class-reference", "jdbc-driver-url"} {
+		signal := dynamicLoaderConfidenceSignal([]RiskCue{{Code: code, Severity: "medium"}})
+		if signal.signal.Code != confidenceReasonDependencyDynamicLoader || signal.signal.Score != 35 {
+			t.Fatalf("expected %s to lower dynamic-loader confidence, got %#v", code, signal)
+		}
+	}
+	if signal := dynamicLoaderConfidenceSignal([]RiskCue{{Code: "wildcard-import"}}); signal.signal.Code != confidenceReasonEntryPointsStatic {
+		t.Fatalf("expected unrelated cues to keep static entrypoints, got %#v", signal)
+	}
+}
+
 func TestReachabilityConfidenceRuntimeOrdering(t *testing.T) {
 	dep := DependencyReport{
 		Name:              "dep",
@@ -811,6 +811,50 @@ func ValidVulnerabilityPriorityThreshold(value string) bool {
 	}
 }
 
+// HasReachableVulnerabilityAtOrAbove reports whether rep breaches the
+// reachable vulnerability threshold. With a baseline comparison only newly
+// reachable findings count; otherwise every reachable, unsuppressed finding
+// does. Findings whose affected range could not be evaluated always count.
+func HasReachableVulnerabilityAtOrAbove(rep Report, threshold string) bool {
+	return countReachableVulnerabilitiesAtOrAbove(rep, threshold) > 0
+}
+
+func countReachableVulnerabilitiesAtOrAbove(rep Report, threshold string) int {
+	if NormalizeVulnerabilityPriorityThreshold(threshold) == VulnerabilityPriorityOff {
+		return 0
+	}
+	count := 0
+	if rep.BaselineComparison != nil {
+		for _, finding := range rep.BaselineComparison.NewReachableVulnerabilities {
+			if vulnerabilityMeetsReachableThreshold(finding.VersionStatus, finding.Priority, threshold) {
+				count++
+			}
+		}
+		return count
+	}
+	for _, dep := range rep.Dependencies {
+		count += len(reachableVulnerabilitiesAtOrAbove(dep, threshold))
+	}
+	return count
+}
+
+func reachableVulnerabilitiesAtOrAbove(dep DependencyReport, threshold string) []VulnerabilityFinding {
+	var findings []VulnerabilityFinding
+	for _, finding := range dep.Vulnerabilities {
+		if !finding.Reachable || FindingSuppressedByException(finding) {
+			continue
+		}
+		if vulnerabilityMeetsReachableThreshold(finding.VersionStatus, finding.Priority, threshold) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func vulnerabilityMeetsReachableThreshold(versionStatus, priority, threshold string) bool {
+	return versionStatus == "unevaluable" || VulnerabilityPriorityMeetsThreshold(priority, threshold)
+}
+
 func sortedUniqueStrings(values []string) []string {
 	if len(values) == 0 {
 		return nil
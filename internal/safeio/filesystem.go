@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 )
 
 // FileSystem captures the filesystem operations safeio needs.
@@ -28,6 +29,7 @@ type Root interface {
 	Lstat(name string) (fs.FileInfo, error)
 	Mkdir(name string, perm os.FileMode) error
 	Chmod(name string, perm os.FileMode) error
+	Chtimes(name string, atime, mtime time.Time) error
 	MkdirAll(name string, perm os.FileMode) error
 	Link(oldName, newName string) error
 	Rename(oldName, newName string) error
@@ -573,6 +575,10 @@ func (r *osRoot) Chmod(name string, perm os.FileMode) error {
 	return r.root.Chmod(name, perm)
 }
 
+func (r *osRoot) Chtimes(name string, atime, mtime time.Time) error {
+	return r.root.Chtimes(name, atime, mtime)
+}
+
 func (r *osRoot) MkdirAll(name string, perm os.FileMode) error {
 	return r.root.MkdirAll(name, perm)
 }
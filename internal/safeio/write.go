@@ -4,10 +4,12 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // WriteRoot pins a filesystem root for path-confined atomic writes.
@@ -118,6 +120,66 @@ func (r *WriteRoot) WriteFileCreatingParentsAtomicallyIfAbsent(targetPath string
 	})
 }
 
+// StreamFileCreatingParents publishes a root-relative file whose contents are
+// produced incrementally by write, creating missing parent directories inside
+// the pinned root. Output goes to a temporary sibling that replaces the target
+// only after write succeeds, so readers never observe a partial file.
+func (r *WriteRoot) StreamFileCreatingParents(targetPath string, write func(io.Writer) error, perm, parentPerm os.FileMode) error {
+	target, err := r.resolveTarget(targetPath)
+	if err != nil {
+		return err
+	}
+	return r.withTargetParent(target, true, parentPerm, func(parent Root, parentTarget rootedTarget) (returnErr error) {
+		tempRel, tempFile, err := createAtomicTempFile(parent, "", perm)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if tempRel != "" {
+				returnErr = errors.Join(returnErr, cleanupAtomicTempFile(parent, tempRel, tempFile))
+			}
+		}()
+		if err := write(tempFile); err != nil {
+			return err
+		}
+		if err := tempFile.Close(); err != nil {
+			return err
+		}
+		tempFile = nil
+		if err := MoveFileWithinRoot(parent, tempRel, parentTarget.rel, parentPerm, perm); err != nil {
+			return err
+		}
+		tempRel = ""
+		return nil
+	})
+}
+
+// ReadFile reads an existing root-relative regular file through the pinned
+// root, so reads resolve against the same directory as writes.
+func (r *WriteRoot) ReadFile(targetPath string) ([]byte, error) {
+	return ReadFileWithinRoot(r.root, targetPath)
+}
+
+// Chtimes sets the access and modification times of an existing root-relative
+// regular file without rewriting it. Parents are opened without following
+// symlinks and a symlinked target is rejected.
+func (r *WriteRoot) Chtimes(targetPath string, atime, mtime time.Time) error {
+	target, err := r.resolveTarget(targetPath)
+	if err != nil {
+		return err
+	}
+	return r.withTargetParent(target, false, 0, func(parent Root, parentTarget rootedTarget) error {
+		info, err := parent.Lstat(parentTarget.rel)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("target is not a regular file: %s", target.abs)
+		}
+		return parent.Chtimes(parentTarget.rel, atime, mtime)
+	})
+}
+
 func (r *WriteRoot) resolveTarget(targetPath string) (rootedTarget, error) {
 	if filepath.IsAbs(targetPath) {
 		return rootedTarget{}, fmt.Errorf("target path must be relative to root: %s", targetPath)
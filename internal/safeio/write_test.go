@@ -11,6 +11,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 )
 
 const (
@@ -920,6 +921,124 @@ func TestWriteRootCreatesMissingParentsAndPublishesAtomicallyIfAbsent(t *testing
 	}
 }
 
+func TestWriteRootStreamsFileCreatingParents(t *testing.T) {
+	rootDir := t.TempDir()
+	root := openTestWriteRoot(t, rootDir, OpenWriteRoot)
+	target := filepath.Join("reports", writeTestFileName)
+
+	if err := root.StreamFileCreatingParents(target, func(w io.Writer) error {
+		for _, line := range []string{"first\n", "second\n"} {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, 0o640, 0o750); err != nil {
+		t.Fatalf("StreamFileCreatingParents returned error: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(rootDir, target)); err != nil {
+		t.Fatalf("read streamed target: %v", err)
+	} else if string(got) != "first\nsecond\n" {
+		t.Fatalf("streamed target = %q", got)
+	}
+	if info, err := os.Stat(filepath.Join(rootDir, target)); err != nil {
+		t.Fatalf("stat streamed target: %v", err)
+	} else if info.Mode().Perm() != 0o640 {
+		t.Fatalf("unexpected file mode: %#o", info.Mode().Perm())
+	}
+
+	streamErr := errors.New("stream failed")
+	if err := root.StreamFileCreatingParents(target, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return streamErr
+	}, 0o640, 0o750); !errors.Is(err, streamErr) {
+		t.Fatalf("failed stream error = %v, want %v", err, streamErr)
+	}
+	if got, err := os.ReadFile(filepath.Join(rootDir, target)); err != nil {
+		t.Fatalf("read preserved target: %v", err)
+	} else if string(got) != "first\nsecond\n" {
+		t.Fatalf("failed stream replaced target with %q", got)
+	}
+	entries, err := os.ReadDir(filepath.Join(rootDir, "reports"))
+	if err != nil {
+		t.Fatalf("read reports dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected failed stream to remove its temp file, found %d entries", len(entries))
+	}
+}
+
+func TestWriteRootChtimesUpdatesTimesWithoutRewriting(t *testing.T) {
+	rootDir := t.TempDir()
+	root := openTestWriteRoot(t, rootDir, OpenWriteRoot)
+	target := filepath.Join("reports", writeTestFileName)
+	if err := root.WriteFileCreatingParents(target, []byte("cached"), 0o640, 0o750); err != nil {
+		t.Fatalf("write target: %v", err)
+	}
+	before, err := os.Stat(filepath.Join(rootDir, target))
+	if err != nil {
+		t.Fatalf("stat target: %v", err)
+	}
+	when := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	if err := root.Chtimes(target, when, when); err != nil {
+		t.Fatalf("Chtimes returned error: %v", err)
+	}
+	after, err := os.Stat(filepath.Join(rootDir, target))
+	if err != nil {
+		t.Fatalf("stat touched target: %v", err)
+	}
+	if !after.ModTime().Equal(when) || !os.SameFile(before, after) {
+		t.Fatalf("expected the same file with mtime %v, got %v", when, after.ModTime())
+	}
+
+	outside := filepath.Join(t.TempDir(), writeTestFileName)
+	if err := os.WriteFile(outside, []byte("outside"), 0o600); err != nil {
+		t.Fatalf("write outside file: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(rootDir, "reports", "link.txt")); err != nil {
+		t.Fatalf("create target symlink: %v", err)
+	}
+	if err := root.Chtimes(filepath.Join("reports", "link.txt"), when, when); err == nil {
+		t.Fatal("expected symlinked target to be rejected")
+	}
+	if err := root.Chtimes(filepath.Join("missing", writeTestFileName), when, when); !os.IsNotExist(err) {
+		t.Fatalf("expected missing parent to fail without being created, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(rootDir, "missing")); !os.IsNotExist(err) {
+		t.Fatalf("expected Chtimes not to create parents, got %v", err)
+	}
+}
+
+func TestWriteRootReadFileReadsThroughPinnedRoot(t *testing.T) {
+	rootDir := t.TempDir()
+	root := openTestWriteRoot(t, rootDir, OpenWriteRoot)
+	target := filepath.Join("reports", writeTestFileName)
+	if err := root.WriteFileCreatingParents(target, []byte("cached"), 0o640, 0o750); err != nil {
+		t.Fatalf("write target: %v", err)
+	}
+	data, err := root.ReadFile(target)
+	if err != nil || string(data) != "cached" {
+		t.Fatalf("unexpected read %q err=%v", data, err)
+	}
+
+	outside := filepath.Join(t.TempDir(), writeTestFileName)
+	if err := os.WriteFile(outside, []byte("outside"), 0o600); err != nil {
+		t.Fatalf("write outside file: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(rootDir, "reports", "link.txt")); err != nil {
+		t.Fatalf("create target symlink: %v", err)
+	}
+	if _, err := root.ReadFile(filepath.Join("reports", "link.txt")); err == nil {
+		t.Fatal("expected symlinked target to be rejected")
+	}
+	if _, err := root.ReadFile(filepath.Join("missing", writeTestFileName)); !os.IsNotExist(err) {
+		t.Fatalf("expected missing target to report not exist, got %v", err)
+	}
+	if _, err := root.ReadFile(outside); err == nil {
+		t.Fatal("expected absolute target to be rejected")
+	}
+}
+
 func TestWriteRootVerifyIdentity(t *testing.T) {
 	rootDir := t.TempDir()
 	root := openTestWriteRoot(t, rootDir, OpenWriteRoot)
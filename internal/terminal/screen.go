@@ -0,0 +1,27 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// SupportsScreenRefresh reports whether out is an interactive terminal that
+// can be cleared and redrawn in place.
+func SupportsScreenRefresh(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
+
+// ClearScreen moves the cursor home and clears the terminal.
+func ClearScreen(out io.Writer) error {
+	_, err := fmt.Fprint(out, "\033[H\033[2J")
+	return err
+}
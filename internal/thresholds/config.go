@@ -22,6 +22,8 @@ type LoadResult struct {
 	AdvisorySourcePath      string
 	AdvisorySourceTrustRoot string
 	VulnerabilityExceptions []report.VulnerabilityException
+	LicenseExceptions       []report.LicenseException
+	PolicyRules             []report.PolicyRule
 	ConfigPath              string
 	PolicySources           []string
 	PolicyTrace             []report.PolicyMergeTrace
@@ -68,6 +70,8 @@ func LoadWithPolicy(repoPath, explicitPath string) (LoadResult, error) {
 			AdvisorySourcePath:      "",
 			AdvisorySourceTrustRoot: "",
 			VulnerabilityExceptions: nil,
+			LicenseExceptions:       nil,
+			PolicyRules:             nil,
 			PolicySources:           []string{defaultPolicySource},
 			PolicyTrace:             policyTraceFromMap(defaultPolicyTrace()),
 		}, nil
@@ -95,6 +99,8 @@ func LoadWithPolicy(repoPath, explicitPath string) (LoadResult, error) {
 		AdvisorySourcePath:      mergeResult.advisorySource.source,
 		AdvisorySourceTrustRoot: mergeResult.advisorySource.trustRoot,
 		VulnerabilityExceptions: append([]report.VulnerabilityException{}, mergeResult.vulnerabilityExceptions.exceptions...),
+		LicenseExceptions:       append([]report.LicenseException{}, mergeResult.licenseExceptions.exceptions...),
+		PolicyRules:             append([]report.PolicyRule{}, mergeResult.policyRules.rules...),
 		ConfigPath:              configPath,
 		PolicySources:           mergeResult.policySourcesHighToLow(),
 		PolicyTrace:             policyTraceFromMap(mergeResult.policyTrace),
@@ -110,21 +116,26 @@ type rawConfig struct {
 	Advisories rawAdvisories `yaml:"advisories" json:"advisories"`
 	// Notifications are parsed by the notify package; keep this field so threshold parsing accepts shared config files.
 	Notifications map[string]any `yaml:"notifications" json:"notifications"`
+	// Rules are policy-as-code checks evaluated against the finished report.
+	Rules []report.PolicyRule `yaml:"rules" json:"rules"`
 
 	Thresholds rawThresholds `yaml:"thresholds" json:"thresholds"`
 
-	FailOnIncreasePercent             *int      `yaml:"fail_on_increase_percent" json:"fail_on_increase_percent"`
-	LowConfidenceWarningPercent       *int      `yaml:"low_confidence_warning_percent" json:"low_confidence_warning_percent"`
-	MinUsagePercentForRecommendations *int      `yaml:"min_usage_percent_for_recommendations" json:"min_usage_percent_for_recommendations"`
-	MaxUncertainImportCount           *int      `yaml:"max_uncertain_import_count" json:"max_uncertain_import_count"`
-	RemovalCandidateWeightUsage       *float64  `yaml:"removal_candidate_weight_usage" json:"removal_candidate_weight_usage"`
-	RemovalCandidateWeightImpact      *float64  `yaml:"removal_candidate_weight_impact" json:"removal_candidate_weight_impact"`
-	RemovalCandidateWeightConfidence  *float64  `yaml:"removal_candidate_weight_confidence" json:"removal_candidate_weight_confidence"`
-	LockfileDriftPolicy               *string   `yaml:"lockfile_drift_policy" json:"lockfile_drift_policy"`
-	LicenseDeny                       *[]string `yaml:"license_deny" json:"license_deny"`
-	LicenseFailOnDeny                 *bool     `yaml:"license_fail_on_deny" json:"license_fail_on_deny"`
-	LicenseIncludeRegistryProvenance  *bool     `yaml:"license_include_registry_provenance" json:"license_include_registry_provenance"`
-	ReachableVulnerabilityPriority    *string   `yaml:"reachable_vulnerability_priority" json:"reachable_vulnerability_priority"`
+	FailOnIncreasePercent             *int                      `yaml:"fail_on_increase_percent" json:"fail_on_increase_percent"`
+	LowConfidenceWarningPercent       *int                      `yaml:"low_confidence_warning_percent" json:"low_confidence_warning_percent"`
+	MinUsagePercentForRecommendations *int                      `yaml:"min_usage_percent_for_recommendations" json:"min_usage_percent_for_recommendations"`
+	MaxUncertainImportCount           *int                      `yaml:"max_uncertain_import_count" json:"max_uncertain_import_count"`
+	RemovalCandidateWeightUsage       *float64                  `yaml:"removal_candidate_weight_usage" json:"removal_candidate_weight_usage"`
+	RemovalCandidateWeightImpact      *float64                  `yaml:"removal_candidate_weight_impact" json:"removal_candidate_weight_impact"`
+	RemovalCandidateWeightConfidence  *float64                  `yaml:"removal_candidate_weight_confidence" json:"removal_candidate_weight_confidence"`
+	LockfileDriftPolicy               *string                   `yaml:"lockfile_drift_policy" json:"lockfile_drift_policy"`
+	LicenseDeny                       *[]string                 `yaml:"license_deny" json:"license_deny"`
+	LicenseAllow                      *[]string                 `yaml:"license_allow" json:"license_allow"`
+	LicenseUnknown                    *string                   `yaml:"license_unknown" json:"license_unknown"`
+	LicenseFailOnDeny                 *bool                     `yaml:"license_fail_on_deny" json:"license_fail_on_deny"`
+	LicenseIncludeRegistryProvenance  *bool                     `yaml:"license_include_registry_provenance" json:"license_include_registry_provenance"`
+	ReachableVulnerabilityPriority    *string                   `yaml:"reachable_vulnerability_priority" json:"reachable_vulnerability_priority"`
+	LicenseExceptions                 []report.LicenseException `yaml:"license_exceptions" json:"license_exceptions"`
 }
 
 type rawPolicy struct {
@@ -156,6 +167,8 @@ type rawThresholds struct {
 	RemovalCandidateWeightConfidence  *float64  `yaml:"removal_candidate_weight_confidence" json:"removal_candidate_weight_confidence"`
 	LockfileDriftPolicy               *string   `yaml:"lockfile_drift_policy" json:"lockfile_drift_policy"`
 	LicenseDeny                       *[]string `yaml:"license_deny" json:"license_deny"`
+	LicenseAllow                      *[]string `yaml:"license_allow" json:"license_allow"`
+	LicenseUnknown                    *string   `yaml:"license_unknown" json:"license_unknown"`
 	LicenseFailOnDeny                 *bool     `yaml:"license_fail_on_deny" json:"license_fail_on_deny"`
 	LicenseIncludeRegistryProvenance  *bool     `yaml:"license_include_registry_provenance" json:"license_include_registry_provenance"`
 	ReachableVulnerabilityPriority    *string   `yaml:"reachable_vulnerability_priority" json:"reachable_vulnerability_priority"`
@@ -0,0 +1,160 @@
+package thresholds
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestLoadWithPolicyParsesLicenseAllowAndUnknown(t *testing.T) {
+	repo := t.TempDir()
+	configPath := filepath.Join(repo, ".lopper.yml")
+	testutil.MustWriteFile(t, configPath, `
+thresholds:
+  license_allow: [mit, "bsd-*", "GPL-2.0-only with Classpath-exception-2.0", MIT]
+  license_unknown: WARN
+`)
+
+	result, err := LoadWithPolicy(repo, "")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := strings.Join(result.Resolved.LicenseAllowList, ","); got != "BSD-*,GPL-2.0-ONLY WITH CLASSPATH-EXCEPTION-2.0,MIT" {
+		t.Fatalf("unexpected allow list: %q", got)
+	}
+	if result.Resolved.LicenseUnknownPolicy != report.LicenseUnknownWarn {
+		t.Fatalf("expected warn unknown policy, got %q", result.Resolved.LicenseUnknownPolicy)
+	}
+	trace := traceSources(result.PolicyTrace)
+	if trace["license.allow"] != configPath || trace["license.unknown"] != configPath {
+		t.Fatalf("expected license allow/unknown traced to config, got %#v", trace)
+	}
+	if _, ok := trace[licenseExceptionsField]; ok {
+		t.Fatalf("did not expect license exceptions trace without exceptions, got %#v", trace)
+	}
+}
+
+func TestLoadWithPolicyLicenseDefaults(t *testing.T) {
+	result, err := LoadWithPolicy(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("load defaults: %v", err)
+	}
+	if len(result.Resolved.LicenseAllowList) != 0 || result.Resolved.LicenseUnknownPolicy != DefaultLicenseUnknownPolicy {
+		t.Fatalf("unexpected license defaults: %#v", result.Resolved)
+	}
+	trace := traceSources(result.PolicyTrace)
+	if _, ok := trace["license.allow"]; ok {
+		t.Fatalf("did not expect license allow trace without an allow-list, got %#v", trace)
+	}
+	if _, ok := trace["license.unknown"]; ok {
+		t.Fatalf("did not expect license unknown trace without an unknown policy, got %#v", trace)
+	}
+}
+
+func TestLoadWithPolicyRejectsInvalidLicenseUnknown(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), "license_unknown: review\n")
+	if _, err := LoadWithPolicy(repo, ""); err == nil || !strings.Contains(err.Error(), "license_unknown") {
+		t.Fatalf("expected invalid license_unknown error, got %v", err)
+	}
+}
+
+func TestLoadWithPolicyRejectsDuplicateLicenseAllow(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), "license_allow: [MIT]\nthresholds:\n  license_allow: [ISC]\n")
+	if _, err := LoadWithPolicy(repo, ""); err == nil || !strings.Contains(err.Error(), "license_allow") {
+		t.Fatalf("expected duplicate license_allow error, got %v", err)
+	}
+}
+
+func TestLoadWithPolicyMergesLicenseExceptionsAcrossPacks(t *testing.T) {
+	repo := t.TempDir()
+	packPath := filepath.Join(repo, "packs", "legal.yml")
+	configPath := filepath.Join(repo, ".lopper.yml")
+	testutil.MustWriteFile(t, packPath, `
+license_allow: [MIT]
+license_exceptions:
+  - package: left-pad
+    license: WTFPL
+    owner: legal
+    reason: reviewed
+    expires: "2027-01-01"
+`)
+	testutil.MustWriteFile(t, configPath, `
+policy:
+  packs: [packs/legal.yml]
+license_exceptions:
+  - purl: pkg:npm/chalk@5.0.0
+    owner: " platform "
+    reason: vendored fork
+    expires: "2027-02-01T00:00:00Z"
+`)
+
+	result, err := LoadWithPolicy(repo, "")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(result.LicenseExceptions) != 2 {
+		t.Fatalf("expected merged license exceptions, got %#v", result.LicenseExceptions)
+	}
+	pack, self := result.LicenseExceptions[0], result.LicenseExceptions[1]
+	if pack.Package != "left-pad" || pack.License != "WTFPL" || pack.Source != packPath {
+		t.Fatalf("unexpected pack exception: %#v", pack)
+	}
+	if self.PURL != "pkg:npm/chalk@5.0.0" || self.Owner != "platform" || self.Source != configPath {
+		t.Fatalf("unexpected repo exception: %#v", self)
+	}
+	trace := traceSources(result.PolicyTrace)
+	if trace[licenseExceptionsField] != configPath || trace["license.allow"] != packPath {
+		t.Fatalf("unexpected license trace: %#v", trace)
+	}
+}
+
+func TestLoadWithPolicyRejectsInvalidLicenseExceptions(t *testing.T) {
+	cases := []struct {
+		name   string
+		fields string
+	}{
+		{name: "missing owner", fields: "package: left-pad\nreason: reviewed\nexpires: \"2027-01-01\"\n"},
+		{name: "missing reason", fields: "package: left-pad\nowner: legal\nexpires: \"2027-01-01\"\n"},
+		{name: "missing expires", fields: "package: left-pad\nowner: legal\nreason: reviewed\n"},
+		{name: "missing scope", fields: "owner: legal\nreason: reviewed\nexpires: \"2027-01-01\"\n"},
+		{name: "wildcard scope", fields: "package: \"*\"\nowner: legal\nreason: reviewed\nexpires: \"2027-01-01\"\n"},
+		{name: "invalid expiry", fields: "package: left-pad\nowner: legal\nreason: reviewed\nexpires: \"2027/01/01\"\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := t.TempDir()
+			testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), "license_exceptions:\n  - "+strings.ReplaceAll(strings.TrimSuffix(tc.fields, "\n"), "\n", "\n    "))
+			if _, err := LoadWithPolicy(repo, ""); err == nil || !strings.Contains(err.Error(), "license_exceptions[0]") {
+				t.Fatalf("expected invalid license exception error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadWithPolicyRejectsLicenseExceptionsUnderThresholds(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), `
+thresholds:
+  license_exceptions:
+    - package: left-pad
+      owner: legal
+      reason: reviewed
+      expires: "2027-01-01"
+`)
+	if _, err := LoadWithPolicy(repo, ""); err == nil || !strings.Contains(err.Error(), "field license_exceptions not found") {
+		t.Fatalf("expected thresholds.license_exceptions to be rejected, got %v", err)
+	}
+}
+
+func traceSources(trace []report.PolicyMergeTrace) map[string]string {
+	sources := make(map[string]string, len(trace))
+	for _, item := range trace {
+		sources[item.Field] = item.Source
+	}
+	return sources
+}
@@ -28,6 +28,7 @@ func (c *rawConfig) toOverrides() (Overrides, error) {
 		RemovalCandidateWeightImpact:      c.RemovalCandidateWeightImpact,
 		RemovalCandidateWeightConfidence:  c.RemovalCandidateWeightConfidence,
 		LockfileDriftPolicy:               c.LockfileDriftPolicy,
+		LicenseUnknownPolicy:              c.LicenseUnknown,
 		LicenseFailOnDeny:                 c.LicenseFailOnDeny,
 		LicenseIncludeRegistryProvenance:  c.LicenseIncludeRegistryProvenance,
 		ReachableVulnerabilityPriority:    c.ReachableVulnerabilityPriority,
@@ -36,6 +37,10 @@ func (c *rawConfig) toOverrides() (Overrides, error) {
 		overrides.LicenseDenyList = cloneStrings(*c.LicenseDeny)
 		overrides.licenseDenyListSet = true
 	}
+	if c.LicenseAllow != nil {
+		overrides.LicenseAllowList = cloneStrings(*c.LicenseAllow)
+		overrides.licenseAllowListSet = true
+	}
 	if err := applyNestedOverride("fail_on_increase_percent", &overrides.FailOnIncreasePercent, c.Thresholds.FailOnIncreasePercent); err != nil {
 		return Overrides{}, err
 	}
@@ -63,6 +68,12 @@ func (c *rawConfig) toOverrides() (Overrides, error) {
 	if err := applyNestedListOverride("license_deny", &overrides.LicenseDenyList, c.Thresholds.LicenseDeny, &overrides.licenseDenyListSet); err != nil {
 		return Overrides{}, err
 	}
+	if err := applyNestedListOverride("license_allow", &overrides.LicenseAllowList, c.Thresholds.LicenseAllow, &overrides.licenseAllowListSet); err != nil {
+		return Overrides{}, err
+	}
+	if err := applyNestedStringOverride("license_unknown", &overrides.LicenseUnknownPolicy, c.Thresholds.LicenseUnknown); err != nil {
+		return Overrides{}, err
+	}
 	if err := applyNestedBoolOverride("license_fail_on_deny", &overrides.LicenseFailOnDeny, c.Thresholds.LicenseFailOnDeny); err != nil {
 		return Overrides{}, err
 	}
@@ -161,6 +172,13 @@ func mergeOverrides(base, higher Overrides) Overrides {
 		merged.LicenseDenyList = cloneStrings(higher.LicenseDenyList)
 		merged.licenseDenyListSet = true
 	}
+	if higher.licenseAllowListSet || len(higher.LicenseAllowList) > 0 {
+		merged.LicenseAllowList = cloneStrings(higher.LicenseAllowList)
+		merged.licenseAllowListSet = true
+	}
+	if higher.LicenseUnknownPolicy != nil {
+		merged.LicenseUnknownPolicy = higher.LicenseUnknownPolicy
+	}
 	if higher.LicenseFailOnDeny != nil {
 		merged.LicenseFailOnDeny = higher.LicenseFailOnDeny
 	}
@@ -216,6 +234,16 @@ type vulnerabilityExceptionConfig struct {
 	set        bool
 }
 
+type licenseExceptionConfig struct {
+	exceptions []report.LicenseException
+	set        bool
+}
+
+type policyRuleConfig struct {
+	rules []report.PolicyRule
+	set   bool
+}
+
 func (a *rawAdvisories) toAdvisorySourceConfig(configPath, trustRoot string) advisorySourceConfig {
 	if a == nil || a.Source == nil {
 		return advisorySourceConfig{}
@@ -299,6 +327,75 @@ func normalizeVulnerabilityException(configPath string, index int, exception rep
 	return normalized, nil
 }
 
+func (c *rawConfig) toLicenseExceptionConfig(configPath string) (licenseExceptionConfig, error) {
+	if len(c.LicenseExceptions) == 0 {
+		return licenseExceptionConfig{}, nil
+	}
+	exceptions := make([]report.LicenseException, 0, len(c.LicenseExceptions))
+	for index, exception := range c.LicenseExceptions {
+		normalized, err := normalizeLicenseException(configPath, index, exception)
+		if err != nil {
+			return licenseExceptionConfig{}, err
+		}
+		exceptions = append(exceptions, normalized)
+	}
+	return licenseExceptionConfig{exceptions: exceptions, set: true}, nil
+}
+
+func normalizeLicenseException(configPath string, index int, exception report.LicenseException) (report.LicenseException, error) {
+	normalized := report.LicenseException{
+		PURL:    strings.TrimSpace(exception.PURL),
+		Package: strings.TrimSpace(exception.Package),
+		License: strings.TrimSpace(exception.License),
+		Owner:   strings.TrimSpace(exception.Owner),
+		Reason:  strings.TrimSpace(exception.Reason),
+		Expires: strings.TrimSpace(exception.Expires),
+		Source:  strings.TrimSpace(exception.Source),
+	}
+	if normalized.Source == "" {
+		normalized.Source = configPath
+	}
+	switch {
+	case normalized.Owner == "":
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d].owner is required", index)
+	case normalized.Reason == "":
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d].reason is required", index)
+	case normalized.Expires == "":
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d].expires is required", index)
+	case normalized.PURL == "" && normalized.Package == "":
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d] must define purl or package scope", index)
+	case normalized.PURL == "*" || normalized.Package == "*":
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d] wildcard scopes are not allowed", index)
+	}
+	if !validVulnerabilityExceptionExpiry(normalized.Expires) {
+		return report.LicenseException{}, fmt.Errorf("license_exceptions[%d].expires must be RFC3339 or YYYY-MM-DD", index)
+	}
+	return normalized, nil
+}
+
+func (c *rawConfig) toPolicyRuleConfig(configPath string) (policyRuleConfig, error) {
+	if len(c.Rules) == 0 {
+		return policyRuleConfig{}, nil
+	}
+	rules := make([]report.PolicyRule, 0, len(c.Rules))
+	seen := make(map[string]int, len(c.Rules))
+	for index, rule := range c.Rules {
+		normalized, err := report.NormalizePolicyRule(rule)
+		if err != nil {
+			return policyRuleConfig{}, fmt.Errorf("rules[%d]: %w", index, err)
+		}
+		if previous, ok := seen[normalized.ID]; ok {
+			return policyRuleConfig{}, fmt.Errorf("rules[%d].id %q duplicates rules[%d]", index, normalized.ID, previous)
+		}
+		seen[normalized.ID] = index
+		if normalized.Source == "" {
+			normalized.Source = configPath
+		}
+		rules = append(rules, normalized)
+	}
+	return policyRuleConfig{rules: rules, set: true}, nil
+}
+
 func validVulnerabilityExceptionExpiry(value string) bool {
 	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
 		if _, err := time.Parse(layout, value); err == nil {
@@ -324,6 +421,38 @@ func mergeVulnerabilityExceptions(base, higher vulnerabilityExceptionConfig) vul
 	return merged
 }
 
+func mergeLicenseExceptions(base, higher licenseExceptionConfig) licenseExceptionConfig {
+	if !higher.set && len(higher.exceptions) == 0 {
+		return base
+	}
+	merged := licenseExceptionConfig{set: base.set || higher.set}
+	merged.exceptions = append(append([]report.LicenseException{}, base.exceptions...), higher.exceptions...)
+	return merged
+}
+
+// mergePolicyRules keeps rule order stable; a higher-precedence rule with the
+// same id replaces the inherited one in place.
+func mergePolicyRules(base, higher policyRuleConfig) policyRuleConfig {
+	if !higher.set && len(higher.rules) == 0 {
+		return base
+	}
+	merged := policyRuleConfig{set: base.set || higher.set}
+	merged.rules = append([]report.PolicyRule{}, base.rules...)
+	positions := make(map[string]int, len(merged.rules))
+	for index, rule := range merged.rules {
+		positions[rule.ID] = index
+	}
+	for _, rule := range higher.rules {
+		if index, ok := positions[rule.ID]; ok {
+			merged.rules[index] = rule
+			continue
+		}
+		positions[rule.ID] = len(merged.rules)
+		merged.rules = append(merged.rules, rule)
+	}
+	return merged
+}
+
 func normalizePathScope(scope PathScope) PathScope {
 	if len(scope.Include) == 0 {
 		scope.Include = make([]string, 0)
@@ -348,6 +477,9 @@ func normalizeOverrides(overrides Overrides) Overrides {
 	if len(overrides.LicenseDenyList) == 0 {
 		overrides.LicenseDenyList = make([]string, 0)
 	}
+	if len(overrides.LicenseAllowList) == 0 {
+		overrides.LicenseAllowList = make([]string, 0)
+	}
 	return overrides
 }
 
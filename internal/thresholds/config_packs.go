@@ -14,6 +14,8 @@ import (
 const invalidPolicyPackErrFmt = "parse config file %s: invalid policy.packs[%d]: %w"
 const advisorySourceField = "advisories.source"
 const advisoryExceptionsField = "advisories.exceptions"
+const licenseExceptionsField = "license.exceptions"
+const policyRulesField = "rules"
 
 type packResolver struct {
 	repoPath string
@@ -30,6 +32,8 @@ type resolveMergeResult struct {
 	features                FeatureConfig
 	advisorySource          advisorySourceConfig
 	vulnerabilityExceptions vulnerabilityExceptionConfig
+	licenseExceptions       licenseExceptionConfig
+	policyRules             policyRuleConfig
 	appliedSourcesLow       []string
 	policyTrace             map[string]string
 }
@@ -95,6 +99,8 @@ func (r *packResolver) resolveFile(path string, trust packTrust) (resolveMergeRe
 	mergedFeatures := FeatureConfig{}
 	mergedAdvisorySource := advisorySourceConfig{}
 	mergedVulnerabilityExceptions := vulnerabilityExceptionConfig{}
+	mergedLicenseExceptions := licenseExceptionConfig{}
+	mergedPolicyRules := policyRuleConfig{}
 	mergedTrace := defaultPolicyTrace()
 	sources := make([]string, 0, len(cfg.Policy.Packs)+1)
 	for idx, packRef := range cfg.Policy.Packs {
@@ -107,6 +113,8 @@ func (r *packResolver) resolveFile(path string, trust packTrust) (resolveMergeRe
 		mergedFeatures = mergeFeatures(mergedFeatures, packResult.features)
 		mergedAdvisorySource = mergeAdvisorySource(mergedAdvisorySource, packResult.advisorySource)
 		mergedVulnerabilityExceptions = mergeVulnerabilityExceptions(mergedVulnerabilityExceptions, packResult.vulnerabilityExceptions)
+		mergedLicenseExceptions = mergeLicenseExceptions(mergedLicenseExceptions, packResult.licenseExceptions)
+		mergedPolicyRules = mergePolicyRules(mergedPolicyRules, packResult.policyRules)
 		mergedTrace = mergePolicyTrace(mergedTrace, packResult.policyTrace)
 		sources = append(sources, packResult.appliedSourcesLow...)
 	}
@@ -125,9 +133,21 @@ func (r *packResolver) resolveFile(path string, trust packTrust) (resolveMergeRe
 		return resolveMergeResult{}, fmt.Errorf(parseConfigErrFmt, canonical, err)
 	}
 	mergedVulnerabilityExceptions = mergeVulnerabilityExceptions(mergedVulnerabilityExceptions, selfVulnerabilityExceptions)
+	selfLicenseExceptions, err := cfg.toLicenseExceptionConfig(canonical)
+	if err != nil {
+		return resolveMergeResult{}, fmt.Errorf(parseConfigErrFmt, canonical, err)
+	}
+	mergedLicenseExceptions = mergeLicenseExceptions(mergedLicenseExceptions, selfLicenseExceptions)
+	selfPolicyRules, err := cfg.toPolicyRuleConfig(canonical)
+	if err != nil {
+		return resolveMergeResult{}, fmt.Errorf(parseConfigErrFmt, canonical, err)
+	}
+	mergedPolicyRules = mergePolicyRules(mergedPolicyRules, selfPolicyRules)
 	mergedTrace = mergePolicyTrace(mergedTrace, traceForOverrides(canonical, selfOverrides))
 	mergedTrace = mergePolicyTrace(mergedTrace, traceForAdvisorySource(canonical, selfAdvisorySource))
 	mergedTrace = mergePolicyTrace(mergedTrace, traceForVulnerabilityExceptions(canonical, selfVulnerabilityExceptions))
+	mergedTrace = mergePolicyTrace(mergedTrace, traceForLicenseExceptions(canonical, selfLicenseExceptions))
+	mergedTrace = mergePolicyTrace(mergedTrace, traceForPolicyRules(canonical, selfPolicyRules))
 	sources = append(sources, canonical)
 
 	return resolveMergeResult{
@@ -136,6 +156,8 @@ func (r *packResolver) resolveFile(path string, trust packTrust) (resolveMergeRe
 		features:                mergedFeatures,
 		advisorySource:          mergedAdvisorySource,
 		vulnerabilityExceptions: mergedVulnerabilityExceptions,
+		licenseExceptions:       mergedLicenseExceptions,
+		policyRules:             mergedPolicyRules,
 		appliedSourcesLow:       dedupeStable(sources),
 		policyTrace:             mergedTrace,
 	}, nil
@@ -251,16 +273,31 @@ var policyTraceFieldNames = []string{
 	"removal_candidate_weights.impact",
 	"removal_candidate_weights.confidence",
 	"license.deny",
+	"license.allow",
+	"license.unknown",
 	"license.fail_on_deny",
 	"license.include_registry_provenance",
+	licenseExceptionsField,
+	policyRulesField,
 	advisorySourceField,
 	advisoryExceptionsField,
 }
 
+// unsetPolicyTraceFields are traced only once a config or the CLI sets them,
+// so runs without that policy keep their default merge trace.
+var unsetPolicyTraceFields = map[string]struct{}{
+	"license.allow":         {},
+	"license.unknown":       {},
+	licenseExceptionsField:  {},
+	policyRulesField:        {},
+	advisorySourceField:     {},
+	advisoryExceptionsField: {},
+}
+
 func defaultPolicyTrace() map[string]string {
 	trace := make(map[string]string, len(policyTraceFieldNames))
 	for _, field := range policyTraceFieldNames {
-		if field == advisorySourceField || field == advisoryExceptionsField {
+		if _, ok := unsetPolicyTraceFields[field]; ok {
 			continue
 		}
 		trace[field] = defaultPolicySource
@@ -311,6 +348,12 @@ func traceForOverrides(source string, overrides Overrides) map[string]string {
 	if overrides.licenseDenyListSet {
 		trace["license.deny"] = source
 	}
+	if overrides.licenseAllowListSet {
+		trace["license.allow"] = source
+	}
+	if overrides.LicenseUnknownPolicy != nil {
+		trace["license.unknown"] = source
+	}
 	if overrides.LicenseFailOnDeny != nil {
 		trace["license.fail_on_deny"] = source
 	}
@@ -330,6 +373,20 @@ func traceForVulnerabilityExceptions(source string, exceptions vulnerabilityExce
 	return map[string]string{advisoryExceptionsField: source}
 }
 
+func traceForLicenseExceptions(source string, exceptions licenseExceptionConfig) map[string]string {
+	if !exceptions.set && len(exceptions.exceptions) == 0 {
+		return nil
+	}
+	return map[string]string{licenseExceptionsField: source}
+}
+
+func traceForPolicyRules(source string, rules policyRuleConfig) map[string]string {
+	if !rules.set && len(rules.rules) == 0 {
+		return nil
+	}
+	return map[string]string{policyRulesField: source}
+}
+
 func traceForAdvisorySource(source string, advisorySource advisorySourceConfig) map[string]string {
 	if !advisorySource.set {
 		return nil
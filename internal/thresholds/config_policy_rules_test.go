@@ -0,0 +1,99 @@
+package thresholds
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+func TestLoadWithPolicyMergesRulesAcrossPacks(t *testing.T) {
+	repo := t.TempDir()
+	packPath := filepath.Join(repo, "packs", "platform.yml")
+	configPath := filepath.Join(repo, ".lopper.yml")
+	testutil.MustWriteFile(t, packPath, `
+rules:
+  - id: runtime-critical-risk
+    severity: error
+    message: runtime-only dependency has a critical risk cue
+    expr: has(dependency.runtimeUsage) && dependency.runtimeUsage.runtimeOnly && has(dependency.riskCues) && dependency.riskCues.exists(c, c.severity == "critical")
+  - id: small-js-footprint
+    severity: note
+    message: low-usage JS dependency
+    expr: dependency.usedPercent < 5
+`)
+	testutil.MustWriteFile(t, configPath, `
+policy:
+  packs: [packs/platform.yml]
+rules:
+  - id: small-js-footprint
+    severity: " Warning "
+    message: low-usage JS dependency adds more than 100 KB
+    expr: dependency.language == "js-ts" && dependency.usedPercent < 5 && dependency.estimatedUnusedBytes > 100 * 1024
+  - id: too-many-dependencies
+    severity: warning
+    scope: report
+    message: more than 200 dependencies
+    expr: size(report.dependencies) > 200
+`)
+
+	result, err := LoadWithPolicy(repo, "")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(result.PolicyRules) != 3 {
+		t.Fatalf("expected merged policy rules, got %#v", result.PolicyRules)
+	}
+	pack, override, self := result.PolicyRules[0], result.PolicyRules[1], result.PolicyRules[2]
+	if pack.ID != "runtime-critical-risk" || pack.Scope != "dependency" || pack.Source != packPath {
+		t.Fatalf("unexpected pack rule: %#v", pack)
+	}
+	if override.ID != "small-js-footprint" || override.Severity != "warning" || override.Source != configPath {
+		t.Fatalf("expected repo rule to replace pack rule in place, got %#v", override)
+	}
+	if self.ID != "too-many-dependencies" || self.Scope != "report" {
+		t.Fatalf("unexpected report rule: %#v", self)
+	}
+	if trace := traceSources(result.PolicyTrace); trace[policyRulesField] != configPath {
+		t.Fatalf("unexpected rules trace: %#v", trace)
+	}
+}
+
+func TestLoadWithPolicyRejectsInvalidRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		fields string
+		want   string
+	}{
+		{name: "missing id", fields: "severity: error\nmessage: m\nexpr: \"true\"\n", want: "id is required"},
+		{name: "invalid id", fields: "id: bad id\nseverity: error\nmessage: m\nexpr: \"true\"\n", want: "may only contain"},
+		{name: "unknown severity", fields: "id: r\nseverity: fatal\nmessage: m\nexpr: \"true\"\n", want: "severity must be one of"},
+		{name: "missing message", fields: "id: r\nseverity: error\nexpr: \"true\"\n", want: "message is required"},
+		{name: "unknown scope", fields: "id: r\nseverity: error\nmessage: m\nscope: module\nexpr: \"true\"\n", want: "scope must be"},
+		{name: "invalid expr", fields: "id: r\nseverity: error\nmessage: m\nexpr: dependency.usedPercent <\n", want: "expr:"},
+		{name: "report rule reads dependency", fields: "id: r\nseverity: error\nmessage: m\nscope: report\nexpr: dependency.usedPercent < 5\n", want: "undeclared reference"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := t.TempDir()
+			testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), "rules:\n  - "+strings.ReplaceAll(strings.TrimSuffix(tc.fields, "\n"), "\n", "\n    "))
+			_, err := LoadWithPolicy(repo, "")
+			if err == nil || !strings.Contains(err.Error(), "rules[0]") || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected invalid rule error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadWithPolicyRejectsDuplicateRuleIDs(t *testing.T) {
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, ".lopper.yml"), `
+rules:
+  - {id: r, severity: error, message: m, expr: "true"}
+  - {id: r, severity: note, message: m, expr: "false"}
+`)
+	if _, err := LoadWithPolicy(repo, ""); err == nil || !strings.Contains(err.Error(), `rules[1].id "r" duplicates rules[0]`) {
+		t.Fatalf("expected duplicate rule id error, got %v", err)
+	}
+}
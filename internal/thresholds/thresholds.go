@@ -2,7 +2,6 @@ package thresholds
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/ben-ranford/lopper/internal/report"
@@ -18,6 +17,7 @@ const (
 	DefaultLockfileDriftPolicy              = "warn"
 	DefaultLicenseFailOnDeny                = false
 	DefaultLicenseIncludeRegistryProvenance = false
+	DefaultLicenseUnknownPolicy             = report.LicenseUnknownAllow
 	DefaultReachableVulnerabilityPriority   = "off"
 )
 
@@ -39,6 +39,8 @@ type Values struct {
 	RemovalCandidateWeightConfidence  float64
 	LockfileDriftPolicy               string
 	LicenseDenyList                   []string
+	LicenseAllowList                  []string
+	LicenseUnknownPolicy              string
 	LicenseFailOnDeny                 bool
 	LicenseIncludeRegistryProvenance  bool
 	ReachableVulnerabilityPriority    string
@@ -55,6 +57,9 @@ type Overrides struct {
 	LockfileDriftPolicy               *string
 	LicenseDenyList                   []string
 	licenseDenyListSet                bool
+	LicenseAllowList                  []string
+	licenseAllowListSet               bool
+	LicenseUnknownPolicy              *string
 	LicenseFailOnDeny                 *bool
 	LicenseIncludeRegistryProvenance  *bool
 	ReachableVulnerabilityPriority    *string
@@ -112,6 +117,11 @@ var stringThresholdValidators = []stringThresholdValidator{
 		override: func(o *Overrides) *string { return o.ReachableVulnerabilityPriority },
 		validate: validateReachableVulnerabilityPriority,
 	},
+	{
+		value:    func(v *Values) string { return v.LicenseUnknownPolicy },
+		override: func(o *Overrides) *string { return o.LicenseUnknownPolicy },
+		validate: validateLicenseUnknownPolicy,
+	},
 }
 
 func (o *Overrides) SetLicenseDenyList(values []string) {
@@ -123,6 +133,15 @@ func (o *Overrides) HasLicenseDenyListOverride() bool {
 	return o.licenseDenyListSet
 }
 
+func (o *Overrides) SetLicenseAllowList(values []string) {
+	o.LicenseAllowList = append(make([]string, 0, len(values)), values...)
+	o.licenseAllowListSet = true
+}
+
+func (o *Overrides) HasLicenseAllowListOverride() bool {
+	return o.licenseAllowListSet
+}
+
 func RemovalCandidateWeights(v Values) report.RemovalCandidateWeights {
 	return report.RemovalCandidateWeights{
 		Usage:      v.RemovalCandidateWeightUsage,
@@ -143,6 +162,8 @@ func Defaults() Values {
 		RemovalCandidateWeightConfidence:  defaultWeights.Confidence,
 		LockfileDriftPolicy:               DefaultLockfileDriftPolicy,
 		LicenseDenyList:                   make([]string, 0),
+		LicenseAllowList:                  make([]string, 0),
+		LicenseUnknownPolicy:              DefaultLicenseUnknownPolicy,
 		LicenseFailOnDeny:                 DefaultLicenseFailOnDeny,
 		LicenseIncludeRegistryProvenance:  DefaultLicenseIncludeRegistryProvenance,
 		ReachableVulnerabilityPriority:    DefaultReachableVulnerabilityPriority,
@@ -159,7 +180,9 @@ func (v *Values) Validate() error {
 	if err := validateValueStrings(v); err != nil {
 		return err
 	}
-	v.LicenseDenyList = normalizeDenyList(v.LicenseDenyList)
+	v.LicenseDenyList = normalizeLicenseList(v.LicenseDenyList)
+	v.LicenseAllowList = normalizeLicenseList(v.LicenseAllowList)
+	v.LicenseUnknownPolicy = report.NormalizeLicenseUnknownPolicy(v.LicenseUnknownPolicy)
 	v.ReachableVulnerabilityPriority = report.NormalizeVulnerabilityPriorityThreshold(v.ReachableVulnerabilityPriority)
 	return nil
 }
@@ -193,6 +216,12 @@ func (o *Overrides) Apply(base Values) Values {
 	if o.licenseDenyListSet || len(o.LicenseDenyList) > 0 {
 		resolved.LicenseDenyList = append(make([]string, 0, len(o.LicenseDenyList)), o.LicenseDenyList...)
 	}
+	if o.licenseAllowListSet || len(o.LicenseAllowList) > 0 {
+		resolved.LicenseAllowList = append(make([]string, 0, len(o.LicenseAllowList)), o.LicenseAllowList...)
+	}
+	if o.LicenseUnknownPolicy != nil {
+		resolved.LicenseUnknownPolicy = report.NormalizeLicenseUnknownPolicy(*o.LicenseUnknownPolicy)
+	}
 	if o.LicenseFailOnDeny != nil {
 		resolved.LicenseFailOnDeny = *o.LicenseFailOnDeny
 	}
@@ -202,7 +231,8 @@ func (o *Overrides) Apply(base Values) Values {
 	if o.ReachableVulnerabilityPriority != nil {
 		resolved.ReachableVulnerabilityPriority = report.NormalizeVulnerabilityPriorityThreshold(*o.ReachableVulnerabilityPriority)
 	}
-	resolved.LicenseDenyList = normalizeDenyList(resolved.LicenseDenyList)
+	resolved.LicenseDenyList = normalizeLicenseList(resolved.LicenseDenyList)
+	resolved.LicenseAllowList = normalizeLicenseList(resolved.LicenseAllowList)
 	return resolved
 }
 
@@ -216,7 +246,12 @@ func (o *Overrides) Validate() error {
 	if err := validateOverrideStrings(o); err != nil {
 		return err
 	}
-	o.LicenseDenyList = normalizeDenyList(o.LicenseDenyList)
+	o.LicenseDenyList = normalizeLicenseList(o.LicenseDenyList)
+	o.LicenseAllowList = normalizeLicenseList(o.LicenseAllowList)
+	if o.LicenseUnknownPolicy != nil {
+		normalized := report.NormalizeLicenseUnknownPolicy(*o.LicenseUnknownPolicy)
+		o.LicenseUnknownPolicy = &normalized
+	}
 	if o.ReachableVulnerabilityPriority != nil {
 		normalized := report.NormalizeVulnerabilityPriorityThreshold(*o.ReachableVulnerabilityPriority)
 		o.ReachableVulnerabilityPriority = &normalized
@@ -249,6 +284,13 @@ func validateReachableVulnerabilityPriority(value string) error {
 	return fmt.Errorf("invalid threshold reachable_vulnerability_priority: %q (must be one of: off, low, medium, high, critical)", value)
 }
 
+func validateLicenseUnknownPolicy(value string) error {
+	if report.ValidLicenseUnknownPolicy(value) {
+		return nil
+	}
+	return fmt.Errorf("invalid threshold license_unknown: %q (must be one of: allow, warn, deny)", value)
+}
+
 func validatePercentageRange(name string, value int) error {
 	if value < 0 || value > 100 {
 		return fmt.Errorf("invalid threshold %s: %d (must be between 0 and 100)", name, value)
@@ -319,37 +361,8 @@ func validateOptionalWeights(overrides *Overrides) error {
 	return report.ValidateRemovalCandidateWeightSet(weights)
 }
 
-func normalizeDenyList(values []string) []string {
-	seen := make(map[string]struct{}, len(values))
-	out := make([]string, 0, len(values))
-	for _, value := range values {
-		item := normalizeSPDXID(value)
-		if item == "" {
-			continue
-		}
-		if _, ok := seen[item]; ok {
-			continue
-		}
-		seen[item] = struct{}{}
-		out = append(out, item)
-	}
-	sort.Strings(out)
-	return out
-}
-
-func normalizeSPDXID(value string) string {
-	var b strings.Builder
-	for _, r := range value {
-		switch {
-		case r >= 'a' && r <= 'z':
-			b.WriteRune(r - 'a' + 'A')
-		case r >= 'A' && r <= 'Z':
-			b.WriteRune(r)
-		case r >= '0' && r <= '9':
-			b.WriteRune(r)
-		case r == '-', r == '.', r == '+':
-			b.WriteRune(r)
-		}
-	}
-	return b.String()
+// normalizeLicenseList normalizes license deny and allow entries, keeping glob
+// wildcards and WITH exception forms intact.
+func normalizeLicenseList(values []string) []string {
+	return report.SortedLicensePatterns(values)
 }
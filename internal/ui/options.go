@@ -10,4 +10,6 @@ type Options struct {
 	BaselinePath      string
 	BaselineStorePath string
 	BaselineKey       string
+	Watch             bool
+	CachePath         string
 }
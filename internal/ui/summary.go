@@ -13,6 +13,7 @@ import (
 
 	"github.com/ben-ranford/lopper/internal/analysis"
 	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/terminal"
 	"github.com/ben-ranford/lopper/internal/workspace"
 )
 
@@ -46,6 +47,9 @@ func (s *Summary) Start(ctx context.Context, opts Options) error {
 	if err != nil {
 		return err
 	}
+	if opts.Watch {
+		return s.startWatching(ctx, opts, reportView)
+	}
 
 	reader := bufio.NewReader(s.In)
 	state := buildSummaryState(opts)
@@ -306,20 +310,11 @@ func clampSummaryPage(reportView summaryReportView, state *summaryState) {
 }
 
 func supportsScreenRefresh(out io.Writer) bool {
-	file, ok := out.(*os.File)
-	if !ok {
-		return false
-	}
-	info, err := file.Stat()
-	if err != nil {
-		return false
-	}
-	return (info.Mode() & os.ModeCharDevice) != 0
+	return terminal.SupportsScreenRefresh(out)
 }
 
 func clearSummaryScreen(out io.Writer) error {
-	_, err := fmt.Fprint(out, "\033[H\033[2J")
-	return err
+	return terminal.ClearScreen(out)
 }
 
 func summaryHelpText() string {
@@ -461,11 +456,15 @@ func (s *Summary) Snapshot(ctx context.Context, opts Options, outputPath string)
 }
 
 func (s *Summary) analyseSummaryView(ctx context.Context, opts Options) (summaryReportView, error) {
-	reportData, err := s.Analyzer.Analyse(ctx, analysis.Request{
+	req := analysis.Request{
 		RepoPath: opts.RepoPath,
 		TopN:     opts.TopN,
 		Language: opts.Language,
-	})
+	}
+	if opts.CachePath != "" {
+		req.Cache = &analysis.CacheOptions{Enabled: true, Path: opts.CachePath}
+	}
+	reportData, err := s.Analyzer.Analyse(ctx, req)
 	if err != nil {
 		return summaryReportView{}, err
 	}
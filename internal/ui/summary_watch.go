@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/analysis"
+	"github.com/ben-ranford/lopper/internal/terminal"
+)
+
+var (
+	summaryWatchPollInterval time.Duration
+	summaryWatchDebounce     time.Duration
+)
+
+type summaryInputEvent struct {
+	input string
+	err   error
+}
+
+type summaryWatchEvent struct {
+	changed []string
+	err     error
+}
+
+// startWatching runs the interactive summary while re-analysing whenever
+// watched files change. Commands keep working between runs; filters, sort
+// order, and page survive a refresh.
+func (s *Summary) startWatching(ctx context.Context, opts Options, reportView summaryReportView) error {
+	watcher, err := analysis.NewRepoWatcher(analysis.WatchOptions{
+		RepoPath:     opts.RepoPath,
+		IgnoredPaths: []string{opts.CachePath},
+		PollInterval: summaryWatchPollInterval,
+		Debounce:     summaryWatchDebounce,
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	inputs := make(chan summaryInputEvent)
+	changes := make(chan summaryWatchEvent)
+	go readSummaryInputs(ctx, bufio.NewReader(s.In), inputs)
+	go watchSummaryChanges(ctx, watcher, changes)
+
+	state := buildSummaryState(opts)
+	refreshInPlace := supportsScreenRefresh(s.Out)
+	status := "Watching for changes."
+	for {
+		if refreshInPlace {
+			if err := clearSummaryScreen(s.Out); err != nil {
+				return err
+			}
+		}
+		if err := s.renderSummaryOutput(reportView, &state); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(s.Out, status); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-inputs:
+			if event.err != nil {
+				return event.err
+			}
+			quit, err := s.handleSummaryInputMutable(ctx, &opts, &reportView, &state, event.input)
+			if err != nil || quit {
+				return err
+			}
+		case event := <-changes:
+			if event.err != nil {
+				return event.err
+			}
+			status = summaryWatchStatus(event.changed, nil)
+			refreshed, err := s.analyseSummaryView(ctx, opts)
+			if err != nil {
+				status = summaryWatchStatus(event.changed, err)
+				continue
+			}
+			reportView = refreshed
+			clampSummaryPage(reportView, &state)
+		}
+	}
+}
+
+func readSummaryInputs(ctx context.Context, reader *bufio.Reader, inputs chan<- summaryInputEvent) {
+	for {
+		input, err := readSummaryInput(reader)
+		select {
+		case inputs <- summaryInputEvent{input: input, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func watchSummaryChanges(ctx context.Context, watcher *analysis.RepoWatcher, changes chan<- summaryWatchEvent) {
+	for {
+		changed, err := watcher.Next(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case changes <- summaryWatchEvent{changed: changed, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func summaryWatchStatus(changed []string, err error) string {
+	status := "Re-analysed after changes to " + strings.Join(terminal.SanitizeStrings(changed), ", ") + "."
+	if err != nil {
+		status = "Re-analysis failed after changes to " + strings.Join(terminal.SanitizeStrings(changed), ", ") + ": " + terminal.SanitizeString(err.Error())
+	}
+	return status
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ben-ranford/lopper/internal/analysis"
+	"github.com/ben-ranford/lopper/internal/report"
+	"github.com/ben-ranford/lopper/internal/testutil"
+)
+
+type watchingAnalyzer struct {
+	reports  []report.Report
+	calls    int
+	analysed chan analysis.Request
+}
+
+func (a *watchingAnalyzer) Analyse(_ context.Context, req analysis.Request) (report.Report, error) {
+	a.calls++
+	a.analysed <- req
+	return a.reports[min(a.calls, len(a.reports))-1], nil
+}
+
+func TestSummaryStartWatchReanalysesOnChange(t *testing.T) {
+	restorePoll, restoreDebounce := summaryWatchPollInterval, summaryWatchDebounce
+	summaryWatchPollInterval, summaryWatchDebounce = 5*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() { summaryWatchPollInterval, summaryWatchDebounce = restorePoll, restoreDebounce })
+
+	repo := t.TempDir()
+	testutil.MustWriteFile(t, filepath.Join(repo, "index.js"), "import { map } from \"lodash\"\n")
+	analyzer := &watchingAnalyzer{analysed: make(chan analysis.Request, 4), reports: []report.Report{
+		{Dependencies: []report.DependencyReport{{Name: "lodash", UsedExportsCount: 1, TotalExportsCount: 4, UsedPercent: 25}}},
+		{Dependencies: []report.DependencyReport{{Name: "lodash", UsedExportsCount: 3, TotalExportsCount: 4, UsedPercent: 75}}},
+	}}
+	input, inputWriter := io.Pipe()
+	var out bytes.Buffer
+	summary := NewSummary(&out, input, analyzer, report.NewFormatter())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- summary.Start(context.Background(), Options{RepoPath: repo, TopN: 5, PageSize: 5, Watch: true, CachePath: filepath.Join(t.TempDir(), "cache")})
+	}()
+	first := <-analyzer.analysed
+	if first.Cache == nil || first.Cache.Path == "" {
+		t.Fatalf("expected watch mode to analyse through an explicit cache, got %#v", first.Cache)
+	}
+
+	deadline := time.After(5 * time.Second)
+	for edit := 0; ; edit++ {
+		testutil.MustWriteFile(t, filepath.Join(repo, "index.js"), fmt.Sprintf("import { map } from \"lodash\"\n// edit %d\n", edit))
+		select {
+		case <-analyzer.analysed:
+		case <-time.After(20 * time.Millisecond):
+			continue
+		case <-deadline:
+			t.Fatalf("expected a re-analysis after editing index.js")
+		}
+		break
+	}
+	if _, err := io.WriteString(inputWriter, "q\n"); err != nil {
+		t.Fatalf("write quit: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("summary start: %v", err)
+	}
+	if err := inputWriter.Close(); err != nil {
+		t.Fatalf("close input: %v", err)
+	}
+	if !strings.Contains(out.String(), "Re-analysed after changes to index.js.") || !strings.Contains(out.String(), "75.0%") {
+		t.Fatalf("expected refreshed summary output, got %q", out.String())
+	}
+}